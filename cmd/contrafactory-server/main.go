@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -22,6 +23,9 @@ import (
 
 var version = "dev"
 
+// configFile is the optional YAML config file passed via --config
+var configFile string
+
 func main() {
 	if err := newRootCmd().Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
@@ -36,6 +40,8 @@ func newRootCmd() *cobra.Command {
 		Version: version,
 	}
 
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "YAML config file (env: "+config.ConfigFileEnv+"); environment variables override file values")
+
 	// Default behavior (no subcommand) is to serve
 	rootCmd.RunE = func(cmd *cobra.Command, args []string) error {
 		return runServe()
@@ -44,10 +50,19 @@ func newRootCmd() *cobra.Command {
 	// Add subcommands
 	rootCmd.AddCommand(newServeCmd())
 	rootCmd.AddCommand(newKeysCmd())
+	rootCmd.AddCommand(newGCCmd())
 
 	return rootCmd
 }
 
+// loadConfig loads server config, preferring the --config flag over CONTRAFACTORY_CONFIG_FILE
+func loadConfig() (*config.Config, error) {
+	if configFile != "" {
+		return config.LoadFile(configFile)
+	}
+	return config.Load()
+}
+
 func newServeCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "serve",
@@ -76,6 +91,7 @@ func newKeysCreateCmd() *cobra.Command {
 	var outputFile string
 	var quiet bool
 	var show bool
+	var admin bool
 
 	cmd := &cobra.Command{
 		Use:   "create",
@@ -97,9 +113,12 @@ EXAMPLES:
 
   # Create key, display on screen
   contrafactory-server keys create --name "ci-release" --show
+
+  # Create an admin key (can see the owner of every package)
+  contrafactory-server keys create --name "registry-admin" --admin
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runKeysCreate(name, outputFile, quiet, show)
+			return runKeysCreate(name, outputFile, quiet, show, admin)
 		},
 	}
 
@@ -107,19 +126,26 @@ EXAMPLES:
 	cmd.Flags().StringVarP(&outputFile, "output", "o", "", "write key to file (default: ./contrafactory-key-{name}.txt)")
 	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "print only the key (for piping)")
 	cmd.Flags().BoolVar(&show, "show", false, "display key on screen")
+	cmd.Flags().BoolVar(&admin, "admin", false, "grant the admin scope")
 	_ = cmd.MarkFlagRequired("name")
 
 	return cmd
 }
 
 func newKeysListCmd() *cobra.Command {
-	return &cobra.Command{
+	var fullIDs bool
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all API keys",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runKeysList()
+			return runKeysList(fullIDs)
 		},
 	}
+
+	cmd.Flags().BoolVar(&fullIDs, "full-ids", false, "show full key IDs (needed for 'contrafactory owner transfer')")
+
+	return cmd
 }
 
 func newKeysRevokeCmd() *cobra.Command {
@@ -148,8 +174,8 @@ EXAMPLES:
 
 // Key management commands
 
-func runKeysCreate(name, outputFile string, quiet, show bool) error {
-	cfg, err := config.Load()
+func runKeysCreate(name, outputFile string, quiet, show, admin bool) error {
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
@@ -166,7 +192,11 @@ func runKeysCreate(name, outputFile string, quiet, show bool) error {
 	}
 
 	// Create the key
-	key, err := store.CreateAPIKey(context.Background(), name)
+	var scopes map[string]any
+	if admin {
+		scopes = map[string]any{storage.ScopeAdmin: true}
+	}
+	key, err := store.CreateAPIKey(context.Background(), name, scopes)
 	if err != nil {
 		return fmt.Errorf("creating API key: %w", err)
 	}
@@ -217,8 +247,8 @@ func runKeysCreate(name, outputFile string, quiet, show bool) error {
 	return nil
 }
 
-func runKeysList() error {
-	cfg, err := config.Load()
+func runKeysList(fullIDs bool) error {
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
@@ -251,7 +281,7 @@ func runKeysList() error {
 		created := k.CreatedAt
 		// Truncate ID for display
 		idDisplay := k.ID
-		if len(k.ID) > 8 {
+		if len(k.ID) > 8 && !fullIDs {
 			idDisplay = k.ID[:8] + "..."
 		}
 		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", idDisplay, k.Name, created, lastUsed)
@@ -262,7 +292,7 @@ func runKeysList() error {
 }
 
 func runKeysRevoke(keyID string) error {
-	cfg, err := config.Load()
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
@@ -299,11 +329,89 @@ func runKeysRevoke(keyID string) error {
 	return nil
 }
 
+func newGCCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Delete orphaned artifacts and blobs",
+		Long: `Delete artifacts whose contract no longer exists and blobs no artifact
+references, in one transaction, and report the reclaimed bytes.
+
+Set storage.gc_interval_minutes (env: GC_INTERVAL_MINUTES) to have the server
+do this periodically instead.
+
+EXAMPLES:
+  contrafactory-server gc --dry-run
+  contrafactory-server gc
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGC(dryRun)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report what would be deleted without deleting it")
+
+	return cmd
+}
+
+func runGC(dryRun bool) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	store, err := storage.New(cfg.Storage, slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError})))
+	if err != nil {
+		return fmt.Errorf("initializing storage: %w", err)
+	}
+	defer store.Close()
+
+	result, err := store.GarbageCollect(context.Background(), dryRun)
+	if err != nil {
+		return fmt.Errorf("collecting garbage: %w", err)
+	}
+
+	verb := "Deleted"
+	if dryRun {
+		verb = "Would delete"
+	}
+	fmt.Printf("%s %d orphaned artifact(s) and %d orphaned blob(s), reclaiming %d bytes\n",
+		verb, result.OrphanedArtifacts, result.OrphanedBlobs, result.ReclaimedBytes)
+	return nil
+}
+
+// runPeriodicGC garbage collects every interval until ctx is cancelled
+func runPeriodicGC(ctx context.Context, store storage.Store, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := store.GarbageCollect(ctx, false)
+			if err != nil {
+				logger.Error("garbage collection failed", "err", err)
+				continue
+			}
+			if result.OrphanedArtifacts > 0 || result.OrphanedBlobs > 0 {
+				logger.Info("garbage collected",
+					"artifacts", result.OrphanedArtifacts,
+					"blobs", result.OrphanedBlobs,
+					"reclaimed_bytes", result.ReclaimedBytes)
+			}
+		}
+	}
+}
+
 // Server command
 
 func runServe() error {
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := loadConfig()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
@@ -330,8 +438,16 @@ func runServe() error {
 		return fmt.Errorf("running migrations: %w", err)
 	}
 
+	// Periodically delete orphaned artifacts and blobs if configured
+	if cfg.Storage.GCIntervalMinutes > 0 {
+		gcCtx, stopGC := context.WithCancel(context.Background())
+		defer stopGC()
+		logger.Info("periodic garbage collection enabled", "interval_minutes", cfg.Storage.GCIntervalMinutes)
+		go runPeriodicGC(gcCtx, store, time.Duration(cfg.Storage.GCIntervalMinutes)*time.Minute, logger)
+	}
+
 	// Create server
-	srv := server.New(cfg, store, logger)
+	srv := server.New(cfg, store, logger, server.WithVersion(version))
 
 	// Create main HTTP server with configurable timeouts
 	mainServer := &http.Server{
@@ -342,9 +458,28 @@ func runServe() error {
 		IdleTimeout:  time.Duration(cfg.Server.IdleTimeout) * time.Second,
 	}
 
+	// Serve HTTPS when a certificate is configured; Go negotiates HTTP/2 over it
+	tlsCfg := cfg.Server.TLS
+	var redirectServer *http.Server
+	if tlsCfg.Enabled() {
+		minVersion, err := tlsCfg.MinTLSVersion()
+		if err != nil {
+			return err
+		}
+		mainServer.TLSConfig = &tls.Config{MinVersion: minVersion}
+
+		if tlsCfg.RedirectPort != 0 {
+			redirectServer = &http.Server{
+				Addr:        fmt.Sprintf("%s:%d", cfg.Server.Host, tlsCfg.RedirectPort),
+				Handler:     server.HTTPSRedirectHandler(cfg.Server.Port),
+				ReadTimeout: 10 * time.Second,
+			}
+		}
+	}
+
 	// Create metrics server if enabled
 	var metricsServer *http.Server
-	errChan := make(chan error, 2)
+	errChan := make(chan error, 3)
 
 	if cfg.Metrics.Enabled {
 		metricsServer = &http.Server{
@@ -356,12 +491,29 @@ func runServe() error {
 
 	// Start main server in goroutine
 	go func() {
-		logger.Info("server listening", "addr", mainServer.Addr)
-		if err := mainServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if tlsCfg.Enabled() {
+			logger.Info("server listening", "addr", mainServer.Addr, "tls", true, "min_tls_version", tlsCfg.MinVersion)
+			err = mainServer.ListenAndServeTLS(tlsCfg.CertFile, tlsCfg.KeyFile)
+		} else {
+			logger.Info("server listening", "addr", mainServer.Addr)
+			err = mainServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			errChan <- fmt.Errorf("main server: %w", err)
 		}
 	}()
 
+	// Start the HTTP to HTTPS redirect server in goroutine if enabled
+	if redirectServer != nil {
+		go func() {
+			logger.Info("redirecting HTTP to HTTPS", "addr", redirectServer.Addr)
+			if err := redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				errChan <- fmt.Errorf("redirect server: %w", err)
+			}
+		}()
+	}
+
 	// Start metrics server in goroutine if enabled
 	if cfg.Metrics.Enabled {
 		go func() {
@@ -395,6 +547,14 @@ func runServe() error {
 		}
 	}
 
+	// Stop redirecting before the HTTPS server it points at goes away
+	if redirectServer != nil {
+		logger.Info("shutting down redirect server")
+		if err := redirectServer.Shutdown(ctx); err != nil {
+			logger.Error("redirect shutdown error", "err", err)
+		}
+	}
+
 	// Shutdown main server
 	if err := mainServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("shutdown error: %w", err)
@@ -6,10 +6,15 @@ import (
 	"github.com/pendergraft/contrafactory/internal/cli"
 )
 
-var version = "dev"
+// Build metadata, set via ldflags (see the Makefile)
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
 
 func main() {
-	if err := cli.Execute(version); err != nil {
+	if err := cli.Execute(cli.VersionInfo{Version: version, Commit: commit, Date: date}); err != nil {
 		os.Exit(1)
 	}
 }
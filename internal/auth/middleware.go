@@ -4,7 +4,9 @@ package auth
 import (
 	"context"
 	"net/http"
+	"strings"
 
+	"github.com/pendergraft/contrafactory/internal/server/errcodes"
 	"github.com/pendergraft/contrafactory/internal/storage"
 )
 
@@ -29,27 +31,40 @@ func GetOwnerIDFromContext(ctx context.Context) string {
 	return ""
 }
 
+// apiKeyFromRequest returns the API key a request carries. X-API-Key is the
+// documented header and wins when set; for proxies and tools that only speak
+// standard auth, the key is also accepted as a Bearer token or as the password of
+// Basic credentials (the user name is ignored).
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	scheme, credentials, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	switch {
+	case strings.EqualFold(scheme, "Bearer"):
+		return strings.TrimSpace(credentials)
+	case strings.EqualFold(scheme, "Basic"):
+		if _, password, ok := r.BasicAuth(); ok {
+			return password
+		}
+	}
+	return ""
+}
+
 // Middleware returns an HTTP middleware that validates API keys.
 func Middleware(store storage.APIKeyStore, writeError func(w http.ResponseWriter, status int, code, message string)) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			apiKey := r.Header.Get("X-API-Key")
-			if apiKey == "" {
-				// Check Authorization header
-				auth := r.Header.Get("Authorization")
-				if len(auth) > 7 && auth[:7] == "Bearer " {
-					apiKey = auth[7:]
-				}
-			}
+			apiKey := apiKeyFromRequest(r)
 
 			if apiKey == "" {
-				writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "API key required")
+				writeError(w, http.StatusUnauthorized, errcodes.Unauthorized, "API key required")
 				return
 			}
 
 			key, err := store.ValidateAPIKey(r.Context(), apiKey)
 			if err != nil {
-				writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Invalid API key")
+				writeError(w, http.StatusUnauthorized, errcodes.Unauthorized, "Invalid API key")
 				return
 			}
 
@@ -65,14 +80,7 @@ func Middleware(store storage.APIKeyStore, writeError func(w http.ResponseWriter
 func OptionalMiddleware(store storage.APIKeyStore) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			apiKey := r.Header.Get("X-API-Key")
-			if apiKey == "" {
-				// Check Authorization header
-				auth := r.Header.Get("Authorization")
-				if len(auth) > 7 && auth[:7] == "Bearer " {
-					apiKey = auth[7:]
-				}
-			}
+			apiKey := apiKeyFromRequest(r)
 
 			if apiKey != "" {
 				key, err := store.ValidateAPIKey(r.Context(), apiKey)
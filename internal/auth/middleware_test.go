@@ -16,7 +16,7 @@ type mockAPIKeyStore struct {
 	keys map[string]*storage.APIKey
 }
 
-func (m *mockAPIKeyStore) CreateAPIKey(ctx context.Context, name string) (string, error) {
+func (m *mockAPIKeyStore) CreateAPIKey(ctx context.Context, name string, scopes map[string]any) (string, error) {
 	return "", nil
 }
 
@@ -27,6 +27,10 @@ func (m *mockAPIKeyStore) ValidateAPIKey(ctx context.Context, key string) (*stor
 	return nil, storage.ErrNotFound
 }
 
+func (m *mockAPIKeyStore) GetAPIKey(ctx context.Context, id string) (*storage.APIKey, error) {
+	return nil, storage.ErrNotFound
+}
+
 func (m *mockAPIKeyStore) ListAPIKeys(ctx context.Context) ([]storage.APIKey, error) {
 	return nil, nil
 }
@@ -136,6 +140,33 @@ func TestMiddleware_BearerToken(t *testing.T) {
 	assert.Equal(t, "key-456", apiKey.ID)
 }
 
+func TestAPIKeyFromRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"none", nil, ""},
+		{"x-api-key", map[string]string{"X-API-Key": "cf_key_a"}, "cf_key_a"},
+		{"bearer", map[string]string{"Authorization": "Bearer cf_key_b"}, "cf_key_b"},
+		{"bearer scheme is case-insensitive", map[string]string{"Authorization": "bearer  cf_key_b"}, "cf_key_b"},
+		{"x-api-key wins over bearer", map[string]string{"X-API-Key": "cf_key_a", "Authorization": "Bearer cf_key_b"}, "cf_key_a"},
+		{"basic uses the password", map[string]string{"Authorization": "Basic Y2k6Y2Zfa2V5X2M="}, "cf_key_c"}, // ci:cf_key_c
+		{"malformed basic", map[string]string{"Authorization": "Basic not-base64"}, ""},
+		{"other scheme", map[string]string{"Authorization": "Digest username=ci"}, ""},
+		{"bearer without token", map[string]string{"Authorization": "Bearer"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			assert.Equal(t, tt.want, apiKeyFromRequest(req))
+		})
+	}
+}
+
 func TestGenerateAPIKey(t *testing.T) {
 	key, err := GenerateAPIKey()
 	require.NoError(t, err)
@@ -0,0 +1,42 @@
+package base58
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []byte
+		encoded string
+	}{
+		{"empty", []byte{}, ""},
+		{"leading zeros", []byte{0, 0, 1}, "112"},
+		{"hello", []byte("hello world"), "StV1DL6CwTryKyV"},
+		{"system program", make([]byte, 32), "11111111111111111111111111111111"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Encode(tt.input); got != tt.encoded {
+				t.Errorf("Encode() = %q, want %q", got, tt.encoded)
+			}
+			decoded, err := Decode(tt.encoded)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !bytes.Equal(decoded, tt.input) {
+				t.Errorf("Decode() = %x, want %x", decoded, tt.input)
+			}
+		})
+	}
+}
+
+func TestDecode_InvalidCharacter(t *testing.T) {
+	for _, s := range []string{"0abc", "Iabc", "abc!"} {
+		if _, err := Decode(s); err == nil {
+			t.Errorf("Decode(%q) expected error", s)
+		}
+	}
+}
@@ -55,7 +55,7 @@ type Builder interface {
 
 // DiscoverOptions configures artifact discovery
 type DiscoverOptions struct {
-	// Contracts to include (empty = all)
+	// Contracts to include by name or source-qualified name ("src/Token.sol:Token") (empty = all)
 	Contracts []string
 	// Patterns to exclude by contract name (e.g., "Test*", "Mock*")
 	Exclude []string
@@ -63,7 +63,24 @@ type DiscoverOptions struct {
 	ExcludePaths []string
 	// Specific dependency contracts to include from lib/
 	IncludeDependencies []string
-}
+	// Contract kinds to exclude (see ContractKinds), e.g. "abstract", "library"
+	ExcludeKinds []string
+	// AllowMissingBuildInfo discovers artifacts even when no build-info was generated.
+	// Verification inputs are unavailable in that case.
+	AllowMissingBuildInfo bool
+}
+
+// Solidity contract kinds, as used by DiscoverOptions.ExcludeKinds and EVMArtifact.Kind.
+// Abstract contracts are reported as "abstract" rather than "contract".
+const (
+	KindContract  = "contract"
+	KindAbstract  = "abstract"
+	KindLibrary   = "library"
+	KindInterface = "interface"
+)
+
+// ContractKinds lists the valid contract kinds.
+var ContractKinds = []string{KindContract, KindAbstract, KindLibrary, KindInterface}
 
 // DependencyInfo describes a third-party contract available in build artifacts
 type DependencyInfo struct {
@@ -82,9 +99,20 @@ type VerifyOptions struct {
 
 // VerifyResult contains verification results
 type VerifyResult struct {
-	Match     bool   // Whether the bytecode matches
-	MatchType string // "full", "partial", "none"
-	Message   string // Human-readable explanation
+	Match      bool        // Whether the bytecode matches
+	MatchType  string      // "full", "partial", "none", "clone"
+	Message    string      // Human-readable explanation
+	Divergence *Divergence // Where the code differs; nil when the chain doesn't report it
+
+	// Implementation is the address a "clone" (EIP-1167 minimal proxy) delegates to
+	Implementation string
+}
+
+// Divergence locates where deployed code departs from the expected code.
+type Divergence struct {
+	Offset         int // First differing byte; -1 when identical
+	MetadataOffset int // Start of the deployed code's trailing metadata; -1 when it has none
+	MetadataLength int // Bytes of metadata stripped before comparing code
 }
 
 // Artifact can represent any chain's contract/program
@@ -101,6 +129,7 @@ type Artifact struct {
 // EVMArtifact contains EVM-specific contract data
 type EVMArtifact struct {
 	SourcePath        string          `json:"sourcePath"`
+	Kind              string          `json:"kind,omitempty"` // one of ContractKinds; empty when unknown
 	License           string          `json:"license,omitempty"`
 	ABI               json.RawMessage `json:"abi"`
 	Bytecode          string          `json:"bytecode"`
@@ -124,11 +153,13 @@ type OptimizerConfig struct {
 	Runs    int  `json:"runs"`
 }
 
-// SolanaArtifact contains Solana-specific program data (future)
+// SolanaArtifact contains Solana-specific program data
 type SolanaArtifact struct {
-	IDL         json.RawMessage `json:"idl"`         // Anchor IDL
-	ProgramHash string          `json:"programHash"` // SHA256 of .so
-	// Binary stored in blob store, referenced by hash
+	SourcePath  string          `json:"sourcePath,omitempty"` // e.g. "programs/counter/src/lib.rs"
+	ProgramID   string          `json:"programId,omitempty"`  // base58 program address, if known
+	IDL         json.RawMessage `json:"idl,omitempty"`        // Anchor IDL
+	ProgramHash string          `json:"programHash"`          // SHA256 of .so
+	Binary      []byte          `json:"-"`                    // Program .so; stored as the "program" artifact
 }
 
 // Registry holds all registered chain modules
@@ -3,6 +3,7 @@ package evm
 import (
 	"github.com/pendergraft/contrafactory/internal/chains"
 	"github.com/pendergraft/contrafactory/internal/chains/evm/foundry"
+	"github.com/pendergraft/contrafactory/internal/chains/evm/truffle"
 )
 
 // NewFoundryBuilder creates a new Foundry builder
@@ -10,6 +11,11 @@ func NewFoundryBuilder() chains.Builder {
 	return foundry.New()
 }
 
+// NewTruffleBuilder creates a new Truffle builder
+func NewTruffleBuilder() chains.Builder {
+	return truffle.New()
+}
+
 // NewHardhatBuilder creates a new Hardhat builder (Phase 2)
 // func NewHardhatBuilder() chains.Builder {
 // 	return hardhat.New()
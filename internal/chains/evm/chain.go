@@ -4,13 +4,16 @@ package evm
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/pendergraft/contrafactory/internal/chains"
 )
 
 // Chain implements the chains.Chain interface for EVM-compatible blockchains
 type Chain struct {
-	builders []chains.Builder
+	builders   []chains.Builder
+	httpClient *http.Client
+	batchSize  int
 }
 
 // NewChain creates a new EVM chain module
@@ -18,8 +21,11 @@ func NewChain() *Chain {
 	return &Chain{
 		builders: []chains.Builder{
 			NewFoundryBuilder(),
+			NewTruffleBuilder(),
 			// NewHardhatBuilder(), // Phase 2
 		},
+		httpClient: http.DefaultClient,
+		batchSize:  DefaultRPCBatchSize,
 	}
 }
 
@@ -64,9 +70,3 @@ func (c *Chain) VerifyDeployment(ctx context.Context, opts chains.VerifyOptions)
 	result := CompareBytecode(deployed, opts.ExpectedCode, opts.Libraries)
 	return result, nil
 }
-
-// GetDeployedBytecode fetches the deployed bytecode from an RPC endpoint
-func (c *Chain) GetDeployedBytecode(ctx context.Context, rpc string, address string) ([]byte, error) {
-	// TODO: Implement eth_getCode RPC call
-	return nil, fmt.Errorf("GetDeployedBytecode not yet implemented")
-}
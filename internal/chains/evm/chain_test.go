@@ -0,0 +1,33 @@
+package evm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain_DetectBuilder(t *testing.T) {
+	c := NewChain()
+
+	for config, builder := range map[string]string{
+		"foundry.toml":      "foundry",
+		"truffle-config.js": "truffle",
+	} {
+		t.Run(builder, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, config), nil, 0644))
+
+			b, err := c.DetectBuilder(dir)
+			require.NoError(t, err)
+			assert.Equal(t, builder, b.Name())
+		})
+	}
+
+	t.Run("none", func(t *testing.T) {
+		_, err := c.DetectBuilder(t.TempDir())
+		assert.ErrorContains(t, err, "no EVM builder detected")
+	})
+}
@@ -0,0 +1,15 @@
+package evm
+
+import "github.com/pendergraft/contrafactory/internal/chains/networks"
+
+// ExplorerBaseURL returns the block explorer for a chain ID, if one is known.
+func ExplorerBaseURL(chainID string) (string, bool) {
+	n, ok := networks.Lookup(chainID)
+	return n.ExplorerURL, ok && n.ExplorerURL != ""
+}
+
+// ExplorerAddressURL returns the explorer page showing the contract code at address,
+// or "" when the chain has no known explorer.
+func ExplorerAddressURL(chainID, address string) string {
+	return networks.ExplorerAddressURL(chainID, address)
+}
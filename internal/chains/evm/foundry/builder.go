@@ -12,7 +12,11 @@ import (
 )
 
 // Builder implements chains.Builder for Foundry projects
-type Builder struct{}
+type Builder struct {
+	// Profile is the foundry.toml profile to read the output paths from; empty
+	// means FOUNDRY_PROFILE, else "default". See ProfileName.
+	Profile string
+}
 
 // New creates a new Foundry builder
 func New() *Builder {
@@ -54,24 +58,29 @@ func (b *Builder) Detect(dir string) (bool, error) {
 
 // Discover finds all contract artifacts in a Foundry project
 func (b *Builder) Discover(dir string, opts chains.DiscoverOptions) ([]string, error) {
-	outDir := filepath.Join(dir, "out")
+	outDir := b.OutDir(dir)
 
 	// Check if out directory exists
 	if _, err := os.Stat(outDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf("out directory not found - run 'forge build' first")
+		return nil, fmt.Errorf("out directory %s not found - run 'forge build' first", displayPath(dir, outDir))
+	}
+
+	// Check for build-info directory (needed for verification inputs)
+	buildInfoDir := b.BuildInfoDir(dir)
+	if _, err := os.Stat(buildInfoDir); os.IsNotExist(err) && !opts.AllowMissingBuildInfo {
+		return nil, fmt.Errorf("build-info directory %s not found - run 'forge build --build-info' first", displayPath(dir, buildInfoDir))
 	}
 
-	// Check for build-info directory
-	buildInfoDir := filepath.Join(outDir, "build-info")
-	if _, err := os.Stat(buildInfoDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf("build-info directory not found - run 'forge build --build-info' first")
+	ignoreRules, err := loadIgnoreFile(dir)
+	if err != nil {
+		return nil, err
 	}
 
 	var artifacts []string
-	seen := make(map[string]bool) // Track seen contract names to avoid duplicates
+	seen := make(map[string]bool) // Track seen source:contract pairs to avoid duplicates
 
 	// Walk the out directory
-	err := filepath.Walk(outDir, func(path string, info os.FileInfo, err error) error {
+	err = filepath.Walk(outDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -82,28 +91,31 @@ func (b *Builder) Discover(dir string, opts chains.DiscoverOptions) ([]string, e
 		}
 
 		// Skip build-info files
-		if strings.Contains(path, "build-info") {
+		if isBuildInfoPath(buildInfoDir, path) {
 			return nil
 		}
 
-		// Get contract name from path (out/{Source}.sol/{Contract}.json)
+		// Artifacts live at out/{Source}.sol/{Contract}.json
 		parentDir := filepath.Dir(path)
 		if !strings.HasSuffix(parentDir, ".sol") {
 			return nil
 		}
 
-		contractName := strings.TrimSuffix(info.Name(), ".json")
-
-		// Skip if we've already seen this contract name
-		if seen[contractName] {
-			return nil
+		// Read the artifact to get the contract's name, source path and kind. Names
+		// come from the compilation target, as in Parse, since the file name can
+		// carry a compiler version suffix (Token.0.8.19.json)
+		sourcePath, contractName, kind, err := b.getArtifactInfo(path)
+		if err != nil {
+			return nil // Skip artifacts we can't read
 		}
 
-		// Check if this contract should be included (explicit list)
+		// Check if this contract should be included (explicit list). Entries may be
+		// source-qualified ("src/v2/Token.sol:Token") to pick one of several
+		// same-named contracts
 		if len(opts.Contracts) > 0 {
 			included := false
 			for _, c := range opts.Contracts {
-				if c == contractName {
+				if c == contractName || c == contractKey(sourcePath, contractName) {
 					included = true
 					break
 				}
@@ -130,10 +142,8 @@ func (b *Builder) Discover(dir string, opts chains.DiscoverOptions) ([]string, e
 			}
 		}
 
-		// Read the artifact to check its source path
-		sourcePath, err := b.getArtifactSourcePath(path)
-		if err != nil {
-			return nil // Skip artifacts we can't read
+		if isExcludedKind(kind, opts.ExcludeKinds) {
+			return nil
 		}
 
 		// Check if this source path should be excluded
@@ -145,6 +155,9 @@ func (b *Builder) Discover(dir string, opts chains.DiscoverOptions) ([]string, e
 				return nil
 			}
 		}
+		if isIgnored(sourcePath, ignoreRules) {
+			return nil
+		}
 
 		// Only include contracts from src/ directory, unless explicitly listed as a dependency
 		if !strings.HasPrefix(sourcePath, "src/") {
@@ -153,7 +166,13 @@ func (b *Builder) Discover(dir string, opts chains.DiscoverOptions) ([]string, e
 			}
 		}
 
-		seen[contractName] = true
+		// Contracts are identified by source and name, so same-named contracts in
+		// different files are all discovered
+		key := contractKey(sourcePath, contractName)
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
 		artifacts = append(artifacts, path)
 		return nil
 	})
@@ -161,29 +180,67 @@ func (b *Builder) Discover(dir string, opts chains.DiscoverOptions) ([]string, e
 	return artifacts, err
 }
 
-// getArtifactSourcePath reads an artifact and returns its source path
-func (b *Builder) getArtifactSourcePath(artifactPath string) (string, error) {
+// contractKey identifies a contract within a project: its name qualified by the
+// source file declaring it, as in "src/Token.sol:Token".
+func contractKey(sourcePath, contractName string) string {
+	return sourcePath + ":" + contractName
+}
+
+// isBuildInfoPath reports whether path lies under buildInfoDir. Only the part below
+// buildInfoDir is checked, so projects located in a "build-info" directory still work.
+func isBuildInfoPath(buildInfoDir, path string) bool {
+	rel, err := filepath.Rel(buildInfoDir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(filepath.ToSlash(rel), "../")
+}
+
+// displayPath shows path relative to the project in dir, for messages.
+func displayPath(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel) + "/"
+	}
+	return path
+}
+
+// getArtifactInfo reads an artifact and returns its source path, contract name
+// and the contract's kind (empty when unknown)
+func (b *Builder) getArtifactInfo(artifactPath string) (sourcePath, contractName, kind string, err error) {
 	data, err := os.ReadFile(artifactPath)
 	if err != nil {
-		return "", err
+		return "", "", "", err
 	}
 
 	var raw FoundryArtifact
 	if err := json.Unmarshal(data, &raw); err != nil {
-		return "", err
+		return "", "", "", err
 	}
 
 	// Parse metadata to get source path
 	if raw.RawMetadata == "" {
-		return "", fmt.Errorf("no metadata")
+		return "", "", "", fmt.Errorf("no metadata")
 	}
 
 	var metadata FoundryMetadata
 	if err := json.Unmarshal([]byte(raw.RawMetadata), &metadata); err != nil {
-		return "", err
+		return "", "", "", err
 	}
 
-	return getFirstKey(metadata.Settings.CompilationTarget), nil
+	sourcePath, contractName = artifactIdentity(&metadata, artifactPath)
+	return sourcePath, contractName, contractKind(&raw, contractName), nil
+}
+
+// artifactIdentity returns the source path and contract name of an artifact.
+// The contract name is the compilation target's; the file name is only a
+// fallback, since it can carry a compiler version suffix (Token.0.8.19.json).
+func artifactIdentity(metadata *FoundryMetadata, artifactPath string) (sourcePath, contractName string) {
+	sourcePath = getFirstKey(metadata.Settings.CompilationTarget)
+	contractName = metadata.Settings.CompilationTarget[sourcePath]
+	if contractName == "" {
+		contractName = strings.TrimSuffix(filepath.Base(artifactPath), ".json")
+	}
+	return sourcePath, contractName
 }
 
 // Parse parses a Foundry artifact file
@@ -209,15 +266,15 @@ func (b *Builder) Parse(artifactPath string) (*chains.Artifact, error) {
 		_ = json.Unmarshal([]byte(raw.RawMetadata), &metadata) // Non-fatal, continue without metadata
 	}
 
-	// Extract contract name from path
-	contractName := strings.TrimSuffix(filepath.Base(artifactPath), ".json")
+	sourcePath, contractName := artifactIdentity(&metadata, artifactPath)
 
 	// Build the artifact
 	artifact := &chains.Artifact{
 		Name:  contractName,
 		Chain: "evm",
 		EVM: &chains.EVMArtifact{
-			SourcePath:       getFirstKey(metadata.Settings.CompilationTarget),
+			SourcePath:       sourcePath,
+			Kind:             contractKind(&raw, contractName),
 			License:          metadata.Sources.FirstLicense(),
 			ABI:              raw.ABI,
 			Bytecode:         raw.Bytecode.Object,
@@ -254,7 +311,7 @@ type buildInfoOutputContracts map[string]map[string]json.RawMessage
 // When sourcePath is non-empty, finds the build-info whose output contains contracts[sourcePath][contractName].
 // When sourcePath is empty, returns the first valid build-info (legacy behavior).
 func (b *Builder) GetVerificationInput(dir string, contractName string, sourcePath string) (*chains.VerificationInput, error) {
-	buildInfoDir := filepath.Join(dir, "out", "build-info")
+	buildInfoDir := b.BuildInfoDir(dir)
 
 	entries, err := os.ReadDir(buildInfoDir)
 	if err != nil {
@@ -400,15 +457,23 @@ func (b *Builder) GeneratePerContractStandardJSON(dir, artifactPath string) ([]b
 		return nil, fmt.Errorf("parsing rawMetadata: %w", err)
 	}
 
+	return StandardJSONFromMetadata(&metadata, func(srcPath string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(dir, srcPath))
+	})
+}
+
+// StandardJSONFromMetadata builds a minimal standard JSON input from a contract's
+// solc metadata, with the content of each source it lists read by readSource.
+// Other builders whose artifacts embed the metadata JSON share it with Foundry.
+func StandardJSONFromMetadata(metadata *FoundryMetadata, readSource func(path string) ([]byte, error)) ([]byte, error) {
 	if len(metadata.Sources) == 0 {
 		return nil, fmt.Errorf("metadata has no sources")
 	}
 
-	// Read each source file from disk
+	// Read the content of each source
 	sources := make(map[string]sourceContent)
 	for srcPath := range metadata.Sources {
-		fullPath := filepath.Join(dir, srcPath)
-		content, err := os.ReadFile(fullPath)
+		content, err := readSource(srcPath)
 		if err != nil {
 			return nil, fmt.Errorf("reading source %s: %w", srcPath, err)
 		}
@@ -465,6 +530,7 @@ type FoundryArtifact struct {
 	StorageLayout    json.RawMessage `json:"storageLayout"`
 	RawMetadata      string          `json:"rawMetadata"`
 	Metadata         json.RawMessage `json:"metadata"`
+	AST              json.RawMessage `json:"ast"` // only with forge build --ast
 }
 
 // BytecodeObject represents bytecode in a Foundry artifact
@@ -540,15 +606,16 @@ func isIncludedDependency(name string, deps []string) bool {
 
 // DiscoverDependencies finds all dependency contracts (from lib/) available in build artifacts
 func (b *Builder) DiscoverDependencies(dir string) ([]chains.DependencyInfo, error) {
-	outDir := filepath.Join(dir, "out")
+	outDir := b.OutDir(dir)
+	buildInfoDir := b.BuildInfoDir(dir)
 
 	// Check if out directory exists
 	if _, err := os.Stat(outDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf("out directory not found - run 'forge build' first")
+		return nil, fmt.Errorf("out directory %s not found - run 'forge build' first", displayPath(dir, outDir))
 	}
 
 	var deps []chains.DependencyInfo
-	seen := make(map[string]bool) // Track seen contract names to avoid duplicates
+	seen := make(map[string]bool) // Track seen source:contract pairs to avoid duplicates
 
 	// Walk the out directory
 	err := filepath.Walk(outDir, func(path string, info os.FileInfo, err error) error {
@@ -562,28 +629,25 @@ func (b *Builder) DiscoverDependencies(dir string) ([]chains.DependencyInfo, err
 		}
 
 		// Skip build-info files
-		if strings.Contains(path, "build-info") {
+		if isBuildInfoPath(buildInfoDir, path) {
 			return nil
 		}
 
-		// Get contract name from path (out/{Source}.sol/{Contract}.json)
+		// Artifacts live at out/{Source}.sol/{Contract}.json
 		parentDir := filepath.Dir(path)
 		if !strings.HasSuffix(parentDir, ".sol") {
 			return nil
 		}
 
-		contractName := strings.TrimSuffix(info.Name(), ".json")
-
-		// Skip if we've already seen this contract name
-		if seen[contractName] {
-			return nil
-		}
-
-		// Read the artifact to check its source path
-		sourcePath, err := b.getArtifactSourcePath(path)
+		// Read the artifact to check its source path and contract name
+		sourcePath, contractName, _, err := b.getArtifactInfo(path)
 		if err != nil {
 			return nil // Skip artifacts we can't read
 		}
+		key := contractKey(sourcePath, contractName)
+		if seen[key] {
+			return nil
+		}
 
 		// Only include contracts NOT from src/ directory (these are dependencies)
 		if strings.HasPrefix(sourcePath, "src/") {
@@ -605,7 +669,7 @@ func (b *Builder) DiscoverDependencies(dir string) ([]chains.DependencyInfo, err
 			return nil // Skip interfaces
 		}
 
-		seen[contractName] = true
+		seen[key] = true
 		deps = append(deps, chains.DependencyInfo{
 			Name:       contractName,
 			SourcePath: sourcePath,
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -116,6 +117,24 @@ func TestBuilder_Discover(t *testing.T) {
 		assert.Contains(t, err.Error(), "build-info")
 	})
 
+	t.Run("without build-info when allowed", func(t *testing.T) {
+		dir := t.TempDir()
+		outDir := filepath.Join(dir, "out")
+		require.NoError(t, os.MkdirAll(filepath.Join(outDir, "Token.sol"), 0755))
+
+		artifact := map[string]any{
+			"abi":         []map[string]any{{"type": "function", "name": "transfer"}},
+			"bytecode":    map[string]any{"object": "0x1234"},
+			"rawMetadata": `{"settings":{"compilationTarget":{"src/Token.sol":"Token"}}}`,
+		}
+		artifactBytes, _ := json.Marshal(artifact)
+		require.NoError(t, os.WriteFile(filepath.Join(outDir, "Token.sol", "Token.json"), artifactBytes, 0644))
+
+		paths, err := b.Discover(dir, chains.DiscoverOptions{AllowMissingBuildInfo: true})
+		require.NoError(t, err)
+		assert.Len(t, paths, 1)
+	})
+
 	t.Run("excludes by source path", func(t *testing.T) {
 		dir := t.TempDir()
 		outDir := filepath.Join(dir, "out")
@@ -143,10 +162,10 @@ func TestBuilder_Discover(t *testing.T) {
 
 		require.NoError(t, os.WriteFile(filepath.Join(buildInfoDir, "abc.json"), []byte("{}"), 0644))
 
-		// Without ExcludePaths: returns first MetaCoin found (order depends on Walk)
+		// Without ExcludePaths: same-named contracts from different sources are all found
 		paths, err := b.Discover(dir, chains.DiscoverOptions{})
 		require.NoError(t, err)
-		assert.Len(t, paths, 1)
+		assert.Len(t, paths, 3)
 
 		// With ExcludePaths: exclude proxy and root, keep only inheritance
 		paths, err = b.Discover(dir, chains.DiscoverOptions{
@@ -159,6 +178,114 @@ func TestBuilder_Discover(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "src/examples/inheritance/MetaCoin.sol", artifact.EVM.SourcePath)
 	})
+
+	t.Run("excludes by contract kind", func(t *testing.T) {
+		dir := t.TempDir()
+		outDir := filepath.Join(dir, "out")
+		require.NoError(t, os.MkdirAll(filepath.Join(outDir, "build-info"), 0755))
+
+		writeArtifact := func(name string, artifact map[string]any) {
+			artifact["abi"] = []map[string]any{}
+			artifact["rawMetadata"] = `{"settings":{"compilationTarget":{"src/` + name + `.sol":"` + name + `"}}}`
+			data, _ := json.Marshal(artifact)
+			require.NoError(t, os.MkdirAll(filepath.Join(outDir, name+".sol"), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(outDir, name+".sol", name+".json"), data, 0644))
+		}
+		astFor := func(name, kind string, abstract bool) map[string]any {
+			return map[string]any{"nodes": []map[string]any{
+				{"nodeType": "PragmaDirective"},
+				{"nodeType": "ContractDefinition", "name": name, "contractKind": kind, "abstract": abstract},
+			}}
+		}
+
+		writeArtifact("Token", map[string]any{"bytecode": map[string]any{"object": "0x1234"}, "ast": astFor("Token", "contract", false)})
+		writeArtifact("Base", map[string]any{"bytecode": map[string]any{"object": "0x"}, "ast": astFor("Base", "contract", true)})
+		writeArtifact("Math", map[string]any{"bytecode": map[string]any{"object": "0x1234"}, "ast": astFor("Math", "library", false)})
+		// No AST: library recognised by its runtime code
+		writeArtifact("Strings", map[string]any{
+			"bytecode":         map[string]any{"object": "0x1234"},
+			"deployedBytecode": map[string]any{"object": "0x" + libraryRuntimePrefix + "80"},
+		})
+
+		names := func(paths []string) []string {
+			var out []string
+			for _, p := range paths {
+				out = append(out, strings.TrimSuffix(filepath.Base(p), ".json"))
+			}
+			return out
+		}
+
+		paths, err := b.Discover(dir, chains.DiscoverOptions{})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"Token", "Base", "Math", "Strings"}, names(paths))
+
+		paths, err = b.Discover(dir, chains.DiscoverOptions{ExcludeKinds: []string{"abstract", "Library"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Token"}, names(paths))
+
+		artifact, err := b.Parse(filepath.Join(outDir, "Math.sol", "Math.json"))
+		require.NoError(t, err)
+		assert.Equal(t, chains.KindLibrary, artifact.EVM.Kind)
+	})
+
+	t.Run("filters version-suffixed artifacts by contract name", func(t *testing.T) {
+		dir := t.TempDir()
+		outDir := filepath.Join(dir, "out")
+		require.NoError(t, os.MkdirAll(filepath.Join(outDir, "build-info"), 0755))
+		require.NoError(t, os.MkdirAll(filepath.Join(outDir, "Foo.sol"), 0755))
+
+		// Built with several solc versions, Foundry suffixes the file name
+		artifact := map[string]any{
+			"abi":         []map[string]any{},
+			"bytecode":    map[string]any{"object": "0x1234"},
+			"rawMetadata": `{"settings":{"compilationTarget":{"src/Foo.sol":"Foo"}}}`,
+		}
+		data, _ := json.Marshal(artifact)
+		require.NoError(t, os.WriteFile(filepath.Join(outDir, "Foo.sol", "Foo.0.8.20.json"), data, 0644))
+
+		paths, err := b.Discover(dir, chains.DiscoverOptions{Contracts: []string{"Foo"}})
+		require.NoError(t, err)
+		assert.Len(t, paths, 1)
+
+		// Suffix patterns match the contract name, not the versioned file name
+		paths, err = b.Discover(dir, chains.DiscoverOptions{Exclude: []string{"Foo"}})
+		require.NoError(t, err)
+		assert.Empty(t, paths)
+
+		paths, err = b.Discover(dir, chains.DiscoverOptions{Exclude: []string{"*.0.8.20"}})
+		require.NoError(t, err)
+		assert.Len(t, paths, 1)
+	})
+
+	t.Run("selects among same-named contracts by source", func(t *testing.T) {
+		dir := t.TempDir()
+		outDir := filepath.Join(dir, "out")
+		require.NoError(t, os.MkdirAll(filepath.Join(outDir, "build-info"), 0755))
+
+		writeToken := func(outSub, sourcePath string) {
+			artifact := map[string]any{
+				"abi":         []map[string]any{},
+				"bytecode":    map[string]any{"object": "0x1234"},
+				"rawMetadata": `{"settings":{"compilationTarget":{"` + sourcePath + `":"Token"}}}`,
+			}
+			data, _ := json.Marshal(artifact)
+			require.NoError(t, os.MkdirAll(filepath.Join(outDir, outSub, "Token.sol"), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(outDir, outSub, "Token.sol", "Token.json"), data, 0644))
+		}
+		writeToken("", "src/Token.sol")
+		writeToken("v2", "src/v2/Token.sol")
+
+		paths, err := b.Discover(dir, chains.DiscoverOptions{Contracts: []string{"Token"}})
+		require.NoError(t, err)
+		assert.Len(t, paths, 2)
+
+		paths, err = b.Discover(dir, chains.DiscoverOptions{Contracts: []string{"src/v2/Token.sol:Token"}})
+		require.NoError(t, err)
+		require.Len(t, paths, 1)
+		artifact, err := b.Parse(paths[0])
+		require.NoError(t, err)
+		assert.Equal(t, "src/v2/Token.sol", artifact.EVM.SourcePath)
+	})
 }
 
 func TestBuilder_Parse(t *testing.T) {
@@ -191,6 +318,25 @@ func TestBuilder_Parse(t *testing.T) {
 		assert.Contains(t, result.EVM.Bytecode, "0x608060")
 	})
 
+	t.Run("name from compilation target", func(t *testing.T) {
+		dir := t.TempDir()
+
+		artifact := map[string]any{
+			"abi":         []map[string]any{},
+			"bytecode":    map[string]any{"object": "0x6080"},
+			"rawMetadata": `{"settings":{"compilationTarget":{"src/v1/Token.sol":"Token"}}}`,
+		}
+		artifactBytes, _ := json.Marshal(artifact)
+		// forge suffixes the compiler version when a contract is built with several
+		artifactPath := filepath.Join(dir, "Token.0.8.19.json")
+		require.NoError(t, os.WriteFile(artifactPath, artifactBytes, 0644))
+
+		result, err := b.Parse(artifactPath)
+		require.NoError(t, err)
+		assert.Equal(t, "Token", result.Name)
+		assert.Equal(t, "src/v1/Token.sol", result.EVM.SourcePath)
+	})
+
 	t.Run("invalid json", func(t *testing.T) {
 		dir := t.TempDir()
 		artifactPath := filepath.Join(dir, "Invalid.json")
@@ -0,0 +1,115 @@
+package foundry
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// defaultOut is forge's output directory when the profile doesn't set `out`.
+const defaultOut = "out"
+
+// profileSettings holds the foundry.toml profile keys read by the builder.
+// Pointers distinguish "not set" (forge default) from an explicit value.
+type profileSettings struct {
+	Out           *string `toml:"out"`
+	BuildInfoPath *string `toml:"build_info_path"`
+	Optimizer     *bool   `toml:"optimizer"`
+	OptimizerRuns *int    `toml:"optimizer_runs"`
+	EVMVersion    *string `toml:"evm_version"`
+	ViaIR         *bool   `toml:"via_ir"`
+}
+
+// foundryConfig is the subset of foundry.toml read by the builder.
+type foundryConfig struct {
+	Profile map[string]profileSettings `toml:"profile"`
+}
+
+// loadConfig parses the foundry.toml at path.
+func loadConfig(path string) (foundryConfig, error) {
+	var cfg foundryConfig
+	_, err := toml.DecodeFile(path, &cfg)
+	return cfg, err
+}
+
+// activeProfile merges the named profile over [profile.default], as forge does.
+func (cfg foundryConfig) activeProfile(name string) profileSettings {
+	merged := cfg.Profile["default"]
+	if name == "" || name == "default" {
+		return merged
+	}
+
+	override := cfg.Profile[name]
+	if override.Out != nil {
+		merged.Out = override.Out
+	}
+	if override.BuildInfoPath != nil {
+		merged.BuildInfoPath = override.BuildInfoPath
+	}
+	if override.Optimizer != nil {
+		merged.Optimizer = override.Optimizer
+	}
+	if override.OptimizerRuns != nil {
+		merged.OptimizerRuns = override.OptimizerRuns
+	}
+	if override.EVMVersion != nil {
+		merged.EVMVersion = override.EVMVersion
+	}
+	if override.ViaIR != nil {
+		merged.ViaIR = override.ViaIR
+	}
+	return merged
+}
+
+// ProfileName returns the foundry profile the builder reads: Profile when set, else
+// FOUNDRY_PROFILE, else "default".
+func (b *Builder) ProfileName() string {
+	if b.Profile != "" {
+		return b.Profile
+	}
+	if name := os.Getenv("FOUNDRY_PROFILE"); name != "" {
+		return name
+	}
+	return "default"
+}
+
+// profile returns the active profile of the project in dir. A missing or unreadable
+// foundry.toml yields an empty profile, i.e. forge's defaults.
+func (b *Builder) profile(dir string) profileSettings {
+	cfg, err := loadConfig(filepath.Join(dir, b.ConfigFile()))
+	if err != nil {
+		return profileSettings{}
+	}
+	return cfg.activeProfile(b.ProfileName())
+}
+
+// OutDir returns the directory forge writes artifacts to for the project in dir: the
+// active profile's `out`, falling back to out/.
+func (b *Builder) OutDir(dir string) string {
+	return projectPath(dir, b.profile(dir).Out, defaultOut)
+}
+
+// BuildInfoDir returns the directory forge writes build-info files to for the project
+// in dir: the active profile's `build_info_path`, falling back to build-info/ under
+// OutDir.
+func (b *Builder) BuildInfoDir(dir string) string {
+	p := b.profile(dir)
+	if p.BuildInfoPath != nil && *p.BuildInfoPath != "" {
+		return projectPath(dir, p.BuildInfoPath, "")
+	}
+	return filepath.Join(projectPath(dir, p.Out, defaultOut), "build-info")
+}
+
+// projectPath resolves a foundry.toml path setting against the project root, using
+// fallback when it is unset or empty.
+func projectPath(dir string, setting *string, fallback string) string {
+	p := fallback
+	if setting != nil && *setting != "" {
+		p = *setting
+	}
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p)
+	}
+	return filepath.Join(dir, p)
+}
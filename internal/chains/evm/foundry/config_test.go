@@ -0,0 +1,95 @@
+package foundry
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/chains"
+)
+
+const profilesToml = `
+[profile.default]
+src = "src"
+out = "artifacts"
+
+[profile.ci]
+out = "ci-out"
+build_info_path = "build/info"
+
+[profile.lite]
+optimizer = false
+`
+
+func TestBuilder_OutDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foundry.toml"), []byte(profilesToml), 0644))
+
+	tests := []struct {
+		name          string
+		profile       string
+		env           string
+		wantOut       string
+		wantBuildInfo string
+	}{
+		{"default profile", "", "", "artifacts", "artifacts/build-info"},
+		{"FOUNDRY_PROFILE", "", "ci", "ci-out", "build/info"},
+		{"Profile overrides FOUNDRY_PROFILE", "default", "ci", "artifacts", "artifacts/build-info"},
+		{"profile without out inherits default", "lite", "", "artifacts", "artifacts/build-info"},
+		{"unknown profile", "nope", "", "artifacts", "artifacts/build-info"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FOUNDRY_PROFILE", tt.env)
+			b := &Builder{Profile: tt.profile}
+			assert.Equal(t, filepath.Join(dir, tt.wantOut), b.OutDir(dir))
+			assert.Equal(t, filepath.Join(dir, tt.wantBuildInfo), b.BuildInfoDir(dir))
+		})
+	}
+
+	t.Run("falls back to out without foundry.toml", func(t *testing.T) {
+		t.Setenv("FOUNDRY_PROFILE", "")
+		empty := t.TempDir()
+		assert.Equal(t, filepath.Join(empty, "out"), New().OutDir(empty))
+		assert.Equal(t, filepath.Join(empty, "out", "build-info"), New().BuildInfoDir(empty))
+	})
+}
+
+func TestBuilder_Discover_CustomOut(t *testing.T) {
+	t.Setenv("FOUNDRY_PROFILE", "")
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foundry.toml"), []byte(profilesToml), 0644))
+
+	writeArtifact := func(outDir string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(outDir, "Token.sol"), 0755))
+		artifact, _ := json.Marshal(map[string]any{
+			"abi":         []map[string]any{{"type": "function", "name": "transfer"}},
+			"bytecode":    map[string]any{"object": "0x1234"},
+			"rawMetadata": `{"settings":{"compilationTarget":{"src/Token.sol":"Token"}}}`,
+		})
+		require.NoError(t, os.WriteFile(filepath.Join(outDir, "Token.sol", "Token.json"), artifact, 0644))
+	}
+	writeArtifact(filepath.Join(dir, "artifacts"))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "artifacts", "build-info"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "artifacts", "build-info", "abc.json"), []byte(`{"solcLongVersion":"0.8.28+commit.7893614a","input":{"language":"Solidity"}}`), 0644))
+
+	paths, err := New().Discover(dir, chains.DiscoverOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "artifacts", "Token.sol", "Token.json")}, paths)
+
+	vi, err := New().GetVerificationInput(dir, "Token", "")
+	require.NoError(t, err)
+	assert.Equal(t, "0.8.28+commit.7893614a", vi.SolcLongVersion)
+
+	// The ci profile's directories haven't been built
+	_, err = (&Builder{Profile: "ci"}).Discover(dir, chains.DiscoverOptions{})
+	assert.ErrorContains(t, err, "ci-out/ not found")
+
+	writeArtifact(filepath.Join(dir, "ci-out"))
+	_, err = (&Builder{Profile: "ci"}).Discover(dir, chains.DiscoverOptions{})
+	assert.ErrorContains(t, err, "build-info directory build/info/ not found")
+}
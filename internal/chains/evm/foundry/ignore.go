@@ -0,0 +1,115 @@
+package foundry
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile lists gitignore-style source path patterns that Discover skips.
+const IgnoreFile = ".contrafactoryignore"
+
+// ignoreRule is one pattern line of an ignore file.
+type ignoreRule struct {
+	pattern  string // filepath.Match pattern without the leading "/" or "**/" and trailing "/"
+	negate   bool   // "!pattern" re-includes paths excluded by an earlier rule
+	dirOnly  bool   // "pattern/" only matches directories
+	anchored bool   // matched from the project root rather than at any depth
+}
+
+// loadIgnoreFile reads dir/.contrafactoryignore. A missing file yields no rules.
+// Blank lines and lines starting with "#" are skipped.
+func loadIgnoreFile(dir string) ([]ignoreRule, error) {
+	f, err := os.Open(filepath.Join(dir, IgnoreFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", IgnoreFile, err)
+	}
+	defer f.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		rule, ok := parseIgnoreLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		if _, err := filepath.Match(rule.pattern, ""); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid pattern %q: %w", IgnoreFile, lineNo, rule.pattern, err)
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", IgnoreFile, err)
+	}
+	return rules, nil
+}
+
+// parseIgnoreLine parses one ignore file line, reporting false for blanks and comments.
+func parseIgnoreLine(line string) (ignoreRule, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	var rule ignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	switch {
+	case strings.HasPrefix(line, "/"):
+		rule.anchored = true
+		line = strings.TrimLeft(line, "/")
+	case strings.HasPrefix(line, "**/"):
+		line = strings.TrimPrefix(line, "**/")
+	default:
+		// As in gitignore, a slash inside the pattern anchors it to the root
+		rule.anchored = strings.Contains(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	rule.pattern = line
+	return rule, true
+}
+
+// isIgnored reports whether sourcePath is excluded by rules. Rules are applied in
+// order, so a later "!pattern" can re-include a path matched by an earlier one.
+func isIgnored(sourcePath string, rules []ignoreRule) bool {
+	segments := strings.Split(filepath.ToSlash(sourcePath), "/")
+	ignored := false
+	for _, rule := range rules {
+		if rule.matches(segments) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// matches reports whether the rule matches the path itself or one of its parent directories.
+func (r ignoreRule) matches(segments []string) bool {
+	depth := strings.Count(r.pattern, "/") + 1
+	for start := 0; start+depth <= len(segments); start++ {
+		if r.anchored && start > 0 {
+			break
+		}
+		end := start + depth
+		// The final segment is the file itself, which a directory pattern can't match
+		if r.dirOnly && end == len(segments) {
+			continue
+		}
+		if matched, _ := filepath.Match(r.pattern, strings.Join(segments[start:end], "/")); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package foundry
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/chains"
+)
+
+func TestIsIgnored(t *testing.T) {
+	var rules []ignoreRule
+	for _, line := range []string{
+		"# examples and mocks",
+		"",
+		"src/examples/",
+		"!src/examples/keep/",
+		"mocks/",
+		"*.t.sol",
+		"/src/Legacy*.sol",
+		"**/scratch/*.sol",
+	} {
+		if rule, ok := parseIgnoreLine(line); ok {
+			rules = append(rules, rule)
+		}
+	}
+	require.Len(t, rules, 6)
+
+	tests := []struct {
+		path    string
+		ignored bool
+	}{
+		{"src/Token.sol", false},
+		{"src/examples/MetaCoin.sol", true},
+		{"src/examples/proxy/MetaCoin.sol", true},
+		{"src/examples/keep/MetaCoin.sol", false},
+		{"src/mocks/MockToken.sol", true},
+		{"src/deep/mocks/MockToken.sol", true},
+		{"src/mocks.sol", false}, // directory pattern doesn't match files
+		{"src/Token.t.sol", true},
+		{"src/LegacyToken.sol", true},
+		{"src/v1/LegacyToken.sol", false}, // anchored to the root
+		{"src/a/scratch/Try.sol", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.ignored, isIgnored(tt.path, rules))
+		})
+	}
+}
+
+func TestLoadIgnoreFile(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		rules, err := loadIgnoreFile(t.TempDir())
+		require.NoError(t, err)
+		assert.Empty(t, rules)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, IgnoreFile), []byte("src/\n[abc\n"), 0644))
+
+		_, err := loadIgnoreFile(dir)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), ".contrafactoryignore:2")
+	})
+}
+
+func TestBuilder_Discover_IgnoreFile(t *testing.T) {
+	b := New()
+	dir := t.TempDir()
+	outDir := filepath.Join(dir, "out")
+	require.NoError(t, os.MkdirAll(filepath.Join(outDir, "build-info"), 0755))
+
+	writeArtifact := func(name, sourcePath string) {
+		data, _ := json.Marshal(map[string]any{
+			"abi":         []map[string]any{},
+			"bytecode":    map[string]any{"object": "0x1234"},
+			"rawMetadata": `{"settings":{"compilationTarget":{"` + sourcePath + `":"` + name + `"}}}`,
+		})
+		require.NoError(t, os.MkdirAll(filepath.Join(outDir, name+".sol"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(outDir, name+".sol", name+".json"), data, 0644))
+	}
+	writeArtifact("Token", "src/Token.sol")
+	writeArtifact("MetaCoin", "src/examples/MetaCoin.sol")
+	writeArtifact("Vault", "src/Vault.sol")
+
+	ignore := "# keep examples out of the registry\nsrc/examples/\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, IgnoreFile), []byte(ignore), 0644))
+
+	paths, err := b.Discover(dir, chains.DiscoverOptions{ExcludePaths: []string{"Vault.sol"}})
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+	assert.Equal(t, filepath.Join(outDir, "Token.sol", "Token.json"), paths[0])
+}
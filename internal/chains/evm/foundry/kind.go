@@ -0,0 +1,67 @@
+package foundry
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pendergraft/contrafactory/internal/chains"
+)
+
+// libraryRuntimePrefix is how solc starts a library's deployed code: PUSH20 of the
+// (zeroed) library address followed by ADDRESS EQ, the call protection check.
+const libraryRuntimePrefix = "73" + "0000000000000000000000000000000000000000" + "3014"
+
+// astSourceUnit is the part of the solc AST needed to find a contract's kind.
+type astSourceUnit struct {
+	Nodes []struct {
+		NodeType     string `json:"nodeType"`
+		Name         string `json:"name"`
+		ContractKind string `json:"contractKind"`
+		Abstract     bool   `json:"abstract"`
+	} `json:"nodes"`
+}
+
+// contractKind returns the kind of contractName (one of chains.ContractKinds).
+// It reads the artifact's AST when present (forge build --ast); otherwise libraries
+// are recognised by their runtime code and anything else is "" (unknown).
+func contractKind(raw *FoundryArtifact, contractName string) string {
+	return ContractKind(raw.AST, raw.DeployedBytecode.Object, contractName)
+}
+
+// ContractKind returns the kind of contractName given the solc AST of its source
+// unit (may be empty) and its deployed bytecode. See contractKind.
+func ContractKind(ast json.RawMessage, deployedBytecode, contractName string) string {
+	if len(ast) > 0 {
+		var unit astSourceUnit
+		if err := json.Unmarshal(ast, &unit); err == nil {
+			for _, node := range unit.Nodes {
+				if node.NodeType != "ContractDefinition" || node.Name != contractName {
+					continue
+				}
+				if node.Abstract {
+					return chains.KindAbstract
+				}
+				return node.ContractKind
+			}
+		}
+	}
+
+	if strings.HasPrefix(strings.TrimPrefix(deployedBytecode, "0x"), libraryRuntimePrefix) {
+		return chains.KindLibrary
+	}
+	return ""
+}
+
+// isExcludedKind reports whether kind is listed in excludeKinds (case-insensitive).
+// Unknown kinds are never excluded.
+func isExcludedKind(kind string, excludeKinds []string) bool {
+	if kind == "" {
+		return false
+	}
+	for _, k := range excludeKinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package foundry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pendergraft/contrafactory/internal/chains"
+)
+
+// CompilerSettings are the compiler settings that change bytecode and therefore must
+// agree between the published Standard JSON Input and the compiled artifact.
+type CompilerSettings struct {
+	OptimizerEnabled bool
+	OptimizerRuns    int
+	EVMVersion       string // empty means the compiler default
+	ViaIR            bool
+}
+
+// SettingsFromCompiler returns the settings recorded in an artifact's metadata.
+func SettingsFromCompiler(c chains.EVMCompiler) CompilerSettings {
+	return CompilerSettings{
+		OptimizerEnabled: c.Optimizer.Enabled,
+		OptimizerRuns:    c.Optimizer.Runs,
+		EVMVersion:       c.EVMVersion,
+		ViaIR:            c.ViaIR,
+	}
+}
+
+// SettingsFromStandardJSON reads the settings from a Standard JSON Input.
+func SettingsFromStandardJSON(stdJSON []byte) (CompilerSettings, error) {
+	var input struct {
+		Settings struct {
+			Optimizer struct {
+				Enabled bool `json:"enabled"`
+				Runs    int  `json:"runs"`
+			} `json:"optimizer"`
+			EVMVersion string `json:"evmVersion"`
+			ViaIR      bool   `json:"viaIR"`
+		} `json:"settings"`
+	}
+	if err := json.Unmarshal(stdJSON, &input); err != nil {
+		return CompilerSettings{}, fmt.Errorf("parsing standard JSON input: %w", err)
+	}
+	s := input.Settings
+	return CompilerSettings{
+		OptimizerEnabled: s.Optimizer.Enabled,
+		OptimizerRuns:    s.Optimizer.Runs,
+		EVMVersion:       s.EVMVersion,
+		ViaIR:            s.ViaIR,
+	}, nil
+}
+
+// DiffSettings describes how b differs from a, one entry per setting ("viaIR: false vs true").
+// Runs are only compared when both enable the optimizer (solc defaults to 200 when unset),
+// and evmVersion only when both name one explicitly.
+func DiffSettings(a, b CompilerSettings) []string {
+	var diffs []string
+	if a.OptimizerEnabled != b.OptimizerEnabled {
+		diffs = append(diffs, fmt.Sprintf("optimizer: %t vs %t", a.OptimizerEnabled, b.OptimizerEnabled))
+	} else if a.OptimizerEnabled && runsOrDefault(a.OptimizerRuns) != runsOrDefault(b.OptimizerRuns) {
+		diffs = append(diffs, fmt.Sprintf("optimizer runs: %d vs %d", runsOrDefault(a.OptimizerRuns), runsOrDefault(b.OptimizerRuns)))
+	}
+	if a.EVMVersion != "" && b.EVMVersion != "" && !strings.EqualFold(a.EVMVersion, b.EVMVersion) {
+		diffs = append(diffs, fmt.Sprintf("evmVersion: %s vs %s", a.EVMVersion, b.EVMVersion))
+	}
+	if a.ViaIR != b.ViaIR {
+		diffs = append(diffs, fmt.Sprintf("viaIR: %t vs %t", a.ViaIR, b.ViaIR))
+	}
+	return diffs
+}
+
+func runsOrDefault(runs int) int {
+	if runs == 0 {
+		return 200
+	}
+	return runs
+}
+
+// RawMetadata returns the compiler metadata JSON (rawMetadata) and deployed bytecode
+// recorded in the artifact at artifactPath.
+func (b *Builder) RawMetadata(artifactPath string) (rawMetadata []byte, deployedBytecode string, err error) {
+	data, err := os.ReadFile(artifactPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading artifact: %w", err)
+	}
+	var raw FoundryArtifact
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, "", fmt.Errorf("parsing artifact: %w", err)
+	}
+	if raw.RawMetadata == "" {
+		return nil, "", fmt.Errorf("artifact has no rawMetadata")
+	}
+	return []byte(raw.RawMetadata), raw.DeployedBytecode.Object, nil
+}
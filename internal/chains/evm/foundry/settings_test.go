@@ -0,0 +1,47 @@
+package foundry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/chains"
+)
+
+func TestSettingsFromStandardJSON(t *testing.T) {
+	s, err := SettingsFromStandardJSON([]byte(`{"settings":{"optimizer":{"enabled":true,"runs":1000},"evmVersion":"cancun","viaIR":true}}`))
+	require.NoError(t, err)
+	assert.Equal(t, CompilerSettings{OptimizerEnabled: true, OptimizerRuns: 1000, EVMVersion: "cancun", ViaIR: true}, s)
+
+	_, err = SettingsFromStandardJSON([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestDiffSettings(t *testing.T) {
+	base := SettingsFromCompiler(chains.EVMCompiler{
+		Optimizer:  chains.OptimizerConfig{Enabled: true, Runs: 200},
+		EVMVersion: "paris",
+	})
+
+	tests := []struct {
+		name  string
+		other CompilerSettings
+		want  []string
+	}{
+		{"identical", base, nil},
+		{"default runs", CompilerSettings{OptimizerEnabled: true, EVMVersion: "paris"}, nil},
+		{"runs differ", CompilerSettings{OptimizerEnabled: true, OptimizerRuns: 1000, EVMVersion: "paris"}, []string{"optimizer runs: 200 vs 1000"}},
+		{"optimizer off", CompilerSettings{OptimizerRuns: 1000, EVMVersion: "paris"}, []string{"optimizer: true vs false"}},
+		{"evm version unset", CompilerSettings{OptimizerEnabled: true, OptimizerRuns: 200}, nil},
+		{"evm version case", CompilerSettings{OptimizerEnabled: true, OptimizerRuns: 200, EVMVersion: "Paris"}, nil},
+		{"evm version differs", CompilerSettings{OptimizerEnabled: true, OptimizerRuns: 200, EVMVersion: "cancun"}, []string{"evmVersion: paris vs cancun"}},
+		{"via ir", CompilerSettings{OptimizerEnabled: true, OptimizerRuns: 200, EVMVersion: "paris", ViaIR: true}, []string{"viaIR: false vs true"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DiffSettings(base, tt.other))
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package foundry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// buildInfoSettings is the subset of build-info input.settings compared against foundry.toml.
+type buildInfoSettings struct {
+	Input struct {
+		Settings struct {
+			Optimizer struct {
+				Enabled *bool `json:"enabled"`
+				Runs    *int  `json:"runs"`
+			} `json:"optimizer"`
+			EVMVersion *string `json:"evmVersion"`
+			ViaIR      *bool   `json:"viaIR"`
+		} `json:"settings"`
+	} `json:"input"`
+}
+
+// CheckStaleness compares foundry.toml with the newest build-info in BuildInfoDir
+// and returns warnings when the build may not reflect the current config: either
+// foundry.toml was modified after the last build, or its optimizer/evmVersion/viaIR
+// settings differ from those the build-info was compiled with. The active profile
+// is chosen as by ProfileName, layered over [profile.default].
+func (b *Builder) CheckStaleness(dir string) ([]string, error) {
+	configPath := filepath.Join(dir, b.ConfigFile())
+	configStat, err := os.Stat(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", b.ConfigFile(), err)
+	}
+
+	buildInfoPath, buildInfoTime, err := newestBuildInfo(b.BuildInfoDir(dir))
+	if err != nil {
+		return nil, err
+	}
+	if buildInfoPath == "" {
+		return nil, nil
+	}
+
+	var warnings []string
+	if configStat.ModTime().After(buildInfoTime) {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s was modified after the last build (%s) - run 'forge build' to refresh out/",
+			b.ConfigFile(), buildInfoTime.Format(time.RFC3339)))
+	}
+
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return warnings, fmt.Errorf("parsing %s: %w", b.ConfigFile(), err)
+	}
+	profile := cfg.activeProfile(b.ProfileName())
+
+	data, err := os.ReadFile(buildInfoPath)
+	if err != nil {
+		return warnings, fmt.Errorf("reading build-info: %w", err)
+	}
+	var bi buildInfoSettings
+	if err := json.Unmarshal(data, &bi); err != nil {
+		return warnings, fmt.Errorf("parsing build-info: %w", err)
+	}
+	built := bi.Input.Settings
+
+	mismatch := func(key, want, got string) {
+		warnings = append(warnings, fmt.Sprintf(
+			"%s sets %s = %s but the last build used %s - run 'forge build' to refresh out/",
+			b.ConfigFile(), key, want, got))
+	}
+	if profile.Optimizer != nil && built.Optimizer.Enabled != nil && *profile.Optimizer != *built.Optimizer.Enabled {
+		mismatch("optimizer", fmt.Sprint(*profile.Optimizer), fmt.Sprint(*built.Optimizer.Enabled))
+	}
+	if profile.OptimizerRuns != nil && built.Optimizer.Runs != nil && *profile.OptimizerRuns != *built.Optimizer.Runs {
+		mismatch("optimizer_runs", fmt.Sprint(*profile.OptimizerRuns), fmt.Sprint(*built.Optimizer.Runs))
+	}
+	if profile.EVMVersion != nil && built.EVMVersion != nil && !strings.EqualFold(*profile.EVMVersion, *built.EVMVersion) {
+		mismatch("evm_version", *profile.EVMVersion, *built.EVMVersion)
+	}
+	if profile.ViaIR != nil {
+		builtViaIR := built.ViaIR != nil && *built.ViaIR
+		if *profile.ViaIR != builtViaIR {
+			mismatch("via_ir", fmt.Sprint(*profile.ViaIR), fmt.Sprint(builtViaIR))
+		}
+	}
+
+	return warnings, nil
+}
+
+// newestBuildInfo returns the most recently modified build-info JSON file.
+// Returns an empty path if the directory is missing or has no JSON files.
+func newestBuildInfo(buildInfoDir string) (string, time.Time, error) {
+	entries, err := os.ReadDir(buildInfoDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", time.Time{}, nil
+		}
+		return "", time.Time{}, fmt.Errorf("reading build-info directory: %w", err)
+	}
+
+	var newestPath string
+	var newestTime time.Time
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if newestPath == "" || info.ModTime().After(newestTime) {
+			newestPath = filepath.Join(buildInfoDir, entry.Name())
+			newestTime = info.ModTime()
+		}
+	}
+	return newestPath, newestTime, nil
+}
@@ -0,0 +1,121 @@
+package foundry
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeStalenessProject writes foundry.toml and a build-info file with the given
+// settings, setting mtimes so the config is older or newer than the build.
+func writeStalenessProject(t *testing.T, foundryToml string, settings map[string]any, configNewer bool) string {
+	t.Helper()
+	dir := t.TempDir()
+	buildInfoDir := filepath.Join(dir, "out", "build-info")
+	require.NoError(t, os.MkdirAll(buildInfoDir, 0755))
+
+	configPath := filepath.Join(dir, "foundry.toml")
+	require.NoError(t, os.WriteFile(configPath, []byte(foundryToml), 0644))
+
+	data, err := json.Marshal(map[string]any{
+		"id":    "abc",
+		"input": map[string]any{"language": "Solidity", "settings": settings},
+	})
+	require.NoError(t, err)
+	buildInfoPath := filepath.Join(buildInfoDir, "abc.json")
+	require.NoError(t, os.WriteFile(buildInfoPath, data, 0644))
+
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+	if configNewer {
+		require.NoError(t, os.Chtimes(buildInfoPath, older, older))
+		require.NoError(t, os.Chtimes(configPath, newer, newer))
+	} else {
+		require.NoError(t, os.Chtimes(configPath, older, older))
+		require.NoError(t, os.Chtimes(buildInfoPath, newer, newer))
+	}
+	return dir
+}
+
+func TestBuilder_CheckStaleness(t *testing.T) {
+	b := New()
+
+	builtSettings := map[string]any{
+		"optimizer":  map[string]any{"enabled": true, "runs": 200},
+		"evmVersion": "cancun",
+	}
+
+	tests := []struct {
+		name         string
+		foundryToml  string
+		settings     map[string]any
+		configNewer  bool
+		profile      string
+		wantContains []string
+	}{
+		{
+			name:        "up to date",
+			foundryToml: "[profile.default]\noptimizer = true\noptimizer_runs = 200\nevm_version = \"Cancun\"\n",
+			settings:    builtSettings,
+		},
+		{
+			name:         "config modified after build",
+			foundryToml:  "[profile.default]\noptimizer_runs = 200\n",
+			settings:     builtSettings,
+			configNewer:  true,
+			wantContains: []string{"modified after the last build"},
+		},
+		{
+			name:         "optimizer runs changed",
+			foundryToml:  "[profile.default]\noptimizer_runs = 10000\n",
+			settings:     builtSettings,
+			wantContains: []string{"optimizer_runs = 10000 but the last build used 200"},
+		},
+		{
+			name:         "via_ir enabled but build without",
+			foundryToml:  "[profile.default]\nvia_ir = true\n",
+			settings:     builtSettings,
+			wantContains: []string{"via_ir = true"},
+		},
+		{
+			name:         "FOUNDRY_PROFILE overrides default",
+			foundryToml:  "[profile.default]\nevm_version = \"cancun\"\n\n[profile.ci]\nevm_version = \"paris\"\n",
+			settings:     builtSettings,
+			profile:      "ci",
+			wantContains: []string{"evm_version = paris"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FOUNDRY_PROFILE", tt.profile)
+			dir := writeStalenessProject(t, tt.foundryToml, tt.settings, tt.configNewer)
+
+			warnings, err := b.CheckStaleness(dir)
+			require.NoError(t, err)
+
+			if len(tt.wantContains) == 0 {
+				assert.Empty(t, warnings)
+				return
+			}
+			require.Len(t, warnings, len(tt.wantContains))
+			for i, want := range tt.wantContains {
+				assert.Contains(t, warnings[i], want)
+			}
+		})
+	}
+
+	t.Run("no build-info", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "foundry.toml"), []byte("[profile.default]"), 0644))
+
+		warnings, err := b.CheckStaleness(dir)
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+}
@@ -0,0 +1,114 @@
+package evm
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pendergraft/contrafactory/pkg/evmutil"
+)
+
+// standardInterfaces maps an interface id (stored as a contract label) to the
+// canonical signatures of the functions a contract must expose to implement it.
+var standardInterfaces = map[string][]string{
+	"erc165": {
+		"supportsInterface(bytes4)",
+	},
+	"erc20": {
+		"totalSupply()",
+		"balanceOf(address)",
+		"transfer(address,uint256)",
+		"transferFrom(address,address,uint256)",
+		"approve(address,uint256)",
+		"allowance(address,address)",
+	},
+	"erc721": {
+		"balanceOf(address)",
+		"ownerOf(uint256)",
+		"safeTransferFrom(address,address,uint256,bytes)",
+		"safeTransferFrom(address,address,uint256)",
+		"transferFrom(address,address,uint256)",
+		"approve(address,uint256)",
+		"setApprovalForAll(address,bool)",
+		"getApproved(uint256)",
+		"isApprovedForAll(address,address)",
+		"supportsInterface(bytes4)",
+	},
+	"erc1155": {
+		"safeTransferFrom(address,address,uint256,uint256,bytes)",
+		"safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)",
+		"balanceOf(address,uint256)",
+		"balanceOfBatch(address[],uint256[])",
+		"setApprovalForAll(address,bool)",
+		"isApprovedForAll(address,address)",
+		"supportsInterface(bytes4)",
+	},
+}
+
+// IsStandardInterface reports whether id is an interface detected at publish time.
+func IsStandardInterface(id string) bool {
+	_, ok := standardInterfaces[id]
+	return ok
+}
+
+// StandardInterfaces returns the detectable interface ids, sorted.
+func StandardInterfaces() []string {
+	ids := make([]string, 0, len(standardInterfaces))
+	for id := range standardInterfaces {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// DetectInterfaces returns the ids of the standard interfaces whose required
+// function selectors are all present in the ABI, sorted.
+func DetectInterfaces(abi json.RawMessage) []string {
+	selectors := ABISelectors(abi)
+	if len(selectors) == 0 {
+		return nil
+	}
+
+	var detected []string
+	for id, signatures := range standardInterfaces {
+		matched := true
+		for _, sig := range signatures {
+			if !selectors[FunctionSelector(sig)] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			detected = append(detected, id)
+		}
+	}
+	sort.Strings(detected)
+	return detected
+}
+
+// ABISelectors indexes the 4-byte selectors (hex, no 0x) of all functions in an ABI.
+// Malformed ABIs yield an empty index.
+func ABISelectors(abi json.RawMessage) map[string]bool {
+	if len(abi) == 0 {
+		return nil
+	}
+	entries, err := evmutil.ParseABI(abi)
+	if err != nil {
+		return nil
+	}
+
+	selectors := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if e.Type != "function" || e.Name == "" {
+			continue
+		}
+		selectors[strings.TrimPrefix(e.Selector, "0x")] = true
+	}
+	return selectors
+}
+
+// FunctionSelector returns the first 4 bytes of keccak256(signature) as hex.
+func FunctionSelector(signature string) string {
+	return hex.EncodeToString(evmutil.Keccak256([]byte(signature))[:4])
+}
@@ -0,0 +1,68 @@
+package evm
+
+import (
+	"reflect"
+	"testing"
+)
+
+const erc20ABI = `[
+	{"type":"function","name":"totalSupply","inputs":[]},
+	{"type":"function","name":"balanceOf","inputs":[{"name":"account","type":"address"}]},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}]},
+	{"type":"function","name":"transferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"value","type":"uint256"}]},
+	{"type":"function","name":"approve","inputs":[{"name":"spender","type":"address"},{"name":"value","type":"uint256"}]},
+	{"type":"function","name":"allowance","inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}]},
+	{"type":"event","name":"Transfer","inputs":[]}
+]`
+
+func TestFunctionSelector(t *testing.T) {
+	tests := map[string]string{
+		"transfer(address,uint256)": "a9059cbb",
+		"supportsInterface(bytes4)": "01ffc9a7",
+		"balanceOf(address)":        "70a08231",
+	}
+	for sig, want := range tests {
+		if got := FunctionSelector(sig); got != want {
+			t.Errorf("FunctionSelector(%q) = %s, want %s", sig, got, want)
+		}
+	}
+}
+
+func TestABISelectors_Tuples(t *testing.T) {
+	abi := `[{"type":"function","name":"f","inputs":[{"type":"tuple[]","components":[{"type":"address"},{"type":"uint256"}]}]}]`
+	selectors := ABISelectors([]byte(abi))
+	if !selectors[FunctionSelector("f((address,uint256)[])")] {
+		t.Errorf("expected tuple signature to be indexed, got %v", selectors)
+	}
+}
+
+func TestDetectInterfaces(t *testing.T) {
+	tests := []struct {
+		name string
+		abi  string
+		want []string
+	}{
+		{name: "erc20", abi: erc20ABI, want: []string{"erc20"}},
+		{
+			name: "erc165 only",
+			abi:  `[{"type":"function","name":"supportsInterface","inputs":[{"type":"bytes4"}]}]`,
+			want: []string{"erc165"},
+		},
+		{
+			name: "partial erc20",
+			abi:  `[{"type":"function","name":"totalSupply","inputs":[]}]`,
+			want: nil,
+		},
+		{name: "malformed", abi: `{"not":"an array"}`, want: nil},
+		{name: "empty", abi: ``, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectInterfaces([]byte(tt.abi))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectInterfaces() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package evm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/pendergraft/contrafactory/internal/base58"
+	"github.com/pendergraft/contrafactory/pkg/evmutil"
+)
+
+// BytecodeMetadata is the CBOR map solc appends to bytecode.
+type BytecodeMetadata struct {
+	evmutil.BytecodeMetadata
+}
+
+// IPFSCID returns the base58 CIDv0 ("Qm...") of the IPFS hash, or "" if there is none.
+func (m *BytecodeMetadata) IPFSCID() string {
+	if len(m.IPFS) == 0 {
+		return ""
+	}
+	return base58.Encode(m.IPFS)
+}
+
+// ExtractBytecodeMetadata decodes the CBOR metadata at the end of bytecode, which may
+// be raw bytes or a 0x-prefixed hex string.
+func ExtractBytecodeMetadata(bytecode []byte) (*BytecodeMetadata, error) {
+	meta, err := evmutil.ParseMetadataCBOR(bytecode)
+	if err != nil {
+		return nil, err
+	}
+	return &BytecodeMetadata{*meta}, nil
+}
+
+// ipfsChunkSize is the go-ipfs default chunk size; larger files hash as a DAG of chunks.
+const ipfsChunkSize = 256 * 1024
+
+// IPFSHash returns the multihash solc embeds for a metadata file: the sha256 of the
+// single-block UnixFS node IPFS builds for it. ok is false for files larger than one
+// IPFS chunk, whose hash depends on the chunked DAG layout.
+func IPFSHash(content []byte) (hash []byte, ok bool) {
+	if len(content) == 0 || len(content) > ipfsChunkSize {
+		return nil, false
+	}
+
+	// UnixFS Data{Type: File, Data: content, filesize: len}
+	var unixfs []byte
+	unixfs = append(unixfs, 0x08, 0x02)
+	unixfs = append(unixfs, 0x12)
+	unixfs = binary.AppendUvarint(unixfs, uint64(len(content)))
+	unixfs = append(unixfs, content...)
+	unixfs = append(unixfs, 0x18)
+	unixfs = binary.AppendUvarint(unixfs, uint64(len(content)))
+
+	// PBNode{Data: unixfs}
+	var node []byte
+	node = append(node, 0x0a)
+	node = binary.AppendUvarint(node, uint64(len(unixfs)))
+	node = append(node, unixfs...)
+
+	digest := sha256.Sum256(node)
+	return append([]byte{0x12, 0x20}, digest[:]...), true
+}
+
+// MetadataMismatches checks a Standard JSON Input against the compiler metadata
+// (the artifact's rawMetadata) and the metadata hash embedded in deployedBytecode,
+// without recompiling. It returns one human-readable problem per mismatch:
+//   - the bytecode's IPFS hash does not match rawMetadata (stale or mixed build output)
+//   - a source in the metadata is missing from the standard JSON or its keccak256 differs
+//
+// Swarm (bzzr) hashes and metadata larger than one IPFS chunk cannot be checked and are skipped.
+func MetadataMismatches(stdJSON, rawMetadata, deployedBytecode []byte) ([]string, error) {
+	var metadata struct {
+		Sources map[string]struct {
+			Keccak256 string `json:"keccak256"`
+		} `json:"sources"`
+	}
+	if err := json.Unmarshal(rawMetadata, &metadata); err != nil {
+		return nil, fmt.Errorf("parsing metadata: %w", err)
+	}
+
+	var input struct {
+		Sources map[string]struct {
+			Content *string `json:"content"`
+		} `json:"sources"`
+	}
+	if err := json.Unmarshal(stdJSON, &input); err != nil {
+		return nil, fmt.Errorf("parsing standard JSON input: %w", err)
+	}
+
+	var problems []string
+
+	if embedded, err := ExtractBytecodeMetadata(deployedBytecode); err == nil && len(embedded.IPFS) > 0 {
+		if computed, ok := IPFSHash(rawMetadata); ok && !bytes.Equal(computed, embedded.IPFS) {
+			problems = append(problems, fmt.Sprintf(
+				"bytecode metadata hash %s does not match the artifact's metadata (%s)",
+				embedded.IPFSCID(), base58.Encode(computed)))
+		}
+	}
+
+	paths := make([]string, 0, len(metadata.Sources))
+	for path := range metadata.Sources {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		want := strings.TrimPrefix(strings.ToLower(metadata.Sources[path].Keccak256), "0x")
+		src, ok := input.Sources[path]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("source %s is missing from the standard JSON", path))
+		case src.Content == nil:
+			// URL-only source; nothing to hash locally
+		case want != "" && keccakHex([]byte(*src.Content)) != want:
+			problems = append(problems, fmt.Sprintf("source %s differs from the one that was compiled (keccak256 mismatch)", path))
+		}
+	}
+	return problems, nil
+}
+
+func keccakHex(data []byte) string {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
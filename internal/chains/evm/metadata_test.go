@@ -0,0 +1,131 @@
+package evm
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/base58"
+)
+
+// metadataTail builds the CBOR section solc >= 0.6 appends:
+// {"ipfs": <multihash>, "solc": 0.8.28} followed by its 2-byte length.
+func metadataTail(ipfs []byte) string {
+	cbor := "a2" + "64" + hex.EncodeToString([]byte("ipfs")) + "5822" + hex.EncodeToString(ipfs) +
+		"64" + hex.EncodeToString([]byte("solc")) + "43" + "00081c"
+	return cbor + "0033"
+}
+
+func TestIPFSHash(t *testing.T) {
+	// Same CIDs `ipfs add` produces
+	h, ok := IPFSHash([]byte("hello world\n"))
+	require.True(t, ok)
+	assert.Equal(t, "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o", base58.Encode(h))
+
+	h, ok = IPFSHash([]byte("hello world"))
+	require.True(t, ok)
+	assert.Equal(t, "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", base58.Encode(h))
+
+	_, ok = IPFSHash(make([]byte, ipfsChunkSize+1))
+	assert.False(t, ok)
+}
+
+func TestExtractBytecodeMetadata(t *testing.T) {
+	ipfs, _ := IPFSHash([]byte("hello world\n"))
+
+	t.Run("ipfs and solc", func(t *testing.T) {
+		meta, err := ExtractBytecodeMetadata([]byte("0x6080604052" + metadataTail(ipfs)))
+		require.NoError(t, err)
+		assert.Equal(t, ipfs, meta.IPFS)
+		assert.Equal(t, "QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o", meta.IPFSCID())
+		assert.Equal(t, "0.8.28", meta.Solc)
+		assert.False(t, meta.Experimental)
+		assert.Equal(t, 53, meta.Length)
+	})
+
+	t.Run("raw bytes", func(t *testing.T) {
+		code, err := hex.DecodeString("6080604052" + metadataTail(ipfs))
+		require.NoError(t, err)
+		meta, err := ExtractBytecodeMetadata(code)
+		require.NoError(t, err)
+		assert.Equal(t, "0.8.28", meta.Solc)
+	})
+
+	t.Run("bzzr1 with experimental flag", func(t *testing.T) {
+		swarm := make([]byte, 32)
+		cbor := "a3" + "65" + hex.EncodeToString([]byte("bzzr1")) + "5820" + hex.EncodeToString(swarm) +
+			"6c" + hex.EncodeToString([]byte("experimental")) + "f5" +
+			"64" + hex.EncodeToString([]byte("solc")) + "43" + "000511"
+		tail := cbor + hex.EncodeToString([]byte{0, byte(len(cbor) / 2)})
+		meta, err := ExtractBytecodeMetadata([]byte("0x6080" + tail))
+		require.NoError(t, err)
+		assert.Equal(t, swarm, meta.Bzzr1)
+		assert.True(t, meta.Experimental)
+		assert.Equal(t, "0.5.17", meta.Solc)
+		assert.Empty(t, meta.IPFSCID())
+	})
+
+	t.Run("no metadata", func(t *testing.T) {
+		_, err := ExtractBytecodeMetadata([]byte("0x6080604052600080fd"))
+		assert.Error(t, err)
+		_, err = ExtractBytecodeMetadata([]byte("0x"))
+		assert.Error(t, err)
+	})
+}
+
+func TestMetadataMismatches(t *testing.T) {
+	source := "contract Token {}"
+	metadata, err := json.Marshal(map[string]any{
+		"language": "Solidity",
+		"sources": map[string]any{
+			"src/Token.sol": map[string]any{"keccak256": "0x" + keccakHex([]byte(source))},
+		},
+	})
+	require.NoError(t, err)
+	ipfs, _ := IPFSHash(metadata)
+	bytecode := []byte("0x6080604052" + metadataTail(ipfs))
+
+	stdJSON := func(content string) []byte {
+		data, _ := json.Marshal(map[string]any{
+			"sources": map[string]any{"src/Token.sol": map[string]any{"content": content}},
+		})
+		return data
+	}
+
+	t.Run("consistent", func(t *testing.T) {
+		problems, err := MetadataMismatches(stdJSON(source), metadata, bytecode)
+		require.NoError(t, err)
+		assert.Empty(t, problems)
+	})
+
+	t.Run("source differs", func(t *testing.T) {
+		problems, err := MetadataMismatches(stdJSON(source+"\n"), metadata, bytecode)
+		require.NoError(t, err)
+		require.Len(t, problems, 1)
+		assert.Contains(t, problems[0], "src/Token.sol")
+		assert.Contains(t, problems[0], "keccak256")
+	})
+
+	t.Run("source missing", func(t *testing.T) {
+		problems, err := MetadataMismatches([]byte(`{"sources":{}}`), metadata, bytecode)
+		require.NoError(t, err)
+		require.Len(t, problems, 1)
+		assert.Contains(t, problems[0], "missing")
+	})
+
+	t.Run("stale bytecode", func(t *testing.T) {
+		other, _ := IPFSHash([]byte("other metadata"))
+		problems, err := MetadataMismatches(stdJSON(source), metadata, []byte("0x6080604052"+metadataTail(other)))
+		require.NoError(t, err)
+		require.Len(t, problems, 1)
+		assert.Contains(t, problems[0], "metadata hash")
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := MetadataMismatches([]byte("nope"), metadata, bytecode)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,177 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrNotContract is returned for addresses with no code (EOAs, self-destructed or
+// not-yet-deployed contracts). eth_getCode reports these as "0x" rather than an error.
+var ErrNotContract = errors.New("no contract code at address")
+
+// DefaultRPCBatchSize caps JSON-RPC batches; many providers reject batches above 100.
+const DefaultRPCBatchSize = 100
+
+// BytecodeResult is the outcome of fetching code for one address in a batch.
+// Err is ErrNotContract when the address has no code, or the per-call RPC error.
+type BytecodeResult struct {
+	Code []byte
+	Err  error
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcResponse struct {
+	ID     int              `json:"id"`
+	Result *json.RawMessage `json:"result"`
+	Error  *rpcError        `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
+}
+
+func getCodeRequest(id int, address string) rpcRequest {
+	return rpcRequest{JSONRPC: "2.0", ID: id, Method: "eth_getCode", Params: []any{address, "latest"}}
+}
+
+// GetDeployedBytecode fetches the deployed bytecode from an RPC endpoint
+func (c *Chain) GetDeployedBytecode(ctx context.Context, rpc string, address string) ([]byte, error) {
+	var resp rpcResponse
+	if err := c.postRPC(ctx, rpc, getCodeRequest(1, address), &resp); err != nil {
+		return nil, err
+	}
+	return decodeGetCode(resp)
+}
+
+// GetDeployedBytecodeBatch fetches code for many addresses using JSON-RPC batch requests
+// of at most c.batchSize calls each. Every address gets an entry in the result; an error
+// is only returned when a whole request fails. Endpoints that reject batches fall back
+// to one eth_getCode call per address.
+func (c *Chain) GetDeployedBytecodeBatch(ctx context.Context, rpc string, addresses []string) (map[string]BytecodeResult, error) {
+	results := make(map[string]BytecodeResult, len(addresses))
+
+	batchSize := c.batchSize
+	if batchSize < 1 {
+		batchSize = DefaultRPCBatchSize
+	}
+
+	for start := 0; start < len(addresses); start += batchSize {
+		chunk := addresses[start:min(start+batchSize, len(addresses))]
+
+		reqs := make([]rpcRequest, len(chunk))
+		for i, addr := range chunk {
+			reqs[i] = getCodeRequest(i, addr)
+		}
+
+		var resps []rpcResponse
+		err := c.postRPC(ctx, rpc, reqs, &resps)
+		if errors.Is(err, errBatchUnsupported) {
+			for _, addr := range chunk {
+				code, err := c.GetDeployedBytecode(ctx, rpc, addr)
+				results[addr] = BytecodeResult{Code: code, Err: err}
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Batch responses may arrive in any order; match them up by id
+		byID := make(map[int]rpcResponse, len(resps))
+		for _, r := range resps {
+			byID[r.ID] = r
+		}
+		for i, addr := range chunk {
+			r, ok := byID[i]
+			if !ok {
+				results[addr] = BytecodeResult{Err: errors.New("no response for address in RPC batch")}
+				continue
+			}
+			code, err := decodeGetCode(r)
+			results[addr] = BytecodeResult{Code: code, Err: err}
+		}
+	}
+
+	return results, nil
+}
+
+func decodeGetCode(resp rpcResponse) ([]byte, error) {
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	if resp.Result == nil || string(*resp.Result) == "null" {
+		return nil, ErrNotContract
+	}
+
+	var hexCode string
+	if err := json.Unmarshal(*resp.Result, &hexCode); err != nil {
+		return nil, fmt.Errorf("decoding eth_getCode result: %w", err)
+	}
+	hexCode = strings.TrimPrefix(hexCode, "0x")
+	if hexCode == "" {
+		return nil, ErrNotContract
+	}
+	code, err := hex.DecodeString(hexCode)
+	if err != nil {
+		return nil, fmt.Errorf("decoding eth_getCode result: %w", err)
+	}
+	return code, nil
+}
+
+// errBatchUnsupported means the endpoint answered a batch with a single object.
+var errBatchUnsupported = errors.New("RPC endpoint does not support batch requests")
+
+// postRPC sends a JSON-RPC request (or batch) and decodes the response into out.
+func (c *Chain) postRPC(ctx context.Context, rpc string, body, out any) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpc, bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("creating RPC request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling RPC: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading RPC response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("RPC returned HTTP %d", resp.StatusCode)
+	}
+
+	// A batch answered with a single object is the usual "batches not supported" reply
+	if _, isBatch := out.(*[]rpcResponse); isBatch && len(bytes.TrimSpace(data)) > 0 && bytes.TrimSpace(data)[0] == '{' {
+		return errBatchUnsupported
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decoding RPC response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNode answers eth_getCode from a fixed address->code table. Unknown addresses
+// return "0x", addresses mapped to "error" return a JSON-RPC error, and "null" returns null.
+func fakeNode(t *testing.T, code map[string]string, batches bool, requests *int32) *httptest.Server {
+	t.Helper()
+	answer := func(req rpcRequest) map[string]any {
+		resp := map[string]any{"jsonrpc": "2.0", "id": req.ID}
+		addr, _ := req.Params[0].(string)
+		switch c := code[addr]; c {
+		case "error":
+			resp["error"] = map[string]any{"code": -32000, "message": "header not found"}
+		case "null":
+			resp["result"] = nil
+		case "":
+			resp["result"] = "0x"
+		default:
+			resp["result"] = c
+		}
+		return resp
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		var raw json.RawMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+
+		if raw[0] == '[' {
+			if !batches {
+				json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": nil, "error": map[string]any{"code": -32600, "message": "batch requests are not supported"}})
+				return
+			}
+			var reqs []rpcRequest
+			require.NoError(t, json.Unmarshal(raw, &reqs))
+			out := make([]map[string]any, 0, len(reqs))
+			for i := len(reqs) - 1; i >= 0; i-- { // reverse order on purpose
+				out = append(out, answer(reqs[i]))
+			}
+			json.NewEncoder(w).Encode(out)
+			return
+		}
+
+		var req rpcRequest
+		require.NoError(t, json.Unmarshal(raw, &req))
+		assert.Equal(t, "eth_getCode", req.Method)
+		json.NewEncoder(w).Encode(answer(req))
+	}))
+}
+
+func TestChain_GetDeployedBytecode(t *testing.T) {
+	var requests int32
+	node := fakeNode(t, map[string]string{"0xaa": "0x6080", "0xbb": "error", "0xcc": "null"}, true, &requests)
+	defer node.Close()
+
+	c := NewChain()
+	ctx := context.Background()
+
+	code, err := c.GetDeployedBytecode(ctx, node.URL, "0xaa")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x60, 0x80}, code)
+
+	_, err = c.GetDeployedBytecode(ctx, node.URL, "0xdd")
+	assert.ErrorIs(t, err, ErrNotContract)
+
+	_, err = c.GetDeployedBytecode(ctx, node.URL, "0xcc")
+	assert.ErrorIs(t, err, ErrNotContract)
+
+	_, err = c.GetDeployedBytecode(ctx, node.URL, "0xbb")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotContract)
+	assert.Contains(t, err.Error(), "header not found")
+}
+
+func TestChain_GetDeployedBytecodeBatch(t *testing.T) {
+	code := map[string]string{"0x01": "0x6001", "0x02": "0x6002", "0x04": "error", "0x05": "0x6005"}
+	addresses := []string{"0x01", "0x02", "0x03", "0x04", "0x05"}
+
+	check := func(t *testing.T, results map[string]BytecodeResult) {
+		require.Len(t, results, 5)
+		assert.Equal(t, []byte{0x60, 0x01}, results["0x01"].Code)
+		assert.Equal(t, []byte{0x60, 0x02}, results["0x02"].Code)
+		assert.ErrorIs(t, results["0x03"].Err, ErrNotContract)
+		require.Error(t, results["0x04"].Err)
+		assert.NotErrorIs(t, results["0x04"].Err, ErrNotContract)
+		assert.Equal(t, []byte{0x60, 0x05}, results["0x05"].Code)
+	}
+
+	t.Run("chunked batches", func(t *testing.T) {
+		var requests int32
+		node := fakeNode(t, code, true, &requests)
+		defer node.Close()
+
+		c := NewChain()
+		c.batchSize = 2
+		results, err := c.GetDeployedBytecodeBatch(context.Background(), node.URL, addresses)
+		require.NoError(t, err)
+		check(t, results)
+		assert.Equal(t, int32(3), requests, "5 addresses in batches of 2")
+	})
+
+	t.Run("falls back when batches are unsupported", func(t *testing.T) {
+		var requests int32
+		node := fakeNode(t, code, false, &requests)
+		defer node.Close()
+
+		results, err := NewChain().GetDeployedBytecodeBatch(context.Background(), node.URL, addresses)
+		require.NoError(t, err)
+		check(t, results)
+		assert.Equal(t, int32(6), requests, "one rejected batch, then one call per address")
+	})
+
+	t.Run("transport failure", func(t *testing.T) {
+		node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer node.Close()
+
+		_, err := NewChain().GetDeployedBytecodeBatch(context.Background(), node.URL, addresses)
+		assert.Error(t, err)
+	})
+
+	t.Run("no addresses", func(t *testing.T) {
+		results, err := NewChain().GetDeployedBytecodeBatch(context.Background(), "http://unused.invalid", nil)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
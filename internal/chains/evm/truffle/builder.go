@@ -0,0 +1,360 @@
+// Package truffle provides the Truffle builder for EVM contracts.
+package truffle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pendergraft/contrafactory/internal/chains"
+	"github.com/pendergraft/contrafactory/internal/chains/evm/foundry"
+)
+
+// projectSourcePrefix is how Truffle (>= 5.2) names the project's own source units
+// in solc metadata, e.g. "project:/contracts/Token.sol".
+const projectSourcePrefix = "project:/"
+
+// Builder implements chains.Builder for Truffle projects
+type Builder struct{}
+
+// New creates a new Truffle builder
+func New() *Builder {
+	return &Builder{}
+}
+
+// Name returns the builder identifier
+func (b *Builder) Name() string {
+	return "truffle"
+}
+
+// DisplayName returns a human-readable name
+func (b *Builder) DisplayName() string {
+	return "Truffle"
+}
+
+// Chain returns the chain this builder targets
+func (b *Builder) Chain() string {
+	return "evm"
+}
+
+// ConfigFile returns the config file name
+func (b *Builder) ConfigFile() string {
+	return "truffle-config.js"
+}
+
+// Detect checks if a directory is a Truffle project. Projects from before
+// Truffle 5 name their config truffle.js.
+func (b *Builder) Detect(dir string) (bool, error) {
+	for _, name := range []string{b.ConfigFile(), "truffle.js"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if err == nil {
+			return true, nil
+		}
+		if !os.IsNotExist(err) {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// BuildDir returns the directory truffle compile writes artifacts to for the
+// project in dir.
+func (b *Builder) BuildDir(dir string) string {
+	return filepath.Join(dir, "build", "contracts")
+}
+
+// Discover finds the artifacts of the project's own contracts (build/contracts/*.json).
+// Contracts imported from node_modules are only included when listed in
+// IncludeDependencies.
+func (b *Builder) Discover(dir string, opts chains.DiscoverOptions) ([]string, error) {
+	artifacts, err := b.readArtifacts(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, a := range artifacts {
+		if !includeContract(a.name, opts) {
+			continue
+		}
+		if kind := a.kind(); kind != "" && containsFold(opts.ExcludeKinds, kind) {
+			continue
+		}
+		if excludedPath(a.sourcePath, opts.ExcludePaths) {
+			continue
+		}
+		if !isProjectSource(a.sourcePath) && !containsFold(opts.IncludeDependencies, a.name) {
+			continue
+		}
+		paths = append(paths, a.path)
+	}
+	return paths, nil
+}
+
+// DiscoverDependencies finds the contracts with bytecode that were compiled from
+// imported (non-project) sources, such as OpenZeppelin from node_modules.
+func (b *Builder) DiscoverDependencies(dir string) ([]chains.DependencyInfo, error) {
+	artifacts, err := b.readArtifacts(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []chains.DependencyInfo
+	for _, a := range artifacts {
+		if isProjectSource(a.sourcePath) || !hasBytecode(a.raw.Bytecode) {
+			continue
+		}
+		deps = append(deps, chains.DependencyInfo{Name: a.name, SourcePath: a.sourcePath})
+	}
+	return deps, nil
+}
+
+// Parse parses a Truffle artifact file
+func (b *Builder) Parse(artifactPath string) (*chains.Artifact, error) {
+	a, err := readArtifact(artifactPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip if no bytecode (interfaces, abstract contracts)
+	if !hasBytecode(a.raw.Bytecode) {
+		return nil, fmt.Errorf("contract has no bytecode (likely an interface)")
+	}
+
+	// The metadata has the exact solc version; the compiler field may carry a
+	// platform suffix (0.8.19+commit.7dd6d404.Emscripten.clang)
+	compilerVersion := a.metadata.Compiler.Version
+	if compilerVersion == "" {
+		compilerVersion = a.raw.Compiler.Version
+	}
+
+	return &chains.Artifact{
+		Name:  a.name,
+		Chain: "evm",
+		EVM: &chains.EVMArtifact{
+			SourcePath:       a.sourcePath,
+			Kind:             a.kind(),
+			License:          a.metadata.Sources.FirstLicense(),
+			ABI:              a.raw.ABI,
+			Bytecode:         a.raw.Bytecode,
+			DeployedBytecode: a.raw.DeployedBytecode,
+			Compiler: chains.EVMCompiler{
+				Version:    compilerVersion,
+				EVMVersion: a.metadata.Settings.EVMVersion,
+				ViaIR:      a.metadata.Settings.ViaIR,
+				Optimizer: chains.OptimizerConfig{
+					Enabled: a.metadata.Settings.Optimizer.Enabled,
+					Runs:    a.metadata.Settings.Optimizer.Runs,
+				},
+			},
+		},
+	}, nil
+}
+
+// GeneratePerContractStandardJSON builds a minimal standard JSON input from the
+// metadata embedded in the artifact, reading the sources it lists from the project
+// and node_modules. Like Foundry's, it reproduces the metadata hash in the bytecode.
+func (b *Builder) GeneratePerContractStandardJSON(dir, artifactPath string) ([]byte, error) {
+	a, err := readArtifact(artifactPath)
+	if err != nil {
+		return nil, err
+	}
+	if a.raw.Metadata == "" {
+		return nil, fmt.Errorf("artifact has no metadata")
+	}
+	return foundry.StandardJSONFromMetadata(&a.metadata, func(srcPath string) ([]byte, error) {
+		return readSource(dir, srcPath)
+	})
+}
+
+// GenerateVerificationInput returns the Standard JSON Input of a contract
+func (b *Builder) GenerateVerificationInput(dir string, contractName string) ([]byte, error) {
+	vi, err := b.GetVerificationInput(dir, contractName, "")
+	if err != nil {
+		return nil, err
+	}
+	return vi.StandardJSON, nil
+}
+
+// GetVerificationInput returns the Standard JSON Input and solc version of a contract.
+// Truffle writes no build-info, so the input is generated from the artifact's metadata
+// (see GeneratePerContractStandardJSON). When sourcePath is non-empty, only the
+// artifact compiled from that source matches.
+func (b *Builder) GetVerificationInput(dir string, contractName string, sourcePath string) (*chains.VerificationInput, error) {
+	artifacts, err := b.readArtifacts(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range artifacts {
+		if a.name != contractName || (sourcePath != "" && a.sourcePath != sourcePath) {
+			continue
+		}
+		stdJSON, err := b.GeneratePerContractStandardJSON(dir, a.path)
+		if err != nil {
+			return nil, err
+		}
+		return &chains.VerificationInput{
+			StandardJSON:    stdJSON,
+			SolcLongVersion: a.metadata.Compiler.Version,
+		}, nil
+	}
+	return nil, fmt.Errorf("artifact not found for contract %s", contractName)
+}
+
+// isProjectSource reports whether a metadata source unit name belongs to the project
+// rather than to an imported package. Truffle 5.2+ prefixes project sources with
+// "project:/"; older versions use the absolute path or contracts/.
+func isProjectSource(sourcePath string) bool {
+	return strings.HasPrefix(sourcePath, projectSourcePrefix) ||
+		strings.HasPrefix(sourcePath, "contracts/") ||
+		filepath.IsAbs(sourcePath)
+}
+
+// readSource reads a metadata source unit of the project in dir: project:/ paths
+// and relative paths from the project, falling back to node_modules for imports.
+func readSource(dir, srcPath string) ([]byte, error) {
+	if rel, ok := strings.CutPrefix(srcPath, projectSourcePrefix); ok {
+		return os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+	}
+	if filepath.IsAbs(srcPath) {
+		return os.ReadFile(srcPath)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(srcPath)))
+	if os.IsNotExist(err) {
+		return os.ReadFile(filepath.Join(dir, "node_modules", filepath.FromSlash(srcPath)))
+	}
+	return content, err
+}
+
+// includeContract applies the Contracts allowlist and Exclude patterns (prefix,
+// suffix or glob) to a contract name
+func includeContract(name string, opts chains.DiscoverOptions) bool {
+	if len(opts.Contracts) > 0 && !slices.Contains(opts.Contracts, name) {
+		return false
+	}
+	for _, pattern := range opts.Exclude {
+		if strings.HasPrefix(name, pattern) || strings.HasSuffix(name, pattern) {
+			return false
+		}
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return false
+		}
+	}
+	return true
+}
+
+// excludedPath reports whether a source path matches any pattern (substring or glob)
+func excludedPath(sourcePath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(sourcePath, pattern) {
+			return true
+		}
+		if matched, _ := filepath.Match(pattern, sourcePath); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasBytecode(bytecode string) bool {
+	return bytecode != "" && bytecode != "0x"
+}
+
+// TruffleArtifact represents the parts of a Truffle artifact (build/contracts/<Name>.json)
+// the builder reads
+type TruffleArtifact struct {
+	ContractName     string          `json:"contractName"`
+	ABI              json.RawMessage `json:"abi"`
+	Metadata         string          `json:"metadata"` // solc metadata JSON, as a string
+	Bytecode         string          `json:"bytecode"`
+	DeployedBytecode string          `json:"deployedBytecode"`
+	SourcePath       string          `json:"sourcePath"` // absolute path on the machine that compiled
+	AST              json.RawMessage `json:"ast"`
+	Compiler         struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"compiler"`
+}
+
+// artifact is a read Truffle artifact with its parsed metadata
+type artifact struct {
+	path       string
+	name       string
+	sourcePath string // compilation target from the metadata, e.g. project:/contracts/Token.sol
+	raw        TruffleArtifact
+	metadata   foundry.FoundryMetadata
+}
+
+func (a *artifact) kind() string {
+	return foundry.ContractKind(a.raw.AST, a.raw.DeployedBytecode, a.name)
+}
+
+// readArtifact reads and parses a Truffle artifact. Artifacts without metadata
+// (compiled by Truffle < 4) are still read, with the source path left empty.
+func readArtifact(artifactPath string) (*artifact, error) {
+	data, err := os.ReadFile(artifactPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading artifact: %w", err)
+	}
+
+	a := &artifact{path: artifactPath}
+	if err := json.Unmarshal(data, &a.raw); err != nil {
+		return nil, fmt.Errorf("parsing artifact JSON: %w", err)
+	}
+	if a.raw.Metadata != "" {
+		if err := json.Unmarshal([]byte(a.raw.Metadata), &a.metadata); err != nil {
+			return nil, fmt.Errorf("parsing metadata: %w", err)
+		}
+	}
+
+	for source, name := range a.metadata.Settings.CompilationTarget {
+		a.sourcePath, a.name = source, name
+	}
+	if a.name == "" {
+		a.name = a.raw.ContractName
+	}
+	if a.name == "" {
+		a.name = strings.TrimSuffix(filepath.Base(artifactPath), ".json")
+	}
+	return a, nil
+}
+
+// readArtifacts reads every artifact in the build directory, skipping files that
+// aren't Truffle artifacts.
+func (b *Builder) readArtifacts(dir string) ([]*artifact, error) {
+	buildDir := b.BuildDir(dir)
+	entries, err := os.ReadDir(buildDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("build directory build/contracts/ not found - run 'truffle compile' first")
+		}
+		return nil, fmt.Errorf("reading build directory: %w", err)
+	}
+
+	var artifacts []*artifact
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		a, err := readArtifact(filepath.Join(buildDir, entry.Name()))
+		if err != nil {
+			continue // Skip artifacts we can't read
+		}
+		artifacts = append(artifacts, a)
+	}
+	return artifacts, nil
+}
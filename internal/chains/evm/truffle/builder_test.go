@@ -0,0 +1,193 @@
+package truffle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/chains"
+)
+
+// writeArtifact writes build/contracts/<name>.json with metadata compiled from source.
+func writeArtifact(t *testing.T, dir, name, source, bytecode string, extra map[string]any) string {
+	t.Helper()
+	metadata, err := json.Marshal(map[string]any{
+		"compiler": map[string]any{"version": "0.8.19+commit.7dd6d404"},
+		"language": "Solidity",
+		"settings": map[string]any{
+			"compilationTarget": map[string]string{source: name},
+			"evmVersion":        "paris",
+			"optimizer":         map[string]any{"enabled": true, "runs": 200},
+			"remappings":        []string{},
+		},
+		"sources": map[string]any{source: map[string]any{"keccak256": "0x01", "license": "MIT"}},
+		"version": 1,
+	})
+	require.NoError(t, err)
+
+	artifact := map[string]any{
+		"contractName":     name,
+		"abi":              []map[string]any{{"type": "function", "name": "transfer"}},
+		"metadata":         string(metadata),
+		"bytecode":         bytecode,
+		"deployedBytecode": bytecode,
+		"sourcePath":       "/home/ci/project/" + source,
+		"compiler":         map[string]any{"name": "solc", "version": "0.8.19+commit.7dd6d404.Emscripten.clang"},
+	}
+	for k, v := range extra {
+		artifact[k] = v
+	}
+	data, err := json.Marshal(artifact)
+	require.NoError(t, err)
+
+	buildDir := filepath.Join(dir, "build", "contracts")
+	require.NoError(t, os.MkdirAll(buildDir, 0755))
+	path := filepath.Join(buildDir, name+".json")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+func TestBuilder_Metadata(t *testing.T) {
+	b := New()
+
+	assert.Equal(t, "truffle", b.Name())
+	assert.Equal(t, "Truffle", b.DisplayName())
+	assert.Equal(t, "evm", b.Chain())
+	assert.Equal(t, "truffle-config.js", b.ConfigFile())
+}
+
+func TestBuilder_Detect(t *testing.T) {
+	b := New()
+
+	for _, config := range []string{"truffle-config.js", "truffle.js"} {
+		t.Run(config, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, config), []byte("module.exports = {};"), 0644))
+
+			detected, err := b.Detect(dir)
+			require.NoError(t, err)
+			assert.True(t, detected)
+		})
+	}
+
+	t.Run("without config", func(t *testing.T) {
+		detected, err := b.Detect(t.TempDir())
+		require.NoError(t, err)
+		assert.False(t, detected)
+	})
+}
+
+func TestBuilder_Discover(t *testing.T) {
+	b := New()
+	dir := t.TempDir()
+	token := writeArtifact(t, dir, "Token", "project:/contracts/Token.sol", "0x6080", nil)
+	mock := writeArtifact(t, dir, "MockToken", "project:/contracts/mocks/MockToken.sol", "0x6080", nil)
+	erc20 := writeArtifact(t, dir, "ERC20", "@openzeppelin/contracts/token/ERC20/ERC20.sol", "0x6080", nil)
+	iface := writeArtifact(t, dir, "IToken", "project:/contracts/IToken.sol", "0x", map[string]any{
+		"ast": map[string]any{"nodes": []map[string]any{{"nodeType": "ContractDefinition", "name": "IToken", "contractKind": "interface"}}},
+	})
+
+	t.Run("project contracts only", func(t *testing.T) {
+		paths, err := b.Discover(dir, chains.DiscoverOptions{})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{token, mock, iface}, paths)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		paths, err := b.Discover(dir, chains.DiscoverOptions{
+			Exclude:             []string{"Mock"},
+			ExcludeKinds:        []string{"interface"},
+			IncludeDependencies: []string{"erc20"},
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{token, erc20}, paths)
+
+		paths, err = b.Discover(dir, chains.DiscoverOptions{ExcludePaths: []string{"mocks"}, Contracts: []string{"MockToken", "Token"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{token}, paths)
+	})
+
+	t.Run("not compiled", func(t *testing.T) {
+		_, err := b.Discover(t.TempDir(), chains.DiscoverOptions{})
+		assert.ErrorContains(t, err, "run 'truffle compile' first")
+	})
+
+	t.Run("dependencies", func(t *testing.T) {
+		deps, err := b.DiscoverDependencies(dir)
+		require.NoError(t, err)
+		assert.Equal(t, []chains.DependencyInfo{{Name: "ERC20", SourcePath: "@openzeppelin/contracts/token/ERC20/ERC20.sol"}}, deps)
+	})
+}
+
+func TestBuilder_Parse(t *testing.T) {
+	b := New()
+	dir := t.TempDir()
+
+	t.Run("contract", func(t *testing.T) {
+		path := writeArtifact(t, dir, "Token", "project:/contracts/Token.sol", "0x6080", nil)
+
+		artifact, err := b.Parse(path)
+		require.NoError(t, err)
+		assert.Equal(t, "Token", artifact.Name)
+		assert.Equal(t, "evm", artifact.Chain)
+		assert.Equal(t, "project:/contracts/Token.sol", artifact.EVM.SourcePath)
+		assert.Equal(t, "MIT", artifact.EVM.License)
+		assert.Equal(t, "0x6080", artifact.EVM.Bytecode)
+		assert.Equal(t, "0x6080", artifact.EVM.DeployedBytecode)
+		assert.Equal(t, chains.EVMCompiler{
+			Version:    "0.8.19+commit.7dd6d404",
+			EVMVersion: "paris",
+			Optimizer:  chains.OptimizerConfig{Enabled: true, Runs: 200},
+		}, artifact.EVM.Compiler)
+		assert.JSONEq(t, `[{"type":"function","name":"transfer"}]`, string(artifact.EVM.ABI))
+	})
+
+	t.Run("interface", func(t *testing.T) {
+		path := writeArtifact(t, dir, "IToken", "project:/contracts/IToken.sol", "0x", nil)
+		_, err := b.Parse(path)
+		assert.ErrorContains(t, err, "no bytecode")
+	})
+}
+
+func TestBuilder_GetVerificationInput(t *testing.T) {
+	b := New()
+	dir := t.TempDir()
+	writeArtifact(t, dir, "Token", "project:/contracts/Token.sol", "0x6080", nil)
+	writeArtifact(t, dir, "ERC20", "@openzeppelin/contracts/token/ERC20/ERC20.sol", "0x6080", nil)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "contracts"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "contracts", "Token.sol"), []byte("contract Token {}"), 0644))
+	ozDir := filepath.Join(dir, "node_modules", "@openzeppelin", "contracts", "token", "ERC20")
+	require.NoError(t, os.MkdirAll(ozDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(ozDir, "ERC20.sol"), []byte("contract ERC20 {}"), 0644))
+
+	for name, source := range map[string]string{
+		"Token": "project:/contracts/Token.sol",
+		"ERC20": "@openzeppelin/contracts/token/ERC20/ERC20.sol",
+	} {
+		vi, err := b.GetVerificationInput(dir, name, source)
+		require.NoError(t, err, name)
+		assert.Equal(t, "0.8.19+commit.7dd6d404", vi.SolcLongVersion)
+
+		var input struct {
+			Language string `json:"language"`
+			Sources  map[string]struct {
+				Content string `json:"content"`
+			} `json:"sources"`
+			Settings struct {
+				EVMVersion string `json:"evmVersion"`
+			} `json:"settings"`
+		}
+		require.NoError(t, json.Unmarshal(vi.StandardJSON, &input))
+		assert.Equal(t, "Solidity", input.Language)
+		assert.Equal(t, "paris", input.Settings.EVMVersion)
+		assert.Equal(t, "contract "+name+" {}", input.Sources[source].Content, "source units keep their metadata names")
+	}
+
+	_, err := b.GetVerificationInput(dir, "Token", "contracts/Other.sol")
+	assert.ErrorContains(t, err, "artifact not found for contract Token")
+}
@@ -0,0 +1,74 @@
+// Package networks lists the EVM networks the registry knows by chain ID, with
+// their canonical names and block explorers.
+package networks
+
+import (
+	"slices"
+	"strings"
+)
+
+// Network is a known EVM network.
+type Network struct {
+	ChainID     string `json:"chainId"`
+	Name        string `json:"name"` // canonical name, e.g. "base-sepolia"
+	ExplorerURL string `json:"explorerUrl,omitempty"`
+	Testnet     bool   `json:"testnet"`
+}
+
+// known holds every network the registry knows, ordered by chain ID.
+var known = []Network{
+	{ChainID: "1", Name: "ethereum", ExplorerURL: "https://etherscan.io"},
+	{ChainID: "10", Name: "optimism", ExplorerURL: "https://optimistic.etherscan.io"},
+	{ChainID: "56", Name: "bsc", ExplorerURL: "https://bscscan.com"},
+	{ChainID: "100", Name: "gnosis", ExplorerURL: "https://gnosisscan.io"},
+	{ChainID: "137", Name: "polygon", ExplorerURL: "https://polygonscan.com"},
+	{ChainID: "250", Name: "fantom", ExplorerURL: "https://ftmscan.com"},
+	{ChainID: "8453", Name: "base", ExplorerURL: "https://basescan.org"},
+	{ChainID: "17000", Name: "holesky", ExplorerURL: "https://holesky.etherscan.io", Testnet: true},
+	{ChainID: "31337", Name: "anvil", Testnet: true},
+	{ChainID: "42161", Name: "arbitrum", ExplorerURL: "https://arbiscan.io"},
+	{ChainID: "43114", Name: "avalanche", ExplorerURL: "https://snowtrace.io"},
+	{ChainID: "59144", Name: "linea", ExplorerURL: "https://lineascan.build"},
+	{ChainID: "80002", Name: "polygon-amoy", ExplorerURL: "https://amoy.polygonscan.com", Testnet: true},
+	{ChainID: "84532", Name: "base-sepolia", ExplorerURL: "https://sepolia.basescan.org", Testnet: true},
+	{ChainID: "421614", Name: "arbitrum-sepolia", ExplorerURL: "https://sepolia.arbiscan.io", Testnet: true},
+	{ChainID: "534352", Name: "scroll", ExplorerURL: "https://scrollscan.com"},
+	{ChainID: "11155111", Name: "sepolia", ExplorerURL: "https://sepolia.etherscan.io", Testnet: true},
+	{ChainID: "11155420", Name: "optimism-sepolia", ExplorerURL: "https://sepolia-optimism.etherscan.io", Testnet: true},
+}
+
+// byChainID indexes known by chain ID.
+var byChainID = func() map[string]Network {
+	m := make(map[string]Network, len(known))
+	for _, n := range known {
+		m[n.ChainID] = n
+	}
+	return m
+}()
+
+// All returns the known networks ordered by chain ID.
+func All() []Network {
+	return slices.Clone(known)
+}
+
+// Lookup returns the network with chainID, if it is known.
+func Lookup(chainID string) (Network, bool) {
+	n, ok := byChainID[strings.TrimSpace(chainID)]
+	return n, ok
+}
+
+// Name returns the canonical name of chainID, or "" when it isn't known.
+func Name(chainID string) string {
+	n, _ := Lookup(chainID)
+	return n.Name
+}
+
+// ExplorerAddressURL returns the explorer page showing the contract code at address,
+// or "" when the chain has no known explorer.
+func ExplorerAddressURL(chainID, address string) string {
+	n, ok := Lookup(chainID)
+	if !ok || n.ExplorerURL == "" {
+		return ""
+	}
+	return n.ExplorerURL + "/address/" + address + "#code"
+}
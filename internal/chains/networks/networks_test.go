@@ -0,0 +1,46 @@
+package networks
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAll_OrderedAndUnique(t *testing.T) {
+	var prev uint64
+	names := map[string]bool{}
+	for i, n := range All() {
+		id, err := strconv.ParseUint(n.ChainID, 10, 64)
+		assert.NoError(t, err, n.ChainID)
+		if i > 0 {
+			assert.Greater(t, id, prev, "networks must be ordered by chain ID")
+		}
+		prev = id
+		assert.False(t, names[n.Name], "duplicate name %s", n.Name)
+		names[n.Name] = true
+	}
+}
+
+func TestLookup(t *testing.T) {
+	n, ok := Lookup("8453")
+	assert.True(t, ok)
+	assert.Equal(t, "base", n.Name)
+	assert.False(t, n.Testnet)
+
+	n, ok = Lookup(" 11155111 ")
+	assert.True(t, ok)
+	assert.Equal(t, "sepolia", n.Name)
+	assert.True(t, n.Testnet)
+
+	_, ok = Lookup("99999")
+	assert.False(t, ok)
+	assert.Equal(t, "", Name("99999"))
+}
+
+func TestExplorerAddressURL(t *testing.T) {
+	addr := "0x1234567890123456789012345678901234567890"
+	assert.Equal(t, "https://etherscan.io/address/"+addr+"#code", ExplorerAddressURL("1", addr))
+	assert.Equal(t, "", ExplorerAddressURL("31337", addr), "local networks have no explorer")
+	assert.Equal(t, "", ExplorerAddressURL("99999", addr))
+}
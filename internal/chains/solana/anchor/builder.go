@@ -0,0 +1,222 @@
+// Package anchor provides the Anchor builder for Solana programs.
+package anchor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pendergraft/contrafactory/internal/base58"
+	"github.com/pendergraft/contrafactory/internal/chains"
+)
+
+// Builder implements chains.Builder for Anchor projects
+type Builder struct{}
+
+// New creates a new Anchor builder
+func New() *Builder {
+	return &Builder{}
+}
+
+// Name returns the builder identifier
+func (b *Builder) Name() string {
+	return "anchor"
+}
+
+// DisplayName returns a human-readable name
+func (b *Builder) DisplayName() string {
+	return "Anchor"
+}
+
+// Chain returns the chain this builder targets
+func (b *Builder) Chain() string {
+	return "solana"
+}
+
+// ConfigFile returns the config file name
+func (b *Builder) ConfigFile() string {
+	return "Anchor.toml"
+}
+
+// Detect checks if a directory is an Anchor project
+func (b *Builder) Detect(dir string) (bool, error) {
+	_, err := os.Stat(filepath.Join(dir, b.ConfigFile()))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Discover finds all program binaries (target/deploy/*.so) in an Anchor project
+func (b *Builder) Discover(dir string, opts chains.DiscoverOptions) ([]string, error) {
+	deployDir := filepath.Join(dir, "target", "deploy")
+	if _, err := os.Stat(deployDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("target/deploy directory not found - run 'anchor build' first")
+	}
+
+	matches, err := filepath.Glob(filepath.Join(deployDir, "*.so"))
+	if err != nil {
+		return nil, fmt.Errorf("listing programs: %w", err)
+	}
+	sort.Strings(matches)
+
+	var programs []string
+	for _, path := range matches {
+		name := strings.TrimSuffix(filepath.Base(path), ".so")
+		if !includeProgram(name, opts) {
+			continue
+		}
+		programs = append(programs, path)
+	}
+	return programs, nil
+}
+
+// includeProgram applies the Contracts allowlist and Exclude patterns to a program name
+func includeProgram(name string, opts chains.DiscoverOptions) bool {
+	if len(opts.Contracts) > 0 {
+		included := false
+		for _, c := range opts.Contracts {
+			if c == name {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	for _, pattern := range opts.Exclude {
+		if strings.HasPrefix(name, pattern) || strings.HasSuffix(name, pattern) {
+			return false
+		}
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse reads a program binary plus its IDL and keypair from the Anchor target directory.
+// artifactPath is target/deploy/<program>.so.
+func (b *Builder) Parse(artifactPath string) (*chains.Artifact, error) {
+	binary, err := os.ReadFile(artifactPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading program: %w", err)
+	}
+	if len(binary) == 0 {
+		return nil, fmt.Errorf("program binary %s is empty", filepath.Base(artifactPath))
+	}
+
+	name := strings.TrimSuffix(filepath.Base(artifactPath), ".so")
+	deployDir := filepath.Dir(artifactPath)
+	targetDir := filepath.Dir(deployDir)
+	projectDir := filepath.Dir(targetDir)
+
+	hash := sha256.Sum256(binary)
+	sol := &chains.SolanaArtifact{
+		SourcePath:  programSourcePath(projectDir, name),
+		ProgramHash: hex.EncodeToString(hash[:]),
+		Binary:      binary,
+	}
+
+	// IDL is optional (programs built without the IDL feature have none)
+	idlPath := filepath.Join(targetDir, "idl", name+".json")
+	if idl, err := os.ReadFile(idlPath); err == nil {
+		if !json.Valid(idl) {
+			return nil, fmt.Errorf("invalid IDL JSON in %s", idlPath)
+		}
+		sol.IDL = idl
+		sol.ProgramID = programIDFromIDL(idl)
+	}
+
+	if sol.ProgramID == "" {
+		keypairPath := filepath.Join(deployDir, name+"-keypair.json")
+		if id, err := programIDFromKeypair(keypairPath); err == nil {
+			sol.ProgramID = id
+		}
+	}
+
+	return &chains.Artifact{
+		Name:   name,
+		Chain:  "solana",
+		Solana: sol,
+	}, nil
+}
+
+// programSourcePath returns programs/<name>/src/lib.rs when it exists.
+// Anchor uses snake_case binaries for kebab-case crate directories, so both are tried.
+func programSourcePath(projectDir, name string) string {
+	for _, dirName := range []string{name, strings.ReplaceAll(name, "_", "-")} {
+		rel := filepath.ToSlash(filepath.Join("programs", dirName, "src", "lib.rs"))
+		if _, err := os.Stat(filepath.Join(projectDir, rel)); err == nil {
+			return rel
+		}
+	}
+	return ""
+}
+
+// programIDFromIDL reads the program address from an IDL.
+// Anchor >= 0.30 uses a top-level "address"; older versions use "metadata.address".
+func programIDFromIDL(idl []byte) string {
+	var parsed struct {
+		Address  string `json:"address"`
+		Metadata struct {
+			Address string `json:"address"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(idl, &parsed); err != nil {
+		return ""
+	}
+	if parsed.Address != "" {
+		return parsed.Address
+	}
+	return parsed.Metadata.Address
+}
+
+// programIDFromKeypair derives the program address from a Solana CLI keypair file
+// (a JSON array of 64 bytes: 32-byte secret seed followed by the 32-byte public key).
+func programIDFromKeypair(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var ints []int
+	if err := json.Unmarshal(data, &ints); err != nil {
+		return "", fmt.Errorf("parsing keypair: %w", err)
+	}
+	if len(ints) != 64 {
+		return "", fmt.Errorf("keypair has %d bytes, want 64", len(ints))
+	}
+	keypair := make([]byte, len(ints))
+	for i, v := range ints {
+		if v < 0 || v > 255 {
+			return "", fmt.Errorf("keypair byte %d out of range", i)
+		}
+		keypair[i] = byte(v)
+	}
+	return base58.Encode(keypair[32:]), nil
+}
+
+// GenerateVerificationInput is not applicable to Solana programs
+func (b *Builder) GenerateVerificationInput(dir string, contractName string) ([]byte, error) {
+	return nil, fmt.Errorf("verification input is not supported for Anchor programs")
+}
+
+// GetVerificationInput is not applicable to Solana programs
+func (b *Builder) GetVerificationInput(dir string, contractName string, sourcePath string) (*chains.VerificationInput, error) {
+	return nil, fmt.Errorf("verification input is not supported for Anchor programs")
+}
+
+// DiscoverDependencies returns nothing; Anchor programs don't ship dependency artifacts
+func (b *Builder) DiscoverDependencies(dir string) ([]chains.DependencyInfo, error) {
+	return nil, nil
+}
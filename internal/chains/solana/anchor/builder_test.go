@@ -0,0 +1,127 @@
+package anchor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/chains"
+)
+
+// writeAnchorProject creates an Anchor project with built programs
+func writeAnchorProject(t *testing.T, programs ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Anchor.toml"), []byte("[programs.localnet]\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "target", "deploy"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "target", "idl"), 0755))
+	for _, p := range programs {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "target", "deploy", p+".so"), []byte("\x7fELF"+p), 0644))
+	}
+	return dir
+}
+
+func TestBuilder_Metadata(t *testing.T) {
+	b := New()
+
+	assert.Equal(t, "anchor", b.Name())
+	assert.Equal(t, "Anchor", b.DisplayName())
+	assert.Equal(t, "solana", b.Chain())
+	assert.Equal(t, "Anchor.toml", b.ConfigFile())
+}
+
+func TestBuilder_Detect(t *testing.T) {
+	b := New()
+
+	detected, err := b.Detect(writeAnchorProject(t))
+	require.NoError(t, err)
+	assert.True(t, detected)
+
+	detected, err = b.Detect(t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, detected)
+}
+
+func TestBuilder_Discover(t *testing.T) {
+	b := New()
+
+	t.Run("finds programs", func(t *testing.T) {
+		dir := writeAnchorProject(t, "counter", "escrow", "mock_oracle")
+
+		paths, err := b.Discover(dir, chains.DiscoverOptions{Exclude: []string{"mock"}})
+		require.NoError(t, err)
+		require.Len(t, paths, 2)
+		assert.Equal(t, "counter.so", filepath.Base(paths[0]))
+		assert.Equal(t, "escrow.so", filepath.Base(paths[1]))
+	})
+
+	t.Run("contracts allowlist", func(t *testing.T) {
+		dir := writeAnchorProject(t, "counter", "escrow")
+
+		paths, err := b.Discover(dir, chains.DiscoverOptions{Contracts: []string{"escrow"}})
+		require.NoError(t, err)
+		require.Len(t, paths, 1)
+		assert.Equal(t, "escrow.so", filepath.Base(paths[0]))
+	})
+
+	t.Run("not built", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "Anchor.toml"), nil, 0644))
+
+		_, err := b.Discover(dir, chains.DiscoverOptions{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "anchor build")
+	})
+}
+
+func TestBuilder_Parse(t *testing.T) {
+	b := New()
+
+	t.Run("program id from IDL", func(t *testing.T) {
+		dir := writeAnchorProject(t, "counter")
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "programs", "counter", "src"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "programs", "counter", "src", "lib.rs"), nil, 0644))
+		idl := `{"address":"Fg6PaFpoGXkYsidMpWTK6W2BeZ7FEfcYkg476zPFsLnS","metadata":{"name":"counter"},"instructions":[]}`
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "target", "idl", "counter.json"), []byte(idl), 0644))
+
+		artifact, err := b.Parse(filepath.Join(dir, "target", "deploy", "counter.so"))
+		require.NoError(t, err)
+
+		assert.Equal(t, "counter", artifact.Name)
+		assert.Equal(t, "solana", artifact.Chain)
+		assert.Nil(t, artifact.EVM)
+		require.NotNil(t, artifact.Solana)
+		assert.Equal(t, "Fg6PaFpoGXkYsidMpWTK6W2BeZ7FEfcYkg476zPFsLnS", artifact.Solana.ProgramID)
+		assert.Equal(t, "programs/counter/src/lib.rs", artifact.Solana.SourcePath)
+		assert.JSONEq(t, idl, string(artifact.Solana.IDL))
+		assert.Equal(t, []byte("\x7fELFcounter"), artifact.Solana.Binary)
+		assert.Len(t, artifact.Solana.ProgramHash, 64)
+	})
+
+	t.Run("program id from keypair", func(t *testing.T) {
+		dir := writeAnchorProject(t, "my_program")
+		keypair := make([]int, 64) // all-zero public key encodes to the system program ID
+		data, err := json.Marshal(keypair)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "target", "deploy", "my_program-keypair.json"), data, 0644))
+
+		artifact, err := b.Parse(filepath.Join(dir, "target", "deploy", "my_program.so"))
+		require.NoError(t, err)
+		assert.Equal(t, "11111111111111111111111111111111", artifact.Solana.ProgramID)
+		assert.Nil(t, artifact.Solana.IDL)
+	})
+
+	t.Run("kebab-case program directory", func(t *testing.T) {
+		dir := writeAnchorProject(t, "my_program")
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "programs", "my-program", "src"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "programs", "my-program", "src", "lib.rs"), nil, 0644))
+
+		artifact, err := b.Parse(filepath.Join(dir, "target", "deploy", "my_program.so"))
+		require.NoError(t, err)
+		assert.Equal(t, "programs/my-program/src/lib.rs", artifact.Solana.SourcePath)
+	})
+}
@@ -0,0 +1,11 @@
+package solana
+
+import (
+	"github.com/pendergraft/contrafactory/internal/chains"
+	"github.com/pendergraft/contrafactory/internal/chains/solana/anchor"
+)
+
+// NewAnchorBuilder creates a new Anchor builder
+func NewAnchorBuilder() chains.Builder {
+	return anchor.New()
+}
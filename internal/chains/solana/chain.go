@@ -0,0 +1,71 @@
+// Package solana provides the Solana chain module for on-chain programs.
+package solana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pendergraft/contrafactory/internal/chains"
+)
+
+// Chain implements the chains.Chain interface for Solana
+type Chain struct {
+	builders   []chains.Builder
+	httpClient *http.Client
+}
+
+// NewChain creates a new Solana chain module
+func NewChain() *Chain {
+	return &Chain{
+		builders: []chains.Builder{
+			NewAnchorBuilder(),
+		},
+		httpClient: http.DefaultClient,
+	}
+}
+
+// Name returns the chain identifier
+func (c *Chain) Name() string {
+	return "solana"
+}
+
+// DisplayName returns a human-readable name
+func (c *Chain) DisplayName() string {
+	return "Solana"
+}
+
+// Builders returns all available builders for this chain
+func (c *Chain) Builders() []chains.Builder {
+	return c.builders
+}
+
+// DetectBuilder detects which builder is used in the given directory
+func (c *Chain) DetectBuilder(dir string) (chains.Builder, error) {
+	for _, b := range c.builders {
+		detected, err := b.Detect(dir)
+		if err != nil {
+			continue
+		}
+		if detected {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("no Solana builder detected in %s", dir)
+}
+
+// VerifyDeployment verifies that the on-chain program matches the expected binary
+func (c *Chain) VerifyDeployment(ctx context.Context, opts chains.VerifyOptions) (*chains.VerifyResult, error) {
+	deployed, err := c.GetDeployedBytecode(ctx, opts.RPC, opts.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program data: %w", err)
+	}
+
+	return CompareProgram(deployed, opts.ExpectedCode), nil
+}
+
+// GetDeployedBytecode fetches the program binary for a program ID.
+// For upgradeable programs this follows the program account to its program data account.
+func (c *Chain) GetDeployedBytecode(ctx context.Context, rpc string, address string) ([]byte, error) {
+	return fetchProgramData(ctx, c.httpClient, rpc, address)
+}
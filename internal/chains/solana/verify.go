@@ -0,0 +1,150 @@
+package solana
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pendergraft/contrafactory/internal/base58"
+	"github.com/pendergraft/contrafactory/internal/chains"
+)
+
+// Loader program IDs that own executable program accounts
+const (
+	upgradeableLoaderID = "BPFLoaderUpgradeab1e11111111111111111111111"
+)
+
+// Upgradeable loader account layout (bincode-serialized UpgradeableLoaderState)
+const (
+	programAccountTag     = 2
+	programDataAccountTag = 3
+	programAccountSize    = 4 + 32         // tag + programdata address
+	programDataHeaderSize = 4 + 8 + 1 + 32 // tag + slot + Option<authority>
+)
+
+// CompareProgram compares on-chain program bytes to a locally built .so.
+// Program data accounts are allocated larger than the ELF and zero-padded,
+// so trailing zeros are ignored on both sides.
+func CompareProgram(deployed, expected []byte) *chains.VerifyResult {
+	if bytes.Equal(deployed, expected) {
+		return &chains.VerifyResult{
+			Match:     true,
+			MatchType: "full",
+			Message:   "Program binary matches exactly",
+		}
+	}
+
+	if len(expected) > 0 && bytes.Equal(bytes.TrimRight(deployed, "\x00"), bytes.TrimRight(expected, "\x00")) {
+		return &chains.VerifyResult{
+			Match:     true,
+			MatchType: "full",
+			Message:   "Program binary matches (ignoring account padding)",
+		}
+	}
+
+	return &chains.VerifyResult{
+		Match:     false,
+		MatchType: "none",
+		Message:   "Program binary does not match",
+	}
+}
+
+// accountInfo is the subset of getAccountInfo we use
+type accountInfo struct {
+	Data  []byte
+	Owner string
+}
+
+// fetchProgramData returns the executable bytes for a program ID, with account padding trimmed.
+func fetchProgramData(ctx context.Context, client *http.Client, rpc, programID string) ([]byte, error) {
+	program, err := getAccountInfo(ctx, client, rpc, programID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Legacy (non-upgradeable) loaders store the ELF directly in the program account
+	if program.Owner != upgradeableLoaderID {
+		return bytes.TrimRight(program.Data, "\x00"), nil
+	}
+
+	if len(program.Data) < programAccountSize || binary.LittleEndian.Uint32(program.Data) != programAccountTag {
+		return nil, fmt.Errorf("account %s is not an upgradeable program", programID)
+	}
+	programDataID := base58.Encode(program.Data[4:programAccountSize])
+
+	programData, err := getAccountInfo(ctx, client, rpc, programDataID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching program data account: %w", err)
+	}
+	if len(programData.Data) < programDataHeaderSize || binary.LittleEndian.Uint32(programData.Data) != programDataAccountTag {
+		return nil, fmt.Errorf("account %s is not a program data account", programDataID)
+	}
+
+	return bytes.TrimRight(programData.Data[programDataHeaderSize:], "\x00"), nil
+}
+
+// getAccountInfo calls the getAccountInfo JSON-RPC method with base64 encoding
+func getAccountInfo(ctx context.Context, client *http.Client, rpc, address string) (*accountInfo, error) {
+	reqBody, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "getAccountInfo",
+		"params":  []any{address, map[string]string{"encoding": "base64"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpc, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("creating RPC request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling RPC: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("RPC returned HTTP %d", resp.StatusCode)
+	}
+
+	var rpcResp struct {
+		Result *struct {
+			Value *struct {
+				Data  []string `json:"data"`
+				Owner string   `json:"owner"`
+			} `json:"value"`
+		} `json:"result"`
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return nil, fmt.Errorf("decoding RPC response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return nil, fmt.Errorf("RPC error %d: %s", rpcResp.Error.Code, rpcResp.Error.Message)
+	}
+	if rpcResp.Result == nil || rpcResp.Result.Value == nil {
+		return nil, fmt.Errorf("account %s not found", address)
+	}
+
+	value := rpcResp.Result.Value
+	if len(value.Data) == 0 {
+		return nil, fmt.Errorf("account %s has no data", address)
+	}
+	data, err := base64.StdEncoding.DecodeString(value.Data[0])
+	if err != nil {
+		return nil, fmt.Errorf("decoding account data: %w", err)
+	}
+
+	return &accountInfo{Data: data, Owner: value.Owner}, nil
+}
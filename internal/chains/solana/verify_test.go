@@ -0,0 +1,122 @@
+package solana
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/base58"
+	"github.com/pendergraft/contrafactory/internal/chains"
+)
+
+func TestCompareProgram(t *testing.T) {
+	elf := []byte("\x7fELFprogram")
+
+	tests := []struct {
+		name      string
+		deployed  []byte
+		wantMatch bool
+	}{
+		{"exact", elf, true},
+		{"zero padded", append(append([]byte{}, elf...), 0, 0, 0, 0), true},
+		{"different", []byte("\x7fELFother"), false},
+		{"empty", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CompareProgram(tt.deployed, elf)
+			assert.Equal(t, tt.wantMatch, result.Match)
+		})
+	}
+}
+
+// fakeRPC serves getAccountInfo for a fixed set of accounts
+func fakeRPC(t *testing.T, accounts map[string]struct {
+	owner string
+	data  []byte
+}) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Params []json.RawMessage
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "getAccountInfo", req.Method)
+		var address string
+		require.NoError(t, json.Unmarshal(req.Params[0], &address))
+
+		acct, ok := accounts[address]
+		if !ok {
+			json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": 1, "result": map[string]any{"value": nil}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"result": map[string]any{
+				"value": map[string]any{
+					"data":  []string{base64.StdEncoding.EncodeToString(acct.data), "base64"},
+					"owner": acct.owner,
+				},
+			},
+		})
+	}))
+}
+
+func TestChain_VerifyDeployment_Upgradeable(t *testing.T) {
+	elf := []byte("\x7fELFcounter")
+	programID := "Fg6PaFpoGXkYsidMpWTK6W2BeZ7FEfcYkg476zPFsLnS"
+	programDataKey := make([]byte, 32)
+	programDataKey[31] = 7
+	programDataID := base58.Encode(programDataKey)
+
+	programAccount := make([]byte, programAccountSize)
+	binary.LittleEndian.PutUint32(programAccount, programAccountTag)
+	copy(programAccount[4:], programDataKey)
+
+	programData := make([]byte, programDataHeaderSize, programDataHeaderSize+len(elf)+16)
+	binary.LittleEndian.PutUint32(programData, programDataAccountTag)
+	programData = append(programData, elf...)
+	programData = append(programData, make([]byte, 16)...) // allocation padding
+
+	srv := fakeRPC(t, map[string]struct {
+		owner string
+		data  []byte
+	}{
+		programID:     {owner: upgradeableLoaderID, data: programAccount},
+		programDataID: {owner: upgradeableLoaderID, data: programData},
+	})
+	defer srv.Close()
+
+	c := NewChain()
+	deployed, err := c.GetDeployedBytecode(context.Background(), srv.URL, programID)
+	require.NoError(t, err)
+	assert.Equal(t, elf, deployed)
+
+	result, err := c.VerifyDeployment(context.Background(), chains.VerifyOptions{
+		RPC:          srv.URL,
+		Address:      programID,
+		ExpectedCode: elf,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Match)
+	assert.Equal(t, "full", result.MatchType)
+}
+
+func TestChain_GetDeployedBytecode_NotFound(t *testing.T) {
+	srv := fakeRPC(t, nil)
+	defer srv.Close()
+
+	_, err := NewChain().GetDeployedBytecode(context.Background(), srv.URL, "Fg6PaFpoGXkYsidMpWTK6W2BeZ7FEfcYkg476zPFsLnS")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func createAliasCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Package version alias commands",
+		Long: `Manage named pointers to package versions, such as "stable" or "audited".
+
+Anywhere a version is accepted (fetch, info, abi, export, the API), an alias can
+be given instead and resolves to the version it points at, so consumers can pin
+to "my-token@audited" and pick up a new release once the alias moves.
+
+Aliases are lowercase letters, digits and dashes, start with a letter and can't
+look like a version or range. "latest" is reserved. Only the package owner can
+set or remove aliases.`,
+	}
+
+	cmd.AddCommand(createAliasSetCmd())
+	cmd.AddCommand(createAliasListCmd())
+	cmd.AddCommand(createAliasRemoveCmd())
+
+	return cmd
+}
+
+func createAliasSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <package> <alias> <version>",
+		Short: "Point an alias at a published version",
+		Long: `Create an alias or move it to another published version. Must be run with
+the owner's key.
+
+EXAMPLES:
+  contrafactory alias set my-token audited 1.2.0
+`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(getServer(), getAPIKey())
+			alias, err := c.SetPackageAlias(context.Background(), args[0], args[1], args[2])
+			if err != nil {
+				return fmt.Errorf("failed to set alias: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "✅ %s@%s → %s\n", args[0], alias.Alias, alias.Version)
+			return nil
+		},
+	}
+}
+
+func createAliasListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list <package>",
+		Short: "List the aliases of a package",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(getServer(), getAPIKey())
+			resp, err := c.ListPackageAliases(context.Background(), args[0])
+			if err != nil {
+				return fmt.Errorf("failed to list aliases: %w", err)
+			}
+			printAliases(cmd.OutOrStdout(), resp)
+			return nil
+		},
+	}
+}
+
+func createAliasRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm <package> <alias>",
+		Aliases: []string{"remove"},
+		Short:   "Remove an alias",
+		Long: `Remove an alias of a package. The version it pointed at is not affected.
+Must be run with the owner's key.
+
+EXAMPLES:
+  contrafactory alias rm my-token audited
+`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(getServer(), getAPIKey())
+			if err := c.DeletePackageAlias(context.Background(), args[0], args[1]); err != nil {
+				return fmt.Errorf("failed to remove alias: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "✅ Removed %s@%s\n", args[0], args[1])
+			return nil
+		},
+	}
+}
+
+func printAliases(out io.Writer, resp *client.PackageAliases) {
+	if len(resp.Aliases) == 0 {
+		fmt.Fprintf(out, "%s has no aliases\n", resp.Name)
+		return
+	}
+	fmt.Fprintf(out, "Aliases of %s:\n", resp.Name)
+	for _, a := range resp.Aliases {
+		fmt.Fprintf(out, "  %-16s %s", a.Alias, a.Version)
+		if a.UpdatedAt != "" {
+			fmt.Fprintf(out, "  (updated %s)", a.UpdatedAt)
+		}
+		fmt.Fprintln(out)
+	}
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasCommand(t *testing.T) {
+	aliases := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/packages/my-token/aliases":
+			list := []map[string]string{}
+			for alias, version := range aliases {
+				list = append(list, map[string]string{"alias": alias, "version": version})
+			}
+			json.NewEncoder(w).Encode(map[string]any{"name": "my-token", "aliases": list})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/packages/my-token/aliases/audited":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			aliases["audited"] = body["version"]
+			json.NewEncoder(w).Encode(map[string]string{"alias": "audited", "version": body["version"]})
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/packages/my-token/aliases/audited":
+			delete(aliases, "audited")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"Alias not found"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	origServer, origKey := server, apiKey
+	defer func() { server, apiKey = origServer, origKey }()
+	server, apiKey = srv.URL, "owner-key"
+
+	run := func(args ...string) (string, error) {
+		cmd := createAliasCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("set", "my-token", "audited", "1.2.0")
+	require.NoError(t, err)
+	assert.Contains(t, out, "my-token@audited → 1.2.0")
+
+	out, err = run("list", "my-token")
+	require.NoError(t, err)
+	assert.Contains(t, out, "audited")
+	assert.Contains(t, out, "1.2.0")
+
+	out, err = run("rm", "my-token", "audited")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Removed my-token@audited")
+
+	out, err = run("list", "my-token")
+	require.NoError(t, err)
+	assert.Contains(t, out, "my-token has no aliases")
+
+	_, err = run("rm", "my-token", "stable")
+	assert.ErrorContains(t, err, "Alias not found")
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func createABICmd() *cobra.Command {
+	var contract string
+	var compact bool
+
+	cmd := &cobra.Command{
+		Use:   "abi <package>/<contract>@<version>",
+		Short: "Print a contract's ABI",
+		Long: `Print the ABI of one contract to stdout, indented unless --compact is set.
+
+The version may be exact, latest or a range, as with fetch.
+
+EXAMPLES:
+  contrafactory abi my-token/Token@1.0.0
+  contrafactory abi my-token/Token@latest > Token.abi.json
+  contrafactory abi my-token@^1.0.0 --contract Token --compact | jq '.[].name'
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(getServer(), getAPIKey())
+			return runABI(context.Background(), cmd.OutOrStdout(), c, args[0], contract, compact)
+		},
+	}
+
+	cmd.Flags().StringVar(&contract, "contract", "", "contract (unless given in the reference)")
+	cmd.Flags().BoolVar(&compact, "compact", false, "print the ABI on one line")
+
+	return cmd
+}
+
+func createBytecodeCmd() *cobra.Command {
+	var contract string
+	var deployed bool
+
+	cmd := &cobra.Command{
+		Use:   "bytecode <package>/<contract>@<version>",
+		Short: "Print a contract's creation or deployed bytecode",
+		Long: `Print the bytecode of one contract to stdout as 0x-prefixed hex: the creation
+bytecode by default, or the runtime bytecode with --deployed.
+
+EXAMPLES:
+  contrafactory bytecode my-token/Token@1.0.0
+  contrafactory bytecode my-token/Token@latest --deployed
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(getServer(), getAPIKey())
+			return runBytecode(context.Background(), cmd.OutOrStdout(), c, args[0], contract, deployed)
+		},
+	}
+
+	cmd.Flags().StringVar(&contract, "contract", "", "contract (unless given in the reference)")
+	cmd.Flags().BoolVar(&deployed, "deployed", false, "print the deployed (runtime) bytecode")
+
+	return cmd
+}
+
+func runABI(ctx context.Context, out io.Writer, c *client.Client, ref, contract string, compact bool) error {
+	resolved, err := resolveContractRef(ctx, c, ref, contract)
+	if err != nil {
+		return err
+	}
+
+	abi, err := c.GetABI(ctx, resolved.Package, resolved.Version, resolved.Contract)
+	if err != nil {
+		return fmt.Errorf("fetching ABI of %s: %w", resolved, err)
+	}
+
+	var buf bytes.Buffer
+	if compact {
+		err = json.Compact(&buf, abi)
+	} else {
+		err = json.Indent(&buf, abi, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("ABI of %s is not valid JSON: %w", resolved, err)
+	}
+	buf.WriteByte('\n')
+	_, err = out.Write(buf.Bytes())
+	return err
+}
+
+func runBytecode(ctx context.Context, out io.Writer, c *client.Client, ref, contract string, deployed bool) error {
+	resolved, err := resolveContractRef(ctx, c, ref, contract)
+	if err != nil {
+		return err
+	}
+
+	get, kind := c.GetBytecode, "bytecode"
+	if deployed {
+		get, kind = c.GetDeployedBytecode, "deployed bytecode"
+	}
+	code, err := get(ctx, resolved.Package, resolved.Version, resolved.Contract)
+	if err != nil {
+		return fmt.Errorf("fetching %s of %s: %w", kind, resolved, err)
+	}
+	_, err = fmt.Fprintln(out, string(bytes.TrimSpace(code)))
+	return err
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func newArtifactTestServer(t *testing.T) *client.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/packages/my-token":
+			w.Write([]byte(`{"name":"my-token","versions":["2.0.0-rc.1","1.2.0","1.0.0"]}`))
+		case "/api/v1/packages/my-token/1.2.0/contracts/Token/abi":
+			w.Write([]byte(`[{"type":"function","name":"transfer"}]`))
+		case "/api/v1/packages/my-token/1.2.0/contracts/Token/bytecode":
+			w.Write([]byte("0x6080"))
+		case "/api/v1/packages/my-token/1.2.0/contracts/Token/deployed-bytecode":
+			w.Write([]byte("0x6090\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return client.New(srv.URL, "")
+}
+
+func TestRunABI(t *testing.T) {
+	c := newArtifactTestServer(t)
+
+	t.Run("indented, latest resolved", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, runABI(context.Background(), &buf, c, "my-token/Token@latest", "", false))
+		assert.Equal(t, "[\n  {\n    \"type\": \"function\",\n    \"name\": \"transfer\"\n  }\n]\n", buf.String())
+	})
+
+	t.Run("compact, contract flag and range", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, runABI(context.Background(), &buf, c, "my-token@^1.0.0", "Token", true))
+		assert.Equal(t, `[{"type":"function","name":"transfer"}]`+"\n", buf.String())
+	})
+
+	t.Run("contract required", func(t *testing.T) {
+		err := runABI(context.Background(), &bytes.Buffer{}, c, "my-token@1.2.0", "", false)
+		assert.ErrorContains(t, err, "contract name required")
+	})
+
+	t.Run("unknown contract", func(t *testing.T) {
+		err := runABI(context.Background(), &bytes.Buffer{}, c, "my-token/Vault@1.2.0", "", false)
+		assert.ErrorContains(t, err, "fetching ABI of my-token/Vault@1.2.0")
+	})
+}
+
+func TestRunBytecode(t *testing.T) {
+	c := newArtifactTestServer(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, runBytecode(context.Background(), &buf, c, "my-token/Token@1.2.0", "", false))
+	assert.Equal(t, "0x6080\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, runBytecode(context.Background(), &buf, c, "my-token/Token@1.2.0", "", true))
+	assert.Equal(t, "0x6090\n", buf.String())
+}
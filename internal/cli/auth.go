@@ -4,11 +4,14 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -16,11 +19,37 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-// Credentials stores API keys per server
+// Credentials stores API keys per server. Servers holds the default profile;
+// named profiles keep separate identities for the same servers.
 type Credentials struct {
+	Servers  map[string]ServerCredential `yaml:"servers"`
+	Profiles map[string]Profile          `yaml:"profiles,omitempty"`
+}
+
+// Profile is a named set of server credentials
+type Profile struct {
 	Servers map[string]ServerCredential `yaml:"servers"`
 }
 
+// profileServers returns the server credentials of a profile ("" is the default
+// profile), creating the profile when create is set.
+func (c *Credentials) profileServers(name string, create bool) map[string]ServerCredential {
+	if name == "" {
+		return c.Servers
+	}
+	if p, ok := c.Profiles[name]; ok {
+		return p.Servers
+	}
+	if !create {
+		return nil
+	}
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]Profile)
+	}
+	c.Profiles[name] = Profile{Servers: make(map[string]ServerCredential)}
+	return c.Profiles[name].Servers
+}
+
 // ServerCredential stores credentials for a single server
 type ServerCredential struct {
 	APIKey string `yaml:"api_key"`
@@ -49,7 +78,9 @@ func createAuthLoginCmd() *cobra.Command {
 		Short: "Authenticate with server",
 		Long: `Save API key credentials for a Contrafactory server.
 
-The API key is stored in ~/.contrafactory/credentials with secure file permissions.
+The API key is stored in ~/.contrafactory/credentials with secure file permissions,
+under the profile selected with --profile or CONTRAFACTORY_PROFILE (the default
+profile when neither is set).
 
 EXAMPLES:
   # Interactive login (prompts for API key)
@@ -60,6 +91,11 @@ EXAMPLES:
 
   # Non-interactive login (for CI)
   contrafactory auth login --api-key $CONTRAFACTORY_API_KEY
+
+  # Save a second identity for the same server under a named profile,
+  # then select it with --profile or CONTRAFACTORY_PROFILE
+  contrafactory auth login --profile staging
+  contrafactory publish --version 1.0.0 --profile staging
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runAuthLogin(serverFlag, apiKeyFlag)
@@ -88,6 +124,9 @@ EXAMPLES:
   # Logout from a specific server
   contrafactory auth logout --server https://contrafactory.example.com
 
+  # Logout a named profile from the default server
+  contrafactory auth logout --profile staging
+
   # Clear all credentials
   contrafactory auth logout --all
 `,
@@ -125,6 +164,9 @@ func runAuthLogin(serverURL, apiKeyInput string) error {
 		serverURL = getServer()
 	}
 
+	// Shared so the key and a later confirmation can both be read from piped stdin
+	stdin := bufio.NewReader(os.Stdin)
+
 	// Get API key
 	apiKey := apiKeyInput
 	if apiKey == "" {
@@ -142,8 +184,7 @@ func runAuthLogin(serverURL, apiKeyInput string) error {
 			apiKey = string(byteKey)
 		} else {
 			// Non-terminal, read from stdin
-			reader := bufio.NewReader(os.Stdin)
-			key, err := reader.ReadString('\n')
+			key, err := stdin.ReadString('\n')
 			if err != nil && err != io.EOF {
 				return fmt.Errorf("failed to read API key: %w", err)
 			}
@@ -155,15 +196,25 @@ func runAuthLogin(serverURL, apiKeyInput string) error {
 		return fmt.Errorf("API key cannot be empty")
 	}
 
-	// Validate the API key by making a request
+	// Validate the API key by asking the server who it belongs to
 	fmt.Printf("Validating credentials with %s...\n", serverURL)
-	valid, err := validateAPIKey(serverURL, apiKey)
-	if err != nil {
+	info, err := validateAPIKey(serverURL, apiKey)
+	var checkErr *keyCheckError
+	switch {
+	case errors.Is(err, errInvalidAPIKey):
+		return err
+	case errors.As(err, &checkErr):
+		// The server couldn't say either way; let the user decide rather than
+		// silently saving a key that may be bad
+		fmt.Printf("⚠️  Could not validate credentials: %v\n", checkErr)
+		fmt.Print("Save the API key anyway? [y/N]: ")
+		answer, _ := stdin.ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			return fmt.Errorf("credentials not saved: %w", checkErr)
+		}
+	case err != nil:
 		return fmt.Errorf("failed to validate credentials: %w", err)
 	}
-	if !valid {
-		return fmt.Errorf("invalid API key")
-	}
 
 	// Save credentials
 	if err := saveCredential(serverURL, apiKey); err != nil {
@@ -172,8 +223,29 @@ func runAuthLogin(serverURL, apiKeyInput string) error {
 
 	// Mask key for display
 	masked := maskAPIKey(apiKey)
-	fmt.Printf("✅ Authenticated to %s (key: %s)\n", serverURL, masked)
-	fmt.Printf("   Credentials saved to %s\n", credentialsFilePath())
+	switch {
+	case info == nil:
+		fmt.Printf("✅ Saved unvalidated credentials for %s (key: %s)\n", serverURL, masked)
+	case !info.AuthEnabled:
+		fmt.Printf("✅ Saved credentials for %s (key: %s)\n", serverURL, masked)
+		fmt.Println("   Note: this server does not require API keys")
+	default:
+		name := info.Name
+		if name == "" {
+			name = info.ID
+		}
+		fmt.Printf("✅ Authenticated to %s as %q (key: %s)\n", serverURL, name, masked)
+		scopes := "none"
+		if len(info.Scopes) > 0 {
+			scopes = strings.Join(info.Scopes, ", ")
+		}
+		fmt.Printf("   Scopes: %s\n", scopes)
+	}
+	if p := getProfile(); p != "" {
+		fmt.Printf("   Credentials saved to %s (profile %s)\n", credentialsFilePath(), p)
+	} else {
+		fmt.Printf("   Credentials saved to %s\n", credentialsFilePath())
+	}
 
 	return nil
 }
@@ -198,18 +270,23 @@ func runAuthLogout(serverURL string, all bool) error {
 		return fmt.Errorf("failed to load credentials: %w", err)
 	}
 
-	if _, exists := creds.Servers[serverURL]; !exists {
-		fmt.Printf("No credentials found for %s\n", serverURL)
+	profileName := getProfile()
+	servers := creds.profileServers(profileName, false)
+	if _, exists := servers[serverURL]; !exists {
+		fmt.Printf("No credentials found for %s%s\n", serverURL, profileSuffix(profileName))
 		return nil
 	}
 
-	delete(creds.Servers, serverURL)
+	delete(servers, serverURL)
+	if profileName != "" && len(servers) == 0 {
+		delete(creds.Profiles, profileName)
+	}
 
 	if err := writeCredentials(creds); err != nil {
 		return fmt.Errorf("failed to save credentials: %w", err)
 	}
 
-	fmt.Printf("✅ Logged out from %s\n", serverURL)
+	fmt.Printf("✅ Logged out from %s%s\n", serverURL, profileSuffix(profileName))
 	return nil
 }
 
@@ -224,14 +301,37 @@ func runAuthStatus() error {
 		return fmt.Errorf("failed to load credentials: %w", err)
 	}
 
-	if len(creds.Servers) == 0 {
+	if len(creds.Servers) == 0 && len(creds.Profiles) == 0 {
 		fmt.Println("Not authenticated to any servers")
 		fmt.Println("\nRun 'contrafactory auth login' to authenticate")
 		return nil
 	}
 
-	fmt.Println("Authenticated servers:")
-	for server, cred := range creds.Servers {
+	active := getProfile()
+	if len(creds.Servers) > 0 {
+		if active == "" && len(creds.Profiles) > 0 {
+			fmt.Println("Authenticated servers (default profile, active):")
+		} else {
+			fmt.Println("Authenticated servers:")
+		}
+		printServerCredentials(creds.Servers)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(creds.Profiles)) {
+		if name == active {
+			fmt.Printf("Profile %s (active):\n", name)
+		} else {
+			fmt.Printf("Profile %s:\n", name)
+		}
+		printServerCredentials(creds.Profiles[name].Servers)
+	}
+
+	return nil
+}
+
+func printServerCredentials(servers map[string]ServerCredential) {
+	for _, server := range slices.Sorted(maps.Keys(servers)) {
+		cred := servers[server]
 		masked := maskAPIKey(cred.APIKey)
 		if cred.Name != "" {
 			fmt.Printf("  • %s (%s, key: %s)\n", server, cred.Name, masked)
@@ -239,8 +339,14 @@ func runAuthStatus() error {
 			fmt.Printf("  • %s (key: %s)\n", server, masked)
 		}
 	}
+}
 
-	return nil
+// profileSuffix describes a non-default profile in messages.
+func profileSuffix(name string) string {
+	if name == "" {
+		return ""
+	}
+	return " (profile " + name + ")"
 }
 
 // Credential file helpers
@@ -272,6 +378,11 @@ func loadCredentials() (*Credentials, error) {
 	if creds.Servers == nil {
 		creds.Servers = make(map[string]ServerCredential)
 	}
+	for name, p := range creds.Profiles {
+		if p.Servers == nil {
+			creds.Profiles[name] = Profile{Servers: make(map[string]ServerCredential)}
+		}
+	}
 
 	return &creds, nil
 }
@@ -291,6 +402,7 @@ func writeCredentials(creds *Credentials) error {
 	return os.WriteFile(path, data, 0600) // Secure permissions
 }
 
+// saveCredential stores the key for serverURL in the selected profile.
 func saveCredential(serverURL, apiKey string) error {
 	creds, err := loadCredentials()
 	if err != nil {
@@ -301,50 +413,87 @@ func saveCredential(serverURL, apiKey string) error {
 		}
 	}
 
-	creds.Servers[serverURL] = ServerCredential{APIKey: apiKey}
+	creds.profileServers(getProfile(), true)[serverURL] = ServerCredential{APIKey: apiKey}
 	return writeCredentials(creds)
 }
 
+// getCredential returns the selected profile's key for serverURL, if any.
 func getCredential(serverURL string) string {
 	creds, err := loadCredentials()
 	if err != nil {
 		return ""
 	}
-	if cred, ok := creds.Servers[serverURL]; ok {
+	// A selected profile never falls back to the default one: using another
+	// identity than the one asked for would be worse than using none
+	if cred, ok := creds.profileServers(getProfile(), false)[serverURL]; ok {
 		return cred.APIKey
 	}
 	return ""
 }
 
-func validateAPIKey(serverURL, apiKey string) (bool, error) {
-	// Make a simple request to validate the key
-	req, err := http.NewRequestWithContext(context.Background(), "GET", serverURL+"/api/v1/packages?limit=1", nil)
+// apiKeyInfo is the server's description of an API key, from GET /api/v1/whoami.
+type apiKeyInfo struct {
+	AuthEnabled bool     `json:"authEnabled"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Scopes      []string `json:"scopes"`
+}
+
+// errInvalidAPIKey is returned by validateAPIKey when the server rejects the key.
+var errInvalidAPIKey = errors.New("invalid API key")
+
+// keyCheckError means the server could not confirm whether a key is valid
+// (a server error, or a server too old to have /whoami).
+type keyCheckError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *keyCheckError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("server returned HTTP %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("server returned HTTP %d", e.StatusCode)
+}
+
+// validateAPIKey looks the key up with the server. It returns the key's info when
+// the key is valid, errInvalidAPIKey when it is rejected, and a *keyCheckError
+// when the server can't tell.
+func validateAPIKey(serverURL, apiKey string) (*apiKeyInfo, error) {
+	req, err := http.NewRequestWithContext(context.Background(), "GET", serverURL+"/api/v1/whoami", nil)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	req.Header.Set("X-API-Key", apiKey)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpClient().Do(req)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// 401 means invalid key, anything else is ok
-	if resp.StatusCode == http.StatusUnauthorized {
-		// Check if it's actually an auth error
-		body, _ := io.ReadAll(resp.Body)
-		var errResp map[string]any
-		if json.Unmarshal(body, &errResp) == nil {
-			if errObj, ok := errResp["error"].(map[string]any); ok {
-				if errObj["code"] == "UNAUTHORIZED" {
-					return false, nil
-				}
-			}
+	body, _ := io.ReadAll(resp.Body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var info apiKeyInfo
+		if err := json.Unmarshal(body, &info); err != nil {
+			return nil, fmt.Errorf("parsing key info: %w", err)
 		}
+		return &info, nil
+	case http.StatusUnauthorized:
+		return nil, errInvalidAPIKey
 	}
 
-	return true, nil
+	checkErr := &keyCheckError{StatusCode: resp.StatusCode}
+	var errResp struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &errResp) == nil {
+		checkErr.Message = errResp.Error.Message
+	}
+	return nil, checkErr
 }
 
 func maskAPIKey(key string) string {
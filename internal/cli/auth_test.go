@@ -43,11 +43,11 @@ func TestStdinFdCrossplatform(t *testing.T) {
 func TestAuthLoginWithFlags(t *testing.T) {
 	// Create a mock server that accepts any API key
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/api/v1/packages" {
+		if r.URL.Path == "/api/v1/whoami" {
 			apiKey := r.Header.Get("X-API-Key")
 			if apiKey == "valid-key" {
 				w.WriteHeader(http.StatusOK)
-				w.Write([]byte(`{"packages":[]}`))
+				w.Write([]byte(`{"authEnabled":true,"id":"key-1","name":"ci","scopes":["admin"]}`))
 			} else {
 				w.WriteHeader(http.StatusUnauthorized)
 				w.Write([]byte(`{"error":{"code":"UNAUTHORIZED"}}`))
@@ -102,11 +102,11 @@ func TestAuthLoginWithFlags(t *testing.T) {
 func TestAuthLoginFromStdin(t *testing.T) {
 	// Create a mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/api/v1/packages" {
+		if r.URL.Path == "/api/v1/whoami" {
 			apiKey := r.Header.Get("X-API-Key")
 			if apiKey == "piped-key" {
 				w.WriteHeader(http.StatusOK)
-				w.Write([]byte(`{"packages":[]}`))
+				w.Write([]byte(`{"authEnabled":true,"id":"key-1","name":"ci","scopes":[]}`))
 			} else {
 				w.WriteHeader(http.StatusUnauthorized)
 				w.Write([]byte(`{"error":{"code":"UNAUTHORIZED"}}`))
@@ -284,9 +284,10 @@ func TestAuthStatus(t *testing.T) {
 func TestValidateAPIKey(t *testing.T) {
 	t.Run("valid key", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1/whoami", r.URL.Path)
 			if r.Header.Get("X-API-Key") == "valid-key" {
 				w.WriteHeader(http.StatusOK)
-				w.Write([]byte(`{"packages":[]}`))
+				w.Write([]byte(`{"authEnabled":true,"id":"key-1","name":"ci-publisher","scopes":["admin"]}`))
 			} else {
 				w.WriteHeader(http.StatusUnauthorized)
 				w.Write([]byte(`{"error":{"code":"UNAUTHORIZED"}}`))
@@ -294,9 +295,10 @@ func TestValidateAPIKey(t *testing.T) {
 		}))
 		defer server.Close()
 
-		valid, err := validateAPIKey(server.URL, "valid-key")
+		info, err := validateAPIKey(server.URL, "valid-key")
 		require.NoError(t, err)
-		assert.True(t, valid)
+		assert.Equal(t, "ci-publisher", info.Name)
+		assert.Equal(t, []string{"admin"}, info.Scopes)
 	})
 
 	t.Run("invalid key", func(t *testing.T) {
@@ -306,21 +308,22 @@ func TestValidateAPIKey(t *testing.T) {
 		}))
 		defer server.Close()
 
-		valid, err := validateAPIKey(server.URL, "invalid-key")
-		require.NoError(t, err)
-		assert.False(t, valid)
+		_, err := validateAPIKey(server.URL, "invalid-key")
+		assert.ErrorIs(t, err, errInvalidAPIKey)
 	})
 
-	t.Run("server error treated as valid", func(t *testing.T) {
-		// Server returning 500 should not be treated as invalid key
+	t.Run("server error is reported, not treated as valid", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":{"code":"INTERNAL_ERROR","message":"database unavailable"}}`))
 		}))
 		defer server.Close()
 
-		valid, err := validateAPIKey(server.URL, "any-key")
-		require.NoError(t, err)
-		assert.True(t, valid) // Non-401 treated as valid
+		_, err := validateAPIKey(server.URL, "any-key")
+		var checkErr *keyCheckError
+		require.ErrorAs(t, err, &checkErr)
+		assert.Equal(t, http.StatusInternalServerError, checkErr.StatusCode)
+		assert.Contains(t, err.Error(), "database unavailable")
 	})
 
 	t.Run("connection error", func(t *testing.T) {
@@ -329,6 +332,50 @@ func TestValidateAPIKey(t *testing.T) {
 	})
 }
 
+// TestAuthLoginServerError tests that a key the server couldn't check is only
+// saved when the user confirms
+func TestAuthLoginServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", origHome)
+	os.Setenv("HOME", tmpDir)
+
+	login := func(answer string) error {
+		origStdin := os.Stdin
+		defer func() { os.Stdin = origStdin }()
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		go func() {
+			defer w.Close()
+			io.WriteString(w, answer)
+		}()
+		os.Stdin = r
+		return runAuthLogin(server.URL, "maybe-key")
+	}
+
+	t.Run("declined", func(t *testing.T) {
+		err := login("n\n")
+		assert.ErrorContains(t, err, "credentials not saved")
+		assert.Empty(t, getCredential(server.URL))
+	})
+
+	t.Run("no answer", func(t *testing.T) {
+		err := login("")
+		assert.Error(t, err)
+		assert.Empty(t, getCredential(server.URL))
+	})
+
+	t.Run("confirmed", func(t *testing.T) {
+		require.NoError(t, login("y\n"))
+		assert.Equal(t, "maybe-key", getCredential(server.URL))
+	})
+}
+
 // TestCredentialFilePermissions verifies credentials are saved with secure permissions
 func TestCredentialFilePermissions(t *testing.T) {
 	// Create temp directory for credentials
@@ -454,6 +501,45 @@ func TestMultipleServersCredentials(t *testing.T) {
 	assert.Len(t, creds.Servers, len(servers))
 }
 
+// TestCredentialProfiles tests that named profiles keep separate keys for one server
+func TestCredentialProfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	origHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", origHome)
+	os.Setenv("HOME", tmpDir)
+	defer func() { profile = "" }()
+
+	serverURL := "http://test:8080"
+
+	// A credentials file from before profiles existed
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, ".contrafactory"), 0700))
+	require.NoError(t, os.WriteFile(credentialsFilePath(), []byte("servers:\n  http://test:8080:\n    api_key: prod-key\n"), 0600))
+	assert.Equal(t, "prod-key", getCredential(serverURL))
+
+	profile = "staging"
+	assert.Empty(t, getCredential(serverURL), "a selected profile must not fall back to the default")
+	require.NoError(t, saveCredential(serverURL, "staging-key"))
+	assert.Equal(t, "staging-key", getCredential(serverURL))
+
+	profile = ""
+	assert.Equal(t, "prod-key", getCredential(serverURL))
+
+	t.Setenv("CONTRAFACTORY_PROFILE", "staging")
+	assert.Equal(t, "staging-key", getCredential(serverURL))
+
+	creds, err := loadCredentials()
+	require.NoError(t, err)
+	assert.Equal(t, "prod-key", creds.Servers[serverURL].APIKey)
+	assert.Equal(t, "staging-key", creds.Profiles["staging"].Servers[serverURL].APIKey)
+
+	// Logging out of the profile leaves the default credentials alone
+	require.NoError(t, runAuthLogout(serverURL, false))
+	creds, err = loadCredentials()
+	require.NoError(t, err)
+	assert.NotContains(t, creds.Profiles, "staging")
+	assert.Equal(t, "prod-key", creds.Servers[serverURL].APIKey)
+}
+
 // TestCredentialOverwrite tests that saving a new key overwrites the old one
 func TestCredentialOverwrite(t *testing.T) {
 	// Create temp directory for credentials
@@ -481,7 +567,7 @@ func TestReadPasswordFromNonTerminal(t *testing.T) {
 	// Create a mock server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"packages":[]}`))
+		w.Write([]byte(`{"authEnabled":false,"scopes":[]}`))
 	}))
 	defer server.Close()
 
@@ -2,15 +2,23 @@ package cli
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
 )
 
 // newTestRootCmd creates a root command for testing purposes
@@ -27,16 +35,30 @@ func newTestRootCmd() *cobra.Command {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./contrafactory.yaml)")
 	rootCmd.PersistentFlags().StringVar(&server, "server", "", "server URL (default from config)")
 	rootCmd.PersistentFlags().StringVar(&apiKey, "api-key", "", "API key for authentication")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "credentials profile to use")
+	rootCmd.PersistentFlags().StringVar(&envName, "env", "", "environment whose server to use from the project config's [servers]")
+	rootCmd.PersistentFlags().StringVar(&foundryProfile, "foundry-profile", "", "foundry.toml profile to read output directories from")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "timeout of each request to the server")
 
 	// Add subcommands
 	rootCmd.AddCommand(createPublishCmd())
 	rootCmd.AddCommand(createFetchCmd())
+	rootCmd.AddCommand(createABICmd())
+	rootCmd.AddCommand(createBytecodeCmd())
 	rootCmd.AddCommand(createListCmd())
+	rootCmd.AddCommand(createSearchCmd())
 	rootCmd.AddCommand(createInfoCmd())
 	rootCmd.AddCommand(createVerifyCmd())
 	rootCmd.AddCommand(createAuthCmd())
 	rootCmd.AddCommand(createDeploymentCmd())
 	rootCmd.AddCommand(createConfigCmd())
+	rootCmd.AddCommand(createIdentifyCmd())
+	rootCmd.AddCommand(createOwnerCmd())
+	rootCmd.AddCommand(createAliasCmd())
+	rootCmd.AddCommand(createStorageDiffCmd())
+	rootCmd.AddCommand(createDeleteCmd())
+	rootCmd.AddCommand(createMirrorCmd())
+	rootCmd.AddCommand(createVersionCmd(VersionInfo{Version: "test"}))
 
 	return rootCmd
 }
@@ -69,6 +91,7 @@ func TestRootCommand(t *testing.T) {
 		assert.NotNil(t, cmd.PersistentFlags().Lookup("config"))
 		assert.NotNil(t, cmd.PersistentFlags().Lookup("server"))
 		assert.NotNil(t, cmd.PersistentFlags().Lookup("api-key"))
+		assert.NotNil(t, cmd.PersistentFlags().Lookup("timeout"))
 	})
 
 	t.Run("has all expected subcommands", func(t *testing.T) {
@@ -78,7 +101,7 @@ func TestRootCommand(t *testing.T) {
 			cmdNames[i] = c.Name()
 		}
 
-		expectedCmds := []string{"publish", "fetch", "list", "info", "verify", "auth", "deployment", "config"}
+		expectedCmds := []string{"publish", "fetch", "list", "search", "info", "verify", "auth", "deployment", "config", "identify"}
 		for _, expected := range expectedCmds {
 			assert.Contains(t, cmdNames, expected, "root should have %s subcommand", expected)
 		}
@@ -179,6 +202,66 @@ func TestListCommand(t *testing.T) {
 		require.NoError(t, err)
 		assert.Contains(t, buf.String(), "list")
 	})
+
+	t.Run("has interface flag", func(t *testing.T) {
+		assert.NotNil(t, cmd.Flags().Lookup("interface"))
+	})
+}
+
+func TestNormalizeInterfaceID(t *testing.T) {
+	for input, want := range map[string]string{"erc721": "erc721", "ERC-20": "erc20", " Erc1155 ": "erc1155"} {
+		got, err := normalizeInterfaceID(input)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := normalizeInterfaceID("erc4626")
+	assert.Error(t, err)
+}
+
+func TestParseSince(t *testing.T) {
+	got, err := parseSince("")
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+
+	got, err = parseSince("2024-05-01T12:00:00+02:00")
+	require.NoError(t, err)
+	assert.True(t, got.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+
+	_, err = parseSince("2024-05-01")
+	assert.ErrorContains(t, err, "RFC3339")
+}
+
+func TestSearchCommand(t *testing.T) {
+	t.Run("requires a query or filter", func(t *testing.T) {
+		cmd := createSearchCmd()
+		cmd.SetArgs([]string{})
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		assert.Error(t, cmd.Execute())
+	})
+
+	t.Run("sends query and filters", func(t *testing.T) {
+		var got url.Values
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1/packages", r.URL.Path)
+			got = r.URL.Query()
+			json.NewEncoder(w).Encode(map[string]any{"data": []any{}, "pagination": map[string]any{}})
+		}))
+		defer ts.Close()
+
+		oldServer := server
+		server = ts.URL
+		defer func() { server = oldServer }()
+
+		cmd := createSearchCmd()
+		cmd.SetArgs([]string{"token", "--chain", "evm", "--project", "myproj", "--contract", "Vault"})
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "token", got.Get("q"))
+		assert.Equal(t, "evm", got.Get("chain"))
+		assert.Equal(t, "myproj", got.Get("project"))
+		assert.Equal(t, "Vault", got.Get("contract"))
+	})
 }
 
 // TestInfoCommand verifies the info command structure
@@ -204,6 +287,52 @@ func TestInfoCommand(t *testing.T) {
 	})
 }
 
+func TestLookupOwner(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/packages/mine/owner":
+			w.Write([]byte(`{"name":"mine","owner":"ci-release","since":"2025-06-15T14:30:45Z"}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":{"code":"FORBIDDEN","message":"Only the package owner or an admin can see ownership"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	origKey := apiKey
+	defer func() { apiKey = origKey }()
+	apiKey = "test-key"
+	c := client.New(srv.URL, "test-key")
+
+	owner := lookupOwner(context.Background(), c, "mine")
+	require.NotNil(t, owner)
+	assert.Equal(t, "ci-release", owner.Owner)
+
+	// Not visible to this key: silently omitted
+	assert.Nil(t, lookupOwner(context.Background(), c, "theirs"))
+}
+
+func TestServerError(t *testing.T) {
+	resp := func(status int, requestID string) *http.Response {
+		r := &http.Response{StatusCode: status, Header: http.Header{}}
+		if requestID != "" {
+			r.Header.Set("X-Request-Id", requestID)
+		}
+		return r
+	}
+
+	err := serverError(resp(500, ""), []byte(`{"error":{"code":"INTERNAL_ERROR","message":"Failed to publish package","requestId":"req-123"}}`))
+	assert.EqualError(t, err, "INTERNAL_ERROR - Failed to publish package (request ID: req-123)")
+
+	// Falls back to the response header when the body is not an API error
+	err = serverError(resp(502, "req-456"), []byte("Bad Gateway"))
+	assert.EqualError(t, err, "status 502: Bad Gateway (request ID: req-456)")
+
+	// Client errors are the user's to fix; no request ID noise
+	err = serverError(resp(409, "req-789"), []byte(`{"error":{"code":"VERSION_EXISTS","message":"Version already exists","requestId":"req-789"}}`))
+	assert.EqualError(t, err, "VERSION_EXISTS - Version already exists")
+}
+
 // TestVerifyCommand verifies the verify command structure
 func TestVerifyCommand(t *testing.T) {
 	cmd := createVerifyCmd()
@@ -524,6 +653,60 @@ func TestParsePackageRef(t *testing.T) {
 	}
 }
 
+func TestResolveVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		versions := []string{"1.0.0", "1.2.0", "1.10.0", "2.0.0"}
+		if r.URL.Query().Get("include_prerelease") == "true" {
+			versions = append(versions, "2.1.0-rc.1")
+		}
+		json.NewEncoder(w).Encode(map[string]any{"name": "my-token", "versions": versions})
+	}))
+	defer srv.Close()
+
+	c := client.New(srv.URL, "")
+
+	tests := []struct {
+		version string
+		want    string
+		wantErr string
+	}{
+		{version: "1.2.0", want: "1.2.0"},
+		{version: "latest", want: "2.0.0"},
+		{version: "^1.2.0", want: "1.10.0"},
+		{version: "1.x", want: "1.10.0"},
+		{version: "~1.2.0", want: "1.2.0"},
+		{version: ">=2.1.0-rc.0", want: "2.1.0-rc.1"},
+		{version: "^3.0.0", wantErr: `no version of my-token satisfies "^3.0.0"`},
+		{version: "^abc", wantErr: "invalid version range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := resolveVersion(context.Background(), c, "my-token", tt.version)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	origTimeout := timeout
+	defer func() { timeout = origTimeout }()
+
+	timeout = 0
+	assert.Equal(t, defaultTimeout, httpClient().Timeout)
+	assert.Equal(t, publishTimeout, publishHTTPClient().Timeout)
+
+	timeout = 10 * time.Minute
+	assert.Equal(t, 10*time.Minute, httpClient().Timeout)
+	assert.Equal(t, 10*time.Minute, publishHTTPClient().Timeout, "--timeout also applies to publish")
+}
+
 func TestGetServer(t *testing.T) {
 	// Save original values
 	origServer := server
@@ -550,6 +733,42 @@ func TestGetServer(t *testing.T) {
 		os.Unsetenv("CONTRAFACTORY_SERVER")
 		assert.Equal(t, "http://localhost:8080", getServer())
 	})
+
+	t.Run("project config server for the selected env", func(t *testing.T) {
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		origEnvName := envName
+		defer func() { envName = origEnvName }()
+		t.Setenv("CONTRAFACTORY_ENV", "")
+
+		os.Chdir(t.TempDir())
+		content := `
+server = "http://dev:8080"
+
+[servers]
+staging = "https://staging.example.com"
+production = "https://prod.example.com"
+`
+		require.NoError(t, os.WriteFile("contrafactory.toml", []byte(content), 0644))
+
+		server = ""
+		os.Unsetenv("CONTRAFACTORY_SERVER")
+		envName = ""
+		assert.Equal(t, "http://dev:8080", getServer())
+
+		t.Setenv("CONTRAFACTORY_ENV", "production")
+		assert.Equal(t, "https://prod.example.com", getServer())
+
+		envName = "staging"
+		assert.Equal(t, "https://staging.example.com", getServer(), "--env takes precedence over CONTRAFACTORY_ENV")
+
+		envName = "qa"
+		assert.Equal(t, "http://dev:8080", getServer(), "unknown env falls back to the top-level server")
+
+		os.Setenv("CONTRAFACTORY_SERVER", "http://env-server:8080")
+		envName = "staging"
+		assert.Equal(t, "http://env-server:8080", getServer(), "CONTRAFACTORY_SERVER takes precedence over the project config")
+	})
 }
 
 func TestGetAPIKey(t *testing.T) {
@@ -657,6 +876,9 @@ project = "test-project"
 chain = "evm"
 exclude = ["Test", "Mock"]
 include_dependencies = ["TransparentUpgradeableProxy"]
+
+[servers]
+staging = "https://staging.example.com"
 `
 		err := os.WriteFile("contrafactory.toml", []byte(content), 0644)
 		require.NoError(t, err)
@@ -669,6 +891,7 @@ include_dependencies = ["TransparentUpgradeableProxy"]
 		assert.Equal(t, "evm", loaded.Chain)
 		assert.Equal(t, []string{"Test", "Mock"}, loaded.Exclude)
 		assert.Equal(t, []string{"TransparentUpgradeableProxy"}, loaded.IncludeDependencies)
+		assert.Equal(t, map[string]string{"staging": "https://staging.example.com"}, loaded.Servers)
 	})
 
 	t.Run("cf.toml fallback", func(t *testing.T) {
@@ -703,6 +926,70 @@ project = "cf-project"
 	})
 }
 
+func TestLoadProjectConfig_ExplicitPath(t *testing.T) {
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	origCfgFile := cfgFile
+	defer func() { cfgFile = origCfgFile }()
+
+	// The working directory has its own config, which an explicit path overrides
+	cwd := t.TempDir()
+	os.Chdir(cwd)
+	require.NoError(t, os.WriteFile("contrafactory.toml", []byte(`server = "http://cwd:8080"`), 0644))
+
+	elsewhere := filepath.Join(t.TempDir(), "configs", "release.toml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(elsewhere), 0755))
+	require.NoError(t, os.WriteFile(elsewhere, []byte("server = \"http://release:8080\"\nproject = \"release\"\n"), 0644))
+
+	t.Run("--config", func(t *testing.T) {
+		cfgFile = elsewhere
+		defer func() { cfgFile = "" }()
+
+		loaded, path, err := loadProjectConfig()
+		require.NoError(t, err)
+		assert.Equal(t, elsewhere, path)
+		assert.Equal(t, "http://release:8080", loaded.Server)
+		assert.Equal(t, "release", loaded.Project)
+		assert.Equal(t, "release", loadProjectConfigSilent().Project)
+	})
+
+	t.Run("CONTRAFACTORY_CONFIG", func(t *testing.T) {
+		t.Setenv("CONTRAFACTORY_CONFIG", elsewhere)
+
+		loaded, path, err := loadProjectConfig()
+		require.NoError(t, err)
+		assert.Equal(t, elsewhere, path)
+		assert.Equal(t, "http://release:8080", loaded.Server)
+	})
+
+	t.Run("--config wins over CONTRAFACTORY_CONFIG", func(t *testing.T) {
+		t.Setenv("CONTRAFACTORY_CONFIG", elsewhere)
+		cfgFile = "contrafactory.toml"
+		defer func() { cfgFile = "" }()
+
+		loaded, _, err := loadProjectConfig()
+		require.NoError(t, err)
+		assert.Equal(t, "http://cwd:8080", loaded.Server)
+	})
+
+	t.Run("missing explicit file is an error, not a fallback", func(t *testing.T) {
+		cfgFile = filepath.Join(t.TempDir(), "missing.toml")
+		defer func() { cfgFile = "" }()
+
+		_, _, err := loadProjectConfig()
+		require.Error(t, err)
+		assert.False(t, os.IsNotExist(err))
+		assert.Contains(t, err.Error(), "missing.toml does not exist")
+	})
+
+	t.Run("falls back to the working directory", func(t *testing.T) {
+		loaded, path, err := loadProjectConfig()
+		require.NoError(t, err)
+		assert.Equal(t, "contrafactory.toml", path)
+		assert.Equal(t, "http://cwd:8080", loaded.Server)
+	})
+}
+
 func TestCredentialStorage(t *testing.T) {
 	// Create temp directory for credentials
 	tmpDir := t.TempDir()
@@ -741,6 +1028,7 @@ func TestCredentialStorage(t *testing.T) {
 func TestProjectConfig(t *testing.T) {
 	config := ProjectConfig{
 		Server:              "http://localhost:8080",
+		Servers:             map[string]string{"staging": "https://staging.example.com", "production": "https://prod.example.com"},
 		Project:             "my-project",
 		Chain:               "evm",
 		Contracts:           []string{"Token", "Registry"},
@@ -759,6 +1047,7 @@ func TestProjectConfig(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Equal(t, config.Server, loaded.Server)
+	assert.Equal(t, config.Servers, loaded.Servers)
 	assert.Equal(t, config.Project, loaded.Project)
 	assert.Equal(t, config.Chain, loaded.Chain)
 	assert.Equal(t, config.Contracts, loaded.Contracts)
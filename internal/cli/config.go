@@ -2,8 +2,10 @@ package cli
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/BurntSushi/toml"
 	"github.com/spf13/cobra"
@@ -15,15 +17,32 @@ var projectConfigFiles = []string{"contrafactory.toml", "cf.toml"}
 
 // ProjectConfig is the project-level TOML configuration
 type ProjectConfig struct {
-	Server              string        `toml:"server"`
-	Project             string        `toml:"project,omitempty"`
-	Chain               string        `toml:"chain,omitempty"`
-	Builder             string        `toml:"builder,omitempty"`
-	Contracts           []string      `toml:"contracts,omitempty"`
-	Exclude             []string      `toml:"exclude,omitempty"`
-	ExcludePaths        []string      `toml:"exclude_paths,omitempty"`
-	IncludeDependencies []string      `toml:"include_dependencies,omitempty"`
-	EVM                 EVMConfigTOML `toml:"evm,omitempty"`
+	Server              string              `toml:"server"`
+	Servers             map[string]string   `toml:"servers,omitempty"` // environment name -> server URL
+	Project             string              `toml:"project,omitempty"`
+	Chain               string              `toml:"chain,omitempty"`
+	Builder             string              `toml:"builder,omitempty"`
+	Contracts           []string            `toml:"contracts,omitempty"`
+	Exclude             []string            `toml:"exclude,omitempty"`         // replaces the built-in exclude patterns
+	DefaultExclude      []string            `toml:"default_exclude,omitempty"` // added to the built-in exclude patterns
+	ExcludePaths        []string            `toml:"exclude_paths,omitempty"`
+	ExcludeKinds        []string            `toml:"exclude_kinds,omitempty"`
+	IncludeDependencies []string            `toml:"include_dependencies,omitempty"`
+	Labels              map[string][]string `toml:"labels,omitempty"` // contract name -> labels
+	EVM                 EVMConfigTOML       `toml:"evm,omitempty"`
+}
+
+// serverFor returns the server URL for the named environment (see --env), or the
+// top-level server when name is "". An environment missing from [servers] is an
+// error; the top-level server is still returned alongside it.
+func (c *ProjectConfig) serverFor(name string) (string, error) {
+	if name == "" {
+		return c.Server, nil
+	}
+	if serverURL, ok := c.Servers[name]; ok {
+		return serverURL, nil
+	}
+	return c.Server, fmt.Errorf("environment %q not found in [servers] of project config", name)
 }
 
 // EVMConfigTOML contains EVM-specific configuration for project config
@@ -125,27 +144,42 @@ func runConfigInit(serverURL, project string, force bool) error {
 		}
 	}
 
+	chain := "evm"
+	if cwd, err := os.Getwd(); err == nil && isAnchorProject(cwd) {
+		chain = "solana"
+	}
+
 	// Generate TOML config
 	content := fmt.Sprintf(`# Contrafactory project configuration
 # See https://github.com/pendergraft/contrafactory for documentation
 
 server = "%s"
 project = "%s"
-chain = "evm"
+chain = "%s"
 
-# Patterns to exclude from publishing
-exclude = ["Test", "Script", "Mock", "Deploy", "Setup"]
+# Contracts named like Test, Script, Mock, Deploy and Setup are never published.
+# Add patterns of your own with default_exclude, or replace the built-ins with exclude.
+# default_exclude = ["Helper", "Fixture"]
 
 # Exclude by source path (substring or glob, e.g. "proxy" or "examples/MetaCoin.sol")
 # exclude_paths = ["proxy", "examples/MetaCoin.sol"]
 
+# Exclude by contract kind: "abstract", "library", "interface" or "contract"
+# (abstract contracts are only recognised when built with 'forge build --ast')
+# exclude_kinds = ["abstract", "library"]
+
 # Specific contracts to publish (empty = all from src/)
 # contracts = ["MyContract", "OtherContract"]
 
 # Third-party contracts to publish as separate packages
 # Useful for proxy patterns that need companion contracts
 # include_dependencies = ["TransparentUpgradeableProxy", "ProxyAdmin"]
-`, serverURL, project)
+
+# Servers per environment, selected with --env or CONTRAFACTORY_ENV
+# [servers]
+# staging = "https://staging.contrafactory.example.com"
+# production = "https://contrafactory.example.com"
+`, serverURL, project, chain)
 
 	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
@@ -171,13 +205,15 @@ func runConfigShow() error {
 
 	// 1. Command line flags
 	fmt.Println("1. Command line flags")
-	fmt.Println("   --server, --api-key, --config")
+	fmt.Println("   --server, --api-key, --config, --env")
 	fmt.Println()
 
 	// 2. Environment variables
 	fmt.Println("2. Environment variables")
 	serverEnv := os.Getenv("CONTRAFACTORY_SERVER")
 	keyEnv := os.Getenv("CONTRAFACTORY_API_KEY")
+	envEnv := os.Getenv("CONTRAFACTORY_ENV")
+	configEnv := os.Getenv("CONTRAFACTORY_CONFIG")
 	if serverEnv != "" {
 		fmt.Printf("   CONTRAFACTORY_SERVER=%s\n", serverEnv)
 	} else {
@@ -188,10 +224,20 @@ func runConfigShow() error {
 	} else {
 		fmt.Println("   CONTRAFACTORY_API_KEY=(not set)")
 	}
+	if envEnv != "" {
+		fmt.Printf("   CONTRAFACTORY_ENV=%s\n", envEnv)
+	} else {
+		fmt.Println("   CONTRAFACTORY_ENV=(not set)")
+	}
+	if configEnv != "" {
+		fmt.Printf("   CONTRAFACTORY_CONFIG=%s\n", configEnv)
+	} else {
+		fmt.Println("   CONTRAFACTORY_CONFIG=(not set)")
+	}
 	fmt.Println()
 
 	// 3. Local project config
-	fmt.Println("3. Local project config (contrafactory.toml or cf.toml)")
+	fmt.Println("3. Project config (--config or CONTRAFACTORY_CONFIG, else contrafactory.toml or cf.toml)")
 	projectConfig, configPath, err := loadProjectConfig()
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -204,6 +250,9 @@ func runConfigShow() error {
 		if projectConfig.Server != "" {
 			fmt.Printf("   server: %s\n", projectConfig.Server)
 		}
+		for _, name := range slices.Sorted(maps.Keys(projectConfig.Servers)) {
+			fmt.Printf("   servers.%s: %s\n", name, projectConfig.Servers[name])
+		}
 		if projectConfig.Project != "" {
 			fmt.Printf("   project: %s\n", projectConfig.Project)
 		}
@@ -219,6 +268,9 @@ func runConfigShow() error {
 		if len(projectConfig.ExcludePaths) > 0 {
 			fmt.Printf("   exclude_paths: %v\n", projectConfig.ExcludePaths)
 		}
+		if len(projectConfig.ExcludeKinds) > 0 {
+			fmt.Printf("   exclude_kinds: %v\n", projectConfig.ExcludeKinds)
+		}
 		if len(projectConfig.IncludeDependencies) > 0 {
 			fmt.Printf("   include_dependencies: %v\n", projectConfig.IncludeDependencies)
 		}
@@ -267,6 +319,9 @@ func runConfigShow() error {
 
 	// Effective config
 	fmt.Println("Effective configuration:")
+	if name := getEnvName(); name != "" {
+		fmt.Printf("   Env:     %s\n", name)
+	}
 	fmt.Printf("   Server:  %s\n", getServer())
 	if key := getAPIKey(); key != "" {
 		fmt.Printf("   API Key: %s\n", maskAPIKey(key))
@@ -277,16 +332,21 @@ func runConfigShow() error {
 	return nil
 }
 
-// loadProjectConfig loads the project config from the first matching config file.
+// loadProjectConfig loads the project config from the file named by --config or
+// CONTRAFACTORY_CONFIG, else from the first config file found in the current directory.
 // Returns the config, the path it was loaded from, and an error.
 func loadProjectConfig() (*ProjectConfig, string, error) {
-	// If --config flag was provided, use that directly
-	if cfgFile != "" {
-		config, err := loadProjectConfigFromPath(cfgFile)
+	// An explicit --config or CONTRAFACTORY_CONFIG replaces the search
+	if path := explicitConfigFile(); path != "" {
+		config, err := loadProjectConfigFromPath(path)
+		if os.IsNotExist(err) {
+			// Not os.ErrNotExist: a file the user named is an error, not "no config"
+			return nil, path, fmt.Errorf("config file %s does not exist", path)
+		}
 		if err != nil {
-			return nil, cfgFile, err
+			return nil, path, err
 		}
-		return config, cfgFile, nil
+		return config, path, nil
 	}
 
 	// Search for config files in order
@@ -302,6 +362,15 @@ func loadProjectConfig() (*ProjectConfig, string, error) {
 	return nil, "", os.ErrNotExist
 }
 
+// explicitConfigFile returns the config file named by --config, else by
+// CONTRAFACTORY_CONFIG, or "" to search the current directory.
+func explicitConfigFile() string {
+	if cfgFile != "" {
+		return cfgFile
+	}
+	return os.Getenv("CONTRAFACTORY_CONFIG")
+}
+
 // loadProjectConfigFromPath loads a project config from a specific path
 func loadProjectConfigFromPath(path string) (*ProjectConfig, error) {
 	data, err := os.ReadFile(path)
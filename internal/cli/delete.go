@@ -1,66 +1,125 @@
 package cli
 
 import (
-	"encoding/json"
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
-	"net/url"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/internal/validation"
+	"github.com/pendergraft/contrafactory/pkg/client"
 )
 
 func createDeleteCmd() *cobra.Command {
 	var version string
 	var contracts []string
 	var exclude []string
+	var noDefaultExclude bool
 	var excludePaths []string
+	var excludeKinds []string
 	var includeDeps []string
 	var prefix string
 	var project string
 	var dryRun bool
+	var all bool
+	var yes bool
 
 	cmd := &cobra.Command{
-		Use:   "delete",
-		Short: "Delete packages from the registry",
-		Long: `Delete packages from the Contrafactory registry using the same discovery logic as publish.
+		Use:     "delete [package@version]",
+		Aliases: []string{"unpublish"},
+		Short:   "Delete packages from the registry",
+		Long: `Delete a package version from the Contrafactory registry, or with --all every
+package of the current project at a version (for cleaning up a botched release).
+
+--all uses the same discovery logic as publish: contracts, exclude and
+include_dependencies from contrafactory.toml, and the same flags.
 
-Discovers packages from your Foundry project (contracts, exclude, include_dependencies
-from contrafactory.toml) and deletes each package at the specified version.
+Deleting needs the API key that owns the packages. You are asked to confirm
+before anything is deleted unless --yes is given.
 
 EXAMPLES:
+  # Delete one package version
+  contrafactory delete my-token@1.0.0
+
   # Delete all packages for version 1.0.0 (same set that would be published)
-  contrafactory delete --version 1.0.0
+  contrafactory delete --all --version 1.0.0
 
   # Delete with prefix (must match what was used when publishing)
-  contrafactory delete --version 1.0.0 --prefix myproject
+  contrafactory delete --all --version 1.0.0 --prefix myproject
 
   # Dry run (show what would be deleted)
-  contrafactory delete --version 1.0.0 --dry-run
+  contrafactory delete --all --version 1.0.0 --dry-run
+
+  # Non-interactive (CI)
+  contrafactory delete my-token@1.0.0 --yes
 `,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDelete(version, prefix, project, contracts, exclude, excludePaths, includeDeps, dryRun)
+			var targets []deleteTarget
+			switch {
+			case len(args) == 1 && all:
+				return fmt.Errorf("give either package@version or --all, not both")
+			case len(args) == 1:
+				name, ver, contract, err := parsePackageRef(args[0])
+				if err != nil {
+					return err
+				}
+				if contract != "" {
+					return fmt.Errorf("delete removes whole package versions; use %s@%s", name, ver)
+				}
+				if err := validation.ValidateVersion(ver); err != nil {
+					return fmt.Errorf("delete needs an exact version: %w", err)
+				}
+				targets = []deleteTarget{{Name: name, Version: ver}}
+			case all:
+				if version == "" {
+					return fmt.Errorf("--version is required with --all")
+				}
+				var err error
+				targets, err = discoverDeleteTargets(version, prefix, contracts, exclude, noDefaultExclude, excludePaths, excludeKinds, includeDeps)
+				if err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("specify package@version, or --all --version <version> to delete the project's packages")
+			}
+
+			return runDelete(cmd.InOrStdin(), cmd.OutOrStdout(), targets, resolveProject(project), dryRun, yes)
 		},
 	}
 
-	cmd.Flags().StringVarP(&version, "version", "v", "", "version to delete (required)")
+	cmd.Flags().BoolVar(&all, "all", false, "delete every package discovered in the current project")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "skip the confirmation prompt")
+	cmd.Flags().StringVarP(&version, "version", "v", "", "version to delete (with --all)")
 	cmd.Flags().StringSliceVar(&contracts, "contracts", nil, "specific contracts to delete (default: all from config)")
-	cmd.Flags().StringSliceVar(&exclude, "exclude", nil, "patterns to exclude by contract name (e.g., Test,Mock)")
+	cmd.Flags().StringSliceVar(&exclude, "exclude", nil, "more patterns to exclude by contract name (must match publish)")
+	cmd.Flags().BoolVar(&noDefaultExclude, "no-default-exclude", false, "don't exclude the built-in patterns (must match publish)")
 	cmd.Flags().StringSliceVar(&excludePaths, "exclude-path", nil, "patterns to exclude by source path (e.g., proxy, examples/MetaCoin.sol)")
+	cmd.Flags().StringSliceVar(&excludeKinds, "exclude-kind", nil, "contract kinds to exclude: abstract, library, interface, contract")
 	cmd.Flags().StringSliceVar(&includeDeps, "include-deps", nil, "dependency contracts to include")
 	cmd.Flags().StringVarP(&prefix, "prefix", "p", "", "prefix for package names (must match publish)")
 	cmd.Flags().StringVar(&project, "project", "", "project scope (overrides contrafactory.toml)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be deleted without deleting")
-	_ = cmd.MarkFlagRequired("version")
 
 	return cmd
 }
 
-func runDelete(version, prefix, projectFlag string, contracts, exclude, excludePaths, includeDeps []string, dryRun bool) error {
+// deleteTarget is a package version to delete.
+type deleteTarget struct {
+	Name    string
+	Version string
+}
+
+// discoverDeleteTargets finds the packages publish would create for version.
+func discoverDeleteTargets(version, prefix string, contracts, exclude []string, noDefaultExclude bool, excludePaths, excludeKinds, includeDeps []string) ([]deleteTarget, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("getting current directory: %w", err)
+		return nil, fmt.Errorf("getting current directory: %w", err)
 	}
 
 	// Load project config and resolve params (same as publish)
@@ -70,43 +129,60 @@ func runDelete(version, prefix, projectFlag string, contracts, exclude, excludeP
 		contracts = projectConfig.Contracts
 	}
 
-	excludePatterns := defaultExcludePatterns
-	if len(exclude) > 0 {
-		excludePatterns = exclude
-	} else if projectConfig != nil && len(projectConfig.Exclude) > 0 {
-		excludePatterns = projectConfig.Exclude
-	}
+	excludePatterns := resolveExcludePatterns(exclude, noDefaultExclude, projectConfig)
 
 	excludePathPatterns := excludePaths
 	if len(excludePathPatterns) == 0 && projectConfig != nil {
 		excludePathPatterns = projectConfig.ExcludePaths
 	}
 
+	if len(excludeKinds) == 0 && projectConfig != nil {
+		excludeKinds = projectConfig.ExcludeKinds
+	}
+	if err := validateContractKinds(excludeKinds); err != nil {
+		return nil, err
+	}
+
 	if len(includeDeps) == 0 && projectConfig != nil {
 		includeDeps = projectConfig.IncludeDependencies
 	}
 
 	// Discover packages (same logic as publish)
-	discovered, err := discoverPackages(cwd, prefix, contracts, excludePatterns, excludePathPatterns, includeDeps)
+	discovered, err := discoverPackages(cwd, prefix, contracts, excludePatterns, excludePathPatterns, excludeKinds, includeDeps, false)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	serverURL := getServer()
+	targets := make([]deleteTarget, len(discovered))
+	for i, pkg := range discovered {
+		targets[i] = deleteTarget{Name: pkg.Name, Version: version}
+	}
+	return targets, nil
+}
 
-	// Resolve project: CLI flag > config (display-only for now; delete API uses name+version only)
-	project := projectFlag
-	if project == "" && projectConfig != nil {
-		project = projectConfig.Project
+// resolveProject returns the --project flag, else the project from contrafactory.toml.
+func resolveProject(projectFlag string) string {
+	if projectFlag != "" {
+		return projectFlag
+	}
+	if projectConfig := loadProjectConfigSilent(); projectConfig != nil {
+		return projectConfig.Project
 	}
+	return ""
+}
+
+// runDelete deletes targets after confirming on in (unless yes). project is display-only;
+// the delete API addresses packages by name and version.
+func runDelete(in io.Reader, out io.Writer, targets []deleteTarget, project string, dryRun, yes bool) error {
+	serverURL := getServer()
 
 	if dryRun {
-		fmt.Printf("DRY RUN - Would delete %d package(s) from %s\n", len(discovered), serverURL)
+		fmt.Fprintf(out, "DRY RUN - Would delete %d package(s) from %s\n", len(targets), serverURL)
 		if project != "" {
-			fmt.Printf("  Project scope: %s\n", project)
+			fmt.Fprintf(out, "  Project scope: %s\n", project)
 		}
-		for _, pkg := range discovered {
-			fmt.Printf("   - %s@%s\n", pkg.Name, version)
+		for _, t := range targets {
+			fmt.Fprintf(out, "   - %s@%s\n", t.Name, t.Version)
 		}
 		return nil
 	}
@@ -115,56 +191,59 @@ func runDelete(version, prefix, projectFlag string, contracts, exclude, excludeP
 	if apiKey == "" {
 		return fmt.Errorf("API key required for delete (use --api-key, CONTRAFACTORY_API_KEY, or contrafactory auth login)")
 	}
+	if len(targets) == 0 {
+		fmt.Fprintln(out, "No packages to delete")
+		return nil
+	}
 
-	fmt.Printf("Deleting %d package(s) from %s...\n", len(discovered), serverURL)
+	if !yes {
+		fmt.Fprintf(out, "About to delete %d package(s) from %s:\n", len(targets), serverURL)
+		for _, t := range targets {
+			fmt.Fprintf(out, "   - %s@%s\n", t.Name, t.Version)
+		}
+		fmt.Fprint(out, "Deleted versions cannot be restored. Continue? [y/N]: ")
+		answer, _ := bufio.NewReader(in).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			return fmt.Errorf("delete cancelled")
+		}
+	}
+
+	fmt.Fprintf(out, "Deleting %d package(s) from %s...\n", len(targets), serverURL)
 
 	var successCount, failCount int
-	for _, pkg := range discovered {
-		err := deletePackage(serverURL, apiKey, pkg.Name, version)
-		if err != nil {
-			fmt.Printf("   X %s@%s: %v\n", pkg.Name, version, err)
+	for _, t := range targets {
+		if err := deletePackage(serverURL, apiKey, t.Name, t.Version); err != nil {
+			fmt.Fprintf(out, "   X %s@%s: %v\n", t.Name, t.Version, err)
 			failCount++
 		} else {
-			fmt.Printf("   OK %s@%s\n", pkg.Name, version)
+			fmt.Fprintf(out, "   OK %s@%s\n", t.Name, t.Version)
 			successCount++
 		}
 	}
 
-	fmt.Println()
+	fmt.Fprintln(out)
 	if failCount > 0 {
 		return fmt.Errorf("deleted %d package(s), %d failed", successCount, failCount)
 	}
 
-	fmt.Printf("Successfully deleted %d package(s)\n", successCount)
+	fmt.Fprintf(out, "Successfully deleted %d package(s)\n", successCount)
 	return nil
 }
 
+// deletePackage deletes one package version, explaining the errors a user can act on.
 func deletePackage(serverURL, apiKey, packageName, version string) error {
-	path := fmt.Sprintf("%s/api/v1/packages/%s/%s", serverURL, url.PathEscape(packageName), url.PathEscape(version))
-	req, err := http.NewRequest("DELETE", path, nil)
-	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
-	}
-
-	req.Header.Set("X-API-Key", apiKey)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("HTTP request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-
-	if resp.StatusCode != http.StatusNoContent {
-		var errResp map[string]any
-		if json.Unmarshal(body, &errResp) == nil {
-			if errObj, ok := errResp["error"].(map[string]any); ok {
-				return fmt.Errorf("%s - %s", errObj["code"], errObj["message"])
-			}
+	err := newClient(serverURL, apiKey).DeletePackage(context.Background(), packageName, version)
+
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case client.ErrorCodeForbidden:
+			return fmt.Errorf("FORBIDDEN: %s is owned by another API key; only its owner can delete it (see 'contrafactory owner show %s')", packageName, packageName)
+		case client.ErrorCodeNotFound:
+			return fmt.Errorf("NOT_FOUND: %s@%s is not in the registry", packageName, version)
+		case client.ErrorCodeUnauthorized:
+			return fmt.Errorf("UNAUTHORIZED: %s (check the key with 'contrafactory auth status')", apiErr.Message)
 		}
-		return fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
 	}
-
-	return nil
+	return err
 }
@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -84,7 +86,7 @@ func TestDeletePackage(t *testing.T) {
 				})
 			},
 			wantErr:        true,
-			wantErrContain: "FORBIDDEN",
+			wantErrContain: "owned by another API key",
 		},
 	}
 
@@ -106,3 +108,70 @@ func TestDeletePackage(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteCommand(t *testing.T) {
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		deleted = append(deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	origServer, origKey := server, apiKey
+	defer func() { server, apiKey = origServer, origKey }()
+	server, apiKey = srv.URL, "owner-key"
+
+	run := func(stdin string, args ...string) (string, error) {
+		deleted = nil
+		cmd := createDeleteCmd()
+		var out bytes.Buffer
+		cmd.SetIn(strings.NewReader(stdin))
+		cmd.SetOut(&out)
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	t.Run("confirmed", func(t *testing.T) {
+		out, err := run("y\n", "my-token@1.0.0")
+		require.NoError(t, err)
+		assert.Contains(t, out, "About to delete 1 package(s)")
+		assert.Equal(t, []string{"/api/v1/packages/my-token/1.0.0"}, deleted)
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		_, err := run("n\n", "my-token@1.0.0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cancelled")
+		assert.Empty(t, deleted)
+	})
+
+	t.Run("no answer", func(t *testing.T) {
+		_, err := run("", "my-token@1.0.0")
+		require.Error(t, err)
+		assert.Empty(t, deleted)
+	})
+
+	t.Run("--yes skips the prompt", func(t *testing.T) {
+		out, err := run("", "my-token@1.0.0", "--yes")
+		require.NoError(t, err)
+		assert.NotContains(t, out, "Continue?")
+		assert.Len(t, deleted, 1)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for _, args := range [][]string{
+			{},
+			{"my-token@latest"},
+			{"my-token/Token@1.0.0"},
+			{"my-token@1.0.0", "--all"},
+			{"--all"},
+		} {
+			_, err := run("y\n", args...)
+			assert.Error(t, err, args)
+		}
+		assert.Empty(t, deleted)
+	})
+}
@@ -6,11 +6,17 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
 	"os"
+	"sort"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/pendergraft/contrafactory/internal/chains/evm"
+	"github.com/pendergraft/contrafactory/internal/chains/networks"
 	"github.com/pendergraft/contrafactory/pkg/client"
 )
 
@@ -23,6 +29,7 @@ func createDeploymentCmd() *cobra.Command {
 	cmd.AddCommand(createDeploymentRecordCmd())
 	cmd.AddCommand(createDeploymentListCmd())
 	cmd.AddCommand(createDeploymentInfoCmd())
+	cmd.AddCommand(createDeploymentVerifyAllCmd())
 
 	return cmd
 }
@@ -33,7 +40,11 @@ func createDeploymentRecordCmd() *cobra.Command {
 	var address string
 	var txHash string
 	var deployerAddress string
+	var salt string
+	var gasUsed uint64
 	var fromBroadcast string
+	var hardhatDeployments string
+	var version string
 
 	cmd := &cobra.Command{
 		Use:   "record",
@@ -48,25 +59,58 @@ EXAMPLES:
     --address 0x1234... \
     --tx-hash 0xabcd...
 
+  # Record a CREATE2 deployment with its salt
+  contrafactory deployment record \
+    --package my-contracts/Token@1.0.0 \
+    --chain-id 1 \
+    --address 0x1234... \
+    --salt 0x0000000000000000000000000000000000000000000000000000000000000001
+
   # Record from Foundry broadcast file
   contrafactory deployment record \
     --from-broadcast broadcast/Deploy.s.sol/1/run-latest.json \
     --package my-contracts@1.0.0
+
+  # Record every hardhat-deploy deployment of a network; contract names come from
+  # the file names and the chain ID from the directory's .chainId file
+  contrafactory deployment record \
+    --hardhat-deployments deployments/mainnet \
+    --package my-contracts --version 1.0.0
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if version != "" {
+				if strings.Contains(pkg, "@") {
+					return fmt.Errorf("give the version in --package or --version, not both")
+				}
+				pkg += "@" + version
+			}
+			if fromBroadcast != "" && hardhatDeployments != "" {
+				return fmt.Errorf("--from-broadcast and --hardhat-deployments cannot be used together")
+			}
 			if fromBroadcast != "" {
 				return runDeploymentRecordFromBroadcast(fromBroadcast, pkg)
 			}
-			return runDeploymentRecord(pkg, chainID, address, txHash, deployerAddress)
+			if hardhatDeployments != "" {
+				if pkg == "" {
+					return fmt.Errorf("--package is required")
+				}
+				c := newClient(getServer(), getAPIKey())
+				return recordHardhatDeployments(context.Background(), cmd.OutOrStdout(), c, hardhatDeployments, pkg)
+			}
+			return runDeploymentRecord(pkg, chainID, address, txHash, deployerAddress, salt, gasUsed)
 		},
 	}
 
 	cmd.Flags().StringVar(&pkg, "package", "", "package/contract@version")
+	cmd.Flags().StringVar(&version, "version", "", "package version, when not given in --package")
 	cmd.Flags().IntVar(&chainID, "chain-id", 0, "chain ID")
 	cmd.Flags().StringVar(&address, "address", "", "contract address")
 	cmd.Flags().StringVar(&txHash, "tx-hash", "", "transaction hash")
 	cmd.Flags().StringVar(&deployerAddress, "deployer", "", "deployer address")
+	cmd.Flags().StringVar(&salt, "salt", "", "CREATE2 salt (bytes32 hex)")
+	cmd.Flags().Uint64Var(&gasUsed, "gas-used", 0, "gas used by the deployment transaction")
 	cmd.Flags().StringVar(&fromBroadcast, "from-broadcast", "", "parse from Foundry broadcast file")
+	cmd.Flags().StringVar(&hardhatDeployments, "hardhat-deployments", "", "record every deployment in a hardhat-deploy network directory, e.g. deployments/mainnet")
 
 	return cmd
 }
@@ -77,6 +121,7 @@ func createDeploymentListCmd() *cobra.Command {
 	var verified *bool
 	var jsonOutput bool
 	var limit int
+	var since string
 
 	cmd := &cobra.Command{
 		Use:   "list",
@@ -95,9 +140,16 @@ EXAMPLES:
 
   # Show only verified deployments
   contrafactory deployment list --verified
+
+  # Deployments recorded since a point in time (RFC3339)
+  contrafactory deployment list --since 2024-05-01T00:00:00Z
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDeploymentList(chainID, packageFilter, verified, limit, jsonOutput)
+			createdAfter, err := parseSince(since)
+			if err != nil {
+				return err
+			}
+			return runDeploymentList(chainID, packageFilter, verified, createdAfter, limit, jsonOutput)
 		},
 	}
 
@@ -105,6 +157,7 @@ EXAMPLES:
 	cmd.Flags().StringVar(&packageFilter, "package", "", "filter by package name")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
 	cmd.Flags().IntVar(&limit, "limit", 20, "number of items to show")
+	cmd.Flags().StringVar(&since, "since", "", "only deployments recorded at or after this RFC3339 timestamp")
 
 	// Handle --verified flag
 	var verifiedFlag bool
@@ -125,7 +178,9 @@ func createDeploymentInfoCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "info <chain-id> <address>",
 		Short: "Show deployment details",
-		Long: `Display detailed information about a deployment.
+		Long: `Display detailed information about a deployment: the package version it was
+recorded against, verification status and, for chains with a known block
+explorer, a link to the contract's code.
 
 EXAMPLES:
   contrafactory deployment info 1 0x1234...
@@ -141,7 +196,7 @@ EXAMPLES:
 	return cmd
 }
 
-func runDeploymentRecord(pkgRef string, chainID int, address, txHash, deployerAddress string) error {
+func runDeploymentRecord(pkgRef string, chainID int, address, txHash, deployerAddress, salt string, gasUsed uint64) error {
 	if pkgRef == "" {
 		return fmt.Errorf("--package is required")
 	}
@@ -161,7 +216,7 @@ func runDeploymentRecord(pkgRef string, chainID int, address, txHash, deployerAd
 		return fmt.Errorf("contract name required (use package/contract@version format)")
 	}
 
-	c := client.New(getServer(), getAPIKey())
+	c := newClient(getServer(), getAPIKey())
 
 	req := client.DeploymentRequest{
 		Package:         name,
@@ -171,6 +226,8 @@ func runDeploymentRecord(pkgRef string, chainID int, address, txHash, deployerAd
 		Address:         address,
 		TxHash:          txHash,
 		DeployerAddress: deployerAddress,
+		Salt:            salt,
+		GasUsed:         gasUsed,
 	}
 
 	if err := c.RecordDeployment(context.Background(), req); err != nil {
@@ -215,7 +272,7 @@ func runDeploymentRecordFromBroadcast(broadcastPath, pkgRef string) error {
 		return err
 	}
 
-	c := client.New(getServer(), getAPIKey())
+	c := newClient(getServer(), getAPIKey())
 
 	fmt.Printf("📝 Recording %d deployment(s) from broadcast...\n", len(broadcast.Transactions))
 
@@ -244,7 +301,7 @@ func runDeploymentRecordFromBroadcast(broadcastPath, pkgRef string) error {
 	return nil
 }
 
-func runDeploymentList(chainID, packageFilter string, verified *bool, limit int, jsonOutput bool) error {
+func runDeploymentList(chainID, packageFilter string, verified *bool, createdAfter time.Time, limit int, jsonOutput bool) error {
 	serverURL := getServer()
 	apiKey := getAPIKey()
 
@@ -263,6 +320,9 @@ func runDeploymentList(chainID, packageFilter string, verified *bool, limit int,
 			url += "verified=false&"
 		}
 	}
+	if !createdAfter.IsZero() {
+		url += "created_after=" + neturl.QueryEscape(createdAfter.Format(time.RFC3339)) + "&"
+	}
 	url += fmt.Sprintf("limit=%d", limit)
 
 	req, err := http.NewRequestWithContext(context.Background(), "GET", url, nil)
@@ -273,7 +333,7 @@ func runDeploymentList(chainID, packageFilter string, verified *bool, limit int,
 		req.Header.Set("X-API-Key", apiKey)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to list deployments: %w", err)
 	}
@@ -282,7 +342,7 @@ func runDeploymentList(chainID, packageFilter string, verified *bool, limit int,
 	body, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to list deployments: %s", string(body))
+		return fmt.Errorf("failed to list deployments: %w", serverError(resp, body))
 	}
 
 	var result struct {
@@ -332,34 +392,89 @@ func runDeploymentList(chainID, packageFilter string, verified *bool, limit int,
 }
 
 func runDeploymentInfo(chainID, address string, jsonOutput bool) error {
-	c := client.New(getServer(), getAPIKey())
+	c := newClient(getServer(), getAPIKey())
 
 	deployment, err := c.GetDeployment(context.Background(), chainID, address)
 	if err != nil {
 		return fmt.Errorf("failed to get deployment: %w", err)
 	}
 
+	return writeDeploymentInfo(os.Stdout, deployment, jsonOutput)
+}
+
+// deploymentInfo is the --json output of deployment info: the deployment plus
+// the explorer link derived from its chain ID.
+type deploymentInfo struct {
+	*client.Deployment
+	ExplorerURL string `json:"explorerUrl,omitempty"`
+}
+
+func writeDeploymentInfo(out io.Writer, deployment *client.Deployment, jsonOutput bool) error {
+	explorerURL := evm.ExplorerAddressURL(deployment.ChainID, deployment.Address)
+
 	if jsonOutput {
-		enc := json.NewEncoder(os.Stdout)
+		enc := json.NewEncoder(out)
 		enc.SetIndent("", "  ")
-		return enc.Encode(deployment)
+		return enc.Encode(deploymentInfo{Deployment: deployment, ExplorerURL: explorerURL})
 	}
 
-	fmt.Printf("Deployment: %s\n", deployment.Address)
-	fmt.Printf("Chain ID:   %s\n", deployment.ChainID)
-	fmt.Printf("Contract:   %s\n", deployment.ContractName)
+	fmt.Fprintf(out, "Deployment: %s\n", deployment.Address)
+	if name := networks.Name(deployment.ChainID); name != "" {
+		fmt.Fprintf(out, "Chain ID:   %s (%s)\n", deployment.ChainID, name)
+	} else {
+		fmt.Fprintf(out, "Chain ID:   %s\n", deployment.ChainID)
+	}
+	if deployment.PackageName != "" {
+		fmt.Fprintf(out, "Package:    %s/%s@%s\n", deployment.PackageName, deployment.ContractName, deployment.PackageVersion)
+	} else {
+		fmt.Fprintf(out, "Contract:   %s\n", deployment.ContractName)
+	}
 	if deployment.TxHash != "" {
-		fmt.Printf("Tx Hash:    %s\n", deployment.TxHash)
+		fmt.Fprintf(out, "Tx Hash:    %s\n", deployment.TxHash)
 	}
 	if deployment.DeployerAddress != "" {
-		fmt.Printf("Deployer:   %s\n", deployment.DeployerAddress)
+		fmt.Fprintf(out, "Deployer:   %s\n", deployment.DeployerAddress)
 	}
 	if deployment.BlockNumber > 0 {
-		fmt.Printf("Block:      %d\n", deployment.BlockNumber)
+		fmt.Fprintf(out, "Block:      %d\n", deployment.BlockNumber)
+	}
+	if deployment.Timestamp != "" {
+		fmt.Fprintf(out, "Mined:      %s\n", deployment.Timestamp)
+	}
+	if deployment.GasUsed > 0 {
+		fmt.Fprintf(out, "Gas Used:   %d\n", deployment.GasUsed)
+	}
+	if deployment.Salt != "" {
+		fmt.Fprintf(out, "Salt:       %s\n", deployment.Salt)
+	}
+	if deployment.ConstructorArgs != "" {
+		fmt.Fprintf(out, "Ctor Args:  %s\n", deployment.ConstructorArgs)
+	}
+	if len(deployment.Libraries) > 0 {
+		fmt.Fprintln(out, "Libraries:")
+		names := make([]string, 0, len(deployment.Libraries))
+		for name := range deployment.Libraries {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(out, "  %s: %s\n", name, deployment.Libraries[name])
+		}
+	}
+
+	switch {
+	case !deployment.Verified:
+		fmt.Fprintln(out, "Verified:   no")
+	case len(deployment.VerifiedOn) > 0:
+		fmt.Fprintf(out, "Verified:   yes (%s)\n", strings.Join(deployment.VerifiedOn, ", "))
+	default:
+		fmt.Fprintln(out, "Verified:   yes")
+	}
+	if explorerURL != "" {
+		fmt.Fprintf(out, "Explorer:   %s\n", explorerURL)
 	}
-	fmt.Printf("Verified:   %v\n", deployment.Verified)
 	if deployment.CreatedAt != "" {
-		fmt.Printf("Recorded:   %s\n", deployment.CreatedAt)
+		fmt.Fprintf(out, "Recorded:   %s\n", deployment.CreatedAt)
 	}
 
 	return nil
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+// hardhatDeployment is a deployments/<network>/<Contract>.json file written by
+// hardhat-deploy. Only the fields recorded in the registry are read.
+type hardhatDeployment struct {
+	Contract        string `json:"-"` // file name without .json
+	Address         string `json:"address"`
+	TransactionHash string `json:"transactionHash"`
+	Receipt         *struct {
+		From        string `json:"from"`
+		BlockNumber int64  `json:"blockNumber"`
+	} `json:"receipt"`
+}
+
+// readHardhatDeployments reads a hardhat-deploy network directory: the chain ID
+// from its .chainId file and one deployment per <Contract>.json file, in file
+// name order. Subdirectories such as solcInputs are skipped.
+func readHardhatDeployments(dir string) (int, []hardhatDeployment, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ".chainId"))
+	if err != nil {
+		return 0, nil, fmt.Errorf("reading chain ID: %w (is %s a hardhat-deploy network directory?)", err, dir)
+	}
+	chainID, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || chainID <= 0 {
+		return 0, nil, fmt.Errorf("invalid chain ID %q in %s", strings.TrimSpace(string(data)), filepath.Join(dir, ".chainId"))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, nil, fmt.Errorf("reading deployments directory: %w", err)
+	}
+
+	var deployments []hardhatDeployment
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return 0, nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		var d hardhatDeployment
+		if err := json.Unmarshal(data, &d); err != nil {
+			return 0, nil, fmt.Errorf("parsing %s: %w", name, err)
+		}
+		if d.Address == "" {
+			return 0, nil, fmt.Errorf("%s has no address", name)
+		}
+		d.Contract = strings.TrimSuffix(name, ".json")
+		deployments = append(deployments, d)
+	}
+	if len(deployments) == 0 {
+		return 0, nil, fmt.Errorf("no deployments found in %s", dir)
+	}
+	return chainID, deployments, nil
+}
+
+// recordHardhatDeployments records every deployment in a hardhat-deploy network
+// directory against the package version in pkgRef, taking each contract name from
+// its file name. A contract in pkgRef (package/contract@version) records only that
+// contract. Deployments the registry rejects are reported and counted in the error.
+func recordHardhatDeployments(ctx context.Context, out io.Writer, c *client.Client, dir, pkgRef string) error {
+	name, version, contract, err := parsePackageRef(pkgRef)
+	if err != nil {
+		return err
+	}
+
+	chainID, deployments, err := readHardhatDeployments(dir)
+	if err != nil {
+		return err
+	}
+	if contract != "" {
+		var matched []hardhatDeployment
+		for _, d := range deployments {
+			if d.Contract == contract {
+				matched = append(matched, d)
+			}
+		}
+		if len(matched) == 0 {
+			return fmt.Errorf("no deployment of %s in %s", contract, dir)
+		}
+		deployments = matched
+	}
+
+	fmt.Fprintf(out, "📝 Recording %d deployment(s) on chain %d from %s...\n", len(deployments), chainID, dir)
+
+	failed := 0
+	for _, d := range deployments {
+		req := client.DeploymentRequest{
+			Package:  name,
+			Version:  version,
+			Contract: d.Contract,
+			ChainID:  chainID,
+			Address:  d.Address,
+			TxHash:   d.TransactionHash,
+		}
+		if d.Receipt != nil {
+			req.DeployerAddress = d.Receipt.From
+			req.BlockNumber = d.Receipt.BlockNumber
+		}
+
+		if err := c.RecordDeployment(ctx, req); err != nil {
+			fmt.Fprintf(out, "  ⚠️  %s: %v\n", d.Contract, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "  ✓ %s at %s\n", d.Contract, d.Address)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d deployment(s) could not be recorded", failed, len(deployments))
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+// writeHardhatDeployments lays out a hardhat-deploy network directory.
+func writeHardhatDeployments(t *testing.T, chainID string, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if chainID != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".chainId"), []byte(chainID), 0o644))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "solcInputs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "solcInputs", "abc.json"), []byte(`{"language":"Solidity"}`), 0o644))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestReadHardhatDeployments(t *testing.T) {
+	t.Run("reads chain ID and deployments", func(t *testing.T) {
+		dir := writeHardhatDeployments(t, "11155111\n", map[string]string{
+			"Vault.json": `{"address":"0x2222222222222222222222222222222222222222","abi":[],"transactionHash":"0xbb"}`,
+			"Token.json": `{"address":"0x1111111111111111111111111111111111111111","abi":[],"transactionHash":"0xaa",
+				"receipt":{"from":"0x3333333333333333333333333333333333333333","blockNumber":42,"gasUsed":"21000"}}`,
+			"README.md": "not a deployment",
+		})
+
+		chainID, deployments, err := readHardhatDeployments(dir)
+		require.NoError(t, err)
+		assert.Equal(t, 11155111, chainID)
+		require.Len(t, deployments, 2)
+		assert.Equal(t, "Token", deployments[0].Contract, "sorted by file name")
+		assert.Equal(t, "0xaa", deployments[0].TransactionHash)
+		assert.Equal(t, int64(42), deployments[0].Receipt.BlockNumber)
+		assert.Equal(t, "Vault", deployments[1].Contract)
+		assert.Nil(t, deployments[1].Receipt)
+	})
+
+	t.Run("missing .chainId", func(t *testing.T) {
+		dir := writeHardhatDeployments(t, "", map[string]string{"Token.json": `{"address":"0x11"}`})
+		_, _, err := readHardhatDeployments(dir)
+		assert.ErrorContains(t, err, "hardhat-deploy network directory")
+	})
+
+	t.Run("invalid .chainId", func(t *testing.T) {
+		dir := writeHardhatDeployments(t, "mainnet", map[string]string{"Token.json": `{"address":"0x11"}`})
+		_, _, err := readHardhatDeployments(dir)
+		assert.ErrorContains(t, err, `invalid chain ID "mainnet"`)
+	})
+
+	t.Run("no deployments", func(t *testing.T) {
+		_, _, err := readHardhatDeployments(writeHardhatDeployments(t, "1", nil))
+		assert.ErrorContains(t, err, "no deployments found")
+	})
+
+	t.Run("deployment without address", func(t *testing.T) {
+		dir := writeHardhatDeployments(t, "1", map[string]string{"Token.json": `{"abi":[]}`})
+		_, _, err := readHardhatDeployments(dir)
+		assert.ErrorContains(t, err, "Token.json has no address")
+	})
+}
+
+func TestRecordHardhatDeployments(t *testing.T) {
+	dir := writeHardhatDeployments(t, "1", map[string]string{
+		"Token.json":  `{"address":"0x1111111111111111111111111111111111111111","transactionHash":"0xaa","receipt":{"from":"0x3333333333333333333333333333333333333333","blockNumber":42}}`,
+		"Helper.json": `{"address":"0x2222222222222222222222222222222222222222"}`,
+	})
+
+	var recorded []client.DeploymentRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req client.DeploymentRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Contract == "Helper" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"contract not found in package"}}`))
+			return
+		}
+		recorded = append(recorded, req)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	c := client.New(srv.URL, "")
+
+	t.Run("all contracts", func(t *testing.T) {
+		recorded = nil
+		var out bytes.Buffer
+		err := recordHardhatDeployments(context.Background(), &out, c, dir, "my-token@1.0.0")
+		assert.ErrorContains(t, err, "1 of 2 deployment(s) could not be recorded")
+		assert.Contains(t, out.String(), "Helper: NOT_FOUND")
+		assert.Contains(t, out.String(), "✓ Token at 0x1111111111111111111111111111111111111111")
+
+		require.Len(t, recorded, 1)
+		assert.Equal(t, client.DeploymentRequest{
+			Package:         "my-token",
+			Version:         "1.0.0",
+			Contract:        "Token",
+			ChainID:         1,
+			Address:         "0x1111111111111111111111111111111111111111",
+			TxHash:          "0xaa",
+			DeployerAddress: "0x3333333333333333333333333333333333333333",
+			BlockNumber:     42,
+		}, recorded[0])
+	})
+
+	t.Run("one contract", func(t *testing.T) {
+		recorded = nil
+		require.NoError(t, recordHardhatDeployments(context.Background(), &bytes.Buffer{}, c, dir, "my-token/Token@1.0.0"))
+		require.Len(t, recorded, 1)
+
+		err := recordHardhatDeployments(context.Background(), &bytes.Buffer{}, c, dir, "my-token/Vault@1.0.0")
+		assert.ErrorContains(t, err, "no deployment of Vault")
+	})
+}
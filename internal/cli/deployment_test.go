@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func TestWriteDeploymentInfo(t *testing.T) {
+	deployment := &client.Deployment{
+		PackageName:     "my-contracts",
+		PackageVersion:  "1.2.0",
+		ContractName:    "Token",
+		ChainID:         "11155111",
+		Address:         "0x1234567890abcdef1234567890abcdef12345678",
+		ConstructorArgs: "0x01",
+		GasUsed:         1234567,
+		Timestamp:       "2026-01-02T03:04:05Z",
+		Verified:        true,
+		VerifiedOn:      []string{"etherscan", "sourcify"},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeDeploymentInfo(&buf, deployment, false))
+		out := buf.String()
+
+		assert.Contains(t, out, "Package:    my-contracts/Token@1.2.0")
+		assert.Contains(t, out, "Ctor Args:  0x01")
+		assert.Contains(t, out, "Gas Used:   1234567")
+		assert.Contains(t, out, "Mined:      2026-01-02T03:04:05Z")
+		assert.NotContains(t, out, "Salt:")
+		assert.Contains(t, out, "Verified:   yes (etherscan, sourcify)")
+		assert.Contains(t, out, "Explorer:   https://sepolia.etherscan.io/address/0x1234567890abcdef1234567890abcdef12345678#code")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeDeploymentInfo(&buf, deployment, true))
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		assert.Equal(t, "my-contracts", got["packageName"])
+		assert.Equal(t, "1.2.0", got["packageVersion"])
+		assert.Equal(t, "https://sepolia.etherscan.io/address/0x1234567890abcdef1234567890abcdef12345678#code", got["explorerUrl"])
+	})
+
+	t.Run("unknown chain and unverified", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeDeploymentInfo(&buf, &client.Deployment{ContractName: "Token", ChainID: "31337", Address: "0xabc"}, false))
+		out := buf.String()
+
+		assert.Contains(t, out, "Contract:   Token")
+		assert.Contains(t, out, "Verified:   no")
+		assert.NotContains(t, out, "Explorer:")
+	})
+}
+
+func TestWriteVersionDeployments(t *testing.T) {
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeVersionDeployments(&buf, []client.VersionDeployment{
+			{ChainID: "1", Address: "0x1234567890abcdef1234567890abcdef12345678", ContractName: "Token", Verified: true},
+			{ChainID: "31337", Address: "0xabc", ContractName: "Token"},
+		})
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 4)
+
+		assert.Equal(t, "Deployments (2):", lines[0])
+		assert.Equal(t, []string{"CHAIN", "ADDRESS", "CONTRACT", "VERIFIED", "EXPLORER"}, strings.Fields(lines[1]))
+		assert.Equal(t, []string{"1", "0x1234...5678", "Token", "yes", "https://etherscan.io/address/0x1234567890abcdef1234567890abcdef12345678#code"}, strings.Fields(lines[2]))
+		assert.Equal(t, []string{"31337", "0xabc", "Token", "no", "-"}, strings.Fields(lines[3]))
+	})
+
+	t.Run("none", func(t *testing.T) {
+		var buf bytes.Buffer
+		writeVersionDeployments(&buf, nil)
+		assert.Equal(t, "Deployments: none recorded\n", buf.String())
+	})
+
+	t.Run("json carries explorer links", func(t *testing.T) {
+		infos := versionDeploymentInfos([]client.VersionDeployment{{ChainID: "1", Address: "0xabc", ContractName: "Token"}})
+		got, err := json.Marshal(infos)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"chainId":"1","address":"0xabc","contractName":"Token","verified":false,"explorerUrl":"https://etherscan.io/address/0xabc#code"}]`, string(got))
+	})
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/internal/chains/evm"
+	"github.com/pendergraft/contrafactory/pkg/client"
+	"github.com/pendergraft/contrafactory/pkg/evmutil"
+)
+
+func createDeploymentVerifyAllCmd() *cobra.Command {
+	var chainID string
+	var rpcURL string
+
+	cmd := &cobra.Command{
+		Use:   "verify-all <package>@<version>",
+		Short: "Verify every deployment of a package version on one chain",
+		Long: `Compare the on-chain code of every recorded deployment of a package version
+with its stored deployed bytecode, using the same metadata-stripping comparison
+as the server. The code of all addresses is fetched with batched eth_getCode
+calls, so large versions take one RPC round-trip per 100 deployments rather
+than one per deployment.
+
+Give package/contract@version to check only one contract's deployments.
+
+EXAMPLES:
+  contrafactory deployment verify-all my-contracts@1.0.0 \
+    --chain-id 1 \
+    --rpc https://eth-mainnet.example.com
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDeploymentVerifyAll(cmd.OutOrStdout(), evm.NewChain(), args[0], chainID, rpcURL)
+		},
+	}
+
+	cmd.Flags().StringVar(&chainID, "chain-id", "", "chain ID the deployments are on (required)")
+	cmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL of that chain (required)")
+	_ = cmd.MarkFlagRequired("chain-id")
+	_ = cmd.MarkFlagRequired("rpc")
+
+	return cmd
+}
+
+// runDeploymentVerifyAll checks the recorded deployments of a package version on
+// chainID against the code at their addresses. Every deployment is reported; the
+// error counts those that did not verify.
+func runDeploymentVerifyAll(out io.Writer, chain *evm.Chain, pkgRef, chainID, rpcURL string) error {
+	name, version, contract, err := parsePackageRef(pkgRef)
+	if err != nil {
+		return fmt.Errorf("invalid package reference: %w", err)
+	}
+
+	ctx := context.Background()
+	c := newClient(getServer(), getAPIKey())
+
+	recorded, err := c.GetVersionDeployments(ctx, name, version)
+	if err != nil {
+		return fmt.Errorf("listing deployments: %w", err)
+	}
+	var deployments []client.VersionDeployment
+	for _, d := range recorded {
+		if d.ChainID == chainID && (contract == "" || d.ContractName == contract) {
+			deployments = append(deployments, d)
+		}
+	}
+	if len(deployments) == 0 {
+		return fmt.Errorf("no deployments of %s recorded on chain %s", pkgRef, chainID)
+	}
+
+	addresses := make([]string, len(deployments))
+	for i, d := range deployments {
+		addresses[i] = d.Address
+	}
+	codes, err := chain.GetDeployedBytecodeBatch(ctx, rpcURL, addresses)
+	if err != nil {
+		return fmt.Errorf("fetching on-chain bytecode: %w", err)
+	}
+
+	fmt.Fprintf(out, "🔍 Verifying %d deployments of %s@%s on chain %s\n\n", len(deployments), name, version, chainID)
+
+	stored := make(map[string][]byte) // contract name -> deployed bytecode
+	failed := 0
+	for _, d := range deployments {
+		expected, ok := stored[d.ContractName]
+		if !ok {
+			artifact, err := c.GetDeployedBytecode(ctx, name, version, d.ContractName)
+			if err != nil {
+				return fmt.Errorf("fetching deployed bytecode of %s: %w", d.ContractName, err)
+			}
+			expected = bytes.TrimSpace(artifact)
+			stored[d.ContractName] = expected
+		}
+
+		code := codes[d.Address]
+		switch {
+		case errors.Is(code.Err, evm.ErrNotContract):
+			fmt.Fprintf(out, "❌ %s %s: no contract code at address\n", d.ContractName, d.Address)
+			failed++
+		case code.Err != nil:
+			fmt.Fprintf(out, "❌ %s %s: %v\n", d.ContractName, d.Address, code.Err)
+			failed++
+		default:
+			result := evmutil.CompareBytecode(code.Code, expected, nil)
+			switch {
+			case result.Match:
+				fmt.Fprintf(out, "✅ %s %s: %s match\n", d.ContractName, d.Address, result.MatchType)
+			case result.MatchType == evmutil.MatchClone:
+				fmt.Fprintf(out, "❌ %s %s: minimal proxy; compare the code at %s instead\n", d.ContractName, d.Address, result.Implementation)
+				failed++
+			default:
+				fmt.Fprintf(out, "❌ %s %s: no match\n", d.ContractName, d.Address)
+				failed++
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d deployments did not verify", failed, len(deployments))
+	}
+	return nil
+}
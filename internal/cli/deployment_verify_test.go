@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/chains/evm"
+)
+
+func TestRunDeploymentVerifyAll(t *testing.T) {
+	const (
+		tokenAddr  = "0x00000000000000000000000000000000000000aa"
+		vaultAddr  = "0x00000000000000000000000000000000000000bb"
+		emptyAddr  = "0x00000000000000000000000000000000000000cc"
+		polyAddr   = "0x00000000000000000000000000000000000000dd"
+		tokenCode  = "0x6001"
+		vaultCode  = "0x6002"
+		wrongVault = "0x6003"
+	)
+
+	onChain := map[string]string{tokenAddr: tokenCode, vaultAddr: wrongVault, emptyAddr: "0x"}
+	var rpcCalls int
+	rpc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rpcCalls++
+		var reqs []struct {
+			ID     int    `json:"id"`
+			Method string `json:"method"`
+			Params []any  `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqs), "expected a batch request")
+		resps := make([]map[string]any, len(reqs))
+		for i, req := range reqs {
+			assert.Equal(t, "eth_getCode", req.Method)
+			resps[i] = map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": onChain[req.Params[0].(string)]}
+		}
+		json.NewEncoder(w).Encode(resps)
+	}))
+	defer rpc.Close()
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/packages/my-contracts/1.0.0/deployments":
+			json.NewEncoder(w).Encode(map[string]any{"deployments": []map[string]any{
+				{"chainId": "1", "address": tokenAddr, "contractName": "Token"},
+				{"chainId": "1", "address": vaultAddr, "contractName": "Vault"},
+				{"chainId": "1", "address": emptyAddr, "contractName": "Token"},
+				{"chainId": "137", "address": polyAddr, "contractName": "Token"},
+			}})
+		case "/api/v1/packages/my-contracts/1.0.0/contracts/Token/deployed-bytecode":
+			w.Write([]byte(tokenCode))
+		case "/api/v1/packages/my-contracts/1.0.0/contracts/Vault/deployed-bytecode":
+			w.Write([]byte(vaultCode))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+
+	oldServer := server
+	server = registry.URL
+	defer func() { server = oldServer }()
+
+	t.Run("all deployments on the chain", func(t *testing.T) {
+		rpcCalls = 0
+		var out bytes.Buffer
+		err := runDeploymentVerifyAll(&out, evm.NewChain(), "my-contracts@1.0.0", "1", rpc.URL)
+		assert.EqualError(t, err, "2 of 3 deployments did not verify")
+		assert.Equal(t, 1, rpcCalls, "code for every address is fetched in one batch")
+
+		assert.Contains(t, out.String(), "✅ Token "+tokenAddr+": full match")
+		assert.Contains(t, out.String(), "❌ Vault "+vaultAddr+": no match")
+		assert.Contains(t, out.String(), "❌ Token "+emptyAddr+": no contract code at address")
+		assert.NotContains(t, out.String(), polyAddr)
+	})
+
+	t.Run("one contract", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runDeploymentVerifyAll(&out, evm.NewChain(), "my-contracts/Vault@1.0.0", "1", rpc.URL)
+		assert.EqualError(t, err, "1 of 1 deployments did not verify")
+		assert.NotContains(t, out.String(), "Token")
+	})
+
+	t.Run("nothing recorded on the chain", func(t *testing.T) {
+		err := runDeploymentVerifyAll(&bytes.Buffer{}, evm.NewChain(), "my-contracts@1.0.0", "10", rpc.URL)
+		assert.ErrorContains(t, err, "no deployments of my-contracts@1.0.0 recorded on chain 10")
+	})
+}
@@ -2,7 +2,9 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"strings"
 	"text/tabwriter"
 
@@ -22,10 +24,13 @@ func createDiscoverCmd() *cobra.Command {
 		Long: `Discover contracts that would be published or are available as dependencies.
 
 This command examines your Foundry build artifacts to show:
-- Contracts from src/ that would be published (default)
+- Contracts that would be published with the contrafactory.toml settings (default),
+  with their source paths and whether they come from src/ or a dependency
 - Dependency contracts from lib/ that could be included (--deps)
 - Both (--all)
 
+Nothing is published; use it to check contracts, exclude and include_dependencies.
+
 EXAMPLES:
   # Show contracts that would be published
   contrafactory discover
@@ -37,7 +42,7 @@ EXAMPLES:
   contrafactory discover --all
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDiscover(showDeps, showAll)
+			return runDiscover(cmd.OutOrStdout(), showDeps, showAll)
 		},
 	}
 
@@ -47,45 +52,55 @@ EXAMPLES:
 	return cmd
 }
 
-func runDiscover(showDeps, showAll bool) error {
+func runDiscover(out io.Writer, showDeps, showAll bool) error {
 	// Get current directory
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("getting current directory: %w", err)
 	}
 
+	if isAnchorProject(cwd) {
+		var exclude []string
+		if projectConfig := loadProjectConfigSilent(); projectConfig != nil {
+			exclude = projectConfig.Exclude
+		}
+		return runDiscoverAnchor(cwd, exclude)
+	}
+
 	// Detect builder
-	builder := foundry.New()
+	builder := newFoundryBuilder()
 	detected, err := builder.Detect(cwd)
 	if err != nil {
 		return fmt.Errorf("detecting builder: %w", err)
 	}
 	if !detected {
-		return fmt.Errorf("no Foundry project detected (missing foundry.toml)")
+		return fmt.Errorf("no Foundry or Anchor project detected (missing foundry.toml or Anchor.toml)")
 	}
 
+	warnBuildStaleness(builder, cwd)
+
+	return discoverFoundry(out, builder, cwd, loadProjectConfigSilent(), showDeps, showAll)
+}
+
+// discoverFoundry prints the contracts of the Foundry project in cwd that publish
+// would pick up with config, and with showDeps/showAll the dependency contracts
+// available to include_dependencies.
+func discoverFoundry(out io.Writer, builder *foundry.Builder, cwd string, projectConfig *ProjectConfig, showDeps, showAll bool) error {
 	// Determine what to show
 	showSrc := !showDeps || showAll
 	showLib := showDeps || showAll
 
-	// Load project config for exclude patterns
-	projectConfig := loadProjectConfigSilent()
-	excludePatterns := defaultExcludePatterns
-	if projectConfig != nil && len(projectConfig.Exclude) > 0 {
-		excludePatterns = projectConfig.Exclude
-	}
-	var excludePathPatterns []string
+	// Resolve the discovery options the way publish does without flags
+	discoverOpts := chains.DiscoverOptions{Exclude: resolveExcludePatterns(nil, false, projectConfig)}
 	if projectConfig != nil {
-		excludePathPatterns = projectConfig.ExcludePaths
+		discoverOpts.Contracts = projectConfig.Contracts
+		discoverOpts.ExcludePaths = projectConfig.ExcludePaths
+		discoverOpts.ExcludeKinds = projectConfig.ExcludeKinds
+		discoverOpts.IncludeDependencies = projectConfig.IncludeDependencies
 	}
 
-	// Discover src contracts
+	// Discover the contracts publish would publish
 	if showSrc {
-		discoverOpts := chains.DiscoverOptions{
-			Exclude:      excludePatterns,
-			ExcludePaths: excludePathPatterns,
-		}
-
 		artifactPaths, err := builder.Discover(cwd, discoverOpts)
 		if err != nil {
 			if strings.Contains(err.Error(), "build-info") {
@@ -95,20 +110,23 @@ func runDiscover(showDeps, showAll bool) error {
 		}
 
 		if len(artifactPaths) == 0 {
-			fmt.Println("No contracts found in src/")
-			fmt.Println("\nMake sure you've run 'forge build' and have contracts in your src/ directory.")
+			fmt.Fprintln(out, "No contracts found in src/")
+			fmt.Fprintln(out, "\nMake sure you've run 'forge build' and have contracts in your src/ directory.")
 		} else {
-			fmt.Printf("Contracts in src/ (%d):\n\n", len(artifactPaths))
+			fmt.Fprintf(out, "Contracts that would be published (%d):\n\n", len(artifactPaths))
 
-			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "  NAME\tSOURCE\tORIGIN\n")
 			for _, path := range artifactPaths {
 				artifact, err := builder.Parse(path)
-				if err != nil {
+				if err != nil || artifact.EVM == nil {
 					continue
 				}
-				if artifact.EVM != nil {
-					fmt.Fprintf(w, "  %s\t%s\n", artifact.Name, artifact.EVM.SourcePath)
+				origin := "src"
+				if !strings.HasPrefix(artifact.EVM.SourcePath, "src/") {
+					origin = "dependency"
 				}
+				fmt.Fprintf(w, "  %s\t%s\t%s\n", artifact.Name, artifact.EVM.SourcePath, origin)
 			}
 			w.Flush()
 		}
@@ -117,33 +135,34 @@ func runDiscover(showDeps, showAll bool) error {
 	// Discover lib dependencies
 	if showLib {
 		if showSrc {
-			fmt.Println()
+			fmt.Fprintln(out)
 		}
 
 		deps, err := builder.DiscoverDependencies(cwd)
 		if err != nil {
-			if strings.Contains(err.Error(), "out directory not found") {
-				return fmt.Errorf("out directory not found - run 'forge build' first")
-			}
 			return fmt.Errorf("discovering dependencies: %w", err)
 		}
 
 		if len(deps) == 0 {
-			fmt.Println("No dependency contracts found in lib/")
-			fmt.Println("\nDependencies are contracts from lib/ that have bytecode.")
-			fmt.Println("Run 'forge install' to add dependencies to your project.")
+			fmt.Fprintln(out, "No dependency contracts found in lib/")
+			fmt.Fprintln(out, "\nDependencies are contracts from lib/ that have bytecode.")
+			fmt.Fprintln(out, "Run 'forge install' to add dependencies to your project.")
 		} else {
-			fmt.Printf("Available dependency contracts from lib/ (%d):\n\n", len(deps))
+			fmt.Fprintf(out, "Available dependency contracts from lib/ (%d):\n\n", len(deps))
 
-			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-			fmt.Fprintf(w, "  NAME\tSOURCE\n")
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "  NAME\tSOURCE\tINCLUDED\n")
 			for _, dep := range deps {
-				fmt.Fprintf(w, "  %s\t%s\n", dep.Name, dep.SourcePath)
+				included := "no"
+				if slices.ContainsFunc(discoverOpts.IncludeDependencies, func(d string) bool { return strings.EqualFold(d, dep.Name) }) {
+					included = "yes"
+				}
+				fmt.Fprintf(w, "  %s\t%s\t%s\n", dep.Name, dep.SourcePath, included)
 			}
 			w.Flush()
 
-			fmt.Println()
-			fmt.Println("Tip: add names to include_dependencies in contrafactory.toml to publish them")
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, "Tip: add names to include_dependencies in contrafactory.toml to publish them")
 		}
 	}
 
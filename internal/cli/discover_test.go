@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/chains/evm/foundry"
+)
+
+// writeFoundryArtifact adds a built artifact for contract compiled from sourcePath.
+func writeFoundryArtifact(t *testing.T, dir, contract, sourcePath string) {
+	t.Helper()
+	artifact := map[string]any{
+		"abi":              []map[string]any{},
+		"bytecode":         map[string]any{"object": "0x6080"},
+		"deployedBytecode": map[string]any{"object": "0x6080"},
+		"rawMetadata":      `{"compiler":{"version":"0.8.28+commit.7893614a"},"settings":{"compilationTarget":{"` + sourcePath + `":"` + contract + `"}}}`,
+	}
+	data, err := json.Marshal(artifact)
+	require.NoError(t, err)
+	outDir := filepath.Join(dir, "out", filepath.Base(sourcePath))
+	require.NoError(t, os.MkdirAll(outDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(outDir, contract+".json"), data, 0644))
+}
+
+func TestDiscoverFoundry(t *testing.T) {
+	dir := writeFoundryProject(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "out", "build-info"), 0755))
+	writeFoundryArtifact(t, dir, "ERC20", "lib/oz/ERC20.sol")
+	writeFoundryArtifact(t, dir, "Ownable", "lib/oz/Ownable.sol")
+
+	t.Run("src only by default", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, discoverFoundry(&out, foundry.New(), dir, nil, false, false))
+		assert.Contains(t, out.String(), "Contracts that would be published (1)")
+		assert.Regexp(t, `Token\s+src/Token.sol\s+src`, out.String())
+		assert.NotContains(t, out.String(), "ERC20")
+	})
+
+	t.Run("include_dependencies are published and marked", func(t *testing.T) {
+		config := &ProjectConfig{IncludeDependencies: []string{"erc20"}}
+		var out bytes.Buffer
+		require.NoError(t, discoverFoundry(&out, foundry.New(), dir, config, false, true))
+		assert.Contains(t, out.String(), "Contracts that would be published (2)")
+		assert.Regexp(t, `ERC20\s+lib/oz/ERC20.sol\s+dependency`, out.String())
+		assert.Contains(t, out.String(), "Available dependency contracts from lib/ (2)")
+		assert.Regexp(t, `ERC20\s+lib/oz/ERC20.sol\s+yes`, out.String())
+		assert.Regexp(t, `Ownable\s+lib/oz/Ownable.sol\s+no`, out.String())
+	})
+
+	t.Run("missing out directory", func(t *testing.T) {
+		empty := t.TempDir()
+		err := discoverFoundry(&bytes.Buffer{}, foundry.New(), empty, nil, true, false)
+		assert.ErrorContains(t, err, "run 'forge build' first")
+	})
+}
@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/internal/validation"
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func createExportCmd() *cobra.Command {
+	var out string
+	var withRemappings bool
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "export <package>@<version>...",
+		Short: "Export package sources as a Foundry dependency",
+		Long: `Download the sources of one or more packages and lay them out like a Foundry
+dependency in lib/, so downstream projects can build against them.
+
+With one package, --out is the package's directory (default lib/<package>). With
+several, each package goes into <out>/<package> (default lib/<package>).
+
+If the package was built with remappings, they are written to the exported
+directory's remappings.txt, which Foundry picks up for nested dependencies.
+--with-remappings also adds a <package>/ entry for each export to ./remappings.txt,
+replacing any existing entry for the same package.
+
+EXAMPLES:
+  contrafactory export my-token@1.0.0 --out lib/my-token --with-remappings
+  contrafactory export my-token@^1.0.0 my-vault@latest --with-remappings
+
+  # In Solidity, after --with-remappings:
+  #   import {Token} from "my-token/Token.sol";
+`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			remappingsFile := ""
+			if withRemappings {
+				remappingsFile = "remappings.txt"
+			}
+			c := newClient(getServer(), getAPIKey())
+			return runExport(context.Background(), cmd.OutOrStdout(), c, args, out, remappingsFile, force)
+		},
+	}
+
+	cmd.Flags().StringVarP(&out, "out", "o", "", "output directory (default lib/<package>)")
+	cmd.Flags().BoolVar(&withRemappings, "with-remappings", false, "add an entry for each package to ./remappings.txt")
+	cmd.Flags().BoolVar(&force, "force", false, "replace output directories that already exist")
+
+	return cmd
+}
+
+// runExport exports each package ref into its own directory and, when
+// remappingsFile is set, points a <package>/ remapping at it.
+func runExport(ctx context.Context, out io.Writer, c *client.Client, refs []string, outDir, remappingsFile string, force bool) error {
+	remappings := make(map[string]string)
+	for _, ref := range refs {
+		name, version, refContract, err := parsePackageRef(ref)
+		if err != nil {
+			return err
+		}
+		if refContract != "" {
+			return fmt.Errorf("export takes whole packages; drop /%s from %s", refContract, ref)
+		}
+
+		dir := filepath.Join("lib", name)
+		switch {
+		case len(refs) == 1 && outDir != "":
+			dir = outDir
+		case outDir != "":
+			dir = filepath.Join(outDir, name)
+		}
+
+		resolved, err := resolveVersion(ctx, c, name, version)
+		if err != nil {
+			return err
+		}
+
+		target, err := exportPackage(ctx, c, name, resolved, dir, force)
+		if err != nil {
+			return fmt.Errorf("%s@%s: %w", name, resolved, err)
+		}
+		remappings[name+"/"] = name + "/=" + target
+		fmt.Fprintf(out, "📦 Exported %s@%s to %s\n", name, resolved, dir)
+	}
+
+	if remappingsFile != "" {
+		if err := updateRemappings(remappingsFile, remappings); err != nil {
+			return fmt.Errorf("updating %s: %w", remappingsFile, err)
+		}
+		fmt.Fprintf(out, "✅ Updated %s\n", remappingsFile)
+	}
+	return nil
+}
+
+// exportPackage writes the sources of a package version to dir and returns the
+// remapping target for it: dir's src/ when the package has one, dir otherwise.
+func exportPackage(ctx context.Context, c *client.Client, name, version, dir string, force bool) (string, error) {
+	data, err := c.GetArchive(ctx, name, version)
+	if err != nil {
+		return "", fmt.Errorf("failed to download archive: %w", err)
+	}
+	files, manifest, err := readArchive(data)
+	if err != nil {
+		return "", fmt.Errorf("reading archive: %w", err)
+	}
+
+	// Every contract carries the sources of its own compilation; they overlap
+	sources := make(map[string][]byte)
+	var remappings []string
+	for _, contract := range manifest.Contracts {
+		prefix := contract.Name + "/sources/"
+		for p, content := range files {
+			if source, ok := strings.CutPrefix(p, prefix); ok {
+				sources[source] = content
+			}
+		}
+		remappings = append(remappings, standardJSONRemappings(files[contract.Name+"/standard-json-input.json"])...)
+	}
+	if len(sources) == 0 {
+		return "", fmt.Errorf("no source files to export (the package was published without sources)")
+	}
+
+	// Check every entry before touching dir, so a bad archive writes nothing
+	dests := make(map[string]string, len(sources))
+	for p := range sources {
+		dest, err := extractPath(dir, p)
+		if err != nil {
+			return "", err
+		}
+		dests[p] = dest
+	}
+
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		if !force {
+			return "", fmt.Errorf("%s already exists (use --force to replace it)", dir)
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return "", err
+		}
+	}
+
+	hasSrc := false
+	for p, content := range sources {
+		hasSrc = hasSrc || strings.HasPrefix(p, "src/")
+		dest := dests[p]
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(dest, content, 0644); err != nil {
+			return "", err
+		}
+	}
+
+	if len(remappings) > 0 {
+		slices.Sort(remappings)
+		content := strings.Join(slices.Compact(remappings), "\n") + "\n"
+		if err := os.WriteFile(filepath.Join(dir, "remappings.txt"), []byte(content), 0644); err != nil {
+			return "", err
+		}
+	}
+
+	target := filepath.ToSlash(dir) + "/"
+	if hasSrc {
+		target = path.Join(target, "src") + "/"
+	}
+	return target, nil
+}
+
+// extractPath returns where the archive entry p is written under dir, refusing
+// entries that would land outside it.
+func extractPath(dir, p string) (string, error) {
+	if err := validation.ValidateSourcePath(p); err != nil {
+		return "", fmt.Errorf("refusing to write source outside %s: %s: %w", dir, p, err)
+	}
+	dest := filepath.Join(dir, filepath.FromSlash(p))
+	if rel, err := filepath.Rel(dir, dest); err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("refusing to write source outside %s: %s", dir, p)
+	}
+	return dest, nil
+}
+
+// standardJSONRemappings returns settings.remappings of a Standard JSON Input.
+func standardJSONRemappings(data []byte) []string {
+	var input struct {
+		Settings struct {
+			Remappings []string `json:"remappings"`
+		} `json:"settings"`
+	}
+	if len(data) == 0 || json.Unmarshal(data, &input) != nil {
+		return nil
+	}
+	return input.Settings.Remappings
+}
+
+// updateRemappings sets the given remappings (keyed by prefix) in a remappings.txt,
+// replacing lines with the same prefix and appending the rest in prefix order.
+func updateRemappings(file string, remappings map[string]string) error {
+	existing, err := os.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var buf bytes.Buffer
+	written := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(existing))
+	for scanner.Scan() {
+		line := scanner.Text()
+		prefix, _, _ := strings.Cut(strings.TrimSpace(line), "=")
+		// Context-specific remappings (ctx:prefix=target) are left alone
+		if remapping, ok := remappings[prefix]; ok {
+			if !written[prefix] {
+				buf.WriteString(remapping + "\n")
+				written[prefix] = true
+			}
+			continue
+		}
+		buf.WriteString(line + "\n")
+	}
+	for _, prefix := range slices.Sorted(maps.Keys(remappings)) {
+		if !written[prefix] {
+			buf.WriteString(remappings[prefix] + "\n")
+		}
+	}
+	return os.WriteFile(file, buf.Bytes(), 0644)
+}
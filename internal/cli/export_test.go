@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func TestRunExport(t *testing.T) {
+	archives := map[string][]byte{
+		"my-token/1.0.0": testArchive(t, "my-token", "1.0.0", map[string]string{
+			"manifest.json":                  `{"name":"my-token","version":"1.0.0","chain":"evm","contracts":[{"name":"Token","sourcePath":"src/Token.sol"},{"name":"Vault","sourcePath":"src/Vault.sol"}]}`,
+			"Token/sources/src/Token.sol":    "contract Token {}",
+			"Token/sources/lib/oz/ERC20.sol": "contract ERC20 {}",
+			"Token/standard-json-input.json": `{"settings":{"remappings":["@oz/=lib/oz/"]}}`,
+			"Vault/sources/src/Vault.sol":    "contract Vault {}",
+			"Vault/sources/src/Token.sol":    "contract Token {}",
+		}),
+		"flat/2.0.0": testArchive(t, "flat", "2.0.0", map[string]string{
+			"manifest.json":       `{"name":"flat","version":"2.0.0","chain":"evm","contracts":[{"name":"Lib","sourcePath":"Lib.sol"}]}`,
+			"Lib/sources/Lib.sol": "library Lib {}",
+		}),
+		"bare/1.0.0": testArchive(t, "bare", "1.0.0", map[string]string{
+			"manifest.json": `{"name":"bare","version":"1.0.0","chain":"evm","contracts":[{"name":"Bare","sourcePath":"src/Bare.sol"}]}`,
+			"Bare/abi.json": `[]`,
+		}),
+		"evil/1.0.0": testArchive(t, "evil", "1.0.0", map[string]string{
+			"manifest.json":                        `{"name":"evil","version":"1.0.0","chain":"evm","contracts":[{"name":"Evil","sourcePath":"src/Evil.sol"}]}`,
+			"Evil/sources/src/Evil.sol":            "contract Evil {}",
+			"Evil/sources/../../../../escaped.sol": "contract Escaped {}",
+		}),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ref, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/packages/"), "/archive")
+		if !ok || archives[ref] == nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(archives[ref])
+	}))
+	defer srv.Close()
+	c := client.New(srv.URL, "")
+
+	t.Run("single package with remappings", func(t *testing.T) {
+		dir := t.TempDir()
+		remappings := filepath.Join(dir, "remappings.txt")
+		require.NoError(t, os.WriteFile(remappings, []byte("forge-std/=lib/forge-std/src/\nmy-token/=lib/old/\n"), 0644))
+
+		out := filepath.Join(dir, "lib", "my-token")
+		var buf bytes.Buffer
+		require.NoError(t, runExport(context.Background(), &buf, c, []string{"my-token@1.0.0"}, out, remappings, false))
+		assert.Contains(t, buf.String(), "Exported my-token@1.0.0 to "+out)
+
+		for p, want := range map[string]string{
+			"src/Token.sol":    "contract Token {}",
+			"src/Vault.sol":    "contract Vault {}",
+			"lib/oz/ERC20.sol": "contract ERC20 {}",
+			"remappings.txt":   "@oz/=lib/oz/\n",
+		} {
+			got, err := os.ReadFile(filepath.Join(out, p))
+			require.NoError(t, err, p)
+			assert.Equal(t, want, string(got), p)
+		}
+
+		got, err := os.ReadFile(remappings)
+		require.NoError(t, err)
+		assert.Equal(t, "forge-std/=lib/forge-std/src/\nmy-token/="+filepath.ToSlash(out)+"/src/\n", string(got))
+
+		// Exporting again needs --force
+		err = runExport(context.Background(), &buf, c, []string{"my-token@1.0.0"}, out, "", false)
+		assert.ErrorContains(t, err, "use --force")
+		require.NoError(t, runExport(context.Background(), &buf, c, []string{"my-token@1.0.0"}, out, "", true))
+	})
+
+	t.Run("several packages go under --out", func(t *testing.T) {
+		dir := t.TempDir()
+		remappings := filepath.Join(dir, "remappings.txt")
+		require.NoError(t, runExport(context.Background(), &bytes.Buffer{}, c, []string{"my-token@1.0.0", "flat@2.0.0"}, dir, remappings, false))
+
+		assert.FileExists(t, filepath.Join(dir, "my-token", "src", "Token.sol"))
+		assert.FileExists(t, filepath.Join(dir, "flat", "Lib.sol"))
+		assert.NoFileExists(t, filepath.Join(dir, "flat", "remappings.txt"))
+
+		got, err := os.ReadFile(remappings)
+		require.NoError(t, err)
+		d := filepath.ToSlash(dir)
+		assert.Equal(t, "flat/="+d+"/flat/\nmy-token/="+d+"/my-token/src/\n", string(got))
+	})
+
+	t.Run("package without sources", func(t *testing.T) {
+		err := runExport(context.Background(), &bytes.Buffer{}, c, []string{"bare@1.0.0"}, t.TempDir(), "", false)
+		assert.ErrorContains(t, err, "bare@1.0.0: no source files to export")
+	})
+
+	t.Run("entries escaping the output directory", func(t *testing.T) {
+		dir := t.TempDir()
+		out := filepath.Join(dir, "a", "b", "lib", "evil")
+		err := runExport(context.Background(), &bytes.Buffer{}, c, []string{"evil@1.0.0"}, out, "", false)
+		assert.ErrorContains(t, err, "refusing to write source outside")
+		assert.NoFileExists(t, filepath.Join(dir, "escaped.sol"))
+		assert.NoDirExists(t, out)
+	})
+
+	t.Run("contract refs are rejected", func(t *testing.T) {
+		err := runExport(context.Background(), &bytes.Buffer{}, c, []string{"my-token/Token@1.0.0"}, t.TempDir(), "", false)
+		assert.ErrorContains(t, err, "drop /Token")
+	})
+}
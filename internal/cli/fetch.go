@@ -16,6 +16,9 @@ func createFetchCmd() *cobra.Command {
 	var output string
 	var only string
 	var contract string
+	var archive string
+	var abiOnly bool
+	var solc string
 
 	cmd := &cobra.Command{
 		Use:   "fetch <package>@<version>",
@@ -28,11 +31,18 @@ EXAMPLES:
   # Fetch a package's artifacts
   contrafactory fetch Token@1.0.0
 
+  # Fetch the highest 1.x release (also: latest, ~1.2.0, 1.x, ">=1.0.0 <2.0.0")
+  contrafactory fetch Token@^1.0.0
+
   # Fetch to a specific directory
   contrafactory fetch Token@1.0.0 --output ./artifacts
 
-  # Fetch only ABI
-  contrafactory fetch Token@1.0.0 --only abi
+  # Fetch only the ABIs (same as --only abi)
+  contrafactory fetch Token@1.0.0 --abi-only
+
+  # Print one contract's ABI or bytecode instead of writing files
+  contrafactory abi Token/Token@1.0.0
+  contrafactory bytecode Token/Token@1.0.0 --deployed
 
   # Fetch only bytecode
   contrafactory fetch Token@1.0.0 --only bytecode
@@ -42,21 +52,42 @@ EXAMPLES:
 
   # Fetch storage layout (for upgradeable contract planning)
   contrafactory fetch Token@1.0.0 --only storage-layout
+
+  # Download the whole package as a single zip (or tar.gz) archive
+  contrafactory fetch Token@1.0.0 --archive zip
+
+  # Warn if the package was compiled with a different solc minor release
+  contrafactory fetch Token@1.0.0 --solc 0.8.19
 `,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runFetch(args[0], output, only, contract)
+			if abiOnly {
+				if only != "" && only != "abi" {
+					return fmt.Errorf("--abi-only cannot be combined with --only %s", only)
+				}
+				only = "abi"
+			}
+			if archive != "" {
+				if only != "" || contract != "" || solc != "" {
+					return fmt.Errorf("--archive cannot be used with --only, --contract or --solc")
+				}
+				return runFetchArchive(args[0], output, archive)
+			}
+			return runFetch(args[0], output, only, contract, solc)
 		},
 	}
 
 	cmd.Flags().StringVarP(&output, "output", "o", ".", "output directory")
 	cmd.Flags().StringVar(&only, "only", "", "fetch only specific artifact type (abi, bytecode, deployed-bytecode, standard-json-input, storage-layout)")
+	cmd.Flags().BoolVar(&abiOnly, "abi-only", false, "fetch only ABIs (shorthand for --only abi)")
 	cmd.Flags().StringVar(&contract, "contract", "", "fetch only a specific contract")
+	cmd.Flags().StringVar(&archive, "archive", "", "download the package as a single archive: tar.gz or zip")
+	cmd.Flags().StringVar(&solc, "solc", "", "solc version you build with; warns if the package used a different major or minor release")
 
 	return cmd
 }
 
-func runFetch(ref, output, only, contractFilter string) error {
+func runFetch(ref, output, only, contractFilter, solc string) error {
 	name, version, refContract, err := parsePackageRef(ref)
 	if err != nil {
 		return err
@@ -67,14 +98,32 @@ func runFetch(ref, output, only, contractFilter string) error {
 		contractFilter = refContract
 	}
 
-	c := client.New(getServer(), getAPIKey())
+	c := newClient(getServer(), getAPIKey())
 	ctx := context.Background()
 
+	resolved, err := resolveVersion(ctx, c, name, version)
+	if err != nil {
+		return err
+	}
+	if resolved != version {
+		fmt.Printf("Resolved %s@%s to %s\n", name, version, resolved)
+		version = resolved
+	}
+
 	// Get package info to list contracts
-	pkg, err := c.GetPackageVersion(ctx, name, version)
+	pkg, err := c.GetPackageVersionForSolc(ctx, name, version, solc)
 	if err != nil {
 		return fmt.Errorf("failed to get package: %w", err)
 	}
+	// An alias (stable, audited) is resolved by the server; fetch the version it
+	// pointed at so a moved alias can't mix artifacts of two versions
+	if pkg.Version != "" && pkg.Version != version {
+		fmt.Printf("Resolved %s@%s to %s\n", name, version, pkg.Version)
+		version = pkg.Version
+	}
+	for _, w := range pkg.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
 
 	// Create output directory
 	outDir := filepath.Join(output, fmt.Sprintf("%s@%s", name, version))
@@ -169,6 +218,49 @@ func runFetch(ref, output, only, contractFilter string) error {
 	return nil
 }
 
+// runFetchArchive downloads the archive of a package version into the output
+// directory as <name>-<version>.<format>.
+func runFetchArchive(ref, output, format string) error {
+	if format != "tar.gz" && format != "zip" {
+		return fmt.Errorf("--archive must be tar.gz or zip, got %q", format)
+	}
+	name, version, refContract, err := parsePackageRef(ref)
+	if err != nil {
+		return err
+	}
+	if refContract != "" {
+		return fmt.Errorf("--archive downloads a whole package; drop /%s from the reference", refContract)
+	}
+
+	c := newClient(getServer(), getAPIKey())
+	ctx := context.Background()
+
+	resolved, err := resolveVersion(ctx, c, name, version)
+	if err != nil {
+		return err
+	}
+	if resolved != version {
+		fmt.Printf("Resolved %s@%s to %s\n", name, version, resolved)
+		version = resolved
+	}
+
+	data, err := c.GetArchiveFormat(ctx, name, version, format)
+	if err != nil {
+		return fmt.Errorf("failed to download archive: %w", err)
+	}
+
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	outPath := filepath.Join(output, fmt.Sprintf("%s-%s.%s", name, version, format))
+	if err := os.WriteFile(outPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	fmt.Printf("✅ Archive saved to %s\n", outPath)
+	return nil
+}
+
 func fetchArtifact(c *client.Client, ctx context.Context, name, version, contract, artifactType, outPath string) error {
 	var content []byte
 	var err error
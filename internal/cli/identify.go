@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/internal/chains/evm"
+)
+
+func createIdentifyCmd() *cobra.Command {
+	var rpcURL string
+	var address string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "identify",
+		Short: "Find the package a deployed contract was published in",
+		Long: `Fetch a contract's deployed bytecode and look it up in the registry.
+
+The on-chain code is hashed and matched against published deployed bytecode,
+so contracts with immutables or linked libraries (whose code differs from the
+compiled artifact) will not be found.
+
+EXAMPLES:
+  contrafactory identify --rpc https://eth.example.com --address 0x1234...
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runIdentify(rpcURL, address, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL of the chain the contract is deployed on (required)")
+	cmd.Flags().StringVar(&address, "address", "", "contract address (required)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	_ = cmd.MarkFlagRequired("rpc")
+	_ = cmd.MarkFlagRequired("address")
+
+	return cmd
+}
+
+func runIdentify(rpcURL, address string, jsonOutput bool) error {
+	ctx := context.Background()
+
+	code, err := evm.NewChain().GetDeployedBytecode(ctx, rpcURL, address)
+	if errors.Is(err, evm.ErrNotContract) {
+		return fmt.Errorf("no contract code at %s", address)
+	}
+	if err != nil {
+		return fmt.Errorf("fetching bytecode: %w", err)
+	}
+
+	hash := bytecodeLookupHash(code)
+	c := newClient(getServer(), getAPIKey())
+	matches, err := c.LookupBytecode(ctx, hash)
+	if err != nil {
+		return fmt.Errorf("looking up bytecode: %w", err)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"address": address,
+			"hash":    hash,
+			"matches": matches,
+		})
+	}
+
+	if len(matches) == 0 {
+		fmt.Printf("No published contract matches the code at %s\n", address)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PACKAGE\tVERSION\tCONTRACT\tMATCHED ON")
+	for _, m := range matches {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.Package, m.Version, m.Contract, m.MatchedOn)
+	}
+	return w.Flush()
+}
+
+// bytecodeLookupHash hashes code the way the registry hashes published bytecode
+// artifacts: "sha256:" plus the sha256 of the 0x-prefixed lowercase hex string.
+func bytecodeLookupHash(code []byte) string {
+	sum := sha256.Sum256([]byte("0x" + hex.EncodeToString(code)))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunIdentify(t *testing.T) {
+	rpc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int    `json:"id"`
+			Params []any  `json:"params"`
+			Method string `json:"method"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		code := "0x6001"
+		if req.Params[0] == "0x00000000000000000000000000000000000000ee" {
+			code = "0x"
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": code})
+	}))
+	defer rpc.Close()
+
+	sum := sha256.Sum256([]byte("0x6001"))
+	wantHash := "sha256:" + hex.EncodeToString(sum[:])
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/lookup/bytecode", r.URL.Path)
+		assert.Equal(t, wantHash, r.URL.Query().Get("hash"))
+		json.NewEncoder(w).Encode(map[string]any{
+			"hash": wantHash,
+			"matches": []map[string]any{
+				{"package": "tokens", "version": "1.0.0", "contract": "Token", "chain": "evm", "matchedOn": "deployed-bytecode"},
+			},
+		})
+	}))
+	defer registry.Close()
+
+	oldServer := server
+	server = registry.URL
+	defer func() { server = oldServer }()
+
+	t.Run("match", func(t *testing.T) {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+
+		err := runIdentify(rpc.URL, "0x1234567890abcdef1234567890abcdef12345678", false)
+
+		w.Close()
+		os.Stdout = oldStdout
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		assert.Contains(t, buf.String(), "tokens")
+		assert.Contains(t, buf.String(), "deployed-bytecode")
+	})
+
+	t.Run("no code at address", func(t *testing.T) {
+		err := runIdentify(rpc.URL, "0x00000000000000000000000000000000000000ee", false)
+		assert.ErrorContains(t, err, "no contract code")
+	})
+}
@@ -4,15 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
+	"github.com/pendergraft/contrafactory/internal/chains/evm"
 	"github.com/pendergraft/contrafactory/pkg/client"
 )
 
 func createInfoCmd() *cobra.Command {
 	var jsonOutput bool
+	var deployments bool
+	var solc string
 
 	cmd := &cobra.Command{
 		Use:   "info <package>[@<version>]",
@@ -28,22 +34,33 @@ EXAMPLES:
   # Show specific version details
   contrafactory info Token@1.0.0
 
+  # Show the highest version matching a range
+  contrafactory info Token@~1.2.0
+
+  # Show where a version is deployed and whether it is verified
+  contrafactory info Token@1.0.0 --deployments
+
+  # Check the version was compiled with the same solc minor release you use
+  contrafactory info Token@1.0.0 --solc 0.8.19
+
   # Output as JSON
   contrafactory info Token@1.0.0 --json
 `,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runInfo(args[0], jsonOutput)
+			return runInfo(args[0], jsonOutput, deployments, solc)
 		},
 	}
 
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	cmd.Flags().BoolVar(&deployments, "deployments", false, "also list the version's deployments")
+	cmd.Flags().StringVar(&solc, "solc", "", "solc version you build with; warns if the version used a different major or minor release")
 
 	return cmd
 }
 
-func runInfo(ref string, jsonOutput bool) error {
-	c := client.New(getServer(), getAPIKey())
+func runInfo(ref string, jsonOutput, deployments bool, solc string) error {
+	c := newClient(getServer(), getAPIKey())
 	ctx := context.Background()
 
 	// Check if version is specified
@@ -73,12 +90,26 @@ func runInfo(ref string, jsonOutput bool) error {
 	}
 
 	if version == "" {
+		if deployments {
+			return fmt.Errorf("--deployments needs a version, e.g. %s@latest", name)
+		}
+		if solc != "" {
+			return fmt.Errorf("--solc needs a version, e.g. %s@latest", name)
+		}
 		// Show package overview
 		return showPackageInfo(c, ctx, name, jsonOutput)
 	}
 
+	resolved, err := resolveVersion(ctx, c, name, version)
+	if err != nil {
+		return err
+	}
+	if resolved != version && !jsonOutput {
+		fmt.Printf("Resolved %s@%s to %s\n\n", name, version, resolved)
+	}
+
 	// Show version details
-	return showVersionInfo(c, ctx, name, version, jsonOutput)
+	return showVersionInfo(c, ctx, name, resolved, jsonOutput, deployments, solc)
 }
 
 func showPackageInfo(c *client.Client, ctx context.Context, name string, jsonOutput bool) error {
@@ -87,10 +118,15 @@ func showPackageInfo(c *client.Client, ctx context.Context, name string, jsonOut
 		return fmt.Errorf("failed to get package: %w", err)
 	}
 
+	owner := lookupOwner(ctx, c, name)
+
 	if jsonOutput {
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
-		return enc.Encode(pkg)
+		return enc.Encode(struct {
+			*client.Package
+			Owner *client.PackageOwner `json:"owner,omitempty"`
+		}{pkg, owner})
 	}
 
 	fmt.Printf("Package: %s\n", pkg.Name)
@@ -98,6 +134,9 @@ func showPackageInfo(c *client.Client, ctx context.Context, name string, jsonOut
 	if pkg.Builder != "" {
 		fmt.Printf("Builder: %s\n", pkg.Builder)
 	}
+	if owner != nil {
+		fmt.Printf("Owner:   %s\n", owner.Owner)
+	}
 	fmt.Println()
 
 	if len(pkg.Versions) == 0 {
@@ -117,16 +156,44 @@ func showPackageInfo(c *client.Client, ctx context.Context, name string, jsonOut
 	return nil
 }
 
-func showVersionInfo(c *client.Client, ctx context.Context, name, version string, jsonOutput bool) error {
-	pkg, err := c.GetPackageVersion(ctx, name, version)
+// lookupOwner returns who owns a package, or nil when it can't be shown: there is
+// no API key configured, the package is unowned, or the key is neither the owner
+// nor an admin.
+func lookupOwner(ctx context.Context, c *client.Client, name string) *client.PackageOwner {
+	if getAPIKey() == "" {
+		return nil
+	}
+	owner, err := c.GetPackageOwner(ctx, name)
+	if err != nil {
+		return nil
+	}
+	return owner
+}
+
+func showVersionInfo(c *client.Client, ctx context.Context, name, version string, jsonOutput, withDeployments bool, solc string) error {
+	pkg, err := c.GetPackageVersionForSolc(ctx, name, version, solc)
 	if err != nil {
 		return fmt.Errorf("failed to get package version: %w", err)
 	}
 
+	var deployments []client.VersionDeployment
+	if withDeployments {
+		deployments, err = c.GetVersionDeployments(ctx, name, version)
+		if err != nil {
+			return fmt.Errorf("failed to get deployments: %w", err)
+		}
+	}
+
 	if jsonOutput {
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
-		return enc.Encode(pkg)
+		if !withDeployments {
+			return enc.Encode(pkg)
+		}
+		return enc.Encode(struct {
+			*client.Package
+			Deployments []versionDeploymentInfo `json:"deployments"`
+		}{pkg, versionDeploymentInfos(deployments)})
 	}
 
 	fmt.Printf("Package:  %s\n", pkg.Name)
@@ -141,6 +208,12 @@ func showVersionInfo(c *client.Client, ctx context.Context, name, version string
 	if pkg.CreatedAt != "" {
 		fmt.Printf("Created:  %s\n", pkg.CreatedAt)
 	}
+	if len(pkg.Tags) > 0 {
+		fmt.Printf("Tags:     %s\n", strings.Join(pkg.Tags, ", "))
+	}
+	for _, w := range pkg.Warnings {
+		fmt.Printf("Warning:  %s\n", w)
+	}
 	fmt.Println()
 
 	if len(pkg.Contracts) > 0 {
@@ -150,8 +223,53 @@ func showVersionInfo(c *client.Client, ctx context.Context, name, version string
 		}
 	}
 
+	if withDeployments {
+		fmt.Println()
+		writeVersionDeployments(os.Stdout, deployments)
+	}
+
 	fmt.Println()
 	fmt.Printf("Fetch:  contrafactory fetch %s@%s\n", name, version)
 
 	return nil
 }
+
+// versionDeploymentInfo is a deployment in info --deployments --json output: the
+// deployment plus the explorer link derived from its chain ID.
+type versionDeploymentInfo struct {
+	client.VersionDeployment
+	ExplorerURL string `json:"explorerUrl,omitempty"`
+}
+
+func versionDeploymentInfos(deployments []client.VersionDeployment) []versionDeploymentInfo {
+	infos := make([]versionDeploymentInfo, 0, len(deployments))
+	for _, d := range deployments {
+		infos = append(infos, versionDeploymentInfo{VersionDeployment: d, ExplorerURL: evm.ExplorerAddressURL(d.ChainID, d.Address)})
+	}
+	return infos
+}
+
+// writeVersionDeployments renders the deployments of a version as a table, with an
+// explorer link for chains that have a known explorer.
+func writeVersionDeployments(out io.Writer, deployments []client.VersionDeployment) {
+	if len(deployments) == 0 {
+		fmt.Fprintln(out, "Deployments: none recorded")
+		return
+	}
+
+	fmt.Fprintf(out, "Deployments (%d):\n", len(deployments))
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  CHAIN\tADDRESS\tCONTRACT\tVERIFIED\tEXPLORER")
+	for _, d := range deployments {
+		verified := "no"
+		if d.Verified {
+			verified = "yes"
+		}
+		explorerURL := evm.ExplorerAddressURL(d.ChainID, d.Address)
+		if explorerURL == "" {
+			explorerURL = "-"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", d.ChainID, truncateAddress(d.Address), d.ContractName, verified, explorerURL)
+	}
+	w.Flush()
+}
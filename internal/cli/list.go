@@ -7,10 +7,13 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/mod/semver"
 
+	"github.com/pendergraft/contrafactory/internal/chains/evm"
+	"github.com/pendergraft/contrafactory/internal/validation"
 	"github.com/pendergraft/contrafactory/pkg/client"
 )
 
@@ -18,6 +21,10 @@ func createListCmd() *cobra.Command {
 	var limit int
 	var jsonOutput bool
 	var chain string
+	var label string
+	var tag string
+	var iface string
+	var since string
 
 	cmd := &cobra.Command{
 		Use:   "list [package]",
@@ -36,34 +43,66 @@ EXAMPLES:
   # Filter by chain
   contrafactory list --chain evm
 
+  # Packages containing a contract labeled erc20
+  contrafactory list --label erc20
+
+  # Packages tagged defi by their publisher
+  contrafactory list --tag defi
+
+  # Packages containing an ERC-721 contract (detected from the ABI at publish)
+  contrafactory list --interface erc721
+
+  # Packages published since a point in time (RFC3339)
+  contrafactory list --since 2024-05-01T00:00:00Z
+
   # Output as JSON
   contrafactory list --json
 `,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c := client.New(getServer(), getAPIKey())
+			c := newClient(getServer(), getAPIKey())
 
 			if len(args) == 1 {
 				// List versions of a specific package
 				return listVersions(c, args[0], jsonOutput)
 			}
 
+			if iface != "" {
+				id, err := normalizeInterfaceID(iface)
+				if err != nil {
+					return err
+				}
+				if label != "" && label != id {
+					return fmt.Errorf("--label and --interface cannot be combined")
+				}
+				label = id
+			}
+
+			createdAfter, err := parseSince(since)
+			if err != nil {
+				return err
+			}
+
 			// List all packages
-			return listPackages(c, chain, limit, jsonOutput)
+			return listPackages(c, client.ListPackagesOptions{Label: label, Tag: tag, CreatedAfter: createdAfter}, chain, limit, jsonOutput)
 		},
 	}
 
 	cmd.Flags().IntVar(&limit, "limit", 20, "number of items to show")
 	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
 	cmd.Flags().StringVar(&chain, "chain", "", "filter by chain (evm, solana)")
+	cmd.Flags().StringVar(&label, "label", "", "only packages with a contract carrying this label (e.g. erc20)")
+	cmd.Flags().StringVar(&tag, "tag", "", "only packages with a version carrying this tag (e.g. defi)")
+	cmd.Flags().StringVar(&since, "since", "", "only packages created at or after this RFC3339 timestamp")
+	cmd.Flags().StringVar(&iface, "interface", "", "only packages with a contract implementing this interface (erc20, erc721, erc1155, erc165)")
 
 	return cmd
 }
 
-func listPackages(c *client.Client, chain string, limit int, jsonOutput bool) error {
+func listPackages(c *client.Client, opts client.ListPackagesOptions, chain string, limit int, jsonOutput bool) error {
 	ctx := context.Background()
 
-	resp, err := c.ListPackages(ctx)
+	resp, err := c.ListPackagesWithOptions(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("failed to list packages: %w", err)
 	}
@@ -153,6 +192,28 @@ func listVersions(c *client.Client, name string, jsonOutput bool) error {
 	return nil
 }
 
+// parseSince parses a --since value as an RFC3339 timestamp. Empty means no bound.
+func parseSince(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q: expected an RFC3339 timestamp such as 2024-05-01T00:00:00Z", s)
+	}
+	return t, nil
+}
+
+// normalizeInterfaceID maps user input such as "ERC-721" to the interface label
+// the server assigns at publish time.
+func normalizeInterfaceID(s string) (string, error) {
+	id := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "-", "")
+	if !evm.IsStandardInterface(id) {
+		return "", fmt.Errorf("unknown interface %q (supported: %s)", s, strings.Join(evm.StandardInterfaces(), ", "))
+	}
+	return id, nil
+}
+
 // parsePackageRef parses "package@version" or "package/contract@version"
 func parsePackageRef(ref string) (name, version, contract string, err error) {
 	// Check for @version
@@ -175,6 +236,73 @@ func parsePackageRef(ref string) (name, version, contract string, err error) {
 	return name, version, contract, nil
 }
 
+// resolveVersion turns "latest" or a semver range (^1.2.0, 1.x, >=1.0.0 <2.0.0) into the
+// highest matching published version. Exact versions are returned unchanged.
+func resolveVersion(ctx context.Context, c *client.Client, name, version string) (string, error) {
+	if version != "latest" && !validation.IsVersionRange(version) {
+		return version, nil
+	}
+
+	if version == "latest" {
+		// Same rule as the server: highest stable version, else highest prerelease
+		versions, err := c.GetVersions(ctx, name, true)
+		if err != nil {
+			return "", fmt.Errorf("listing versions of %s: %w", name, err)
+		}
+		if len(versions) == 0 {
+			return "", fmt.Errorf("package %s has no published versions", name)
+		}
+		return validation.ResolveLatest(versions, false), nil
+	}
+
+	r, err := validation.ParseVersionRange(version)
+	if err != nil {
+		return "", err
+	}
+	versions, err := c.GetVersions(ctx, name, r.IncludesPrerelease())
+	if err != nil {
+		return "", fmt.Errorf("listing versions of %s: %w", name, err)
+	}
+	best := r.MaxSatisfying(versions)
+	if best == "" {
+		return "", fmt.Errorf("no version of %s satisfies %q (available: %s)", name, version, strings.Join(versions, ", "))
+	}
+	return best, nil
+}
+
+// contractRef is a package/Contract@version reference with its version resolved
+type contractRef struct {
+	Package  string `json:"package"`
+	Version  string `json:"version"`
+	Contract string `json:"contract"`
+}
+
+func (r contractRef) String() string {
+	return fmt.Sprintf("%s/%s@%s", r.Package, r.Contract, r.Version)
+}
+
+// resolveContractRef parses a package/Contract@version reference, taking the
+// contract from contract when the reference doesn't name one, and resolves the
+// version (latest or a range) to a published one.
+func resolveContractRef(ctx context.Context, c *client.Client, ref, contract string) (contractRef, error) {
+	name, version, refContract, err := parsePackageRef(ref)
+	if err != nil {
+		return contractRef{}, err
+	}
+	if refContract != "" {
+		contract = refContract
+	}
+	if contract == "" {
+		return contractRef{}, fmt.Errorf("contract name required (use --contract or package/Contract@version)")
+	}
+
+	version, err = resolveVersion(ctx, c, name, version)
+	if err != nil {
+		return contractRef{}, err
+	}
+	return contractRef{Package: name, Version: version, Contract: contract}, nil
+}
+
 // findLatestVersion finds the highest stable version using semver comparison.
 // Returns the first version if none are valid semver.
 func findLatestVersion(versions []string) string {
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func createMineCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "mine",
+		Short: "List the packages your API key owns",
+		Long: `List every package owned by the configured API key, with its versions.
+
+Use it to audit what a key has published, e.g. before rotating it or to find
+names to deprecate.
+
+EXAMPLES:
+  contrafactory mine
+  contrafactory mine --json
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if getAPIKey() == "" {
+				return fmt.Errorf("an API key is required (set CONTRAFACTORY_API_KEY or run 'contrafactory auth login')")
+			}
+			c := newClient(getServer(), getAPIKey())
+			return runMine(context.Background(), cmd.OutOrStdout(), c, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+
+	return cmd
+}
+
+// runMine lists every page of the caller's packages.
+func runMine(ctx context.Context, out io.Writer, c *client.Client, jsonOutput bool) error {
+	var packages []client.Package
+	cursor := ""
+	for {
+		resp, err := c.ListMyPackages(ctx, 100, cursor)
+		if err != nil {
+			return fmt.Errorf("failed to list your packages: %w", err)
+		}
+		packages = append(packages, resp.Data...)
+		if !resp.Pagination.HasMore || resp.Pagination.NextCursor == "" {
+			break
+		}
+		cursor = resp.Pagination.NextCursor
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"packages": packages,
+			"count":    len(packages),
+		})
+	}
+
+	if len(packages) == 0 {
+		fmt.Fprintln(out, "You don't own any packages")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCHAIN\tLATEST\tVERSIONS")
+	for _, p := range packages {
+		latest := ""
+		if len(p.Versions) > 0 {
+			latest = findLatestVersion(p.Versions)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Name, p.Chain, latest, strings.Join(p.Versions, ", "))
+	}
+	w.Flush()
+	fmt.Fprintf(out, "\n%d package(s)\n", len(packages))
+	return nil
+}
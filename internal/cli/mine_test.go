@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func TestRunMine(t *testing.T) {
+	var cursors []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/me/packages", r.URL.Path)
+		assert.Equal(t, "my-key", r.Header.Get("X-API-Key"))
+		cursor := r.URL.Query().Get("cursor")
+		cursors = append(cursors, cursor)
+		if cursor == "" {
+			w.Write([]byte(`{"data":[{"name":"token","chain":"evm","versions":["1.0.0","1.2.0"]}],"pagination":{"hasMore":true,"nextCursor":"c1"}}`))
+		} else {
+			w.Write([]byte(`{"data":[{"name":"vault","chain":"evm","versions":["2.0.0"]}],"pagination":{"hasMore":false}}`))
+		}
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	require.NoError(t, runMine(context.Background(), &out, client.New(srv.URL, "my-key"), false))
+
+	assert.Equal(t, []string{"", "c1"}, cursors, "every page is listed")
+	assert.Regexp(t, `token\s+evm\s+1.2.0\s+1.0.0, 1.2.0`, out.String())
+	assert.Regexp(t, `vault\s+evm\s+2.0.0`, out.String())
+	assert.Contains(t, out.String(), "2 package(s)")
+}
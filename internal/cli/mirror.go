@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/internal/validation"
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func createMirrorCmd() *cobra.Command {
+	var from, to string
+	var fromKey, toKey string
+	var since string
+
+	cmd := &cobra.Command{
+		Use:   "mirror --from <url> --to <url>",
+		Short: "Copy packages from one registry to another",
+		Long: `Copy every package version from a source registry into a destination registry,
+e.g. to keep a standby registry for disaster recovery.
+
+Each version is downloaded as an archive and republished to the destination with
+the same name, version, project and metadata, and each contract keeps its compiler
+settings and labels. Versions already present in the destination are skipped, so
+mirror can be re-run to catch up.
+
+Artifacts that archives don't carry (extra artifacts such as devdoc) and recorded
+deployments are not copied.
+
+API keys default to the stored credentials for each server; publishing to the
+destination needs a key with write access.
+
+EXAMPLES:
+  # Mirror everything
+  contrafactory mirror --from https://registry.example.com --to https://standby.example.com
+
+  # Only versions published since a point in time (RFC3339)
+  contrafactory mirror --from https://registry.example.com --to https://standby.example.com --since 2024-05-01T00:00:00Z
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" || to == "" {
+				return fmt.Errorf("--from and --to are required")
+			}
+			if strings.TrimSuffix(from, "/") == strings.TrimSuffix(to, "/") {
+				return fmt.Errorf("--from and --to must be different registries")
+			}
+			createdAfter, err := parseSince(since)
+			if err != nil {
+				return err
+			}
+
+			if fromKey == "" {
+				fromKey = getCredential(from)
+			}
+			if toKey == "" {
+				toKey = getCredential(to)
+			}
+			if toKey == "" {
+				toKey = getAPIKey()
+			}
+
+			src := newClient(from, fromKey)
+			dst := newClient(to, toKey)
+			return runMirror(context.Background(), cmd.OutOrStdout(), src, dst, client.ListPackagesOptions{CreatedAfter: createdAfter})
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "source registry URL")
+	cmd.Flags().StringVar(&to, "to", "", "destination registry URL")
+	cmd.Flags().StringVar(&fromKey, "from-api-key", "", "API key for the source registry")
+	cmd.Flags().StringVar(&toKey, "to-api-key", "", "API key for the destination registry")
+	cmd.Flags().StringVar(&since, "since", "", "only versions published at or after this RFC3339 timestamp")
+
+	return cmd
+}
+
+// mirrorPageSize is the number of packages requested per page of the source listing.
+const mirrorPageSize = 100
+
+// runMirror copies the versions of every package listed by src with opts into dst.
+func runMirror(ctx context.Context, out io.Writer, src, dst *client.Client, opts client.ListPackagesOptions) error {
+	opts.Limit = mirrorPageSize
+
+	var copied, skipped, failed int
+	for {
+		page, err := src.ListPackagesWithOptions(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("listing source packages: %w", err)
+		}
+
+		for _, pkg := range page.Data {
+			// Oldest first, so the destination's publish order matches the source's
+			versions := slices.Clone(pkg.Versions)
+			slices.SortFunc(versions, validation.CompareVersions)
+
+			for _, version := range versions {
+				err := mirrorVersion(ctx, src, dst, pkg.Name, version)
+				switch {
+				case err == nil:
+					fmt.Fprintf(out, "   OK %s@%s\n", pkg.Name, version)
+					copied++
+				case client.IsVersionExists(err):
+					fmt.Fprintf(out, "   - %s@%s: already present\n", pkg.Name, version)
+					skipped++
+				default:
+					fmt.Fprintf(out, "   X %s@%s: %v\n", pkg.Name, version, err)
+					failed++
+				}
+			}
+		}
+
+		if !page.Pagination.HasMore || page.Pagination.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.Pagination.NextCursor
+	}
+
+	fmt.Fprintf(out, "\nMirrored %d version(s), %d already present, %d failed\n", copied, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d version(s) failed to mirror", failed)
+	}
+	return nil
+}
+
+// mirrorVersion republishes one version of src into dst.
+func mirrorVersion(ctx context.Context, src, dst *client.Client, name, version string) error {
+	data, err := src.GetArchive(ctx, name, version)
+	if err != nil {
+		return fmt.Errorf("downloading archive: %w", err)
+	}
+	req, err := publishRequestFromArchive(data)
+	if err != nil {
+		return fmt.Errorf("reading archive: %w", err)
+	}
+
+	// Archives hold the artifacts; the rest comes from the source's API
+	pkg, err := src.GetPackageVersion(ctx, name, version)
+	if err != nil {
+		return fmt.Errorf("getting package: %w", err)
+	}
+	req.Project = pkg.Project
+	if len(pkg.Metadata) > 0 {
+		req.Metadata = make(map[string]string, len(pkg.Metadata))
+		for k, v := range pkg.Metadata {
+			req.Metadata[k] = fmt.Sprint(v)
+		}
+	}
+	for i := range req.Artifacts {
+		contract, err := src.GetContract(ctx, name, version, req.Artifacts[i].Name)
+		if err != nil {
+			return fmt.Errorf("getting contract %s: %w", req.Artifacts[i].Name, err)
+		}
+		req.Artifacts[i].Compiler = contract.Compiler
+		req.Artifacts[i].Labels = contract.Labels
+		req.Artifacts[i].Metadata = contract.Metadata
+	}
+
+	return dst.Publish(ctx, name, version, *req)
+}
+
+// archiveManifest is manifest.json of a package archive.
+type archiveManifest struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Chain     string `json:"chain"`
+	Builder   string `json:"builder"`
+	Contracts []struct {
+		Name       string `json:"name"`
+		SourcePath string `json:"sourcePath"`
+	} `json:"contracts"`
+}
+
+// readArchive reads the files of a tar.gz package archive (GET .../archive), keyed by
+// their path without the leading <name>-<version>/ directory, and parses its manifest.
+func readArchive(data []byte) (map[string][]byte, *archiveManifest, error) {
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer gr.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		_, rel, ok := strings.Cut(hdr.Name, "/")
+		if !ok {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, nil, err
+		}
+		files[rel] = content
+	}
+
+	manifestData, ok := files["manifest.json"]
+	if !ok {
+		return nil, nil, fmt.Errorf("missing manifest.json")
+	}
+	var manifest archiveManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("parsing manifest.json: %w", err)
+	}
+	return files, &manifest, nil
+}
+
+// publishRequestFromArchive rebuilds the publish request of a package version from
+// its archive (GET .../archive): <name>-<version>/manifest.json plus one directory of
+// artifact files per contract.
+func publishRequestFromArchive(data []byte) (*client.PublishRequest, error) {
+	files, manifest, err := readArchive(data)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &client.PublishRequest{
+		Chain:     manifest.Chain,
+		Builder:   manifest.Builder,
+		Artifacts: make([]client.Artifact, 0, len(manifest.Contracts)),
+	}
+	for _, c := range manifest.Contracts {
+		dir := c.Name + "/"
+		artifact := client.Artifact{
+			Name:              c.Name,
+			SourcePath:        c.SourcePath,
+			ABI:               files[dir+"abi.json"],
+			Bytecode:          string(files[dir+"bytecode.hex"]),
+			DeployedBytecode:  string(files[dir+"deployed-bytecode.hex"]),
+			StandardJSONInput: files[dir+"standard-json-input.json"],
+			StorageLayout:     files[dir+"storage-layout.json"],
+			IDL:               files[dir+"idl.json"],
+			Program:           files[dir+c.Name+".so"],
+		}
+
+		// Signatures were verified by the source; the destination checks them again
+		if data, ok := files[dir+"signature.json"]; ok {
+			var sig client.Signature
+			if err := json.Unmarshal(data, &sig); err != nil {
+				return nil, fmt.Errorf("parsing %ssignature.json: %w", dir, err)
+			}
+			artifact.Signature = sig.Signature
+			artifact.PublicKey = sig.PublicKey
+		}
+
+		// Sources are stored as an object of source path to content
+		sources := make(map[string]string)
+		for p, content := range files {
+			if source, ok := strings.CutPrefix(p, dir+"sources/"); ok {
+				sources[source] = string(content)
+			}
+		}
+		if len(sources) > 0 {
+			encoded, err := json.Marshal(sources)
+			if err != nil {
+				return nil, err
+			}
+			artifact.Extra = map[string]json.RawMessage{"sources": encoded}
+		}
+
+		req.Artifacts = append(req.Artifacts, artifact)
+	}
+	return req, nil
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+// testArchive builds a package archive laid out like the server's GET .../archive.
+func testArchive(t *testing.T, name, version string, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for p, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name + "-" + version + "/" + p, Mode: 0644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func TestPublishRequestFromArchive(t *testing.T) {
+	data := testArchive(t, "token", "1.0.0", map[string]string{
+		"manifest.json":                       `{"name":"token","version":"1.0.0","chain":"evm","builder":"foundry","contracts":[{"name":"Token","sourcePath":"src/Token.sol"}]}`,
+		"Token/abi.json":                      `[{"type":"constructor","inputs":[]}]`,
+		"Token/bytecode.hex":                  "0x6080",
+		"Token/deployed-bytecode.hex":         "0x6081",
+		"Token/standard-json-input.json":      `{"language":"Solidity"}`,
+		"Token/sources/src/Token.sol":         "contract Token {}",
+		"Token/sources/lib/oz/ERC20.sol":      "contract ERC20 {}",
+		"Token/storage-layout.json":           `{"storage":[]}`,
+		"Token/signature.json":                `{"algorithm":"ed25519","publicKey":"cHVi","signature":"c2ln","digest":"sha256:00"}`,
+		"Token/unrelated-file-for-the-future": "ignored",
+	})
+
+	req, err := publishRequestFromArchive(data)
+	require.NoError(t, err)
+	assert.Equal(t, "evm", req.Chain)
+	assert.Equal(t, "foundry", req.Builder)
+	require.Len(t, req.Artifacts, 1)
+
+	a := req.Artifacts[0]
+	assert.Equal(t, "Token", a.Name)
+	assert.Equal(t, "src/Token.sol", a.SourcePath)
+	assert.JSONEq(t, `[{"type":"constructor","inputs":[]}]`, string(a.ABI))
+	assert.Equal(t, "0x6080", a.Bytecode)
+	assert.Equal(t, "0x6081", a.DeployedBytecode)
+	assert.JSONEq(t, `{"language":"Solidity"}`, string(a.StandardJSONInput))
+	assert.JSONEq(t, `{"storage":[]}`, string(a.StorageLayout))
+	assert.JSONEq(t, `{"src/Token.sol":"contract Token {}","lib/oz/ERC20.sol":"contract ERC20 {}"}`, string(a.Extra["sources"]))
+	assert.Nil(t, a.Program)
+	assert.Equal(t, "c2ln", a.Signature)
+	assert.Equal(t, "cHVi", a.PublicKey)
+
+	_, err = publishRequestFromArchive(testArchive(t, "token", "1.0.0", map[string]string{"Token/abi.json": "[]"}))
+	assert.ErrorContains(t, err, "manifest.json")
+}
+
+func TestRunMirror(t *testing.T) {
+	archives := map[string][]byte{
+		"token@1.0.0": testArchive(t, "token", "1.0.0", map[string]string{
+			"manifest.json":  `{"name":"token","version":"1.0.0","chain":"evm","builder":"foundry","contracts":[{"name":"Token","sourcePath":"src/Token.sol"}]}`,
+			"Token/abi.json": `[]`,
+		}),
+		"token@1.1.0": testArchive(t, "token", "1.1.0", map[string]string{
+			"manifest.json":  `{"name":"token","version":"1.1.0","chain":"evm","builder":"foundry","contracts":[{"name":"Token","sourcePath":"src/Token.sol"}]}`,
+			"Token/abi.json": `[]`,
+		}),
+		"vault@2.0.0": testArchive(t, "vault", "2.0.0", map[string]string{
+			"manifest.json":  `{"name":"vault","version":"2.0.0","chain":"evm","builder":"foundry","contracts":[{"name":"Vault","sourcePath":"src/Vault.sol"}]}`,
+			"Vault/abi.json": `[]`,
+		}),
+	}
+
+	var cursors []string
+	src := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/packages"), "/")
+		switch {
+		case len(parts) == 1:
+			// Two pages, one package each
+			cursors = append(cursors, r.URL.Query().Get("cursor"))
+			if r.URL.Query().Get("cursor") == "" {
+				w.Write([]byte(`{"data":[{"name":"token","versions":["1.1.0","1.0.0"]}],"pagination":{"hasMore":true,"nextCursor":"c1"}}`))
+			} else {
+				w.Write([]byte(`{"data":[{"name":"vault","versions":["2.0.0"]}],"pagination":{"hasMore":false}}`))
+			}
+		case len(parts) == 4 && parts[3] == "archive":
+			w.Write(archives[parts[1]+"@"+parts[2]])
+		case len(parts) == 3:
+			w.Write([]byte(`{"name":"` + parts[1] + `","version":"` + parts[2] + `","project":"defi","metadata":{"commit":"abc123"}}`))
+		case len(parts) == 5 && parts[3] == "contracts":
+			w.Write([]byte(`{"name":"` + parts[4] + `","compiler":{"version":"0.8.28","viaIR":true},"labels":["erc20"],"metadata":{"audit":"passed"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer src.Close()
+
+	published := make(map[string]client.PublishRequest)
+	var order []string
+	dst := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "POST", r.Method)
+		assert.Equal(t, "dst-key", r.Header.Get("X-API-Key"))
+		ref := strings.Join(strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/packages/"), "/"), "@")
+		if ref == "vault@2.0.0" {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":{"code":"VERSION_EXISTS","message":"Version already exists and is immutable"}}`))
+			return
+		}
+		var req client.PublishRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		published[ref] = req
+		order = append(order, ref)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	}))
+	defer dst.Close()
+
+	var out bytes.Buffer
+	err := runMirror(context.Background(), &out, client.New(src.URL, ""), client.New(dst.URL, "dst-key"), client.ListPackagesOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"", "c1"}, cursors, "every page is listed")
+	assert.Equal(t, []string{"token@1.0.0", "token@1.1.0"}, order, "versions are mirrored oldest first")
+
+	req := published["token@1.0.0"]
+	assert.Equal(t, "defi", req.Project)
+	assert.Equal(t, map[string]string{"commit": "abc123"}, req.Metadata)
+	require.Len(t, req.Artifacts, 1)
+	assert.Equal(t, "0.8.28", req.Artifacts[0].Compiler.Version)
+	assert.True(t, req.Artifacts[0].Compiler.ViaIR)
+	assert.Equal(t, []string{"erc20"}, req.Artifacts[0].Labels)
+	assert.Equal(t, map[string]string{"audit": "passed"}, req.Artifacts[0].Metadata)
+
+	assert.Contains(t, out.String(), "vault@2.0.0: already present")
+	assert.Contains(t, out.String(), "Mirrored 2 version(s), 1 already present, 0 failed")
+}
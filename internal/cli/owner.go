@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func createOwnerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "owner",
+		Short: "Package ownership commands",
+		Long: `Show or transfer package ownership and manage collaborators.
+
+A package name belongs to the API key that first published it. The owner and any
+collaborator keys it adds can publish new versions and delete versions; only the
+owner can manage collaborators or hand the name to another key.`,
+	}
+
+	cmd.AddCommand(createOwnerShowCmd())
+	cmd.AddCommand(createOwnerTransferCmd())
+	cmd.AddCommand(createOwnerCollaboratorsCmd())
+	cmd.AddCommand(createOwnerAddCollaboratorCmd())
+	cmd.AddCommand(createOwnerRemoveCollaboratorCmd())
+
+	return cmd
+}
+
+func createOwnerShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <package>",
+		Short: "Show which API key owns a package",
+		Long: `Show the name of the API key that owns a package.
+
+Only the owning key and admin keys can see ownership.
+
+EXAMPLES:
+  contrafactory owner show my-token
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(getServer(), getAPIKey())
+			owner, err := c.GetPackageOwner(context.Background(), args[0])
+			if err != nil {
+				return fmt.Errorf("failed to get owner: %w", err)
+			}
+			printOwner(cmd.OutOrStdout(), owner)
+			return nil
+		},
+	}
+}
+
+func createOwnerTransferCmd() *cobra.Command {
+	var to string
+
+	cmd := &cobra.Command{
+		Use:   "transfer <package>",
+		Short: "Transfer a package to another API key",
+		Long: `Hand a package to another API key, e.g. when a CI key is rotated.
+
+Must be run with the current owner's key. The target is given by key ID
+(see 'contrafactory-server keys list --full-ids') and must not be revoked.
+
+EXAMPLES:
+  contrafactory owner transfer my-token --to 6f1c2a9e-0b7d-4c55-9a43-2f0e8d1b7c3a
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(getServer(), getAPIKey())
+			owner, err := c.TransferPackageOwner(context.Background(), args[0], to)
+			if err != nil {
+				return fmt.Errorf("failed to transfer ownership: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "✅ Transferred %s\n", args[0])
+			printOwner(cmd.OutOrStdout(), owner)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "ID of the API key to transfer to (required)")
+	_ = cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func createOwnerCollaboratorsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "collaborators <package>",
+		Short: "List the collaborator keys of a package",
+		Long: `List the API keys that may publish to and delete from a package besides
+its owner.
+
+Only the owner, its collaborators and admin keys can see collaborators.
+
+EXAMPLES:
+  contrafactory owner collaborators my-token
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(getServer(), getAPIKey())
+			resp, err := c.ListPackageCollaborators(context.Background(), args[0])
+			if err != nil {
+				return fmt.Errorf("failed to list collaborators: %w", err)
+			}
+			printCollaborators(cmd.OutOrStdout(), resp)
+			return nil
+		},
+	}
+}
+
+func createOwnerAddCollaboratorCmd() *cobra.Command {
+	var key string
+
+	cmd := &cobra.Command{
+		Use:   "add-collaborator <package>",
+		Short: "Let another API key publish to a package",
+		Long: `Add an API key as a collaborator on a package, e.g. a second CI pipeline.
+
+Collaborators can publish new versions and delete versions, but cannot manage
+collaborators or transfer the package. Must be run with the owner's key. The
+collaborator is given by key ID (see 'contrafactory-server keys list --full-ids')
+and must not be revoked.
+
+EXAMPLES:
+  contrafactory owner add-collaborator my-token --key 6f1c2a9e-0b7d-4c55-9a43-2f0e8d1b7c3a
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(getServer(), getAPIKey())
+			resp, err := c.AddPackageCollaborator(context.Background(), args[0], key)
+			if err != nil {
+				return fmt.Errorf("failed to add collaborator: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "✅ Added %s as a collaborator on %s\n", key, args[0])
+			printCollaborators(cmd.OutOrStdout(), resp)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&key, "key", "", "ID of the API key to add (required)")
+	_ = cmd.MarkFlagRequired("key")
+
+	return cmd
+}
+
+func createOwnerRemoveCollaboratorCmd() *cobra.Command {
+	var key string
+
+	cmd := &cobra.Command{
+		Use:   "remove-collaborator <package>",
+		Short: "Revoke a collaborator's access to a package",
+		Long: `Remove an API key from the collaborators of a package. Must be run with the
+owner's key.
+
+EXAMPLES:
+  contrafactory owner remove-collaborator my-token --key 6f1c2a9e-0b7d-4c55-9a43-2f0e8d1b7c3a
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(getServer(), getAPIKey())
+			resp, err := c.RemovePackageCollaborator(context.Background(), args[0], key)
+			if err != nil {
+				return fmt.Errorf("failed to remove collaborator: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "✅ Removed %s from %s\n", key, args[0])
+			printCollaborators(cmd.OutOrStdout(), resp)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&key, "key", "", "ID of the API key to remove (required)")
+	_ = cmd.MarkFlagRequired("key")
+
+	return cmd
+}
+
+func printCollaborators(out io.Writer, resp *client.PackageCollaborators) {
+	if len(resp.Collaborators) == 0 {
+		fmt.Fprintf(out, "%s has no collaborators\n", resp.Name)
+		return
+	}
+	fmt.Fprintf(out, "Collaborators of %s:\n", resp.Name)
+	for _, c := range resp.Collaborators {
+		fmt.Fprintf(out, "  %-38s %s", c.KeyID, c.Name)
+		if c.Since != "" {
+			fmt.Fprintf(out, "  (since %s)", c.Since)
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+func printOwner(out io.Writer, owner *client.PackageOwner) {
+	fmt.Fprintf(out, "Package: %s\n", owner.Name)
+	fmt.Fprintf(out, "Owner:   %s\n", owner.Owner)
+	if owner.Since != "" {
+		fmt.Fprintf(out, "Since:   %s\n", owner.Since)
+	}
+}
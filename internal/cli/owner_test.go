@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnerTransferCommand(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/packages/my-token/transfer-owner", r.URL.Path)
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body["toKeyId"] != "key-2" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"INVALID_REQUEST","message":"invalid owner key: key key-9 does not exist or is revoked"}}`))
+			return
+		}
+		w.Write([]byte(`{"name":"my-token","owner":"release-2025","since":"2025-06-15T14:30:45Z"}`))
+	}))
+	defer srv.Close()
+
+	origServer, origKey := server, apiKey
+	defer func() { server, apiKey = origServer, origKey }()
+	server, apiKey = srv.URL, "owner-key"
+
+	t.Run("transfers", func(t *testing.T) {
+		cmd := createOwnerCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"transfer", "my-token", "--to", "key-2"})
+		require.NoError(t, cmd.Execute())
+		assert.Contains(t, out.String(), "Transferred my-token")
+		assert.Contains(t, out.String(), "Owner:   release-2025")
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		cmd := createOwnerCmd()
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs([]string{"transfer", "my-token", "--to", "key-9"})
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not exist or is revoked")
+	})
+
+	t.Run("requires --to", func(t *testing.T) {
+		cmd := createOwnerCmd()
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs([]string{"transfer", "my-token"})
+		assert.Error(t, cmd.Execute())
+	})
+}
+
+func TestOwnerCollaboratorCommands(t *testing.T) {
+	collaborators := []map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/packages/my-token/collaborators":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			collaborators = append(collaborators, map[string]string{"keyId": body["keyId"], "name": "ci-deployer"})
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/packages/my-token/collaborators/key-2":
+			collaborators = collaborators[:0]
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/packages/my-token/collaborators":
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"name": "my-token", "collaborators": collaborators})
+	}))
+	defer srv.Close()
+
+	origServer, origKey := server, apiKey
+	defer func() { server, apiKey = origServer, origKey }()
+	server, apiKey = srv.URL, "owner-key"
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := createOwnerCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+		return out.String()
+	}
+
+	out := run("add-collaborator", "my-token", "--key", "key-2")
+	assert.Contains(t, out, "Added key-2 as a collaborator on my-token")
+	assert.Regexp(t, `key-2\s+ci-deployer`, out)
+
+	assert.Regexp(t, `key-2\s+ci-deployer`, run("collaborators", "my-token"))
+
+	out = run("remove-collaborator", "my-token", "--key", "key-2")
+	assert.Contains(t, out, "Removed key-2 from my-token")
+	assert.Contains(t, out, "my-token has no collaborators")
+}
@@ -1,23 +1,28 @@
 package cli
 
 import (
-	"bytes"
+	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
+	"maps"
 	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"unicode"
 
 	"github.com/spf13/cobra"
 
 	"github.com/pendergraft/contrafactory/internal/chains"
+	"github.com/pendergraft/contrafactory/internal/chains/evm"
 	"github.com/pendergraft/contrafactory/internal/chains/evm/foundry"
+	"github.com/pendergraft/contrafactory/internal/chains/solana"
+	"github.com/pendergraft/contrafactory/pkg/client"
 )
 
 // PublishRequest matches the server's expected format
@@ -27,6 +32,7 @@ type PublishRequest struct {
 	Project   string            `json:"project,omitempty"`
 	Artifacts []PublishArtifact `json:"artifacts"`
 	Metadata  map[string]string `json:"metadata,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
 }
 
 // PublishArtifact represents a contract artifact to publish
@@ -38,6 +44,19 @@ type PublishArtifact struct {
 	DeployedBytecode  string          `json:"deployedBytecode,omitempty"`
 	StandardJSONInput json.RawMessage `json:"standardJsonInput,omitempty"`
 	Compiler          *CompilerInfo   `json:"compiler,omitempty"`
+	IDL               json.RawMessage `json:"idl,omitempty"`     // Solana: Anchor IDL
+	Program           []byte          `json:"program,omitempty"` // Solana: program .so (base64 in JSON)
+	Labels            []string        `json:"labels,omitempty"`
+
+	// Contract-level metadata, with --contract-metadata
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Extra artifacts stored by type (e.g. "devdoc", "method-identifiers")
+	Extra map[string]json.RawMessage `json:"extra,omitempty"`
+
+	// Publisher signature over the ABI and bytecode, with --sign-key
+	Signature string `json:"signature,omitempty"`
+	PublicKey string `json:"publicKey,omitempty"`
 }
 
 // CompilerInfo is compiler metadata for verification
@@ -63,6 +82,23 @@ var defaultExcludePatterns = []string{
 	"Setup",  // *Setup test helpers
 }
 
+// resolveExcludePatterns returns the contract name patterns to exclude: the built-in
+// defaults (or the config's exclude, which replaces them), plus the config's
+// default_exclude, plus the --exclude flag. noDefaults drops the built-ins.
+func resolveExcludePatterns(flagPatterns []string, noDefaults bool, config *ProjectConfig) []string {
+	var patterns []string
+	switch {
+	case config != nil && len(config.Exclude) > 0:
+		patterns = append(patterns, config.Exclude...)
+	case !noDefaults:
+		patterns = append(patterns, defaultExcludePatterns...)
+	}
+	if config != nil {
+		patterns = append(patterns, config.DefaultExclude...)
+	}
+	return append(patterns, flagPatterns...)
+}
+
 // DiscoveredPackage is a package discovered by the project's discovery logic
 type DiscoveredPackage struct {
 	Name     string
@@ -72,21 +108,36 @@ type DiscoveredPackage struct {
 
 // discoverPackages discovers packages using the same logic as publish.
 // Returns package names and artifact paths. Used by both publish and delete.
-func discoverPackages(cwd, prefix string, contracts, exclude, excludePaths, includeDeps []string) ([]DiscoveredPackage, error) {
-	builder := foundry.New()
+// With noVerify, a missing build-info directory is a warning instead of an error.
+func discoverPackages(cwd, prefix string, contracts, exclude, excludePaths, excludeKinds, includeDeps []string, noVerify bool) ([]DiscoveredPackage, error) {
+	if isAnchorProject(cwd) {
+		return discoverAnchorPackages(cwd, prefix, contracts, exclude)
+	}
+
+	builder := newFoundryBuilder()
 	detected, err := builder.Detect(cwd)
 	if err != nil {
 		return nil, fmt.Errorf("detecting builder: %w", err)
 	}
 	if !detected {
-		return nil, fmt.Errorf("no Foundry project detected (missing foundry.toml) - currently only Foundry projects are supported")
+		return nil, fmt.Errorf("no Foundry or Anchor project detected (missing foundry.toml or Anchor.toml)")
+	}
+
+	warnBuildStaleness(builder, cwd)
+
+	if noVerify {
+		if _, err := os.Stat(builder.BuildInfoDir(cwd)); os.IsNotExist(err) {
+			fmt.Fprintln(os.Stderr, "Warning: build-info directory not found; publishing without verification artifacts")
+		}
 	}
 
 	discoverOpts := chains.DiscoverOptions{
-		Contracts:           contracts,
-		Exclude:             exclude,
-		ExcludePaths:        excludePaths,
-		IncludeDependencies: includeDeps,
+		Contracts:             contracts,
+		Exclude:               exclude,
+		ExcludePaths:          excludePaths,
+		ExcludeKinds:          excludeKinds,
+		IncludeDependencies:   includeDeps,
+		AllowMissingBuildInfo: noVerify,
 	}
 
 	artifactPaths, err := builder.Discover(cwd, discoverOpts)
@@ -108,6 +159,7 @@ func discoverPackages(cwd, prefix string, contracts, exclude, excludePaths, incl
 	}
 
 	var packages []DiscoveredPackage
+	sources := make(map[string]string) // package name -> source:contract publishing as it
 	for _, path := range artifactPaths {
 		artifact, err := builder.Parse(path)
 		if err != nil {
@@ -127,6 +179,13 @@ func discoverPackages(cwd, prefix string, contracts, exclude, excludePaths, incl
 			packageName = prefix + "-" + packageName
 		}
 
+		// Same-named contracts in different files would publish over each other
+		source := artifact.EVM.SourcePath + ":" + artifact.Name
+		if other, ok := sources[packageName]; ok {
+			return nil, fmt.Errorf("%s and %s would both publish as package %s; pick one with --contracts %s, or leave one out with exclude_paths or --exclude-path", other, source, packageName, source)
+		}
+		sources[packageName] = source
+
 		packages = append(packages, DiscoveredPackage{Name: packageName, Path: path, Artifact: artifact})
 	}
 
@@ -141,12 +200,33 @@ func createPublishCmd() *cobra.Command {
 	var version string
 	var contracts []string
 	var exclude []string
+	var noDefaultExclude bool
 	var excludePaths []string
+	var excludeKinds []string
 	var includeDeps []string
 	var prefix string
 	var project string
+	var chain string
 	var dryRun bool
 	var metadata []string
+	var tags []string
+	var contractMetadata []string
+	var name string
+	var fromStdin bool
+	var noVerify bool
+	var standardJSON []string
+	var checkMetadata bool
+	var concurrency int
+	var summaryOut string
+	var includeSources bool
+	var batch bool
+	var bestEffort bool
+	var skipExisting bool
+	var signKeyPath string
+	var onlyChanged bool
+	var base string
+	var maxArtifacts int
+	var fromStandardJSON standardJSONPublishOptions
 
 	cmd := &cobra.Command{
 		Use:   "publish",
@@ -159,6 +239,8 @@ and verification artifacts. Package names default to the contract name.
 REQUIREMENTS:
   Run 'forge build --build-info' before publishing to generate the
   Standard JSON Input needed for block explorer verification.
+  Use --no-verify to publish only ABI and bytecode when build-info is
+  unavailable; such packages cannot be verified later.
 
 EXAMPLES:
   # Publish all contracts (one package per contract)
@@ -172,40 +254,183 @@ EXAMPLES:
   # Publish specific contracts only
   contrafactory publish --version 1.0.0 --contracts Token,Registry
 
+  # Skip abstract contracts and libraries (needs 'forge build --ast' for abstract)
+  contrafactory publish --version 1.0.0 --exclude-kind abstract,library
+
   # Publish with dependency contracts from lib/
   contrafactory publish --version 1.0.0 --include-deps TransparentUpgradeableProxy,ProxyAdmin
 
   # Publish with metadata
   contrafactory publish --version 1.0.0 --metadata audit_status=passed --metadata auditor="Trail of Bits"
 
+  # Tag the packages for discovery (see: contrafactory list --tag defi)
+  contrafactory publish --version 1.0.0 --tag defi --tag erc20
+
+  # Attach metadata to a single contract
+  contrafactory publish --version 1.0.0 --contract-metadata Token:audit=passed
+
+  # Set the chain packages are published for (default: chain in contrafactory.toml,
+  # else the project's own). To publish the same artifacts for several chains, run
+  # publish once per chain with a different --prefix
+  contrafactory publish --version 1.0.0 --chain evm
+
   # Dry run (show what would be published)
   contrafactory publish --version 1.0.0 --dry-run
+
+  # Publish only contracts whose source file changed since the merge-base with the
+  # default branch, or since a given revision. Contracts are matched by their own
+  # file, so publish everything when a shared import changes
+  contrafactory publish --version 1.1.0 --only-changed
+  contrafactory publish --version 1.1.0 --only-changed --base v1.0.0
+
+  # Write per-package results as JSON for later CI steps
+  contrafactory publish --version 1.0.0 --summary-out publish-summary.json
+
+  # Publish up to 8 packages in parallel (default 4)
+  contrafactory publish --version 1.0.0 --concurrency 8
+
+  # Re-run a partially failed publish, skipping versions already in the registry
+  contrafactory publish --version 1.0.0 --skip-existing
+
+  # Refuse to publish if discovery finds more than 50 contracts (guards against
+  # a misconfigured exclude publishing test or dependency contracts)
+  contrafactory publish --version 1.0.0 --max-artifacts 50
+
+  # Publish every package in one request; if any fails, none are published
+  contrafactory publish --version 1.0.0 --batch
+
+  # One request, but keep the packages that succeed
+  contrafactory publish --version 1.0.0 --batch --best-effort
+
+  # Quick publish without build-info (ABI and bytecode only)
+  contrafactory publish --version 1.0.0 --no-verify
+
+  # Use a hand-made Standard JSON Input for one contract (when generation doesn't verify)
+  contrafactory publish --version 1.0.0 --standard-json Token=./token.standard.json
+
+  # Also store each contract's Solidity sources (browsable under sources/ in the archive)
+  contrafactory publish --version 1.0.0 --include-sources
+
+  # Check that the Standard JSON Input reproduces the bytecode's metadata hash
+  contrafactory publish --version 1.0.0 --check-metadata --dry-run
+
+  # Sign each contract's ABI and bytecode so consumers can check provenance
+  # (key: openssl genpkey -algorithm ed25519 -out key.pem)
+  contrafactory publish --version 1.0.0 --sign-key key.pem
+
+  # Publish a PublishRequest (or single artifact) JSON generated elsewhere
+  generate-payload | contrafactory publish --version 1.0.0 --name my-pkg --stdin
+
+  # Publish one contract built elsewhere from its Standard JSON Input, ABI and bytecode
+  contrafactory publish --version 1.0.0 --name Foo --from-standard-json input.json \
+    --abi Foo.abi.json --bytecode Foo.bin --compiler-version 0.8.28+commit.7893614a
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runPublish(version, prefix, project, contracts, exclude, excludePaths, includeDeps, dryRun, metadata)
+			if concurrency < 1 {
+				return fmt.Errorf("--concurrency must be at least 1")
+			}
+			if maxArtifacts < 0 {
+				return fmt.Errorf("--max-artifacts must not be negative")
+			}
+			signKey, err := loadSignKey(signKeyPath)
+			if err != nil {
+				return err
+			}
+			if fromStdin {
+				if fromStandardJSON.InputPath != "" {
+					return fmt.Errorf("--stdin cannot be used with --from-standard-json")
+				}
+				return runPublishStdin(cmd.InOrStdin(), name, version, project, chain, dryRun, metadata, tags, contractMetadata, signKey, maxArtifacts)
+			}
+			if fromStandardJSON.InputPath != "" {
+				fromStandardJSON.Name = name
+				return runPublishStandardJSON(fromStandardJSON, version, project, chain, dryRun, metadata, tags, contractMetadata, signKey, maxArtifacts)
+			}
+			if includeSources && noVerify {
+				return fmt.Errorf("--include-sources cannot be used with --no-verify")
+			}
+			batchMode := ""
+			if batch {
+				batchMode = batchModeAtomic
+				if bestEffort {
+					batchMode = batchModeBestEffort
+				}
+			} else if bestEffort {
+				return fmt.Errorf("--best-effort requires --batch")
+			}
+			if batch && skipExisting {
+				return fmt.Errorf("--skip-existing cannot be used with --batch")
+			}
+			if base != "" && !onlyChanged {
+				return fmt.Errorf("--base requires --only-changed")
+			}
+			var changed *changedSources
+			if onlyChanged {
+				changed, err = changedSolidityFiles(".", base)
+				if errors.Is(err, errNotGitRepo) {
+					fmt.Fprintln(os.Stderr, "Warning: --only-changed: not in a git repository; publishing all contracts")
+				} else if err != nil {
+					return fmt.Errorf("--only-changed: %w", err)
+				}
+			}
+			return runPublish(version, prefix, project, chain, contracts, exclude, noDefaultExclude, excludePaths, excludeKinds, includeDeps, dryRun, noVerify, checkMetadata, includeSources, skipExisting, concurrency, metadata, tags, contractMetadata, standardJSON, summaryOut, batchMode, signKey, changed, maxArtifacts)
 		},
 	}
 
 	cmd.Flags().StringVarP(&version, "version", "v", "", "version to publish (required)")
-	cmd.Flags().StringSliceVar(&contracts, "contracts", nil, "specific contracts to publish (default: all from src/)")
-	cmd.Flags().StringSliceVar(&exclude, "exclude", nil, "patterns to exclude by contract name (e.g., Test,Mock) - replaces config defaults")
+	cmd.Flags().StringSliceVar(&contracts, "contracts", nil, "specific contracts to publish, by name or as source:name (default: all from src/)")
+	cmd.Flags().StringSliceVar(&exclude, "exclude", nil, "more patterns to exclude by contract name (e.g., Helper,Fixture), added to the defaults")
+	cmd.Flags().BoolVar(&noDefaultExclude, "no-default-exclude", false, "don't exclude the built-in patterns (Test, Script, Mock, Deploy, Setup)")
 	cmd.Flags().StringSliceVar(&excludePaths, "exclude-path", nil, "patterns to exclude by source path (e.g., proxy, examples/MetaCoin.sol)")
+	cmd.Flags().StringSliceVar(&excludeKinds, "exclude-kind", nil, "contract kinds to exclude: abstract, library, interface, contract")
 	cmd.Flags().StringSliceVar(&includeDeps, "include-deps", nil, "dependency contracts to publish from lib/")
 	cmd.Flags().StringVarP(&prefix, "prefix", "p", "", "prefix for package names (e.g., 'myproject' creates 'myproject-Token')")
 	cmd.Flags().StringVar(&project, "project", "", "project scope (overrides contrafactory.toml)")
+	cmd.Flags().StringVar(&chain, "chain", "", "chain to publish for, e.g. evm or solana (overrides contrafactory.toml)")
 	cmd.Flags().StringSliceVar(&metadata, "metadata", nil, "package metadata as key=value pairs (repeatable)")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "tag the packages for discovery, e.g. defi or erc20 (repeatable)")
+	cmd.Flags().StringArrayVar(&contractMetadata, "contract-metadata", nil, "contract metadata as Contract:key=value (repeatable)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be published without publishing")
+	cmd.Flags().StringVar(&name, "name", "", "package name (required with --stdin and --from-standard-json)")
+	cmd.Flags().BoolVar(&fromStdin, "stdin", false, "read a PublishRequest or single artifact as JSON from stdin (skips discovery)")
+	cmd.Flags().StringVar(&fromStandardJSON.InputPath, "from-standard-json", "", "publish one contract from this Standard JSON Input file with --abi and --bytecode (skips discovery)")
+	cmd.Flags().StringVar(&fromStandardJSON.ABIPath, "abi", "", "ABI file, with --from-standard-json")
+	cmd.Flags().StringVar(&fromStandardJSON.Bytecode, "bytecode", "", "creation bytecode as 0x-prefixed hex or a file containing it, with --from-standard-json")
+	cmd.Flags().StringVar(&fromStandardJSON.Contract, "contract", "", "contract name, with --from-standard-json (default: --name)")
+	cmd.Flags().StringVar(&fromStandardJSON.CompilerVersion, "compiler-version", "", "full solc version the input was compiled with, with --from-standard-json")
+	cmd.Flags().BoolVar(&noVerify, "no-verify", false, "publish ABI and bytecode only, without build-info or Standard JSON Input")
+	cmd.Flags().IntVar(&concurrency, "concurrency", defaultPublishConcurrency, "number of packages to publish in parallel")
+	cmd.Flags().BoolVar(&checkMetadata, "check-metadata", false, "check each Standard JSON Input against the metadata hash in the bytecode (no compilation)")
+	cmd.Flags().StringArrayVar(&standardJSON, "standard-json", nil, "use a Standard JSON Input file verbatim for a contract as Contract=path (repeatable)")
+	cmd.Flags().StringVar(&summaryOut, "summary-out", "", "write a JSON summary of each package's publish status to this file")
+	cmd.Flags().BoolVar(&batch, "batch", false, "publish all packages in a single all-or-nothing request")
+	cmd.Flags().BoolVar(&bestEffort, "best-effort", false, "with --batch, keep the packages that publish even if others fail")
+	cmd.Flags().BoolVar(&skipExisting, "skip-existing", false, "skip packages whose version is already in the registry instead of failing on them")
+	cmd.Flags().StringVar(&signKeyPath, "sign-key", "", "sign each artifact's ABI and bytecode with this ed25519 private key (PEM)")
+	cmd.Flags().BoolVar(&onlyChanged, "only-changed", false, "publish only contracts whose .sol file changed in git since --base")
+	cmd.Flags().StringVar(&base, "base", "", "git revision --only-changed compares HEAD with (default: merge-base with the default branch)")
+	cmd.Flags().IntVar(&maxArtifacts, "max-artifacts", 0, "refuse to publish more than this many artifacts in total (0: no limit besides the server's)")
+	cmd.Flags().BoolVar(&includeSources, "include-sources", false, "also store Solidity sources as a 'sources' artifact (default: sources only inside the Standard JSON Input)")
 	_ = cmd.MarkFlagRequired("version")
 
 	return cmd
 }
 
-func runPublish(version, prefix, projectFlag string, contracts, exclude, excludePaths, includeDeps []string, dryRun bool, metadataPairs []string) error {
+func runPublish(version, prefix, projectFlag, chainFlag string, contracts, exclude []string, noDefaultExclude bool, excludePaths, excludeKinds, includeDeps []string, dryRun, noVerify, checkMetadata, includeSources, skipExisting bool, concurrency int, metadataPairs, tags, contractMetadataPairs, standardJSONPairs []string, summaryOut, batchMode string, signKey ed25519.PrivateKey, changed *changedSources, maxArtifacts int) error {
 	// Parse metadata key=value pairs
 	metadata, err := parseMetadata(metadataPairs)
 	if err != nil {
 		return fmt.Errorf("parsing metadata: %w", err)
 	}
+	contractMetadata, err := parseContractMetadata(contractMetadataPairs)
+	if err != nil {
+		return err
+	}
+
+	stdJSONOverrides, err := parseStandardJSONOverrides(standardJSONPairs)
+	if err != nil {
+		return err
+	}
 
 	// Get current directory
 	cwd, err := os.Getwd()
@@ -221,13 +446,7 @@ func runPublish(version, prefix, projectFlag string, contracts, exclude, exclude
 		contracts = projectConfig.Contracts
 	}
 
-	// Resolve exclude: CLI flag > config > hardcoded defaults
-	excludePatterns := defaultExcludePatterns
-	if len(exclude) > 0 {
-		excludePatterns = exclude
-	} else if projectConfig != nil && len(projectConfig.Exclude) > 0 {
-		excludePatterns = projectConfig.Exclude
-	}
+	excludePatterns := resolveExcludePatterns(exclude, noDefaultExclude, projectConfig)
 
 	// Resolve exclude_paths: CLI flag > config
 	excludePathPatterns := excludePaths
@@ -235,18 +454,49 @@ func runPublish(version, prefix, projectFlag string, contracts, exclude, exclude
 		excludePathPatterns = projectConfig.ExcludePaths
 	}
 
+	// Resolve exclude_kinds: CLI flag > config
+	if len(excludeKinds) == 0 && projectConfig != nil {
+		excludeKinds = projectConfig.ExcludeKinds
+	}
+	if err := validateContractKinds(excludeKinds); err != nil {
+		return err
+	}
+
 	// Resolve include_dependencies: CLI flag > config
 	if len(includeDeps) == 0 && projectConfig != nil {
 		includeDeps = projectConfig.IncludeDependencies
 	}
 
 	// Discover packages (same logic used by delete)
-	discovered, err := discoverPackages(cwd, prefix, contracts, excludePatterns, excludePathPatterns, includeDeps)
+	discovered, err := discoverPackages(cwd, prefix, contracts, excludePatterns, excludePathPatterns, excludeKinds, includeDeps, noVerify)
+	if err != nil {
+		return err
+	}
+
+	if isAnchorProject(cwd) {
+		if batchMode != "" {
+			return fmt.Errorf("--batch is not supported for Anchor projects")
+		}
+		if changed != nil {
+			return fmt.Errorf("--only-changed is not supported for Anchor projects")
+		}
+		project := projectFlag
+		if project == "" && projectConfig != nil {
+			project = projectConfig.Project
+		}
+		chain, err := resolvePublishChain(chainFlag, projectConfig, "solana", "anchor")
+		if err != nil {
+			return err
+		}
+		return runPublishAnchor(cwd, discovered, version, project, chain, dryRun, skipExisting, concurrency, metadata, tags, contractMetadata, projectConfig, summaryOut, signKey, maxArtifacts)
+	}
+
+	chain, err := resolvePublishChain(chainFlag, projectConfig, "evm", "foundry")
 	if err != nil {
 		return err
 	}
 
-	builder := foundry.New()
+	builder := newFoundryBuilder()
 	fmt.Printf("Detected Foundry project in %s\n", cwd)
 
 	// Count src vs dependency contracts for output
@@ -283,41 +533,45 @@ func runPublish(version, prefix, projectFlag string, contracts, exclude, exclude
 			continue
 		}
 
-		pa := PublishArtifact{
-			Name:             artifact.Name,
-			SourcePath:       artifact.EVM.SourcePath,
-			ABI:              artifact.EVM.ABI,
-			Bytecode:         artifact.EVM.Bytecode,
-			DeployedBytecode: artifact.EVM.DeployedBytecode,
+		// Unchanged contracts also consume their overrides, which aren't errors then
+		if changed != nil && !changed.includes(artifact.EVM.SourcePath) {
+			delete(stdJSONOverrides, artifact.Name)
+			takeContractMetadata(contractMetadata, artifact.Name, nil)
+			fmt.Printf("  = %s unchanged since %s, skipped\n", artifact.Name, shortRev(changed.base))
+			continue
 		}
 
-		// Compiler info: prefer the full version (with +commit.xxx) from whichever source has it.
-		// Artifact metadata (rawMetadata) has the full version from Solidity; build-info may have short "0.8.28".
-		compilerVersion := artifact.EVM.Compiler.Version
-		if vi, err := builder.GetVerificationInput(cwd, artifact.Name, artifact.EVM.SourcePath); err == nil && vi.SolcLongVersion != "" {
-			// Use build-info if it has full version; else keep artifact's if it has full; else use build-info
-			if strings.Contains(vi.SolcLongVersion, "+commit.") {
-				compilerVersion = vi.SolcLongVersion
-			} else if !strings.Contains(compilerVersion, "+commit.") {
-				compilerVersion = vi.SolcLongVersion
+		var stdJSON json.RawMessage
+		if path, ok := stdJSONOverrides[artifact.Name]; ok {
+			stdJSON, err = loadStandardJSONOverride(path, artifact.EVM.SourcePath)
+			if err != nil {
+				return fmt.Errorf("--standard-json %s: %w", artifact.Name, err)
 			}
-		}
-		pa.Compiler = &CompilerInfo{
-			Version:    compilerVersion,
-			EVMVersion: artifact.EVM.Compiler.EVMVersion,
-			ViaIR:      artifact.EVM.Compiler.ViaIR,
-			Optimizer: &OptimizerInfo{
-				Enabled: artifact.EVM.Compiler.Optimizer.Enabled,
-				Runs:    artifact.EVM.Compiler.Optimizer.Runs,
-			},
+			delete(stdJSONOverrides, artifact.Name)
+			fmt.Printf("  Using Standard JSON Input for %s from %s\n", artifact.Name, path)
 		}
 
-		// Prefer per-contract minimal standard JSON (matches bytecode metadata hash); fallback to build-info
-		if stdJSON, err := builder.GeneratePerContractStandardJSON(cwd, pkg.Path); err == nil {
-			pa.StandardJSONInput = stdJSON
-		} else if vi, err := builder.GetVerificationInput(cwd, artifact.Name, artifact.EVM.SourcePath); err == nil {
-			fmt.Printf("  Warning: could not generate per-contract standard JSON for %s (%v), using build-info\n", artifact.Name, err)
-			pa.StandardJSONInput = vi.StandardJSON
+		pa := buildEVMPublishArtifact(builder, cwd, pkg, noVerify, stdJSON)
+		if checkMetadata && pa.StandardJSONInput != nil {
+			for _, w := range metadataWarnings(builder, pkg.Path, artifact.Name, pa.StandardJSONInput) {
+				fmt.Printf("  Warning: %s\n", w)
+			}
+		}
+		if includeSources {
+			sources, err := sourcesFromStandardJSON(pa.StandardJSONInput)
+			if err != nil {
+				return fmt.Errorf("--include-sources %s: %w", artifact.Name, err)
+			}
+			pa.Extra = map[string]json.RawMessage{"sources": sources}
+		}
+		if projectConfig != nil {
+			pa.Labels = projectConfig.Labels[artifact.Name]
+		}
+		pa.Metadata = takeContractMetadata(contractMetadata, artifact.Name, nil)
+		if signKey != nil {
+			if err := signArtifact(signKey, &pa); err != nil {
+				return err
+			}
 		}
 
 		isDep := !strings.HasPrefix(artifact.EVM.SourcePath, "src/")
@@ -335,17 +589,52 @@ func runPublish(version, prefix, projectFlag string, contracts, exclude, exclude
 		}
 	}
 
+	if len(stdJSONOverrides) > 0 {
+		unknown := slices.Sorted(maps.Keys(stdJSONOverrides))
+		return fmt.Errorf("--standard-json: %s not among the contracts being published", strings.Join(unknown, ", "))
+	}
+	if err := checkContractMetadataUsed(contractMetadata); err != nil {
+		return err
+	}
+
 	// Resolve project: CLI flag > config
 	project := projectFlag
 	if project == "" && projectConfig != nil {
 		project = projectConfig.Project
 	}
 
+	serverURL := getServer()
+	summary := &publishSummary{Server: serverURL, Version: version, DryRun: dryRun, Packages: make([]publishSummaryEntry, len(packages))}
+	for i, pkg := range packages {
+		summary.Packages[i] = publishSummaryEntry{
+			Name:       pkg.name,
+			Version:    version,
+			Contract:   pkg.artifact.Name,
+			SourcePath: pkg.sourcePath,
+			Dependency: pkg.isDep,
+			Status:     summaryStatusDryRun,
+		}
+	}
+
+	if len(packages) == 0 && changed != nil {
+		fmt.Printf("\nNo contracts changed since %s; nothing to publish\n", shortRev(changed.base))
+		return writePublishSummary(summaryOut, summary)
+	}
+
 	if dryRun {
-		fmt.Printf("\nDRY RUN - Would publish %d package(s) to %s\n", len(packages), getServer())
+		fmt.Printf("\nDRY RUN - Would publish %d package(s) to %s\n", len(packages), serverURL)
 		if project != "" {
 			fmt.Printf("  Project: %s\n", project)
 		}
+		if noVerify {
+			fmt.Println("  Verification artifacts: skipped (--no-verify)")
+		}
+		if includeSources {
+			fmt.Println("  Sources: included (--include-sources)")
+		}
+		if signKey != nil {
+			fmt.Println("  Signatures: included (--sign-key)")
+		}
 		for _, pkg := range packages {
 			if pkg.isDep {
 				fmt.Printf("   - %s@%s [dependency]\n", pkg.name, version)
@@ -353,26 +642,58 @@ func runPublish(version, prefix, projectFlag string, contracts, exclude, exclude
 				fmt.Printf("   - %s@%s\n", pkg.name, version)
 			}
 		}
-		return nil
+		return writePublishSummary(summaryOut, summary)
 	}
 
-	// Publish each contract as its own package
-	serverURL := getServer()
-	fmt.Printf("\nPublishing %d package(s) to %s...\n", len(packages), serverURL)
+	// Each contract is its own package, so every request carries one artifact
+	if err := checkArtifactLimit(serverURL, len(packages), 1, maxArtifacts); err != nil {
+		return err
+	}
 
-	var successCount, failCount int
-	for _, pkg := range packages {
-		err := publishPackage(serverURL, pkg.name, version, project, pkg.artifact, metadata)
+	// Publish each contract as its own package
+	var successCount, failCount, existingCount int
+	if batchMode != "" {
+		fmt.Printf("\nPublishing %d package(s) to %s in one %s batch...\n", len(packages), serverURL, batchMode)
+		items := make([]publishBatchItem, len(packages))
+		for i, pkg := range packages {
+			items[i] = publishBatchItem{Name: pkg.name, Version: version, Request: evmPublishRequest(chain, project, pkg.artifact, metadata, tags)}
+		}
+		successCount, failCount, err = publishBatch(serverURL, items, batchMode, summary)
 		if err != nil {
-			fmt.Printf("   X %s@%s: %v\n", pkg.name, version, err)
-			failCount++
-		} else {
-			fmt.Printf("   OK %s@%s\n", pkg.name, version)
-			successCount++
+			return fmt.Errorf("batch publish failed: %w", err)
 		}
+	} else {
+		fmt.Printf("\nPublishing %d package(s) to %s...\n", len(packages), serverURL)
+		publishConcurrently(len(packages), concurrency, func(i int) error {
+			pkg := packages[i]
+			return publishUnlessExists(serverURL, pkg.name, version, skipExisting, func() error {
+				return publishPackage(serverURL, pkg.name, version, chain, project, pkg.artifact, metadata, tags)
+			})
+		}, func(i int, err error) {
+			summary.record(i, err)
+			switch {
+			case errors.Is(err, errVersionExists):
+				fmt.Printf("   - %s@%s: already published, skipped\n", packages[i].name, version)
+				existingCount++
+			case err != nil:
+				fmt.Printf("   X %s@%s: %v\n", packages[i].name, version, err)
+				failCount++
+			default:
+				fmt.Printf("   OK %s@%s\n", packages[i].name, version)
+				successCount++
+			}
+		})
+	}
+
+	// Written before reporting failures so CI sees per-package status either way
+	if err := writePublishSummary(summaryOut, summary); err != nil {
+		return err
 	}
 
 	fmt.Println()
+	if existingCount > 0 {
+		fmt.Printf("Skipped %d package(s) already in the registry\n", existingCount)
+	}
 	if failCount > 0 {
 		return fmt.Errorf("published %d package(s), %d failed", successCount, failCount)
 	}
@@ -385,7 +706,182 @@ func runPublish(version, prefix, projectFlag string, contracts, exclude, exclude
 	return nil
 }
 
+// buildEVMPublishArtifact assembles the publish payload for a discovered Foundry contract.
+// With noVerify, build-info is not consulted and no Standard JSON Input is attached.
+// A non-nil standardJSON (from --standard-json) is used verbatim instead of generating one.
+func buildEVMPublishArtifact(builder *foundry.Builder, cwd string, pkg DiscoveredPackage, noVerify bool, standardJSON json.RawMessage) PublishArtifact {
+	artifact := pkg.Artifact
+	pa := PublishArtifact{
+		Name:             artifact.Name,
+		SourcePath:       artifact.EVM.SourcePath,
+		ABI:              artifact.EVM.ABI,
+		Bytecode:         artifact.EVM.Bytecode,
+		DeployedBytecode: artifact.EVM.DeployedBytecode,
+	}
+
+	var buildInfo *chains.VerificationInput
+	if !noVerify {
+		if vi, err := builder.GetVerificationInput(cwd, artifact.Name, artifact.EVM.SourcePath); err == nil {
+			buildInfo = vi
+		}
+	}
+
+	// Compiler info: prefer the full version (with +commit.xxx) from whichever source has it.
+	// Artifact metadata (rawMetadata) has the full version from Solidity; build-info may have short "0.8.28".
+	compilerVersion := artifact.EVM.Compiler.Version
+	if buildInfo != nil && buildInfo.SolcLongVersion != "" {
+		// Use build-info if it has full version; else keep artifact's if it has full; else use build-info
+		if strings.Contains(buildInfo.SolcLongVersion, "+commit.") {
+			compilerVersion = buildInfo.SolcLongVersion
+		} else if !strings.Contains(compilerVersion, "+commit.") {
+			compilerVersion = buildInfo.SolcLongVersion
+		}
+	}
+	pa.Compiler = &CompilerInfo{
+		Version:    compilerVersion,
+		EVMVersion: artifact.EVM.Compiler.EVMVersion,
+		ViaIR:      artifact.EVM.Compiler.ViaIR,
+		Optimizer: &OptimizerInfo{
+			Enabled: artifact.EVM.Compiler.Optimizer.Enabled,
+			Runs:    artifact.EVM.Compiler.Optimizer.Runs,
+		},
+	}
+
+	source := "--standard-json"
+	switch {
+	case standardJSON != nil:
+		pa.StandardJSONInput = standardJSON
+	case noVerify:
+		return pa
+	default:
+		// Prefer per-contract minimal standard JSON (matches bytecode metadata hash); fallback to build-info
+		if stdJSON, err := builder.GeneratePerContractStandardJSON(cwd, pkg.Path); err == nil {
+			pa.StandardJSONInput = stdJSON
+			source = "per-contract standard JSON"
+		} else if buildInfo != nil {
+			fmt.Printf("  Warning: could not generate per-contract standard JSON for %s (%v), using build-info\n", artifact.Name, err)
+			pa.StandardJSONInput = buildInfo.StandardJSON
+			source = "build-info"
+		}
+	}
+
+	for _, w := range compilerSettingsWarnings(artifact.Name, source, pa.StandardJSONInput, artifact.EVM.Compiler, buildInfo) {
+		fmt.Printf("  Warning: %s\n", w)
+	}
+	return pa
+}
+
+// sourcesFromStandardJSON extracts the source files of a Standard JSON Input as a
+// {"path": "content"} object, the format of the "sources" artifact.
+func sourcesFromStandardJSON(stdJSON []byte) (json.RawMessage, error) {
+	if stdJSON == nil {
+		return nil, fmt.Errorf("no Standard JSON Input to take sources from (run 'forge build --build-info')")
+	}
+	var input struct {
+		Sources map[string]struct {
+			Content *string `json:"content"`
+		} `json:"sources"`
+	}
+	if err := json.Unmarshal(stdJSON, &input); err != nil {
+		return nil, fmt.Errorf("parsing Standard JSON Input: %w", err)
+	}
+	if len(input.Sources) == 0 {
+		return nil, fmt.Errorf("standard JSON Input has no sources")
+	}
+
+	sources := make(map[string]string, len(input.Sources))
+	for path, src := range input.Sources {
+		if src.Content == nil {
+			return nil, fmt.Errorf("source %s has no content (only urls)", path)
+		}
+		sources[path] = *src.Content
+	}
+	return json.Marshal(sources)
+}
+
+// compilerSettingsWarnings cross-checks optimizer, evmVersion and viaIR between the Standard
+// JSON Input about to be published (from source) and the artifact metadata and build-info.
+// A divergence usually means verification will fail on the explorer even if it works locally.
+func compilerSettingsWarnings(contract, source string, stdJSON []byte, compiler chains.EVMCompiler, buildInfo *chains.VerificationInput) []string {
+	if stdJSON == nil {
+		return nil
+	}
+	used, err := foundry.SettingsFromStandardJSON(stdJSON)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: cannot read compiler settings from %s: %v", contract, source, err)}
+	}
+
+	type reference struct {
+		name     string
+		settings foundry.CompilerSettings
+	}
+	references := []reference{{"artifact metadata", foundry.SettingsFromCompiler(compiler)}}
+	if buildInfo != nil && source != "build-info" {
+		if s, err := foundry.SettingsFromStandardJSON(buildInfo.StandardJSON); err == nil {
+			references = append(references, reference{"build-info", s})
+		}
+	}
+
+	var warnings []string
+	for _, ref := range references {
+		if diffs := foundry.DiffSettings(ref.settings, used); len(diffs) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s: compiler settings differ between %s and %s (%s); publishing %s",
+				contract, ref.name, source, strings.Join(diffs, ", "), source))
+		}
+	}
+	return warnings
+}
+
+// metadataWarnings reports the ways stdJSON cannot reproduce the metadata hash embedded
+// in the artifact's deployed bytecode, with guidance when there are any.
+func metadataWarnings(builder *foundry.Builder, artifactPath, contract string, stdJSON []byte) []string {
+	rawMetadata, deployed, err := builder.RawMetadata(artifactPath)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: metadata check skipped: %v", contract, err)}
+	}
+	problems, err := evm.MetadataMismatches(stdJSON, rawMetadata, []byte(deployed))
+	if err != nil {
+		return []string{fmt.Sprintf("%s: metadata check skipped: %v", contract, err)}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	warnings := make([]string, 0, len(problems)+1)
+	for _, p := range problems {
+		warnings = append(warnings, fmt.Sprintf("%s: %s", contract, p))
+	}
+	warnings = append(warnings, fmt.Sprintf(
+		"%s: explorer verification will at best be a partial match - run 'forge build' to refresh out/, or pass --standard-json %s=<file> with the exact compiler input",
+		contract, contract))
+	return warnings
+}
+
+// newFoundryBuilder returns the Foundry builder for the --foundry-profile profile.
+func newFoundryBuilder() *foundry.Builder {
+	return &foundry.Builder{Profile: foundryProfile}
+}
+
+// warnBuildStaleness prints a warning when out/ may not reflect the current foundry.toml.
+// The check is advisory, so failures to run it are ignored.
+func warnBuildStaleness(builder *foundry.Builder, cwd string) {
+	warnings, _ := builder.CheckStaleness(cwd)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+}
+
 // validateDependencies checks that all requested dependencies were found
+// validateContractKinds rejects kinds other than chains.ContractKinds.
+func validateContractKinds(kinds []string) error {
+	for _, k := range kinds {
+		if !slices.Contains(chains.ContractKinds, strings.ToLower(k)) {
+			return fmt.Errorf("invalid contract kind %q (expected one of: %s)", k, strings.Join(chains.ContractKinds, ", "))
+		}
+	}
+	return nil
+}
+
 func validateDependencies(builder *foundry.Builder, cwd string, requestedDeps []string, foundPaths []string) error {
 	// Build a set of found contract names
 	found := make(map[string]bool)
@@ -474,87 +970,250 @@ func findSuggestions(requested string, available []chains.DependencyInfo) []chai
 	return suggestions
 }
 
-// normalizePackageName converts a contract name to a valid package name.
-// PascalCase/camelCase is converted to lowercase-with-hyphens.
-// Example: PredicateRegistry -> predicate-registry
+// normalizePackageName converts a contract name to a valid package name:
+// lowercase words joined by hyphens. Words are split as follows:
+//   - before an uppercase letter that follows a lowercase one: PredicateRegistry -> predicate-registry
+//   - before the last capital of an acronym followed by a lowercase word: JSONParser -> json-parser
+//   - digits stay attached to the preceding word, and an uppercase letter after them starts a
+//     new word unless it ends the name: ERC20Token -> erc20-token, V2Router -> v2-router,
+//     ERC20USDC -> erc20-usdc, ERC721A -> erc721a
+//   - a trailing all-caps run stays together: PriceFeedETHUSD -> price-feed-ethusd
+//   - underscores and other non-alphanumeric characters become hyphens
+//
+// Repeated hyphens are collapsed and leading/trailing hyphens trimmed.
 func normalizePackageName(name string) string {
-	// Insert hyphens before uppercase letters (except at start)
+	runes := []rune(name)
 	var result strings.Builder
-	for i, r := range name {
-		if i > 0 && unicode.IsUpper(r) {
-			// Check if previous char was lowercase or next char is lowercase
-			// This handles cases like "ERC20" -> "erc20" not "e-r-c-20"
-			prev := rune(name[i-1])
-			if unicode.IsLower(prev) {
-				result.WriteRune('-')
-			} else if i+1 < len(name) && unicode.IsLower(rune(name[i+1])) {
-				result.WriteRune('-')
-			}
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			result.WriteRune('-')
+			continue
+		}
+		if i > 0 && unicode.IsUpper(r) && startsWord(runes, i) {
+			result.WriteRune('-')
 		}
 		result.WriteRune(unicode.ToLower(r))
 	}
 
-	// Replace any remaining invalid characters
-	normalized := result.String()
+	normalized := repeatedHyphensRegex.ReplaceAllString(result.String(), "-")
+	return strings.Trim(normalized, "-")
+}
+
+var repeatedHyphensRegex = regexp.MustCompile(`-+`)
+
+// startsWord reports whether the uppercase letter at runes[i] begins a new word.
+func startsWord(runes []rune, i int) bool {
+	prev := runes[i-1]
+	switch {
+	case unicode.IsLower(prev):
+		return true
+	case unicode.IsUpper(prev):
+		return i+1 < len(runes) && unicode.IsLower(runes[i+1])
+	case unicode.IsDigit(prev):
+		return i+1 < len(runes) && unicode.IsLetter(runes[i+1])
+	default:
+		return false
+	}
+}
+
+// defaultPublishConcurrency is the default number of parallel publish requests.
+const defaultPublishConcurrency = 4
+
+// publishTransport is shared by publish requests so parallel workers reuse connections.
+var publishTransport = newPublishTransport()
 
-	// Replace underscores with hyphens
-	normalized = strings.ReplaceAll(normalized, "_", "-")
+// publishHTTPClient returns the HTTP client for publish requests: --timeout, else
+// publishTimeout, over the shared publishTransport.
+func publishHTTPClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout(publishTimeout), Transport: publishTransport}
+}
 
-	// Remove consecutive hyphens
-	re := regexp.MustCompile(`-+`)
-	normalized = re.ReplaceAllString(normalized, "-")
+func newPublishTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConnsPerHost = 16 // default of 2 forces reconnects with parallel workers
+	return t
+}
 
-	// Trim leading/trailing hyphens
-	normalized = strings.Trim(normalized, "-")
+// publishConcurrently runs publish for indexes 0..n-1 on up to concurrency workers.
+// report is called from the calling goroutine for each result in index order, as soon as
+// all earlier results are in, so output stays deterministic. A failed publish does not
+// stop the others.
+func publishConcurrently(n, concurrency int, publish func(i int) error, report func(i int, err error)) {
+	type result struct {
+		i   int
+		err error
+	}
 
-	return normalized
+	jobs := make(chan int)
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for w := 0; w < min(max(concurrency, 1), n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results <- result{i, publish(i)}
+			}
+		}()
+	}
+	go func() {
+		for i := 0; i < n; i++ {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]error)
+	next := 0
+	for r := range results {
+		pending[r.i] = r.err
+		for {
+			err, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			report(next, err)
+			next++
+		}
+	}
+}
+
+// errVersionExists is returned by publishUnlessExists for a version already in the
+// registry, which is reported as skipped rather than failed.
+var errVersionExists = errors.New("version already published")
+
+// publishUnlessExists runs publish, unless skipExisting is set and packageName@version
+// is already in the registry, in which case it returns errVersionExists. This makes a
+// re-run after a partial failure publish only what is missing.
+func publishUnlessExists(serverURL, packageName, version string, skipExisting bool, publish func() error) error {
+	if skipExisting {
+		_, err := newClient(serverURL, getAPIKey()).GetPackageVersion(context.Background(), packageName, version)
+		switch {
+		case err == nil:
+			return errVersionExists
+		case !client.IsNotFound(err):
+			return fmt.Errorf("checking for an existing version: %w", err)
+		}
+	}
+	return publish()
 }
 
 // publishPackage publishes a single contract as its own package
-func publishPackage(serverURL, packageName, version, project string, artifact PublishArtifact, metadata map[string]string) error {
-	req := PublishRequest{
-		Chain:     "evm",
+func publishPackage(serverURL, packageName, version, chain, project string, artifact PublishArtifact, metadata map[string]string, tags []string) error {
+	return sendPublishRequest(serverURL, packageName, version, evmPublishRequest(chain, project, artifact, metadata, tags))
+}
+
+// evmPublishRequest wraps a Foundry contract's artifact in its own package's publish request.
+func evmPublishRequest(chain, project string, artifact PublishArtifact, metadata map[string]string, tags []string) PublishRequest {
+	return PublishRequest{
+		Chain:     chain,
 		Builder:   "foundry",
 		Project:   project,
 		Artifacts: []PublishArtifact{artifact},
 		Metadata:  metadata,
+		Tags:      tags,
 	}
+}
+
+// publishChains returns the registry of chains packages can be published for.
+func publishChains() *chains.Registry {
+	registry := chains.NewRegistry()
+	registry.Register(evm.NewChain())
+	registry.Register(solana.NewChain())
+	return registry
+}
+
+// resolvePublishChain picks the chain to publish for: --chain, else the project
+// config's chain, else fallback (the project's own chain). See validatePublishChain.
+func resolvePublishChain(chainFlag string, config *ProjectConfig, fallback, builder string) (string, error) {
+	chain := chainFlag
+	if chain == "" && config != nil {
+		chain = config.Chain
+	}
+	if chain == "" {
+		chain = fallback
+	}
+	return chain, validatePublishChain(chain, builder)
+}
+
+// validatePublishChain checks that chain is registered and, when builder is set,
+// that its artifacts can be published for it.
+func validatePublishChain(chain, builder string) error {
+	registry := publishChains()
+	c, ok := registry.Get(chain)
+	if !ok {
+		var names []string
+		for _, c := range registry.List() {
+			names = append(names, c.Name())
+		}
+		slices.Sort(names)
+		return fmt.Errorf("unknown chain %q (expected one of: %s)", chain, strings.Join(names, ", "))
+	}
+	if builder == "" {
+		return nil
+	}
+	for _, b := range c.Builders() {
+		if b.Name() == builder {
+			return nil
+		}
+	}
+	return fmt.Errorf("chain %q does not take %s artifacts (set --chain or chain in contrafactory.toml)", chain, builder)
+}
 
+// sendPublishRequest POSTs a publish request for a single package version
+func sendPublishRequest(serverURL, packageName, version string, req PublishRequest) error {
 	reqBody, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("marshaling request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/api/v1/packages/%s/%s", serverURL, url.PathEscape(packageName), url.PathEscape(version))
-	httpReq, err := http.NewRequest("POST", url, bytes.NewReader(reqBody))
-	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
-	}
+	c := client.New(serverURL, getAPIKey(), client.WithHTTPClient(publishHTTPClient()))
+	return c.PublishJSON(context.Background(), packageName, version, reqBody)
+}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	if key := getAPIKey(); key != "" {
-		httpReq.Header.Set("X-API-Key", key)
+// parseStandardJSONOverrides parses Contract=path pairs from --standard-json
+func parseStandardJSONOverrides(pairs []string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	for _, pair := range pairs {
+		name, path, ok := strings.Cut(pair, "=")
+		name, path = strings.TrimSpace(name), strings.TrimSpace(path)
+		if !ok || name == "" || path == "" {
+			return nil, fmt.Errorf("invalid --standard-json value %q (expected Contract=path)", pair)
+		}
+		if _, dup := overrides[name]; dup {
+			return nil, fmt.Errorf("--standard-json given twice for contract %q", name)
+		}
+		overrides[name] = path
 	}
+	return overrides, nil
+}
 
-	resp, err := http.DefaultClient.Do(httpReq)
+// loadStandardJSONOverride reads a user-supplied Standard JSON Input and checks that it
+// parses and includes the contract's source file.
+func loadStandardJSONOverride(path, sourcePath string) (json.RawMessage, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("HTTP request failed: %w", err)
+		return nil, fmt.Errorf("reading file: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
 
-	if resp.StatusCode != http.StatusCreated {
-		var errResp map[string]any
-		if json.Unmarshal(body, &errResp) == nil {
-			if errObj, ok := errResp["error"].(map[string]any); ok {
-				return fmt.Errorf("%s - %s", errObj["code"], errObj["message"])
-			}
+	var input struct {
+		Sources map[string]json.RawMessage `json:"sources"`
+	}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(input.Sources) == 0 {
+		return nil, fmt.Errorf("%s has no sources (not a Standard JSON Input?)", path)
+	}
+	if sourcePath != "" {
+		if _, ok := input.Sources[sourcePath]; !ok {
+			return nil, fmt.Errorf("%s does not contain the contract source %s", path, sourcePath)
 		}
-		return fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
 	}
-
-	return nil
+	return data, nil
 }
 
 // parseMetadata parses key=value pairs into a map
@@ -574,3 +1233,51 @@ func parseMetadata(pairs []string) (map[string]string, error) {
 	}
 	return metadata, nil
 }
+
+// parseContractMetadata parses Contract:key=value pairs from --contract-metadata into
+// metadata per contract name.
+func parseContractMetadata(pairs []string) (map[string]map[string]string, error) {
+	byContract := make(map[string]map[string]string)
+	for _, pair := range pairs {
+		name, kv, ok := strings.Cut(pair, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --contract-metadata value %q (expected Contract:key=value)", pair)
+		}
+		metadata, err := parseMetadata([]string{kv})
+		if err != nil {
+			return nil, fmt.Errorf("--contract-metadata %s: %w", name, err)
+		}
+		if byContract[name] == nil {
+			byContract[name] = make(map[string]string)
+		}
+		maps.Copy(byContract[name], metadata)
+	}
+	return byContract, nil
+}
+
+// takeContractMetadata returns the --contract-metadata for contract merged over
+// existing, removing it from contractMetadata so leftovers can be reported.
+func takeContractMetadata(contractMetadata map[string]map[string]string, contract string, existing map[string]string) map[string]string {
+	metadata, ok := contractMetadata[contract]
+	if !ok {
+		return existing
+	}
+	delete(contractMetadata, contract)
+	if len(existing) == 0 {
+		return metadata
+	}
+	merged := maps.Clone(existing)
+	maps.Copy(merged, metadata)
+	return merged
+}
+
+// checkContractMetadataUsed reports --contract-metadata naming contracts that aren't
+// being published.
+func checkContractMetadataUsed(contractMetadata map[string]map[string]string) error {
+	if len(contractMetadata) == 0 {
+		return nil
+	}
+	unknown := slices.Sorted(maps.Keys(contractMetadata))
+	return fmt.Errorf("--contract-metadata: %s not among the contracts being published", strings.Join(unknown, ", "))
+}
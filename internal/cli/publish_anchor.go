@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/pendergraft/contrafactory/internal/chains"
+	"github.com/pendergraft/contrafactory/internal/chains/solana/anchor"
+)
+
+// isAnchorProject reports whether dir is an Anchor (Solana) project.
+// Foundry wins when both config files are present.
+func isAnchorProject(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, "foundry.toml")); err == nil {
+		return false
+	}
+	detected, err := anchor.New().Detect(dir)
+	return err == nil && detected
+}
+
+// discoverAnchorPackages discovers built Solana programs, one package per program.
+func discoverAnchorPackages(cwd, prefix string, programs, exclude []string) ([]DiscoveredPackage, error) {
+	builder := anchor.New()
+
+	paths, err := builder.Discover(cwd, chains.DiscoverOptions{
+		Contracts: programs,
+		Exclude:   exclude,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("discovering programs: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no program binaries found\n\nMake sure you've run 'anchor build' (expected target/deploy/*.so)")
+	}
+
+	var packages []DiscoveredPackage
+	for _, path := range paths {
+		artifact, err := builder.Parse(path)
+		if err != nil {
+			fmt.Printf("Warning: skipping %s: %v\n", filepath.Base(path), err)
+			continue
+		}
+
+		packageName := normalizePackageName(artifact.Name)
+		if prefix != "" {
+			packageName = prefix + "-" + packageName
+		}
+		packages = append(packages, DiscoveredPackage{Name: packageName, Path: path, Artifact: artifact})
+	}
+
+	if len(packages) == 0 {
+		return nil, fmt.Errorf("no publishable programs found")
+	}
+	return packages, nil
+}
+
+// runPublishAnchor publishes each Anchor program (binary + IDL) as its own package.
+func runPublishAnchor(cwd string, discovered []DiscoveredPackage, version, project, chain string, dryRun, skipExisting bool, concurrency int, metadata map[string]string, tags []string, contractMetadata map[string]map[string]string, projectConfig *ProjectConfig, summaryOut string, signKey ed25519.PrivateKey, maxArtifacts int) error {
+	fmt.Printf("Detected Anchor project in %s\n", cwd)
+	fmt.Printf("Found %d program(s) in target/deploy/\n", len(discovered))
+
+	type packageToPublish struct {
+		name     string
+		artifact PublishArtifact
+	}
+	var packages []packageToPublish
+
+	for _, pkg := range discovered {
+		sol := pkg.Artifact.Solana
+		if sol == nil {
+			continue
+		}
+
+		pa := PublishArtifact{
+			Name:       pkg.Artifact.Name,
+			SourcePath: sol.SourcePath,
+			IDL:        sol.IDL,
+			Program:    sol.Binary,
+		}
+		if projectConfig != nil {
+			pa.Labels = projectConfig.Labels[pkg.Artifact.Name]
+		}
+		pa.Metadata = takeContractMetadata(contractMetadata, pkg.Artifact.Name, nil)
+		if signKey != nil {
+			if err := signArtifact(signKey, &pa); err != nil {
+				return err
+			}
+		}
+		packages = append(packages, packageToPublish{name: pkg.Name, artifact: pa})
+
+		programID := sol.ProgramID
+		if programID == "" {
+			programID = "unknown program id"
+		}
+		fmt.Printf("  + %s (%s) -> %s@%s\n", pkg.Artifact.Name, programID, pkg.Name, version)
+		if sol.IDL == nil {
+			fmt.Printf("  Warning: no IDL found for %s (expected target/idl/%s.json)\n", pkg.Artifact.Name, pkg.Artifact.Name)
+		}
+	}
+	if err := checkContractMetadataUsed(contractMetadata); err != nil {
+		return err
+	}
+
+	serverURL := getServer()
+	summary := &publishSummary{Server: serverURL, Version: version, DryRun: dryRun, Packages: make([]publishSummaryEntry, len(packages))}
+	for i, pkg := range packages {
+		summary.Packages[i] = publishSummaryEntry{
+			Name:       pkg.name,
+			Version:    version,
+			Contract:   pkg.artifact.Name,
+			SourcePath: pkg.artifact.SourcePath,
+			Status:     summaryStatusDryRun,
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("\nDRY RUN - Would publish %d package(s) to %s\n", len(packages), serverURL)
+		if project != "" {
+			fmt.Printf("  Project: %s\n", project)
+		}
+		for _, pkg := range packages {
+			fmt.Printf("   - %s@%s\n", pkg.name, version)
+		}
+		return writePublishSummary(summaryOut, summary)
+	}
+
+	if err := checkArtifactLimit(serverURL, len(packages), 1, maxArtifacts); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nPublishing %d package(s) to %s...\n", len(packages), serverURL)
+
+	var successCount, failCount, existingCount int
+	publishConcurrently(len(packages), concurrency, func(i int) error {
+		req := PublishRequest{
+			Chain:     chain,
+			Builder:   "anchor",
+			Project:   project,
+			Artifacts: []PublishArtifact{packages[i].artifact},
+			Metadata:  metadata,
+			Tags:      tags,
+		}
+		return publishUnlessExists(serverURL, packages[i].name, version, skipExisting, func() error {
+			return sendPublishRequest(serverURL, packages[i].name, version, req)
+		})
+	}, func(i int, err error) {
+		summary.record(i, err)
+		switch {
+		case errors.Is(err, errVersionExists):
+			fmt.Printf("   - %s@%s: already published, skipped\n", packages[i].name, version)
+			existingCount++
+		case err != nil:
+			fmt.Printf("   X %s@%s: %v\n", packages[i].name, version, err)
+			failCount++
+		default:
+			fmt.Printf("   OK %s@%s\n", packages[i].name, version)
+			successCount++
+		}
+	})
+
+	if err := writePublishSummary(summaryOut, summary); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	if existingCount > 0 {
+		fmt.Printf("Skipped %d package(s) already in the registry\n", existingCount)
+	}
+	if failCount > 0 {
+		return fmt.Errorf("published %d package(s), %d failed", successCount, failCount)
+	}
+
+	fmt.Printf("Successfully published %d package(s)\n", successCount)
+	return nil
+}
+
+// runDiscoverAnchor lists the programs an Anchor project would publish.
+func runDiscoverAnchor(cwd string, exclude []string) error {
+	discovered, err := discoverAnchorPackages(cwd, "", nil, exclude)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Programs in target/deploy/ (%d):\n\n", len(discovered))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, pkg := range discovered {
+		sol := pkg.Artifact.Solana
+		idl := "no IDL"
+		if sol.IDL != nil {
+			idl = "IDL"
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", pkg.Artifact.Name, sol.ProgramID, idl)
+	}
+	w.Flush()
+	return nil
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAnchorProject(t *testing.T) {
+	dir := t.TempDir()
+	assert.False(t, isAnchorProject(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Anchor.toml"), nil, 0644))
+	assert.True(t, isAnchorProject(dir))
+
+	// Foundry takes precedence in mixed repos
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foundry.toml"), nil, 0644))
+	assert.False(t, isAnchorProject(dir))
+}
+
+func TestRunPublishAnchor(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Anchor.toml"), nil, 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "target", "deploy"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "target", "idl"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "target", "deploy", "token_vault.so"), []byte("\x7fELF"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "target", "idl", "token_vault.json"),
+		[]byte(`{"address":"Fg6PaFpoGXkYsidMpWTK6W2BeZ7FEfcYkg476zPFsLnS","instructions":[]}`), 0644))
+
+	var gotPath string
+	var gotReq PublishRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotReq))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	oldServer := server
+	server = srv.URL
+	defer func() { server = oldServer }()
+
+	discovered, err := discoverAnchorPackages(dir, "", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, discovered, 1)
+	assert.Equal(t, "token-vault", discovered[0].Name)
+
+	config := &ProjectConfig{Labels: map[string][]string{"token_vault": {"vault"}}}
+	require.NoError(t, runPublishAnchor(dir, discovered, "1.0.0", "", "solana", false, false, 1, nil, nil, nil, config, "", nil, 0))
+
+	assert.Equal(t, "/api/v1/packages/token-vault/1.0.0", gotPath)
+	assert.Equal(t, "solana", gotReq.Chain)
+	assert.Equal(t, "anchor", gotReq.Builder)
+	require.Len(t, gotReq.Artifacts, 1)
+	assert.Equal(t, "token_vault", gotReq.Artifacts[0].Name)
+	assert.Equal(t, []byte("\x7fELF"), gotReq.Artifacts[0].Program)
+	assert.NotEmpty(t, gotReq.Artifacts[0].IDL)
+	assert.Equal(t, []string{"vault"}, gotReq.Artifacts[0].Labels)
+}
+
+func TestRunPublishAnchor_SummaryOut(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Anchor.toml"), nil, 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "target", "deploy"), 0755))
+	for _, program := range []string{"token_vault", "staking"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "target", "deploy", program+".so"), []byte("\x7fELF"), 0644))
+	}
+
+	// Reject one of the two packages to check partial failures are recorded
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/packages/staking/1.0.0" {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": "VERSION_EXISTS", "message": "Version already exists"}})
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	oldServer := server
+	server = srv.URL
+	defer func() { server = oldServer }()
+
+	discovered, err := discoverAnchorPackages(dir, "", nil, nil)
+	require.NoError(t, err)
+
+	summaryPath := filepath.Join(t.TempDir(), "summary.json")
+	err = runPublishAnchor(dir, discovered, "1.0.0", "", "solana", false, false, 1, nil, nil, nil, nil, summaryPath, nil, 0)
+	require.Error(t, err)
+
+	data, err := os.ReadFile(summaryPath)
+	require.NoError(t, err)
+	var summary publishSummary
+	require.NoError(t, json.Unmarshal(data, &summary))
+
+	assert.Equal(t, srv.URL, summary.Server)
+	assert.Equal(t, 1, summary.Published)
+	assert.Equal(t, 1, summary.Failed)
+	require.Len(t, summary.Packages, 2)
+	byName := map[string]publishSummaryEntry{}
+	for _, p := range summary.Packages {
+		byName[p.Name] = p
+	}
+	assert.Equal(t, summaryStatusPublished, byName["token-vault"].Status)
+	assert.Equal(t, "1.0.0", byName["token-vault"].Version)
+	assert.Equal(t, summaryStatusFailed, byName["staking"].Status)
+	assert.Contains(t, byName["staking"].Error, "VERSION_EXISTS")
+}
+
+func TestRunPublishAnchor_SkipExisting(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Anchor.toml"), nil, 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "target", "deploy"), 0755))
+	for _, program := range []string{"token_vault", "staking"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "target", "deploy", program+".so"), []byte("\x7fELF"), 0644))
+	}
+
+	// staking@1.0.0 was published by an earlier, partially failed run
+	var posted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/packages/staking/1.0.0":
+			w.Write([]byte(`{"name":"staking","version":"1.0.0"}`))
+		case r.Method == "GET":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"Package not found"}}`))
+		default:
+			posted = append(posted, r.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer srv.Close()
+
+	oldServer := server
+	server = srv.URL
+	defer func() { server = oldServer }()
+
+	discovered, err := discoverAnchorPackages(dir, "", nil, nil)
+	require.NoError(t, err)
+
+	summaryPath := filepath.Join(t.TempDir(), "summary.json")
+	require.NoError(t, runPublishAnchor(dir, discovered, "1.0.0", "", "solana", false, true, 1, nil, nil, nil, nil, summaryPath, nil, 0))
+	assert.Equal(t, []string{"/api/v1/packages/token-vault/1.0.0"}, posted)
+
+	data, err := os.ReadFile(summaryPath)
+	require.NoError(t, err)
+	var summary publishSummary
+	require.NoError(t, json.Unmarshal(data, &summary))
+	assert.Equal(t, 1, summary.Published)
+	assert.Equal(t, 1, summary.Existing)
+	assert.Equal(t, 0, summary.Failed)
+	for _, p := range summary.Packages {
+		if p.Name == "staking" {
+			assert.Equal(t, summaryStatusExists, p.Status)
+		} else {
+			assert.Equal(t, summaryStatusPublished, p.Status)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Batch modes for --batch, matching the server's publish-batch ?mode= values
+const (
+	batchModeAtomic     = "atomic"
+	batchModeBestEffort = "best-effort"
+)
+
+// publishBatchItem is one package version in a publish-batch request.
+type publishBatchItem struct {
+	Name    string         `json:"name"`
+	Version string         `json:"version"`
+	Request PublishRequest `json:"request"`
+}
+
+// publishBatchResponse is the server's per-package outcome of a publish-batch request.
+type publishBatchResponse struct {
+	Atomic    bool `json:"atomic"`
+	Published int  `json:"published"`
+	Failed    int  `json:"failed"`
+	Results   []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Status  string `json:"status"` // published, failed, rolled-back or skipped
+		Error   *struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"results"`
+}
+
+// sendPublishBatch POSTs all items in a single publish-batch request.
+func sendPublishBatch(serverURL string, items []publishBatchItem, mode string) (*publishBatchResponse, error) {
+	reqBody, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", serverURL+"/api/v1/publish-batch?mode="+mode, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if key := getAPIKey(); key != "" {
+		httpReq.Header.Set("X-API-Key", key)
+	}
+
+	resp, err := publishHTTPClient().Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	// 201 when everything was published, 200 when some items were not
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, serverError(resp, body)
+	}
+
+	var result publishBatchResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	if len(result.Results) != len(items) {
+		return nil, fmt.Errorf("server returned %d results for %d packages", len(result.Results), len(items))
+	}
+	return &result, nil
+}
+
+// publishBatch publishes items in one request, printing and recording in summary the
+// outcome of each. Entries in summary must be in the same order as items.
+func publishBatch(serverURL string, items []publishBatchItem, mode string, summary *publishSummary) (published, failed int, err error) {
+	resp, err := sendPublishBatch(serverURL, items, mode)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for i, r := range resp.Results {
+		switch r.Status {
+		case summaryStatusPublished:
+			summary.record(i, nil)
+			fmt.Printf("   OK %s@%s\n", r.Name, r.Version)
+		case summaryStatusFailed:
+			msg := "failed"
+			if r.Error != nil {
+				msg = fmt.Sprintf("%s - %s", r.Error.Code, r.Error.Message)
+			}
+			summary.record(i, fmt.Errorf("%s", msg))
+			fmt.Printf("   X %s@%s: %s\n", r.Name, r.Version, msg)
+		default:
+			// rolled-back or skipped: not published because another package failed
+			summary.Packages[i].Status = r.Status
+			fmt.Printf("   - %s@%s: %s\n", r.Name, r.Version, r.Status)
+		}
+	}
+
+	if resp.Atomic && resp.Failed > 0 {
+		fmt.Println("\nBatch is atomic: no packages were published")
+	}
+	return resp.Published, resp.Failed, nil
+}
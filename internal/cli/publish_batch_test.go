@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishBatch(t *testing.T) {
+	var gotMode string
+	var gotItems []publishBatchItem
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/publish-batch", r.URL.Path)
+		gotMode = r.URL.Query().Get("mode")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotItems))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"atomic":true,"published":0,"failed":1,"results":[
+			{"name":"Token","version":"1.0.0","status":"rolled-back"},
+			{"name":"Registry","version":"1.0.0","status":"failed","error":{"code":"VERSION_EXISTS","message":"Version already exists and is immutable"}},
+			{"name":"Vault","version":"1.0.0","status":"skipped"}]}`))
+	}))
+	defer srv.Close()
+
+	items := []publishBatchItem{
+		{Name: "Token", Version: "1.0.0", Request: evmPublishRequest("evm", "", PublishArtifact{Name: "Token"}, nil, nil)},
+		{Name: "Registry", Version: "1.0.0", Request: evmPublishRequest("evm", "", PublishArtifact{Name: "Registry"}, nil, nil)},
+		{Name: "Vault", Version: "1.0.0", Request: evmPublishRequest("evm", "", PublishArtifact{Name: "Vault"}, nil, nil)},
+	}
+	summary := &publishSummary{Packages: make([]publishSummaryEntry, len(items))}
+
+	published, failed, err := publishBatch(srv.URL, items, batchModeAtomic, summary)
+	require.NoError(t, err)
+	assert.Equal(t, batchModeAtomic, gotMode)
+	assert.Len(t, gotItems, 3)
+	assert.Equal(t, "foundry", gotItems[0].Request.Builder)
+	assert.Equal(t, 0, published)
+	assert.Equal(t, 1, failed)
+
+	assert.Equal(t, summaryStatusRolledBack, summary.Packages[0].Status)
+	assert.Equal(t, summaryStatusFailed, summary.Packages[1].Status)
+	assert.Contains(t, summary.Packages[1].Error, "VERSION_EXISTS")
+	assert.Equal(t, summaryStatusSkipped, summary.Packages[2].Status)
+	assert.Equal(t, 1, summary.Failed)
+}
+
+func TestPublishBatch_RequestRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"INVALID_REQUEST","message":"invalid batch: no packages"}}`))
+	}))
+	defer srv.Close()
+
+	_, _, err := publishBatch(srv.URL, []publishBatchItem{{Name: "Token", Version: "1.0.0"}}, batchModeBestEffort, &publishSummary{Packages: make([]publishSummaryEntry, 1)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "INVALID_REQUEST")
+}
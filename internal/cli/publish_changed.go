@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// errNotGitRepo is returned by changedSolidityFiles outside a git work tree (or
+// without git installed), where --only-changed falls back to publishing everything.
+var errNotGitRepo = errors.New("not a git repository")
+
+// changedSources is the set of Solidity files changed between a base revision and
+// HEAD, as paths relative to the project directory (like artifact source paths).
+type changedSources struct {
+	base  string
+	paths map[string]bool
+}
+
+// includes reports whether the contract compiled from sourcePath changed.
+func (c *changedSources) includes(sourcePath string) bool {
+	return c.paths[sourcePath]
+}
+
+// changedSolidityFiles lists the .sol files under dir changed in base..HEAD. An
+// empty base means the merge-base of HEAD and the default branch.
+func changedSolidityFiles(dir, base string) (*changedSources, error) {
+	if _, err := runGit(dir, "rev-parse", "--is-inside-work-tree"); err != nil {
+		return nil, errNotGitRepo
+	}
+
+	if base == "" {
+		branch, err := defaultBranch(dir)
+		if err != nil {
+			return nil, err
+		}
+		base, err = runGit(dir, "merge-base", "HEAD", branch)
+		if err != nil {
+			return nil, fmt.Errorf("finding merge-base with %s: %w", branch, err)
+		}
+	}
+
+	// --relative makes paths relative to dir and leaves out changes outside it,
+	// so a project in a monorepo subdirectory only sees its own files
+	out, err := runGit(dir, "diff", "--name-only", "--relative", base+"..HEAD", "--", "*.sol")
+	if err != nil {
+		return nil, fmt.Errorf("listing files changed since %s: %w", base, err)
+	}
+
+	changed := &changedSources{base: base, paths: make(map[string]bool)}
+	for _, path := range strings.Split(out, "\n") {
+		if path != "" {
+			changed.paths[path] = true
+		}
+	}
+	return changed, nil
+}
+
+// defaultBranch returns the remote's default branch (origin/HEAD), falling back to
+// the first of origin/main, origin/master, main and master that exists.
+func defaultBranch(dir string) (string, error) {
+	if ref, err := runGit(dir, "symbolic-ref", "--quiet", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		return ref, nil
+	}
+	for _, branch := range []string{"origin/main", "origin/master", "main", "master"} {
+		if _, err := runGit(dir, "rev-parse", "--verify", "--quiet", branch+"^{commit}"); err == nil {
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("can't find the default branch to compare against; pass --base")
+}
+
+// runGit runs git in dir and returns its trimmed stdout, or stderr as the error.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", errors.New(msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// shortRev abbreviates a full commit hash for display; refs and tags pass through.
+func shortRev(rev string) string {
+	if len(rev) == 40 && strings.Trim(rev, "0123456789abcdef") == "" {
+		return rev[:12]
+	}
+	return rev
+}
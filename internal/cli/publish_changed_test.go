@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedSolidityFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		out, err := runGit(repo, append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		require.NoError(t, err, "git %v", args)
+		return out
+	}
+	write := func(path, content string) {
+		t.Helper()
+		full := filepath.Join(repo, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0o644))
+	}
+
+	git("init", "--quiet", "--initial-branch=main")
+	for _, path := range []string{"contracts/src/Token.sol", "contracts/src/Vault.sol", "other/src/Token.sol"} {
+		write(path, "// v1")
+	}
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "initial")
+	git("tag", "v1")
+
+	git("checkout", "--quiet", "-b", "feature")
+	write("contracts/src/Vault.sol", "// v2")
+	write("contracts/README.md", "docs")
+	write("other/src/Token.sol", "// v2")
+	git("add", "-A")
+	git("commit", "--quiet", "-m", "change")
+
+	paths := func(c *changedSources) []string {
+		return slices.Sorted(maps.Keys(c.paths))
+	}
+
+	t.Run("default base is the merge-base with main", func(t *testing.T) {
+		changed, err := changedSolidityFiles(filepath.Join(repo, "contracts"), "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"src/Vault.sol"}, paths(changed), "paths are relative to the project; other projects and non-Solidity files are left out")
+		assert.True(t, changed.includes("src/Vault.sol"))
+		assert.False(t, changed.includes("src/Token.sol"))
+		assert.Equal(t, git("rev-parse", "v1"), changed.base)
+	})
+
+	t.Run("explicit base", func(t *testing.T) {
+		changed, err := changedSolidityFiles(repo, "HEAD")
+		require.NoError(t, err)
+		assert.Empty(t, changed.paths)
+
+		changed, err = changedSolidityFiles(repo, "v1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"contracts/src/Vault.sol", "other/src/Token.sol"}, paths(changed))
+	})
+
+	t.Run("unknown base", func(t *testing.T) {
+		_, err := changedSolidityFiles(repo, "no-such-ref")
+		assert.ErrorContains(t, err, "listing files changed since no-such-ref")
+	})
+
+	t.Run("not a git repository", func(t *testing.T) {
+		_, err := changedSolidityFiles(t.TempDir(), "")
+		assert.ErrorIs(t, err, errNotGitRepo)
+	})
+}
+
+func TestShortRev(t *testing.T) {
+	assert.Equal(t, "0123456789ab", shortRev("0123456789abcdef0123456789abcdef01234567"))
+	assert.Equal(t, "v1.0.0", shortRev("v1.0.0"))
+	assert.Equal(t, "origin/main", shortRev("origin/main"))
+}
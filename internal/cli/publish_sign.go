@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
+
+	"github.com/pendergraft/contrafactory/internal/provenance"
+)
+
+// loadSignKey reads the ed25519 private key (PKCS #8 PEM) given with --sign-key.
+// It returns nil when no key is given.
+func loadSignKey(path string) (ed25519.PrivateKey, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("--sign-key: %w", err)
+	}
+	key, err := provenance.ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("--sign-key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// signArtifact signs what the server checks a signature against: the ABI and
+// creation bytecode, or a Solana program's IDL and binary.
+func signArtifact(key ed25519.PrivateKey, a *PublishArtifact) error {
+	abi, code := a.ABI, []byte(a.Bytecode)
+	if len(a.Program) > 0 {
+		abi, code = a.IDL, a.Program
+	}
+	sig, err := provenance.Sign(key, abi, code)
+	if err != nil {
+		return fmt.Errorf("signing %s: %w", a.Name, err)
+	}
+	a.Signature = sig.Signature
+	a.PublicKey = sig.PublicKey
+	return nil
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/provenance"
+)
+
+func TestLoadSignKeyAndSignArtifact(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+
+	loaded, err := loadSignKey(path)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(loaded))
+
+	none, err := loadSignKey("")
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	_, err = loadSignKey(filepath.Join(t.TempDir(), "missing.pem"))
+	assert.ErrorContains(t, err, "--sign-key")
+
+	t.Run("EVM signs ABI and bytecode", func(t *testing.T) {
+		a := PublishArtifact{Name: "Token", ABI: []byte(`[]`), Bytecode: "0x6080"}
+		require.NoError(t, signArtifact(loaded, &a))
+		sig := &provenance.Signature{Algorithm: provenance.Algorithm, PublicKey: a.PublicKey, Signature: a.Signature}
+		assert.NoError(t, provenance.Verify(sig, []byte(`[]`), []byte("0x6080")))
+	})
+
+	t.Run("Solana signs IDL and program", func(t *testing.T) {
+		a := PublishArtifact{Name: "vault", IDL: []byte(`{"instructions":[]}`), Program: []byte("\x7fELF")}
+		require.NoError(t, signArtifact(loaded, &a))
+		sig := &provenance.Signature{Algorithm: provenance.Algorithm, PublicKey: a.PublicKey, Signature: a.Signature}
+		assert.NoError(t, provenance.Verify(sig, []byte(`{"instructions":[]}`), []byte("\x7fELF")))
+	})
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pendergraft/contrafactory/internal/chains/evm/foundry"
+)
+
+// standardJSONPublishOptions are the inputs of publish --from-standard-json.
+type standardJSONPublishOptions struct {
+	InputPath       string // Standard JSON Input file
+	ABIPath         string
+	Bytecode        string // 0x-prefixed creation bytecode, or a file containing it
+	Name            string // package name
+	Contract        string // contract name; defaults to Name
+	CompilerVersion string // optional full solc version, e.g. 0.8.28+commit.7893614a
+}
+
+// runPublishStandardJSON publishes a single contract from a Standard JSON Input, ABI
+// and bytecode produced by another build system, bypassing project discovery.
+func runPublishStandardJSON(opts standardJSONPublishOptions, version, projectFlag, chainFlag string, dryRun bool, metadataPairs, tags, contractMetadataPairs []string, signKey ed25519.PrivateKey, maxArtifacts int) error {
+	if opts.Name == "" {
+		return fmt.Errorf("--name is required when using --from-standard-json")
+	}
+
+	metadata, err := parseMetadata(metadataPairs)
+	if err != nil {
+		return fmt.Errorf("parsing metadata: %w", err)
+	}
+	contractMetadata, err := parseContractMetadata(contractMetadataPairs)
+	if err != nil {
+		return err
+	}
+
+	chain, err := resolvePublishChain(chainFlag, loadProjectConfigSilent(), "evm", "")
+	if err != nil {
+		return err
+	}
+
+	artifact, err := artifactFromStandardJSON(opts)
+	if err != nil {
+		return err
+	}
+	req := &PublishRequest{Chain: chain, Builder: "standard-json", Artifacts: []PublishArtifact{*artifact}}
+	return publishPayload(req, opts.Name, version, projectFlag, metadata, tags, contractMetadata, dryRun, signKey, maxArtifacts)
+}
+
+// artifactFromStandardJSON builds the artifact for publish --from-standard-json.
+func artifactFromStandardJSON(opts standardJSONPublishOptions) (*PublishArtifact, error) {
+	if opts.ABIPath == "" || opts.Bytecode == "" {
+		return nil, fmt.Errorf("--abi and --bytecode are required with --from-standard-json")
+	}
+	contract := opts.Contract
+	if contract == "" {
+		contract = opts.Name
+	}
+
+	input, err := os.ReadFile(opts.InputPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading Standard JSON Input: %w", err)
+	}
+	sources, err := validateStandardJSONInput(input)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", opts.InputPath, err)
+	}
+	sourcePath, err := findContractSource(sources, contract)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", opts.InputPath, err)
+	}
+
+	abi, err := os.ReadFile(opts.ABIPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading ABI: %w", err)
+	}
+
+	bytecode := opts.Bytecode
+	if !strings.HasPrefix(bytecode, "0x") {
+		data, err := os.ReadFile(bytecode)
+		if err != nil {
+			return nil, fmt.Errorf("--bytecode must be 0x-prefixed hex or a file containing it: %w", err)
+		}
+		bytecode = strings.TrimSpace(string(data))
+	}
+
+	artifact := &PublishArtifact{
+		Name:              contract,
+		SourcePath:        sourcePath,
+		ABI:               abi,
+		Bytecode:          bytecode,
+		StandardJSONInput: input,
+	}
+	if opts.CompilerVersion != "" {
+		settings, err := foundry.SettingsFromStandardJSON(input)
+		if err != nil {
+			return nil, err
+		}
+		artifact.Compiler = &CompilerInfo{
+			Version:    opts.CompilerVersion,
+			Optimizer:  &OptimizerInfo{Enabled: settings.OptimizerEnabled, Runs: settings.OptimizerRuns},
+			EVMVersion: settings.EVMVersion,
+			ViaIR:      settings.ViaIR,
+		}
+	}
+	return artifact, nil
+}
+
+// validateStandardJSONInput checks that input is a Standard JSON Input: an object
+// with a language, at least one source and settings. It returns the sources.
+func validateStandardJSONInput(input []byte) (map[string]json.RawMessage, error) {
+	var parsed struct {
+		Language string                     `json:"language"`
+		Sources  map[string]json.RawMessage `json:"sources"`
+		Settings json.RawMessage            `json:"settings"`
+	}
+	if err := json.Unmarshal(input, &parsed); err != nil {
+		return nil, fmt.Errorf("not a Standard JSON Input: %w", describeJSONError(err))
+	}
+	if parsed.Language == "" {
+		return nil, fmt.Errorf("not a Standard JSON Input: missing language")
+	}
+	if len(parsed.Sources) == 0 {
+		return nil, fmt.Errorf("not a Standard JSON Input: missing sources")
+	}
+	if len(parsed.Settings) == 0 || parsed.Settings[0] != '{' {
+		return nil, fmt.Errorf("not a Standard JSON Input: missing settings")
+	}
+	return parsed.Sources, nil
+}
+
+// findContractSource returns the path of the one source declaring contract (as a
+// contract, abstract contract or library).
+func findContractSource(sources map[string]json.RawMessage, contract string) (string, error) {
+	decl := regexp.MustCompile(`\b(?:contract|library)\s+` + regexp.QuoteMeta(contract) + `\b`)
+	var found []string
+	for path, raw := range sources {
+		var source struct {
+			Content string `json:"content"`
+		}
+		if err := json.Unmarshal(raw, &source); err != nil {
+			return "", fmt.Errorf("source %s: %w", path, describeJSONError(err))
+		}
+		if decl.MatchString(source.Content) {
+			found = append(found, path)
+		}
+	}
+	slices.Sort(found)
+
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("no source declares contract %s (use --contract to name it)", contract)
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("contract %s is declared in several sources: %s", contract, strings.Join(found, ", "))
+	}
+}
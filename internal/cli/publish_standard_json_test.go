@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testStandardJSON = `{
+	"language": "Solidity",
+	"sources": {
+		"src/Foo.sol": {"content": "import {IFoo} from \"./IFoo.sol\";\ncontract Foo is IFoo {}"},
+		"src/IFoo.sol": {"content": "interface IFoo {}"}
+	},
+	"settings": {"optimizer": {"enabled": true, "runs": 1000}, "evmVersion": "cancun"}
+}`
+
+func TestValidateStandardJSONInput(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantErrContain string
+	}{
+		{name: "valid", input: testStandardJSON},
+		{name: "not JSON", input: "nope", wantErrContain: "not a Standard JSON Input"},
+		{name: "missing language", input: `{"sources":{"a.sol":{"content":""}},"settings":{}}`, wantErrContain: "missing language"},
+		{name: "missing sources", input: `{"language":"Solidity","sources":{},"settings":{}}`, wantErrContain: "missing sources"},
+		{name: "missing settings", input: `{"language":"Solidity","sources":{"a.sol":{"content":""}}}`, wantErrContain: "missing settings"},
+		{name: "settings not an object", input: `{"language":"Solidity","sources":{"a.sol":{"content":""}},"settings":null}`, wantErrContain: "missing settings"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateStandardJSONInput([]byte(tt.input))
+			if tt.wantErrContain != "" {
+				assert.ErrorContains(t, err, tt.wantErrContain)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestFindContractSource(t *testing.T) {
+	sources, err := validateStandardJSONInput([]byte(testStandardJSON))
+	require.NoError(t, err)
+
+	path, err := findContractSource(sources, "Foo")
+	require.NoError(t, err)
+	assert.Equal(t, "src/Foo.sol", path)
+
+	_, err = findContractSource(sources, "IFoo")
+	assert.ErrorContains(t, err, "no source declares contract IFoo", "interfaces have no bytecode to publish")
+
+	sources["src/Foo2.sol"] = json.RawMessage(`{"content":"contract Foo {}"}`)
+	_, err = findContractSource(sources, "Foo")
+	assert.ErrorContains(t, err, "src/Foo.sol, src/Foo2.sol")
+}
+
+func TestRunPublishStandardJSON(t *testing.T) {
+	var gotReq PublishRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/limits" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "/api/v1/packages/foo/1.0.0", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &gotReq))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	oldServer := server
+	server = ts.URL
+	defer func() { server = oldServer }()
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+		return p
+	}
+	opts := standardJSONPublishOptions{
+		InputPath:       write("input.json", testStandardJSON),
+		ABIPath:         write("Foo.abi.json", `[{"type":"constructor","inputs":[]}]`),
+		Bytecode:        write("Foo.bin", "0x6080\n"),
+		Name:            "foo",
+		Contract:        "Foo",
+		CompilerVersion: "0.8.28+commit.7893614a",
+	}
+
+	require.NoError(t, runPublishStandardJSON(opts, "1.0.0", "", "", false, []string{"ci=external"}, nil, nil, nil, 0))
+	assert.Equal(t, "evm", gotReq.Chain)
+	assert.Equal(t, "standard-json", gotReq.Builder)
+	assert.Equal(t, "external", gotReq.Metadata["ci"])
+	require.Len(t, gotReq.Artifacts, 1)
+	a := gotReq.Artifacts[0]
+	assert.Equal(t, "Foo", a.Name)
+	assert.Equal(t, "src/Foo.sol", a.SourcePath)
+	assert.Equal(t, "0x6080", a.Bytecode)
+	assert.JSONEq(t, testStandardJSON, string(a.StandardJSONInput))
+	require.NotNil(t, a.Compiler)
+	assert.Equal(t, CompilerInfo{Version: "0.8.28+commit.7893614a", Optimizer: &OptimizerInfo{Enabled: true, Runs: 1000}, EVMVersion: "cancun"}, *a.Compiler)
+
+	t.Run("inline bytecode, contract defaults to name", func(t *testing.T) {
+		opts := opts
+		opts.Name, opts.Contract, opts.Bytecode, opts.CompilerVersion = "Foo", "", "0x6090", ""
+		_, err := artifactFromStandardJSON(opts)
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid input is rejected before sending", func(t *testing.T) {
+		opts := opts
+		opts.InputPath = write("bad.json", `{"language":"Solidity"}`)
+		err := runPublishStandardJSON(opts, "1.0.0", "", "", false, nil, nil, nil, nil, 0)
+		assert.ErrorContains(t, err, "missing sources")
+	})
+
+	t.Run("requires name, ABI and bytecode", func(t *testing.T) {
+		opts := opts
+		opts.Name = ""
+		assert.ErrorContains(t, runPublishStandardJSON(opts, "1.0.0", "", "", false, nil, nil, nil, nil, 0), "--name")
+
+		opts.Name, opts.Bytecode = "foo", ""
+		assert.ErrorContains(t, runPublishStandardJSON(opts, "1.0.0", "", "", false, nil, nil, nil, nil, 0), "--bytecode")
+	})
+}
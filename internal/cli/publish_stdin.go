@@ -0,0 +1,247 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pendergraft/contrafactory/internal/validation"
+)
+
+// runPublishStdin publishes a payload read from r, bypassing project discovery.
+// The payload is either a full PublishRequest or a single PublishArtifact.
+func runPublishStdin(r io.Reader, name, version, projectFlag, chainFlag string, dryRun bool, metadataPairs, tags, contractMetadataPairs []string, signKey ed25519.PrivateKey, maxArtifacts int) error {
+	if name == "" {
+		return fmt.Errorf("--name is required when using --stdin")
+	}
+
+	metadata, err := parseMetadata(metadataPairs)
+	if err != nil {
+		return fmt.Errorf("parsing metadata: %w", err)
+	}
+	contractMetadata, err := parseContractMetadata(contractMetadataPairs)
+	if err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("reading stdin: %w", err)
+	}
+
+	req, err := decodePublishPayload(data)
+	if err != nil {
+		return err
+	}
+	// --chain overrides the payload's chain; the config only fills in a missing one
+	if chainFlag != "" || req.Chain == "" {
+		if req.Chain, err = resolvePublishChain(chainFlag, loadProjectConfigSilent(), "evm", ""); err != nil {
+			return err
+		}
+	}
+	return publishPayload(req, name, version, projectFlag, metadata, tags, contractMetadata, dryRun, signKey, maxArtifacts)
+}
+
+// publishPayload publishes a request built without project discovery (--stdin,
+// --from-standard-json) as name@version. The project, metadata and contract metadata
+// flags override the request's; tags are added to its own.
+func publishPayload(req *PublishRequest, name, version, projectFlag string, metadata map[string]string, tags []string, contractMetadata map[string]map[string]string, dryRun bool, signKey ed25519.PrivateKey, maxArtifacts int) error {
+	if projectFlag != "" {
+		req.Project = projectFlag
+	}
+	if len(metadata) > 0 {
+		if req.Metadata == nil {
+			req.Metadata = make(map[string]string)
+		}
+		for k, v := range metadata {
+			req.Metadata[k] = v
+		}
+	}
+	req.Tags = append(req.Tags, tags...)
+	for i := range req.Artifacts {
+		req.Artifacts[i].Metadata = takeContractMetadata(contractMetadata, req.Artifacts[i].Name, req.Artifacts[i].Metadata)
+	}
+	if err := checkContractMetadataUsed(contractMetadata); err != nil {
+		return err
+	}
+
+	if err := validatePublishPayload(name, version, req); err != nil {
+		return err
+	}
+	if signKey != nil {
+		for i := range req.Artifacts {
+			if err := signArtifact(signKey, &req.Artifacts[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	serverURL := getServer()
+	if dryRun {
+		fmt.Printf("DRY RUN - Would publish %s@%s to %s\n", name, version, serverURL)
+		if req.Project != "" {
+			fmt.Printf("  Project: %s\n", req.Project)
+		}
+		for _, a := range req.Artifacts {
+			fmt.Printf("   - %s (%s)\n", a.Name, a.SourcePath)
+		}
+		return nil
+	}
+
+	if err := checkArtifactLimit(serverURL, len(req.Artifacts), len(req.Artifacts), maxArtifacts); err != nil {
+		return err
+	}
+
+	fmt.Printf("Publishing %s@%s to %s...\n", name, version, serverURL)
+	if err := sendPublishRequest(serverURL, name, version, *req); err != nil {
+		return fmt.Errorf("publishing %s@%s: %w", name, version, err)
+	}
+
+	fmt.Printf("   OK %s@%s\n", name, version)
+	return nil
+}
+
+// checkArtifactLimit fails before anything is sent when a publish has more
+// artifacts in total than maxArtifacts (--max-artifacts, 0 for no cap), or more in
+// one request than the server's max_artifacts_per_publish. Servers that don't
+// expose /api/v1/limits are not checked; they still enforce the limit on publish.
+// The server's limit is per request and at least 1, so requests of one artifact
+// each (discovered projects, --batch items) aren't checked against it.
+func checkArtifactLimit(serverURL string, total, perRequest, maxArtifacts int) error {
+	if maxArtifacts > 0 && total > maxArtifacts {
+		return fmt.Errorf("publish has %d artifacts, more than --max-artifacts %d", total, maxArtifacts)
+	}
+	if perRequest <= 1 {
+		return nil
+	}
+	c := newClient(serverURL, getAPIKey())
+	limits, err := c.GetLimits(context.Background())
+	if err != nil {
+		return nil
+	}
+	if limits.MaxArtifactsPerPublish > 0 && perRequest > limits.MaxArtifactsPerPublish {
+		return fmt.Errorf("payload has %d artifacts but %s allows at most %d per publish",
+			perRequest, serverURL, limits.MaxArtifactsPerPublish)
+	}
+	return nil
+}
+
+// decodePublishPayload decodes a PublishRequest or a single artifact from JSON.
+// An object with an "artifacts" key is treated as a full request.
+func decodePublishPayload(data []byte) (*PublishRequest, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no input on stdin")
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("invalid publish payload: %w", describeJSONError(err))
+	}
+
+	if _, ok := probe["artifacts"]; ok {
+		var req PublishRequest
+		if err := strictUnmarshal(data, &req); err != nil {
+			return nil, fmt.Errorf("invalid publish request: %w", describeJSONError(err))
+		}
+		return &req, nil
+	}
+
+	var artifact PublishArtifact
+	if err := strictUnmarshal(data, &artifact); err != nil {
+		return nil, fmt.Errorf("invalid artifact: %w", describeJSONError(err))
+	}
+	return &PublishRequest{Artifacts: []PublishArtifact{artifact}}, nil
+}
+
+// validatePublishPayload checks a publish request before it is sent, using the
+// server's rules, and fills in chain/builder defaults. Artifact problems are
+// returned together as validation.FieldErrors naming each field.
+func validatePublishPayload(name, version string, req *PublishRequest) error {
+	if err := validation.ValidatePackageName(name); err != nil {
+		return fmt.Errorf("name: %w", err)
+	}
+	if err := validation.ValidateVersion(version); err != nil {
+		return fmt.Errorf("version: %w", err)
+	}
+
+	if req.Chain == "" {
+		req.Chain = "evm"
+	}
+	if req.Builder == "" {
+		req.Builder = "foundry"
+	}
+
+	names := make([]string, len(req.Artifacts))
+	for i, a := range req.Artifacts {
+		names[i] = a.Name
+	}
+	errs := validation.ValidatePublishRequest(req.Chain, names)
+	for i, a := range req.Artifacts {
+		field := fmt.Sprintf("artifacts[%d]", i)
+		if len(a.ABI) > 0 {
+			if err := validation.ValidateABI(a.ABI); err != nil {
+				errs = append(errs, validation.FieldError{Field: field + ".abi", Message: err.Error()})
+			}
+		}
+		if err := validateHexField(a.Bytecode); err != nil {
+			errs = append(errs, validation.FieldError{Field: field + ".bytecode", Message: err.Error()})
+		}
+		if err := validateHexField(a.DeployedBytecode); err != nil {
+			errs = append(errs, validation.FieldError{Field: field + ".deployedBytecode", Message: err.Error()})
+		}
+		if len(a.StandardJSONInput) > 0 && !json.Valid(a.StandardJSONInput) {
+			errs = append(errs, validation.FieldError{Field: field + ".standardJsonInput", Message: "invalid JSON"})
+		}
+		if a.Compiler != nil && a.Compiler.Version == "" {
+			errs = append(errs, validation.FieldError{Field: field + ".compiler.version", Message: "is required when compiler is set"})
+		}
+	}
+	if errs != nil {
+		return errs
+	}
+	return nil
+}
+
+// validateHexField checks that an optional bytecode string is 0x-prefixed hex.
+func validateHexField(s string) error {
+	if s == "" {
+		return nil
+	}
+	if !strings.HasPrefix(s, "0x") {
+		return errors.New("must start with 0x")
+	}
+	for _, c := range s[2:] {
+		isDigit := c >= '0' && c <= '9'
+		isLowerHex := c >= 'a' && c <= 'f'
+		isUpperHex := c >= 'A' && c <= 'F'
+		if !isDigit && !isLowerHex && !isUpperHex {
+			return errors.New("contains non-hex characters")
+		}
+	}
+	return nil
+}
+
+// strictUnmarshal decodes JSON and rejects unknown fields so typos are reported.
+func strictUnmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// describeJSONError rewrites decoder errors to name the offending field.
+func describeJSONError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return fmt.Errorf("%s: expected %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	}
+	if msg := err.Error(); strings.HasPrefix(msg, "json: unknown field ") {
+		return fmt.Errorf("unknown field %s", strings.TrimPrefix(msg, "json: unknown field "))
+	}
+	return err
+}
@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/validation"
+)
+
+func TestDecodePublishPayload(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantArtifacts  int
+		wantChain      string
+		wantErrContain string
+	}{
+		{
+			name:          "full request",
+			input:         `{"chain":"evm","builder":"hardhat","artifacts":[{"name":"Token","sourcePath":"src/Token.sol"}]}`,
+			wantArtifacts: 1,
+			wantChain:     "evm",
+		},
+		{
+			name:          "single artifact",
+			input:         `{"name":"Token","sourcePath":"src/Token.sol","bytecode":"0x6080"}`,
+			wantArtifacts: 1,
+		},
+		{
+			name:           "empty input",
+			input:          "  \n",
+			wantErrContain: "no input",
+		},
+		{
+			name:           "not JSON",
+			input:          "not json",
+			wantErrContain: "invalid publish payload",
+		},
+		{
+			name:           "wrong type names field",
+			input:          `{"artifacts":[{"name":42}]}`,
+			wantErrContain: "name: expected string",
+		},
+		{
+			name:           "unknown field",
+			input:          `{"name":"Token","bytcode":"0x"}`,
+			wantErrContain: `unknown field "bytcode"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := decodePublishPayload([]byte(tt.input))
+			if tt.wantErrContain != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrContain)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, req.Artifacts, tt.wantArtifacts)
+			assert.Equal(t, tt.wantChain, req.Chain)
+		})
+	}
+}
+
+func TestValidatePublishPayload(t *testing.T) {
+	valid := func() *PublishRequest {
+		return &PublishRequest{Artifacts: []PublishArtifact{{
+			Name:     "Token",
+			ABI:      json.RawMessage(`[]`),
+			Bytecode: "0x6080",
+		}}}
+	}
+
+	tests := []struct {
+		name           string
+		pkgName        string
+		version        string
+		mutate         func(*PublishRequest)
+		wantErrContain string
+	}{
+		{name: "valid", pkgName: "token", version: "1.0.0"},
+		{name: "bad package name", pkgName: "Token", version: "1.0.0", wantErrContain: "name:"},
+		{name: "bad version", pkgName: "token", version: "1.0", wantErrContain: "version:"},
+		{
+			name: "no artifacts", pkgName: "token", version: "1.0.0",
+			mutate:         func(r *PublishRequest) { r.Artifacts = nil },
+			wantErrContain: "artifacts: must contain at least one",
+		},
+		{
+			name: "missing artifact name", pkgName: "token", version: "1.0.0",
+			mutate:         func(r *PublishRequest) { r.Artifacts[0].Name = "" },
+			wantErrContain: "artifacts[0].name",
+		},
+		{
+			name: "abi not array", pkgName: "token", version: "1.0.0",
+			mutate:         func(r *PublishRequest) { r.Artifacts[0].ABI = json.RawMessage(`{}`) },
+			wantErrContain: "artifacts[0].abi: ABI must be a JSON array",
+		},
+		{
+			name: "abi entry invalid", pkgName: "token", version: "1.0.0",
+			mutate:         func(r *PublishRequest) { r.Artifacts[0].ABI = json.RawMessage(`[{"type":"function"}]`) },
+			wantErrContain: "artifacts[0].abi: abi[0]",
+		},
+		{
+			name: "bytecode not hex", pkgName: "token", version: "1.0.0",
+			mutate:         func(r *PublishRequest) { r.Artifacts[0].Bytecode = "0xzz" },
+			wantErrContain: "artifacts[0].bytecode",
+		},
+		{
+			name: "deployed bytecode without prefix", pkgName: "token", version: "1.0.0",
+			mutate:         func(r *PublishRequest) { r.Artifacts[0].DeployedBytecode = "6080" },
+			wantErrContain: "artifacts[0].deployedBytecode: must start with 0x",
+		},
+		{
+			name: "standard json input invalid", pkgName: "token", version: "1.0.0",
+			mutate:         func(r *PublishRequest) { r.Artifacts[0].StandardJSONInput = json.RawMessage(`{`) },
+			wantErrContain: "artifacts[0].standardJsonInput",
+		},
+		{
+			name: "compiler without version", pkgName: "token", version: "1.0.0",
+			mutate:         func(r *PublishRequest) { r.Artifacts[0].Compiler = &CompilerInfo{} },
+			wantErrContain: "artifacts[0].compiler.version",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			if tt.mutate != nil {
+				tt.mutate(req)
+			}
+			err := validatePublishPayload(tt.pkgName, tt.version, req)
+			if tt.wantErrContain != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrContain)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "evm", req.Chain)
+			assert.Equal(t, "foundry", req.Builder)
+		})
+	}
+
+	t.Run("reports every bad field", func(t *testing.T) {
+		req := valid()
+		req.Artifacts[0].ABI = json.RawMessage(`{}`)
+		req.Artifacts[0].Bytecode = "zz"
+		err := validatePublishPayload("token", "1.0.0", req)
+		var fieldErrs validation.FieldErrors
+		require.ErrorAs(t, err, &fieldErrs)
+		assert.Len(t, fieldErrs, 2)
+	})
+}
+
+func TestRunPublishStdin(t *testing.T) {
+	var gotReq PublishRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/limits" {
+			json.NewEncoder(w).Encode(map[string]int{"maxArtifactsPerPublish": 1})
+			return
+		}
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/packages/my-pkg/1.0.0", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &gotReq))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	oldServer := server
+	server = ts.URL
+	defer func() { server = oldServer }()
+
+	input := `{"name":"Token","sourcePath":"src/Token.sol","bytecode":"0x6080","metadata":{"audit":"pending","auditor":"acme"}}`
+	err := runPublishStdin(strings.NewReader(input), "my-pkg", "1.0.0", "proj", "", false, []string{"team=core"}, []string{"defi"}, []string{"Token:audit=passed"}, nil, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, "evm", gotReq.Chain)
+	assert.Equal(t, "proj", gotReq.Project)
+	assert.Equal(t, "core", gotReq.Metadata["team"])
+	assert.Equal(t, []string{"defi"}, gotReq.Tags)
+	require.Len(t, gotReq.Artifacts, 1)
+	assert.Equal(t, "Token", gotReq.Artifacts[0].Name)
+	assert.Equal(t, map[string]string{"audit": "passed", "auditor": "acme"}, gotReq.Artifacts[0].Metadata, "--contract-metadata overrides the payload's")
+
+	t.Run("unknown contract metadata", func(t *testing.T) {
+		err := runPublishStdin(strings.NewReader(input), "my-pkg", "1.0.0", "", "", false, nil, nil, []string{"Vault:audit=passed"}, nil, 0)
+		assert.ErrorContains(t, err, "--contract-metadata: Vault not among the contracts being published")
+	})
+
+	t.Run("exceeds server artifact limit", func(t *testing.T) {
+		input := `{"artifacts":[{"name":"A"},{"name":"B"}]}`
+		err := runPublishStdin(strings.NewReader(input), "my-pkg", "1.0.0", "", "", false, nil, nil, nil, nil, 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "at most 1")
+	})
+
+	t.Run("chain flag overrides the payload's", func(t *testing.T) {
+		input := `{"chain":"evm","artifacts":[{"name":"Token"}]}`
+		require.NoError(t, runPublishStdin(strings.NewReader(input), "my-pkg", "1.0.0", "", "solana", false, nil, nil, nil, nil, 0))
+		assert.Equal(t, "solana", gotReq.Chain)
+
+		err := runPublishStdin(strings.NewReader(input), "my-pkg", "1.0.0", "", "cosmos", false, nil, nil, nil, nil, 0)
+		assert.ErrorContains(t, err, `unknown chain "cosmos" (expected one of: evm, solana)`)
+	})
+
+	t.Run("invalid request is rejected before sending", func(t *testing.T) {
+		for _, input := range []string{`{"artifacts":[]}`, `{"artifacts":[{"name":""}]}`} {
+			err := runPublishStdin(strings.NewReader(input), "my-pkg", "1.0.0", "", "", false, nil, nil, nil, nil, 0)
+			var fieldErrs validation.FieldErrors
+			assert.ErrorAs(t, err, &fieldErrs, input)
+		}
+
+		err := runPublishStdin(strings.NewReader(input), "my-pkg", "1.0", "", "", false, nil, nil, nil, nil, 0)
+		assert.ErrorContains(t, err, "version:")
+
+		// Dry runs are validated too
+		err = runPublishStdin(strings.NewReader(`{"name":"Token","abi":{},"bytecode":"zz"}`), "my-pkg", "1.0.0", "", "", true, nil, nil, nil, nil, 0)
+		assert.ErrorContains(t, err, "artifacts[0].abi")
+		assert.ErrorContains(t, err, "artifacts[0].bytecode")
+	})
+
+	t.Run("requires name", func(t *testing.T) {
+		err := runPublishStdin(strings.NewReader(input), "", "1.0.0", "", "", false, nil, nil, nil, nil, 0)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--name")
+	})
+}
+
+func TestCheckArtifactLimit(t *testing.T) {
+	var limitsCalls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limitsCalls++
+		json.NewEncoder(w).Encode(map[string]int{"maxArtifactsPerPublish": 2})
+	}))
+	defer ts.Close()
+
+	t.Run("--max-artifacts caps the total", func(t *testing.T) {
+		assert.ErrorContains(t, checkArtifactLimit(ts.URL, 120, 1, 100), "120 artifacts, more than --max-artifacts 100")
+		assert.NoError(t, checkArtifactLimit(ts.URL, 120, 1, 0), "0 is no cap")
+	})
+
+	t.Run("one artifact per request skips the server", func(t *testing.T) {
+		limitsCalls = 0
+		assert.NoError(t, checkArtifactLimit(ts.URL, 500, 1, 0))
+		assert.Zero(t, limitsCalls)
+	})
+
+	t.Run("server limit per request", func(t *testing.T) {
+		assert.NoError(t, checkArtifactLimit(ts.URL, 2, 2, 0))
+		assert.ErrorContains(t, checkArtifactLimit(ts.URL, 3, 3, 0), "allows at most 2 per publish")
+	})
+
+	t.Run("server without limits", func(t *testing.T) {
+		assert.NoError(t, checkArtifactLimit("http://127.0.0.1:1", 3, 3, 0))
+	})
+}
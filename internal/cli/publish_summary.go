@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Publish statuses recorded in the --summary-out file
+const (
+	summaryStatusPublished = "published"
+	summaryStatusFailed    = "failed"
+	summaryStatusDryRun    = "dry-run"
+	summaryStatusExists    = "exists" // with --skip-existing: already in the registry
+
+	// Only with --batch: not published because another package in the batch failed
+	summaryStatusRolledBack = "rolled-back"
+	summaryStatusSkipped    = "skipped"
+)
+
+// publishSummary is the machine-readable result of a publish run, written by
+// --summary-out for CI steps that act on what was published.
+type publishSummary struct {
+	Server    string                `json:"server"`
+	Version   string                `json:"version"`
+	DryRun    bool                  `json:"dryRun"`
+	Published int                   `json:"published"`
+	Failed    int                   `json:"failed"`
+	Existing  int                   `json:"existing"`
+	Packages  []publishSummaryEntry `json:"packages"`
+}
+
+// publishSummaryEntry is the outcome for one package.
+type publishSummaryEntry struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	Contract   string `json:"contract"`
+	SourcePath string `json:"sourcePath"`
+	Dependency bool   `json:"dependency"`
+	Status     string `json:"status"` // published, failed, dry-run, exists, rolled-back or skipped
+	Error      string `json:"error,omitempty"`
+}
+
+// record sets the outcome of entry i from its publish error.
+func (s *publishSummary) record(i int, err error) {
+	if errors.Is(err, errVersionExists) {
+		s.Packages[i].Status = summaryStatusExists
+		s.Existing++
+		return
+	}
+	if err != nil {
+		s.Packages[i].Status = summaryStatusFailed
+		s.Packages[i].Error = err.Error()
+		s.Failed++
+		return
+	}
+	s.Packages[i].Status = summaryStatusPublished
+	s.Published++
+}
+
+// writePublishSummary writes the summary to path. An empty path is a no-op.
+func writePublishSummary(path string, s *publishSummary) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing publish summary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/chains"
+	"github.com/pendergraft/contrafactory/internal/chains/evm/foundry"
+)
+
+// writeFoundryProject creates a minimal built Foundry project without out/build-info.
+func writeFoundryProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foundry.toml"), []byte("[profile.default]\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "out", "Token.sol"), 0755))
+
+	artifact := map[string]any{
+		"abi":              []map[string]any{{"type": "function", "name": "transfer"}},
+		"bytecode":         map[string]any{"object": "0x6080"},
+		"deployedBytecode": map[string]any{"object": "0x6080"},
+		"rawMetadata":      `{"compiler":{"version":"0.8.28+commit.7893614a"},"settings":{"compilationTarget":{"src/Token.sol":"Token"}}}`,
+	}
+	data, err := json.Marshal(artifact)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "out", "Token.sol", "Token.json"), data, 0644))
+	return dir
+}
+
+func TestDiscoverPackages_NoVerify(t *testing.T) {
+	dir := writeFoundryProject(t)
+
+	t.Run("missing build-info fails by default", func(t *testing.T) {
+		_, err := discoverPackages(dir, "", nil, nil, nil, nil, nil, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "build-info")
+	})
+
+	t.Run("missing build-info is tolerated with no-verify", func(t *testing.T) {
+		discovered, err := discoverPackages(dir, "", nil, nil, nil, nil, nil, true)
+		require.NoError(t, err)
+		require.Len(t, discovered, 1)
+		assert.Equal(t, "token", discovered[0].Name)
+
+		pa := buildEVMPublishArtifact(foundry.New(), dir, discovered[0], true, nil)
+		assert.Equal(t, "Token", pa.Name)
+		assert.NotEmpty(t, pa.ABI)
+		assert.NotEmpty(t, pa.Bytecode)
+		assert.NotEmpty(t, pa.DeployedBytecode)
+		assert.Nil(t, pa.StandardJSONInput)
+		require.NotNil(t, pa.Compiler)
+		assert.Equal(t, "0.8.28+commit.7893614a", pa.Compiler.Version)
+	})
+}
+
+func TestResolveExcludePatterns(t *testing.T) {
+	builtins := defaultExcludePatterns
+	tests := []struct {
+		name       string
+		flag       []string
+		noDefaults bool
+		config     *ProjectConfig
+		want       []string
+	}{
+		{name: "built-ins", want: builtins},
+		{name: "flag adds to the built-ins", flag: []string{"Helper"}, want: append(slices.Clone(builtins), "Helper")},
+		{name: "no defaults", noDefaults: true, flag: []string{"Helper"}, want: []string{"Helper"}},
+		{name: "default_exclude adds to the built-ins", config: &ProjectConfig{DefaultExclude: []string{"Fixture"}}, flag: []string{"Helper"}, want: append(slices.Clone(builtins), "Fixture", "Helper")},
+		{name: "exclude replaces the built-ins", config: &ProjectConfig{Exclude: []string{"Test"}, DefaultExclude: []string{"Fixture"}}, want: []string{"Test", "Fixture"}},
+		{name: "no defaults with default_exclude", noDefaults: true, config: &ProjectConfig{DefaultExclude: []string{"Fixture"}}, want: []string{"Fixture"}},
+		{name: "nothing excluded", noDefaults: true, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resolveExcludePatterns(tt.flag, tt.noDefaults, tt.config))
+		})
+	}
+	assert.Equal(t, []string{"Test", "Script", "Mock", "Deploy", "Setup"}, defaultExcludePatterns, "built-ins are not modified")
+}
+
+func TestResolvePublishChain(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		config  *ProjectConfig
+		builder string
+		want    string
+		wantErr string
+	}{
+		{name: "project's own chain", builder: "foundry", want: "evm"},
+		{name: "from config", config: &ProjectConfig{Chain: "evm"}, builder: "foundry", want: "evm"},
+		{name: "flag overrides config", flag: "solana", config: &ProjectConfig{Chain: "evm"}, want: "solana"},
+		{name: "unknown chain", flag: "cosmos", wantErr: `unknown chain "cosmos" (expected one of: evm, solana)`},
+		{name: "builder not on chain", config: &ProjectConfig{Chain: "solana"}, builder: "foundry", wantErr: `chain "solana" does not take foundry artifacts`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolvePublishChain(tt.flag, tt.config, "evm", tt.builder)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDiscoverPackages_SameNameInTwoSources(t *testing.T) {
+	dir := writeFoundryProject(t)
+	artifact := map[string]any{
+		"abi":         []map[string]any{},
+		"bytecode":    map[string]any{"object": "0x6080"},
+		"rawMetadata": `{"settings":{"compilationTarget":{"src/v2/Token.sol":"Token"}}}`,
+	}
+	data, err := json.Marshal(artifact)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "out", "v2", "Token.sol"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "out", "v2", "Token.sol", "Token.json"), data, 0644))
+
+	_, err = discoverPackages(dir, "", nil, nil, nil, nil, nil, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "src/Token.sol:Token")
+	assert.Contains(t, err.Error(), "src/v2/Token.sol:Token")
+
+	discovered, err := discoverPackages(dir, "", nil, nil, []string{"src/v2/"}, nil, nil, true)
+	require.NoError(t, err)
+	require.Len(t, discovered, 1)
+	assert.Equal(t, "src/Token.sol", discovered[0].Artifact.EVM.SourcePath)
+
+	discovered, err = discoverPackages(dir, "", []string{"src/v2/Token.sol:Token"}, nil, nil, nil, nil, true)
+	require.NoError(t, err)
+	require.Len(t, discovered, 1)
+	assert.Equal(t, "src/v2/Token.sol", discovered[0].Artifact.EVM.SourcePath)
+}
+
+func TestNormalizePackageName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Token", "token"},
+		{"PredicateRegistry", "predicate-registry"},
+		{"myToken", "my-token"},
+		{"ERC20", "erc20"},
+		{"IERC20", "ierc20"},
+		{"ERC20Token", "erc20-token"},
+		{"ERC1155Supply", "erc1155-supply"},
+		{"ERC20USDC", "erc20-usdc"},
+		{"ERC721A", "erc721a"},
+		{"V2Router", "v2-router"},
+		{"UniswapV3Pool", "uniswap-v3-pool"},
+		{"L2ToL1Bridge", "l2-to-l1-bridge"},
+		{"Uint256Math", "uint256-math"},
+		{"Vault4626x", "vault4626x"},
+		{"JSONParser", "json-parser"},
+		{"ABCDef", "abc-def"},
+		{"TokenUSDC", "token-usdc"},
+		{"PriceFeedETHUSD", "price-feed-ethusd"},
+		{"USDC", "usdc"},
+		{"Ownable_Upgradeable", "ownable-upgradeable"},
+		{"__Base__", "base"},
+		{"Token$Helper", "token-helper"},
+		{"Ä", "ä"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, normalizePackageName(tt.name))
+		})
+	}
+}
+
+func TestValidateContractKinds(t *testing.T) {
+	assert.NoError(t, validateContractKinds(nil))
+	assert.NoError(t, validateContractKinds([]string{"abstract", "Library", "interface", "contract"}))
+
+	err := validateContractKinds([]string{"abstract", "mock"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"mock"`)
+}
+
+func TestParseStandardJSONOverrides(t *testing.T) {
+	overrides, err := parseStandardJSONOverrides([]string{"Token=./token.json", " Vault = vault.json "})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Token": "./token.json", "Vault": "vault.json"}, overrides)
+
+	for _, bad := range [][]string{{"Token"}, {"=token.json"}, {"Token="}, {"Token=a.json", "Token=b.json"}} {
+		_, err := parseStandardJSONOverrides(bad)
+		assert.Error(t, err, "%v", bad)
+	}
+}
+
+func TestParseContractMetadata(t *testing.T) {
+	metadata, err := parseContractMetadata([]string{"Token:audit=passed", " Token : auditor = acme ", "Vault:note=a:b=c"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]string{
+		"Token": {"audit": "passed", "auditor": "acme"},
+		"Vault": {"note": "a:b=c"},
+	}, metadata)
+
+	for _, bad := range []string{"audit=passed", ":audit=passed", "Token:audit", "Token:=passed"} {
+		_, err := parseContractMetadata([]string{bad})
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestLoadStandardJSONOverride(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	valid := write("token.json", `{"language":"Solidity","sources":{"src/Token.sol":{"content":"contract Token {}"}}}`)
+	data, err := loadStandardJSONOverride(valid, "src/Token.sol")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"language":"Solidity","sources":{"src/Token.sol":{"content":"contract Token {}"}}}`, string(data))
+
+	_, err = loadStandardJSONOverride(valid, "src/Vault.sol")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "src/Vault.sol")
+
+	_, err = loadStandardJSONOverride(write("broken.json", `{"sources":`), "src/Token.sol")
+	assert.Error(t, err)
+
+	_, err = loadStandardJSONOverride(write("empty.json", `{"language":"Solidity"}`), "src/Token.sol")
+	assert.Error(t, err)
+
+	_, err = loadStandardJSONOverride(filepath.Join(dir, "missing.json"), "src/Token.sol")
+	assert.Error(t, err)
+}
+
+func TestBuildEVMPublishArtifact_StandardJSONOverride(t *testing.T) {
+	dir := writeFoundryProject(t)
+	discovered, err := discoverPackages(dir, "", nil, nil, nil, nil, nil, true)
+	require.NoError(t, err)
+	require.Len(t, discovered, 1)
+
+	override := json.RawMessage(`{"sources":{"src/Token.sol":{"content":"contract Token {}"}}}`)
+	pa := buildEVMPublishArtifact(foundry.New(), dir, discovered[0], false, override)
+	assert.Equal(t, override, pa.StandardJSONInput)
+}
+
+func TestSourcesFromStandardJSON(t *testing.T) {
+	sources, err := sourcesFromStandardJSON([]byte(`{"language":"Solidity","sources":{"src/Token.sol":{"content":"contract Token {}"},"lib/A.sol":{"content":""}}}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"src/Token.sol":"contract Token {}","lib/A.sol":""}`, string(sources))
+
+	_, err = sourcesFromStandardJSON(nil)
+	assert.Error(t, err)
+	_, err = sourcesFromStandardJSON([]byte(`{"sources":{"src/Token.sol":{"urls":["bzz-raw://x"]}}}`))
+	assert.ErrorContains(t, err, "src/Token.sol")
+}
+
+func TestCompilerSettingsWarnings(t *testing.T) {
+	compiler := chains.EVMCompiler{
+		Optimizer:  chains.OptimizerConfig{Enabled: true, Runs: 200},
+		EVMVersion: "paris",
+	}
+	matching := []byte(`{"settings":{"optimizer":{"enabled":true,"runs":200},"evmVersion":"paris"}}`)
+	viaIR := []byte(`{"settings":{"optimizer":{"enabled":true,"runs":200},"evmVersion":"paris","viaIR":true}}`)
+
+	t.Run("all sources agree", func(t *testing.T) {
+		buildInfo := &chains.VerificationInput{StandardJSON: matching}
+		assert.Empty(t, compilerSettingsWarnings("Token", "per-contract standard JSON", matching, compiler, buildInfo))
+	})
+
+	t.Run("published JSON diverges from artifact and build-info", func(t *testing.T) {
+		buildInfo := &chains.VerificationInput{StandardJSON: matching}
+		warnings := compilerSettingsWarnings("Token", "per-contract standard JSON", viaIR, compiler, buildInfo)
+		require.Len(t, warnings, 2)
+		assert.Contains(t, warnings[0], "Token")
+		assert.Contains(t, warnings[0], "artifact metadata")
+		assert.Contains(t, warnings[0], "viaIR: false vs true")
+		assert.Contains(t, warnings[0], "publishing per-contract standard JSON")
+		assert.Contains(t, warnings[1], "build-info")
+	})
+
+	t.Run("build-info fallback is only compared with the artifact", func(t *testing.T) {
+		buildInfo := &chains.VerificationInput{StandardJSON: viaIR}
+		warnings := compilerSettingsWarnings("Token", "build-info", viaIR, compiler, buildInfo)
+		require.Len(t, warnings, 1)
+		assert.Contains(t, warnings[0], "publishing build-info")
+	})
+
+	t.Run("nothing published", func(t *testing.T) {
+		assert.Empty(t, compilerSettingsWarnings("Token", "build-info", nil, compiler, nil))
+	})
+}
+
+func TestMetadataWarnings(t *testing.T) {
+	dir := t.TempDir()
+	artifactPath := filepath.Join(dir, "Token.json")
+	artifact := map[string]any{
+		"bytecode":         map[string]any{"object": "0x6080"},
+		"deployedBytecode": map[string]any{"object": "0x6080"},
+		"rawMetadata":      `{"sources":{"src/Token.sol":{"keccak256":"0x00"}}}`,
+	}
+	data, err := json.Marshal(artifact)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(artifactPath, data, 0644))
+
+	stdJSON := []byte(`{"sources":{"src/Token.sol":{"content":"contract Token {}"}}}`)
+	warnings := metadataWarnings(foundry.New(), artifactPath, "Token", stdJSON)
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "src/Token.sol")
+	assert.Contains(t, warnings[1], "--standard-json Token=")
+
+	// URL-only sources can't be hashed locally, so there is nothing to report
+	assert.Empty(t, metadataWarnings(foundry.New(), artifactPath, "Token", []byte(`{"sources":{"src/Token.sol":{"urls":["ipfs://x"]}}}`)))
+
+	warnings = metadataWarnings(foundry.New(), filepath.Join(dir, "missing.json"), "Token", stdJSON)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "skipped")
+}
+
+func TestPublishConcurrently(t *testing.T) {
+	const n = 20
+	var mu sync.Mutex
+	var inFlight, peak int
+
+	var order []int
+	failures := 0
+	publishConcurrently(n, 4, func(i int) error {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+
+		// Finish in roughly reverse order to exercise reordering
+		time.Sleep(time.Duration(n-i) * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if i%5 == 0 {
+			return errors.New("boom")
+		}
+		return nil
+	}, func(i int, err error) {
+		order = append(order, i)
+		if err != nil {
+			failures++
+		}
+	})
+
+	require.Len(t, order, n)
+	for i, got := range order {
+		assert.Equal(t, i, got, "results must be reported in index order")
+	}
+	assert.Equal(t, 4, failures, "failures must not stop other packages")
+	assert.LessOrEqual(t, peak, 4)
+	assert.Greater(t, peak, 1)
+}
+
+func TestPublishConcurrently_Empty(t *testing.T) {
+	called := false
+	publishConcurrently(0, 4, func(int) error { called = true; return nil }, func(int, error) { called = true })
+	assert.False(t, called)
+}
@@ -1,42 +1,75 @@
 package cli
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
 )
 
 var (
 	cfgFile string
 	server  string
 	apiKey  string
+	profile string
+	envName string
+
+	foundryProfile string
+	timeout        time.Duration
+)
+
+// Default HTTP timeouts when --timeout isn't given. Publish requests carry whole
+// artifacts, so they get longer for slow links.
+const (
+	defaultTimeout = 30 * time.Second
+	publishTimeout = 5 * time.Minute
 )
 
 // Execute runs the CLI
-func Execute(version string) error {
+func Execute(info VersionInfo) error {
 	rootCmd := &cobra.Command{
 		Use:     "contrafactory",
 		Short:   "Smart contract artifact registry CLI",
 		Long:    `Contrafactory is a CLI for publishing, fetching, and managing smart contract artifacts.`,
-		Version: version,
+		Version: info.Version,
 	}
 
 	// Global flags
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: contrafactory.toml or cf.toml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "project config file (default: CONTRAFACTORY_CONFIG, else contrafactory.toml or cf.toml in the current directory)")
 	rootCmd.PersistentFlags().StringVar(&server, "server", "", "server URL (default from config)")
 	rootCmd.PersistentFlags().StringVar(&apiKey, "api-key", "", "API key for authentication")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "credentials profile to use (default: CONTRAFACTORY_PROFILE, else the default profile)")
+	rootCmd.PersistentFlags().StringVar(&envName, "env", "", "environment whose server to use from the project config's [servers] (default: CONTRAFACTORY_ENV)")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "timeout of each request to the server, e.g. 2m (default 30s, 5m for publish)")
+	rootCmd.PersistentFlags().StringVar(&foundryProfile, "foundry-profile", "", "foundry.toml profile to read the out and build_info_path directories from (default: FOUNDRY_PROFILE, else default)")
 
 	// Add subcommands
 	rootCmd.AddCommand(createPublishCmd())
 	rootCmd.AddCommand(createDeleteCmd())
+	rootCmd.AddCommand(createMirrorCmd())
 	rootCmd.AddCommand(createFetchCmd())
+	rootCmd.AddCommand(createABICmd())
+	rootCmd.AddCommand(createBytecodeCmd())
+	rootCmd.AddCommand(createExportCmd())
 	rootCmd.AddCommand(createListCmd())
+	rootCmd.AddCommand(createSearchCmd())
 	rootCmd.AddCommand(createInfoCmd())
 	rootCmd.AddCommand(createVerifyCmd())
 	rootCmd.AddCommand(createAuthCmd())
 	rootCmd.AddCommand(createDeploymentCmd())
 	rootCmd.AddCommand(createConfigCmd())
 	rootCmd.AddCommand(createDiscoverCmd())
+	rootCmd.AddCommand(createIdentifyCmd())
+	rootCmd.AddCommand(createOwnerCmd())
+	rootCmd.AddCommand(createAliasCmd())
+	rootCmd.AddCommand(createMineCmd())
+	rootCmd.AddCommand(createStorageDiffCmd())
+	rootCmd.AddCommand(createVersionCmd(info))
 
 	return rootCmd.Execute()
 }
@@ -53,9 +86,15 @@ func getServer() string {
 		return env
 	}
 
-	// 3. Project config file (TOML)
-	if config := loadProjectConfigSilent(); config != nil && config.Server != "" {
-		return config.Server
+	// 3. Project config file (TOML), for the selected environment if any
+	if config := loadProjectConfigSilent(); config != nil {
+		serverURL, err := config.serverFor(getEnvName())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		if serverURL != "" {
+			return serverURL
+		}
 	}
 
 	// 4. Default
@@ -74,7 +113,7 @@ func getAPIKey() string {
 		return env
 	}
 
-	// 3. Credentials file (keyed by server URL)
+	// 3. Credentials file (keyed by profile and server URL)
 	serverURL := getServer()
 	if cred := getCredential(serverURL); cred != "" {
 		return cred
@@ -82,3 +121,68 @@ func getAPIKey() string {
 
 	return ""
 }
+
+// requestTimeout returns --timeout when set, else fallback.
+func requestTimeout(fallback time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	return fallback
+}
+
+// newClient returns an API client for serverURL that honors --timeout.
+func newClient(serverURL, key string) *client.Client {
+	return client.New(serverURL, key, client.WithTimeout(requestTimeout(defaultTimeout)))
+}
+
+// httpClient returns an HTTP client honoring --timeout, for the few requests made
+// outside pkg/client.
+func httpClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout(defaultTimeout)}
+}
+
+// getProfile returns the selected credentials profile from flag or env; "" is
+// the default profile.
+func getProfile() string {
+	if profile != "" {
+		return profile
+	}
+	return os.Getenv("CONTRAFACTORY_PROFILE")
+}
+
+// getEnvName returns the selected project environment from flag or env; "" selects
+// the project config's top-level server.
+func getEnvName() string {
+	if envName != "" {
+		return envName
+	}
+	return os.Getenv("CONTRAFACTORY_ENV")
+}
+
+// serverError turns a failed API response into an error of the form
+// "CODE - message". Server-side (5xx) failures also carry the request ID so
+// users can quote it in bug reports.
+func serverError(resp *http.Response, body []byte) error {
+	var errResp struct {
+		Error struct {
+			Code      string `json:"code"`
+			Message   string `json:"message"`
+			RequestID string `json:"requestId"`
+		} `json:"error"`
+	}
+	var err error
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error.Code != "" {
+		err = fmt.Errorf("%s - %s", errResp.Error.Code, errResp.Error.Message)
+	} else {
+		err = fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+	}
+
+	requestID := errResp.Error.RequestID
+	if requestID == "" {
+		requestID = resp.Header.Get("X-Request-Id")
+	}
+	if resp.StatusCode >= 500 && requestID != "" {
+		return fmt.Errorf("%w (request ID: %s)", err, requestID)
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func createSearchCmd() *cobra.Command {
+	var opts client.ListPackagesOptions
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "search [query]",
+		Short: "Search packages by name, contract, chain or project",
+		Long: `Search the registry for packages.
+
+The query matches package names (substring, case-insensitive). Use --contract
+to find which packages contain a contract with an exact name.
+
+EXAMPLES:
+  # Packages whose name contains "token"
+  contrafactory search token
+
+  # Narrow by chain and project
+  contrafactory search token --chain evm --project myproj
+
+  # Which packages contain a contract named Vault?
+  contrafactory search --contract Vault
+
+  # Audited packages (package metadata set with publish --metadata)
+  contrafactory search --metadata audit_status=passed
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				opts.Query = args[0]
+			}
+			if opts.Query == "" && opts.Contract == "" && opts.Project == "" && opts.Chain == "" && len(opts.Metadata) == 0 {
+				return fmt.Errorf("provide a query or at least one of --contract, --project, --chain, --metadata")
+			}
+
+			c := newClient(getServer(), getAPIKey())
+			return searchPackages(c, opts, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Chain, "chain", "", "filter by chain (evm, solana)")
+	cmd.Flags().StringVar(&opts.Project, "project", "", "filter by project")
+	cmd.Flags().StringVar(&opts.Contract, "contract", "", "only packages containing a contract with this name")
+	cmd.Flags().StringToStringVar(&opts.Metadata, "metadata", nil, "only packages with this metadata, as key=value (repeatable)")
+	cmd.Flags().IntVar(&opts.Limit, "limit", 20, "maximum number of results")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+
+	return cmd
+}
+
+func searchPackages(c *client.Client, opts client.ListPackagesOptions, jsonOutput bool) error {
+	resp, err := c.ListPackagesWithOptions(context.Background(), opts)
+	if err != nil {
+		return fmt.Errorf("failed to search packages: %w", err)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{
+			"packages":   resp.Data,
+			"count":      len(resp.Data),
+			"hasMore":    resp.Pagination.HasMore,
+			"nextCursor": resp.Pagination.NextCursor,
+		})
+	}
+
+	if len(resp.Data) == 0 {
+		fmt.Println("No matching packages")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCHAIN\tBUILDER\tLATEST\tCONTRACTS")
+	for _, p := range resp.Data {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.Name, p.Chain, p.Builder, findLatestVersion(p.Versions), strings.Join(p.Contracts, ", "))
+	}
+	w.Flush()
+
+	if resp.Pagination.HasMore {
+		fmt.Printf("\n(showing %d matches, more available; refine the search or raise --limit)\n", len(resp.Data))
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+	"github.com/pendergraft/contrafactory/pkg/evmutil"
+)
+
+// errUpgradeUnsafe is returned by storage-diff so scripts and CI fail on unsafe upgrades.
+var errUpgradeUnsafe = errors.New("storage layout is not upgrade safe")
+
+func createStorageDiffCmd() *cobra.Command {
+	var contract string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "storage-diff <old-ref> <new-ref>",
+		Short: "Check whether a new version's storage layout is upgrade safe",
+		Long: `Compare the storage layouts of a contract in two package versions, for
+upgrading the implementation behind a proxy.
+
+Reports variables that moved, changed type at the same slot, were removed or were
+inserted over existing storage (unsafe), and variables appended after existing
+storage or placed in a shrunk __gap (safe). Exits non-zero when the upgrade is unsafe.
+
+References are package@version or package/Contract@version; versions may be
+"latest" or ranges. Both versions must have been published with storage layouts.
+
+EXAMPLES:
+  contrafactory storage-diff my-vault@1.0.0 my-vault@2.0.0 --contract Vault
+  contrafactory storage-diff my-vault/Vault@1.0.0 my-vault/Vault@latest --json
+`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newClient(getServer(), getAPIKey())
+			return runStorageDiff(cmd.OutOrStdout(), c, args[0], args[1], contract, jsonOutput)
+		},
+	}
+
+	cmd.Flags().StringVar(&contract, "contract", "", "contract to compare (unless given in the references)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+
+	return cmd
+}
+
+func runStorageDiff(out io.Writer, c *client.Client, oldRef, newRef, contract string, jsonOutput bool) error {
+	ctx := context.Background()
+
+	older, oldLayout, err := fetchStorageLayout(ctx, c, oldRef, contract)
+	if err != nil {
+		return err
+	}
+	newer, newLayout, err := fetchStorageLayout(ctx, c, newRef, contract)
+	if err != nil {
+		return err
+	}
+
+	diff, err := evmutil.DiffStorageLayouts(oldLayout, newLayout)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		changes := make([]map[string]any, len(diff.Changes))
+		for i, ch := range diff.Changes {
+			changes[i] = map[string]any{"kind": ch.Kind, "label": ch.Label, "safe": ch.Safe, "message": ch.Message}
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]any{
+			"old":     older,
+			"new":     newer,
+			"safe":    diff.Safe,
+			"changes": changes,
+		}); err != nil {
+			return err
+		}
+	} else {
+		printStorageDiff(out, older, newer, diff)
+	}
+
+	if !diff.Safe {
+		return errUpgradeUnsafe
+	}
+	return nil
+}
+
+// fetchStorageLayout resolves ref (and contract, when ref doesn't name one) and
+// downloads its storage layout.
+func fetchStorageLayout(ctx context.Context, c *client.Client, ref, contract string) (contractRef, *evmutil.StorageLayout, error) {
+	resolved, err := resolveContractRef(ctx, c, ref, contract)
+	if err != nil {
+		return contractRef{}, nil, err
+	}
+
+	data, err := c.GetStorageLayout(ctx, resolved.Package, resolved.Version, resolved.Contract)
+	if err != nil {
+		return resolved, nil, fmt.Errorf("fetching storage layout of %s: %w", resolved, err)
+	}
+	layout, err := evmutil.ParseStorageLayout(data)
+	if err != nil {
+		return resolved, nil, fmt.Errorf("%s: %w", resolved, err)
+	}
+	return resolved, layout, nil
+}
+
+func printStorageDiff(out io.Writer, older, newer contractRef, diff *evmutil.StorageDiff) {
+	fmt.Fprintf(out, "🔍 Storage layout %s/%s: %s → %s\n", older.Package, older.Contract, older.Version, newer.Version)
+	if newer.Package != older.Package || newer.Contract != older.Contract {
+		fmt.Fprintf(out, "   (compared against %s/%s)\n", newer.Package, newer.Contract)
+	}
+	fmt.Fprintln(out)
+
+	if len(diff.Changes) == 0 {
+		fmt.Fprintln(out, "   No storage changes")
+	}
+	for _, ch := range diff.Changes {
+		mark := "❌"
+		if ch.Safe {
+			mark = "✅"
+		}
+		fmt.Fprintf(out, "   %s %s\n", mark, ch.Message)
+	}
+	fmt.Fprintln(out)
+
+	if diff.Safe {
+		fmt.Fprintln(out, "✅ UPGRADE SAFE")
+	} else {
+		fmt.Fprintln(out, "❌ UPGRADE UNSAFE")
+	}
+}
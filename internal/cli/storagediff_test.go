@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func TestRunStorageDiff(t *testing.T) {
+	const types = `"types":{"t_uint256":{"label":"uint256","numberOfBytes":"32"},"t_address":{"label":"address","numberOfBytes":"20"}}`
+	layouts := map[string]string{
+		"1.0.0": `{"storage":[{"label":"owner","offset":0,"slot":"0","type":"t_address"},{"label":"total","offset":0,"slot":"1","type":"t_uint256"}],` + types + `}`,
+		"1.1.0": `{"storage":[{"label":"owner","offset":0,"slot":"0","type":"t_address"},{"label":"total","offset":0,"slot":"1","type":"t_uint256"},{"label":"cap","offset":0,"slot":"2","type":"t_uint256"}],` + types + `}`,
+		"2.0.0": `{"storage":[{"label":"total","offset":0,"slot":"0","type":"t_uint256"},{"label":"owner","offset":0,"slot":"1","type":"t_address"}],` + types + `}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for version, layout := range layouts {
+			if r.URL.Path == "/api/v1/packages/my-vault/"+version+"/contracts/Vault/storage-layout" {
+				w.Write([]byte(layout))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"Artifact not found"}}`))
+	}))
+	defer srv.Close()
+	c := client.New(srv.URL, "")
+
+	t.Run("safe", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, runStorageDiff(&out, c, "my-vault@1.0.0", "my-vault@1.1.0", "Vault", false))
+		assert.Contains(t, out.String(), "cap (uint256) appended at slot 2")
+		assert.Contains(t, out.String(), "UPGRADE SAFE")
+	})
+
+	t.Run("unsafe", func(t *testing.T) {
+		var out bytes.Buffer
+		err := runStorageDiff(&out, c, "my-vault/Vault@1.0.0", "my-vault/Vault@2.0.0", "", false)
+		assert.ErrorIs(t, err, errUpgradeUnsafe)
+		assert.Contains(t, out.String(), "owner moved from slot 0 offset 0 to slot 1 offset 0")
+		assert.Contains(t, out.String(), "UPGRADE UNSAFE")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, runStorageDiff(&out, c, "my-vault@1.0.0", "my-vault@1.1.0", "Vault", true))
+		assert.Contains(t, out.String(), `"safe": true`)
+		assert.Contains(t, out.String(), `"kind": "appended"`)
+	})
+
+	t.Run("missing layout", func(t *testing.T) {
+		err := runStorageDiff(new(bytes.Buffer), c, "my-vault@1.0.0", "my-vault@3.0.0", "Vault", false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "my-vault/Vault@3.0.0")
+	})
+
+	t.Run("contract required", func(t *testing.T) {
+		err := runStorageDiff(new(bytes.Buffer), c, "my-vault@1.0.0", "my-vault@1.1.0", "", false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "contract name required")
+	})
+}
@@ -7,8 +7,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/internal/verification/blockscout"
+	"github.com/pendergraft/contrafactory/pkg/client"
+	"github.com/pendergraft/contrafactory/pkg/evmutil"
 )
 
 func createVerifyCmd() *cobra.Command {
@@ -16,6 +23,12 @@ func createVerifyCmd() *cobra.Command {
 	var chainID int
 	var address string
 	var rpcURL string
+	var local bool
+	var bytecodeFile string
+	var useBlockscout bool
+	var instance string
+	var wait bool
+	var waitTimeout time.Duration
 
 	cmd := &cobra.Command{
 		Use:   "verify",
@@ -38,19 +51,70 @@ EXAMPLES:
     --chain-id 1 \
     --address 0x1234... \
     --rpc https://eth-mainnet.example.com
+
+  # Compare on-chain bytecode saved to a file, without the server calling an RPC
+  contrafactory verify --local --bytecode-file onchain.hex my-token/Token@1.0.0
+
+  # Verify the source on a Blockscout explorer using the stored Standard JSON Input
+  contrafactory verify my-token/Token@1.0.0 \
+    --blockscout --instance https://eth.blockscout.com \
+    --chain-id 1 \
+    --address 0x1234...
+
+  # In CI, block until the deployment is recorded as verified on an explorer
+  contrafactory verify my-token/Token@1.0.0 --chain-id 1 --address 0x1234... \
+    --wait --wait-timeout 10m
 `,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runVerify(pkg, chainID, address, rpcURL)
+			if len(args) == 1 {
+				if pkg != "" {
+					return fmt.Errorf("specify the package either as an argument or with --package, not both")
+				}
+				pkg = args[0]
+			}
+			if pkg == "" {
+				return fmt.Errorf("package is required (package/contract@version)")
+			}
+			if local {
+				if wait {
+					return fmt.Errorf("--wait cannot be used with --local")
+				}
+				if bytecodeFile == "" {
+					return fmt.Errorf("--bytecode-file is required with --local")
+				}
+				return runVerifyLocal(cmd.OutOrStdout(), pkg, bytecodeFile)
+			}
+			if chainID == 0 || address == "" {
+				return fmt.Errorf("--chain-id and --address are required (or use --local --bytecode-file)")
+			}
+			var err error
+			if useBlockscout {
+				if instance == "" {
+					return fmt.Errorf("--instance is required with --blockscout")
+				}
+				err = runVerifyBlockscout(cmd.OutOrStdout(), blockscout.New(instance), pkg, chainID, address)
+			} else {
+				err = runVerify(pkg, chainID, address, rpcURL, wait)
+			}
+			if err != nil || !wait {
+				return err
+			}
+			c := newClient(getServer(), getAPIKey())
+			return waitForVerification(context.Background(), cmd.OutOrStdout(), c, fmt.Sprintf("%d", chainID), address, waitTimeout, verificationPollInterval)
 		},
 	}
 
-	cmd.Flags().StringVar(&pkg, "package", "", "package/contract@version (required)")
-	cmd.Flags().IntVar(&chainID, "chain-id", 0, "chain ID (required)")
-	cmd.Flags().StringVar(&address, "address", "", "contract address (required)")
+	cmd.Flags().StringVar(&pkg, "package", "", "package/contract@version")
+	cmd.Flags().IntVar(&chainID, "chain-id", 0, "chain ID (required unless --local)")
+	cmd.Flags().StringVar(&address, "address", "", "contract address (required unless --local)")
 	cmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL (optional, uses default for chain)")
-	_ = cmd.MarkFlagRequired("package")
-	_ = cmd.MarkFlagRequired("chain-id")
-	_ = cmd.MarkFlagRequired("address")
+	cmd.Flags().BoolVar(&local, "local", false, "compare against a local bytecode file instead of asking the server to fetch it")
+	cmd.Flags().StringVar(&bytecodeFile, "bytecode-file", "", "file with the on-chain (runtime) bytecode as hex, for --local")
+	cmd.Flags().BoolVar(&useBlockscout, "blockscout", false, "verify the source on a Blockscout explorer")
+	cmd.Flags().StringVar(&instance, "instance", "", "Blockscout instance URL, for --blockscout (e.g. https://eth.blockscout.com)")
+	cmd.Flags().BoolVar(&wait, "wait", false, "after checking, wait until the deployment is recorded as verified on an explorer")
+	cmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 5*time.Minute, "how long --wait waits before failing")
 
 	return cmd
 }
@@ -67,16 +131,21 @@ type VerifyRequest struct {
 
 // VerifyResponse is the response from the verify endpoint
 type VerifyResponse struct {
-	Match   bool   `json:"match"`
-	Type    string `json:"type"` // "full", "partial", "none"
-	Message string `json:"message,omitempty"`
-	Details struct {
-		DeployedLength int `json:"deployedLength,omitempty"`
-		ArtifactLength int `json:"artifactLength,omitempty"`
+	Success   bool   `json:"success"`
+	MatchType string `json:"matchType"` // "full", "partial", "none", "clone", "pending"
+	Message   string `json:"message,omitempty"`
+	Details   struct {
+		DivergenceOffset *int `json:"divergenceOffset,omitempty"`
+		MetadataOffset   *int `json:"metadataOffset,omitempty"`
+		MetadataLength   int  `json:"metadataLength,omitempty"`
 	} `json:"details,omitempty"`
+	CheckedAt string `json:"checkedAt,omitempty"`
+	Cached    bool   `json:"cached,omitempty"`
 }
 
-func runVerify(pkgRef string, chainID int, address, rpcURL string) error {
+// runVerify asks the server to compare the on-chain bytecode with the artifact. With
+// strict, a mismatch is an error.
+func runVerify(pkgRef string, chainID int, address, rpcURL string, strict bool) error {
 	// Parse package reference
 	name, version, contract, err := parsePackageRef(pkgRef)
 	if err != nil {
@@ -118,7 +187,7 @@ func runVerify(pkgRef string, chainID int, address, rpcURL string) error {
 		httpReq.Header.Set("X-API-Key", key)
 	}
 
-	resp, err := http.DefaultClient.Do(httpReq)
+	resp, err := httpClient().Do(httpReq)
 	if err != nil {
 		return fmt.Errorf("verification request failed: %w", err)
 	}
@@ -127,13 +196,7 @@ func runVerify(pkgRef string, chainID int, address, rpcURL string) error {
 	body, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		var errResp map[string]any
-		if json.Unmarshal(body, &errResp) == nil {
-			if errObj, ok := errResp["error"].(map[string]any); ok {
-				return fmt.Errorf("verification failed: %s - %s", errObj["code"], errObj["message"])
-			}
-		}
-		return fmt.Errorf("verification failed with status %d: %s", resp.StatusCode, string(body))
+		return fmt.Errorf("verification failed: %w", serverError(resp, body))
 	}
 
 	var result VerifyResponse
@@ -142,28 +205,220 @@ func runVerify(pkgRef string, chainID int, address, rpcURL string) error {
 	}
 
 	fmt.Println()
+	printVerifyResult(os.Stdout, result.MatchType, result.Success, result.Message)
+	divergence, metadataOffset := -1, -1
+	if result.Details.DivergenceOffset != nil {
+		divergence = *result.Details.DivergenceOffset
+	}
+	if result.Details.MetadataOffset != nil {
+		metadataOffset = *result.Details.MetadataOffset
+	}
+	printDivergence(os.Stdout, result.MatchType, divergence, metadataOffset, result.Details.MetadataLength)
+	if result.Cached {
+		fmt.Printf("   (cached result from %s)\n", result.CheckedAt)
+	}
+	if strict && (result.MatchType == evmutil.MatchNone || result.MatchType == evmutil.MatchClone && !result.Success) {
+		return fmt.Errorf("deployed bytecode does not match %s/%s@%s", name, contract, version)
+	}
+	return nil
+}
+
+// verificationPollInterval is how often verify --wait checks the verification status.
+const verificationPollInterval = 5 * time.Second
+
+// spinnerFrames animate the verify --wait progress line.
+var spinnerFrames = []string{"|", "/", "-", "\\"}
 
-	switch result.Type {
-	case "full":
-		fmt.Println("✅ VERIFIED - Full match")
-		fmt.Println("   Deployed bytecode exactly matches the artifact (including metadata)")
-	case "partial":
-		fmt.Println("✅ VERIFIED - Partial match")
-		fmt.Println("   Executable code matches, but metadata differs")
-		fmt.Println("   (This can happen with different source paths or comments)")
-	case "none":
-		fmt.Println("❌ NOT VERIFIED - No match")
-		fmt.Println("   Deployed bytecode does not match the artifact")
-		if result.Message != "" {
-			fmt.Printf("   Reason: %s\n", result.Message)
+// waitForVerification polls the deployment's verification status until it is
+// verified, failing when timeout elapses or the deployment isn't recorded.
+func waitForVerification(ctx context.Context, out io.Writer, c *client.Client, chainID, address string, timeout, interval time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	timedOut := func() error {
+		fmt.Fprintln(out)
+		return fmt.Errorf("timed out after %s waiting for %s on chain %s to be verified", timeout, address, chainID)
+	}
+
+	start := time.Now()
+	for i := 0; ; i++ {
+		status, err := c.GetVerificationStatus(ctx, chainID, address)
+		switch {
+		case ctx.Err() != nil:
+			return timedOut()
+		case client.IsNotFound(err):
+			return fmt.Errorf("no deployment recorded at %s on chain %s (record it with 'contrafactory deployment record')", address, chainID)
+		case err != nil:
+			return fmt.Errorf("checking verification status: %w", err)
+		case status.Verified:
+			fmt.Fprintf(out, "\r✅ Verified on %s\n", strings.Join(status.VerifiedOn, ", "))
+			return nil
+		}
+
+		fmt.Fprintf(out, "\r%s Waiting for verification (%s)", spinnerFrames[i%len(spinnerFrames)], time.Since(start).Round(time.Second))
+		select {
+		case <-ctx.Done():
+			return timedOut()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// printVerifyResult prints a full/partial/none/clone verification outcome.
+func printVerifyResult(out io.Writer, matchType string, match bool, message string) {
+	switch matchType {
+	case evmutil.MatchFull:
+		fmt.Fprintln(out, "✅ VERIFIED - Full match")
+		fmt.Fprintln(out, "   Deployed bytecode exactly matches the artifact (including metadata)")
+	case evmutil.MatchPartial:
+		fmt.Fprintln(out, "✅ VERIFIED - Partial match")
+		fmt.Fprintln(out, "   Executable code matches, but metadata differs")
+		fmt.Fprintln(out, "   (This can happen with different source paths or comments)")
+	case evmutil.MatchNone:
+		fmt.Fprintln(out, "❌ NOT VERIFIED - No match")
+		fmt.Fprintln(out, "   Deployed bytecode does not match the artifact")
+		if message != "" {
+			fmt.Fprintf(out, "   Reason: %s\n", message)
+		}
+	case evmutil.MatchClone:
+		if match {
+			fmt.Fprintln(out, "✅ VERIFIED - Minimal proxy (EIP-1167) clone")
+		} else {
+			fmt.Fprintln(out, "❌ NOT VERIFIED - Minimal proxy (EIP-1167) clone")
+		}
+		if message != "" {
+			fmt.Fprintf(out, "   %s\n", message)
 		}
 	default:
-		if result.Match {
-			fmt.Println("✅ VERIFIED")
+		if match {
+			fmt.Fprintln(out, "✅ VERIFIED")
 		} else {
-			fmt.Println("❌ NOT VERIFIED")
+			fmt.Fprintln(out, "❌ NOT VERIFIED")
 		}
 	}
+}
+
+// printDivergence shows where the on-chain code departs from the artifact, so a
+// mismatch can be told apart from a metadata-only difference without other tools.
+func printDivergence(out io.Writer, matchType string, divergence, metadataOffset, metadataLength int) {
+	if (matchType != evmutil.MatchPartial && matchType != evmutil.MatchNone) || divergence < 0 {
+		return
+	}
+	fmt.Fprintf(out, "   First difference at byte %d (0x%x)\n", divergence, divergence)
+	if metadataOffset < 0 {
+		fmt.Fprintln(out, "   On-chain code has no metadata section")
+		return
+	}
+	fmt.Fprintf(out, "   Metadata: %d bytes at byte %d, stripped before comparing code\n", metadataLength, metadataOffset)
+	if divergence < metadataOffset {
+		fmt.Fprintln(out, "   The difference is in the executable code, not just the metadata")
+	}
+}
 
+// runVerifyLocal compares bytecode read from a file with the package's deployed
+// bytecode, using the same metadata-stripping comparison as the server. Nothing is
+// sent to the server besides downloading the artifact.
+func runVerifyLocal(out io.Writer, pkgRef, bytecodeFile string) error {
+	name, version, contract, err := parsePackageRef(pkgRef)
+	if err != nil {
+		return fmt.Errorf("invalid package reference: %w", err)
+	}
+	if contract == "" {
+		return fmt.Errorf("contract name required (use package/contract@version format)")
+	}
+
+	data, err := os.ReadFile(bytecodeFile)
+	if err != nil {
+		return fmt.Errorf("reading bytecode file: %w", err)
+	}
+	onchain, err := evmutil.DecodeHex(string(data))
+	if err != nil {
+		return fmt.Errorf("reading bytecode file %s: %w", bytecodeFile, err)
+	}
+	if len(onchain) == 0 {
+		return fmt.Errorf("bytecode file %s is empty", bytecodeFile)
+	}
+
+	c := newClient(getServer(), getAPIKey())
+	artifact, err := c.GetDeployedBytecode(context.Background(), name, version, contract)
+	if err != nil {
+		return fmt.Errorf("fetching deployed bytecode: %w", err)
+	}
+
+	fmt.Fprintf(out, "🔍 Verifying %s/%s@%s against %s\n", name, contract, version, bytecodeFile)
+	fmt.Fprintln(out)
+
+	result := evmutil.CompareBytecode(onchain, bytes.TrimSpace(artifact), nil)
+	printVerifyResult(out, result.MatchType, result.Match, result.Message)
+	printDivergence(out, result.MatchType, result.DivergenceOffset, result.MetadataOffset, result.MetadataLength)
+	if result.MatchType == evmutil.MatchClone {
+		fmt.Fprintf(out, "   Compare the code at %s instead\n", result.Implementation)
+	}
+	return nil
+}
+
+// runVerifyBlockscout submits the package's stored Standard JSON Input to a Blockscout
+// instance and, once verified, records "blockscout:<instance>" in the deployment's verifiedOn.
+func runVerifyBlockscout(out io.Writer, bs *blockscout.Client, pkgRef string, chainID int, address string) error {
+	name, version, contract, err := parsePackageRef(pkgRef)
+	if err != nil {
+		return fmt.Errorf("invalid package reference: %w", err)
+	}
+	if contract == "" {
+		return fmt.Errorf("contract name required (use package/contract@version format)")
+	}
+
+	ctx := context.Background()
+	c := newClient(getServer(), getAPIKey())
+
+	info, err := c.GetContract(ctx, name, version, contract)
+	if err != nil {
+		return fmt.Errorf("fetching contract: %w", err)
+	}
+	if info.Compiler == nil || info.Compiler.Version == "" {
+		return fmt.Errorf("%s/%s@%s has no compiler version recorded", name, contract, version)
+	}
+	stdJSON, err := c.GetStandardJSONInput(ctx, name, version, contract)
+	if err != nil {
+		return fmt.Errorf("fetching standard JSON input: %w", err)
+	}
+
+	// Constructor arguments come from the recorded deployment when there is one
+	chainIDStr := fmt.Sprintf("%d", chainID)
+	var constructorArgs string
+	deployment, err := c.GetDeployment(ctx, chainIDStr, address)
+	if err == nil {
+		constructorArgs = deployment.ConstructorArgs
+	}
+
+	fmt.Fprintf(out, "🔍 Verifying %s/%s@%s on %s\n", name, contract, version, bs.Instance())
+	fmt.Fprintf(out, "   Chain:   %d\n", chainID)
+	fmt.Fprintf(out, "   Address: %s\n", address)
+
+	result, err := bs.Verify(ctx, blockscout.VerifyRequest{
+		Address:         address,
+		ContractName:    contract,
+		CompilerVersion: info.Compiler.Version,
+		StandardJSON:    stdJSON,
+		ConstructorArgs: constructorArgs,
+		License:         info.License,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	if result.AlreadyVerified {
+		fmt.Fprintln(out, "✅ Already verified on Blockscout")
+	} else {
+		fmt.Fprintln(out, "✅ Verified on Blockscout")
+	}
+
+	explorer := "blockscout:" + bs.Instance()
+	if _, err := c.MarkDeploymentVerified(ctx, chainIDStr, address, explorer); err != nil {
+		fmt.Fprintf(out, "   Warning: could not record verification on the deployment: %v\n", err)
+		fmt.Fprintln(out, "   (record it first with 'contrafactory deployment record')")
+		return nil
+	}
+	fmt.Fprintf(out, "   Recorded verifiedOn: %s\n", explorer)
 	return nil
 }
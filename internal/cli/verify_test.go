@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/verification/blockscout"
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func TestRunVerifyLocal(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/packages/my-token/1.0.0/contracts/Token/deployed-bytecode" {
+			http.NotFound(w, r)
+			return
+		}
+		// Runtime code followed by an "ipfs" metadata section
+		w.Write([]byte("0x60806040a264697066735822010009"))
+	}))
+	defer registry.Close()
+
+	oldServer := server
+	server = registry.URL
+	defer func() { server = oldServer }()
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	tests := []struct {
+		name       string
+		onchain    string
+		wantText   string
+		wantDetail string
+	}{
+		{"full", "0x60806040a264697066735822010009\n", "Full match", ""},
+		{"partial", "60806040a264697066735822020009", "Partial match", "Metadata: 11 bytes at byte 4"},
+		{"none", "0x60806050a264697066735822010009", "No match", "First difference at byte 3"},
+		{"clone", "0x363d3d373d3d3d363d73bebebebebebebebebebebebebebebebebebebebe5af43d82803e903d91602b57fd5bf3", "Minimal proxy (EIP-1167) clone", "Compare the code at 0xbebebebebebebebebebebebebebebebebebebebe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := runVerifyLocal(&out, "my-token/Token@1.0.0", write(tt.name+".hex", tt.onchain))
+			require.NoError(t, err)
+			assert.Contains(t, out.String(), tt.wantText)
+			if tt.wantDetail == "" {
+				assert.NotContains(t, out.String(), "First difference")
+
+			} else {
+				assert.Contains(t, out.String(), tt.wantDetail)
+			}
+		})
+	}
+
+	t.Run("invalid file", func(t *testing.T) {
+		err := runVerifyLocal(&bytes.Buffer{}, "my-token/Token@1.0.0", write("bad.hex", "not hex"))
+		assert.ErrorContains(t, err, "invalid hex")
+	})
+
+	t.Run("contract required", func(t *testing.T) {
+		err := runVerifyLocal(&bytes.Buffer{}, "my-token@1.0.0", write("ok.hex", "0x6080"))
+		assert.ErrorContains(t, err, "contract name required")
+	})
+
+	t.Run("unknown package", func(t *testing.T) {
+		err := runVerifyLocal(&bytes.Buffer{}, "other/Token@1.0.0", write("ok.hex", "0x6080"))
+		assert.Error(t, err)
+	})
+}
+
+func TestRunVerifyBlockscout(t *testing.T) {
+	const address = "0x1234567890abcdef1234567890abcdef12345678"
+
+	explorer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/smart-contracts/" + address + "/verification/via/standard-input":
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			assert.Equal(t, "v0.8.28+commit.7893614a", r.FormValue("compiler_version"))
+			assert.Equal(t, "abcd", r.FormValue("constructor_args"))
+			w.Write([]byte(`{"message":"Smart-contract verification started"}`))
+		case "/api/v2/smart-contracts/" + address:
+			w.Write([]byte(`{"is_verified":true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer explorer.Close()
+
+	var markedExplorer string
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/packages/my-token/1.0.0/contracts/Token":
+			json.NewEncoder(w).Encode(map[string]any{"name": "Token", "license": "MIT", "compiler": map[string]any{"version": "0.8.28+commit.7893614a"}})
+		case "/api/v1/packages/my-token/1.0.0/contracts/Token/standard-json-input":
+			w.Write([]byte(`{"language":"Solidity"}`))
+		case "/api/v1/deployments/1/" + address:
+			json.NewEncoder(w).Encode(map[string]any{"chainId": "1", "address": address, "constructorArgs": "0xabcd"})
+		case "/api/v1/deployments/1/" + address + "/verified":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			markedExplorer = body["explorer"]
+			json.NewEncoder(w).Encode(map[string]any{"chainId": "1", "address": address, "verified": true})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer registry.Close()
+
+	oldServer := server
+	server = registry.URL
+	defer func() { server = oldServer }()
+
+	bs := blockscout.New(explorer.URL, blockscout.WithPolling(time.Millisecond, time.Second))
+
+	var out bytes.Buffer
+	err := runVerifyBlockscout(&out, bs, "my-token/Token@1.0.0", 1, address)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Verified on Blockscout")
+	assert.Equal(t, "blockscout:"+explorer.URL, markedExplorer)
+
+	err = runVerifyBlockscout(&out, bs, "my-token@1.0.0", 1, address)
+	assert.ErrorContains(t, err, "contract name required")
+}
+
+func TestWaitForVerification(t *testing.T) {
+	var polls int
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/deployments/verification-status", r.URL.Path)
+		switch r.URL.Query().Get("address") {
+		case "0xverified":
+			// Verified on the third poll
+			polls++
+			verified := polls >= 3
+			json.NewEncoder(w).Encode(map[string]any{"verified": verified, "verifiedOn": []string{"etherscan"}})
+		case "0xpending":
+			w.Write([]byte(`{"verified":false,"status":"unverified"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"Deployment not found"}}`))
+		}
+	}))
+	defer registry.Close()
+	c := client.New(registry.URL, "")
+
+	t.Run("resolves when verified", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, waitForVerification(context.Background(), &out, c, "1", "0xverified", time.Second, time.Millisecond))
+		assert.Equal(t, 3, polls)
+		assert.Contains(t, out.String(), "Waiting for verification")
+		assert.Contains(t, out.String(), "Verified on etherscan")
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		err := waitForVerification(context.Background(), &bytes.Buffer{}, c, "1", "0xpending", 20*time.Millisecond, time.Millisecond)
+		assert.ErrorContains(t, err, "timed out")
+	})
+
+	t.Run("unrecorded deployment fails fast", func(t *testing.T) {
+		err := waitForVerification(context.Background(), &bytes.Buffer{}, c, "1", "0xmissing", time.Second, time.Millisecond)
+		assert.ErrorContains(t, err, "no deployment recorded")
+	})
+}
@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+// VersionInfo is the CLI's build metadata, injected into the main package via ldflags
+type VersionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"` // GOOS/GOARCH
+}
+
+// withBuildInfo fills what ldflags didn't set from the binary's embedded build
+// info, so `go install` builds still report their commit.
+func (v VersionInfo) withBuildInfo() VersionInfo {
+	if v.GoVersion == "" {
+		v.GoVersion = runtime.Version()
+	}
+	if v.Platform == "" {
+		v.Platform = runtime.GOOS + "/" + runtime.GOARCH
+	}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return v
+	}
+	if (v.Version == "" || v.Version == "dev") && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		v.Version = bi.Main.Version
+	}
+	for _, s := range bi.Settings {
+		switch {
+		case s.Key == "vcs.revision" && v.Commit == "":
+			v.Commit = s.Value
+		case s.Key == "vcs.time" && v.Date == "":
+			v.Date = s.Value
+		}
+	}
+	return v
+}
+
+// versionOutput is the JSON output of the version command
+type versionOutput struct {
+	CLI    VersionInfo    `json:"cli"`
+	Server *serverVersion `json:"server,omitempty"`
+}
+
+// serverVersion is the version of the configured server, or why it couldn't be read
+type serverVersion struct {
+	URL     string `json:"url"`
+	Version string `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+func createVersionCmd(info VersionInfo) *cobra.Command {
+	var jsonOutput bool
+	var noServer bool
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show the CLI and server versions",
+		Long: `Show the CLI's version, commit, build date and Go version, and the version of
+the server it is configured to talk to. Include this output in bug reports.
+
+EXAMPLES:
+  contrafactory version
+  contrafactory version --json
+  contrafactory version --no-server
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var c *client.Client
+			if !noServer {
+				c = newClient(getServer(), getAPIKey())
+			}
+			return runVersion(context.Background(), cmd.OutOrStdout(), info.withBuildInfo(), c, getServer(), jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	cmd.Flags().BoolVar(&noServer, "no-server", false, "don't contact the server")
+
+	return cmd
+}
+
+// runVersion writes the CLI's build metadata and, when c is set, the version of
+// the server at serverURL. An unreachable server is reported, not an error.
+func runVersion(ctx context.Context, out io.Writer, info VersionInfo, c *client.Client, serverURL string, jsonOutput bool) error {
+	result := versionOutput{CLI: info}
+	if c != nil {
+		result.Server = fetchServerVersion(ctx, c, serverURL)
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	fmt.Fprintf(out, "contrafactory %s\n", info.Version)
+	if info.Commit != "" {
+		fmt.Fprintf(out, "  Commit:  %s\n", info.Commit)
+	}
+	if info.Date != "" {
+		fmt.Fprintf(out, "  Built:   %s\n", info.Date)
+	}
+	fmt.Fprintf(out, "  Go:      %s\n", info.GoVersion)
+	fmt.Fprintf(out, "  OS/Arch: %s\n", info.Platform)
+
+	if s := result.Server; s != nil {
+		switch {
+		case s.Error != "":
+			fmt.Fprintf(out, "Server:    %s (unreachable: %s)\n", s.URL, s.Error)
+		case s.Version != "":
+			fmt.Fprintf(out, "Server:    %s (%s)\n", s.URL, s.Version)
+		default:
+			fmt.Fprintf(out, "Server:    %s (version not reported)\n", s.URL)
+		}
+	}
+	return nil
+}
+
+// fetchServerVersion asks the server for its version, giving up quickly so the
+// command stays useful when the server is down.
+func fetchServerVersion(ctx context.Context, c *client.Client, serverURL string) *serverVersion {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	s := &serverVersion{URL: serverURL}
+	health, err := c.GetHealth(ctx)
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	s.Version = health.Version
+	return s
+}
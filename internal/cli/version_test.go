@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/pkg/client"
+)
+
+func TestRunVersion(t *testing.T) {
+	info := VersionInfo{Version: "1.4.0", Commit: "abc1234", Date: "2026-01-02T03:04:05Z", GoVersion: "go1.25.0", Platform: "linux/amd64"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		w.Write([]byte(`{"status":"ok","version":"1.3.2"}`))
+	}))
+	defer srv.Close()
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, runVersion(context.Background(), &buf, info, client.New(srv.URL, ""), srv.URL, false))
+		out := buf.String()
+
+		assert.Contains(t, out, "contrafactory 1.4.0")
+		assert.Contains(t, out, "Commit:  abc1234")
+		assert.Contains(t, out, "Built:   2026-01-02T03:04:05Z")
+		assert.Contains(t, out, "Go:      go1.25.0")
+		assert.Contains(t, out, "OS/Arch: linux/amd64")
+		assert.Contains(t, out, "Server:    "+srv.URL+" (1.3.2)")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, runVersion(context.Background(), &buf, info, client.New(srv.URL, ""), srv.URL, true))
+		assert.JSONEq(t, `{
+			"cli":{"version":"1.4.0","commit":"abc1234","date":"2026-01-02T03:04:05Z","goVersion":"go1.25.0","platform":"linux/amd64"},
+			"server":{"url":"`+srv.URL+`","version":"1.3.2"}
+		}`, buf.String())
+	})
+
+	t.Run("unreachable server is not an error", func(t *testing.T) {
+		down := httptest.NewServer(http.NotFoundHandler())
+		down.Close()
+
+		var buf bytes.Buffer
+		require.NoError(t, runVersion(context.Background(), &buf, info, client.New(down.URL, ""), down.URL, true))
+		var got versionOutput
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		assert.Equal(t, down.URL, got.Server.URL)
+		assert.NotEmpty(t, got.Server.Error)
+	})
+
+	t.Run("without server", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, runVersion(context.Background(), &buf, VersionInfo{Version: "dev"}.withBuildInfo(), nil, "", false))
+		assert.Contains(t, buf.String(), "contrafactory ")
+		assert.NotContains(t, buf.String(), "Server:")
+	})
+}
@@ -1,171 +1,394 @@
 package config
 
 import (
+	"crypto/tls"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/pendergraft/contrafactory/internal/validation"
 )
 
 // Config holds all configuration for the server
 type Config struct {
-	Server    ServerConfig
-	Storage   StorageConfig
-	Auth      AuthConfig
-	Cache     CacheConfig
-	Logging   LoggingConfig
-	RateLimit RateLimitConfig
-	Security  SecurityConfig
-	Proxy     ProxyConfig
-	Metrics   MetricsConfig
+	Server    ServerConfig    `yaml:"server"`
+	Storage   StorageConfig   `yaml:"storage"`
+	Auth      AuthConfig      `yaml:"auth"`
+	Cache     CacheConfig     `yaml:"cache"`
+	Logging   LoggingConfig   `yaml:"logging"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	Security  SecurityConfig  `yaml:"security"`
+	Proxy     ProxyConfig     `yaml:"proxy"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+	Limits    LimitsConfig    `yaml:"limits"`
+	Compilers CompilersConfig `yaml:"compilers"`
+	Verify    VerifyConfig    `yaml:"verify"`
+	CORS      CORSConfig      `yaml:"cors"`
+	GraphQL   GraphQLConfig   `yaml:"graphql"`
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
-	Port           int
-	Host           string
-	ReadTimeout    int // seconds
-	WriteTimeout   int // seconds
-	IdleTimeout    int // seconds
-	RequestTimeout int // seconds
+	Port           int    `yaml:"port"`
+	Host           string `yaml:"host"`
+	ReadTimeout    int    `yaml:"read_timeout"`    // seconds
+	WriteTimeout   int    `yaml:"write_timeout"`   // seconds
+	IdleTimeout    int    `yaml:"idle_timeout"`    // seconds
+	RequestTimeout int    `yaml:"request_timeout"` // seconds
+
+	TLS TLSConfig `yaml:"tls"`
+}
+
+// TLSConfig serves HTTPS (and HTTP/2) on the server port. TLS is off unless
+// cert_file and key_file are set.
+type TLSConfig struct {
+	CertFile     string `yaml:"cert_file"`
+	KeyFile      string `yaml:"key_file"`
+	MinVersion   string `yaml:"min_version"`   // "1.2" or "1.3"
+	RedirectPort int    `yaml:"redirect_port"` // plain HTTP port redirecting to HTTPS; 0 = none
+}
+
+// Enabled reports whether the server should serve HTTPS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// MinTLSVersion returns the crypto/tls constant for MinVersion
+func (c TLSConfig) MinTLSVersion() (uint16, error) {
+	switch c.MinVersion {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("tls: unsupported min_version %q (expected 1.2 or 1.3)", c.MinVersion)
+	}
+}
+
+// validate checks that the TLS settings are complete and consistent
+func (c TLSConfig) validate() error {
+	if !c.Enabled() {
+		if c.RedirectPort != 0 {
+			return fmt.Errorf("tls: redirect_port needs cert_file and key_file")
+		}
+		return nil
+	}
+	if c.CertFile == "" || c.KeyFile == "" {
+		return fmt.Errorf("tls: cert_file and key_file must be set together")
+	}
+	_, err := c.MinTLSVersion()
+	return err
 }
 
 // MetricsConfig holds metrics/observability settings
 type MetricsConfig struct {
-	Enabled     bool
-	ServiceName string
-	Port        int // separate port for metrics server
+	Enabled     bool   `yaml:"enabled"`
+	ServiceName string `yaml:"service_name"`
+	Port        int    `yaml:"port"` // separate port for metrics server
 }
 
 // StorageConfig holds storage configuration
 type StorageConfig struct {
-	Type     string // "sqlite" or "postgres"
-	Postgres PostgresConfig
-	SQLite   SQLiteConfig
-	Blobs    BlobsConfig
+	Type     string         `yaml:"type"` // "sqlite" or "postgres"
+	Postgres PostgresConfig `yaml:"postgres"`
+	SQLite   SQLiteConfig   `yaml:"sqlite"`
+	Blobs    BlobsConfig    `yaml:"blobs"`
+
+	// Connection pool; 0 keeps the backend's default (see storage.New)
+	MaxOpenConns           int `yaml:"max_open_conns"`
+	MaxIdleConns           int `yaml:"max_idle_conns"`
+	ConnMaxLifetimeSeconds int `yaml:"conn_max_lifetime_seconds"`
+
+	// How often the server deletes orphaned artifacts and blobs; 0 disables it
+	// (run `contrafactory-server gc` instead)
+	GCIntervalMinutes int `yaml:"gc_interval_minutes"`
 }
 
 // PostgresConfig holds Postgres connection settings
 type PostgresConfig struct {
-	URL string
+	URL string `yaml:"url"`
 }
 
 // SQLiteConfig holds SQLite settings
 type SQLiteConfig struct {
-	Path string
+	Path string `yaml:"path"`
 }
 
 // BlobsConfig holds blob storage settings
 type BlobsConfig struct {
-	Type     string // "postgres", "filesystem", "s3"
-	BasePath string // for filesystem
+	Type     string `yaml:"type"`      // "postgres", "filesystem", "s3"
+	BasePath string `yaml:"base_path"` // for filesystem
 }
 
 // AuthConfig holds authentication settings
 type AuthConfig struct {
-	Type string // "none" or "api-key"
+	Type string `yaml:"type"` // "none" or "api-key"
 }
 
 // CacheConfig holds cache settings
 type CacheConfig struct {
-	Enabled    bool
-	MaxSizeMB  int
-	TTLSeconds int
+	Enabled    bool `yaml:"enabled"`
+	MaxSizeMB  int  `yaml:"max_size_mb"`
+	TTLSeconds int  `yaml:"ttl_seconds"`
 }
 
 // LoggingConfig holds logging settings
 type LoggingConfig struct {
-	Level  string
-	Format string // "text" or "json"
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"` // "text" or "json"
 }
 
 // RateLimitConfig holds rate limiting settings
 type RateLimitConfig struct {
-	Enabled        bool
-	RequestsPerMin int
-	BurstSize      int
-	CleanupMinutes int
+	Enabled        bool `yaml:"enabled"`
+	RequestsPerMin int  `yaml:"requests_per_min"`
+	BurstSize      int  `yaml:"burst_size"`
+	CleanupMinutes int  `yaml:"cleanup_minutes"`
 }
 
 // SecurityConfig holds security filter settings
 type SecurityConfig struct {
-	FilterEnabled bool
-	MaxBodySizeMB int
+	FilterEnabled bool `yaml:"filter_enabled"`
+	MaxBodySizeMB int  `yaml:"max_body_size_mb"`
+}
+
+// LimitsConfig holds per-request work limits
+type LimitsConfig struct {
+	MaxArtifactsPerPublish int `yaml:"max_artifacts_per_publish"` // 0 = unlimited
+	MaxOwnerStorageMB      int `yaml:"max_owner_storage_mb"`      // artifact bytes per API key; 0 = unlimited
+	MaxVersionsPerPackage  int `yaml:"max_versions_per_package"`  // 0 = unlimited
+	MaxPageSize            int `yaml:"max_page_size"`             // largest limit list endpoints accept
+}
+
+// VerifyConfig holds on-chain verification settings
+type VerifyConfig struct {
+	RPCTimeoutSeconds int `yaml:"rpc_timeout_seconds"` // per verification, for all RPC calls
+}
+
+// CORSConfig allows browser-based clients on other origins to call the API.
+// CORS is off unless allowed_origins is set.
+type CORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowed_origins"` // "*" allows any origin
+	AllowedMethods   []string `yaml:"allowed_methods"`
+	AllowedHeaders   []string `yaml:"allowed_headers"`
+	AllowCredentials bool     `yaml:"allow_credentials"`
+	MaxAgeSeconds    int      `yaml:"max_age_seconds"` // preflight cache lifetime
+}
+
+// GraphQLConfig controls the read-only GraphQL endpoint at /api/v1/graphql.
+type GraphQLConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// CompilersConfig restricts which compilers published artifacts may use.
+// Versions are version ranges (e.g. "<0.8.0", "^0.8.20"); evmVersions are names
+// such as "cancun". Empty lists impose no restriction.
+type CompilersConfig struct {
+	AllowedVersions    []string `yaml:"allowed_versions"`
+	DeniedVersions     []string `yaml:"denied_versions"`
+	AllowedEVMVersions []string `yaml:"allowed_evm_versions"`
+	DeniedEVMVersions  []string `yaml:"denied_evm_versions"`
 }
 
 // ProxyConfig holds trusted proxy settings for X-Forwarded-For handling
 type ProxyConfig struct {
-	TrustProxy     bool
-	TrustedProxies []string // CIDR notation
+	TrustProxy     bool     `yaml:"trust_proxy"`
+	TrustedProxies []string `yaml:"trusted_proxies"` // CIDR notation
 }
 
-// Load loads configuration from environment variables
+// ConfigFileEnv names the environment variable that points at an optional YAML config file.
+const ConfigFileEnv = "CONTRAFACTORY_CONFIG_FILE"
+
+// Load loads configuration from the file named by CONTRAFACTORY_CONFIG_FILE
+// (if set) and environment variables.
 func Load() (*Config, error) {
-	cfg := &Config{
+	return LoadFile(os.Getenv(ConfigFileEnv))
+}
+
+// LoadFile loads configuration with the precedence defaults < file < env.
+// An empty path skips the file, so only defaults and env are used. Secrets
+// such as the database URL can therefore be kept out of the file entirely.
+func LoadFile(path string) (*Config, error) {
+	cfg := defaults()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+		}
+	}
+
+	applyEnv(cfg)
+
+	if err := cfg.Server.TLS.validate(); err != nil {
+		return nil, err
+	}
+
+	for _, ranges := range [][]string{cfg.Compilers.AllowedVersions, cfg.Compilers.DeniedVersions} {
+		for _, r := range ranges {
+			if _, err := validation.ParseVersionRange(r); err != nil {
+				return nil, fmt.Errorf("compilers: %w", err)
+			}
+		}
+	}
+
+	// If a database URL is set, default to postgres
+	if cfg.Storage.Postgres.URL != "" && cfg.Storage.Type == "sqlite" {
+		cfg.Storage.Type = "postgres"
+	}
+
+	// Default blob storage to same as main storage
+	if cfg.Storage.Blobs.Type == "" {
+		cfg.Storage.Blobs.Type = cfg.Storage.Type
+	}
+
+	return cfg, nil
+}
+
+// defaults returns the built-in configuration.
+func defaults() *Config {
+	return &Config{
 		Server: ServerConfig{
-			Port:           getEnvInt("PORT", 8080),
-			Host:           getEnv("HOST", "0.0.0.0"),
-			ReadTimeout:    getEnvInt("SERVER_READ_TIMEOUT", 30),
-			WriteTimeout:   getEnvInt("SERVER_WRITE_TIMEOUT", 60),
-			IdleTimeout:    getEnvInt("SERVER_IDLE_TIMEOUT", 120),
-			RequestTimeout: getEnvInt("SERVER_REQUEST_TIMEOUT", 30),
+			Port:           8080,
+			Host:           "0.0.0.0",
+			ReadTimeout:    30,
+			WriteTimeout:   60,
+			IdleTimeout:    120,
+			RequestTimeout: 30,
+			TLS:            TLSConfig{MinVersion: "1.2"},
 		},
 		Storage: StorageConfig{
-			Type: getEnv("STORAGE_TYPE", "sqlite"),
-			Postgres: PostgresConfig{
-				URL: getEnv("DATABASE_URL", ""),
-			},
+			Type: "sqlite",
 			SQLite: SQLiteConfig{
-				Path: getEnv("SQLITE_PATH", "./data/contrafactory.db"),
+				Path: "./data/contrafactory.db",
 			},
 			Blobs: BlobsConfig{
-				Type:     getEnv("BLOB_STORAGE_TYPE", ""),
-				BasePath: getEnv("BLOB_STORAGE_PATH", "./data/blobs"),
+				BasePath: "./data/blobs",
 			},
 		},
 		Auth: AuthConfig{
-			Type: getEnv("AUTH_TYPE", "none"),
+			Type: "none",
 		},
 		Cache: CacheConfig{
-			Enabled:    getEnvBool("CACHE_ENABLED", true),
-			MaxSizeMB:  getEnvInt("CACHE_MAX_SIZE_MB", 100),
-			TTLSeconds: getEnvInt("CACHE_TTL_SECONDS", 3600),
+			Enabled:    false,
+			MaxSizeMB:  100,
+			TTLSeconds: 3600,
 		},
 		Logging: LoggingConfig{
-			Level:  getEnv("LOG_LEVEL", "info"),
-			Format: getEnv("LOG_FORMAT", "json"),
+			Level:  "info",
+			Format: "json",
 		},
 		RateLimit: RateLimitConfig{
-			Enabled:        getEnvBool("RATE_LIMIT_ENABLED", true),
-			RequestsPerMin: getEnvInt("RATE_LIMIT_RPM", 300),
-			BurstSize:      getEnvInt("RATE_LIMIT_BURST", 50),
-			CleanupMinutes: getEnvInt("RATE_LIMIT_CLEANUP_MINUTES", 10),
+			Enabled:        true,
+			RequestsPerMin: 300,
+			BurstSize:      50,
+			CleanupMinutes: 10,
 		},
 		Security: SecurityConfig{
-			FilterEnabled: getEnvBool("SECURITY_FILTER_ENABLED", true),
-			MaxBodySizeMB: getEnvInt("SECURITY_MAX_BODY_SIZE_MB", 50),
+			FilterEnabled: true,
+			MaxBodySizeMB: 50,
 		},
 		Proxy: ProxyConfig{
-			TrustProxy:     getEnvBool("TRUST_PROXY", false),
-			TrustedProxies: getEnvStringSlice("TRUSTED_PROXIES", []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}),
+			TrustProxy:     false,
+			TrustedProxies: []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"},
 		},
 		Metrics: MetricsConfig{
-			Enabled:     getEnvBool("OTEL_METRICS_ENABLED", true),
-			ServiceName: getEnv("OTEL_SERVICE_NAME", "contrafactory"),
-			Port:        getEnvInt("METRICS_PORT", 9090),
+			Enabled:     true,
+			ServiceName: "contrafactory",
+			Port:        9090,
+		},
+		Limits: LimitsConfig{
+			MaxArtifactsPerPublish: 0,
+			MaxPageSize:            100,
+		},
+		Verify: VerifyConfig{
+			RPCTimeoutSeconds: 15,
+		},
+		CORS: CORSConfig{
+			AllowedMethods: []string{"GET", "HEAD"},
+			AllowedHeaders: []string{"Content-Type", "If-None-Match"},
+			MaxAgeSeconds:  600,
 		},
 	}
+}
 
-	// If DATABASE_URL is set, default to postgres
-	if cfg.Storage.Postgres.URL != "" && cfg.Storage.Type == "sqlite" {
-		cfg.Storage.Type = "postgres"
-	}
+// applyEnv overrides cfg with any environment variables that are set.
+func applyEnv(cfg *Config) {
+	cfg.Server.Port = getEnvInt("PORT", cfg.Server.Port)
+	cfg.Server.Host = getEnv("HOST", cfg.Server.Host)
+	cfg.Server.ReadTimeout = getEnvInt("SERVER_READ_TIMEOUT", cfg.Server.ReadTimeout)
+	cfg.Server.WriteTimeout = getEnvInt("SERVER_WRITE_TIMEOUT", cfg.Server.WriteTimeout)
+	cfg.Server.IdleTimeout = getEnvInt("SERVER_IDLE_TIMEOUT", cfg.Server.IdleTimeout)
+	cfg.Server.RequestTimeout = getEnvInt("SERVER_REQUEST_TIMEOUT", cfg.Server.RequestTimeout)
+	cfg.Server.TLS.CertFile = getEnv("TLS_CERT_FILE", cfg.Server.TLS.CertFile)
+	cfg.Server.TLS.KeyFile = getEnv("TLS_KEY_FILE", cfg.Server.TLS.KeyFile)
+	cfg.Server.TLS.MinVersion = getEnv("TLS_MIN_VERSION", cfg.Server.TLS.MinVersion)
+	cfg.Server.TLS.RedirectPort = getEnvInt("TLS_REDIRECT_PORT", cfg.Server.TLS.RedirectPort)
 
-	// Default blob storage to same as main storage
-	if cfg.Storage.Blobs.Type == "" {
-		cfg.Storage.Blobs.Type = cfg.Storage.Type
-	}
+	cfg.Storage.Type = getEnv("STORAGE_TYPE", cfg.Storage.Type)
+	cfg.Storage.Postgres.URL = getEnv("DATABASE_URL", cfg.Storage.Postgres.URL)
+	cfg.Storage.SQLite.Path = getEnv("SQLITE_PATH", cfg.Storage.SQLite.Path)
+	cfg.Storage.Blobs.Type = getEnv("BLOB_STORAGE_TYPE", cfg.Storage.Blobs.Type)
+	cfg.Storage.Blobs.BasePath = getEnv("BLOB_STORAGE_PATH", cfg.Storage.Blobs.BasePath)
+	cfg.Storage.MaxOpenConns = getEnvInt("DB_MAX_OPEN_CONNS", cfg.Storage.MaxOpenConns)
+	cfg.Storage.MaxIdleConns = getEnvInt("DB_MAX_IDLE_CONNS", cfg.Storage.MaxIdleConns)
+	cfg.Storage.ConnMaxLifetimeSeconds = getEnvInt("DB_CONN_MAX_LIFETIME_SECONDS", cfg.Storage.ConnMaxLifetimeSeconds)
+	cfg.Storage.GCIntervalMinutes = getEnvInt("GC_INTERVAL_MINUTES", cfg.Storage.GCIntervalMinutes)
 
-	return cfg, nil
+	cfg.Auth.Type = getEnv("AUTH_TYPE", cfg.Auth.Type)
+
+	cfg.Cache.Enabled = getEnvBool("CACHE_ENABLED", cfg.Cache.Enabled)
+	cfg.Cache.MaxSizeMB = getEnvInt("CACHE_MAX_SIZE_MB", cfg.Cache.MaxSizeMB)
+	cfg.Cache.TTLSeconds = getEnvInt("CACHE_TTL_SECONDS", cfg.Cache.TTLSeconds)
+
+	cfg.Logging.Level = getEnv("LOG_LEVEL", cfg.Logging.Level)
+	cfg.Logging.Format = getEnv("LOG_FORMAT", cfg.Logging.Format)
+
+	cfg.RateLimit.Enabled = getEnvBool("RATE_LIMIT_ENABLED", cfg.RateLimit.Enabled)
+	cfg.RateLimit.RequestsPerMin = getEnvInt("RATE_LIMIT_RPM", cfg.RateLimit.RequestsPerMin)
+	cfg.RateLimit.BurstSize = getEnvInt("RATE_LIMIT_BURST", cfg.RateLimit.BurstSize)
+	cfg.RateLimit.CleanupMinutes = getEnvInt("RATE_LIMIT_CLEANUP_MINUTES", cfg.RateLimit.CleanupMinutes)
+
+	cfg.Security.FilterEnabled = getEnvBool("SECURITY_FILTER_ENABLED", cfg.Security.FilterEnabled)
+	cfg.Security.MaxBodySizeMB = getEnvInt("SECURITY_MAX_BODY_SIZE_MB", cfg.Security.MaxBodySizeMB)
+
+	cfg.Proxy.TrustProxy = getEnvBool("TRUST_PROXY", cfg.Proxy.TrustProxy)
+	cfg.Proxy.TrustedProxies = getEnvStringSlice("TRUSTED_PROXIES", cfg.Proxy.TrustedProxies)
+
+	cfg.Metrics.Enabled = getEnvBool("OTEL_METRICS_ENABLED", cfg.Metrics.Enabled)
+	cfg.Metrics.ServiceName = getEnv("OTEL_SERVICE_NAME", cfg.Metrics.ServiceName)
+	cfg.Metrics.Port = getEnvInt("METRICS_PORT", cfg.Metrics.Port)
+
+	cfg.Limits.MaxArtifactsPerPublish = getEnvInt("MAX_ARTIFACTS_PER_PUBLISH", cfg.Limits.MaxArtifactsPerPublish)
+	cfg.Limits.MaxOwnerStorageMB = getEnvInt("MAX_OWNER_STORAGE_MB", cfg.Limits.MaxOwnerStorageMB)
+	cfg.Limits.MaxVersionsPerPackage = getEnvInt("MAX_VERSIONS_PER_PACKAGE", cfg.Limits.MaxVersionsPerPackage)
+	cfg.Limits.MaxPageSize = getEnvInt("MAX_PAGE_SIZE", cfg.Limits.MaxPageSize)
+
+	cfg.Verify.RPCTimeoutSeconds = getEnvInt("VERIFY_RPC_TIMEOUT_SECONDS", cfg.Verify.RPCTimeoutSeconds)
+
+	cfg.CORS.AllowedOrigins = getEnvStringSlice("CORS_ALLOWED_ORIGINS", cfg.CORS.AllowedOrigins)
+	cfg.CORS.AllowedMethods = getEnvStringSlice("CORS_ALLOWED_METHODS", cfg.CORS.AllowedMethods)
+	cfg.CORS.AllowedHeaders = getEnvStringSlice("CORS_ALLOWED_HEADERS", cfg.CORS.AllowedHeaders)
+	cfg.CORS.AllowCredentials = getEnvBool("CORS_ALLOW_CREDENTIALS", cfg.CORS.AllowCredentials)
+	cfg.CORS.MaxAgeSeconds = getEnvInt("CORS_MAX_AGE_SECONDS", cfg.CORS.MaxAgeSeconds)
+
+	cfg.GraphQL.Enabled = getEnvBool("GRAPHQL_ENABLED", cfg.GraphQL.Enabled)
+
+	// CONTRAFACTORY_-prefixed names win over the unprefixed ones above
+	cfg.Server.Port = getEnvInt("CONTRAFACTORY_SERVER_PORT", cfg.Server.Port)
+	cfg.Server.Host = getEnv("CONTRAFACTORY_SERVER_HOST", cfg.Server.Host)
+	cfg.Storage.Type = getEnv("CONTRAFACTORY_STORAGE_TYPE", cfg.Storage.Type)
+	cfg.Storage.Postgres.URL = getEnv("CONTRAFACTORY_DB_URL", cfg.Storage.Postgres.URL)
+	cfg.Storage.SQLite.Path = getEnv("CONTRAFACTORY_SQLITE_PATH", cfg.Storage.SQLite.Path)
+	cfg.Auth.Type = getEnv("CONTRAFACTORY_AUTH_TYPE", cfg.Auth.Type)
+	cfg.Logging.Level = getEnv("CONTRAFACTORY_LOG_LEVEL", cfg.Logging.Level)
+	cfg.Logging.Format = getEnv("CONTRAFACTORY_LOG_FORMAT", cfg.Logging.Format)
 }
 
 func getEnv(key, defaultValue string) string {
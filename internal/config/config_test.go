@@ -0,0 +1,280 @@
+package config
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clearEnv unsets every variable Load reads so tests don't leak host settings.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{
+		ConfigFileEnv,
+		"PORT", "HOST", "STORAGE_TYPE", "DATABASE_URL", "SQLITE_PATH", "AUTH_TYPE",
+		"LOG_LEVEL", "LOG_FORMAT", "BLOB_STORAGE_TYPE",
+		"CONTRAFACTORY_SERVER_PORT", "CONTRAFACTORY_SERVER_HOST", "CONTRAFACTORY_STORAGE_TYPE",
+		"CONTRAFACTORY_DB_URL", "CONTRAFACTORY_SQLITE_PATH", "CONTRAFACTORY_AUTH_TYPE",
+		"CONTRAFACTORY_LOG_LEVEL", "CONTRAFACTORY_LOG_FORMAT", "VERIFY_RPC_TIMEOUT_SECONDS",
+		"CORS_ALLOWED_ORIGINS", "TLS_CERT_FILE", "TLS_KEY_FILE", "TLS_MIN_VERSION", "TLS_REDIRECT_PORT",
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.Equal(t, "0.0.0.0", cfg.Server.Host)
+	assert.Equal(t, "sqlite", cfg.Storage.Type)
+	assert.Equal(t, "sqlite", cfg.Storage.Blobs.Type)
+	assert.Equal(t, "./data/contrafactory.db", cfg.Storage.SQLite.Path)
+	assert.Equal(t, "none", cfg.Auth.Type)
+	assert.Equal(t, []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}, cfg.Proxy.TrustedProxies)
+	assert.Equal(t, 15, cfg.Verify.RPCTimeoutSeconds)
+	assert.Empty(t, cfg.CORS.AllowedOrigins, "CORS is opt-in")
+}
+
+func TestLoadEnvOverrides(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		assert func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "DATABASE_URL switches to postgres",
+			env:  map[string]string{"DATABASE_URL": "postgres://u:p@db/cf"},
+			assert: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "postgres", cfg.Storage.Type)
+				assert.Equal(t, "postgres://u:p@db/cf", cfg.Storage.Postgres.URL)
+				assert.Equal(t, "postgres", cfg.Storage.Blobs.Type)
+			},
+		},
+		{
+			name: "database pool",
+			env:  map[string]string{"DB_MAX_OPEN_CONNS": "20", "DB_MAX_IDLE_CONNS": "5", "DB_CONN_MAX_LIFETIME_SECONDS": "300"},
+			assert: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 20, cfg.Storage.MaxOpenConns)
+				assert.Equal(t, 5, cfg.Storage.MaxIdleConns)
+				assert.Equal(t, 300, cfg.Storage.ConnMaxLifetimeSeconds)
+			},
+		},
+		{
+			name: "garbage collection interval",
+			env:  map[string]string{"GC_INTERVAL_MINUTES": "60"},
+			assert: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 60, cfg.Storage.GCIntervalMinutes)
+			},
+		},
+		{
+			name: "graphql",
+			env:  map[string]string{"GRAPHQL_ENABLED": "true"},
+			assert: func(t *testing.T, cfg *Config) {
+				assert.True(t, cfg.GraphQL.Enabled)
+			},
+		},
+		{
+			name: "verification RPC timeout",
+			env:  map[string]string{"VERIFY_RPC_TIMEOUT_SECONDS": "30"},
+			assert: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 30, cfg.Verify.RPCTimeoutSeconds)
+			},
+		},
+		{
+			name: "CORS origins",
+			env:  map[string]string{"CORS_ALLOWED_ORIGINS": "https://ui.example.com, http://localhost:3000"},
+			assert: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, []string{"https://ui.example.com", "http://localhost:3000"}, cfg.CORS.AllowedOrigins)
+				assert.Equal(t, []string{"GET", "HEAD"}, cfg.CORS.AllowedMethods)
+			},
+		},
+		{
+			name: "TLS",
+			env:  map[string]string{"TLS_CERT_FILE": "/etc/cf/tls.crt", "TLS_KEY_FILE": "/etc/cf/tls.key", "TLS_MIN_VERSION": "1.3", "TLS_REDIRECT_PORT": "8081"},
+			assert: func(t *testing.T, cfg *Config) {
+				assert.True(t, cfg.Server.TLS.Enabled())
+				assert.Equal(t, TLSConfig{CertFile: "/etc/cf/tls.crt", KeyFile: "/etc/cf/tls.key", MinVersion: "1.3", RedirectPort: 8081}, cfg.Server.TLS)
+			},
+		},
+		{
+			name: "CONTRAFACTORY_DB_URL wins over DATABASE_URL",
+			env: map[string]string{
+				"DATABASE_URL":         "postgres://legacy/cf",
+				"CONTRAFACTORY_DB_URL": "postgres://prefixed/cf",
+			},
+			assert: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "postgres://prefixed/cf", cfg.Storage.Postgres.URL)
+				assert.Equal(t, "postgres", cfg.Storage.Type)
+			},
+		},
+		{
+			name: "prefixed server and storage settings",
+			env: map[string]string{
+				"CONTRAFACTORY_SERVER_PORT":  "9000",
+				"CONTRAFACTORY_SERVER_HOST":  "127.0.0.1",
+				"CONTRAFACTORY_STORAGE_TYPE": "postgres",
+				"CONTRAFACTORY_AUTH_TYPE":    "api-key",
+				"CONTRAFACTORY_LOG_LEVEL":    "debug",
+			},
+			assert: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 9000, cfg.Server.Port)
+				assert.Equal(t, "127.0.0.1", cfg.Server.Host)
+				assert.Equal(t, "postgres", cfg.Storage.Type)
+				assert.Equal(t, "api-key", cfg.Auth.Type)
+				assert.Equal(t, "debug", cfg.Logging.Level)
+			},
+		},
+		{
+			name: "invalid int falls back",
+			env:  map[string]string{"PORT": "not-a-number"},
+			assert: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 8080, cfg.Server.Port)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := Load()
+			require.NoError(t, err)
+			tt.assert(t, cfg)
+		})
+	}
+}
+
+func TestLoadFilePrecedence(t *testing.T) {
+	path := writeConfigFile(t, `
+server:
+  port: 7000
+  host: 10.0.0.1
+storage:
+  type: postgres
+  postgres:
+    url: postgres://file/cf
+logging:
+  level: warn
+`)
+
+	t.Run("file overrides defaults", func(t *testing.T) {
+		clearEnv(t)
+
+		cfg, err := LoadFile(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, 7000, cfg.Server.Port)
+		assert.Equal(t, "10.0.0.1", cfg.Server.Host)
+		assert.Equal(t, "postgres", cfg.Storage.Type)
+		assert.Equal(t, "postgres://file/cf", cfg.Storage.Postgres.URL)
+		assert.Equal(t, "warn", cfg.Logging.Level)
+		// Unset fields keep their defaults
+		assert.Equal(t, 60, cfg.Server.WriteTimeout)
+		assert.Equal(t, "json", cfg.Logging.Format)
+	})
+
+	t.Run("env overrides file", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("CONTRAFACTORY_SERVER_PORT", "7100")
+		t.Setenv("CONTRAFACTORY_DB_URL", "postgres://env/cf")
+		t.Setenv("LOG_LEVEL", "error")
+
+		cfg, err := LoadFile(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, 7100, cfg.Server.Port)
+		assert.Equal(t, "10.0.0.1", cfg.Server.Host)
+		assert.Equal(t, "postgres://env/cf", cfg.Storage.Postgres.URL)
+		assert.Equal(t, "error", cfg.Logging.Level)
+	})
+
+	t.Run("Load reads CONTRAFACTORY_CONFIG_FILE", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv(ConfigFileEnv, path)
+
+		cfg, err := Load()
+		require.NoError(t, err)
+		assert.Equal(t, 7000, cfg.Server.Port)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		clearEnv(t)
+
+		_, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "reading config file")
+	})
+
+	t.Run("invalid YAML", func(t *testing.T) {
+		clearEnv(t)
+
+		_, err := LoadFile(writeConfigFile(t, "server: [unclosed"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "parsing config file")
+	})
+}
+
+func TestLoadFileCompilers(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := LoadFile(writeConfigFile(t, `
+compilers:
+  allowed_versions: [">=0.8.0"]
+  denied_evm_versions: [homestead]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{">=0.8.0"}, cfg.Compilers.AllowedVersions)
+	assert.Equal(t, []string{"homestead"}, cfg.Compilers.DeniedEVMVersions)
+
+	_, err = LoadFile(writeConfigFile(t, `
+compilers:
+  denied_versions: ["^"]
+`))
+	assert.ErrorContains(t, err, "compilers")
+}
+
+func TestLoadFileTLS(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := LoadFile(writeConfigFile(t, `
+server:
+  tls:
+    cert_file: tls.crt
+    key_file: tls.key
+`))
+	require.NoError(t, err)
+	minVersion, err := cfg.Server.TLS.MinTLSVersion()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), minVersion, "defaults to TLS 1.2")
+
+	for name, content := range map[string]string{
+		"cert without key":      "server:\n  tls:\n    cert_file: tls.crt\n",
+		"unknown min version":   "server:\n  tls:\n    cert_file: tls.crt\n    key_file: tls.key\n    min_version: \"1.0\"\n",
+		"redirect without cert": "server:\n  tls:\n    redirect_port: 8081\n",
+	} {
+		_, err := LoadFile(writeConfigFile(t, content))
+		assert.ErrorContains(t, err, "tls:", name)
+	}
+
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Server.TLS.Enabled(), "plaintext by default")
+}
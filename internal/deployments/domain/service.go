@@ -3,13 +3,17 @@ package domain
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/pendergraft/contrafactory/internal/chains/networks"
 	"github.com/pendergraft/contrafactory/internal/storage"
 	"github.com/pendergraft/contrafactory/internal/validation"
 )
@@ -20,6 +24,9 @@ var (
 	ErrPackageNotFound = errors.New("package not found")
 	ErrInvalidAddress  = errors.New("invalid address")
 	ErrInvalidChainID  = errors.New("invalid chain ID")
+	ErrInvalidExplorer = errors.New("invalid explorer")
+	ErrInvalidSalt     = errors.New("invalid salt")
+	ErrInvalidCursor   = errors.New("invalid cursor")
 )
 
 // PackageStore defines the storage operations needed by the deployments domain.
@@ -33,6 +40,7 @@ type DeploymentStore interface {
 	GetDeployment(ctx context.Context, chain, chainID, address string) (*storage.Deployment, error)
 	ListDeployments(ctx context.Context, filter storage.DeploymentFilter, pagination storage.PaginationParams) (*storage.PaginatedResult[storage.Deployment], error)
 	UpdateVerificationStatus(ctx context.Context, id string, verified bool, verifiedOn []string) error
+	ListDeploymentEvents(ctx context.Context, chain, chainID, address string) ([]storage.DeploymentEvent, error)
 }
 
 // DeploymentSummary is a lightweight deployment summary.
@@ -42,6 +50,7 @@ type DeploymentSummary struct {
 	ContractName string `json:"contractName"`
 	Verified     bool   `json:"verified"`
 	TxHash       string `json:"txHash,omitempty"`
+	BlockNumber  int64  `json:"blockNumber,omitempty"`
 }
 
 type service struct {
@@ -59,14 +68,9 @@ func NewService(packages PackageStore, deployments DeploymentStore) *service {
 
 // Record records a new deployment.
 func (s *service) Record(ctx context.Context, req RecordRequest) (*Deployment, error) {
-	// Validate address
-	if err := validation.ValidateAddress(req.Address); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
-	}
-
-	// Validate chain ID
-	if err := validation.ValidateChainID(req.ChainID); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrInvalidChainID, err)
+	chain, chainID, address, err := resolveDeploymentTarget(req)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get package
@@ -78,13 +82,16 @@ func (s *service) Record(ctx context.Context, req RecordRequest) (*Deployment, e
 		return nil, fmt.Errorf("getting package: %w", err)
 	}
 
-	// Build deployment data
-	deploymentData := make(map[string]any)
-	if req.ConstructorArgs != "" {
-		deploymentData["constructorArgs"] = req.ConstructorArgs
+	// Solana programs must be recorded by cluster + program ID, and only against Solana packages
+	if (chain == "solana") != (pkg.Chain == "solana") {
+		if chain == "solana" {
+			return nil, fmt.Errorf("%w: package %s@%s is not a Solana package; use chainId", ErrInvalidChainID, req.Package, req.Version)
+		}
+		return nil, fmt.Errorf("%w: package %s@%s is a Solana package; use cluster", ErrInvalidChainID, req.Package, req.Version)
 	}
-	if len(req.Libraries) > 0 {
-		deploymentData["libraries"] = req.Libraries
+
+	if req.Salt != "" && !isBytes32(req.Salt) {
+		return nil, fmt.Errorf("%w: %q is not 0x-prefixed 32-byte hex", ErrInvalidSalt, req.Salt)
 	}
 
 	deployment := &storage.Deployment{
@@ -92,25 +99,101 @@ func (s *service) Record(ctx context.Context, req RecordRequest) (*Deployment, e
 		PackageID:       pkg.ID,
 		ContractName:    req.Contract,
 		Chain:           pkg.Chain,
-		ChainID:         strconv.Itoa(req.ChainID),
-		Address:         req.Address,
-		DeployerAddress: req.DeployerAddress,
+		ChainID:         chainID,
+		Address:         address,
+		DeployerAddress: normalizeDeployer(req.DeployerAddress),
 		TxHash:          req.TxHash,
 		BlockNumber:     req.BlockNumber,
-		DeploymentData:  deploymentData,
-		Verified:        false,
+		DeploymentData: storage.DeploymentData{
+			ConstructorArgs: req.ConstructorArgs,
+			Libraries:       req.Libraries,
+			Salt:            strings.ToLower(req.Salt),
+			GasUsed:         req.GasUsed,
+			Timestamp:       req.Timestamp.UTC(),
+		},
+		Verified: false,
 	}
 
 	if err := s.deployments.RecordDeployment(ctx, deployment); err != nil {
 		return nil, fmt.Errorf("recording deployment: %w", err)
 	}
 
-	return toDeployment(deployment), nil
+	result := toDeployment(deployment)
+	// Custom and private chains are legitimate, so an unknown chain ID is only a warning
+	if chain == "evm" {
+		if _, ok := networks.Lookup(chainID); !ok {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("chain ID %s is not a known network; no explorer links will be available", chainID))
+		}
+	}
+	return result, nil
+}
+
+// resolveDeploymentTarget validates the address and chain of a record request
+// and returns the address in its canonical stored form.
+// Solana deployments set Cluster and a base58 program ID; everything else is EVM.
+func resolveDeploymentTarget(req RecordRequest) (chain, chainID, address string, err error) {
+	if req.Cluster != "" {
+		if err := validation.ValidateSolanaCluster(req.Cluster); err != nil {
+			return "", "", "", fmt.Errorf("%w: %v", ErrInvalidChainID, err)
+		}
+		if err := validation.ValidateSolanaAddress(req.Address); err != nil {
+			return "", "", "", fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+		}
+		return "solana", req.Cluster, req.Address, nil
+	}
+
+	// Validate address
+	if err := validation.ValidateAddress(req.Address); err != nil {
+		return "", "", "", fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+
+	// Validate chain ID
+	if err := validation.ValidateChainID(req.ChainID); err != nil {
+		return "", "", "", fmt.Errorf("%w: %v", ErrInvalidChainID, err)
+	}
+	return "evm", strconv.Itoa(req.ChainID), validation.NormalizeAddress(req.Address), nil
+}
+
+// normalizeDeployer lowercases a deployer address when it is a valid EVM address.
+// The field is informational, so anything else is stored as given.
+func normalizeDeployer(addr string) string {
+	if addr != "" && validation.ValidateAddress(addr) == nil {
+		return validation.NormalizeAddress(addr)
+	}
+	return addr
+}
+
+// lookupAddress validates an address used to look up a deployment on chainID
+// and returns its canonical stored form.
+func lookupAddress(chainID, address string) (string, error) {
+	if chainForID(chainID) == "solana" {
+		if err := validation.ValidateSolanaAddress(address); err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+		}
+		return address, nil
+	}
+	if err := validation.ValidateAddress(address); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+	return validation.NormalizeAddress(address), nil
+}
+
+// chainForID maps a deployment chain ID to its chain: Solana cluster names, otherwise EVM.
+func chainForID(chainID string) string {
+	if validation.ValidateSolanaCluster(chainID) == nil {
+		return "solana"
+	}
+	return "evm"
 }
 
 // Get retrieves a deployment by chain and address.
 func (s *service) Get(ctx context.Context, chainID, address string) (*Deployment, error) {
-	deployment, err := s.deployments.GetDeployment(ctx, "evm", chainID, address)
+	address, err := lookupAddress(chainID, address)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment, err := s.deployments.GetDeployment(ctx, chainForID(chainID), chainID, address)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			return nil, ErrNotFound
@@ -121,17 +204,49 @@ func (s *service) Get(ctx context.Context, chainID, address string) (*Deployment
 	return toDeployment(deployment), nil
 }
 
+// Events returns the activity timeline of a deployment, oldest first.
+func (s *service) Events(ctx context.Context, chainID, address string) ([]Event, error) {
+	address, err := lookupAddress(chainID, address)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := chainForID(chainID)
+	if _, err := s.deployments.GetDeployment(ctx, chain, chainID, address); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting deployment: %w", err)
+	}
+
+	stored, err := s.deployments.ListDeploymentEvents(ctx, chain, chainID, address)
+	if err != nil {
+		return nil, fmt.Errorf("listing deployment events: %w", err)
+	}
+	events := make([]Event, len(stored))
+	for i, e := range stored {
+		// Parse SQLite datetime format
+		timestamp, _ := time.Parse("2006-01-02 15:04:05", e.CreatedAt)
+		events[i] = Event{Type: e.Type, Timestamp: timestamp, Details: e.Details}
+	}
+	return events, nil
+}
+
 // List lists deployments with filtering and pagination.
 func (s *service) List(ctx context.Context, filter ListFilter, pagination PaginationParams) (*ListResult, error) {
 	result, err := s.deployments.ListDeployments(ctx, storage.DeploymentFilter{
-		Chain:    filter.Chain,
-		ChainID:  filter.ChainID,
-		Package:  filter.Package,
-		Verified: filter.Verified,
+		Chain:        filter.Chain,
+		ChainID:      filter.ChainID,
+		Package:      filter.Package,
+		Verified:     filter.Verified,
+		CreatedAfter: filter.CreatedAfter,
 	}, storage.PaginationParams{
 		Limit:  pagination.Limit,
 		Cursor: pagination.Cursor,
 	})
+	if errors.Is(err, storage.ErrInvalidCursor) {
+		return nil, fmt.Errorf("%w: %q is not a listed deployment", ErrInvalidCursor, pagination.Cursor)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("listing deployments: %w", err)
 	}
@@ -151,7 +266,12 @@ func (s *service) List(ctx context.Context, filter ListFilter, pagination Pagina
 
 // UpdateVerificationStatus updates the verification status of a deployment.
 func (s *service) UpdateVerificationStatus(ctx context.Context, chainID, address string, verified bool, verifiedOn []string) error {
-	deployment, err := s.deployments.GetDeployment(ctx, "evm", chainID, address)
+	address, err := lookupAddress(chainID, address)
+	if err != nil {
+		return err
+	}
+
+	deployment, err := s.deployments.GetDeployment(ctx, chainForID(chainID), chainID, address)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			return ErrNotFound
@@ -166,6 +286,40 @@ func (s *service) UpdateVerificationStatus(ctx context.Context, chainID, address
 	return nil
 }
 
+// MarkVerified records that a deployment was verified on explorer (e.g. "etherscan" or
+// "blockscout:https://eth.blockscout.com"), keeping the explorers it was verified on before.
+func (s *service) MarkVerified(ctx context.Context, chainID, address, explorer string) (*Deployment, error) {
+	explorer = strings.TrimSpace(explorer)
+	if explorer == "" {
+		return nil, fmt.Errorf("%w: explorer is required", ErrInvalidExplorer)
+	}
+
+	address, err := lookupAddress(chainID, address)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment, err := s.deployments.GetDeployment(ctx, chainForID(chainID), chainID, address)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting deployment: %w", err)
+	}
+
+	verifiedOn := deployment.VerifiedOn
+	if !slices.Contains(verifiedOn, explorer) {
+		verifiedOn = append(slices.Clone(verifiedOn), explorer)
+	}
+	if err := s.deployments.UpdateVerificationStatus(ctx, deployment.ID, true, verifiedOn); err != nil {
+		return nil, fmt.Errorf("updating verification status: %w", err)
+	}
+
+	deployment.Verified = true
+	deployment.VerifiedOn = verifiedOn
+	return toDeployment(deployment), nil
+}
+
 // ListByPackage lists deployments for a specific package version.
 func (s *service) ListByPackage(ctx context.Context, packageName, version string) ([]DeploymentSummary, error) {
 	// Get the package to get its ID
@@ -197,6 +351,7 @@ func (s *service) ListByPackage(ctx context.Context, packageName, version string
 				ContractName: d.ContractName,
 				Verified:     d.Verified,
 				TxHash:       d.TxHash,
+				BlockNumber:  d.BlockNumber,
 			})
 		}
 	}
@@ -213,6 +368,8 @@ func toDeployment(d *storage.Deployment) *Deployment {
 	return &Deployment{
 		ID:              d.ID,
 		PackageID:       d.PackageID,
+		PackageName:     d.PackageName,
+		PackageVersion:  d.PackageVersion,
 		ContractName:    d.ContractName,
 		Chain:           d.Chain,
 		ChainID:         d.ChainID,
@@ -220,9 +377,23 @@ func toDeployment(d *storage.Deployment) *Deployment {
 		DeployerAddress: d.DeployerAddress,
 		TxHash:          d.TxHash,
 		BlockNumber:     d.BlockNumber,
-		DeploymentData:  d.DeploymentData,
+		ConstructorArgs: d.DeploymentData.ConstructorArgs,
+		Libraries:       d.DeploymentData.Libraries,
+		Salt:            d.DeploymentData.Salt,
+		GasUsed:         d.DeploymentData.GasUsed,
+		Timestamp:       d.DeploymentData.Timestamp,
 		Verified:        d.Verified,
 		VerifiedOn:      d.VerifiedOn,
 		CreatedAt:       createdAt,
 	}
 }
+
+// isBytes32 reports whether s is 0x-prefixed hex of exactly 32 bytes.
+func isBytes32(s string) bool {
+	digits, ok := strings.CutPrefix(s, "0x")
+	if !ok {
+		return false
+	}
+	b, err := hex.DecodeString(digits)
+	return err == nil && len(b) == 32
+}
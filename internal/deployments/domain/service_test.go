@@ -2,7 +2,9 @@ package domain
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,12 +16,14 @@ import (
 type mockStore struct {
 	packages    map[string]*storage.Package
 	deployments map[string]*storage.Deployment
+	events      map[string][]storage.DeploymentEvent
 }
 
 func newMockStore() *mockStore {
 	return &mockStore{
 		packages:    make(map[string]*storage.Package),
 		deployments: make(map[string]*storage.Deployment),
+		events:      make(map[string][]storage.DeploymentEvent),
 	}
 }
 
@@ -37,6 +41,10 @@ func (m *mockStore) RecordDeployment(ctx context.Context, d *storage.Deployment)
 	return nil
 }
 
+func (m *mockStore) ListDeploymentEvents(ctx context.Context, chain, chainID, address string) ([]storage.DeploymentEvent, error) {
+	return m.events[chain+"/"+chainID+"/"+address], nil
+}
+
 func (m *mockStore) GetDeployment(ctx context.Context, chain, chainID, address string) (*storage.Deployment, error) {
 	key := chain + "/" + chainID + "/" + address
 	if d, ok := m.deployments[key]; ok {
@@ -46,9 +54,14 @@ func (m *mockStore) GetDeployment(ctx context.Context, chain, chainID, address s
 }
 
 func (m *mockStore) ListDeployments(ctx context.Context, filter storage.DeploymentFilter, pagination storage.PaginationParams) (*storage.PaginatedResult[storage.Deployment], error) {
+	cursorFound := pagination.Cursor == ""
 	var deployments []storage.Deployment
 	for _, d := range m.deployments {
 		deployments = append(deployments, *d)
+		cursorFound = cursorFound || d.ID == pagination.Cursor
+	}
+	if !cursorFound {
+		return nil, storage.ErrInvalidCursor
 	}
 	return &storage.PaginatedResult[storage.Deployment]{Data: deployments}, nil
 }
@@ -156,6 +169,107 @@ func TestService_Record(t *testing.T) {
 	}
 }
 
+func TestService_RecordSolana(t *testing.T) {
+	const programID = "Fg6PaFpoGXkYsidMpWTK6W2BeZ7FEfcYkg476zPFsLnS"
+
+	newStore := func() *mockStore {
+		store := newMockStore()
+		store.packages["counter@1.0.0"] = &storage.Package{ID: "pkg-sol", Name: "counter", Chain: "solana"}
+		store.packages["token@1.0.0"] = &storage.Package{ID: "pkg-evm", Name: "token", Chain: "evm"}
+		return store
+	}
+
+	t.Run("record and get by cluster", func(t *testing.T) {
+		store := newStore()
+		svc := NewService(store, store)
+
+		d, err := svc.Record(context.Background(), RecordRequest{
+			Package: "counter", Version: "1.0.0", Contract: "counter",
+			Cluster: "devnet", Address: programID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "solana", d.Chain)
+		assert.Equal(t, "devnet", d.ChainID)
+
+		got, err := svc.Get(context.Background(), "devnet", programID)
+		require.NoError(t, err)
+		assert.Equal(t, d.ID, got.ID)
+	})
+
+	t.Run("invalid cluster", func(t *testing.T) {
+		store := newStore()
+		_, err := NewService(store, store).Record(context.Background(), RecordRequest{
+			Package: "counter", Version: "1.0.0", Cluster: "mainnet", Address: programID,
+		})
+		assert.ErrorIs(t, err, ErrInvalidChainID)
+	})
+
+	t.Run("evm address rejected for cluster", func(t *testing.T) {
+		store := newStore()
+		_, err := NewService(store, store).Record(context.Background(), RecordRequest{
+			Package: "counter", Version: "1.0.0", Cluster: "devnet",
+			Address: "0x1234567890abcdef1234567890abcdef12345678",
+		})
+		assert.ErrorIs(t, err, ErrInvalidAddress)
+	})
+
+	t.Run("cluster on evm package", func(t *testing.T) {
+		store := newStore()
+		_, err := NewService(store, store).Record(context.Background(), RecordRequest{
+			Package: "token", Version: "1.0.0", Cluster: "devnet", Address: programID,
+		})
+		assert.ErrorIs(t, err, ErrInvalidChainID)
+	})
+
+	t.Run("chain ID on solana package", func(t *testing.T) {
+		store := newStore()
+		_, err := NewService(store, store).Record(context.Background(), RecordRequest{
+			Package: "counter", Version: "1.0.0", ChainID: 1,
+			Address: "0x1234567890abcdef1234567890abcdef12345678",
+		})
+		assert.ErrorIs(t, err, ErrInvalidChainID)
+	})
+}
+
+func TestService_AddressNormalization(t *testing.T) {
+	const checksummed = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+	const lower = "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"
+
+	store := newMockStore()
+	store.packages["my-pkg@1.0.0"] = &storage.Package{ID: "pkg-123", Name: "my-pkg", Chain: "evm"}
+	svc := NewService(store, store)
+
+	d, err := svc.Record(context.Background(), RecordRequest{
+		Package: "my-pkg", Version: "1.0.0", Contract: "Token", ChainID: 1,
+		Address: checksummed, DeployerAddress: checksummed,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, lower, d.Address)
+	assert.Equal(t, lower, d.DeployerAddress)
+
+	// Re-recording in another case hits the same row
+	_, err = svc.Record(context.Background(), RecordRequest{
+		Package: "my-pkg", Version: "1.0.0", Contract: "Token", ChainID: 1, Address: lower,
+	})
+	require.NoError(t, err)
+	assert.Len(t, store.deployments, 1)
+
+	for _, addr := range []string{checksummed, lower, "0x" + strings.ToUpper(lower[2:])} {
+		got, err := svc.Get(context.Background(), "1", addr)
+		require.NoError(t, err, addr)
+		assert.Equal(t, lower, got.Address)
+	}
+
+	_, err = svc.Record(context.Background(), RecordRequest{
+		Package: "my-pkg", Version: "1.0.0", Contract: "Token", ChainID: 1,
+		Address: "0xF39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+	})
+	assert.ErrorIs(t, err, ErrInvalidAddress, "bad EIP-55 checksum")
+
+	_, err = svc.Get(context.Background(), "1", "not-an-address")
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+}
+
 func TestService_Get(t *testing.T) {
 	store := newMockStore()
 	store.deployments["evm/1/0x1234567890abcdef1234567890abcdef12345678"] = &storage.Deployment{
@@ -180,6 +294,52 @@ func TestService_Get(t *testing.T) {
 	})
 }
 
+func TestService_ConstructorArgsAndLibraries(t *testing.T) {
+	store := newMockStore()
+	store.packages["my-token@1.0.0"] = &storage.Package{ID: "pkg-1", Name: "my-token", Version: "1.0.0", Chain: "evm"}
+
+	svc := NewService(store, store)
+
+	recorded, err := svc.Record(context.Background(), RecordRequest{
+		Package:         "my-token",
+		Version:         "1.0.0",
+		Contract:        "Token",
+		ChainID:         1,
+		Address:         "0x1234567890abcdef1234567890abcdef12345678",
+		ConstructorArgs: "0x0000000000000000000000000000000000000000000000000000000000000001",
+		Libraries:       map[string]string{"src/Math.sol:Math": "0xabcdef1234567890abcdef1234567890abcdef12"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000001", recorded.ConstructorArgs)
+	assert.Equal(t, "0xabcdef1234567890abcdef1234567890abcdef12", recorded.Libraries["src/Math.sol:Math"])
+
+	t.Run("salt, gas and timestamp", func(t *testing.T) {
+		salt := "0x00000000000000000000000000000000000000000000000000000000000000AA"
+		minedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		_, err := svc.Record(context.Background(), RecordRequest{
+			Package: "my-token", Version: "1.0.0", Contract: "Token", ChainID: 1,
+			Address: "0xabcdef1234567890abcdef1234567890abcdef12",
+			Salt:    salt, GasUsed: 1234567, Timestamp: minedAt,
+		})
+		require.NoError(t, err)
+
+		d, err := svc.Get(context.Background(), "1", "0xabcdef1234567890abcdef1234567890abcdef12")
+		require.NoError(t, err)
+		assert.Equal(t, strings.ToLower(salt), d.Salt)
+		assert.Equal(t, uint64(1234567), d.GasUsed)
+		assert.Equal(t, minedAt, d.Timestamp)
+	})
+
+	t.Run("invalid salt", func(t *testing.T) {
+		_, err := svc.Record(context.Background(), RecordRequest{
+			Package: "my-token", Version: "1.0.0", Contract: "Token", ChainID: 1,
+			Address: "0xabcdef1234567890abcdef1234567890abcdef12",
+			Salt:    "0x1234",
+		})
+		assert.ErrorIs(t, err, ErrInvalidSalt)
+	})
+}
+
 func TestService_List(t *testing.T) {
 	store := newMockStore()
 	store.deployments["evm/1/0x1234567890abcdef1234567890abcdef12345678"] = &storage.Deployment{
@@ -198,6 +358,9 @@ func TestService_List(t *testing.T) {
 	result, err := svc.List(context.Background(), ListFilter{}, PaginationParams{Limit: 10})
 	require.NoError(t, err)
 	assert.Len(t, result.Deployments, 2)
+
+	_, err = svc.List(context.Background(), ListFilter{}, PaginationParams{Limit: 10, Cursor: "deploy-9"})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
 }
 
 func TestService_UpdateVerificationStatus(t *testing.T) {
@@ -221,6 +384,37 @@ func TestService_UpdateVerificationStatus(t *testing.T) {
 	assert.Contains(t, d.VerifiedOn, "etherscan")
 }
 
+func TestService_MarkVerified(t *testing.T) {
+	store := newMockStore()
+	store.deployments["evm/1/0x1234567890abcdef1234567890abcdef12345678"] = &storage.Deployment{
+		ID:         "deploy-123",
+		Chain:      "evm",
+		ChainID:    "1",
+		Address:    "0x1234567890abcdef1234567890abcdef12345678",
+		VerifiedOn: []string{"etherscan"},
+	}
+
+	svc := NewService(store, store)
+	ctx := context.Background()
+
+	d, err := svc.MarkVerified(ctx, "1", "0x1234567890abcdef1234567890abcdef12345678", "blockscout:https://eth.blockscout.com")
+	require.NoError(t, err)
+	assert.True(t, d.Verified)
+	assert.Equal(t, []string{"etherscan", "blockscout:https://eth.blockscout.com"}, d.VerifiedOn)
+
+	// Marking the same explorer twice does not duplicate it
+	d, err = svc.MarkVerified(ctx, "1", "0x1234567890abcdef1234567890abcdef12345678", "etherscan")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"etherscan", "blockscout:https://eth.blockscout.com"}, d.VerifiedOn)
+	assert.Equal(t, d.VerifiedOn, store.deployments["evm/1/0x1234567890abcdef1234567890abcdef12345678"].VerifiedOn)
+
+	_, err = svc.MarkVerified(ctx, "1", "0x1234567890abcdef1234567890abcdef12345678", " ")
+	assert.ErrorIs(t, err, ErrInvalidExplorer)
+
+	_, err = svc.MarkVerified(ctx, "1", "0x0000000000000000000000000000000000000001", "etherscan")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
 func TestToDeployment_TimestampParsing(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -280,3 +474,52 @@ func TestToDeployment_TimestampParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Record_UnknownChainID(t *testing.T) {
+	store := newMockStore()
+	store.packages["my-pkg@1.0.0"] = &storage.Package{ID: "pkg-123", Name: "my-pkg", Chain: "evm"}
+	svc := NewService(store, store)
+
+	req := RecordRequest{
+		Package: "my-pkg", Version: "1.0.0", Contract: "Token",
+		Address: "0x1234567890abcdef1234567890abcdef12345678",
+	}
+
+	req.ChainID = 1
+	d, err := svc.Record(context.Background(), req)
+	require.NoError(t, err)
+	assert.Empty(t, d.Warnings)
+
+	// Custom chains are recorded, with a warning
+	req.ChainID = 99999
+	d, err = svc.Record(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "99999", d.ChainID)
+	require.Len(t, d.Warnings, 1)
+	assert.Contains(t, d.Warnings[0], "chain ID 99999 is not a known network")
+}
+
+func TestService_Events(t *testing.T) {
+	store := newMockStore()
+	store.deployments["evm/1/0x1234567890123456789012345678901234567890"] = &storage.Deployment{ID: "deploy-1", Chain: "evm", ChainID: "1", Address: "0x1234567890123456789012345678901234567890"}
+	store.events["evm/1/0x1234567890123456789012345678901234567890"] = []storage.DeploymentEvent{
+		{Type: storage.DeploymentEventDeployed, Details: map[string]string{"contract": "Token"}, CreatedAt: "2025-01-01 10:00:00"},
+		{Type: storage.DeploymentEventVerified, Details: map[string]string{"explorer": "etherscan"}, CreatedAt: "2025-01-01 10:05:00"},
+	}
+	svc := NewService(store, store)
+	ctx := context.Background()
+
+	// Addresses are looked up in their stored (lowercase) form
+	events, err := svc.Events(ctx, "1", "0x1234567890123456789012345678901234567890")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "deployed", events[0].Type)
+	assert.Equal(t, time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), events[0].Timestamp)
+	assert.Equal(t, "etherscan", events[1].Details["explorer"])
+
+	_, err = svc.Events(ctx, "1", "0x0000000000000000000000000000000000000001")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = svc.Events(ctx, "1", "not-an-address")
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+}
@@ -9,6 +9,8 @@ import (
 type Deployment struct {
 	ID              string
 	PackageID       string
+	PackageName     string
+	PackageVersion  string
 	ContractName    string
 	Chain           string
 	ChainID         string
@@ -16,11 +18,25 @@ type Deployment struct {
 	DeployerAddress string
 	TxHash          string
 	BlockNumber     int64
-	DeploymentData  map[string]any
+	ConstructorArgs string            // ABI-encoded constructor args
+	Libraries       map[string]string // linked libraries
+	Salt            string            // CREATE2 salt
+	GasUsed         uint64
+	Timestamp       time.Time // when the deployment was mined; zero when not recorded
 	Verified        bool
 	VerifiedAt      time.Time
 	VerifiedOn      []string
 	CreatedAt       time.Time
+	// Warnings are advisory problems found when recording, e.g. an unknown chain
+	// ID. They are not stored.
+	Warnings []string
+}
+
+// Event is one entry of a deployment's activity timeline.
+type Event struct {
+	Type      string            // "deployed", "verified" or "unverified"
+	Timestamp time.Time         // when the event was recorded
+	Details   map[string]string // e.g. contract and txHash when deployed, explorer when verified
 }
 
 // RecordRequest is the request to record a new deployment.
@@ -29,12 +45,16 @@ type RecordRequest struct {
 	Version         string            `json:"version"`
 	Contract        string            `json:"contract"`
 	ChainID         int               `json:"chainId"`
-	Address         string            `json:"address"`
+	Cluster         string            `json:"cluster,omitempty"` // Solana cluster (mainnet-beta, devnet, ...) instead of chainId
+	Address         string            `json:"address"`           // EVM address or Solana program ID
 	TxHash          string            `json:"txHash,omitempty"`
 	DeployerAddress string            `json:"deployerAddress,omitempty"`
 	BlockNumber     int64             `json:"blockNumber,omitempty"`
 	ConstructorArgs string            `json:"constructorArgs,omitempty"`
 	Libraries       map[string]string `json:"libraries,omitempty"`
+	Salt            string            `json:"salt,omitempty"` // CREATE2 salt, 0x-prefixed 32 bytes
+	GasUsed         uint64            `json:"gasUsed,omitempty"`
+	Timestamp       time.Time         `json:"timestamp,omitzero"`
 }
 
 // ListFilter contains filter options for listing deployments.
@@ -43,6 +63,8 @@ type ListFilter struct {
 	ChainID  string
 	Package  string
 	Verified *bool
+	// CreatedAfter, when non-zero, only returns deployments recorded at or after it
+	CreatedAfter time.Time
 }
 
 // PaginationParams contains pagination options.
@@ -7,12 +7,16 @@ import (
 	"errors"
 	"io"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/pendergraft/contrafactory/internal/chains/networks"
 	"github.com/pendergraft/contrafactory/internal/deployments/domain"
+	"github.com/pendergraft/contrafactory/internal/jsonnum"
+	"github.com/pendergraft/contrafactory/internal/middleware/requestid"
+	"github.com/pendergraft/contrafactory/internal/pagination"
+	"github.com/pendergraft/contrafactory/internal/server/errcodes"
 )
 
 // Service defines the deployment service interface for HTTP transport.
@@ -21,11 +25,14 @@ type Service interface {
 	Get(ctx context.Context, chainID, address string) (*domain.Deployment, error)
 	List(ctx context.Context, filter domain.ListFilter, pagination domain.PaginationParams) (*domain.ListResult, error)
 	ListByPackage(ctx context.Context, packageName, version string) ([]domain.DeploymentSummary, error)
+	MarkVerified(ctx context.Context, chainID, address, explorer string) (*domain.Deployment, error)
+	Events(ctx context.Context, chainID, address string) ([]domain.Event, error)
 }
 
 // Handler handles HTTP requests for deployments.
 type Handler struct {
-	svc Service
+	svc         Service
+	maxPageSize int // 0 = pagination.DefaultMaxLimit
 }
 
 // NewHandler creates a new deployments HTTP handler.
@@ -33,6 +40,11 @@ func NewHandler(svc Service) *Handler {
 	return &Handler{svc: svc}
 }
 
+// SetMaxPageSize sets the largest limit the list endpoint accepts
+func (h *Handler) SetMaxPageSize(n int) {
+	h.maxPageSize = n
+}
+
 // RegisterRoutes registers all deployment routes on a chi router.
 // Deprecated: Use RegisterReadRoutes and RegisterWriteRoutes for proper auth separation.
 func (h *Handler) RegisterRoutes(r chi.Router) {
@@ -43,20 +55,27 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 // RegisterReadRoutes registers read-only deployment routes (no auth required).
 func (h *Handler) RegisterReadRoutes(r chi.Router) {
 	r.Get("/", h.handleList)
+	r.Get("/verification-status", h.handleVerificationStatus)
 	r.Get("/{chainId}/{address}", h.handleGet)
+	r.Get("/{chainId}/{address}/events", h.handleEvents)
 }
 
 // RegisterWriteRoutes registers write deployment routes (auth required).
 func (h *Handler) RegisterWriteRoutes(r chi.Router) {
 	r.Post("/", h.handleRecord)
+	r.Post("/{chainId}/{address}/verified", h.handleMarkVerified)
+}
+
+// parsePagination reads limit and cursor, capping limit at the handler's max page size
+func (h *Handler) parsePagination(r *http.Request) (pagination.Params, error) {
+	return pagination.Parse(r, h.maxPageSize)
 }
 
 func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
-	limit := 20
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
-			limit = parsed
-		}
+	page, err := h.parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+		return
 	}
 
 	var verified *bool
@@ -65,17 +84,32 @@ func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
 		verified = &b
 	}
 
+	var createdAfter time.Time
+	if v := r.URL.Query().Get("created_after"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "created_after must be an RFC3339 timestamp")
+			return
+		}
+		createdAfter = t
+	}
+
 	result, err := h.svc.List(r.Context(), domain.ListFilter{
-		Chain:    r.URL.Query().Get("chain"),
-		ChainID:  r.URL.Query().Get("chain_id"),
-		Package:  r.URL.Query().Get("package"),
-		Verified: verified,
+		Chain:        r.URL.Query().Get("chain"),
+		ChainID:      r.URL.Query().Get("chain_id"),
+		Package:      r.URL.Query().Get("package"),
+		Verified:     verified,
+		CreatedAfter: createdAfter,
 	}, domain.PaginationParams{
-		Limit:  limit,
-		Cursor: r.URL.Query().Get("cursor"),
+		Limit:  page.Limit,
+		Cursor: page.Cursor,
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to list deployments")
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to list deployments")
 		return
 	}
 
@@ -87,13 +121,14 @@ func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
 			ContractName: d.ContractName,
 			Verified:     d.Verified,
 			TxHash:       d.TxHash,
+			BlockNumber:  jsonnum.New(d.BlockNumber, jsonnum.StringMode(r)),
 		}
 	}
 
 	writeJSON(w, http.StatusOK, DeploymentListResponse{
 		Data: data,
 		Pagination: Pagination{
-			Limit:      limit,
+			Limit:      page.Limit,
 			HasMore:    result.HasMore,
 			NextCursor: result.NextCursor,
 		},
@@ -103,13 +138,13 @@ func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) handleRecord(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Failed to read request body")
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Failed to read request body")
 		return
 	}
 
 	var req RecordRequest
 	if err := json.Unmarshal(body, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid JSON")
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Invalid JSON")
 		return
 	}
 
@@ -117,13 +152,13 @@ func (h *Handler) handleRecord(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrPackageNotFound):
-			writeError(w, http.StatusNotFound, "NOT_FOUND", "Package not found")
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package not found")
 		case errors.Is(err, domain.ErrInvalidAddress):
-			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
-		case errors.Is(err, domain.ErrInvalidChainID):
-			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+		case errors.Is(err, domain.ErrInvalidChainID), errors.Is(err, domain.ErrInvalidSalt):
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
 		default:
-			writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to record deployment")
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to record deployment")
 		}
 		return
 	}
@@ -134,6 +169,7 @@ func (h *Handler) handleRecord(w http.ResponseWriter, r *http.Request) {
 		Address:  deployment.Address,
 		Verified: deployment.Verified,
 		Message:  "Deployment recorded successfully",
+		Warnings: deployment.Warnings,
 	})
 }
 
@@ -143,31 +179,148 @@ func (h *Handler) handleGet(w http.ResponseWriter, r *http.Request) {
 
 	deployment, err := h.svc.Get(r.Context(), chainID, address)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			writeError(w, http.StatusNotFound, "NOT_FOUND", "Deployment not found")
-			return
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Deployment not found")
+		case errors.Is(err, domain.ErrInvalidAddress):
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get deployment")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toDeploymentResponse(r, deployment))
+}
+
+// handleEvents returns a deployment's activity timeline: when it was recorded
+// and verified on each explorer.
+func (h *Handler) handleEvents(w http.ResponseWriter, r *http.Request) {
+	chainID := chi.URLParam(r, "chainId")
+	address := chi.URLParam(r, "address")
+
+	events, err := h.svc.Events(r.Context(), chainID, address)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Deployment not found")
+		case errors.Is(err, domain.ErrInvalidAddress):
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get deployment events")
+		}
+		return
+	}
+
+	resp := EventsResponse{ChainID: chainID, Address: address, Events: make([]EventResponse, len(events))}
+	for i, e := range events {
+		resp.Events[i] = EventResponse{Type: e.Type, Timestamp: e.Timestamp.Format(time.RFC3339), Details: e.Details}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleVerificationStatus reports whether a deployment has been verified on an
+// explorer, for clients polling until an asynchronous verification resolves.
+func (h *Handler) handleVerificationStatus(w http.ResponseWriter, r *http.Request) {
+	chainID := r.URL.Query().Get("chainId")
+	address := r.URL.Query().Get("address")
+	if chainID == "" || address == "" {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "chainId and address are required")
+		return
+	}
+
+	deployment, err := h.svc.Get(r.Context(), chainID, address)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Deployment not found")
+		case errors.Is(err, domain.ErrInvalidAddress):
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get deployment")
+		}
+		return
+	}
+
+	status := VerificationStatusUnverified
+	if deployment.Verified {
+		status = VerificationStatusVerified
+	}
+	verifiedOn := deployment.VerifiedOn
+	if verifiedOn == nil {
+		verifiedOn = []string{}
+	}
+	writeJSON(w, http.StatusOK, VerificationStatusResponse{
+		ChainID:    deployment.ChainID,
+		Address:    deployment.Address,
+		Status:     status,
+		Verified:   deployment.Verified,
+		VerifiedOn: verifiedOn,
+	})
+}
+
+func (h *Handler) handleMarkVerified(w http.ResponseWriter, r *http.Request) {
+	chainID := chi.URLParam(r, "chainId")
+	address := chi.URLParam(r, "address")
+
+	var req MarkVerifiedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Invalid JSON")
+		return
+	}
+
+	deployment, err := h.svc.MarkVerified(r.Context(), chainID, address, req.Explorer)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Deployment not found")
+		case errors.Is(err, domain.ErrInvalidAddress), errors.Is(err, domain.ErrInvalidChainID), errors.Is(err, domain.ErrInvalidExplorer):
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to update verification status")
 		}
-		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get deployment")
 		return
 	}
 
+	writeJSON(w, http.StatusOK, toDeploymentResponse(r, deployment))
+}
+
+func toDeploymentResponse(r *http.Request, deployment *domain.Deployment) DeploymentResponse {
 	verifiedOn := deployment.VerifiedOn
 	if verifiedOn == nil {
 		verifiedOn = []string{}
 	}
-	writeJSON(w, http.StatusOK, DeploymentResponse{
+	var timestamp string
+	if !deployment.Timestamp.IsZero() {
+		timestamp = deployment.Timestamp.Format(time.RFC3339)
+	}
+	var network, explorerURL string
+	if deployment.Chain != "solana" {
+		network = networks.Name(deployment.ChainID)
+		explorerURL = networks.ExplorerAddressURL(deployment.ChainID, deployment.Address)
+	}
+	return DeploymentResponse{
 		ID:              deployment.ID,
 		PackageID:       deployment.PackageID,
+		PackageName:     deployment.PackageName,
+		PackageVersion:  deployment.PackageVersion,
 		ChainID:         deployment.ChainID,
 		Address:         deployment.Address,
 		ContractName:    deployment.ContractName,
 		DeployerAddress: deployment.DeployerAddress,
 		TxHash:          deployment.TxHash,
-		BlockNumber:     deployment.BlockNumber,
+		BlockNumber:     jsonnum.New(deployment.BlockNumber, jsonnum.StringMode(r)),
+		ConstructorArgs: deployment.ConstructorArgs,
+		Libraries:       deployment.Libraries,
+		Salt:            deployment.Salt,
+		GasUsed:         deployment.GasUsed,
+		Timestamp:       timestamp,
 		Verified:        deployment.Verified,
 		VerifiedOn:      verifiedOn,
 		CreatedAt:       deployment.CreatedAt.Format(time.RFC3339),
-	})
+		Network:         network,
+		ExplorerURL:     explorerURL,
+	}
 }
 
 // Helper functions
@@ -182,6 +335,6 @@ func writeError(w http.ResponseWriter, status int, code, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(ErrorResponse{
-		Error: ErrorDetail{Code: code, Message: message},
+		Error: ErrorDetail{Code: code, Message: message, RequestID: w.Header().Get(requestid.Header)},
 	})
 }
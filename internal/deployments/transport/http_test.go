@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -17,7 +20,9 @@ import (
 
 // mockService implements Service for testing
 type mockService struct {
-	deployments map[string]*domain.Deployment
+	deployments    map[string]*domain.Deployment
+	listFilter     domain.ListFilter
+	listPagination domain.PaginationParams
 }
 
 func newMockService() *mockService {
@@ -47,11 +52,20 @@ func (m *mockService) Get(ctx context.Context, chainID, address string) (*domain
 }
 
 func (m *mockService) List(ctx context.Context, filter domain.ListFilter, pagination domain.PaginationParams) (*domain.ListResult, error) {
+	m.listFilter = filter
+	m.listPagination = pagination
+	if pagination.Cursor == "bogus" {
+		return nil, fmt.Errorf("%w: %q is not a listed deployment", domain.ErrInvalidCursor, pagination.Cursor)
+	}
 	var deployments []domain.Deployment
 	for _, d := range m.deployments {
 		deployments = append(deployments, *d)
 	}
-	return &domain.ListResult{Deployments: deployments}, nil
+	result := &domain.ListResult{Deployments: deployments}
+	if len(deployments) > 0 {
+		result.NextCursor = deployments[len(deployments)-1].ID
+	}
+	return result, nil
 }
 
 func (m *mockService) ListByPackage(ctx context.Context, packageName, version string) ([]domain.DeploymentSummary, error) {
@@ -68,6 +82,31 @@ func (m *mockService) ListByPackage(ctx context.Context, packageName, version st
 	return summaries, nil
 }
 
+func (m *mockService) MarkVerified(ctx context.Context, chainID, address, explorer string) (*domain.Deployment, error) {
+	if explorer == "" {
+		return nil, domain.ErrInvalidExplorer
+	}
+	d, ok := m.deployments[chainID+"/"+address]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	d.Verified = true
+	d.VerifiedOn = append(d.VerifiedOn, explorer)
+	return d, nil
+}
+
+func (m *mockService) Events(ctx context.Context, chainID, address string) ([]domain.Event, error) {
+	d, ok := m.deployments[chainID+"/"+address]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	events := []domain.Event{{Type: "deployed", Timestamp: d.CreatedAt, Details: map[string]string{"contract": d.ContractName}}}
+	for _, explorer := range d.VerifiedOn {
+		events = append(events, domain.Event{Type: "verified", Timestamp: d.VerifiedAt, Details: map[string]string{"explorer": explorer}})
+	}
+	return events, nil
+}
+
 func setupRouter(svc Service) *chi.Mux {
 	r := chi.NewRouter()
 	h := NewHandler(svc)
@@ -101,6 +140,61 @@ func TestHandler_List(t *testing.T) {
 	assert.Contains(t, resp, "pagination")
 }
 
+func TestHandler_List_Limit(t *testing.T) {
+	svc := newMockService()
+	r := chi.NewRouter()
+	h := NewHandler(svc)
+	h.SetMaxPageSize(50)
+	r.Route("/deployments", h.RegisterRoutes)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/deployments/?limit=500&cursor=abc", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, domain.PaginationParams{Limit: 50, Cursor: "abc"}, svc.listPagination, "limit is capped at the max page size")
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/deployments/?limit=many", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "limit must be a positive integer")
+}
+
+func TestHandler_List_Cursor(t *testing.T) {
+	svc := newMockService()
+	svc.deployments["1/0x1234567890abcdef1234567890abcdef12345678"] = &domain.Deployment{
+		ID:      "deploy-1",
+		ChainID: "1",
+		Address: "0x1234567890abcdef1234567890abcdef12345678",
+	}
+	router := setupRouter(svc)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/deployments/?limit=1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var resp DeploymentListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "deploy-1", resp.Pagination.NextCursor)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/deployments/?cursor=bogus", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "INVALID_REQUEST")
+}
+
+func TestHandler_List_CreatedAfter(t *testing.T) {
+	svc := newMockService()
+	router := setupRouter(svc)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/deployments/?created_after=2024-05-01T10:00:00Z", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, svc.listFilter.CreatedAfter.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/deployments/?created_after=yesterday", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "INVALID_REQUEST")
+}
+
 func TestHandler_Record(t *testing.T) {
 	svc := newMockService()
 	router := setupRouter(svc)
@@ -131,12 +225,17 @@ func TestHandler_Record(t *testing.T) {
 func TestHandler_Get(t *testing.T) {
 	svc := newMockService()
 	svc.deployments["1/0x1234567890abcdef1234567890abcdef12345678"] = &domain.Deployment{
-		ID:           "deploy-1",
-		ChainID:      "1",
-		Address:      "0x1234567890abcdef1234567890abcdef12345678",
-		ContractName: "Token",
-		Verified:     true,
-		VerifiedOn:   []string{"etherscan"},
+		ID:              "deploy-1",
+		ChainID:         "1",
+		Address:         "0x1234567890abcdef1234567890abcdef12345678",
+		ContractName:    "Token",
+		Verified:        true,
+		VerifiedOn:      []string{"etherscan"},
+		ConstructorArgs: "0x01",
+		Libraries:       map[string]string{"Math": "0xabcdef1234567890abcdef1234567890abcdef12"},
+		GasUsed:         1234567,
+		Timestamp:       time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		BlockNumber:     9007199254740993, // 2^53 + 1
 	}
 
 	router := setupRouter(svc)
@@ -155,6 +254,27 @@ func TestHandler_Get(t *testing.T) {
 		assert.Equal(t, "1", resp["chainId"])
 		assert.Equal(t, "0x1234567890abcdef1234567890abcdef12345678", resp["address"])
 		assert.Equal(t, true, resp["verified"])
+		assert.Equal(t, "0x01", resp["constructorArgs"])
+		assert.Equal(t, map[string]any{"Math": "0xabcdef1234567890abcdef1234567890abcdef12"}, resp["libraries"])
+		assert.Equal(t, float64(1234567), resp["gasUsed"])
+		assert.Equal(t, "2026-01-02T03:04:05Z", resp["timestamp"])
+		assert.NotContains(t, resp, "salt")
+		assert.Equal(t, "ethereum", resp["network"])
+		assert.Equal(t, "https://etherscan.io/address/0x1234567890abcdef1234567890abcdef12345678#code", resp["explorerUrl"])
+	})
+
+	t.Run("block number as number by default", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/deployments/1/0x1234567890abcdef1234567890abcdef12345678", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Contains(t, rec.Body.String(), `"blockNumber":9007199254740993`)
+	})
+
+	t.Run("block number as string with bigints=string", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/deployments/1/0x1234567890abcdef1234567890abcdef12345678?bigints=string", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Contains(t, rec.Body.String(), `"blockNumber":"9007199254740993"`)
 	})
 
 	t.Run("non-existing deployment", func(t *testing.T) {
@@ -167,6 +287,31 @@ func TestHandler_Get(t *testing.T) {
 	})
 }
 
+func TestHandler_Record_BlockNumberString(t *testing.T) {
+	var got domain.RecordRequest
+	svc := &recordCapture{mockService: newMockService(), got: &got}
+	router := setupRouter(svc)
+
+	body := `{"package":"p","version":"1.0.0","contract":"C","chainId":1,"address":"0x1234567890abcdef1234567890abcdef12345678","blockNumber":"9007199254740993"}`
+	req := httptest.NewRequest("POST", "/deployments/", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, int64(9007199254740993), got.BlockNumber)
+}
+
+// recordCapture records the domain request passed to Record.
+type recordCapture struct {
+	*mockService
+	got *domain.RecordRequest
+}
+
+func (r *recordCapture) Record(ctx context.Context, req domain.RecordRequest) (*domain.Deployment, error) {
+	*r.got = req
+	return r.mockService.Record(ctx, req)
+}
+
 func TestHandler_Record_InvalidJSON(t *testing.T) {
 	svc := newMockService()
 	router := setupRouter(svc)
@@ -179,3 +324,97 @@ func TestHandler_Record_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
+
+func TestHandler_MarkVerified(t *testing.T) {
+	svc := newMockService()
+	svc.deployments["1/0x1234567890abcdef1234567890abcdef12345678"] = &domain.Deployment{
+		ID:      "deploy-1",
+		ChainID: "1",
+		Address: "0x1234567890abcdef1234567890abcdef12345678",
+	}
+	router := setupRouter(svc)
+
+	post := func(address, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/deployments/1/"+address+"/verified", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post("0x1234567890abcdef1234567890abcdef12345678", `{"explorer":"blockscout:https://eth.blockscout.com"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp DeploymentResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.Verified)
+	assert.Equal(t, []string{"blockscout:https://eth.blockscout.com"}, resp.VerifiedOn)
+
+	assert.Equal(t, http.StatusBadRequest, post("0x1234567890abcdef1234567890abcdef12345678", `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post("0x1234567890abcdef1234567890abcdef12345678", `not json`).Code)
+	assert.Equal(t, http.StatusNotFound, post("0x0000000000000000000000000000000000000001", `{"explorer":"etherscan"}`).Code)
+}
+
+func TestHandler_VerificationStatus(t *testing.T) {
+	svc := newMockService()
+	svc.deployments["1/0x1234567890abcdef1234567890abcdef12345678"] = &domain.Deployment{
+		ChainID: "1",
+		Address: "0x1234567890abcdef1234567890abcdef12345678",
+	}
+	router := setupRouter(svc)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/deployments/verification-status?"+query, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("chainId=1&address=0x1234567890abcdef1234567890abcdef12345678")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp VerificationStatusResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, VerificationStatusUnverified, resp.Status)
+	assert.False(t, resp.Verified)
+	assert.Equal(t, []string{}, resp.VerifiedOn)
+
+	svc.deployments["1/0x1234567890abcdef1234567890abcdef12345678"].Verified = true
+	svc.deployments["1/0x1234567890abcdef1234567890abcdef12345678"].VerifiedOn = []string{"etherscan"}
+	rec = get("chainId=1&address=0x1234567890abcdef1234567890abcdef12345678")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, VerificationStatusVerified, resp.Status)
+	assert.Equal(t, []string{"etherscan"}, resp.VerifiedOn)
+
+	assert.Equal(t, http.StatusBadRequest, get("chainId=1").Code)
+	assert.Equal(t, http.StatusNotFound, get("chainId=1&address=0x0000000000000000000000000000000000000001").Code)
+}
+
+func TestHandler_Events(t *testing.T) {
+	svc := newMockService()
+	svc.deployments["1/0xabc"] = &domain.Deployment{
+		ChainID:      "1",
+		Address:      "0xabc",
+		ContractName: "Token",
+		CreatedAt:    time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+		VerifiedAt:   time.Date(2025, 1, 1, 10, 5, 0, 0, time.UTC),
+		VerifiedOn:   []string{"etherscan"},
+	}
+	router := setupRouter(svc)
+
+	req := httptest.NewRequest("GET", "/deployments/1/0xabc/events", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{
+		"chainId": "1",
+		"address": "0xabc",
+		"events": [
+			{"type": "deployed", "timestamp": "2025-01-01T10:00:00Z", "details": {"contract": "Token"}},
+			{"type": "verified", "timestamp": "2025-01-01T10:05:00Z", "details": {"explorer": "etherscan"}}
+		]
+	}`, rec.Body.String())
+
+	req = httptest.NewRequest("GET", "/deployments/1/0xdef/events", nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -1,7 +1,12 @@
 // Package transport provides HTTP request/response types for the deployments domain.
 package transport
 
-import "github.com/pendergraft/contrafactory/internal/deployments/domain"
+import (
+	"time"
+
+	"github.com/pendergraft/contrafactory/internal/deployments/domain"
+	"github.com/pendergraft/contrafactory/internal/jsonnum"
+)
 
 // RecordRequest is the HTTP request body for recording a deployment.
 type RecordRequest struct {
@@ -9,12 +14,16 @@ type RecordRequest struct {
 	Version         string            `json:"version"`
 	Contract        string            `json:"contract"`
 	ChainID         int               `json:"chainId"`
+	Cluster         string            `json:"cluster,omitempty"`
 	Address         string            `json:"address"`
 	TxHash          string            `json:"txHash,omitempty"`
 	DeployerAddress string            `json:"deployerAddress,omitempty"`
-	BlockNumber     int64             `json:"blockNumber,omitempty"`
+	BlockNumber     jsonnum.Int64     `json:"blockNumber"` // number or decimal string
 	ConstructorArgs string            `json:"constructorArgs,omitempty"`
 	Libraries       map[string]string `json:"libraries,omitempty"`
+	Salt            string            `json:"salt,omitempty"`
+	GasUsed         uint64            `json:"gasUsed,omitempty"`
+	Timestamp       time.Time         `json:"timestamp,omitzero"` // RFC 3339
 }
 
 // ToDomain converts RecordRequest to domain.RecordRequest.
@@ -24,12 +33,16 @@ func (r RecordRequest) ToDomain() domain.RecordRequest {
 		Version:         r.Version,
 		Contract:        r.Contract,
 		ChainID:         r.ChainID,
+		Cluster:         r.Cluster,
 		Address:         r.Address,
 		TxHash:          r.TxHash,
 		DeployerAddress: r.DeployerAddress,
-		BlockNumber:     r.BlockNumber,
+		BlockNumber:     r.BlockNumber.Value,
 		ConstructorArgs: r.ConstructorArgs,
 		Libraries:       r.Libraries,
+		Salt:            r.Salt,
+		GasUsed:         r.GasUsed,
+		Timestamp:       r.Timestamp,
 	}
 }
 
@@ -41,11 +54,12 @@ type DeploymentListResponse struct {
 
 // DeploymentItem is a deployment in a list.
 type DeploymentItem struct {
-	ChainID      string `json:"chainId"`
-	Address      string `json:"address"`
-	ContractName string `json:"contractName"`
-	Verified     bool   `json:"verified"`
-	TxHash       string `json:"txHash,omitempty"`
+	ChainID      string        `json:"chainId"`
+	Address      string        `json:"address"`
+	ContractName string        `json:"contractName"`
+	Verified     bool          `json:"verified"`
+	TxHash       string        `json:"txHash,omitempty"`
+	BlockNumber  jsonnum.Int64 `json:"blockNumber"`
 }
 
 // Pagination provides pagination metadata.
@@ -57,26 +71,70 @@ type Pagination struct {
 
 // DeploymentResponse is the response for getting a deployment.
 type DeploymentResponse struct {
-	ID              string   `json:"id"`
-	PackageID       string   `json:"packageId"`
-	ChainID         string   `json:"chainId"`
-	Address         string   `json:"address"`
-	ContractName    string   `json:"contractName"`
-	DeployerAddress string   `json:"deployerAddress"`
-	TxHash          string   `json:"txHash"`
-	BlockNumber     int64    `json:"blockNumber"`
-	Verified        bool     `json:"verified"`
-	VerifiedOn      []string `json:"verifiedOn"`
-	CreatedAt       string   `json:"createdAt"`
+	ID              string            `json:"id"`
+	PackageID       string            `json:"packageId"`
+	PackageName     string            `json:"packageName,omitempty"`
+	PackageVersion  string            `json:"packageVersion,omitempty"`
+	ChainID         string            `json:"chainId"`
+	Address         string            `json:"address"`
+	ContractName    string            `json:"contractName"`
+	DeployerAddress string            `json:"deployerAddress"`
+	TxHash          string            `json:"txHash"`
+	BlockNumber     jsonnum.Int64     `json:"blockNumber"`
+	ConstructorArgs string            `json:"constructorArgs,omitempty"`
+	Libraries       map[string]string `json:"libraries,omitempty"`
+	Salt            string            `json:"salt,omitempty"`
+	GasUsed         uint64            `json:"gasUsed,omitempty"`
+	Timestamp       string            `json:"timestamp,omitempty"` // when the deployment was mined
+	Verified        bool              `json:"verified"`
+	VerifiedOn      []string          `json:"verifiedOn"`
+	CreatedAt       string            `json:"createdAt"`
+	Network         string            `json:"network,omitempty"`     // canonical name of a known chain ID
+	ExplorerURL     string            `json:"explorerUrl,omitempty"` // contract page on the chain's explorer
+}
+
+// MarkVerifiedRequest is the HTTP request body for recording an explorer verification.
+type MarkVerifiedRequest struct {
+	Explorer string `json:"explorer"` // e.g. "etherscan", "blockscout:https://eth.blockscout.com"
+}
+
+// Verification statuses of a deployment
+const (
+	VerificationStatusVerified   = "verified"
+	VerificationStatusUnverified = "unverified"
+)
+
+// VerificationStatusResponse is the response for a deployment's verification status.
+type VerificationStatusResponse struct {
+	ChainID    string   `json:"chainId"`
+	Address    string   `json:"address"`
+	Status     string   `json:"status"` // verified or unverified
+	Verified   bool     `json:"verified"`
+	VerifiedOn []string `json:"verifiedOn"`
+}
+
+// EventsResponse is a deployment's activity timeline, oldest first.
+type EventsResponse struct {
+	ChainID string          `json:"chainId"`
+	Address string          `json:"address"`
+	Events  []EventResponse `json:"events"`
+}
+
+// EventResponse is one entry of a deployment's timeline.
+type EventResponse struct {
+	Type      string            `json:"type"` // deployed, verified or unverified
+	Timestamp string            `json:"timestamp"`
+	Details   map[string]string `json:"details,omitempty"`
 }
 
 // RecordResponse is the response for recording a deployment.
 type RecordResponse struct {
-	ID       string `json:"id"`
-	ChainID  string `json:"chainId"`
-	Address  string `json:"address"`
-	Verified bool   `json:"verified"`
-	Message  string `json:"message"`
+	ID       string   `json:"id"`
+	ChainID  string   `json:"chainId"`
+	Address  string   `json:"address"`
+	Verified bool     `json:"verified"`
+	Message  string   `json:"message"`
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // ErrorResponse is the standard error response format.
@@ -86,6 +144,7 @@ type ErrorResponse struct {
 
 // ErrorDetail contains error information.
 type ErrorDetail struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"requestId,omitempty"`
 }
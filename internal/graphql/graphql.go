@@ -0,0 +1,319 @@
+// Package graphql serves a read-only GraphQL API over the packages and deployments
+// domain services, so a client can fetch a package with its contracts, versions and
+// deployments in one request instead of one per resource.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	gql "github.com/graphql-go/graphql"
+
+	deploymentsDomain "github.com/pendergraft/contrafactory/internal/deployments/domain"
+	"github.com/pendergraft/contrafactory/internal/middleware/requestid"
+	packagesDomain "github.com/pendergraft/contrafactory/internal/packages/domain"
+	"github.com/pendergraft/contrafactory/internal/server/errcodes"
+)
+
+// PackageService is the subset of the packages service the schema reads from.
+type PackageService interface {
+	Get(ctx context.Context, name, version string) (*packagesDomain.Package, error)
+	GetVersions(ctx context.Context, name string, opts packagesDomain.VersionsOptions) (*packagesDomain.VersionsResult, error)
+	GetContracts(ctx context.Context, name, version string) ([]packagesDomain.Contract, error)
+}
+
+// DeploymentService is the subset of the deployments service the schema reads from.
+type DeploymentService interface {
+	ListByPackage(ctx context.Context, packageName, version string) ([]deploymentsDomain.DeploymentSummary, error)
+}
+
+// Handler serves GraphQL queries over HTTP.
+type Handler struct {
+	schema gql.Schema
+}
+
+// NewHandler creates a GraphQL handler backed by the given services. The schema
+// only has a query type; mutations are rejected during validation.
+func NewHandler(packages PackageService, deployments DeploymentService) *Handler {
+	schema, err := newSchema(packages, deployments)
+	if err != nil {
+		// The schema is static, so this only fails on a programming error
+		panic("graphql: building schema: " + err.Error())
+	}
+	return &Handler{schema: schema}
+}
+
+// request is a GraphQL request, as a POST body or GET query parameters.
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// ServeHTTP executes the query of a GET (?query=&variables=&operationName=) or POST
+// (JSON body) request. Query errors are reported in the response's errors array with
+// status 200, as GraphQL clients expect; only malformed requests get a 400.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "variables must be a JSON object")
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Invalid JSON body")
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, errcodes.InvalidRequest, "GraphQL queries must use GET or POST")
+		return
+	}
+	if req.Query == "" {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "query is required")
+		return
+	}
+
+	result := gql.Do(gql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        r.Context(),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// errInternal is reported in place of storage errors, which may leak internals.
+var errInternal = errors.New("internal error")
+
+// newSchema builds the schema:
+//
+//	type Query {
+//	  package(name: String!, version: String = "latest"): Package
+//	}
+//	type Package {
+//	  name, version, project, chain, builder, compilerVersion, createdAt: String
+//	  metadata: [MetadataEntry!]!
+//	  versions(includePrerelease: Boolean = false): [String!]!
+//	  contracts: [Contract!]!
+//	  deployments: [Deployment!]!
+//	}
+//
+// Types don't refer back to Package, so query depth is bounded by the schema itself.
+func newSchema(packages PackageService, deployments DeploymentService) (gql.Schema, error) {
+	metadataEntryType := gql.NewObject(gql.ObjectConfig{
+		Name:        "MetadataEntry",
+		Description: "A free-form key/value metadata pair.",
+		Fields: gql.Fields{
+			"key":   &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"value": &gql.Field{Type: gql.NewNonNull(gql.String)},
+		},
+	})
+	metadataField := func(get func(any) map[string]string) *gql.Field {
+		return &gql.Field{
+			Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(metadataEntryType))),
+			Resolve: func(p gql.ResolveParams) (any, error) {
+				return metadataEntries(get(p.Source)), nil
+			},
+		}
+	}
+
+	contractType := gql.NewObject(gql.ObjectConfig{
+		Name:        "Contract",
+		Description: "A contract within a package version.",
+		Fields: gql.Fields{
+			"name":            stringField(func(c packagesDomain.Contract) string { return c.Name }),
+			"sourcePath":      stringField(func(c packagesDomain.Contract) string { return c.SourcePath }),
+			"chain":           stringField(func(c packagesDomain.Contract) string { return c.Chain }),
+			"license":         stringField(func(c packagesDomain.Contract) string { return c.License }),
+			"compilerVersion": stringField(func(c packagesDomain.Contract) string { return c.CompilerVersion }),
+			"labels": &gql.Field{
+				Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(gql.String))),
+				Resolve: func(p gql.ResolveParams) (any, error) {
+					labels := p.Source.(packagesDomain.Contract).Labels
+					if labels == nil {
+						labels = []string{}
+					}
+					return labels, nil
+				},
+			},
+			"metadata": metadataField(func(src any) map[string]string { return src.(packagesDomain.Contract).Metadata }),
+		},
+	})
+
+	deploymentType := gql.NewObject(gql.ObjectConfig{
+		Name:        "Deployment",
+		Description: "A recorded deployment of one of the package version's contracts.",
+		Fields: gql.Fields{
+			"chainId":      stringField(func(d deploymentsDomain.DeploymentSummary) string { return d.ChainID }),
+			"address":      stringField(func(d deploymentsDomain.DeploymentSummary) string { return d.Address }),
+			"contractName": stringField(func(d deploymentsDomain.DeploymentSummary) string { return d.ContractName }),
+			"txHash":       stringField(func(d deploymentsDomain.DeploymentSummary) string { return d.TxHash }),
+			"verified": &gql.Field{
+				Type: gql.NewNonNull(gql.Boolean),
+				Resolve: func(p gql.ResolveParams) (any, error) {
+					return p.Source.(deploymentsDomain.DeploymentSummary).Verified, nil
+				},
+			},
+			"blockNumber": &gql.Field{
+				Type: gql.Int,
+				Resolve: func(p gql.ResolveParams) (any, error) {
+					if n := p.Source.(deploymentsDomain.DeploymentSummary).BlockNumber; n != 0 {
+						return n, nil
+					}
+					return nil, nil
+				},
+			},
+		},
+	})
+
+	packageType := gql.NewObject(gql.ObjectConfig{
+		Name:        "Package",
+		Description: "A published package version.",
+		Fields: gql.Fields{
+			"name":            stringField(func(p *packagesDomain.Package) string { return p.Name }),
+			"version":         stringField(func(p *packagesDomain.Package) string { return p.Version }),
+			"project":         stringField(func(p *packagesDomain.Package) string { return p.Project }),
+			"chain":           stringField(func(p *packagesDomain.Package) string { return p.Chain }),
+			"builder":         stringField(func(p *packagesDomain.Package) string { return p.Builder }),
+			"compilerVersion": stringField(func(p *packagesDomain.Package) string { return p.CompilerVersion }),
+			"createdAt": stringField(func(p *packagesDomain.Package) string {
+				if p.CreatedAt.IsZero() {
+					return ""
+				}
+				return p.CreatedAt.Format(time.RFC3339)
+			}),
+			"metadata": metadataField(func(src any) map[string]string { return src.(*packagesDomain.Package).Metadata }),
+			"versions": &gql.Field{
+				Type:        gql.NewNonNull(gql.NewList(gql.NewNonNull(gql.String))),
+				Description: "All published versions of the package, newest first.",
+				Args: gql.FieldConfigArgument{
+					"includePrerelease": &gql.ArgumentConfig{Type: gql.Boolean, DefaultValue: false},
+				},
+				Resolve: func(p gql.ResolveParams) (any, error) {
+					pkg := p.Source.(*packagesDomain.Package)
+					includePrerelease, _ := p.Args["includePrerelease"].(bool)
+					result, err := packages.GetVersions(p.Context, pkg.Name, packagesDomain.VersionsOptions{IncludePrerelease: includePrerelease})
+					if err != nil {
+						if errors.Is(err, packagesDomain.ErrNotFound) {
+							return []string{}, nil
+						}
+						return nil, errInternal
+					}
+					return result.Versions, nil
+				},
+			},
+			"contracts": &gql.Field{
+				Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(contractType))),
+				Resolve: func(p gql.ResolveParams) (any, error) {
+					pkg := p.Source.(*packagesDomain.Package)
+					contracts, err := packages.GetContracts(p.Context, pkg.Name, pkg.Version)
+					if err != nil {
+						return nil, errInternal
+					}
+					if contracts == nil {
+						contracts = []packagesDomain.Contract{}
+					}
+					return contracts, nil
+				},
+			},
+			"deployments": &gql.Field{
+				Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(deploymentType))),
+				Resolve: func(p gql.ResolveParams) (any, error) {
+					pkg := p.Source.(*packagesDomain.Package)
+					summaries, err := deployments.ListByPackage(p.Context, pkg.Name, pkg.Version)
+					if err != nil && !errors.Is(err, deploymentsDomain.ErrPackageNotFound) {
+						return nil, errInternal
+					}
+					if summaries == nil {
+						summaries = []deploymentsDomain.DeploymentSummary{}
+					}
+					return summaries, nil
+				},
+			},
+		},
+	})
+
+	queryType := gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"package": &gql.Field{
+				Type:        packageType,
+				Description: "A package version; null when it doesn't exist. version defaults to latest.",
+				Args: gql.FieldConfigArgument{
+					"name":    &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+					"version": &gql.ArgumentConfig{Type: gql.String, DefaultValue: "latest"},
+				},
+				Resolve: func(p gql.ResolveParams) (any, error) {
+					name, _ := p.Args["name"].(string)
+					version, _ := p.Args["version"].(string)
+					pkg, err := packages.Get(p.Context, name, version)
+					if err != nil {
+						if errors.Is(err, packagesDomain.ErrNotFound) {
+							return nil, nil
+						}
+						return nil, errInternal
+					}
+					return pkg, nil
+				},
+			},
+		},
+	})
+
+	return gql.NewSchema(gql.SchemaConfig{Query: queryType})
+}
+
+// stringField is a nullable String field read from a source of type T; empty
+// strings resolve to null.
+func stringField[T any](get func(T) string) *gql.Field {
+	return &gql.Field{
+		Type: gql.String,
+		Resolve: func(p gql.ResolveParams) (any, error) {
+			if s := get(p.Source.(T)); s != "" {
+				return s, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+// metadataEntry is a resolved MetadataEntry.
+type metadataEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// metadataEntries turns a metadata map into entries sorted by key.
+func metadataEntries(m map[string]string) []metadataEntry {
+	entries := make([]metadataEntry, 0, len(m))
+	for k, v := range m {
+		entries = append(entries, metadataEntry{Key: k, Value: v})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+// writeError writes an error in the API's usual envelope.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	detail := map[string]string{"code": code, "message": message}
+	if id := w.Header().Get(requestid.Header); id != "" {
+		detail["requestId"] = id
+	}
+	json.NewEncoder(w).Encode(map[string]any{"error": detail})
+}
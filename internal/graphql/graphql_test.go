@@ -0,0 +1,192 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	deploymentsDomain "github.com/pendergraft/contrafactory/internal/deployments/domain"
+	packagesDomain "github.com/pendergraft/contrafactory/internal/packages/domain"
+)
+
+// mockPackages implements PackageService for testing
+type mockPackages struct {
+	packages  map[string]*packagesDomain.Package // keyed name@version
+	contracts map[string][]packagesDomain.Contract
+	versions  []string
+	err       error
+}
+
+func (m *mockPackages) Get(ctx context.Context, name, version string) (*packagesDomain.Package, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if version == "latest" {
+		// Newest stable version, as the real service resolves it
+		for _, v := range m.versions {
+			if !strings.Contains(v, "-") {
+				version = v
+				break
+			}
+		}
+	}
+	if p, ok := m.packages[name+"@"+version]; ok {
+		return p, nil
+	}
+	return nil, packagesDomain.ErrNotFound
+}
+
+func (m *mockPackages) GetVersions(ctx context.Context, name string, opts packagesDomain.VersionsOptions) (*packagesDomain.VersionsResult, error) {
+	var versions []string
+	for _, v := range m.versions {
+		if opts.IncludePrerelease || !strings.Contains(v, "-") {
+			versions = append(versions, v)
+		}
+	}
+	return &packagesDomain.VersionsResult{Name: name, Versions: versions}, nil
+}
+
+func (m *mockPackages) GetContracts(ctx context.Context, name, version string) ([]packagesDomain.Contract, error) {
+	return m.contracts[name+"@"+version], nil
+}
+
+// mockDeployments implements DeploymentService for testing
+type mockDeployments struct {
+	deployments map[string][]deploymentsDomain.DeploymentSummary // keyed name@version
+}
+
+func (m *mockDeployments) ListByPackage(ctx context.Context, packageName, version string) ([]deploymentsDomain.DeploymentSummary, error) {
+	return m.deployments[packageName+"@"+version], nil
+}
+
+func newTestHandler() (*Handler, *mockPackages) {
+	pkgs := &mockPackages{
+		packages: map[string]*packagesDomain.Package{
+			"token@1.1.0": {Name: "token", Version: "1.1.0", Chain: "evm", Builder: "foundry", CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+			"token@1.0.0": {Name: "token", Version: "1.0.0", Chain: "evm", Builder: "foundry", Metadata: map[string]string{"commit": "abc", "audit": "passed"}},
+		},
+		contracts: map[string][]packagesDomain.Contract{
+			"token@1.1.0": {{Name: "Token", SourcePath: "src/Token.sol", Labels: []string{"erc20"}}},
+		},
+		versions: []string{"2.0.0-rc.1", "1.1.0", "1.0.0"},
+	}
+	deps := &mockDeployments{
+		deployments: map[string][]deploymentsDomain.DeploymentSummary{
+			"token@1.1.0": {{ChainID: "1", Address: "0xabc", ContractName: "Token", Verified: true, BlockNumber: 19000000}},
+		},
+	}
+	return NewHandler(pkgs, deps), pkgs
+}
+
+// query POSTs a GraphQL query and decodes the response.
+func query(t *testing.T, h http.Handler, q string, variables map[string]any) map[string]any {
+	t.Helper()
+	body, err := json.Marshal(request{Query: q, Variables: variables})
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestPackageQuery(t *testing.T) {
+	h, _ := newTestHandler()
+
+	t.Run("package with contracts, versions and deployments", func(t *testing.T) {
+		resp := query(t, h, `query($name: String!) {
+			package(name: $name, version: "1.1.0") {
+				name version chain createdAt
+				versions
+				contracts { name sourcePath labels }
+				deployments { chainId address contractName verified blockNumber }
+			}
+		}`, map[string]any{"name": "token"})
+		assert.Nil(t, resp["errors"])
+		got, err := json.Marshal(resp["data"])
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"package":{
+			"name":"token","version":"1.1.0","chain":"evm","createdAt":"2026-01-02T03:04:05Z",
+			"versions":["1.1.0","1.0.0"],
+			"contracts":[{"name":"Token","sourcePath":"src/Token.sol","labels":["erc20"]}],
+			"deployments":[{"chainId":"1","address":"0xabc","contractName":"Token","verified":true,"blockNumber":19000000}]
+		}}`, string(got))
+	})
+
+	t.Run("version defaults to latest", func(t *testing.T) {
+		resp := query(t, h, `{ package(name: "token") { version } }`, nil)
+		assert.Equal(t, map[string]any{"package": map[string]any{"version": "1.1.0"}}, resp["data"])
+	})
+
+	t.Run("prereleases on request", func(t *testing.T) {
+		resp := query(t, h, `{ package(name: "token", version: "1.0.0") { versions(includePrerelease: true) } }`, nil)
+		assert.Equal(t, []any{"2.0.0-rc.1", "1.1.0", "1.0.0"}, resp["data"].(map[string]any)["package"].(map[string]any)["versions"])
+	})
+
+	t.Run("metadata sorted by key, empty lists not null", func(t *testing.T) {
+		resp := query(t, h, `{ package(name: "token", version: "1.0.0") { metadata { key value } contracts { name } deployments { address } } }`, nil)
+		got, err := json.Marshal(resp["data"])
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"package":{"metadata":[{"key":"audit","value":"passed"},{"key":"commit","value":"abc"}],"contracts":[],"deployments":[]}}`, string(got))
+	})
+
+	t.Run("unknown package is null", func(t *testing.T) {
+		resp := query(t, h, `{ package(name: "missing", version: "1.0.0") { name } }`, nil)
+		assert.Nil(t, resp["errors"])
+		assert.Equal(t, map[string]any{"package": nil}, resp["data"])
+	})
+
+	t.Run("mutations are rejected", func(t *testing.T) {
+		resp := query(t, h, `mutation { deletePackage(name: "token") }`, nil)
+		assert.NotEmpty(t, resp["errors"])
+	})
+}
+
+func TestPackageQueryStorageError(t *testing.T) {
+	h, pkgs := newTestHandler()
+	pkgs.err = errors.New("connection refused to 10.0.0.5")
+
+	resp := query(t, h, `{ package(name: "token", version: "1.0.0") { name } }`, nil)
+	errs := resp["errors"].([]any)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "internal error", errs[0].(map[string]any)["message"], "storage details are not leaked")
+}
+
+func TestServeHTTP(t *testing.T) {
+	h, _ := newTestHandler()
+
+	t.Run("GET with variables", func(t *testing.T) {
+		q := url.Values{
+			"query":     {`query($v: String) { package(name: "token", version: $v) { version } }`},
+			"variables": {`{"v":"1.0.0"}`},
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/graphql?"+q.Encode(), nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data":{"package":{"version":"1.0.0"}}}`, rec.Body.String())
+	})
+
+	t.Run("malformed requests", func(t *testing.T) {
+		for _, req := range []*http.Request{
+			httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{`)),
+			httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{}`)),
+			httptest.NewRequest(http.MethodGet, "/graphql?query=x&variables=nope", nil),
+		} {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), "INVALID_REQUEST")
+		}
+	})
+}
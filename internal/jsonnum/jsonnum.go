@@ -0,0 +1,58 @@
+// Package jsonnum controls how large integers are serialized in API responses.
+//
+// JavaScript parses JSON numbers as float64, which silently rounds integers above
+// 2^53. Clients that need exact values can request ?bigints=string to receive
+// those fields as decimal strings instead.
+package jsonnum
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// QueryParam is the query parameter selecting string serialization ("bigints=string").
+const QueryParam = "bigints"
+
+// Int64 is an integer response field that marshals as a JSON number by default,
+// or as a decimal string when AsString is set.
+type Int64 struct {
+	Value    int64
+	AsString bool
+}
+
+// New returns an Int64 for v.
+func New(v int64, asString bool) Int64 {
+	return Int64{Value: v, AsString: asString}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (i Int64) MarshalJSON() ([]byte, error) {
+	s := strconv.FormatInt(i.Value, 10)
+	if i.AsString {
+		return []byte(strconv.Quote(s)), nil
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either form.
+func (i *Int64) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+		i.AsString = true
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	i.Value = v
+	return nil
+}
+
+// StringMode reports whether the request asked for large integers as strings.
+func StringMode(r *http.Request) bool {
+	return r.URL.Query().Get(QueryParam) == "string"
+}
@@ -0,0 +1,43 @@
+package jsonnum
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInt64_Marshal(t *testing.T) {
+	big := int64(1<<53 + 1)
+
+	data, err := json.Marshal(map[string]Int64{"n": New(big, false)})
+	require.NoError(t, err)
+	assert.Equal(t, `{"n":9007199254740993}`, string(data))
+
+	data, err = json.Marshal(map[string]Int64{"n": New(big, true)})
+	require.NoError(t, err)
+	assert.Equal(t, `{"n":"9007199254740993"}`, string(data))
+}
+
+func TestInt64_Unmarshal(t *testing.T) {
+	var null Int64
+	require.NoError(t, json.Unmarshal([]byte(`null`), &null))
+	assert.Equal(t, int64(0), null.Value)
+
+	for _, input := range []string{`12345`, `"12345"`} {
+		var v Int64
+		require.NoError(t, json.Unmarshal([]byte(input), &v))
+		assert.Equal(t, int64(12345), v.Value)
+	}
+
+	var v Int64
+	assert.Error(t, json.Unmarshal([]byte(`"abc"`), &v))
+}
+
+func TestStringMode(t *testing.T) {
+	assert.True(t, StringMode(httptest.NewRequest("GET", "/x?bigints=string", nil)))
+	assert.False(t, StringMode(httptest.NewRequest("GET", "/x?bigints=number", nil)))
+	assert.False(t, StringMode(httptest.NewRequest("GET", "/x", nil)))
+}
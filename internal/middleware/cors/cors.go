@@ -0,0 +1,95 @@
+// Package cors provides middleware answering cross-origin (CORS) requests from
+// browser-based clients.
+package cors
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Config holds the configuration for the CORS middleware
+type Config struct {
+	// AllowedOrigins lists origins (e.g. https://ui.example.com) allowed to call the
+	// API, or "*" for any. Empty disables CORS.
+	AllowedOrigins []string
+	// AllowedMethods are the methods cross-origin requests may use
+	AllowedMethods []string
+	// AllowedHeaders are the request headers cross-origin requests may send
+	AllowedHeaders []string
+	// AllowCredentials lets browsers send cookies and auth headers
+	AllowCredentials bool
+	// MaxAgeSeconds is how long browsers may cache a preflight response
+	MaxAgeSeconds int
+}
+
+// exposedHeaders are response headers browser scripts may read.
+var exposedHeaders = "ETag, X-Request-Id, X-ABI-Collisions"
+
+// Middleware returns an HTTP middleware that adds CORS headers to requests from
+// allowed origins using allowed methods, and answers their preflight requests.
+// Other requests pass through untouched, so with no allowed origins it does nothing.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	anyOrigin := slices.Contains(cfg.AllowedOrigins, "*")
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+
+	allowed := func(origin, method string) bool {
+		if origin == "" || !slices.ContainsFunc(cfg.AllowedMethods, func(m string) bool { return strings.EqualFold(m, method) }) {
+			return false
+		}
+		return anyOrigin || slices.Contains(cfg.AllowedOrigins, origin)
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(cfg.AllowedOrigins) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Origin")
+
+			// Preflight: answer here, the routes only handle the real request
+			if requested := r.Header.Get("Access-Control-Request-Method"); r.Method == http.MethodOptions && requested != "" {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+				if allowed(origin, requested) {
+					setAllowOrigin(w, origin, anyOrigin, cfg.AllowCredentials)
+					w.Header().Set("Access-Control-Allow-Methods", methods)
+					if headers != "" {
+						w.Header().Set("Access-Control-Allow-Headers", headers)
+					}
+					if cfg.MaxAgeSeconds > 0 {
+						w.Header().Set("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAgeSeconds))
+					}
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if allowed(origin, r.Method) {
+				setAllowOrigin(w, origin, anyOrigin, cfg.AllowCredentials)
+				w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// setAllowOrigin allows origin. Browsers reject "*" on credentialed requests, so the
+// origin is echoed back instead when credentials are allowed.
+func setAllowOrigin(w http.ResponseWriter, origin string, anyOrigin, credentials bool) {
+	if anyOrigin && !credentials {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+	if credentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
@@ -0,0 +1,107 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	var reached bool
+	handler := Middleware(Config{
+		AllowedOrigins: []string{"https://ui.example.com"},
+		AllowedMethods: []string{"GET", "HEAD"},
+		AllowedHeaders: []string{"Content-Type", "If-None-Match"},
+		MaxAgeSeconds:  600,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+
+	serve := func(method, origin string, header map[string]string) *httptest.ResponseRecorder {
+		reached = false
+		req := httptest.NewRequest(method, "/api/v1/packages/", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("allowed origin", func(t *testing.T) {
+		rec := serve("GET", "https://ui.example.com", nil)
+		assert.True(t, reached)
+		assert.Equal(t, "https://ui.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, rec.Header().Get("Access-Control-Expose-Headers"), "ETag")
+		assert.Equal(t, "Origin", rec.Header().Get("Vary"))
+	})
+
+	t.Run("other origin", func(t *testing.T) {
+		rec := serve("GET", "https://evil.example.com", nil)
+		assert.True(t, reached)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("same-origin request", func(t *testing.T) {
+		rec := serve("GET", "", nil)
+		assert.True(t, reached)
+		assert.Empty(t, rec.Header().Get("Vary"))
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rec := serve("POST", "https://ui.example.com", nil)
+		assert.True(t, reached)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		rec := serve("OPTIONS", "https://ui.example.com", map[string]string{"Access-Control-Request-Method": "GET"})
+		assert.False(t, reached)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://ui.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, HEAD", rec.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "Content-Type, If-None-Match", rec.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("preflight for a disallowed method", func(t *testing.T) {
+		rec := serve("OPTIONS", "https://ui.example.com", map[string]string{"Access-Control-Request-Method": "DELETE"})
+		assert.False(t, reached)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestMiddleware_AnyOrigin(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Origin", "https://anywhere.example")
+
+	rec := httptest.NewRecorder()
+	Middleware(Config{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}})(next).ServeHTTP(rec, req)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+
+	// Credentialed responses must name the origin
+	rec = httptest.NewRecorder()
+	Middleware(Config{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, AllowCredentials: true})(next).ServeHTTP(rec, req)
+	assert.Equal(t, "https://anywhere.example", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestMiddleware_Disabled(t *testing.T) {
+	req := httptest.NewRequest("OPTIONS", "/", nil)
+	req.Header.Set("Origin", "https://ui.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+
+	rec := httptest.NewRecorder()
+	Middleware(Config{AllowedMethods: []string{"GET"}})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	})).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -10,6 +10,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/pendergraft/contrafactory/internal/middleware/realip"
+	"github.com/pendergraft/contrafactory/internal/server/errcodes"
 )
 
 // Config holds the configuration for rate limiting
@@ -145,7 +146,7 @@ func (rl *RateLimiter) Middleware() func(http.Handler) http.Handler {
 				w.WriteHeader(http.StatusTooManyRequests)
 				json.NewEncoder(w).Encode(map[string]any{
 					"error": map[string]any{
-						"code":    "RATE_LIMIT_EXCEEDED",
+						"code":    errcodes.RateLimitExceeded,
 						"message": "Too many requests. Please try again later.",
 					},
 				})
@@ -0,0 +1,75 @@
+// Package requestid provides middleware that assigns every request a correlation
+// ID, echoes it in the X-Request-Id response header and attaches it to a
+// request-scoped logger.
+package requestid
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// Header is the request and response header carrying the request ID.
+const Header = "X-Request-Id"
+
+// maxLength bounds incoming IDs so clients can't inflate log lines.
+const maxLength = 128
+
+type contextKey string
+
+const loggerKey contextKey = "request_logger"
+
+// Middleware returns an HTTP middleware that reuses a well-formed incoming
+// X-Request-Id or generates a new one. The ID is stored where chi's
+// middleware.GetReqID finds it, set on the response before the handler runs
+// (so error helpers can read it back from the response headers), and attached
+// to a logger available through Logger.
+func Middleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(Header)
+			if !valid(id) {
+				id = uuid.NewString()
+			}
+
+			w.Header().Set(Header, id)
+			ctx := context.WithValue(r.Context(), middleware.RequestIDKey, id)
+			ctx = context.WithValue(ctx, loggerKey, logger.With("request_id", id))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// FromContext returns the request ID, or "" outside a request.
+func FromContext(ctx context.Context) string {
+	return middleware.GetReqID(ctx)
+}
+
+// Logger returns the request-scoped logger carrying request_id, or fallback
+// when ctx did not pass through Middleware.
+func Logger(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
+		return l
+	}
+	return fallback
+}
+
+// valid reports whether an incoming ID is safe to reuse: non-empty, bounded and
+// limited to characters that need no escaping in headers or logs.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':', c == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
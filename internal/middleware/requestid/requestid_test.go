@@ -0,0 +1,59 @@
+package requestid
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	var gotID string
+	handler := Middleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = FromContext(r.Context())
+		Logger(r.Context(), nil).Info("handled")
+	}))
+
+	t.Run("generates an ID", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+		assert.NotEmpty(t, gotID)
+		assert.Equal(t, gotID, rec.Header().Get(Header))
+		assert.Contains(t, logs.String(), "request_id="+gotID)
+	})
+
+	t.Run("honors an incoming ID", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(Header, "ci-run-42/publish")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, "ci-run-42/publish", gotID)
+		assert.Equal(t, "ci-run-42/publish", rec.Header().Get(Header))
+	})
+
+	t.Run("replaces malformed incoming IDs", func(t *testing.T) {
+		for _, bad := range []string{"has space", "new\nline", strings.Repeat("a", maxLength+1)} {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set(Header, bad)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.NotEqual(t, bad, gotID)
+			assert.Equal(t, gotID, rec.Header().Get(Header))
+		}
+	})
+}
+
+func TestLogger_Fallback(t *testing.T) {
+	fallback := slog.Default()
+	assert.Same(t, fallback, Logger(httptest.NewRequest("GET", "/", nil).Context(), fallback))
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+
+	"github.com/pendergraft/contrafactory/internal/server/errcodes"
 )
 
 // Config holds the configuration for security middleware
@@ -117,7 +119,7 @@ func writeBlockedResponse(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusBadRequest)
 	json.NewEncoder(w).Encode(map[string]any{
 		"error": map[string]any{
-			"code":    "BAD_REQUEST",
+			"code":    errcodes.BadRequest,
 			"message": "Invalid request",
 		},
 	})
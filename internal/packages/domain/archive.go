@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ArchiveFormat is the container format of a package archive.
+type ArchiveFormat string
+
+// Supported archive formats.
+const (
+	ArchiveTarGz ArchiveFormat = "tar.gz" // default
+	ArchiveZip   ArchiveFormat = "zip"
+)
+
+// ParseArchiveFormat parses an archive format name; "" means tar.gz.
+func ParseArchiveFormat(s string) (ArchiveFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "tar.gz", "tgz":
+		return ArchiveTarGz, nil
+	case "zip":
+		return ArchiveZip, nil
+	default:
+		return "", fmt.Errorf("%w: %q (expected tar.gz or zip)", ErrInvalidArchiveFormat, s)
+	}
+}
+
+// Extension returns the file extension of the format, without the dot.
+func (f ArchiveFormat) Extension() string {
+	if f == ArchiveZip {
+		return "zip"
+	}
+	return "tar.gz"
+}
+
+// ContentType returns the MIME type of the format.
+func (f ArchiveFormat) ContentType() string {
+	if f == ArchiveZip {
+		return "application/zip"
+	}
+	return "application/gzip"
+}
+
+// archiveWriter writes the entries of a package archive, whatever its format.
+type archiveWriter interface {
+	add(path string, content []byte) error
+	close() error
+}
+
+// newArchiveWriter returns a writer for format writing to w.
+func newArchiveWriter(w io.Writer, format ArchiveFormat) archiveWriter {
+	if format == ArchiveZip {
+		return &zipArchive{zw: zip.NewWriter(w)}
+	}
+	gw := gzip.NewWriter(w)
+	return &tarGzArchive{gw: gw, tw: tar.NewWriter(gw)}
+}
+
+type tarGzArchive struct {
+	gw *gzip.Writer
+	tw *tar.Writer
+}
+
+func (a *tarGzArchive) add(path string, content []byte) error {
+	return addToTar(a.tw, path, content)
+}
+
+func (a *tarGzArchive) close() error {
+	if err := a.tw.Close(); err != nil {
+		return fmt.Errorf("closing tar: %w", err)
+	}
+	if err := a.gw.Close(); err != nil {
+		return fmt.Errorf("closing gzip: %w", err)
+	}
+	return nil
+}
+
+// zipModTime is the fixed modification time of every zip entry: the earliest time
+// a zip (MS-DOS) timestamp can hold.
+var zipModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type zipArchive struct {
+	zw *zip.Writer
+}
+
+func (a *zipArchive) add(path string, content []byte) error {
+	w, err := a.zw.CreateHeader(&zip.FileHeader{
+		Name:     path,
+		Method:   zip.Deflate,
+		Modified: zipModTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+func (a *zipArchive) close() error {
+	if err := a.zw.Close(); err != nil {
+		return fmt.Errorf("closing zip: %w", err)
+	}
+	return nil
+}
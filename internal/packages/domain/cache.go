@@ -0,0 +1,300 @@
+package domain
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pendergraft/contrafactory/internal/validation"
+)
+
+// entryOverhead approximates the size of a cached struct value (package or contract metadata).
+const entryOverhead = 1024
+
+// CachingMiddleware returns a service middleware that keeps an in-memory LRU cache of
+// version-scoped reads (package, contracts, artifacts, archive). Published versions are
+// immutable, so entries only go stale on delete or when another instance (e.g. the
+// primary of a mirror) changes the data; Invalidate handles the latter.
+// "latest" and alias lookups, version lists, package lists and tag counts are never
+// cached.
+func CachingMiddleware(ttl time.Duration, maxBytes int64) func(loggingService) *cachingMiddleware {
+	return func(next loggingService) *cachingMiddleware {
+		return &cachingMiddleware{
+			next:     next,
+			ttl:      ttl,
+			maxBytes: maxBytes,
+			entries:  make(map[string]*list.Element),
+			lru:      list.New(),
+		}
+	}
+}
+
+type cacheEntry struct {
+	key       string
+	value     any
+	size      int64
+	expiresAt time.Time
+}
+
+type cachingMiddleware struct {
+	next     loggingService
+	ttl      time.Duration
+	maxBytes int64
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	size    int64
+}
+
+// cacheKey builds "<name>@<version>/<kind>/<parts...>". The name@version prefix is what
+// invalidation matches on.
+func cacheKey(name, version, kind string, parts ...string) string {
+	return versionPrefix(name, version) + kind + "/" + strings.Join(parts, "/")
+}
+
+func versionPrefix(name, version string) string {
+	return name + "@" + validation.NormalizeVersion(version) + "/"
+}
+
+// cacheable reports whether reads of version may be cached: only exact versions
+// are immutable, while "latest" and aliases move.
+func cacheable(version string) bool {
+	return validation.ValidateVersion(version) == nil
+}
+
+func (m *cachingMiddleware) get(key string) (any, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if m.ttl > 0 && time.Now().After(entry.expiresAt) {
+		m.removeElement(el)
+		return nil, false
+	}
+	m.lru.MoveToFront(el)
+	return entry.value, true
+}
+
+func (m *cachingMiddleware) put(key string, value any, size int64) {
+	if m.maxBytes > 0 && size > m.maxBytes {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.entries[key]; ok {
+		m.removeElement(el)
+	}
+	entry := &cacheEntry{key: key, value: value, size: size, expiresAt: time.Now().Add(m.ttl)}
+	m.entries[key] = m.lru.PushFront(entry)
+	m.size += size
+
+	for m.maxBytes > 0 && m.size > m.maxBytes {
+		m.removeElement(m.lru.Back())
+	}
+}
+
+func (m *cachingMiddleware) removeElement(el *list.Element) {
+	entry := el.Value.(*cacheEntry)
+	m.lru.Remove(el)
+	delete(m.entries, entry.key)
+	m.size -= entry.size
+}
+
+// Invalidate evicts cached entries for a package version, or for every version of the
+// package when version is empty. It returns the number of evicted entries.
+func (m *cachingMiddleware) Invalidate(name, version string) int {
+	prefix := name + "@"
+	if version != "" {
+		prefix = versionPrefix(name, version)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	evicted := 0
+	for key, el := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			m.removeElement(el)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// InvalidateAll evicts every cached entry and returns how many there were.
+func (m *cachingMiddleware) InvalidateAll() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	evicted := len(m.entries)
+	m.entries = make(map[string]*list.Element)
+	m.lru.Init()
+	m.size = 0
+	return evicted
+}
+
+func (m *cachingMiddleware) Publish(ctx context.Context, name, version string, ownerID string, req PublishRequest) error {
+	err := m.next.Publish(ctx, name, version, ownerID, req)
+	if err == nil {
+		m.Invalidate(name, version)
+	}
+	return err
+}
+
+func (m *cachingMiddleware) PublishBatch(ctx context.Context, ownerID string, items []BatchItem, atomic bool) ([]BatchItemResult, error) {
+	results, err := m.next.PublishBatch(ctx, ownerID, items, atomic)
+	for _, r := range results {
+		if r.Status == BatchPublished {
+			m.Invalidate(r.Name, r.Version)
+		}
+	}
+	return results, err
+}
+
+func (m *cachingMiddleware) Get(ctx context.Context, name, version string) (*Package, error) {
+	if !cacheable(version) {
+		return m.next.Get(ctx, name, version)
+	}
+	key := cacheKey(name, version, "package")
+	if v, ok := m.get(key); ok {
+		return v.(*Package), nil
+	}
+	pkg, err := m.next.Get(ctx, name, version)
+	if err == nil {
+		m.put(key, pkg, entryOverhead)
+	}
+	return pkg, err
+}
+
+func (m *cachingMiddleware) GetVersions(ctx context.Context, name string, opts VersionsOptions) (*VersionsResult, error) {
+	return m.next.GetVersions(ctx, name, opts)
+}
+
+func (m *cachingMiddleware) List(ctx context.Context, filter ListFilter, pagination PaginationParams) (*ListResult, error) {
+	return m.next.List(ctx, filter, pagination)
+}
+
+func (m *cachingMiddleware) ListOwned(ctx context.Context, ownerID string, pagination PaginationParams) (*ListResult, error) {
+	return m.next.ListOwned(ctx, ownerID, pagination)
+}
+
+func (m *cachingMiddleware) Delete(ctx context.Context, name, version string, ownerID string) error {
+	err := m.next.Delete(ctx, name, version, ownerID)
+	if err == nil {
+		m.Invalidate(name, version)
+	}
+	return err
+}
+
+func (m *cachingMiddleware) GetOwner(ctx context.Context, name, callerID string, callerIsAdmin bool) (*Owner, error) {
+	return m.next.GetOwner(ctx, name, callerID, callerIsAdmin)
+}
+
+func (m *cachingMiddleware) TransferOwner(ctx context.Context, name, callerID, toKeyID string) (*Owner, error) {
+	return m.next.TransferOwner(ctx, name, callerID, toKeyID)
+}
+
+func (m *cachingMiddleware) ListCollaborators(ctx context.Context, name, callerID string, callerIsAdmin bool) ([]Collaborator, error) {
+	return m.next.ListCollaborators(ctx, name, callerID, callerIsAdmin)
+}
+
+func (m *cachingMiddleware) AddCollaborator(ctx context.Context, name, callerID, keyID string) ([]Collaborator, error) {
+	return m.next.AddCollaborator(ctx, name, callerID, keyID)
+}
+
+func (m *cachingMiddleware) RemoveCollaborator(ctx context.Context, name, callerID, keyID string) ([]Collaborator, error) {
+	return m.next.RemoveCollaborator(ctx, name, callerID, keyID)
+}
+
+func (m *cachingMiddleware) ListTags(ctx context.Context) ([]TagCount, error) {
+	return m.next.ListTags(ctx)
+}
+
+func (m *cachingMiddleware) ListAliases(ctx context.Context, name string) ([]Alias, error) {
+	return m.next.ListAliases(ctx, name)
+}
+
+func (m *cachingMiddleware) SetAlias(ctx context.Context, name, alias, version, callerID string) (*Alias, error) {
+	return m.next.SetAlias(ctx, name, alias, version, callerID)
+}
+
+func (m *cachingMiddleware) DeleteAlias(ctx context.Context, name, alias, callerID string) error {
+	return m.next.DeleteAlias(ctx, name, alias, callerID)
+}
+
+func (m *cachingMiddleware) GetContracts(ctx context.Context, name, version string) ([]Contract, error) {
+	if !cacheable(version) {
+		return m.next.GetContracts(ctx, name, version)
+	}
+	key := cacheKey(name, version, "contracts")
+	if v, ok := m.get(key); ok {
+		return v.([]Contract), nil
+	}
+	contracts, err := m.next.GetContracts(ctx, name, version)
+	if err == nil {
+		m.put(key, contracts, int64(len(contracts)+1)*entryOverhead)
+	}
+	return contracts, err
+}
+
+func (m *cachingMiddleware) GetContract(ctx context.Context, name, version, contractName string) (*Contract, error) {
+	if !cacheable(version) {
+		return m.next.GetContract(ctx, name, version, contractName)
+	}
+	key := cacheKey(name, version, "contract", contractName)
+	if v, ok := m.get(key); ok {
+		return v.(*Contract), nil
+	}
+	contract, err := m.next.GetContract(ctx, name, version, contractName)
+	if err == nil {
+		m.put(key, contract, entryOverhead)
+	}
+	return contract, err
+}
+
+func (m *cachingMiddleware) GetArtifact(ctx context.Context, name, version, contractName, artifactType string) ([]byte, error) {
+	if !cacheable(version) {
+		return m.next.GetArtifact(ctx, name, version, contractName, artifactType)
+	}
+	key := cacheKey(name, version, "artifact", contractName, artifactType)
+	if v, ok := m.get(key); ok {
+		return v.([]byte), nil
+	}
+	content, err := m.next.GetArtifact(ctx, name, version, contractName, artifactType)
+	if err == nil {
+		m.put(key, content, int64(len(content)))
+	}
+	return content, err
+}
+
+func (m *cachingMiddleware) GetArchive(ctx context.Context, name, version string, format ArchiveFormat) ([]byte, error) {
+	if !cacheable(version) {
+		return m.next.GetArchive(ctx, name, version, format)
+	}
+	key := cacheKey(name, version, "archive", string(format))
+	if v, ok := m.get(key); ok {
+		return v.([]byte), nil
+	}
+	content, err := m.next.GetArchive(ctx, name, version, format)
+	if err == nil {
+		m.put(key, content, int64(len(content)))
+	}
+	return content, err
+}
+
+func (m *cachingMiddleware) LookupBytecode(ctx context.Context, hash string) ([]BytecodeMatch, error) {
+	return m.next.LookupBytecode(ctx, hash)
+}
+
+func (m *cachingMiddleware) LookupSelector(ctx context.Context, selector string) ([]SelectorMatch, error) {
+	return m.next.LookupSelector(ctx, selector)
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingService counts reads that reach the underlying service.
+type countingService struct {
+	loggingService
+	artifactCalls int
+	getCalls      int
+}
+
+func (c *countingService) GetArtifact(ctx context.Context, name, version, contractName, artifactType string) ([]byte, error) {
+	c.artifactCalls++
+	return c.loggingService.GetArtifact(ctx, name, version, contractName, artifactType)
+}
+
+func (c *countingService) Get(ctx context.Context, name, version string) (*Package, error) {
+	c.getCalls++
+	return c.loggingService.Get(ctx, name, version)
+}
+
+func newCachedService(t *testing.T, ttl time.Duration, maxBytes int64) (*cachingMiddleware, *countingService) {
+	t.Helper()
+	store := newMockStore()
+	next := &countingService{loggingService: NewService(store, store)}
+	cache := CachingMiddleware(ttl, maxBytes)(next)
+
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		req := PublishRequest{Chain: "evm", Artifacts: []Artifact{{Name: "Token", ABI: []byte(`[]`)}}}
+		require.NoError(t, cache.Publish(context.Background(), "token", version, "", req))
+	}
+	return cache, next
+}
+
+func TestCachingMiddleware_CachesReads(t *testing.T) {
+	cache, next := newCachedService(t, time.Hour, 0)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		abi, err := cache.GetArtifact(ctx, "token", "1.0.0", "Token", "abi")
+		require.NoError(t, err)
+		assert.Equal(t, "[]", string(abi))
+	}
+	assert.Equal(t, 1, next.artifactCalls)
+
+	// "v"-prefixed versions share entries
+	_, err := cache.GetArtifact(ctx, "token", "v1.0.0", "Token", "abi")
+	require.NoError(t, err)
+	assert.Equal(t, 1, next.artifactCalls)
+
+	// "latest" is never cached
+	for i := 0; i < 2; i++ {
+		_, err := cache.Get(ctx, "token", "latest")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, next.getCalls)
+
+	// Errors are not cached
+	for i := 0; i < 2; i++ {
+		_, err := cache.GetArtifact(ctx, "token", "1.0.0", "Missing", "abi")
+		require.Error(t, err)
+	}
+	assert.Equal(t, 3, next.artifactCalls)
+}
+
+func TestCachingMiddleware_AliasesNotCached(t *testing.T) {
+	store := newMockStore()
+	cache := CachingMiddleware(time.Hour, 0)(NewService(store, store))
+	ctx := context.Background()
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		req := PublishRequest{Chain: "evm", Artifacts: []Artifact{{Name: "Token", ABI: []byte(`[]`)}}}
+		require.NoError(t, cache.Publish(ctx, "token", version, "owner-1", req))
+	}
+
+	_, err := cache.SetAlias(ctx, "token", "stable", "1.0.0", "owner-1")
+	require.NoError(t, err)
+	pkg, err := cache.Get(ctx, "token", "stable")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", pkg.Version)
+
+	_, err = cache.SetAlias(ctx, "token", "stable", "2.0.0", "owner-1")
+	require.NoError(t, err)
+	pkg, err = cache.Get(ctx, "token", "stable")
+	require.NoError(t, err)
+	assert.Equal(t, "2.0.0", pkg.Version, "a moved alias is read through, not served from cache")
+}
+
+func TestCachingMiddleware_Invalidate(t *testing.T) {
+	cache, next := newCachedService(t, time.Hour, 0)
+	ctx := context.Background()
+
+	warm := func() {
+		for _, version := range []string{"1.0.0", "2.0.0"} {
+			_, err := cache.GetArtifact(ctx, "token", version, "Token", "abi")
+			require.NoError(t, err)
+		}
+	}
+
+	warm()
+	assert.Equal(t, 2, next.artifactCalls)
+
+	assert.Equal(t, 1, cache.Invalidate("token", "1.0.0"))
+	warm()
+	assert.Equal(t, 3, next.artifactCalls, "only 1.0.0 should be refetched")
+
+	assert.Equal(t, 2, cache.Invalidate("token", ""))
+	assert.Equal(t, 0, cache.Invalidate("other", ""))
+
+	warm()
+	assert.Equal(t, 2, cache.InvalidateAll())
+	warm()
+	assert.Equal(t, 7, next.artifactCalls)
+}
+
+func TestCachingMiddleware_DeleteInvalidates(t *testing.T) {
+	cache, _ := newCachedService(t, time.Hour, 0)
+	ctx := context.Background()
+
+	_, err := cache.Get(ctx, "token", "1.0.0")
+	require.NoError(t, err)
+	require.NoError(t, cache.Delete(ctx, "token", "1.0.0", ""))
+
+	_, err = cache.Get(ctx, "token", "1.0.0")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCachingMiddleware_Bounds(t *testing.T) {
+	t.Run("ttl expiry", func(t *testing.T) {
+		cache, next := newCachedService(t, time.Nanosecond, 0)
+		for i := 0; i < 2; i++ {
+			_, err := cache.GetArtifact(context.Background(), "token", "1.0.0", "Token", "abi")
+			require.NoError(t, err)
+			time.Sleep(time.Millisecond)
+		}
+		assert.Equal(t, 2, next.artifactCalls)
+	})
+
+	t.Run("size eviction", func(t *testing.T) {
+		cache, _ := newCachedService(t, time.Hour, entryOverhead)
+		ctx := context.Background()
+		_, err := cache.Get(ctx, "token", "1.0.0")
+		require.NoError(t, err)
+		_, err = cache.Get(ctx, "token", "2.0.0")
+		require.NoError(t, err)
+
+		assert.LessOrEqual(t, cache.size, int64(entryOverhead))
+		assert.Len(t, cache.entries, 1)
+	})
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/pendergraft/contrafactory/internal/validation"
+)
+
+// CompilerPolicy restricts the compilers published artifacts may be built with.
+// Versions are version ranges matched against the compiler version (build metadata
+// such as "+commit.7893614a" is ignored); evmVersions are compared by name. Empty
+// lists impose no restriction, and deny lists win over allow lists.
+type CompilerPolicy struct {
+	AllowedVersions    []string
+	DeniedVersions     []string
+	AllowedEVMVersions []string
+	DeniedEVMVersions  []string
+}
+
+// check returns an ErrCompilerNotAllowed error naming the artifact when its compiler
+// is rejected by the policy. Artifacts without compiler info and unset evmVersions
+// (the compiler default) pass.
+func (p CompilerPolicy) check(artifact Artifact) error {
+	c := artifact.Compiler
+	if c == nil {
+		return nil
+	}
+
+	if c.Version != "" {
+		denied, err := matchesAnyRange(p.DeniedVersions, c.Version)
+		if err != nil {
+			return err
+		}
+		if denied {
+			return fmt.Errorf("%w: %s: compiler version %s is denied", ErrCompilerNotAllowed, artifact.Name, c.Version)
+		}
+		if len(p.AllowedVersions) > 0 {
+			allowed, err := matchesAnyRange(p.AllowedVersions, c.Version)
+			if err != nil {
+				return err
+			}
+			if !allowed {
+				return fmt.Errorf("%w: %s: compiler version %s is not in the allowed list", ErrCompilerNotAllowed, artifact.Name, c.Version)
+			}
+		}
+	}
+
+	if c.EVMVersion != "" {
+		if containsFold(p.DeniedEVMVersions, c.EVMVersion) {
+			return fmt.Errorf("%w: %s: evmVersion %s is denied", ErrCompilerNotAllowed, artifact.Name, c.EVMVersion)
+		}
+		if len(p.AllowedEVMVersions) > 0 && !containsFold(p.AllowedEVMVersions, c.EVMVersion) {
+			return fmt.Errorf("%w: %s: evmVersion %s is not in the allowed list", ErrCompilerNotAllowed, artifact.Name, c.EVMVersion)
+		}
+	}
+	return nil
+}
+
+func matchesAnyRange(ranges []string, version string) (bool, error) {
+	for _, expr := range ranges {
+		r, err := validation.ParseVersionRange(expr)
+		if err != nil {
+			return false, fmt.Errorf("compiler policy: %w", err)
+		}
+		if r.Match(version) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+// CompilerMismatch returns an advisory when a consumer building with solc version
+// requested would use a different major or minor release than the package was
+// published with; patch releases are treated as compatible. It returns "" when
+// the versions agree or the published version is unknown, and an
+// ErrInvalidCompilerVersion error when requested isn't a version.
+func CompilerMismatch(published, requested string) (string, error) {
+	want := solcSemver(requested)
+	if !semver.IsValid(want) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCompilerVersion, requested)
+	}
+	have := solcSemver(published)
+	if !semver.IsValid(have) || semver.MajorMinor(have) == semver.MajorMinor(want) {
+		return "", nil
+	}
+	return fmt.Sprintf("package was compiled with solc %s but you are using %s; "+
+		"output may differ across minor compiler releases", strings.TrimPrefix(have, "v"), strings.TrimPrefix(want, "v")), nil
+}
+
+// solcSemver converts a solc version ("0.8.28", "v0.8.28+commit.7893614a") to the
+// "v"-prefixed form the semver package expects, dropping build metadata.
+func solcSemver(v string) string {
+	v, _, _ = strings.Cut(strings.TrimSpace(v), "+")
+	return "v" + validation.NormalizeVersion(v)
+}
@@ -4,19 +4,34 @@ import (
 	"context"
 	"log/slog"
 	"time"
+
+	"github.com/pendergraft/contrafactory/internal/middleware/requestid"
 )
 
 // loggingService is the interface required for logging middleware.
 type loggingService interface {
 	Publish(ctx context.Context, name, version string, ownerID string, req PublishRequest) error
+	PublishBatch(ctx context.Context, ownerID string, items []BatchItem, atomic bool) ([]BatchItemResult, error)
 	Get(ctx context.Context, name, version string) (*Package, error)
-	GetVersions(ctx context.Context, name string, includePrerelease bool) (*VersionsResult, error)
+	GetVersions(ctx context.Context, name string, opts VersionsOptions) (*VersionsResult, error)
 	List(ctx context.Context, filter ListFilter, pagination PaginationParams) (*ListResult, error)
+	ListOwned(ctx context.Context, ownerID string, pagination PaginationParams) (*ListResult, error)
 	Delete(ctx context.Context, name, version string, ownerID string) error
+	GetOwner(ctx context.Context, name, callerID string, callerIsAdmin bool) (*Owner, error)
+	TransferOwner(ctx context.Context, name, callerID, toKeyID string) (*Owner, error)
+	ListCollaborators(ctx context.Context, name, callerID string, callerIsAdmin bool) ([]Collaborator, error)
+	AddCollaborator(ctx context.Context, name, callerID, keyID string) ([]Collaborator, error)
+	RemoveCollaborator(ctx context.Context, name, callerID, keyID string) ([]Collaborator, error)
+	ListAliases(ctx context.Context, name string) ([]Alias, error)
+	ListTags(ctx context.Context) ([]TagCount, error)
+	SetAlias(ctx context.Context, name, alias, version, callerID string) (*Alias, error)
+	DeleteAlias(ctx context.Context, name, alias, callerID string) error
 	GetContracts(ctx context.Context, name, version string) ([]Contract, error)
 	GetContract(ctx context.Context, name, version, contractName string) (*Contract, error)
 	GetArtifact(ctx context.Context, name, version, contractName, artifactType string) ([]byte, error)
-	GetArchive(ctx context.Context, name, version string) ([]byte, error)
+	GetArchive(ctx context.Context, name, version string, format ArchiveFormat) ([]byte, error)
+	LookupBytecode(ctx context.Context, hash string) ([]BytecodeMatch, error)
+	LookupSelector(ctx context.Context, selector string) ([]SelectorMatch, error)
 }
 
 // LoggingMiddleware returns a service middleware that logs all operations.
@@ -34,10 +49,15 @@ type loggingMiddleware struct {
 	logger *slog.Logger
 }
 
+// log returns the request-scoped logger (carrying request_id) when there is one.
+func (m *loggingMiddleware) log(ctx context.Context) *slog.Logger {
+	return requestid.Logger(ctx, m.logger)
+}
+
 func (m *loggingMiddleware) Publish(ctx context.Context, name, version string, ownerID string, req PublishRequest) error {
 	start := time.Now()
 	err := m.next.Publish(ctx, name, version, ownerID, req)
-	m.logger.Info("Publish",
+	m.log(ctx).Info("Publish",
 		"name", name,
 		"version", version,
 		"chain", req.Chain,
@@ -48,10 +68,29 @@ func (m *loggingMiddleware) Publish(ctx context.Context, name, version string, o
 	return err
 }
 
+func (m *loggingMiddleware) PublishBatch(ctx context.Context, ownerID string, items []BatchItem, atomic bool) ([]BatchItemResult, error) {
+	start := time.Now()
+	results, err := m.next.PublishBatch(ctx, ownerID, items, atomic)
+	published := 0
+	for _, r := range results {
+		if r.Status == BatchPublished {
+			published++
+		}
+	}
+	m.log(ctx).Info("PublishBatch",
+		"packages", len(items),
+		"atomic", atomic,
+		"published", published,
+		"duration", time.Since(start),
+		"error", err,
+	)
+	return results, err
+}
+
 func (m *loggingMiddleware) Get(ctx context.Context, name, version string) (*Package, error) {
 	start := time.Now()
 	pkg, err := m.next.Get(ctx, name, version)
-	m.logger.Debug("Get",
+	m.log(ctx).Debug("Get",
 		"name", name,
 		"version", version,
 		"duration", time.Since(start),
@@ -60,12 +99,15 @@ func (m *loggingMiddleware) Get(ctx context.Context, name, version string) (*Pac
 	return pkg, err
 }
 
-func (m *loggingMiddleware) GetVersions(ctx context.Context, name string, includePrerelease bool) (*VersionsResult, error) {
+func (m *loggingMiddleware) GetVersions(ctx context.Context, name string, opts VersionsOptions) (*VersionsResult, error) {
 	start := time.Now()
-	result, err := m.next.GetVersions(ctx, name, includePrerelease)
-	m.logger.Debug("GetVersions",
+	result, err := m.next.GetVersions(ctx, name, opts)
+	m.log(ctx).Debug("GetVersions",
 		"name", name,
-		"includePrerelease", includePrerelease,
+		"includePrerelease", opts.IncludePrerelease,
+		"withDeployments", opts.WithDeployments,
+		"detailed", opts.Detailed,
+		"limit", opts.Limit,
 		"duration", time.Since(start),
 		"error", err,
 	)
@@ -75,7 +117,7 @@ func (m *loggingMiddleware) GetVersions(ctx context.Context, name string, includ
 func (m *loggingMiddleware) List(ctx context.Context, filter ListFilter, pagination PaginationParams) (*ListResult, error) {
 	start := time.Now()
 	result, err := m.next.List(ctx, filter, pagination)
-	m.logger.Debug("List",
+	m.log(ctx).Debug("List",
 		"filter", filter,
 		"limit", pagination.Limit,
 		"duration", time.Since(start),
@@ -84,12 +126,134 @@ func (m *loggingMiddleware) List(ctx context.Context, filter ListFilter, paginat
 	return result, err
 }
 
+func (m *loggingMiddleware) ListOwned(ctx context.Context, ownerID string, pagination PaginationParams) (*ListResult, error) {
+	start := time.Now()
+	result, err := m.next.ListOwned(ctx, ownerID, pagination)
+	m.log(ctx).Debug("ListOwned",
+		"owner", ownerID,
+		"limit", pagination.Limit,
+		"duration", time.Since(start),
+		"error", err,
+	)
+	return result, err
+}
+
 func (m *loggingMiddleware) Delete(ctx context.Context, name, version string, ownerID string) error {
 	start := time.Now()
 	err := m.next.Delete(ctx, name, version, ownerID)
-	m.logger.Info("Delete",
+	m.log(ctx).Info("Delete",
+		"name", name,
+		"version", version,
+		"duration", time.Since(start),
+		"error", err,
+	)
+	return err
+}
+
+func (m *loggingMiddleware) GetOwner(ctx context.Context, name, callerID string, callerIsAdmin bool) (*Owner, error) {
+	start := time.Now()
+	owner, err := m.next.GetOwner(ctx, name, callerID, callerIsAdmin)
+	m.log(ctx).Debug("GetOwner",
+		"name", name,
+		"duration", time.Since(start),
+		"error", err,
+	)
+	return owner, err
+}
+
+func (m *loggingMiddleware) TransferOwner(ctx context.Context, name, callerID, toKeyID string) (*Owner, error) {
+	start := time.Now()
+	owner, err := m.next.TransferOwner(ctx, name, callerID, toKeyID)
+	m.log(ctx).Info("TransferOwner",
+		"name", name,
+		"from", callerID,
+		"to", toKeyID,
+		"duration", time.Since(start),
+		"error", err,
+	)
+	return owner, err
+}
+
+func (m *loggingMiddleware) ListCollaborators(ctx context.Context, name, callerID string, callerIsAdmin bool) ([]Collaborator, error) {
+	start := time.Now()
+	collaborators, err := m.next.ListCollaborators(ctx, name, callerID, callerIsAdmin)
+	m.log(ctx).Debug("ListCollaborators",
+		"name", name,
+		"duration", time.Since(start),
+		"error", err,
+	)
+	return collaborators, err
+}
+
+func (m *loggingMiddleware) AddCollaborator(ctx context.Context, name, callerID, keyID string) ([]Collaborator, error) {
+	start := time.Now()
+	collaborators, err := m.next.AddCollaborator(ctx, name, callerID, keyID)
+	m.log(ctx).Info("AddCollaborator",
+		"name", name,
+		"owner", callerID,
+		"key", keyID,
+		"duration", time.Since(start),
+		"error", err,
+	)
+	return collaborators, err
+}
+
+func (m *loggingMiddleware) RemoveCollaborator(ctx context.Context, name, callerID, keyID string) ([]Collaborator, error) {
+	start := time.Now()
+	collaborators, err := m.next.RemoveCollaborator(ctx, name, callerID, keyID)
+	m.log(ctx).Info("RemoveCollaborator",
+		"name", name,
+		"owner", callerID,
+		"key", keyID,
+		"duration", time.Since(start),
+		"error", err,
+	)
+	return collaborators, err
+}
+
+func (m *loggingMiddleware) ListTags(ctx context.Context) ([]TagCount, error) {
+	start := time.Now()
+	tags, err := m.next.ListTags(ctx)
+	m.log(ctx).Debug("ListTags",
+		"count", len(tags),
+		"duration", time.Since(start),
+		"error", err,
+	)
+	return tags, err
+}
+
+func (m *loggingMiddleware) ListAliases(ctx context.Context, name string) ([]Alias, error) {
+	start := time.Now()
+	aliases, err := m.next.ListAliases(ctx, name)
+	m.log(ctx).Debug("ListAliases",
+		"name", name,
+		"duration", time.Since(start),
+		"error", err,
+	)
+	return aliases, err
+}
+
+func (m *loggingMiddleware) SetAlias(ctx context.Context, name, alias, version, callerID string) (*Alias, error) {
+	start := time.Now()
+	result, err := m.next.SetAlias(ctx, name, alias, version, callerID)
+	m.log(ctx).Info("SetAlias",
 		"name", name,
+		"alias", alias,
 		"version", version,
+		"owner", callerID,
+		"duration", time.Since(start),
+		"error", err,
+	)
+	return result, err
+}
+
+func (m *loggingMiddleware) DeleteAlias(ctx context.Context, name, alias, callerID string) error {
+	start := time.Now()
+	err := m.next.DeleteAlias(ctx, name, alias, callerID)
+	m.log(ctx).Info("DeleteAlias",
+		"name", name,
+		"alias", alias,
+		"owner", callerID,
 		"duration", time.Since(start),
 		"error", err,
 	)
@@ -99,7 +263,7 @@ func (m *loggingMiddleware) Delete(ctx context.Context, name, version string, ow
 func (m *loggingMiddleware) GetContracts(ctx context.Context, name, version string) ([]Contract, error) {
 	start := time.Now()
 	contracts, err := m.next.GetContracts(ctx, name, version)
-	m.logger.Debug("GetContracts",
+	m.log(ctx).Debug("GetContracts",
 		"name", name,
 		"version", version,
 		"count", len(contracts),
@@ -112,7 +276,7 @@ func (m *loggingMiddleware) GetContracts(ctx context.Context, name, version stri
 func (m *loggingMiddleware) GetContract(ctx context.Context, name, version, contractName string) (*Contract, error) {
 	start := time.Now()
 	contract, err := m.next.GetContract(ctx, name, version, contractName)
-	m.logger.Debug("GetContract",
+	m.log(ctx).Debug("GetContract",
 		"name", name,
 		"version", version,
 		"contract", contractName,
@@ -125,7 +289,7 @@ func (m *loggingMiddleware) GetContract(ctx context.Context, name, version, cont
 func (m *loggingMiddleware) GetArtifact(ctx context.Context, name, version, contractName, artifactType string) ([]byte, error) {
 	start := time.Now()
 	content, err := m.next.GetArtifact(ctx, name, version, contractName, artifactType)
-	m.logger.Debug("GetArtifact",
+	m.log(ctx).Debug("GetArtifact",
 		"name", name,
 		"version", version,
 		"contract", contractName,
@@ -137,15 +301,40 @@ func (m *loggingMiddleware) GetArtifact(ctx context.Context, name, version, cont
 	return content, err
 }
 
-func (m *loggingMiddleware) GetArchive(ctx context.Context, name, version string) ([]byte, error) {
+func (m *loggingMiddleware) GetArchive(ctx context.Context, name, version string, format ArchiveFormat) ([]byte, error) {
 	start := time.Now()
-	content, err := m.next.GetArchive(ctx, name, version)
-	m.logger.Info("GetArchive",
+	content, err := m.next.GetArchive(ctx, name, version, format)
+	m.log(ctx).Info("GetArchive",
 		"name", name,
 		"version", version,
+		"format", format,
 		"size", len(content),
 		"duration", time.Since(start),
 		"error", err,
 	)
 	return content, err
 }
+
+func (m *loggingMiddleware) LookupBytecode(ctx context.Context, hash string) ([]BytecodeMatch, error) {
+	start := time.Now()
+	matches, err := m.next.LookupBytecode(ctx, hash)
+	m.log(ctx).Debug("LookupBytecode",
+		"hash", hash,
+		"matches", len(matches),
+		"duration", time.Since(start),
+		"error", err,
+	)
+	return matches, err
+}
+
+func (m *loggingMiddleware) LookupSelector(ctx context.Context, selector string) ([]SelectorMatch, error) {
+	start := time.Now()
+	matches, err := m.next.LookupSelector(ctx, selector)
+	m.log(ctx).Debug("LookupSelector",
+		"selector", selector,
+		"matches", len(matches),
+		"duration", time.Since(start),
+		"error", err,
+	)
+	return matches, err
+}
@@ -4,89 +4,231 @@ package domain
 import (
 	"archive/tar"
 	"bytes"
-	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"sort"
 	"time"
 
+	"github.com/pendergraft/contrafactory/internal/chains/evm"
+	"github.com/pendergraft/contrafactory/internal/provenance"
 	"github.com/pendergraft/contrafactory/internal/storage"
 	"github.com/pendergraft/contrafactory/internal/validation"
 )
 
 // Common errors returned by the package service.
 var (
-	ErrNotFound       = errors.New("package not found")
-	ErrVersionExists  = errors.New("version already exists")
-	ErrForbidden      = errors.New("not authorized to modify this package")
-	ErrInvalidVersion = errors.New("invalid semver version")
-	ErrInvalidName    = errors.New("invalid package name")
+	ErrNotFound               = errors.New("package not found")
+	ErrVersionExists          = errors.New("version already exists")
+	ErrForbidden              = errors.New("not authorized to modify this package")
+	ErrInvalidVersion         = errors.New("invalid semver version")
+	ErrInvalidName            = errors.New("invalid package name")
+	ErrTooManyArtifacts       = errors.New("too many artifacts in publish request")
+	ErrInvalidLabel           = errors.New("invalid contract label")
+	ErrInvalidTag             = errors.New("invalid package tag")
+	ErrCompilerNotAllowed     = errors.New("compiler not allowed")
+	ErrInvalidCompilerVersion = errors.New("invalid compiler version")
+	ErrInvalidHash            = errors.New("invalid hash")
+	ErrInvalidSelector        = errors.New("invalid selector")
+	ErrInvalidOwner           = errors.New("invalid owner key")
+	ErrInvalidCollaborator    = errors.New("invalid collaborator key")
+	ErrInvalidAlias           = errors.New("invalid alias")
+	ErrInvalidArtifact        = errors.New("invalid artifact")
+	ErrQuotaExceeded          = errors.New("quota exceeded")
+	ErrInvalidBatch           = errors.New("invalid batch")
+	ErrInvalidSignature       = errors.New("invalid artifact signature")
+	ErrInvalidArchiveFormat   = errors.New("invalid archive format")
+	ErrInvalidCursor          = errors.New("invalid cursor")
 )
 
+// MaxBatchItems is the most package versions a single PublishBatch call accepts.
+const MaxBatchItems = 200
+
 // PackageStore defines the storage operations needed by the packages domain.
 type PackageStore interface {
-	CreatePackage(ctx context.Context, pkg *storage.Package) error
+	CreatePackageVersions(ctx context.Context, versions []storage.PackageVersion) error
 	GetPackage(ctx context.Context, name, version string) (*storage.Package, error)
 	GetPackageVersions(ctx context.Context, name string, includePrerelease bool) ([]string, error)
+	GetPackageVersionDetails(ctx context.Context, name string) ([]storage.VersionDetail, error)
 	ListPackages(ctx context.Context, filter storage.PackageFilter, pagination storage.PaginationParams) (*storage.PaginatedResult[storage.Package], error)
+	ListPackagesByOwner(ctx context.Context, ownerKeyID string, pagination storage.PaginationParams) (*storage.PaginatedResult[storage.Package], error)
+	CountPackages(ctx context.Context, filter storage.PackageFilter) (int, error)
 	DeletePackage(ctx context.Context, name, version string) error
 	PackageExists(ctx context.Context, name, version string) (bool, error)
 	GetPackageOwner(ctx context.Context, name string) (string, error)
-	SetPackageOwner(ctx context.Context, name, ownerKeyID string) error
+	GetPackageOwnerInfo(ctx context.Context, name string) (*storage.PackageOwner, error)
+	UpdatePackageOwner(ctx context.Context, name, fromKeyID, toKeyID string) error
+	IsAuthorized(ctx context.Context, name, keyID string) (bool, error)
+	AddPackageCollaborator(ctx context.Context, name, keyID string) error
+	RemovePackageCollaborator(ctx context.Context, name, keyID string) error
+	ListPackageCollaborators(ctx context.Context, name string) ([]storage.PackageCollaborator, error)
+	SetPackageAlias(ctx context.Context, name, alias, version string) error
+	GetPackageAlias(ctx context.Context, name, alias string) (string, error)
+	ListPackageAliases(ctx context.Context, name string) ([]storage.PackageAlias, error)
+	DeletePackageAlias(ctx context.Context, name, alias string) error
+	ListPackageTags(ctx context.Context) ([]storage.TagCount, error)
+	GetAPIKey(ctx context.Context, id string) (*storage.APIKey, error)
+	GetOwnerArtifactBytes(ctx context.Context, ownerKeyID string) (int64, error)
+	CountDeploymentsByVersion(ctx context.Context, packageName string) ([]storage.VersionDeploymentCount, error)
 }
 
 // ContractStore defines the contract and artifact storage operations needed by the packages domain.
 type ContractStore interface {
-	CreateContract(ctx context.Context, packageID string, contract *storage.Contract) error
 	GetContract(ctx context.Context, packageID, contractName string) (*storage.Contract, error)
 	ListContracts(ctx context.Context, packageID string) ([]storage.Contract, error)
-	StoreArtifact(ctx context.Context, contractID, artifactType string, content []byte) error
 	GetArtifact(ctx context.Context, contractID, artifactType string) ([]byte, error)
+	FindContractsByHash(ctx context.Context, hash string) ([]storage.BytecodeMatch, error)
+	FindContractsBySelector(ctx context.Context, selector string) ([]storage.SelectorMatch, error)
 }
 
 type service struct {
 	packages  PackageStore
 	contracts ContractStore
+
+	maxArtifactsPerPublish int   // 0 means unlimited
+	maxOwnerArtifactBytes  int64 // 0 means unlimited
+	maxVersionsPerPackage  int   // 0 means unlimited
+	compilerPolicy         CompilerPolicy
+}
+
+// Option configures the package service.
+type Option func(*service)
+
+// WithMaxArtifactsPerPublish caps the number of artifacts accepted by a single
+// Publish call. Zero or negative disables the limit.
+func WithMaxArtifactsPerPublish(n int) Option {
+	return func(s *service) {
+		s.maxArtifactsPerPublish = n
+	}
+}
+
+// WithMaxOwnerArtifactBytes caps the total size of artifacts stored across all
+// packages owned by one API key. Zero or negative disables the limit.
+func WithMaxOwnerArtifactBytes(n int64) Option {
+	return func(s *service) {
+		s.maxOwnerArtifactBytes = n
+	}
+}
+
+// WithMaxVersionsPerPackage caps how many versions a package may have, prereleases
+// included. Zero or negative disables the limit.
+func WithMaxVersionsPerPackage(n int) Option {
+	return func(s *service) {
+		s.maxVersionsPerPackage = n
+	}
+}
+
+// WithCompilerPolicy rejects publishes containing artifacts built with compiler
+// versions or evmVersions the policy does not allow.
+func WithCompilerPolicy(p CompilerPolicy) Option {
+	return func(s *service) {
+		s.compilerPolicy = p
+	}
 }
 
 // NewService creates a new package service.
-func NewService(packages PackageStore, contracts ContractStore) *service {
-	return &service{
+func NewService(packages PackageStore, contracts ContractStore, opts ...Option) *service {
+	s := &service{
 		packages:  packages,
 		contracts: contracts,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Publish publishes a new package version.
 func (s *service) Publish(ctx context.Context, name, version string, ownerID string, req PublishRequest) error {
+	v, err := s.preparePublish(ctx, name, version, ownerID, req, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.packages.CreatePackageVersions(ctx, []storage.PackageVersion{*v}); err != nil {
+		return fmt.Errorf("storing package: %w", err)
+	}
+	return nil
+}
+
+// preparePublish validates a publish and builds the version it stores, without
+// writing anything. pending holds what earlier items of the same batch will add, so
+// the quotas cover the batch as a whole.
+func (s *service) preparePublish(ctx context.Context, name, version string, ownerID string, req PublishRequest, pending *batchUsage) (*storage.PackageVersion, error) {
 	// Validate package name
 	if err := validation.ValidatePackageName(name); err != nil {
-		return fmt.Errorf("%w: %v", ErrInvalidName, err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidName, err)
 	}
 
 	// Validate and normalize version
 	if err := validation.ValidateVersion(version); err != nil {
-		return fmt.Errorf("%w: %v", ErrInvalidVersion, err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVersion, err)
 	}
 	version = validation.NormalizeVersion(version)
 
-	// Check package ownership
-	currentOwner, err := s.packages.GetPackageOwner(ctx, name)
+	// Bound the work a single publish can trigger
+	if s.maxArtifactsPerPublish > 0 && len(req.Artifacts) > s.maxArtifactsPerPublish {
+		return nil, fmt.Errorf("%w: got %d, max %d", ErrTooManyArtifacts, len(req.Artifacts), s.maxArtifactsPerPublish)
+	}
+
+	for _, artifact := range req.Artifacts {
+		if err := s.compilerPolicy.check(artifact); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate and normalize contract labels, adding any detected standard interfaces
+	labels := make([][]string, len(req.Artifacts))
+	signatures := make([]*provenance.Signature, len(req.Artifacts))
+	for i, artifact := range req.Artifacts {
+		normalized, err := normalizeLabels(slices.Concat(artifact.Labels, evm.DetectInterfaces(artifact.ABI)))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidLabel, artifact.Name, err)
+		}
+		labels[i] = normalized
+
+		// Consumers (and the selector index) expect a parseable ABI
+		if len(artifact.ABI) > 0 {
+			if err := validation.ValidateABI(artifact.ABI); err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrInvalidArtifact, artifact.Name, err)
+			}
+		}
+
+		if err := validateExtraArtifacts(artifact.Extra); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidArtifact, artifact.Name, err)
+		}
+
+		sig, err := verifySignature(artifact)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidSignature, artifact.Name, err)
+		}
+		signatures[i] = sig
+	}
+
+	tags, err := normalizeTags(req.Tags)
 	if err != nil {
-		return fmt.Errorf("checking ownership: %w", err)
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTag, err)
 	}
-	if currentOwner != "" && currentOwner != ownerID {
-		return ErrForbidden
+
+	// Check the caller may publish under this name
+	if err := s.checkAuthorized(ctx, name, ownerID); err != nil {
+		return nil, err
 	}
 
 	// Check if version already exists
 	exists, err := s.packages.PackageExists(ctx, name, version)
 	if err != nil {
-		return fmt.Errorf("checking existence: %w", err)
+		return nil, fmt.Errorf("checking existence: %w", err)
 	}
 	if exists {
-		return ErrVersionExists
+		return nil, ErrVersionExists
+	}
+
+	if err := s.checkQuotas(ctx, name, ownerID, req, pending); err != nil {
+		return nil, err
 	}
 
 	// Extract compiler version and settings from first artifact (if available)
@@ -120,79 +262,223 @@ func (s *service) Publish(ctx context.Context, name, version string, ownerID str
 		CompilerVersion:  compilerVersion,
 		CompilerSettings: compilerSettings,
 		Metadata:         req.Metadata,
+		Tags:             tags,
 		OwnerID:          ownerID,
 	}
 
-	if err := s.packages.CreatePackage(ctx, pkg); err != nil {
-		return fmt.Errorf("creating package: %w", err)
-	}
+	// The first publisher owns the package
+	v := &storage.PackageVersion{Package: pkg, OwnerKeyID: ownerID}
 
-	// Set package ownership (first publisher owns the package)
-	if ownerID != "" {
-		if err := s.packages.SetPackageOwner(ctx, name, ownerID); err != nil {
-			// Log but don't fail - ownership is best-effort
-			_ = err
+	for i, artifact := range req.Artifacts {
+		// Solana programs are identified by their binary; EVM contracts by creation bytecode
+		primaryHash := storage.HashContent([]byte(artifact.Bytecode))
+		if len(artifact.Program) > 0 {
+			primaryHash = storage.HashContent(artifact.Program)
 		}
-	}
 
-	// Create contracts and store artifacts
-	for _, artifact := range req.Artifacts {
 		contract := &storage.Contract{
 			ID:          generateID(),
 			PackageID:   pkg.ID,
 			Name:        artifact.Name,
 			Chain:       req.Chain,
 			SourcePath:  artifact.SourcePath,
-			PrimaryHash: computeHash([]byte(artifact.Bytecode)),
+			PrimaryHash: primaryHash,
+			Labels:      labels[i],
+			Selectors:   storage.SelectorsFromABI(artifact.ABI),
+			Metadata:    artifact.Metadata,
 		}
 
-		if err := s.contracts.CreateContract(ctx, pkg.ID, contract); err != nil {
-			return fmt.Errorf("creating contract %s: %w", artifact.Name, err)
+		var artifacts []storage.ArtifactContent
+		add := func(artifactType string, content []byte) {
+			artifacts = append(artifacts, storage.ArtifactContent{Type: artifactType, Content: content})
 		}
-
-		// Store artifacts
 		if artifact.ABI != nil {
-			if err := s.contracts.StoreArtifact(ctx, contract.ID, "abi", artifact.ABI); err != nil {
-				return fmt.Errorf("storing ABI for %s: %w", artifact.Name, err)
-			}
+			add("abi", artifact.ABI)
 		}
 		if artifact.Bytecode != "" {
-			if err := s.contracts.StoreArtifact(ctx, contract.ID, "bytecode", []byte(artifact.Bytecode)); err != nil {
-				return fmt.Errorf("storing bytecode for %s: %w", artifact.Name, err)
-			}
+			add("bytecode", []byte(artifact.Bytecode))
 		}
 		if artifact.DeployedBytecode != "" {
-			if err := s.contracts.StoreArtifact(ctx, contract.ID, "deployed-bytecode", []byte(artifact.DeployedBytecode)); err != nil {
-				return fmt.Errorf("storing deployed bytecode for %s: %w", artifact.Name, err)
-			}
+			add("deployed-bytecode", []byte(artifact.DeployedBytecode))
 		}
 		if artifact.StandardJSONInput != nil {
-			if err := s.contracts.StoreArtifact(ctx, contract.ID, "standard-json-input", artifact.StandardJSONInput); err != nil {
-				return fmt.Errorf("storing standard JSON input for %s: %w", artifact.Name, err)
-			}
+			add("standard-json-input", artifact.StandardJSONInput)
 		}
 		if artifact.StorageLayout != nil {
-			if err := s.contracts.StoreArtifact(ctx, contract.ID, "storage-layout", artifact.StorageLayout); err != nil {
-				return fmt.Errorf("storing storage layout for %s: %w", artifact.Name, err)
+			add("storage-layout", artifact.StorageLayout)
+		}
+		if artifact.IDL != nil {
+			add("idl", artifact.IDL)
+		}
+		if len(artifact.Program) > 0 {
+			add("program", artifact.Program)
+		}
+		if sig := signatures[i]; sig != nil {
+			content, err := json.Marshal(sig)
+			if err != nil {
+				return nil, fmt.Errorf("encoding signature for %s: %w", artifact.Name, err)
 			}
+			add(SignatureArtifactType, content)
+		}
+		for _, artifactType := range slices.Sorted(maps.Keys(artifact.Extra)) {
+			add(artifactType, artifact.Extra[artifactType])
 		}
+
+		v.Contracts = append(v.Contracts, storage.ContractArtifacts{Contract: contract, Artifacts: artifacts})
 	}
 
-	return nil
+	return v, nil
 }
 
-// Get retrieves a specific package version.
-func (s *service) Get(ctx context.Context, name, version string) (*Package, error) {
-	// Handle "latest" version
-	if version == "latest" {
-		versions, err := s.packages.GetPackageVersions(ctx, name, false)
+// PublishBatch publishes several package versions in one call, in order. When atomic
+// is true the batch is all-or-nothing: every item is checked first, and only when
+// all pass are they stored, in a single transaction. Otherwise every item is
+// published on its own and reported on its own. Per-item failures are reported in
+// the results; the error is only set when the batch itself is invalid.
+func (s *service) PublishBatch(ctx context.Context, ownerID string, items []BatchItem, atomic bool) ([]BatchItemResult, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no packages", ErrInvalidBatch)
+	}
+	if len(items) > MaxBatchItems {
+		return nil, fmt.Errorf("%w: got %d packages, max %d", ErrInvalidBatch, len(items), MaxBatchItems)
+	}
+
+	results := make([]BatchItemResult, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		version := item.Version
+		if validation.ValidateVersion(version) == nil {
+			version = validation.NormalizeVersion(version)
+		}
+		key := item.Name + "@" + version
+		if seen[key] {
+			return nil, fmt.Errorf("%w: %s appears more than once", ErrInvalidBatch, key)
+		}
+		seen[key] = true
+		results[i] = BatchItemResult{Name: item.Name, Version: version, Status: BatchSkipped}
+	}
+
+	if !atomic {
+		for i, item := range items {
+			if err := s.Publish(ctx, item.Name, item.Version, ownerID, item.Request); err != nil {
+				results[i].Status = BatchFailed
+				results[i].Err = err
+				continue
+			}
+			results[i].Status = BatchPublished
+		}
+		return results, nil
+	}
+
+	versions := make([]storage.PackageVersion, len(items))
+	pending := &batchUsage{}
+	for i, item := range items {
+		v, err := s.preparePublish(ctx, item.Name, item.Version, ownerID, item.Request, pending)
 		if err != nil {
-			return nil, fmt.Errorf("getting versions: %w", err)
+			results[i].Status = BatchFailed
+			results[i].Err = err
+			for j := range i {
+				results[j].Status = BatchRolledBack
+			}
+			return results, nil
 		}
-		if len(versions) == 0 {
-			return nil, ErrNotFound
+		versions[i] = *v
+	}
+
+	if err := s.packages.CreatePackageVersions(ctx, versions); err != nil {
+		err = fmt.Errorf("storing batch: %w", err)
+		for i := range results {
+			results[i].Status = BatchFailed
+			results[i].Err = err
+		}
+		return results, nil
+	}
+	for i := range results {
+		results[i].Status = BatchPublished
+	}
+	return results, nil
+}
+
+// batchUsage tracks what the items of an atomic batch checked so far will add, as
+// none of it is in the store until the whole batch is.
+type batchUsage struct {
+	versions map[string]int   // package name -> versions
+	bytes    map[string]int64 // owner key -> artifact bytes
+}
+
+func (u *batchUsage) pendingVersions(name string) int {
+	if u == nil {
+		return 0
+	}
+	return u.versions[name]
+}
+
+func (u *batchUsage) pendingBytes(ownerID string) int64 {
+	if u == nil {
+		return 0
+	}
+	return u.bytes[ownerID]
+}
+
+func (u *batchUsage) add(name, packageOwner string, size int64) {
+	if u == nil {
+		return
+	}
+	if u.versions == nil {
+		u.versions = make(map[string]int)
+		u.bytes = make(map[string]int64)
+	}
+	u.versions[name]++
+	u.bytes[packageOwner] += size
+}
+
+// checkQuotas rejects a publish that would take the package past the version limit
+// or the package's owner past their artifact storage limit. Storage counts against
+// whoever owns the package, not the key publishing to it, so a collaborator's
+// publish uses up the owner's quota. Once the publish passes it is added to pending.
+func (s *service) checkQuotas(ctx context.Context, name, ownerID string, req PublishRequest, pending *batchUsage) error {
+	if s.maxVersionsPerPackage > 0 {
+		versions, err := s.packages.GetPackageVersions(ctx, name, true)
+		if err != nil {
+			return fmt.Errorf("counting versions: %w", err)
+		}
+		count := len(versions) + pending.pendingVersions(name)
+		if count >= s.maxVersionsPerPackage {
+			return fmt.Errorf("%w: %s already has %d of %d allowed versions",
+				ErrQuotaExceeded, name, count, s.maxVersionsPerPackage)
 		}
-		version = validation.ResolveLatest(versions, false)
+	}
+
+	// A package nobody owns yet is claimed by this publish
+	packageOwner, err := s.packages.GetPackageOwner(ctx, name)
+	if err != nil {
+		return fmt.Errorf("checking ownership: %w", err)
+	}
+	if packageOwner == "" {
+		packageOwner = ownerID
+	}
+
+	size := publishSize(req)
+	if s.maxOwnerArtifactBytes > 0 && packageOwner != "" {
+		used, err := s.packages.GetOwnerArtifactBytes(ctx, packageOwner)
+		if err != nil {
+			return fmt.Errorf("checking storage usage: %w", err)
+		}
+		used += pending.pendingBytes(packageOwner)
+		if used+size > s.maxOwnerArtifactBytes {
+			return fmt.Errorf("%w: artifact storage in use by the owner of %s is %d of %d bytes, this publish adds %d",
+				ErrQuotaExceeded, name, used, s.maxOwnerArtifactBytes, size)
+		}
+	}
+	pending.add(name, packageOwner, size)
+	return nil
+}
+
+// Get retrieves a specific package version.
+func (s *service) Get(ctx context.Context, name, version string) (*Package, error) {
+	version, err := s.resolveVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
 	}
 
 	pkg, err := s.packages.GetPackage(ctx, name, version)
@@ -206,52 +492,151 @@ func (s *service) Get(ctx context.Context, name, version string) (*Package, erro
 	return toPackage(pkg), nil
 }
 
+// resolveVersion turns "latest" and aliases into the version they currently name.
+// Other versions, and names that aren't aliases of the package, come back unchanged
+// for the store lookup to reject.
+func (s *service) resolveVersion(ctx context.Context, name, version string) (string, error) {
+	if version == "latest" {
+		// Prereleases are needed for packages that have nothing else; ResolveLatest
+		// prefers stable versions
+		versions, err := s.packages.GetPackageVersions(ctx, name, true)
+		if err != nil {
+			return "", fmt.Errorf("getting versions: %w", err)
+		}
+		if len(versions) == 0 {
+			return "", ErrNotFound
+		}
+		return validation.ResolveLatest(versions, false), nil
+	}
+	if validation.ValidateAlias(version) != nil {
+		return version, nil
+	}
+
+	target, err := s.packages.GetPackageAlias(ctx, name, version)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return version, nil
+		}
+		return "", fmt.Errorf("resolving alias %s: %w", version, err)
+	}
+	return target, nil
+}
+
 // GetVersions retrieves all versions of a package.
-func (s *service) GetVersions(ctx context.Context, name string, includePrerelease bool) (*VersionsResult, error) {
+func (s *service) GetVersions(ctx context.Context, name string, opts VersionsOptions) (*VersionsResult, error) {
+	includePrerelease := opts.IncludePrerelease
 	versions, err := s.packages.GetPackageVersions(ctx, name, includePrerelease)
 	if err != nil {
 		return nil, fmt.Errorf("getting versions: %w", err)
 	}
 
-	if len(versions) == 0 {
+	// Stores return an empty list (not an error) for unknown packages. A package with
+	// only prereleases still exists when they are hidden; it lists no versions.
+	all := versions
+	if len(versions) == 0 && !includePrerelease {
+		if all, err = s.packages.GetPackageVersions(ctx, name, true); err != nil {
+			return nil, fmt.Errorf("getting versions: %w", err)
+		}
+		versions = []string{}
+	}
+	if len(all) == 0 {
 		return nil, ErrNotFound
 	}
+	versions, hasMore, err := pageVersions(versions, opts.Limit, opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
 
-	// Get chain/builder from the latest version
+	// Get chain/builder from the latest version. A store failure here must surface
+	// as an error rather than a successful result with missing fields; only a version
+	// deleted since the listing above is tolerated.
 	var chain, builder string
-	if len(versions) > 0 {
-		latestVersion := validation.ResolveLatest(versions, includePrerelease)
-		if latestVersion != "" {
-			pkg, err := s.packages.GetPackage(ctx, name, latestVersion)
-			if err == nil {
-				chain = pkg.Chain
-				builder = pkg.Builder
-			}
+	if latestVersion := validation.ResolveLatest(all, includePrerelease); latestVersion != "" {
+		pkg, err := s.packages.GetPackage(ctx, name, latestVersion)
+		switch {
+		case err == nil:
+			chain = pkg.Chain
+			builder = pkg.Builder
+		case !errors.Is(err, storage.ErrNotFound):
+			return nil, fmt.Errorf("getting latest version %s: %w", latestVersion, err)
 		}
 	}
 
-	return &VersionsResult{
+	result := &VersionsResult{
 		Name:     name,
 		Chain:    chain,
 		Builder:  builder,
 		Versions: versions,
-	}, nil
+		HasMore:  hasMore,
+	}
+	if len(versions) > 0 {
+		result.NextCursor = versions[len(versions)-1]
+	}
+
+	if opts.Detailed {
+		details, err := s.packages.GetPackageVersionDetails(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("getting version details: %w", err)
+		}
+		listed := make(map[string]bool, len(versions))
+		for _, v := range versions {
+			listed[v] = true
+		}
+		result.Details = []VersionDetail{}
+		for _, d := range details {
+			if !listed[d.Version] {
+				continue
+			}
+			createdAt, _ := time.Parse("2006-01-02 15:04:05", d.CreatedAt)
+			result.Details = append(result.Details, VersionDetail{
+				Version:         d.Version,
+				Chain:           d.Chain,
+				Builder:         d.Builder,
+				CompilerVersion: d.CompilerVersion,
+				CreatedAt:       createdAt,
+			})
+		}
+	}
+
+	if opts.WithDeployments {
+		counts, err := s.packages.CountDeploymentsByVersion(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("counting deployments: %w", err)
+		}
+		result.Deployments = make(map[string]VersionDeployments, len(versions))
+		for _, v := range versions {
+			result.Deployments[v] = VersionDeployments{}
+		}
+		for _, c := range counts {
+			if _, ok := result.Deployments[c.Version]; ok {
+				result.Deployments[c.Version] = VersionDeployments{Count: c.Deployments, Verified: c.Verified}
+			}
+		}
+	}
+
+	return result, nil
 }
 
 // List lists packages with filtering and pagination.
 func (s *service) List(ctx context.Context, filter ListFilter, pagination PaginationParams) (*ListResult, error) {
-	result, err := s.packages.ListPackages(ctx, storage.PackageFilter{
-		Query:    filter.Query,
-		Chain:    filter.Chain,
-		Sort:     filter.Sort,
-		Order:    filter.Order,
-		Project:  filter.Project,
-		Version:  filter.Version,
-		Contract: filter.Contract,
-		Latest:   filter.Latest,
-	}, storage.PaginationParams{
+	storeFilter := storage.PackageFilter{
+		Query:           filter.Query,
+		Chain:           filter.Chain,
+		Sort:            filter.Sort,
+		Order:           filter.Order,
+		Project:         filter.Project,
+		Version:         filter.Version,
+		Contract:        filter.Contract,
+		Label:           validation.NormalizeLabel(filter.Label),
+		Tag:             validation.NormalizeTag(filter.Tag),
+		Latest:          filter.Latest,
+		CreatedAfter:    filter.CreatedAfter,
+		MetadataFilters: filter.Metadata,
+	}
+	result, err := s.packages.ListPackages(ctx, storeFilter, storage.PaginationParams{
 		Limit:  pagination.Limit,
 		Cursor: pagination.Cursor,
+		Before: pagination.Before,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("listing packages: %w", err)
@@ -265,7 +650,7 @@ func (s *service) List(ctx context.Context, filter ListFilter, pagination Pagina
 	}
 
 	// When project or version filter is set, inline contracts for each package
-	if (filter.Project != "" || filter.Version != "" || filter.Contract != "") && len(packages) > 0 {
+	if (filter.Project != "" || filter.Version != "" || filter.Contract != "" || filter.Label != "") && len(packages) > 0 {
 		for i := range packages {
 			versionToUse := filter.Version
 			if versionToUse == "" && len(packages[i].Versions) > 0 {
@@ -287,6 +672,41 @@ func (s *service) List(ctx context.Context, filter ListFilter, pagination Pagina
 		}
 	}
 
+	list := &ListResult{
+		Packages:   packages,
+		HasMore:    result.HasMore,
+		NextCursor: result.NextCursor,
+		PrevCursor: result.PrevCursor,
+	}
+	if filter.Count {
+		total, err := s.packages.CountPackages(ctx, storeFilter)
+		if err != nil {
+			return nil, fmt.Errorf("counting packages: %w", err)
+		}
+		list.Total = &total
+	}
+	return list, nil
+}
+
+// ListOwned lists the packages whose name ownerID owns, with the same pagination as
+// List.
+func (s *service) ListOwned(ctx context.Context, ownerID string, pagination PaginationParams) (*ListResult, error) {
+	if ownerID == "" {
+		return nil, ErrForbidden
+	}
+	result, err := s.packages.ListPackagesByOwner(ctx, ownerID, storage.PaginationParams{
+		Limit:  pagination.Limit,
+		Cursor: pagination.Cursor,
+		Before: pagination.Before,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing owned packages: %w", err)
+	}
+
+	packages := make([]Package, len(result.Data))
+	for i, p := range result.Data {
+		packages[i] = *toPackage(&p)
+	}
 	return &ListResult{
 		Packages:   packages,
 		HasMore:    result.HasMore,
@@ -295,16 +715,299 @@ func (s *service) List(ctx context.Context, filter ListFilter, pagination Pagina
 	}, nil
 }
 
-// Delete deletes a package version.
-func (s *service) Delete(ctx context.Context, name, version string, ownerID string) error {
-	// Check package ownership
-	currentOwner, err := s.packages.GetPackageOwner(ctx, name)
+// LookupBytecode finds the contracts whose creation bytecode, deployed bytecode or
+// program binary has the given hash (see NormalizeHash).
+func (s *service) LookupBytecode(ctx context.Context, hash string) ([]BytecodeMatch, error) {
+	hash, err := NormalizeHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := s.contracts.FindContractsByHash(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("looking up bytecode: %w", err)
+	}
+
+	matches := make([]BytecodeMatch, len(found))
+	for i, m := range found {
+		matches[i] = BytecodeMatch{
+			Package:   m.PackageName,
+			Version:   m.Version,
+			Contract:  m.ContractName,
+			Chain:     m.Chain,
+			MatchedOn: m.MatchedOn,
+		}
+	}
+	return matches, nil
+}
+
+// LookupSelector finds the contracts whose ABI has a function, error or event with the
+// given selector (see NormalizeSelector).
+func (s *service) LookupSelector(ctx context.Context, selector string) ([]SelectorMatch, error) {
+	selector, err := NormalizeSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := s.contracts.FindContractsBySelector(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("looking up selector: %w", err)
+	}
+
+	matches := make([]SelectorMatch, len(found))
+	for i, m := range found {
+		matches[i] = SelectorMatch{
+			Package:   m.PackageName,
+			Version:   m.Version,
+			Contract:  m.ContractName,
+			Chain:     m.Chain,
+			Type:      m.Type,
+			Signature: m.Signature,
+		}
+	}
+	return matches, nil
+}
+
+// GetOwner returns the API key that owns a package name. Ownership is only
+// disclosed to the owning key itself and to admin keys.
+func (s *service) GetOwner(ctx context.Context, name, callerID string, callerIsAdmin bool) (*Owner, error) {
+	owner, err := s.packages.GetPackageOwnerInfo(ctx, name)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("getting owner: %w", err)
+	}
+	if !callerIsAdmin && (callerID == "" || callerID != owner.KeyID) {
+		return nil, ErrForbidden
+	}
+
+	var since time.Time
+	if owner.CreatedAt != "" {
+		since, _ = time.Parse("2006-01-02 15:04:05", owner.CreatedAt)
+	}
+	return &Owner{Name: owner.KeyName, KeyID: owner.KeyID, Since: since}, nil
+}
+
+// TransferOwner hands a package name to another API key. Only the current owner
+// may transfer; the target must be an existing, unrevoked key. Each transfer is
+// recorded by the store for auditing.
+func (s *service) TransferOwner(ctx context.Context, name, callerID, toKeyID string) (*Owner, error) {
+	current, err := s.requireOwner(ctx, name, callerID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.packages.GetAPIKey(ctx, toKeyID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("%w: key %s does not exist or is revoked", ErrInvalidOwner, toKeyID)
+		}
+		return nil, fmt.Errorf("looking up key: %w", err)
+	}
+	if target.ID == current {
+		return nil, fmt.Errorf("%w: key %s already owns %s", ErrInvalidOwner, toKeyID, name)
+	}
+
+	if err := s.packages.UpdatePackageOwner(ctx, name, current, target.ID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			// Ownership changed since the check above
+			return nil, ErrForbidden
+		}
+		return nil, fmt.Errorf("transferring ownership: %w", err)
+	}
+	return s.GetOwner(ctx, name, target.ID, false)
+}
+
+// checkAuthorized fails with ErrForbidden unless keyID may publish to and delete
+// from package name: the name is unowned, or keyID owns it or collaborates on it.
+func (s *service) checkAuthorized(ctx context.Context, name, keyID string) error {
+	authorized, err := s.packages.IsAuthorized(ctx, name, keyID)
 	if err != nil {
 		return fmt.Errorf("checking ownership: %w", err)
 	}
-	if currentOwner != "" && currentOwner != ownerID {
+	if !authorized {
 		return ErrForbidden
 	}
+	return nil
+}
+
+// requireOwner returns the key that owns package name, failing with ErrNotFound
+// when it is unowned and ErrForbidden when callerID isn't the owner.
+func (s *service) requireOwner(ctx context.Context, name, callerID string) (string, error) {
+	owner, err := s.packages.GetPackageOwner(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("checking ownership: %w", err)
+	}
+	if owner == "" {
+		return "", ErrNotFound
+	}
+	if callerID == "" || callerID != owner {
+		return "", ErrForbidden
+	}
+	return owner, nil
+}
+
+// ListCollaborators lists the keys that may publish to a package besides its owner.
+// They are only disclosed to the owner, the collaborators themselves and admin keys.
+func (s *service) ListCollaborators(ctx context.Context, name, callerID string, callerIsAdmin bool) ([]Collaborator, error) {
+	owner, err := s.packages.GetPackageOwner(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("checking ownership: %w", err)
+	}
+	if owner == "" {
+		return nil, ErrNotFound
+	}
+
+	stored, err := s.packages.ListPackageCollaborators(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("listing collaborators: %w", err)
+	}
+	visible := callerIsAdmin || (callerID != "" && callerID == owner)
+	collaborators := make([]Collaborator, len(stored))
+	for i, c := range stored {
+		if callerID != "" && c.KeyID == callerID {
+			visible = true
+		}
+		var since time.Time
+		if c.CreatedAt != "" {
+			since, _ = time.Parse("2006-01-02 15:04:05", c.CreatedAt)
+		}
+		collaborators[i] = Collaborator{Name: c.KeyName, KeyID: c.KeyID, Since: since}
+	}
+	if !visible {
+		return nil, ErrForbidden
+	}
+	return collaborators, nil
+}
+
+// AddCollaborator lets keyID publish and delete versions of a package alongside its
+// owner. Only the owner may add collaborators; keyID must be an existing, unrevoked
+// key other than the owner. It returns the updated collaborators.
+func (s *service) AddCollaborator(ctx context.Context, name, callerID, keyID string) ([]Collaborator, error) {
+	owner, err := s.requireOwner(ctx, name, callerID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.packages.GetAPIKey(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("%w: key %s does not exist or is revoked", ErrInvalidCollaborator, keyID)
+		}
+		return nil, fmt.Errorf("looking up key: %w", err)
+	}
+	if target.ID == owner {
+		return nil, fmt.Errorf("%w: key %s already owns %s", ErrInvalidCollaborator, keyID, name)
+	}
+
+	if err := s.packages.AddPackageCollaborator(ctx, name, target.ID); err != nil {
+		return nil, fmt.Errorf("adding collaborator: %w", err)
+	}
+	return s.ListCollaborators(ctx, name, callerID, false)
+}
+
+// RemoveCollaborator revokes keyID's access to a package. Only the owner may remove
+// collaborators. It returns the remaining collaborators, or ErrNotFound when keyID
+// isn't one.
+func (s *service) RemoveCollaborator(ctx context.Context, name, callerID, keyID string) ([]Collaborator, error) {
+	if _, err := s.requireOwner(ctx, name, callerID); err != nil {
+		return nil, err
+	}
+	if err := s.packages.RemovePackageCollaborator(ctx, name, keyID); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("removing collaborator: %w", err)
+	}
+	return s.ListCollaborators(ctx, name, callerID, false)
+}
+
+// ListTags lists every package tag in use with how many packages carry it, by tag.
+func (s *service) ListTags(ctx context.Context) ([]TagCount, error) {
+	stored, err := s.packages.ListPackageTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags: %w", err)
+	}
+	tags := make([]TagCount, len(stored))
+	for i, t := range stored {
+		tags[i] = TagCount{Tag: t.Tag, Packages: t.Packages}
+	}
+	return tags, nil
+}
+
+// ListAliases lists the aliases of a package, by name.
+func (s *service) ListAliases(ctx context.Context, name string) ([]Alias, error) {
+	stored, err := s.packages.ListPackageAliases(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("listing aliases: %w", err)
+	}
+	aliases := make([]Alias, len(stored))
+	for i, a := range stored {
+		aliases[i] = Alias{Name: a.Alias, Version: a.Version}
+		if a.UpdatedAt != "" {
+			aliases[i].UpdatedAt, _ = time.Parse("2006-01-02 15:04:05", a.UpdatedAt)
+		}
+	}
+	return aliases, nil
+}
+
+// SetAlias points alias at a published version of a package, creating the alias or
+// moving it. Only the package owner may set aliases.
+func (s *service) SetAlias(ctx context.Context, name, alias, version, callerID string) (*Alias, error) {
+	if err := validation.ValidateAlias(alias); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAlias, err)
+	}
+	if err := validation.ValidateVersion(version); err != nil {
+		return nil, fmt.Errorf("%w: an alias must point at an exact version: %v", ErrInvalidVersion, err)
+	}
+	if _, err := s.requireOwner(ctx, name, callerID); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.packages.PackageExists(ctx, name, version)
+	if err != nil {
+		return nil, fmt.Errorf("checking existence: %w", err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	if err := s.packages.SetPackageAlias(ctx, name, alias, version); err != nil {
+		return nil, fmt.Errorf("setting alias: %w", err)
+	}
+	return &Alias{Name: alias, Version: version, UpdatedAt: time.Now().UTC().Truncate(time.Second)}, nil
+}
+
+// DeleteAlias removes an alias of a package. Only the package owner may remove
+// aliases; ErrNotFound means the package or the alias doesn't exist.
+func (s *service) DeleteAlias(ctx context.Context, name, alias, callerID string) error {
+	if _, err := s.requireOwner(ctx, name, callerID); err != nil {
+		return err
+	}
+	if err := s.packages.DeletePackageAlias(ctx, name, alias); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("deleting alias: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a package version.
+func (s *service) Delete(ctx context.Context, name, version string, ownerID string) error {
+	// Check the caller may delete from this name
+	if err := s.checkAuthorized(ctx, name, ownerID); err != nil {
+		return err
+	}
+
+	exists, err := s.packages.PackageExists(ctx, name, version)
+	if err != nil {
+		return fmt.Errorf("checking existence: %w", err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
 
 	if err := s.packages.DeletePackage(ctx, name, version); err != nil {
 		return fmt.Errorf("deleting package: %w", err)
@@ -315,6 +1018,11 @@ func (s *service) Delete(ctx context.Context, name, version string, ownerID stri
 
 // GetContracts lists contracts in a package version.
 func (s *service) GetContracts(ctx context.Context, name, version string) ([]Contract, error) {
+	version, err := s.resolveVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+
 	pkg, err := s.packages.GetPackage(ctx, name, version)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
@@ -338,6 +1046,8 @@ func (s *service) GetContracts(ctx context.Context, name, version string) ([]Con
 			SourcePath:  c.SourcePath,
 			License:     c.License,
 			PrimaryHash: c.PrimaryHash,
+			Labels:      c.Labels,
+			Metadata:    c.Metadata,
 		}
 	}
 
@@ -346,6 +1056,11 @@ func (s *service) GetContracts(ctx context.Context, name, version string) ([]Con
 
 // GetContract retrieves a specific contract.
 func (s *service) GetContract(ctx context.Context, name, version, contractName string) (*Contract, error) {
+	version, err := s.resolveVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+
 	pkg, err := s.packages.GetPackage(ctx, name, version)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
@@ -375,6 +1090,8 @@ func (s *service) GetContract(ctx context.Context, name, version, contractName s
 		SourcePath:        contract.SourcePath,
 		License:           contract.License,
 		PrimaryHash:       contract.PrimaryHash,
+		Labels:            contract.Labels,
+		Metadata:          contract.Metadata,
 		CompilationTarget: compilationTarget,
 		CompilerVersion:   pkg.CompilerVersion,
 		CompilerSettings:  pkg.CompilerSettings,
@@ -383,6 +1100,11 @@ func (s *service) GetContract(ctx context.Context, name, version, contractName s
 
 // GetArtifact retrieves a specific artifact for a contract.
 func (s *service) GetArtifact(ctx context.Context, name, version, contractName, artifactType string) ([]byte, error) {
+	version, err := s.resolveVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+
 	pkg, err := s.packages.GetPackage(ctx, name, version)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
@@ -410,8 +1132,14 @@ func (s *service) GetArtifact(ctx context.Context, name, version, contractName,
 	return content, nil
 }
 
-// GetArchive returns a gzipped tarball of all artifacts for a package version.
-func (s *service) GetArchive(ctx context.Context, name, version string) ([]byte, error) {
+// GetArchive returns an archive of all artifacts for a package version, as a
+// gzipped tarball or a zip depending on format.
+func (s *service) GetArchive(ctx context.Context, name, version string, format ArchiveFormat) ([]byte, error) {
+	version, err := s.resolveVersion(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get package
 	pkg, err := s.packages.GetPackage(ctx, name, version)
 	if err != nil {
@@ -421,27 +1149,31 @@ func (s *service) GetArchive(ctx context.Context, name, version string) ([]byte,
 		return nil, fmt.Errorf("getting package: %w", err)
 	}
 
-	// Get contracts
+	// Get contracts, sorted so the archive layout doesn't depend on storage order
 	contracts, err := s.contracts.ListContracts(ctx, pkg.ID)
 	if err != nil {
 		return nil, fmt.Errorf("listing contracts: %w", err)
 	}
+	sort.Slice(contracts, func(i, j int) bool { return contracts[i].Name < contracts[j].Name })
 
-	// Create archive
+	// Create archive. Archives must be byte-for-byte reproducible for a given version
+	// so they can be pinned by content hash: no wall-clock timestamps anywhere.
 	var buf bytes.Buffer
-	gw := gzip.NewWriter(&buf)
-	tw := tar.NewWriter(gw)
+	aw := newArchiveWriter(&buf, format)
 
 	basePath := fmt.Sprintf("%s-%s", name, version)
 
-	// Add manifest
+	// Add manifest (map keys are marshaled in sorted order)
 	manifest := map[string]any{
 		"name":      name,
 		"version":   version,
 		"chain":     pkg.Chain,
 		"builder":   pkg.Builder,
 		"contracts": make([]map[string]string, 0, len(contracts)),
-		"createdAt": time.Now().UTC().Format(time.RFC3339),
+	}
+	// createdAt is the version's publish time, which never changes
+	if createdAt := toPackage(pkg).CreatedAt; !createdAt.IsZero() {
+		manifest["createdAt"] = createdAt.UTC().Format(time.RFC3339)
 	}
 	contractList := manifest["contracts"].([]map[string]string)
 	for _, c := range contracts {
@@ -453,66 +1185,106 @@ func (s *service) GetArchive(ctx context.Context, name, version string) ([]byte,
 	manifest["contracts"] = contractList
 
 	manifestData, _ := json.MarshalIndent(manifest, "", "  ")
-	if err := addToTar(tw, basePath+"/manifest.json", manifestData); err != nil {
+	if err := aw.add(basePath+"/manifest.json", manifestData); err != nil {
 		return nil, fmt.Errorf("adding manifest: %w", err)
 	}
 
-	// Add each contract's artifacts
+	// Add each contract's artifacts, always in the same file order
 	for _, contract := range contracts {
 		contractPath := fmt.Sprintf("%s/%s", basePath, contract.Name)
 
 		// ABI
 		if content, err := s.contracts.GetArtifact(ctx, contract.ID, "abi"); err == nil {
-			if err := addToTar(tw, contractPath+"/abi.json", content); err != nil {
+			if err := aw.add(contractPath+"/abi.json", content); err != nil {
 				return nil, fmt.Errorf("adding ABI: %w", err)
 			}
 		}
 
 		// Bytecode
 		if content, err := s.contracts.GetArtifact(ctx, contract.ID, "bytecode"); err == nil {
-			if err := addToTar(tw, contractPath+"/bytecode.hex", content); err != nil {
+			if err := aw.add(contractPath+"/bytecode.hex", content); err != nil {
 				return nil, fmt.Errorf("adding bytecode: %w", err)
 			}
 		}
 
 		// Deployed bytecode
 		if content, err := s.contracts.GetArtifact(ctx, contract.ID, "deployed-bytecode"); err == nil {
-			if err := addToTar(tw, contractPath+"/deployed-bytecode.hex", content); err != nil {
+			if err := aw.add(contractPath+"/deployed-bytecode.hex", content); err != nil {
 				return nil, fmt.Errorf("adding deployed bytecode: %w", err)
 			}
 		}
 
 		// Standard JSON Input
 		if content, err := s.contracts.GetArtifact(ctx, contract.ID, "standard-json-input"); err == nil {
-			if err := addToTar(tw, contractPath+"/standard-json-input.json", content); err != nil {
+			if err := aw.add(contractPath+"/standard-json-input.json", content); err != nil {
 				return nil, fmt.Errorf("adding standard JSON input: %w", err)
 			}
 		}
 
 		// Storage Layout
 		if content, err := s.contracts.GetArtifact(ctx, contract.ID, "storage-layout"); err == nil {
-			if err := addToTar(tw, contractPath+"/storage-layout.json", content); err != nil {
+			if err := aw.add(contractPath+"/storage-layout.json", content); err != nil {
 				return nil, fmt.Errorf("adding storage layout: %w", err)
 			}
 		}
-	}
 
-	if err := tw.Close(); err != nil {
-		return nil, fmt.Errorf("closing tar: %w", err)
+		// Anchor IDL (Solana)
+		if content, err := s.contracts.GetArtifact(ctx, contract.ID, "idl"); err == nil {
+			if err := aw.add(contractPath+"/idl.json", content); err != nil {
+				return nil, fmt.Errorf("adding IDL: %w", err)
+			}
+		}
+
+		// Program binary (Solana)
+		if content, err := s.contracts.GetArtifact(ctx, contract.ID, "program"); err == nil {
+			if err := aw.add(contractPath+"/"+contract.Name+".so", content); err != nil {
+				return nil, fmt.Errorf("adding program binary: %w", err)
+			}
+		}
+
+		// Publisher signature, only stored for signed artifacts
+		if content, err := s.contracts.GetArtifact(ctx, contract.ID, SignatureArtifactType); err == nil {
+			if err := aw.add(contractPath+"/signature.json", content); err != nil {
+				return nil, fmt.Errorf("adding signature: %w", err)
+			}
+		}
+
+		// Sources, only stored when published with --include-sources
+		if content, err := s.contracts.GetArtifact(ctx, contract.ID, SourcesArtifactType); err == nil {
+			sources, err := decodeSources(content)
+			if err != nil {
+				return nil, fmt.Errorf("reading sources of %s: %w", contract.Name, err)
+			}
+			for _, p := range slices.Sorted(maps.Keys(sources)) {
+				// Sources published under older, looser rules may hold paths that
+				// would escape the directory the archive is extracted into
+				if validation.ValidateSourcePath(p) != nil {
+					continue
+				}
+				if err := aw.add(contractPath+"/sources/"+path.Clean(p), []byte(sources[p])); err != nil {
+					return nil, fmt.Errorf("adding source %s: %w", p, err)
+				}
+			}
+		}
 	}
-	if err := gw.Close(); err != nil {
-		return nil, fmt.Errorf("closing gzip: %w", err)
+
+	if err := aw.close(); err != nil {
+		return nil, err
 	}
 
 	return buf.Bytes(), nil
 }
 
+// archiveModTime is the fixed modification time of every archive entry.
+var archiveModTime = time.Unix(0, 0).UTC()
+
 func addToTar(tw *tar.Writer, path string, content []byte) error {
 	header := &tar.Header{
-		Name:    path,
-		Mode:    0644,
-		Size:    int64(len(content)),
-		ModTime: time.Now(),
+		Typeflag: tar.TypeReg,
+		Name:     path,
+		Mode:     0644,
+		Size:     int64(len(content)),
+		ModTime:  archiveModTime,
 	}
 	if err := tw.WriteHeader(header); err != nil {
 		return err
@@ -539,6 +1311,7 @@ func toPackage(p *storage.Package) *Package {
 		CompilerVersion:  p.CompilerVersion,
 		CompilerSettings: p.CompilerSettings,
 		Metadata:         p.Metadata,
+		Tags:             p.Tags,
 		OwnerID:          p.OwnerID,
 		CreatedAt:        createdAt,
 		Versions:         p.Versions,
@@ -1,13 +1,28 @@
 package domain
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/pendergraft/contrafactory/internal/provenance"
 	"github.com/pendergraft/contrafactory/internal/storage"
+	"github.com/pendergraft/contrafactory/internal/validation"
 )
 
 // mockStore implements storage.Store for testing
@@ -16,6 +31,16 @@ type mockStore struct {
 	contracts map[string]*storage.Contract
 	artifacts map[string][]byte
 	owners    map[string]string
+
+	collaborators map[string][]string // package name -> key IDs, oldest first
+	aliases       map[string]string   // name@alias -> version
+
+	deploymentCounts []storage.VersionDeploymentCount
+
+	// Injected failures
+	getPackageErr     error
+	listVersionErr    error
+	createVersionsErr error
 }
 
 func newMockStore() *mockStore {
@@ -24,16 +49,45 @@ func newMockStore() *mockStore {
 		contracts: make(map[string]*storage.Contract),
 		artifacts: make(map[string][]byte),
 		owners:    make(map[string]string),
+
+		collaborators: make(map[string][]string),
+		aliases:       make(map[string]string),
 	}
 }
 
+func (m *mockStore) CountDeploymentsByVersion(ctx context.Context, packageName string) ([]storage.VersionDeploymentCount, error) {
+	return m.deploymentCounts, nil
+}
+
 func (m *mockStore) CreatePackage(ctx context.Context, pkg *storage.Package) error {
 	key := pkg.Name + "@" + pkg.Version
 	m.packages[key] = pkg
 	return nil
 }
 
+func (m *mockStore) CreatePackageVersions(ctx context.Context, versions []storage.PackageVersion) error {
+	if m.createVersionsErr != nil {
+		return m.createVersionsErr
+	}
+	for _, v := range versions {
+		_ = m.CreatePackage(ctx, v.Package)
+		if v.OwnerKeyID != "" {
+			_ = m.SetPackageOwner(ctx, v.Package.Name, v.OwnerKeyID)
+		}
+		for _, c := range v.Contracts {
+			_ = m.CreateContract(ctx, v.Package.ID, c.Contract)
+			for _, a := range c.Artifacts {
+				_ = m.StoreArtifact(ctx, c.Contract.ID, a.Type, a.Content)
+			}
+		}
+	}
+	return nil
+}
+
 func (m *mockStore) GetPackage(ctx context.Context, name, version string) (*storage.Package, error) {
+	if m.getPackageErr != nil {
+		return nil, m.getPackageErr
+	}
 	key := name + "@" + version
 	if pkg, ok := m.packages[key]; ok {
 		return pkg, nil
@@ -42,14 +96,38 @@ func (m *mockStore) GetPackage(ctx context.Context, name, version string) (*stor
 }
 
 func (m *mockStore) GetPackageVersions(ctx context.Context, name string, includePrerelease bool) ([]string, error) {
+	if m.listVersionErr != nil {
+		return nil, m.listVersionErr
+	}
+	var pkgs []*storage.Package
+	for _, pkg := range m.packages {
+		if pkg.Name == name && (includePrerelease || !validation.IsPrerelease(pkg.Version)) {
+			pkgs = append(pkgs, pkg)
+		}
+	}
+	// Newest first, like the stores
+	sort.Slice(pkgs, func(i, j int) bool {
+		if pkgs[i].CreatedAt != pkgs[j].CreatedAt {
+			return pkgs[i].CreatedAt > pkgs[j].CreatedAt
+		}
+		return pkgs[i].Version > pkgs[j].Version
+	})
 	var versions []string
-	for key, pkg := range m.packages {
+	for _, pkg := range pkgs {
+		versions = append(versions, pkg.Version)
+	}
+	return versions, nil
+}
+
+func (m *mockStore) GetPackageVersionDetails(ctx context.Context, name string) ([]storage.VersionDetail, error) {
+	var details []storage.VersionDetail
+	for _, pkg := range m.packages {
 		if pkg.Name == name {
-			versions = append(versions, pkg.Version)
-			_ = key
+			details = append(details, storage.VersionDetail{Version: pkg.Version, Chain: pkg.Chain, Builder: pkg.Builder, CompilerVersion: pkg.CompilerVersion, CreatedAt: pkg.CreatedAt})
 		}
 	}
-	return versions, nil
+	sort.Slice(details, func(i, j int) bool { return details[i].CreatedAt > details[j].CreatedAt })
+	return details, nil
 }
 
 func (m *mockStore) ListPackages(ctx context.Context, filter storage.PackageFilter, pagination storage.PaginationParams) (*storage.PaginatedResult[storage.Package], error) {
@@ -60,6 +138,24 @@ func (m *mockStore) ListPackages(ctx context.Context, filter storage.PackageFilt
 	return &storage.PaginatedResult[storage.Package]{Data: packages}, nil
 }
 
+func (m *mockStore) ListPackagesByOwner(ctx context.Context, ownerKeyID string, pagination storage.PaginationParams) (*storage.PaginatedResult[storage.Package], error) {
+	var packages []storage.Package
+	for _, pkg := range m.packages {
+		if m.owners[pkg.Name] == ownerKeyID {
+			packages = append(packages, *pkg)
+		}
+	}
+	return &storage.PaginatedResult[storage.Package]{Data: packages}, nil
+}
+
+func (m *mockStore) CountPackages(ctx context.Context, filter storage.PackageFilter) (int, error) {
+	names := make(map[string]bool)
+	for _, pkg := range m.packages {
+		names[pkg.Name] = true
+	}
+	return len(names), nil
+}
+
 func (m *mockStore) DeletePackage(ctx context.Context, name, version string) error {
 	key := name + "@" + version
 	delete(m.packages, key)
@@ -76,6 +172,125 @@ func (m *mockStore) GetPackageOwner(ctx context.Context, name string) (string, e
 	return m.owners[name], nil
 }
 
+func (m *mockStore) GetPackageOwnerInfo(ctx context.Context, name string) (*storage.PackageOwner, error) {
+	keyID, ok := m.owners[name]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return &storage.PackageOwner{KeyID: keyID, KeyName: "key-" + keyID, CreatedAt: "2025-06-15 14:30:45"}, nil
+}
+
+func (m *mockStore) UpdatePackageOwner(ctx context.Context, name, fromKeyID, toKeyID string) error {
+	if m.owners[name] != fromKeyID {
+		return storage.ErrNotFound
+	}
+	m.owners[name] = toKeyID
+	return nil
+}
+
+func (m *mockStore) IsAuthorized(ctx context.Context, name, keyID string) (bool, error) {
+	owner, owned := m.owners[name]
+	return !owned || owner == keyID || slices.Contains(m.collaborators[name], keyID), nil
+}
+
+func (m *mockStore) AddPackageCollaborator(ctx context.Context, name, keyID string) error {
+	if !slices.Contains(m.collaborators[name], keyID) {
+		m.collaborators[name] = append(m.collaborators[name], keyID)
+	}
+	return nil
+}
+
+func (m *mockStore) RemovePackageCollaborator(ctx context.Context, name, keyID string) error {
+	i := slices.Index(m.collaborators[name], keyID)
+	if i < 0 {
+		return storage.ErrNotFound
+	}
+	m.collaborators[name] = slices.Delete(m.collaborators[name], i, i+1)
+	return nil
+}
+
+func (m *mockStore) ListPackageCollaborators(ctx context.Context, name string) ([]storage.PackageCollaborator, error) {
+	var collaborators []storage.PackageCollaborator
+	for _, keyID := range m.collaborators[name] {
+		collaborators = append(collaborators, storage.PackageCollaborator{KeyID: keyID, KeyName: "key-" + keyID, CreatedAt: "2025-07-01 09:00:00"})
+	}
+	return collaborators, nil
+}
+
+func (m *mockStore) SetPackageAlias(ctx context.Context, name, alias, version string) error {
+	m.aliases[name+"@"+alias] = version
+	return nil
+}
+
+func (m *mockStore) GetPackageAlias(ctx context.Context, name, alias string) (string, error) {
+	version, ok := m.aliases[name+"@"+alias]
+	if !ok {
+		return "", storage.ErrNotFound
+	}
+	return version, nil
+}
+
+func (m *mockStore) ListPackageAliases(ctx context.Context, name string) ([]storage.PackageAlias, error) {
+	var aliases []storage.PackageAlias
+	for _, key := range slices.Sorted(maps.Keys(m.aliases)) {
+		if alias, ok := strings.CutPrefix(key, name+"@"); ok {
+			aliases = append(aliases, storage.PackageAlias{Alias: alias, Version: m.aliases[key], UpdatedAt: "2025-08-01 12:00:00"})
+		}
+	}
+	return aliases, nil
+}
+
+func (m *mockStore) ListPackageTags(ctx context.Context) ([]storage.TagCount, error) {
+	names := make(map[string]map[string]bool)
+	for _, pkg := range m.packages {
+		for _, tag := range pkg.Tags {
+			if names[tag] == nil {
+				names[tag] = make(map[string]bool)
+			}
+			names[tag][pkg.Name] = true
+		}
+	}
+	var tags []storage.TagCount
+	for _, tag := range slices.Sorted(maps.Keys(names)) {
+		tags = append(tags, storage.TagCount{Tag: tag, Packages: len(names[tag])})
+	}
+	return tags, nil
+}
+
+func (m *mockStore) DeletePackageAlias(ctx context.Context, name, alias string) error {
+	if _, ok := m.aliases[name+"@"+alias]; !ok {
+		return storage.ErrNotFound
+	}
+	delete(m.aliases, name+"@"+alias)
+	return nil
+}
+
+func (m *mockStore) GetAPIKey(ctx context.Context, id string) (*storage.APIKey, error) {
+	if !strings.HasPrefix(id, "owner-") {
+		return nil, storage.ErrNotFound
+	}
+	return &storage.APIKey{ID: id, Name: "key-" + id}, nil
+}
+
+func (m *mockStore) GetOwnerArtifactBytes(ctx context.Context, ownerKeyID string) (int64, error) {
+	pkgNames := make(map[string]string, len(m.packages)) // package ID -> name
+	for _, p := range m.packages {
+		pkgNames[p.ID] = p.Name
+	}
+	var total int64
+	for _, c := range m.contracts {
+		if m.owners[pkgNames[c.PackageID]] != ownerKeyID {
+			continue
+		}
+		for key, content := range m.artifacts {
+			if strings.HasPrefix(key, c.ID+"/") {
+				total += int64(len(content))
+			}
+		}
+	}
+	return total, nil
+}
+
 func (m *mockStore) SetPackageOwner(ctx context.Context, name, ownerKeyID string) error {
 	if _, exists := m.owners[name]; !exists {
 		m.owners[name] = ownerKeyID
@@ -122,6 +337,45 @@ func (m *mockStore) GetArtifact(ctx context.Context, contractID, artifactType st
 	return nil, storage.ErrNotFound
 }
 
+func (m *mockStore) FindContractsBySelector(ctx context.Context, selector string) ([]storage.SelectorMatch, error) {
+	var matches []storage.SelectorMatch
+	for _, pkg := range m.packages {
+		for _, c := range m.contracts {
+			if c.PackageID != pkg.ID {
+				continue
+			}
+			for _, sel := range c.Selectors {
+				if sel.Selector == selector {
+					matches = append(matches, storage.SelectorMatch{PackageName: pkg.Name, Version: pkg.Version, ContractName: c.Name, Chain: c.Chain, Type: sel.Type, Signature: sel.Signature})
+				}
+			}
+		}
+	}
+	return matches, nil
+}
+
+func (m *mockStore) FindContractsByHash(ctx context.Context, hash string) ([]storage.BytecodeMatch, error) {
+	var matches []storage.BytecodeMatch
+	for _, pkg := range m.packages {
+		for _, c := range m.contracts {
+			if c.PackageID != pkg.ID {
+				continue
+			}
+			match := storage.BytecodeMatch{PackageName: pkg.Name, Version: pkg.Version, ContractName: c.Name, Chain: c.Chain}
+			switch {
+			case c.PrimaryHash == hash:
+				match.MatchedOn = "bytecode"
+			case storage.HashContent(m.artifacts[c.ID+"/deployed-bytecode"]) == hash:
+				match.MatchedOn = "deployed-bytecode"
+			default:
+				continue
+			}
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
+
 func (m *mockStore) Close() error                      { return nil }
 func (m *mockStore) Migrate(ctx context.Context) error { return nil }
 
@@ -211,94 +465,1166 @@ func TestService_Publish(t *testing.T) {
 	}
 }
 
-func TestService_Get(t *testing.T) {
-	store := newMockStore()
-	store.packages["my-package@1.0.0"] = &storage.Package{
-		ID:      "pkg-123",
-		Name:    "my-package",
-		Version: "1.0.0",
-		Chain:   "evm",
+func TestService_PublishBatch(t *testing.T) {
+	req := PublishRequest{Chain: "evm", Artifacts: []Artifact{{Name: "Token", Bytecode: "0x1234"}}}
+	items := []BatchItem{
+		{Name: "token", Version: "1.0.0", Request: req},
+		{Name: "registry", Version: "v1.0.0", Request: req},
+		{Name: "existing", Version: "1.0.0", Request: req},
+		{Name: "vault", Version: "1.0.0", Request: req},
+	}
+	statuses := func(results []BatchItemResult) []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.Status)
+		}
+		return out
 	}
 
-	svc := NewService(store, store)
+	t.Run("atomic rolls back", func(t *testing.T) {
+		store := newMockStore()
+		store.packages["existing@1.0.0"] = &storage.Package{Name: "existing", Version: "1.0.0"}
+		svc := NewService(store, store)
 
-	t.Run("existing package", func(t *testing.T) {
-		pkg, err := svc.Get(context.Background(), "my-package", "1.0.0")
+		results, err := svc.PublishBatch(context.Background(), "owner-1", items, true)
 		require.NoError(t, err)
-		assert.Equal(t, "my-package", pkg.Name)
-		assert.Equal(t, "1.0.0", pkg.Version)
+		assert.Equal(t, []string{BatchRolledBack, BatchRolledBack, BatchFailed, BatchSkipped}, statuses(results))
+		assert.ErrorIs(t, results[2].Err, ErrVersionExists)
+		assert.Equal(t, "1.0.0", results[1].Version)
+		assert.Len(t, store.packages, 1)
+		assert.Empty(t, store.owners)
+		assert.Empty(t, store.contracts)
+		assert.Empty(t, store.artifacts)
 	})
 
-	t.Run("non-existing package", func(t *testing.T) {
-		_, err := svc.Get(context.Background(), "not-found", "1.0.0")
-		require.Error(t, err)
-		assert.ErrorIs(t, err, ErrNotFound)
+	t.Run("atomic store failure", func(t *testing.T) {
+		store := newMockStore()
+		store.createVersionsErr = errors.New("artifact rejected")
+		svc := NewService(store, store)
+
+		results, err := svc.PublishBatch(context.Background(), "owner-1", items[:2], true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{BatchFailed, BatchFailed}, statuses(results))
+		assert.ErrorContains(t, results[0].Err, "artifact rejected")
+		assert.Empty(t, store.packages)
+		assert.Empty(t, store.owners)
+		assert.Empty(t, store.artifacts)
 	})
 
-	t.Run("latest version", func(t *testing.T) {
-		store.packages["my-package@2.0.0"] = &storage.Package{
-			ID:      "pkg-456",
-			Name:    "my-package",
-			Version: "2.0.0",
-			Chain:   "evm",
-		}
-		pkg, err := svc.Get(context.Background(), "my-package", "latest")
+	t.Run("atomic quotas cover the whole batch", func(t *testing.T) {
+		store := newMockStore()
+		svc := NewService(store, store, WithMaxVersionsPerPackage(1))
+
+		results, err := svc.PublishBatch(context.Background(), "owner-1", []BatchItem{
+			{Name: "token", Version: "1.0.0", Request: req},
+			{Name: "token", Version: "1.1.0", Request: req},
+		}, true)
 		require.NoError(t, err)
-		assert.Equal(t, "2.0.0", pkg.Version)
+		assert.Equal(t, []string{BatchRolledBack, BatchFailed}, statuses(results))
+		assert.ErrorIs(t, results[1].Err, ErrQuotaExceeded)
+		assert.Empty(t, store.packages)
 	})
-}
 
-func TestService_GetVersions(t *testing.T) {
-	store := newMockStore()
-	store.packages["my-package@1.0.0"] = &storage.Package{Name: "my-package", Version: "1.0.0"}
-	store.packages["my-package@2.0.0"] = &storage.Package{Name: "my-package", Version: "2.0.0"}
+	t.Run("best effort", func(t *testing.T) {
+		store := newMockStore()
+		store.packages["existing@1.0.0"] = &storage.Package{Name: "existing", Version: "1.0.0"}
+		svc := NewService(store, store)
 
-	svc := NewService(store, store)
+		results, err := svc.PublishBatch(context.Background(), "", items, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{BatchPublished, BatchPublished, BatchFailed, BatchPublished}, statuses(results))
+		assert.Contains(t, store.packages, "vault@1.0.0")
+	})
 
-	t.Run("existing package", func(t *testing.T) {
-		result, err := svc.GetVersions(context.Background(), "my-package", false)
+	t.Run("invalid batch", func(t *testing.T) {
+		svc := NewService(newMockStore(), newMockStore())
+		_, err := svc.PublishBatch(context.Background(), "", nil, true)
+		assert.ErrorIs(t, err, ErrInvalidBatch)
+
+		_, err = svc.PublishBatch(context.Background(), "", []BatchItem{items[0], {Name: "token", Version: "v1.0.0"}}, true)
+		assert.ErrorIs(t, err, ErrInvalidBatch)
+	})
+}
+
+func TestService_PublishLabels(t *testing.T) {
+	t.Run("normalizes and deduplicates", func(t *testing.T) {
+		store := newMockStore()
+		svc := NewService(store, store)
+		req := PublishRequest{
+			Chain:     "evm",
+			Artifacts: []Artifact{{Name: "Token", Labels: []string{" Upgradeable", "ERC20", "erc20"}}},
+		}
+		require.NoError(t, svc.Publish(context.Background(), "my-package", "1.0.0", "", req))
+
+		contract, err := svc.GetContract(context.Background(), "my-package", "1.0.0", "Token")
 		require.NoError(t, err)
-		assert.Equal(t, "my-package", result.Name)
-		assert.Len(t, result.Versions, 2)
+		assert.Equal(t, []string{"erc20", "upgradeable"}, contract.Labels)
 	})
 
-	t.Run("non-existing package", func(t *testing.T) {
-		_, err := svc.GetVersions(context.Background(), "not-found", false)
+	t.Run("invalid label", func(t *testing.T) {
+		store := newMockStore()
+		svc := NewService(store, store)
+		req := PublishRequest{
+			Chain:     "evm",
+			Artifacts: []Artifact{{Name: "Token", Labels: []string{"erc 20"}}},
+		}
+		err := svc.Publish(context.Background(), "my-package", "1.0.0", "", req)
 		require.Error(t, err)
-		assert.ErrorIs(t, err, ErrNotFound)
+		assert.ErrorIs(t, err, ErrInvalidLabel)
+
+		exists, err := store.PackageExists(context.Background(), "my-package", "1.0.0")
+		require.NoError(t, err)
+		assert.False(t, exists, "nothing should be stored when a label is invalid")
 	})
 }
 
-func TestService_List(t *testing.T) {
+func TestService_PublishTags(t *testing.T) {
 	store := newMockStore()
-	store.packages["pkg-a@1.0.0"] = &storage.Package{Name: "pkg-a", Version: "1.0.0"}
-	store.packages["pkg-b@1.0.0"] = &storage.Package{Name: "pkg-b", Version: "1.0.0"}
+	svc := NewService(store, store)
+	ctx := context.Background()
+	publish := func(name, version string, tags ...string) error {
+		return svc.Publish(ctx, name, version, "", PublishRequest{Chain: "evm", Artifacts: []Artifact{{Name: "Token"}}, Tags: tags})
+	}
+
+	require.NoError(t, publish("my-token", "1.0.0", " DeFi", "erc20", "defi"))
+	require.NoError(t, publish("my-token", "1.1.0", "erc20"))
+	require.NoError(t, publish("my-dao", "1.0.0", "governance", "erc20"))
+
+	pkg, err := svc.Get(ctx, "my-token", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"defi", "erc20"}, pkg.Tags, "tags are normalized, sorted and de-duplicated")
+
+	tags, err := svc.ListTags(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []TagCount{{Tag: "defi", Packages: 1}, {Tag: "erc20", Packages: 2}, {Tag: "governance", Packages: 1}}, tags)
+
+	err = publish("my-token", "2.0.0", "de fi")
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	exists, err := store.PackageExists(ctx, "my-token", "2.0.0")
+	require.NoError(t, err)
+	assert.False(t, exists, "nothing should be stored when a tag is invalid")
+}
 
+func TestService_PublishContractMetadata(t *testing.T) {
+	store := newMockStore()
 	svc := NewService(store, store)
+	req := PublishRequest{
+		Chain: "evm",
+		Artifacts: []Artifact{
+			{Name: "Token", Metadata: map[string]string{"audit": "passed"}},
+			{Name: "Vault"},
+		},
+		Metadata: map[string]string{"commit": "abc123"},
+	}
+	require.NoError(t, svc.Publish(context.Background(), "my-package", "1.0.0", "", req))
 
-	result, err := svc.List(context.Background(), ListFilter{}, PaginationParams{Limit: 10})
+	contract, err := svc.GetContract(context.Background(), "my-package", "1.0.0", "Token")
 	require.NoError(t, err)
-	assert.Len(t, result.Packages, 2)
+	assert.Equal(t, map[string]string{"audit": "passed"}, contract.Metadata)
+
+	contract, err = svc.GetContract(context.Background(), "my-package", "1.0.0", "Vault")
+	require.NoError(t, err)
+	assert.Empty(t, contract.Metadata, "package metadata is not copied onto contracts")
 }
 
-func TestService_Delete(t *testing.T) {
+func TestService_PublishDetectsInterfaces(t *testing.T) {
 	store := newMockStore()
-	store.packages["my-package@1.0.0"] = &storage.Package{Name: "my-package", Version: "1.0.0"}
-	store.owners["my-package"] = "owner-123"
+	svc := NewService(store, store)
+	abi := []byte(`[
+		{"type":"function","name":"totalSupply","inputs":[]},
+		{"type":"function","name":"balanceOf","inputs":[{"type":"address"}]},
+		{"type":"function","name":"transfer","inputs":[{"type":"address"},{"type":"uint256"}]},
+		{"type":"function","name":"transferFrom","inputs":[{"type":"address"},{"type":"address"},{"type":"uint256"}]},
+		{"type":"function","name":"approve","inputs":[{"type":"address"},{"type":"uint256"}]},
+		{"type":"function","name":"allowance","inputs":[{"type":"address"},{"type":"address"}]}
+	]`)
+	req := PublishRequest{
+		Chain:     "evm",
+		Artifacts: []Artifact{{Name: "Token", ABI: abi, Labels: []string{"upgradeable"}}},
+	}
+	require.NoError(t, svc.Publish(context.Background(), "my-package", "1.0.0", "", req))
+
+	contract, err := svc.GetContract(context.Background(), "my-package", "1.0.0", "Token")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"erc20", "upgradeable"}, contract.Labels)
+}
 
+func TestService_PublishSolanaProgram(t *testing.T) {
+	store := newMockStore()
 	svc := NewService(store, store)
+	program := []byte("\x7fELFcounter")
+	idl := []byte(`{"address":"Fg6PaFpoGXkYsidMpWTK6W2BeZ7FEfcYkg476zPFsLnS"}`)
 
-	t.Run("owner can delete", func(t *testing.T) {
-		err := svc.Delete(context.Background(), "my-package", "1.0.0", "owner-123")
+	req := PublishRequest{
+		Chain:     "solana",
+		Builder:   "anchor",
+		Artifacts: []Artifact{{Name: "counter", SourcePath: "programs/counter/src/lib.rs", IDL: idl, Program: program}},
+	}
+	require.NoError(t, svc.Publish(context.Background(), "counter", "1.0.0", "", req))
+
+	gotProgram, err := svc.GetArtifact(context.Background(), "counter", "1.0.0", "counter", "program")
+	require.NoError(t, err)
+	assert.Equal(t, program, gotProgram)
+
+	gotIDL, err := svc.GetArtifact(context.Background(), "counter", "1.0.0", "counter", "idl")
+	require.NoError(t, err)
+	assert.JSONEq(t, string(idl), string(gotIDL))
+
+	pkg, err := store.GetPackage(context.Background(), "counter", "1.0.0")
+	require.NoError(t, err)
+	contract, err := store.GetContract(context.Background(), pkg.ID, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, storage.HashContent(program), contract.PrimaryHash)
+}
+
+func TestService_PublishExtraArtifacts(t *testing.T) {
+	t.Run("stores each under its type", func(t *testing.T) {
+		store := newMockStore()
+		svc := NewService(store, store)
+		req := PublishRequest{
+			Chain: "evm",
+			Artifacts: []Artifact{{Name: "Token", Extra: map[string]json.RawMessage{
+				"devdoc":             json.RawMessage(`{"kind":"dev"}`),
+				"method-identifiers": json.RawMessage(`{"transfer(address,uint256)":"a9059cbb"}`),
+			}}},
+		}
+		require.NoError(t, svc.Publish(context.Background(), "my-package", "1.0.0", "", req))
+
+		devdoc, err := svc.GetArtifact(context.Background(), "my-package", "1.0.0", "Token", "devdoc")
 		require.NoError(t, err)
+		assert.JSONEq(t, `{"kind":"dev"}`, string(devdoc))
+
+		ids, err := svc.GetArtifact(context.Background(), "my-package", "1.0.0", "Token", "method-identifiers")
+		require.NoError(t, err)
+		assert.Contains(t, string(ids), "a9059cbb")
 	})
 
-	t.Run("non-owner cannot delete", func(t *testing.T) {
-		store.packages["my-package@2.0.0"] = &storage.Package{Name: "my-package", Version: "2.0.0"}
-		err := svc.Delete(context.Background(), "my-package", "2.0.0", "owner-456")
+	for name, extra := range map[string]map[string]json.RawMessage{
+		"built-in type": {"abi": json.RawMessage(`[]`)},
+		"invalid type":  {"Dev Doc": json.RawMessage(`{}`)},
+		"empty content": {"devdoc": nil},
+		"sources shape": {"sources": json.RawMessage(`["src/Token.sol"]`)},
+		"sources path":  {"sources": json.RawMessage(`{"../../etc/passwd":"x"}`)},
+		"sources dots":  {"sources": json.RawMessage(`{"src/../../../../etc/x":"x"}`)},
+		"sources abs":   {"sources": json.RawMessage(`{"/etc/x":"x"}`)},
+		"sources slash": {"sources": json.RawMessage(`{"src\\..\\..\\x":"x"}`)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := newMockStore()
+			svc := NewService(store, store)
+			req := PublishRequest{Chain: "evm", Artifacts: []Artifact{{Name: "Token", Extra: extra}}}
+			err := svc.Publish(context.Background(), "my-package", "1.0.0", "", req)
+			assert.ErrorIs(t, err, ErrInvalidArtifact)
+		})
+	}
+}
+
+func TestService_PublishSignature(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	abi := json.RawMessage(`[{"type":"function","name":"mint"}]`)
+	sig, err := provenance.Sign(key, abi, []byte("0x6080"))
+	require.NoError(t, err)
+
+	t.Run("stores a valid signature", func(t *testing.T) {
+		store := newMockStore()
+		svc := NewService(store, store)
+		req := PublishRequest{Chain: "evm", Artifacts: []Artifact{{
+			Name: "Token", ABI: abi, Bytecode: "0x6080", Signature: sig.Signature, PublicKey: sig.PublicKey,
+		}}}
+		require.NoError(t, svc.Publish(context.Background(), "my-package", "1.0.0", "", req))
+
+		content, err := svc.GetArtifact(context.Background(), "my-package", "1.0.0", "Token", SignatureArtifactType)
+		require.NoError(t, err)
+		var stored provenance.Signature
+		require.NoError(t, json.Unmarshal(content, &stored))
+		assert.Equal(t, sig.Digest, stored.Digest)
+		assert.NoError(t, provenance.Verify(&stored, abi, []byte("0x6080")))
+	})
+
+	for name, artifact := range map[string]Artifact{
+		"other bytecode":    {Name: "Token", ABI: abi, Bytecode: "0x6081", Signature: sig.Signature, PublicKey: sig.PublicKey},
+		"missing publicKey": {Name: "Token", ABI: abi, Bytecode: "0x6080", Signature: sig.Signature},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := newMockStore()
+			svc := NewService(store, store)
+			err := svc.Publish(context.Background(), "my-package", "1.0.0", "", PublishRequest{Chain: "evm", Artifacts: []Artifact{artifact}})
+			assert.ErrorIs(t, err, ErrInvalidSignature)
+			assert.Empty(t, store.packages, "nothing is stored for a bad signature")
+		})
+	}
+
+	t.Run("signature is not an extra artifact type", func(t *testing.T) {
+		store := newMockStore()
+		svc := NewService(store, store)
+		req := PublishRequest{Chain: "evm", Artifacts: []Artifact{{Name: "Token", Extra: map[string]json.RawMessage{
+			SignatureArtifactType: json.RawMessage(`{"algorithm":"ed25519"}`),
+		}}}}
+		assert.ErrorIs(t, svc.Publish(context.Background(), "my-package", "1.0.0", "", req), ErrInvalidArtifact)
+	})
+}
+
+func TestService_PublishInvalidABI(t *testing.T) {
+	store := newMockStore()
+	svc := NewService(store, store)
+
+	for name, abi := range map[string]string{
+		"not an array":   `{"abi":[]}`,
+		"unnamed":        `[{"type":"function","inputs":[]}]`,
+		"untyped params": `[{"type":"function","name":"f","inputs":[{"name":"x"}]}]`,
+	} {
+		req := PublishRequest{Chain: "evm", Artifacts: []Artifact{{Name: "Token", ABI: json.RawMessage(abi)}}}
+		err := svc.Publish(context.Background(), "my-package", "1.0.0", "", req)
+		assert.ErrorIs(t, err, ErrInvalidArtifact, name)
+	}
+
+	exists, err := store.PackageExists(context.Background(), "my-package", "1.0.0")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestService_PublishQuotas(t *testing.T) {
+	publish := func(svc *service, version string, abi string) error {
+		req := PublishRequest{Chain: "evm", Artifacts: []Artifact{{Name: "Token", ABI: json.RawMessage(abi)}}}
+		return svc.Publish(context.Background(), "my-package", version, "owner-1", req)
+	}
+
+	t.Run("unlimited by default", func(t *testing.T) {
+		store := newMockStore()
+		svc := NewService(store, store)
+		for _, v := range []string{"1.0.0", "1.1.0", "1.2.0"} {
+			require.NoError(t, publish(svc, v, `[{"type":"function","name":"f"}]`))
+		}
+	})
+
+	t.Run("max versions per package", func(t *testing.T) {
+		store := newMockStore()
+		svc := NewService(store, store, WithMaxVersionsPerPackage(2))
+		require.NoError(t, publish(svc, "1.0.0", `[]`))
+		require.NoError(t, publish(svc, "1.1.0", `[]`))
+
+		err := publish(svc, "1.2.0", `[]`)
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+		assert.Contains(t, err.Error(), "2 of 2 allowed versions")
+	})
+
+	t.Run("max artifact bytes per owner", func(t *testing.T) {
+		store := newMockStore()
+		svc := NewService(store, store, WithMaxOwnerArtifactBytes(20))
+		require.NoError(t, publish(svc, "1.0.0", `[{"name":"a"}]`)) // 14 bytes
+
+		err := publish(svc, "1.1.0", `[{"name":"b"}]`)
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+		assert.Contains(t, err.Error(), "14 of 20 bytes, this publish adds 14")
+
+		exists, err := store.PackageExists(context.Background(), "my-package", "1.1.0")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("collaborator publishes count against the package owner", func(t *testing.T) {
+		store := newMockStore()
+		svc := NewService(store, store, WithMaxOwnerArtifactBytes(20))
+		require.NoError(t, publish(svc, "1.0.0", `[{"name":"a"}]`)) // 14 bytes, owned by owner-1
+		store.collaborators["my-package"] = []string{"owner-2"}
+
+		req := PublishRequest{Chain: "evm", Artifacts: []Artifact{{Name: "Token", ABI: json.RawMessage(`[{"name":"b"}]`)}}}
+		err := svc.Publish(context.Background(), "my-package", "1.1.0", "owner-2", req)
+		require.ErrorIs(t, err, ErrQuotaExceeded)
+		assert.Contains(t, err.Error(), "14 of 20 bytes")
+
+		// The collaborator's own quota is untouched by the owner's package
+		err = svc.Publish(context.Background(), "their-package", "1.0.0", "owner-2", req)
+		require.NoError(t, err)
+		assert.Equal(t, "owner-2", store.owners["their-package"])
+	})
+}
+
+func TestService_PublishMaxArtifacts(t *testing.T) {
+	req := PublishRequest{
+		Chain:     "evm",
+		Artifacts: []Artifact{{Name: "A"}, {Name: "B"}, {Name: "C"}},
+	}
+
+	t.Run("over limit", func(t *testing.T) {
+		svc := NewService(newMockStore(), newMockStore(), WithMaxArtifactsPerPublish(2))
+		err := svc.Publish(context.Background(), "my-package", "1.0.0", "", req)
 		require.Error(t, err)
-		assert.ErrorIs(t, err, ErrForbidden)
+		assert.ErrorIs(t, err, ErrTooManyArtifacts)
+		assert.Contains(t, err.Error(), "got 3, max 2")
 	})
+
+	t.Run("at limit", func(t *testing.T) {
+		store := newMockStore()
+		svc := NewService(store, store, WithMaxArtifactsPerPublish(3))
+		require.NoError(t, svc.Publish(context.Background(), "my-package", "1.0.0", "", req))
+	})
+
+	t.Run("zero disables limit", func(t *testing.T) {
+		store := newMockStore()
+		svc := NewService(store, store, WithMaxArtifactsPerPublish(0))
+		require.NoError(t, svc.Publish(context.Background(), "my-package", "1.0.0", "", req))
+	})
+}
+
+func TestService_PublishCompilerPolicy(t *testing.T) {
+	policy := CompilerPolicy{
+		AllowedVersions:   []string{">=0.8.0"},
+		DeniedVersions:    []string{"0.8.13"},
+		DeniedEVMVersions: []string{"homestead"},
+	}
+	publish := func(compiler *CompilerInfo) error {
+		store := newMockStore()
+		svc := NewService(store, store, WithCompilerPolicy(policy))
+		return svc.Publish(context.Background(), "my-package", "1.0.0", "", PublishRequest{
+			Chain:     "evm",
+			Artifacts: []Artifact{{Name: "Token", Compiler: compiler}},
+		})
+	}
+
+	t.Run("allowed version", func(t *testing.T) {
+		require.NoError(t, publish(&CompilerInfo{Version: "0.8.28+commit.7893614a", EVMVersion: "cancun"}))
+	})
+
+	t.Run("version outside allowlist", func(t *testing.T) {
+		err := publish(&CompilerInfo{Version: "0.7.6+commit.7338295f"})
+		require.ErrorIs(t, err, ErrCompilerNotAllowed)
+		assert.Contains(t, err.Error(), "Token")
+		assert.Contains(t, err.Error(), "0.7.6+commit.7338295f")
+	})
+
+	t.Run("denied version", func(t *testing.T) {
+		err := publish(&CompilerInfo{Version: "0.8.13"})
+		require.ErrorIs(t, err, ErrCompilerNotAllowed)
+		assert.Contains(t, err.Error(), "denied")
+	})
+
+	t.Run("denied evm version", func(t *testing.T) {
+		err := publish(&CompilerInfo{Version: "0.8.28", EVMVersion: "Homestead"})
+		require.ErrorIs(t, err, ErrCompilerNotAllowed)
+		assert.Contains(t, err.Error(), "evmVersion Homestead")
+	})
+
+	t.Run("no compiler info", func(t *testing.T) {
+		require.NoError(t, publish(nil))
+	})
+}
+
+func TestCompilerMismatch(t *testing.T) {
+	tests := []struct {
+		published, requested string
+		warn                 bool
+	}{
+		{"0.8.28", "0.8.19", false},
+		{"0.8.28+commit.7893614a", "v0.8.28", false},
+		{"0.8.28", "0.8", false},
+		{"0.8.28", "0.7.6", true},
+		{"0.8.28", "0.9.0", true},
+		{"", "0.8.19", false}, // unknown published compiler
+		{"unknown", "0.8.19", false},
+	}
+	for _, tt := range tests {
+		warning, err := CompilerMismatch(tt.published, tt.requested)
+		require.NoError(t, err)
+		assert.Equal(t, tt.warn, warning != "", "%s vs %s: %q", tt.published, tt.requested, warning)
+	}
+
+	_, err := CompilerMismatch("0.8.28", "latest")
+	assert.ErrorIs(t, err, ErrInvalidCompilerVersion)
+}
+
+func TestService_LookupBytecode(t *testing.T) {
+	store := newMockStore()
+	svc := NewService(store, store)
+	require.NoError(t, svc.Publish(context.Background(), "tokens", "1.0.0", "", PublishRequest{
+		Chain: "evm",
+		Artifacts: []Artifact{
+			{Name: "Token", Bytecode: "0x6080", DeployedBytecode: "0x6001"},
+			{Name: "Vault", Bytecode: "0x6090"},
+		},
+	}))
+
+	t.Run("deployed bytecode", func(t *testing.T) {
+		matches, err := svc.LookupBytecode(context.Background(), storage.HashContent([]byte("0x6001")))
+		require.NoError(t, err)
+		assert.Equal(t, []BytecodeMatch{{Package: "tokens", Version: "1.0.0", Contract: "Token", Chain: "evm", MatchedOn: "deployed-bytecode"}}, matches)
+	})
+
+	t.Run("creation bytecode, bare 0x-prefixed upper-case digest", func(t *testing.T) {
+		digest := strings.TrimPrefix(storage.HashContent([]byte("0x6090")), "sha256:")
+		matches, err := svc.LookupBytecode(context.Background(), "0x"+strings.ToUpper(digest))
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, "Vault", matches[0].Contract)
+		assert.Equal(t, "bytecode", matches[0].MatchedOn)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		matches, err := svc.LookupBytecode(context.Background(), storage.HashContent([]byte("0xdead")))
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("invalid hash", func(t *testing.T) {
+		_, err := svc.LookupBytecode(context.Background(), "abc")
+		assert.ErrorIs(t, err, ErrInvalidHash)
+		_, err = svc.LookupBytecode(context.Background(), strings.Repeat("zz", 32))
+		assert.ErrorIs(t, err, ErrInvalidHash)
+		_, err = svc.LookupBytecode(context.Background(), "keccak256:"+strings.Repeat("ab", 32))
+		assert.ErrorIs(t, err, ErrInvalidHash)
+	})
+}
+
+func TestService_LookupSelector(t *testing.T) {
+	store := newMockStore()
+	svc := NewService(store, store)
+	require.NoError(t, svc.Publish(context.Background(), "tokens", "1.0.0", "", PublishRequest{
+		Chain: "evm",
+		Artifacts: []Artifact{
+			{Name: "Token", Bytecode: "0x6080", ABI: json.RawMessage(`[{"type":"function","name":"transfer","inputs":[{"type":"address"},{"type":"uint256"}]},{"type":"error","name":"Unauthorized","inputs":[]}]`)},
+			{Name: "Vault", Bytecode: "0x6090", ABI: json.RawMessage(`[{"type":"function","name":"deposit","inputs":[{"type":"uint256"}]}]`)},
+		},
+	}))
+
+	t.Run("function", func(t *testing.T) {
+		matches, err := svc.LookupSelector(context.Background(), "0xA9059CBB")
+		require.NoError(t, err)
+		assert.Equal(t, []SelectorMatch{{Package: "tokens", Version: "1.0.0", Contract: "Token", Chain: "evm", Type: "function", Signature: "transfer(address,uint256)"}}, matches)
+	})
+
+	t.Run("error, without 0x", func(t *testing.T) {
+		matches, err := svc.LookupSelector(context.Background(), "82b42900")
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, "Unauthorized()", matches[0].Signature)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		matches, err := svc.LookupSelector(context.Background(), "0xdeadbeef")
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("invalid selector", func(t *testing.T) {
+		for _, sel := range []string{"", "0x1234", "0xzzzzzzzz", "transfer(address,uint256)"} {
+			_, err := svc.LookupSelector(context.Background(), sel)
+			assert.ErrorIs(t, err, ErrInvalidSelector, sel)
+		}
+	})
+}
+
+func TestService_Get(t *testing.T) {
+	store := newMockStore()
+	store.packages["my-package@1.0.0"] = &storage.Package{
+		ID:      "pkg-123",
+		Name:    "my-package",
+		Version: "1.0.0",
+		Chain:   "evm",
+	}
+
+	svc := NewService(store, store)
+
+	t.Run("existing package", func(t *testing.T) {
+		pkg, err := svc.Get(context.Background(), "my-package", "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, "my-package", pkg.Name)
+		assert.Equal(t, "1.0.0", pkg.Version)
+	})
+
+	t.Run("non-existing package", func(t *testing.T) {
+		_, err := svc.Get(context.Background(), "not-found", "1.0.0")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("latest version", func(t *testing.T) {
+		store.packages["my-package@2.0.0"] = &storage.Package{
+			ID:      "pkg-456",
+			Name:    "my-package",
+			Version: "2.0.0",
+			Chain:   "evm",
+		}
+		pkg, err := svc.Get(context.Background(), "my-package", "latest")
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", pkg.Version)
+	})
+}
+
+func TestService_GetVersions(t *testing.T) {
+	store := newMockStore()
+	store.packages["my-package@1.0.0"] = &storage.Package{Name: "my-package", Version: "1.0.0"}
+	store.packages["my-package@2.0.0"] = &storage.Package{Name: "my-package", Version: "2.0.0"}
+
+	svc := NewService(store, store)
+
+	t.Run("existing package", func(t *testing.T) {
+		result, err := svc.GetVersions(context.Background(), "my-package", VersionsOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "my-package", result.Name)
+		assert.Len(t, result.Versions, 2)
+		assert.Nil(t, result.Deployments, "deployments are opt-in")
+	})
+
+	t.Run("with deployments", func(t *testing.T) {
+		store.deploymentCounts = []storage.VersionDeploymentCount{
+			{Version: "1.0.0", Deployments: 3, Verified: 1},
+		}
+		result, err := svc.GetVersions(context.Background(), "my-package", VersionsOptions{WithDeployments: true})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]VersionDeployments{
+			"1.0.0": {Count: 3, Verified: 1},
+			"2.0.0": {},
+		}, result.Deployments)
+	})
+
+	t.Run("detailed", func(t *testing.T) {
+		store.packages["my-package@1.0.0"].Builder = "foundry"
+		store.packages["my-package@1.0.0"].CompilerVersion = "0.8.20"
+		store.packages["my-package@1.0.0"].CreatedAt = "2024-01-01 10:00:00"
+		store.packages["my-package@2.0.0"].Builder = "standard-json"
+		store.packages["my-package@2.0.0"].CompilerVersion = "0.8.28"
+		store.packages["my-package@2.0.0"].CreatedAt = "2024-06-01 10:00:00"
+
+		result, err := svc.GetVersions(context.Background(), "my-package", VersionsOptions{Detailed: true})
+		require.NoError(t, err)
+		assert.Equal(t, []VersionDetail{
+			{Version: "2.0.0", Builder: "standard-json", CompilerVersion: "0.8.28", CreatedAt: time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)},
+			{Version: "1.0.0", Builder: "foundry", CompilerVersion: "0.8.20", CreatedAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+		}, result.Details)
+	})
+
+	t.Run("paginated", func(t *testing.T) {
+		store.packages["my-package@3.0.0"] = &storage.Package{Name: "my-package", Version: "3.0.0", CreatedAt: "2024-09-01 10:00:00"}
+		defer delete(store.packages, "my-package@3.0.0")
+
+		all, err := svc.GetVersions(context.Background(), "my-package", VersionsOptions{})
+		require.NoError(t, err)
+		require.Len(t, all.Versions, 3)
+
+		first, err := svc.GetVersions(context.Background(), "my-package", VersionsOptions{Limit: 2, WithDeployments: true})
+		require.NoError(t, err)
+		assert.Equal(t, all.Versions[:2], first.Versions)
+		assert.True(t, first.HasMore)
+		assert.Equal(t, all.Versions[1], first.NextCursor)
+		assert.Len(t, first.Deployments, 2, "deployments follow the page")
+
+		second, err := svc.GetVersions(context.Background(), "my-package", VersionsOptions{Limit: 2, Cursor: first.NextCursor})
+		require.NoError(t, err)
+		assert.Equal(t, all.Versions[2:], second.Versions)
+		assert.False(t, second.HasMore)
+
+		_, err = svc.GetVersions(context.Background(), "my-package", VersionsOptions{Limit: 2, Cursor: "9.9.9"})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("non-existing package", func(t *testing.T) {
+		_, err := svc.GetVersions(context.Background(), "not-found", VersionsOptions{})
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("version list store error", func(t *testing.T) {
+		dbErr := errors.New("connection reset")
+		store.listVersionErr = dbErr
+		defer func() { store.listVersionErr = nil }()
+
+		result, err := svc.GetVersions(context.Background(), "my-package", VersionsOptions{})
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, dbErr)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("latest version lookup store error", func(t *testing.T) {
+		dbErr := errors.New("connection reset")
+		store.getPackageErr = dbErr
+		defer func() { store.getPackageErr = nil }()
+
+		result, err := svc.GetVersions(context.Background(), "my-package", VersionsOptions{})
+		require.Error(t, err)
+		assert.Nil(t, result, "a failed lookup must not be reported as a successful result")
+		assert.ErrorIs(t, err, dbErr)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("latest version deleted concurrently", func(t *testing.T) {
+		store.getPackageErr = storage.ErrNotFound
+		defer func() { store.getPackageErr = nil }()
+
+		result, err := svc.GetVersions(context.Background(), "my-package", VersionsOptions{})
+		require.NoError(t, err)
+		assert.Len(t, result.Versions, 2)
+		assert.Empty(t, result.Chain)
+	})
+}
+
+func TestService_GetVersions_Prereleases(t *testing.T) {
+	store := newMockStore()
+	store.packages["my-package@1.0.0"] = &storage.Package{Name: "my-package", Version: "1.0.0", Chain: "evm"}
+	store.packages["my-package@2.0.0-beta.1"] = &storage.Package{Name: "my-package", Version: "2.0.0-beta.1", Chain: "evm"}
+	store.packages["my-package@1.1.0+build.7"] = &storage.Package{Name: "my-package", Version: "1.1.0+build.7", Chain: "evm"}
+	store.packages["next@3.0.0-rc.1"] = &storage.Package{Name: "next", Version: "3.0.0-rc.1", Chain: "evm"}
+
+	svc := NewService(store, store)
+
+	t.Run("hidden by default", func(t *testing.T) {
+		result, err := svc.GetVersions(context.Background(), "my-package", VersionsOptions{})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"1.0.0", "1.1.0+build.7"}, result.Versions)
+	})
+
+	t.Run("details follow the listed versions", func(t *testing.T) {
+		result, err := svc.GetVersions(context.Background(), "my-package", VersionsOptions{Detailed: true})
+		require.NoError(t, err)
+		var versions []string
+		for _, d := range result.Details {
+			versions = append(versions, d.Version)
+		}
+		assert.ElementsMatch(t, []string{"1.0.0", "1.1.0+build.7"}, versions)
+	})
+
+	t.Run("included on request", func(t *testing.T) {
+		result, err := svc.GetVersions(context.Background(), "my-package", VersionsOptions{IncludePrerelease: true})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"1.0.0", "1.1.0+build.7", "2.0.0-beta.1"}, result.Versions)
+	})
+
+	t.Run("package with only prereleases", func(t *testing.T) {
+		result, err := svc.GetVersions(context.Background(), "next", VersionsOptions{})
+		require.NoError(t, err, "the package exists even when its versions are hidden")
+		assert.Empty(t, result.Versions)
+		assert.NotNil(t, result.Versions)
+		assert.Equal(t, "evm", result.Chain)
+	})
+
+	t.Run("latest of a package with only prereleases", func(t *testing.T) {
+		pkg, err := svc.Get(context.Background(), "next", "latest")
+		require.NoError(t, err)
+		assert.Equal(t, "3.0.0-rc.1", pkg.Version)
+	})
+}
+
+func TestService_List(t *testing.T) {
+	store := newMockStore()
+	store.packages["pkg-a@1.0.0"] = &storage.Package{Name: "pkg-a", Version: "1.0.0"}
+	store.packages["pkg-b@1.0.0"] = &storage.Package{Name: "pkg-b", Version: "1.0.0"}
+
+	svc := NewService(store, store)
+
+	result, err := svc.List(context.Background(), ListFilter{}, PaginationParams{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, result.Packages, 2)
+	assert.Nil(t, result.Total, "counting is opt-in")
+
+	result, err = svc.List(context.Background(), ListFilter{Count: true}, PaginationParams{Limit: 1})
+	require.NoError(t, err)
+	require.NotNil(t, result.Total)
+	assert.Equal(t, 2, *result.Total)
+}
+
+func TestService_ListOwned(t *testing.T) {
+	store := newMockStore()
+	store.packages["pkg-a@1.0.0"] = &storage.Package{Name: "pkg-a", Version: "1.0.0"}
+	store.packages["pkg-b@1.0.0"] = &storage.Package{Name: "pkg-b", Version: "1.0.0"}
+	store.owners["pkg-a"] = "key-1"
+	store.owners["pkg-b"] = "key-2"
+
+	svc := NewService(store, store)
+
+	result, err := svc.ListOwned(context.Background(), "key-1", PaginationParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Packages, 1)
+	assert.Equal(t, "pkg-a", result.Packages[0].Name)
+
+	_, err = svc.ListOwned(context.Background(), "", PaginationParams{Limit: 10})
+	assert.ErrorIs(t, err, ErrForbidden, "anonymous callers own nothing")
+}
+
+func TestService_Delete(t *testing.T) {
+	store := newMockStore()
+	store.packages["my-package@1.0.0"] = &storage.Package{Name: "my-package", Version: "1.0.0"}
+	store.owners["my-package"] = "owner-123"
+
+	svc := NewService(store, store)
+
+	t.Run("owner can delete", func(t *testing.T) {
+		err := svc.Delete(context.Background(), "my-package", "1.0.0", "owner-123")
+		require.NoError(t, err)
+	})
+
+	t.Run("non-owner cannot delete", func(t *testing.T) {
+		store.packages["my-package@2.0.0"] = &storage.Package{Name: "my-package", Version: "2.0.0"}
+		err := svc.Delete(context.Background(), "my-package", "2.0.0", "owner-456")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("missing version", func(t *testing.T) {
+		err := svc.Delete(context.Background(), "my-package", "9.9.9", "owner-123")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestService_GetOwner(t *testing.T) {
+	store := newMockStore()
+	store.owners["my-package"] = "owner-123"
+	svc := NewService(store, store)
+
+	t.Run("owner sees ownership", func(t *testing.T) {
+		owner, err := svc.GetOwner(context.Background(), "my-package", "owner-123", false)
+		require.NoError(t, err)
+		assert.Equal(t, "key-owner-123", owner.Name)
+		assert.Equal(t, time.Date(2025, 6, 15, 14, 30, 45, 0, time.UTC), owner.Since)
+	})
+
+	t.Run("admin sees ownership", func(t *testing.T) {
+		owner, err := svc.GetOwner(context.Background(), "my-package", "admin-1", true)
+		require.NoError(t, err)
+		assert.Equal(t, "owner-123", owner.KeyID)
+	})
+
+	t.Run("others are forbidden", func(t *testing.T) {
+		_, err := svc.GetOwner(context.Background(), "my-package", "owner-456", false)
+		assert.ErrorIs(t, err, ErrForbidden)
+		_, err = svc.GetOwner(context.Background(), "my-package", "", false)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("unowned package", func(t *testing.T) {
+		_, err := svc.GetOwner(context.Background(), "other", "owner-123", true)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestService_TransferOwner(t *testing.T) {
+	store := newMockStore()
+	store.owners["my-package"] = "owner-123"
+	svc := NewService(store, store)
+	ctx := context.Background()
+
+	t.Run("non-owner cannot transfer", func(t *testing.T) {
+		_, err := svc.TransferOwner(ctx, "my-package", "owner-456", "owner-456")
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("target must be an active key", func(t *testing.T) {
+		_, err := svc.TransferOwner(ctx, "my-package", "owner-123", "revoked-key")
+		assert.ErrorIs(t, err, ErrInvalidOwner)
+		_, err = svc.TransferOwner(ctx, "my-package", "owner-123", "owner-123")
+		assert.ErrorIs(t, err, ErrInvalidOwner)
+	})
+
+	t.Run("unowned package", func(t *testing.T) {
+		_, err := svc.TransferOwner(ctx, "other", "owner-123", "owner-456")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("owner transfers", func(t *testing.T) {
+		owner, err := svc.TransferOwner(ctx, "my-package", "owner-123", "owner-456")
+		require.NoError(t, err)
+		assert.Equal(t, "owner-456", owner.KeyID)
+		assert.Equal(t, "owner-456", store.owners["my-package"])
+	})
+}
+
+func TestService_Collaborators(t *testing.T) {
+	store := newMockStore()
+	store.owners["my-package"] = "owner-123"
+	svc := NewService(store, store)
+	ctx := context.Background()
+	req := PublishRequest{Chain: "evm", Artifacts: []Artifact{{Name: "Token", Bytecode: "0x1234"}}}
+
+	t.Run("only the owner adds collaborators", func(t *testing.T) {
+		_, err := svc.AddCollaborator(ctx, "my-package", "owner-456", "owner-456")
+		assert.ErrorIs(t, err, ErrForbidden)
+		_, err = svc.AddCollaborator(ctx, "other", "owner-123", "owner-456")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("collaborator must be an active key other than the owner", func(t *testing.T) {
+		_, err := svc.AddCollaborator(ctx, "my-package", "owner-123", "revoked-key")
+		assert.ErrorIs(t, err, ErrInvalidCollaborator)
+		_, err = svc.AddCollaborator(ctx, "my-package", "owner-123", "owner-123")
+		assert.ErrorIs(t, err, ErrInvalidCollaborator)
+	})
+
+	t.Run("collaborators publish and delete", func(t *testing.T) {
+		require.ErrorIs(t, svc.Publish(ctx, "my-package", "1.0.0", "owner-456", req), ErrForbidden)
+
+		collaborators, err := svc.AddCollaborator(ctx, "my-package", "owner-123", "owner-456")
+		require.NoError(t, err)
+		require.Len(t, collaborators, 1)
+		assert.Equal(t, "key-owner-456", collaborators[0].Name)
+		assert.Equal(t, time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC), collaborators[0].Since)
+
+		require.NoError(t, svc.Publish(ctx, "my-package", "1.0.0", "owner-456", req))
+		require.NoError(t, svc.Delete(ctx, "my-package", "1.0.0", "owner-456"))
+		assert.ErrorIs(t, svc.Publish(ctx, "my-package", "1.0.0", "owner-789", req), ErrForbidden)
+		assert.Equal(t, "owner-123", store.owners["my-package"], "collaborators don't take ownership")
+	})
+
+	t.Run("owner, collaborators and admins see the list", func(t *testing.T) {
+		for _, caller := range []struct {
+			id    string
+			admin bool
+		}{{"owner-123", false}, {"owner-456", false}, {"admin-1", true}} {
+			collaborators, err := svc.ListCollaborators(ctx, "my-package", caller.id, caller.admin)
+			require.NoError(t, err, caller.id)
+			assert.Len(t, collaborators, 1)
+		}
+		_, err := svc.ListCollaborators(ctx, "my-package", "owner-789", false)
+		assert.ErrorIs(t, err, ErrForbidden)
+		_, err = svc.ListCollaborators(ctx, "my-package", "", false)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+
+	t.Run("owner removes collaborators", func(t *testing.T) {
+		_, err := svc.RemoveCollaborator(ctx, "my-package", "owner-456", "owner-456")
+		assert.ErrorIs(t, err, ErrForbidden)
+
+		collaborators, err := svc.RemoveCollaborator(ctx, "my-package", "owner-123", "owner-456")
+		require.NoError(t, err)
+		assert.Empty(t, collaborators)
+		assert.ErrorIs(t, svc.Publish(ctx, "my-package", "1.1.0", "owner-456", req), ErrForbidden)
+
+		_, err = svc.RemoveCollaborator(ctx, "my-package", "owner-123", "owner-456")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestService_Aliases(t *testing.T) {
+	store := newMockStore()
+	store.owners["my-package"] = "owner-123"
+	for _, v := range []string{"1.0.0", "2.0.0"} {
+		store.packages["my-package@"+v] = &storage.Package{ID: "pkg-" + v, Name: "my-package", Version: v, Chain: "evm"}
+	}
+	store.contracts["pkg-1.0.0/Token"] = &storage.Contract{ID: "c1", PackageID: "pkg-1.0.0", Name: "Token"}
+	store.artifacts["c1/abi"] = []byte(`[]`)
+	svc := NewService(store, store)
+	ctx := context.Background()
+
+	t.Run("only the owner sets aliases", func(t *testing.T) {
+		_, err := svc.SetAlias(ctx, "my-package", "stable", "1.0.0", "owner-456")
+		assert.ErrorIs(t, err, ErrForbidden)
+		_, err = svc.SetAlias(ctx, "other", "stable", "1.0.0", "owner-123")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("alias and target are validated", func(t *testing.T) {
+		_, err := svc.SetAlias(ctx, "my-package", "latest", "1.0.0", "owner-123")
+		assert.ErrorIs(t, err, ErrInvalidAlias)
+		_, err = svc.SetAlias(ctx, "my-package", "stable", "^1.0.0", "owner-123")
+		assert.ErrorIs(t, err, ErrInvalidVersion)
+		_, err = svc.SetAlias(ctx, "my-package", "stable", "3.0.0", "owner-123")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("reads resolve aliases", func(t *testing.T) {
+		alias, err := svc.SetAlias(ctx, "my-package", "stable", "1.0.0", "owner-123")
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", alias.Version)
+
+		pkg, err := svc.Get(ctx, "my-package", "stable")
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", pkg.Version)
+
+		abi, err := svc.GetArtifact(ctx, "my-package", "stable", "Token", "abi")
+		require.NoError(t, err)
+		assert.Equal(t, `[]`, string(abi))
+
+		// Moving the alias moves reads with it
+		_, err = svc.SetAlias(ctx, "my-package", "stable", "2.0.0", "owner-123")
+		require.NoError(t, err)
+		pkg, err = svc.Get(ctx, "my-package", "stable")
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", pkg.Version)
+
+		_, err = svc.Get(ctx, "my-package", "unknown")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("list and delete", func(t *testing.T) {
+		aliases, err := svc.ListAliases(ctx, "my-package")
+		require.NoError(t, err)
+		require.Len(t, aliases, 1)
+		assert.Equal(t, Alias{Name: "stable", Version: "2.0.0", UpdatedAt: time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)}, aliases[0])
+
+		assert.ErrorIs(t, svc.DeleteAlias(ctx, "my-package", "stable", "owner-456"), ErrForbidden)
+		require.NoError(t, svc.DeleteAlias(ctx, "my-package", "stable", "owner-123"))
+		assert.ErrorIs(t, svc.DeleteAlias(ctx, "my-package", "stable", "owner-123"), ErrNotFound)
+	})
+}
+
+func TestService_GetArchiveReproducible(t *testing.T) {
+	store := newMockStore()
+	svc := NewService(store, store)
+	req := PublishRequest{
+		Chain:   "evm",
+		Builder: "foundry",
+		Artifacts: []Artifact{
+			{Name: "Token", ABI: []byte(`[]`), Bytecode: "0x6001"},
+			{Name: "Vault", ABI: []byte(`[]`), Bytecode: "0x6002"},
+			{Name: "Router", ABI: []byte(`[]`), Bytecode: "0x6003"},
+		},
+	}
+	require.NoError(t, svc.Publish(context.Background(), "my-package", "1.0.0", "", req))
+	store.packages["my-package@1.0.0"].CreatedAt = "2025-06-15 14:30:45"
+
+	first, err := svc.GetArchive(context.Background(), "my-package", "1.0.0", ArchiveTarGz)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		again, err := svc.GetArchive(context.Background(), "my-package", "1.0.0", ArchiveTarGz)
+		require.NoError(t, err)
+		require.Equal(t, first, again, "archives of the same version must be byte-identical")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(first))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), hdr.ModTime.Unix())
+		names = append(names, hdr.Name)
+		if hdr.Name == "my-package-1.0.0/manifest.json" {
+			manifest, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			assert.Contains(t, string(manifest), `"createdAt": "2025-06-15T14:30:45Z"`)
+		}
+	}
+	assert.Equal(t, []string{
+		"my-package-1.0.0/manifest.json",
+		"my-package-1.0.0/Router/abi.json",
+		"my-package-1.0.0/Router/bytecode.hex",
+		"my-package-1.0.0/Token/abi.json",
+		"my-package-1.0.0/Token/bytecode.hex",
+		"my-package-1.0.0/Vault/abi.json",
+		"my-package-1.0.0/Vault/bytecode.hex",
+	}, names)
+}
+
+func TestService_GetArchiveZip(t *testing.T) {
+	store := newMockStore()
+	svc := NewService(store, store)
+	req := PublishRequest{
+		Chain:     "evm",
+		Builder:   "foundry",
+		Artifacts: []Artifact{{Name: "Token", ABI: []byte(`[]`), Bytecode: "0x6001"}},
+	}
+	require.NoError(t, svc.Publish(context.Background(), "my-package", "1.0.0", "", req))
+
+	first, err := svc.GetArchive(context.Background(), "my-package", "1.0.0", ArchiveZip)
+	require.NoError(t, err)
+	again, err := svc.GetArchive(context.Background(), "my-package", "1.0.0", ArchiveZip)
+	require.NoError(t, err)
+	require.Equal(t, first, again, "zip archives must be reproducible too")
+
+	zr, err := zip.NewReader(bytes.NewReader(first), int64(len(first)))
+	require.NoError(t, err)
+	files := make(map[string]string)
+	var names []string
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		names = append(names, f.Name)
+		files[f.Name] = string(content)
+	}
+	assert.Equal(t, []string{
+		"my-package-1.0.0/manifest.json",
+		"my-package-1.0.0/Token/abi.json",
+		"my-package-1.0.0/Token/bytecode.hex",
+	}, names, "same layout as the tar.gz archive")
+	assert.Equal(t, "0x6001", files["my-package-1.0.0/Token/bytecode.hex"])
+	assert.Contains(t, files["my-package-1.0.0/manifest.json"], `"name": "my-package"`)
+}
+
+func TestParseArchiveFormat(t *testing.T) {
+	for in, want := range map[string]ArchiveFormat{"": ArchiveTarGz, "tar.gz": ArchiveTarGz, "tgz": ArchiveTarGz, "ZIP": ArchiveZip} {
+		got, err := ParseArchiveFormat(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := ParseArchiveFormat("rar")
+	assert.ErrorIs(t, err, ErrInvalidArchiveFormat)
+}
+
+func TestService_GetArchiveSources(t *testing.T) {
+	store := newMockStore()
+	svc := NewService(store, store)
+	req := PublishRequest{
+		Chain: "evm",
+		Artifacts: []Artifact{{Name: "Token", ABI: []byte(`[]`), Extra: map[string]json.RawMessage{
+			"sources": json.RawMessage(`{"src/Token.sol":"contract Token {}","lib/oz/ERC20.sol":"contract ERC20 {}"}`),
+		}}},
+	}
+	require.NoError(t, svc.Publish(context.Background(), "my-package", "1.0.0", "", req))
+
+	archive, err := svc.GetArchive(context.Background(), "my-package", "1.0.0", ArchiveTarGz)
+	require.NoError(t, err)
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	files := make(map[string]string)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+		files[hdr.Name] = string(content)
+	}
+	assert.Equal(t, []string{
+		"my-package-1.0.0/manifest.json",
+		"my-package-1.0.0/Token/abi.json",
+		"my-package-1.0.0/Token/sources/lib/oz/ERC20.sol",
+		"my-package-1.0.0/Token/sources/src/Token.sol",
+	}, names)
+	assert.Equal(t, "contract Token {}", files["my-package-1.0.0/Token/sources/src/Token.sol"])
+}
+
+func TestService_GetArchiveSkipsTraversalPaths(t *testing.T) {
+	store := newMockStore()
+	svc := NewService(store, store)
+	req := PublishRequest{Chain: "evm", Artifacts: []Artifact{{Name: "Token", ABI: []byte(`[]`)}}}
+	require.NoError(t, svc.Publish(context.Background(), "my-package", "1.0.0", "", req))
+
+	// Stored before publish validation rejected such paths
+	for key := range store.artifacts {
+		contractID, _, _ := strings.Cut(key, "/")
+		store.artifacts[contractID+"/"+SourcesArtifactType] = []byte(`{"../../../../etc/x":"x","/etc/y":"y","src/Token.sol":"contract Token {}"}`)
+	}
+
+	archive, err := svc.GetArchive(context.Background(), "my-package", "1.0.0", ArchiveTarGz)
+	require.NoError(t, err)
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+	}
+	assert.Equal(t, []string{
+		"my-package-1.0.0/manifest.json",
+		"my-package-1.0.0/Token/abi.json",
+		"my-package-1.0.0/Token/sources/src/Token.sol",
+	}, names)
 }
 
 func TestService_GetArtifact(t *testing.T) {
@@ -4,6 +4,8 @@ package domain
 import (
 	"encoding/json"
 	"time"
+
+	"github.com/pendergraft/contrafactory/internal/validation"
 )
 
 // Package represents a published package version.
@@ -17,6 +19,7 @@ type Package struct {
 	CompilerVersion  string
 	CompilerSettings map[string]any
 	Metadata         map[string]string
+	Tags             []string
 	OwnerID          string
 	CreatedAt        time.Time
 	Versions         []string // Used for list aggregation
@@ -33,6 +36,8 @@ type Contract struct {
 	License           string
 	PrimaryHash       string
 	MetadataHash      string
+	Labels            []string
+	Metadata          map[string]string // Contract-level metadata (e.g. audit status)
 	CreatedAt         time.Time
 	CompilationTarget map[string]string // For verification: {sourcePath: contractName}
 	CompilerVersion   string
@@ -52,6 +57,26 @@ type Artifact struct {
 	StandardJSONInput json.RawMessage `json:"standardJsonInput,omitempty"`
 	StorageLayout     json.RawMessage `json:"storageLayout,omitempty"`
 	Compiler          *CompilerInfo   `json:"compiler,omitempty"`
+
+	// Solana-specific fields
+	IDL     json.RawMessage `json:"idl,omitempty"`     // Anchor IDL
+	Program []byte          `json:"program,omitempty"` // Program .so binary (base64 in JSON)
+
+	// Labels tag the contract for discovery (e.g. erc20, upgradeable)
+	Labels []string `json:"labels,omitempty"`
+
+	// Metadata is free-form contract-level metadata (e.g. audit=passed), alongside
+	// the package-level metadata of the publish request
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Signature is the publisher's base64 ed25519 signature over the ABI (or IDL) and
+	// bytecode (or program), made with the key PublicKey; see internal/provenance
+	Signature string `json:"signature,omitempty"`
+	PublicKey string `json:"publicKey,omitempty"`
+
+	// Extra holds additional named artifacts (e.g. devdoc, userdoc, method-identifiers,
+	// ast), each stored under its key and served from /artifacts/{type}
+	Extra map[string]json.RawMessage `json:"extra,omitempty"`
 }
 
 // CompilerInfo contains compiler settings.
@@ -75,6 +100,71 @@ type PublishRequest struct {
 	Project   string            `json:"project,omitempty"`
 	Artifacts []Artifact        `json:"artifacts"`
 	Metadata  map[string]string `json:"metadata,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+}
+
+// Validate checks the request has a chain and at least one artifact, each with a
+// name. The error is a validation.FieldErrors listing every problem.
+func (r PublishRequest) Validate() error {
+	names := make([]string, len(r.Artifacts))
+	for i, a := range r.Artifacts {
+		names[i] = a.Name
+	}
+	if errs := validation.ValidatePublishRequest(r.Chain, names); errs != nil {
+		return errs
+	}
+	return nil
+}
+
+// BatchItem is one package version in a batch publish.
+type BatchItem struct {
+	Name    string
+	Version string
+	Request PublishRequest
+}
+
+// Statuses of a BatchItemResult
+const (
+	BatchPublished  = "published"
+	BatchFailed     = "failed"
+	BatchRolledBack = "rolled-back" // passed its checks, but not stored because a later item failed
+	BatchSkipped    = "skipped"     // not attempted because an earlier item failed
+)
+
+// BatchItemResult is the outcome of one item of a batch publish.
+type BatchItemResult struct {
+	Name    string
+	Version string
+	Status  string
+	Err     error // set when Status is BatchFailed
+}
+
+// Owner identifies the API key that owns a package name.
+type Owner struct {
+	Name  string // the key's name, e.g. "ci-release"
+	KeyID string
+	Since time.Time // when the name was first published
+}
+
+// Collaborator is an API key that may publish to a package name besides its owner.
+type Collaborator struct {
+	Name  string // the key's name, e.g. "ci-staging"
+	KeyID string
+	Since time.Time // when the key was added
+}
+
+// Alias is a named pointer, such as "stable", that the package owner moves between
+// versions. Reads resolve it to the version it points at.
+type Alias struct {
+	Name      string
+	Version   string
+	UpdatedAt time.Time // when the alias was last moved
+}
+
+// TagCount is a package tag and how many packages have a version carrying it.
+type TagCount struct {
+	Tag      string
+	Packages int
 }
 
 // ListFilter contains filter options for listing packages.
@@ -86,13 +176,42 @@ type ListFilter struct {
 	Project  string
 	Version  string
 	Contract string
+	Label    string
+	Tag      string
 	Latest   bool
+	// Metadata only returns versions whose package metadata has all these key/value pairs
+	Metadata map[string]string
+	// CreatedAfter, when non-zero, only returns packages created at or after it
+	CreatedAfter time.Time
+	// Count also counts all matching packages into ListResult.Total (an extra query)
+	Count bool
+}
+
+// BytecodeMatch is a published contract matching a bytecode lookup.
+type BytecodeMatch struct {
+	Package   string
+	Version   string
+	Contract  string
+	Chain     string
+	MatchedOn string // "bytecode", "deployed-bytecode" or "program"
+}
+
+// SelectorMatch is a published contract matching a selector lookup.
+type SelectorMatch struct {
+	Package   string
+	Version   string
+	Contract  string
+	Chain     string
+	Type      string // "function", "error" or "event"
+	Signature string // e.g. transfer(address,uint256)
 }
 
-// PaginationParams contains pagination options.
+// PaginationParams contains pagination options. Cursor pages forward and
+// Before pages backward; at most one is set.
 type PaginationParams struct {
 	Limit  int
 	Cursor string
+	Before string
 }
 
 // ListResult contains paginated list results.
@@ -101,6 +220,19 @@ type ListResult struct {
 	HasMore    bool
 	NextCursor string
 	PrevCursor string
+	Total      *int // matching packages across all pages; only set with ListFilter.Count
+}
+
+// VersionsOptions controls what GetVersions returns.
+type VersionsOptions struct {
+	IncludePrerelease bool
+	WithDeployments   bool // annotate each version with its deployment counts
+	Detailed          bool // describe how each version was built
+
+	// Limit caps the versions returned, 0 returns them all. Cursor is the
+	// NextCursor of the previous page.
+	Limit  int
+	Cursor string
 }
 
 // VersionsResult contains version list results.
@@ -108,5 +240,31 @@ type VersionsResult struct {
 	Name     string
 	Chain    string
 	Builder  string
-	Versions []string
+	Versions []string // newest first
+
+	// HasMore reports whether versions follow this page; NextCursor fetches them.
+	HasMore    bool
+	NextCursor string
+
+	// Details has an entry per version, newest first; only set when requested via
+	// VersionsOptions.
+	Details []VersionDetail
+
+	// Deployments is keyed by version; only set when requested via VersionsOptions.
+	Deployments map[string]VersionDeployments
+}
+
+// VersionDetail describes how one version of a package was built.
+type VersionDetail struct {
+	Version         string
+	Chain           string
+	Builder         string
+	CompilerVersion string
+	CreatedAt       time.Time
+}
+
+// VersionDeployments summarizes the deployments recorded against one version.
+type VersionDeployments struct {
+	Count    int
+	Verified int
 }
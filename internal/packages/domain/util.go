@@ -3,8 +3,17 @@ package domain
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/google/uuid"
+
+	"github.com/pendergraft/contrafactory/internal/provenance"
+	"github.com/pendergraft/contrafactory/internal/storage"
+	"github.com/pendergraft/contrafactory/internal/validation"
 )
 
 // generateID generates a new UUID.
@@ -12,8 +21,196 @@ func generateID() string {
 	return uuid.New().String()
 }
 
-// computeHash computes a SHA256 hash of content.
-func computeHash(content []byte) string {
-	hash := sha256.Sum256(content)
-	return hex.EncodeToString(hash[:])
+// NormalizeHash returns a content hash in the stored "sha256:<hex>" form (see
+// storage.HashContent). A bare or 0x-prefixed hex digest is taken to be sha256.
+func NormalizeHash(hash string) (string, error) {
+	if algorithm, digest, ok := strings.Cut(hash, ":"); ok {
+		if algorithm != storage.HashAlgorithm {
+			return "", fmt.Errorf("%w: unsupported algorithm %q (hashes are %s)", ErrInvalidHash, algorithm, storage.HashAlgorithm)
+		}
+		hash = digest
+	}
+	hash = strings.ToLower(strings.TrimPrefix(hash, "0x"))
+	if len(hash) != sha256.Size*2 {
+		return "", fmt.Errorf("%w: expected %d hex characters", ErrInvalidHash, sha256.Size*2)
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidHash, err)
+	}
+	return storage.HashAlgorithm + ":" + hash, nil
+}
+
+// NormalizeSelector returns a selector in the indexed form: lowercase 0x-prefixed hex
+// of 4 bytes (functions and errors) or 32 bytes (event topics).
+func NormalizeSelector(selector string) (string, error) {
+	digits := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(selector, "0x"), "0X"))
+	if len(digits) != 8 && len(digits) != 64 {
+		return "", fmt.Errorf("%w: expected 4 bytes (function or error) or 32 bytes (event) of hex", ErrInvalidSelector)
+	}
+	if _, err := hex.DecodeString(digits); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidSelector, err)
+	}
+	return "0x" + digits, nil
+}
+
+// BuiltinArtifactTypes are the artifact types with dedicated publish fields.
+var BuiltinArtifactTypes = []string{
+	"abi", "bytecode", "deployed-bytecode", "standard-json-input", "storage-layout", "idl", "program",
+	SignatureArtifactType,
+}
+
+// validateExtraArtifacts checks that extra artifact types are well-formed, don't
+// shadow a built-in type and have content.
+func validateExtraArtifacts(extra map[string]json.RawMessage) error {
+	for artifactType, content := range extra {
+		if err := validation.ValidateArtifactType(artifactType); err != nil {
+			return fmt.Errorf("%q: %w", artifactType, err)
+		}
+		if slices.Contains(BuiltinArtifactTypes, artifactType) {
+			return fmt.Errorf("%q is a built-in artifact type", artifactType)
+		}
+		if len(content) == 0 {
+			return fmt.Errorf("%q is empty", artifactType)
+		}
+		if artifactType == SourcesArtifactType {
+			if _, err := parseSources(content); err != nil {
+				return fmt.Errorf("%q: %w", artifactType, err)
+			}
+		}
+	}
+	return nil
+}
+
+// SignatureArtifactType is the artifact holding a contract's verified publisher
+// signature, as a provenance.Signature.
+const SignatureArtifactType = "signature"
+
+// signatureInputs returns what an artifact's signature covers: its ABI and creation
+// bytecode, or for Solana programs its IDL and program binary.
+func signatureInputs(a Artifact) (abi, code []byte) {
+	if len(a.Program) > 0 {
+		return a.IDL, a.Program
+	}
+	return a.ABI, []byte(a.Bytecode)
+}
+
+// verifySignature checks an artifact's publisher signature, returning the record to
+// store, or nil when the artifact isn't signed.
+func verifySignature(a Artifact) (*provenance.Signature, error) {
+	if a.Signature == "" && a.PublicKey == "" {
+		return nil, nil
+	}
+	if a.Signature == "" || a.PublicKey == "" {
+		return nil, fmt.Errorf("signature and publicKey must be given together")
+	}
+	abi, code := signatureInputs(a)
+	digest, err := provenance.Digest(abi, code)
+	if err != nil {
+		return nil, err
+	}
+	sig := &provenance.Signature{Algorithm: provenance.Algorithm, PublicKey: a.PublicKey, Signature: a.Signature, Digest: digest}
+	if err := provenance.Verify(sig, abi, code); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// SourcesArtifactType is the extra artifact holding a contract's source files as a
+// {"path": "content"} object, published with `publish --include-sources`.
+const SourcesArtifactType = "sources"
+
+// parseSources decodes a sources artifact, rejecting paths that would escape the
+// sources directory when the archive is extracted.
+func parseSources(content []byte) (map[string]string, error) {
+	sources, err := decodeSources(content)
+	if err != nil {
+		return nil, err
+	}
+	for p := range sources {
+		if err := validation.ValidateSourcePath(p); err != nil {
+			return nil, fmt.Errorf("invalid source path %q: %w", p, err)
+		}
+	}
+	return sources, nil
+}
+
+// decodeSources decodes a sources artifact without checking its paths.
+func decodeSources(content []byte) (map[string]string, error) {
+	var sources map[string]string
+	if err := json.Unmarshal(content, &sources); err != nil {
+		return nil, fmt.Errorf("must be an object of source paths to contents: %w", err)
+	}
+	return sources, nil
+}
+
+// publishSize returns the number of artifact bytes a publish will store.
+func publishSize(req PublishRequest) int64 {
+	var size int
+	for _, a := range req.Artifacts {
+		size += len(a.ABI) + len(a.Bytecode) + len(a.DeployedBytecode) + len(a.StandardJSONInput) +
+			len(a.StorageLayout) + len(a.IDL) + len(a.Program)
+		for _, content := range a.Extra {
+			size += len(content)
+		}
+	}
+	return int64(size)
+}
+
+// normalizeLabels lowercases, validates, sorts and de-duplicates contract labels.
+func normalizeLabels(labels []string) ([]string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]bool, len(labels))
+	result := make([]string, 0, len(labels))
+	for _, l := range labels {
+		label := validation.NormalizeLabel(l)
+		if err := validation.ValidateLabel(label); err != nil {
+			return nil, fmt.Errorf("%q: %w", l, err)
+		}
+		if !seen[label] {
+			seen[label] = true
+			result = append(result, label)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// normalizeTags lowercases, validates, sorts and de-duplicates package tags.
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		tag := validation.NormalizeTag(t)
+		if err := validation.ValidateTag(tag); err != nil {
+			return nil, fmt.Errorf("%q: %w", t, err)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			result = append(result, tag)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// pageVersions returns the versions following cursor, the last version of the
+// previous page, capped at limit (0 for no cap). hasMore reports whether versions
+// were left out.
+func pageVersions(versions []string, limit int, cursor string) (page []string, hasMore bool, err error) {
+	if cursor != "" {
+		i := slices.Index(versions, cursor)
+		if i < 0 {
+			return nil, false, fmt.Errorf("%w: %q is not a listed version", ErrInvalidCursor, cursor)
+		}
+		versions = versions[i+1:]
+	}
+	if limit > 0 && len(versions) > limit {
+		return versions[:limit], true, nil
+	}
+	return versions, false, nil
 }
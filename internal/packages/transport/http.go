@@ -3,31 +3,56 @@ package transport
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"github.com/pendergraft/contrafactory/internal/auth"
+	"github.com/pendergraft/contrafactory/internal/base58"
+	"github.com/pendergraft/contrafactory/internal/jsonnum"
+	"github.com/pendergraft/contrafactory/internal/middleware/requestid"
 	"github.com/pendergraft/contrafactory/internal/packages/domain"
+	"github.com/pendergraft/contrafactory/internal/pagination"
+	"github.com/pendergraft/contrafactory/internal/server/errcodes"
+	"github.com/pendergraft/contrafactory/internal/storage"
+	"github.com/pendergraft/contrafactory/internal/validation"
+	"github.com/pendergraft/contrafactory/internal/verification/etherscan"
+	"github.com/pendergraft/contrafactory/pkg/evmutil"
 )
 
 // Service defines the package service interface for HTTP transport.
 type Service interface {
 	Publish(ctx context.Context, name, version string, ownerID string, req domain.PublishRequest) error
+	PublishBatch(ctx context.Context, ownerID string, items []domain.BatchItem, atomic bool) ([]domain.BatchItemResult, error)
 	Get(ctx context.Context, name, version string) (*domain.Package, error)
-	GetVersions(ctx context.Context, name string, includePrerelease bool) (*domain.VersionsResult, error)
+	GetVersions(ctx context.Context, name string, opts domain.VersionsOptions) (*domain.VersionsResult, error)
 	List(ctx context.Context, filter domain.ListFilter, pagination domain.PaginationParams) (*domain.ListResult, error)
+	ListOwned(ctx context.Context, ownerID string, pagination domain.PaginationParams) (*domain.ListResult, error)
 	Delete(ctx context.Context, name, version string, ownerID string) error
+	GetOwner(ctx context.Context, name, callerID string, callerIsAdmin bool) (*domain.Owner, error)
+	TransferOwner(ctx context.Context, name, callerID, toKeyID string) (*domain.Owner, error)
+	ListCollaborators(ctx context.Context, name, callerID string, callerIsAdmin bool) ([]domain.Collaborator, error)
+	AddCollaborator(ctx context.Context, name, callerID, keyID string) ([]domain.Collaborator, error)
+	RemoveCollaborator(ctx context.Context, name, callerID, keyID string) ([]domain.Collaborator, error)
+	ListAliases(ctx context.Context, name string) ([]domain.Alias, error)
+	SetAlias(ctx context.Context, name, alias, version, callerID string) (*domain.Alias, error)
+	DeleteAlias(ctx context.Context, name, alias, callerID string) error
 	GetContracts(ctx context.Context, name, version string) ([]domain.Contract, error)
 	GetContract(ctx context.Context, name, version, contractName string) (*domain.Contract, error)
 	GetArtifact(ctx context.Context, name, version, contractName, artifactType string) ([]byte, error)
-	GetArchive(ctx context.Context, name, version string) ([]byte, error)
+	GetArchive(ctx context.Context, name, version string, format domain.ArchiveFormat) ([]byte, error)
+	LookupBytecode(ctx context.Context, hash string) ([]domain.BytecodeMatch, error)
+	LookupSelector(ctx context.Context, selector string) ([]domain.SelectorMatch, error)
+	ListTags(ctx context.Context) ([]domain.TagCount, error)
 }
 
 // DeploymentLister is an interface for listing deployments by package
@@ -37,17 +62,19 @@ type DeploymentLister interface {
 
 // DeploymentSummary is a summary of a deployment
 type DeploymentSummary struct {
-	ChainID      string `json:"chainId"`
-	Address      string `json:"address"`
-	ContractName string `json:"contractName"`
-	Verified     bool   `json:"verified"`
-	TxHash       string `json:"txHash,omitempty"`
+	ChainID      string        `json:"chainId"`
+	Address      string        `json:"address"`
+	ContractName string        `json:"contractName"`
+	Verified     bool          `json:"verified"`
+	TxHash       string        `json:"txHash,omitempty"`
+	BlockNumber  jsonnum.Int64 `json:"blockNumber"`
 }
 
 // Handler handles HTTP requests for packages.
 type Handler struct {
 	svc         Service
 	deployments DeploymentLister
+	maxPageSize int // 0 = pagination.DefaultMaxLimit
 }
 
 // NewHandler creates a new packages HTTP handler.
@@ -60,6 +87,11 @@ func (h *Handler) SetDeploymentLister(dl DeploymentLister) {
 	h.deployments = dl
 }
 
+// SetMaxPageSize sets the largest limit the list endpoints accept
+func (h *Handler) SetMaxPageSize(n int) {
+	h.maxPageSize = n
+}
+
 // RegisterRoutes registers all package routes on a chi router.
 // Deprecated: Use RegisterReadRoutes and RegisterWriteRoutes for proper auth separation.
 func (h *Handler) RegisterRoutes(r chi.Router) {
@@ -72,6 +104,7 @@ func (h *Handler) RegisterReadRoutes(r chi.Router) {
 	r.Get("/", h.handleList)
 	r.Get("/{name}", h.handleGetVersions)
 	r.Get("/{name}/{version}", h.handleGet)
+	r.Get("/{name}/aliases", h.handleListAliases)
 
 	// Archive route
 	r.Get("/{name}/{version}/archive", h.handleGetArchive)
@@ -87,48 +120,143 @@ func (h *Handler) RegisterReadRoutes(r chi.Router) {
 	r.Get("/{name}/{version}/contracts/{contract}/deployed-bytecode", h.handleGetDeployedBytecode)
 	r.Get("/{name}/{version}/contracts/{contract}/standard-json-input", h.handleGetStandardJSON)
 	r.Get("/{name}/{version}/contracts/{contract}/storage-layout", h.handleGetStorageLayout)
+	r.Get("/{name}/{version}/contracts/{contract}/idl", h.handleGetIDL)
+	r.Get("/{name}/{version}/contracts/{contract}/program", h.handleGetProgram)
+	r.Get("/{name}/{version}/contracts/{contract}/signature", h.handleGetSignature)
+	r.Get("/{name}/{version}/contracts/{contract}/artifacts/{type}", h.handleGetArtifactByType)
+	r.Get("/{name}/{version}/contracts/{contract}/verify-payload", h.handleGetVerifyPayload)
+	r.Get("/{name}/{version}/contracts/{contract}/bytecode-metadata", h.handleGetBytecodeMetadata)
+}
+
+// RegisterLookupRoutes registers reverse-lookup routes (no auth required). They
+// live outside /packages, so mount them on the API root.
+func (h *Handler) RegisterLookupRoutes(r chi.Router) {
+	r.Get("/lookup/bytecode", h.handleLookupBytecode)
+	r.Get("/lookup/selector/{sig}", h.handleLookupSelector)
+}
+
+// RegisterTagRoutes registers the package tag listing (no auth required). Like the
+// lookup routes it lives outside /packages, so mount it on the API root.
+func (h *Handler) RegisterTagRoutes(r chi.Router) {
+	r.Get("/tags", h.handleListTags)
+}
+
+// RegisterBatchRoutes registers the batch publish route (auth required). Like the
+// lookup routes it lives outside /packages, so mount it on the API root.
+func (h *Handler) RegisterBatchRoutes(r chi.Router) {
+	r.Post("/publish-batch", h.handlePublishBatch)
+}
+
+// RegisterAccountRoutes registers routes about the caller's own packages (auth
+// required). They live under /me, so mount them on the API root.
+func (h *Handler) RegisterAccountRoutes(r chi.Router) {
+	r.Get("/me/packages", h.handleListOwned)
 }
 
 // RegisterWriteRoutes registers write package routes (auth required).
+// Ownership lookups live here too since they depend on the caller's key.
 func (h *Handler) RegisterWriteRoutes(r chi.Router) {
 	r.Post("/{name}/{version}", h.handlePublish)
 	r.Delete("/{name}/{version}", h.handleDelete)
+	r.Get("/{name}/owner", h.handleGetOwner)
+	r.Post("/{name}/transfer-owner", h.handleTransferOwner)
+	r.Get("/{name}/collaborators", h.handleListCollaborators)
+	r.Post("/{name}/collaborators", h.handleAddCollaborator)
+	r.Delete("/{name}/collaborators/{keyId}", h.handleRemoveCollaborator)
+	r.Post("/{name}/aliases/{alias}", h.handleSetAlias)
+	r.Delete("/{name}/aliases/{alias}", h.handleDeleteAlias)
 }
 
-func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
-	limit := 20
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
-			limit = parsed
+// parseMetadataFilters collects metadata.<key>=<value> query parameters.
+func parseMetadataFilters(query url.Values) (map[string]string, error) {
+	var filters map[string]string
+	for param, values := range query {
+		key, ok := strings.CutPrefix(param, "metadata.")
+		if !ok {
+			continue
+		}
+		if key == "" {
+			return nil, errors.New("metadata filters must name a key: metadata.<key>=<value>")
+		}
+		if len(values) > 1 {
+			return nil, fmt.Errorf("metadata.%s given more than once", key)
 		}
+		if filters == nil {
+			filters = make(map[string]string)
+		}
+		filters[key] = values[0]
+	}
+	return filters, nil
+}
+
+// parsePagination reads limit and cursor, capping limit at the handler's max page size
+func (h *Handler) parsePagination(r *http.Request) (pagination.Params, error) {
+	return pagination.Parse(r, h.maxPageSize)
+}
+
+func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
+	page, err := h.parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+		return
 	}
 
 	project := r.URL.Query().Get("project")
 	version := r.URL.Query().Get("version")
 	contract := r.URL.Query().Get("contract")
+	label := r.URL.Query().Get("label")
+	tag := r.URL.Query().Get("tag")
 	latest := r.URL.Query().Get("latest") == "true"
 
 	// latest requires project
 	if latest && project == "" {
-		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "latest parameter requires project parameter")
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "latest parameter requires project parameter")
+		return
+	}
+
+	before := r.URL.Query().Get("before")
+	if page.Cursor != "" && before != "" {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "cursor and before cannot be combined")
+		return
+	}
+
+	var createdAfter time.Time
+	if v := r.URL.Query().Get("created_after"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "created_after must be an RFC3339 timestamp")
+			return
+		}
+		createdAfter = t
+	}
+
+	metadata, err := parseMetadataFilters(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
 		return
 	}
 
 	result, err := h.svc.List(r.Context(), domain.ListFilter{
-		Query:    r.URL.Query().Get("q"),
-		Chain:    r.URL.Query().Get("chain"),
-		Sort:     r.URL.Query().Get("sort"),
-		Order:    r.URL.Query().Get("order"),
-		Project:  project,
-		Version:  version,
-		Contract: contract,
-		Latest:   latest,
+		Query:        r.URL.Query().Get("q"),
+		Chain:        r.URL.Query().Get("chain"),
+		Sort:         r.URL.Query().Get("sort"),
+		Order:        r.URL.Query().Get("order"),
+		Project:      project,
+		Version:      version,
+		Contract:     contract,
+		Label:        label,
+		Tag:          tag,
+		Latest:       latest,
+		Metadata:     metadata,
+		CreatedAfter: createdAfter,
+		Count:        r.URL.Query().Get("count") == "true",
 	}, domain.PaginationParams{
-		Limit:  limit,
-		Cursor: r.URL.Query().Get("cursor"),
+		Limit:  page.Limit,
+		Cursor: page.Cursor,
+		Before: before,
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to list packages")
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to list packages")
 		return
 	}
 
@@ -149,33 +277,132 @@ func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, ListResponse{
 		Data: data,
 		Pagination: Pagination{
-			Limit:      limit,
+			Limit:      page.Limit,
 			HasMore:    result.HasMore,
 			NextCursor: result.NextCursor,
+			PrevCursor: result.PrevCursor,
+			Total:      result.Total,
+		},
+	})
+}
+
+// handleListOwned lists the packages owned by the calling API key.
+func (h *Handler) handleListOwned(w http.ResponseWriter, r *http.Request) {
+	ownerID := auth.GetOwnerIDFromContext(r.Context())
+	if ownerID == "" {
+		writeError(w, http.StatusUnauthorized, errcodes.Unauthorized, "An API key is required to list your packages")
+		return
+	}
+
+	page, err := h.parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+		return
+	}
+	before := r.URL.Query().Get("before")
+	if page.Cursor != "" && before != "" {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "cursor and before cannot be combined")
+		return
+	}
+
+	result, err := h.svc.ListOwned(r.Context(), ownerID, domain.PaginationParams{
+		Limit:  page.Limit,
+		Cursor: page.Cursor,
+		Before: before,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to list packages")
+		return
+	}
+
+	data := make([]PackageItem, len(result.Packages))
+	for i, p := range result.Packages {
+		data[i] = PackageItem{
+			Name:     p.Name,
+			Chain:    p.Chain,
+			Builder:  p.Builder,
+			Versions: p.Versions,
+		}
+	}
+
+	writeJSON(w, http.StatusOK, ListResponse{
+		Data: data,
+		Pagination: Pagination{
+			Limit:      page.Limit,
+			HasMore:    result.HasMore,
+			NextCursor: result.NextCursor,
+			PrevCursor: result.PrevCursor,
 		},
 	})
 }
 
 func (h *Handler) handleGetVersions(w http.ResponseWriter, r *http.Request) {
+	page, err := h.parsePagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+		return
+	}
+
 	name := chi.URLParam(r, "name")
-	includePrerelease := r.URL.Query().Get("include_prerelease") == "true"
+	opts := domain.VersionsOptions{
+		IncludePrerelease: r.URL.Query().Get("include_prerelease") == "true",
+		WithDeployments:   r.URL.Query().Get("with_deployments") == "true",
+		Detailed:          r.URL.Query().Get("detailed") == "true",
+		Limit:             page.Limit,
+		Cursor:            page.Cursor,
+	}
 
-	result, err := h.svc.GetVersions(r.Context(), name, includePrerelease)
+	result, err := h.svc.GetVersions(r.Context(), name, opts)
 	if err != nil {
-		if errors.Is(err, domain.ErrNotFound) {
-			writeError(w, http.StatusNotFound, "NOT_FOUND", "Package not found")
-			return
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package not found")
+		case errors.Is(err, domain.ErrInvalidCursor):
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get package")
 		}
-		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get package")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, VersionsResponse{
+	resp := VersionsResponse{
 		Name:     result.Name,
 		Chain:    result.Chain,
 		Builder:  result.Builder,
 		Versions: result.Versions,
-	})
+		Pagination: Pagination{
+			Limit:      page.Limit,
+			HasMore:    result.HasMore,
+			NextCursor: result.NextCursor,
+		},
+	}
+	if result.Deployments != nil {
+		resp.Deployments = make(map[string]VersionDeploymentsResponse, len(result.Deployments))
+		for v, d := range result.Deployments {
+			resp.Deployments[v] = VersionDeploymentsResponse{
+				Count:       d.Count,
+				Verified:    d.Verified,
+				AnyVerified: d.Verified > 0,
+			}
+		}
+	}
+
+	if opts.Detailed {
+		details := make([]VersionDetailResponse, len(result.Details))
+		for i, d := range result.Details {
+			details[i] = VersionDetailResponse{
+				Version:         d.Version,
+				Chain:           d.Chain,
+				Builder:         d.Builder,
+				CompilerVersion: d.CompilerVersion,
+				CreatedAt:       d.CreatedAt.Format(time.RFC3339),
+			}
+		}
+		writeJSON(w, http.StatusOK, DetailedVersionsResponse{VersionsResponse: resp, Versions: details})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
 }
 
 func (h *Handler) handleGet(w http.ResponseWriter, r *http.Request) {
@@ -185,16 +412,29 @@ func (h *Handler) handleGet(w http.ResponseWriter, r *http.Request) {
 	pkg, err := h.svc.Get(r.Context(), name, version)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			writeError(w, http.StatusNotFound, "NOT_FOUND", "Package version not found")
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package version not found")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get package")
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get package")
 		return
 	}
 
+	// The consumer's compiler, checked against the one the package was built with
+	var warnings []string
+	if solc := r.URL.Query().Get("solc"); solc != "" {
+		warning, err := domain.CompilerMismatch(pkg.CompilerVersion, solc)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errcodes.InvalidVersion, "Invalid solc version: "+solc)
+			return
+		}
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+
 	contracts, err := h.svc.GetContracts(r.Context(), name, version)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to list contracts")
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to list contracts")
 		return
 	}
 
@@ -206,11 +446,14 @@ func (h *Handler) handleGet(w http.ResponseWriter, r *http.Request) {
 	response := PackageResponse{
 		Name:            pkg.Name,
 		Version:         pkg.Version,
+		Project:         pkg.Project,
 		Chain:           pkg.Chain,
 		Builder:         pkg.Builder,
 		CompilerVersion: pkg.CompilerVersion,
 		Contracts:       contractNames,
 		CreatedAt:       pkg.CreatedAt.Format(time.RFC3339),
+		Tags:            pkg.Tags,
+		Warnings:        warnings,
 	}
 	if len(pkg.Metadata) > 0 {
 		metadata := make(map[string]any)
@@ -232,31 +475,27 @@ func (h *Handler) handlePublish(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Failed to read request body")
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Failed to read request body")
 		return
 	}
 
 	var req PublishRequest
 	if err := json.Unmarshal(body, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid JSON")
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Invalid JSON")
+		return
+	}
+
+	publishReq := req.ToDomain()
+	if err := publishReq.Validate(); err != nil {
+		writeValidationError(w, err)
 		return
 	}
 
 	ownerID := auth.GetOwnerIDFromContext(r.Context())
 
-	if err := h.svc.Publish(r.Context(), name, version, ownerID, req.ToDomain()); err != nil {
-		switch {
-		case errors.Is(err, domain.ErrInvalidName):
-			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
-		case errors.Is(err, domain.ErrInvalidVersion):
-			writeError(w, http.StatusBadRequest, "INVALID_VERSION", err.Error())
-		case errors.Is(err, domain.ErrVersionExists):
-			writeError(w, http.StatusConflict, "VERSION_EXISTS", "Version already exists and is immutable")
-		case errors.Is(err, domain.ErrForbidden):
-			writeError(w, http.StatusForbidden, "FORBIDDEN", "Package owned by another user")
-		default:
-			writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to publish package")
-		}
+	if err := h.svc.Publish(r.Context(), name, version, ownerID, publishReq); err != nil {
+		status, code, message := publishError(err)
+		writeError(w, status, code, message)
 		return
 	}
 
@@ -267,6 +506,92 @@ func (h *Handler) handlePublish(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// publishError maps a Publish error to its HTTP status, error code and message.
+func publishError(err error) (int, string, string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidName):
+		return http.StatusBadRequest, errcodes.InvalidRequest, err.Error()
+	case errors.Is(err, domain.ErrInvalidVersion):
+		return http.StatusBadRequest, errcodes.InvalidVersion, err.Error()
+	case errors.Is(err, domain.ErrVersionExists):
+		return http.StatusConflict, errcodes.VersionExists, "Version already exists and is immutable"
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, errcodes.Forbidden, "Package owned by another user"
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return http.StatusForbidden, errcodes.QuotaExceeded, err.Error()
+	case errors.Is(err, domain.ErrTooManyArtifacts):
+		return http.StatusRequestEntityTooLarge, errcodes.TooManyArtifacts, err.Error()
+	case errors.Is(err, domain.ErrInvalidLabel):
+		return http.StatusBadRequest, errcodes.InvalidLabel, err.Error()
+	case errors.Is(err, domain.ErrInvalidTag):
+		return http.StatusBadRequest, errcodes.InvalidRequest, err.Error()
+	case errors.Is(err, domain.ErrInvalidArtifact):
+		return http.StatusBadRequest, errcodes.InvalidRequest, err.Error()
+	case errors.Is(err, domain.ErrCompilerNotAllowed):
+		return http.StatusBadRequest, errcodes.InvalidRequest, err.Error()
+	case errors.Is(err, domain.ErrInvalidSignature):
+		return http.StatusBadRequest, errcodes.InvalidSignature, err.Error()
+	default:
+		return http.StatusInternalServerError, errcodes.InternalError, "Failed to publish package"
+	}
+}
+
+// handlePublishBatch publishes several package versions in one request. By default the
+// batch is atomic; ?mode=best-effort attempts every item. Responds 201 when every item
+// was published and 200 otherwise, with each item's status in the body.
+func (h *Handler) handlePublishBatch(w http.ResponseWriter, r *http.Request) {
+	atomic := true
+	switch r.URL.Query().Get("mode") {
+	case "", "atomic":
+	case "best-effort":
+		atomic = false
+	default:
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "mode must be atomic or best-effort")
+		return
+	}
+
+	var items []PublishBatchItem
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Invalid JSON")
+		return
+	}
+
+	batch := make([]domain.BatchItem, len(items))
+	for i, item := range items {
+		batch[i] = domain.BatchItem{Name: item.Name, Version: item.Version, Request: item.Request.ToDomain()}
+	}
+
+	ownerID := auth.GetOwnerIDFromContext(r.Context())
+	results, err := h.svc.PublishBatch(r.Context(), ownerID, batch, atomic)
+	if results == nil {
+		if errors.Is(err, domain.ErrInvalidBatch) {
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to publish batch")
+		return
+	}
+
+	resp := PublishBatchResponse{Atomic: atomic, Results: make([]PublishBatchResult, len(results))}
+	for i, res := range results {
+		resp.Results[i] = PublishBatchResult{Name: res.Name, Version: res.Version, Status: res.Status}
+		switch res.Status {
+		case domain.BatchPublished:
+			resp.Published++
+		case domain.BatchFailed:
+			resp.Failed++
+			_, code, message := publishError(res.Err)
+			resp.Results[i].Error = &ErrorDetail{Code: code, Message: message}
+		}
+	}
+
+	status := http.StatusCreated
+	if resp.Published < len(results) {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, resp)
+}
+
 func (h *Handler) handleDelete(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	version := chi.URLParam(r, "version")
@@ -275,38 +600,362 @@ func (h *Handler) handleDelete(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.svc.Delete(r.Context(), name, version, ownerID); err != nil {
 		if errors.Is(err, domain.ErrForbidden) {
-			writeError(w, http.StatusForbidden, "FORBIDDEN", "Package owned by another user")
+			writeError(w, http.StatusForbidden, errcodes.Forbidden, "Package owned by another user")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to delete package")
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package version not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to delete package")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) handleGetOwner(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var callerID string
+	var callerIsAdmin bool
+	if key := auth.GetAPIKeyFromContext(r.Context()); key != nil {
+		callerID = key.ID
+		callerIsAdmin = key.IsAdmin()
+	}
+
+	owner, err := h.svc.GetOwner(r.Context(), name, callerID, callerIsAdmin)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package has no owner")
+		case errors.Is(err, domain.ErrForbidden):
+			writeError(w, http.StatusForbidden, errcodes.Forbidden, "Only the package owner or an admin can see ownership")
+		default:
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get package owner")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toOwnerResponse(name, owner))
+}
+
+func (h *Handler) handleTransferOwner(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var req TransferOwnerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Invalid JSON")
+		return
+	}
+	if req.ToKeyID == "" {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "toKeyId is required")
+		return
+	}
+
+	ownerID := auth.GetOwnerIDFromContext(r.Context())
+
+	owner, err := h.svc.TransferOwner(r.Context(), name, ownerID, req.ToKeyID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package has no owner")
+		case errors.Is(err, domain.ErrForbidden):
+			writeError(w, http.StatusForbidden, errcodes.Forbidden, "Only the package owner can transfer ownership")
+		case errors.Is(err, domain.ErrInvalidOwner):
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to transfer ownership")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toOwnerResponse(name, owner))
+}
+
+func (h *Handler) handleListCollaborators(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var callerID string
+	var callerIsAdmin bool
+	if key := auth.GetAPIKeyFromContext(r.Context()); key != nil {
+		callerID = key.ID
+		callerIsAdmin = key.IsAdmin()
+	}
+
+	collaborators, err := h.svc.ListCollaborators(r.Context(), name, callerID, callerIsAdmin)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package has no owner")
+		case errors.Is(err, domain.ErrForbidden):
+			writeError(w, http.StatusForbidden, errcodes.Forbidden, "Only the package owner, its collaborators or an admin can see collaborators")
+		default:
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to list collaborators")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toCollaboratorsResponse(name, collaborators))
+}
+
+func (h *Handler) handleAddCollaborator(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var req AddCollaboratorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Invalid JSON")
+		return
+	}
+	if req.KeyID == "" {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "keyId is required")
+		return
+	}
+
+	ownerID := auth.GetOwnerIDFromContext(r.Context())
+
+	collaborators, err := h.svc.AddCollaborator(r.Context(), name, ownerID, req.KeyID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package has no owner")
+		case errors.Is(err, domain.ErrForbidden):
+			writeError(w, http.StatusForbidden, errcodes.Forbidden, "Only the package owner can add collaborators")
+		case errors.Is(err, domain.ErrInvalidCollaborator):
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to add collaborator")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toCollaboratorsResponse(name, collaborators))
+}
+
+func (h *Handler) handleRemoveCollaborator(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	keyID := chi.URLParam(r, "keyId")
+
+	ownerID := auth.GetOwnerIDFromContext(r.Context())
+
+	collaborators, err := h.svc.RemoveCollaborator(r.Context(), name, ownerID, keyID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package has no owner or the key is not a collaborator")
+		case errors.Is(err, domain.ErrForbidden):
+			writeError(w, http.StatusForbidden, errcodes.Forbidden, "Only the package owner can remove collaborators")
+		default:
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to remove collaborator")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toCollaboratorsResponse(name, collaborators))
+}
+
+func (h *Handler) handleListAliases(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	aliases, err := h.svc.ListAliases(r.Context(), name)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to list aliases")
+		return
+	}
+
+	resp := AliasesResponse{Name: name, Aliases: make([]AliasResponse, len(aliases))}
+	for i, a := range aliases {
+		resp.Aliases[i] = toAliasResponse(a)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) handleSetAlias(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	alias := chi.URLParam(r, "alias")
+
+	var req SetAliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Invalid JSON")
+		return
+	}
+	if req.Version == "" {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "version is required")
+		return
+	}
+
+	ownerID := auth.GetOwnerIDFromContext(r.Context())
+
+	a, err := h.svc.SetAlias(r.Context(), name, alias, req.Version, ownerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidAlias):
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+		case errors.Is(err, domain.ErrInvalidVersion):
+			writeError(w, http.StatusBadRequest, errcodes.InvalidVersion, err.Error())
+		case errors.Is(err, domain.ErrForbidden):
+			writeError(w, http.StatusForbidden, errcodes.Forbidden, "Only the package owner can set aliases")
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package version not found")
+		default:
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to set alias")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAliasResponse(*a))
+}
+
+func (h *Handler) handleDeleteAlias(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	alias := chi.URLParam(r, "alias")
+
+	ownerID := auth.GetOwnerIDFromContext(r.Context())
+
+	if err := h.svc.DeleteAlias(r.Context(), name, alias, ownerID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrForbidden):
+			writeError(w, http.StatusForbidden, errcodes.Forbidden, "Only the package owner can remove aliases")
+		case errors.Is(err, domain.ErrNotFound):
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Alias not found")
+		default:
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to remove alias")
+		}
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func toAliasResponse(a domain.Alias) AliasResponse {
+	resp := AliasResponse{Alias: a.Name, Version: a.Version}
+	if !a.UpdatedAt.IsZero() {
+		resp.UpdatedAt = a.UpdatedAt.Format(time.RFC3339)
+	}
+	return resp
+}
+
+func toCollaboratorsResponse(name string, collaborators []domain.Collaborator) CollaboratorsResponse {
+	resp := CollaboratorsResponse{Name: name, Collaborators: make([]CollaboratorResponse, len(collaborators))}
+	for i, c := range collaborators {
+		resp.Collaborators[i] = CollaboratorResponse{KeyID: c.KeyID, Name: c.Name}
+		if !c.Since.IsZero() {
+			resp.Collaborators[i].Since = c.Since.Format(time.RFC3339)
+		}
+	}
+	return resp
+}
+
+func toOwnerResponse(name string, owner *domain.Owner) OwnerResponse {
+	resp := OwnerResponse{Name: name, Owner: owner.Name}
+	if !owner.Since.IsZero() {
+		resp.Since = owner.Since.Format(time.RFC3339)
+	}
+	return resp
+}
+
 func (h *Handler) handleGetArchive(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	version := chi.URLParam(r, "version")
 
-	content, err := h.svc.GetArchive(r.Context(), name, version)
+	format, err := domain.ParseArchiveFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "format must be tar.gz or zip")
+		return
+	}
+
+	content, err := h.svc.GetArchive(r.Context(), name, version, format)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			writeError(w, http.StatusNotFound, "NOT_FOUND", "Package version not found")
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package version not found")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to generate archive")
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to generate archive")
 		return
 	}
 
-	filename := fmt.Sprintf("%s-%s.tar.gz", name, version)
-	w.Header().Set("Content-Type", "application/gzip")
+	filename := fmt.Sprintf("%s-%s.%s", name, version, format.Extension())
+	w.Header().Set("Content-Type", format.ContentType())
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
 	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
 	w.WriteHeader(http.StatusOK)
 	w.Write(content)
 }
 
+func (h *Handler) handleLookupBytecode(w http.ResponseWriter, r *http.Request) {
+	hash := r.URL.Query().Get("hash")
+	if hash == "" {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "hash parameter is required")
+		return
+	}
+
+	matches, err := h.svc.LookupBytecode(r.Context(), hash)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidHash) {
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to look up bytecode")
+		return
+	}
+
+	// Report the hash in its stored, algorithm-prefixed form
+	if normalized, err := domain.NormalizeHash(hash); err == nil {
+		hash = normalized
+	}
+	resp := BytecodeLookupResponse{Hash: hash, Matches: make([]BytecodeMatch, len(matches))}
+	for i, m := range matches {
+		resp.Matches[i] = BytecodeMatch{
+			Package:   m.Package,
+			Version:   m.Version,
+			Contract:  m.Contract,
+			Chain:     m.Chain,
+			MatchedOn: m.MatchedOn,
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) handleLookupSelector(w http.ResponseWriter, r *http.Request) {
+	matches, err := h.svc.LookupSelector(r.Context(), chi.URLParam(r, "sig"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidSelector) {
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to look up selector")
+		return
+	}
+
+	selector, _ := domain.NormalizeSelector(chi.URLParam(r, "sig"))
+	resp := SelectorLookupResponse{Selector: selector, Matches: make([]SelectorMatch, len(matches))}
+	for i, m := range matches {
+		resp.Matches[i] = SelectorMatch{
+			Package:   m.Package,
+			Version:   m.Version,
+			Contract:  m.Contract,
+			Chain:     m.Chain,
+			Type:      m.Type,
+			Signature: m.Signature,
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) handleListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.svc.ListTags(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to list tags")
+		return
+	}
+
+	resp := TagsResponse{Tags: make([]TagCount, len(tags))}
+	for i, t := range tags {
+		resp.Tags[i] = TagCount{Tag: t.Tag, Packages: t.Packages}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
 func (h *Handler) handleGetVersionDeployments(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	version := chi.URLParam(r, "version")
@@ -315,10 +964,10 @@ func (h *Handler) handleGetVersionDeployments(w http.ResponseWriter, r *http.Req
 	_, err := h.svc.Get(r.Context(), name, version)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			writeError(w, http.StatusNotFound, "NOT_FOUND", "Package version not found")
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package version not found")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get package")
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get package")
 		return
 	}
 
@@ -330,10 +979,15 @@ func (h *Handler) handleGetVersionDeployments(w http.ResponseWriter, r *http.Req
 
 	deployments, err := h.deployments.ListByPackage(r.Context(), name, version)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to list deployments")
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to list deployments")
 		return
 	}
 
+	asString := jsonnum.StringMode(r)
+	for i := range deployments {
+		deployments[i].BlockNumber.AsString = asString
+	}
+
 	writeJSON(w, http.StatusOK, DeploymentsResponse{Deployments: deployments})
 }
 
@@ -344,10 +998,10 @@ func (h *Handler) handleListContracts(w http.ResponseWriter, r *http.Request) {
 	contracts, err := h.svc.GetContracts(r.Context(), name, version)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			writeError(w, http.StatusNotFound, "NOT_FOUND", "Package not found")
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package not found")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to list contracts")
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to list contracts")
 		return
 	}
 
@@ -357,6 +1011,7 @@ func (h *Handler) handleListContracts(w http.ResponseWriter, r *http.Request) {
 			Name:       c.Name,
 			SourcePath: c.SourcePath,
 			Chain:      c.Chain,
+			Labels:     c.Labels,
 		}
 	}
 
@@ -371,10 +1026,10 @@ func (h *Handler) handleGetContract(w http.ResponseWriter, r *http.Request) {
 	contract, err := h.svc.GetContract(r.Context(), name, version, contractName)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			writeError(w, http.StatusNotFound, "NOT_FOUND", "Contract not found")
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Contract not found")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get contract")
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get contract")
 		return
 	}
 
@@ -383,6 +1038,8 @@ func (h *Handler) handleGetContract(w http.ResponseWriter, r *http.Request) {
 		SourcePath: contract.SourcePath,
 		Chain:      contract.Chain,
 		License:    contract.License,
+		Labels:     contract.Labels,
+		Metadata:   contract.Metadata,
 	}
 	if len(contract.CompilationTarget) > 0 {
 		resp.CompilationTarget = contract.CompilationTarget
@@ -404,8 +1061,48 @@ func (h *Handler) handleGetContract(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// handleGetABI returns a contract's ABI. With ?merge=Proxy[,Other] it returns the ABI
+// merged with those of other contracts in the same version (see evmutil.MergeABIs);
+// selectors claimed with different signatures are listed in X-ABI-Collisions headers.
 func (h *Handler) handleGetABI(w http.ResponseWriter, r *http.Request) {
-	h.handleGetArtifact(w, r, "abi")
+	var merge []string
+	for _, v := range r.URL.Query()["merge"] {
+		for _, c := range strings.Split(v, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				merge = append(merge, c)
+			}
+		}
+	}
+	if len(merge) == 0 {
+		h.handleGetArtifact(w, r, "abi")
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	version := chi.URLParam(r, "version")
+	abis := make([]evmutil.NamedABI, 0, len(merge)+1)
+	for _, contractName := range append([]string{chi.URLParam(r, "contract")}, merge...) {
+		content, err := h.svc.GetArtifact(r.Context(), name, version, contractName, "abi")
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				writeError(w, http.StatusNotFound, errcodes.NotFound, fmt.Sprintf("ABI of %s not found", contractName))
+				return
+			}
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get artifact")
+			return
+		}
+		abis = append(abis, evmutil.NamedABI{Contract: contractName, ABI: content})
+	}
+
+	merged, err := evmutil.MergeABIs(abis)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, errcodes.InvalidABI, fmt.Sprintf("Cannot merge ABIs: %v", err))
+		return
+	}
+	for _, c := range merged.Collisions {
+		w.Header().Add("X-ABI-Collisions", c.String())
+	}
+	writeArtifact(w, r, "abi", merged.ABI)
 }
 
 func (h *Handler) handleGetBytecode(w http.ResponseWriter, r *http.Request) {
@@ -424,6 +1121,26 @@ func (h *Handler) handleGetStorageLayout(w http.ResponseWriter, r *http.Request)
 	h.handleGetArtifact(w, r, "storage-layout")
 }
 
+func (h *Handler) handleGetIDL(w http.ResponseWriter, r *http.Request) {
+	h.handleGetArtifact(w, r, "idl")
+}
+
+func (h *Handler) handleGetProgram(w http.ResponseWriter, r *http.Request) {
+	h.handleGetArtifact(w, r, "program")
+}
+
+// handleGetSignature serves a signed contract's publisher signature, verified when
+// it was published.
+func (h *Handler) handleGetSignature(w http.ResponseWriter, r *http.Request) {
+	h.handleGetArtifact(w, r, domain.SignatureArtifactType)
+}
+
+// handleGetArtifactByType serves any stored artifact, including extra artifact types.
+// The typed routes above are aliases for their built-in types.
+func (h *Handler) handleGetArtifactByType(w http.ResponseWriter, r *http.Request) {
+	h.handleGetArtifact(w, r, chi.URLParam(r, "type"))
+}
+
 func (h *Handler) handleGetArtifact(w http.ResponseWriter, r *http.Request, artifactType string) {
 	name := chi.URLParam(r, "name")
 	version := chi.URLParam(r, "version")
@@ -432,23 +1149,175 @@ func (h *Handler) handleGetArtifact(w http.ResponseWriter, r *http.Request, arti
 	content, err := h.svc.GetArtifact(r.Context(), name, version, contractName, artifactType)
 	if err != nil {
 		if errors.Is(err, domain.ErrNotFound) {
-			writeError(w, http.StatusNotFound, "NOT_FOUND", "Artifact not found")
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Artifact not found")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get artifact")
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get artifact")
 		return
 	}
+	writeArtifact(w, r, artifactType, content)
+}
 
-	// For JSON artifacts, set proper content type
-	if artifactType == "abi" || artifactType == "standard-json-input" || artifactType == "storage-layout" {
-		w.Header().Set("Content-Type", "application/json")
-	} else {
+// writeArtifact writes artifact content with an ETag, answering If-None-Match.
+func writeArtifact(w http.ResponseWriter, r *http.Request, artifactType string, content []byte) {
+	// Artifacts of a published version never change, so their content hash is a
+	// strong validator
+	etag := `"` + storage.HashContent(content) + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	// Set content type by artifact kind; extra artifacts are always JSON
+	switch artifactType {
+	case "bytecode", "deployed-bytecode":
 		w.Header().Set("Content-Type", "text/plain")
+	case "program":
+		w.Header().Set("Content-Type", "application/octet-stream")
+	default:
+		w.Header().Set("Content-Type", "application/json")
 	}
 	w.WriteHeader(http.StatusOK)
 	w.Write(content)
 }
 
+// handleGetVerifyPayload builds the explorer verification request for a contract from
+// its stored Standard JSON Input and compiler settings, without submitting it.
+func (h *Handler) handleGetVerifyPayload(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	version := chi.URLParam(r, "version")
+	contractName := chi.URLParam(r, "contract")
+
+	query := r.URL.Query()
+	if target := query.Get("target"); target != "etherscan" {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "target must be etherscan")
+		return
+	}
+	chainID := query.Get("chainId")
+	if _, err := strconv.ParseUint(chainID, 10, 64); err != nil {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "chainId must be a positive integer")
+		return
+	}
+
+	contract, err := h.svc.GetContract(r.Context(), name, version, contractName)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Contract not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get contract")
+		return
+	}
+	if contract.Chain != "" && contract.Chain != "evm" {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Etherscan verification is only available for EVM contracts")
+		return
+	}
+
+	stdJSON, err := h.svc.GetArtifact(r.Context(), name, version, contractName, "standard-json-input")
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Standard JSON input not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get standard JSON input")
+		return
+	}
+
+	req := etherscan.VerifyRequest{
+		ChainID:         chainID,
+		Address:         query.Get("address"),
+		SourcePath:      contract.SourcePath,
+		ContractName:    contract.Name,
+		CompilerVersion: contract.CompilerVersion,
+		StandardJSON:    stdJSON,
+		EVMVersion:      getStringFromMap(contract.CompilerSettings, "evmVersion"),
+		ConstructorArgs: query.Get("constructorArgs"),
+		License:         contract.License,
+	}
+	if opt, ok := contract.CompilerSettings["optimizer"].(map[string]any); ok {
+		req.OptimizerEnabled = getBoolFromMap(opt, "enabled")
+		req.OptimizerRuns = getIntFromMap(opt, "runs")
+	}
+	form, err := req.Form()
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, errcodes.NotVerifiable, fmt.Sprintf("Cannot build verification payload: %v", err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, VerifyPayloadResponse{
+		Target: "etherscan",
+		URL:    etherscan.APIURL + "?chainid=" + chainID,
+		Form:   form,
+	})
+}
+
+// handleGetBytecodeMetadata decodes the CBOR metadata solc appended to a contract's
+// bytecode, on demand from the stored artifact.
+func (h *Handler) handleGetBytecodeMetadata(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	version := chi.URLParam(r, "version")
+	contractName := chi.URLParam(r, "contract")
+
+	contract, err := h.svc.GetContract(r.Context(), name, version, contractName)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Contract not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get contract")
+		return
+	}
+	if contract.Chain != "" && contract.Chain != "evm" {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Bytecode metadata is only available for EVM contracts")
+		return
+	}
+
+	// Runtime code ends with the metadata; creation code usually does too
+	source := "deployed-bytecode"
+	bytecode, err := h.svc.GetArtifact(r.Context(), name, version, contractName, source)
+	if errors.Is(err, domain.ErrNotFound) {
+		source = "bytecode"
+		bytecode, err = h.svc.GetArtifact(r.Context(), name, version, contractName, source)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Bytecode not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to get bytecode")
+		return
+	}
+
+	meta, err := evmutil.ParseMetadataCBOR(bytecode)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, errcodes.NoMetadata, fmt.Sprintf("Cannot decode bytecode metadata: %v", err))
+		return
+	}
+
+	resp := BytecodeMetadataResponse{
+		Source:                  source,
+		HashType:                meta.HashType(),
+		SolcVersion:             meta.Solc,
+		Experimental:            meta.Experimental,
+		DeclaredCompilerVersion: contract.CompilerVersion,
+	}
+	if hash := meta.Hash(); hash != nil {
+		resp.Hash = "0x" + hex.EncodeToString(hash)
+	}
+	if len(meta.IPFS) > 0 {
+		resp.IPFSCID = base58.Encode(meta.IPFS)
+	}
+	if meta.Solc != "" && contract.CompilerVersion != "" {
+		// The declared version may carry a commit suffix ("0.8.28+commit.7893614a")
+		declared, _, _ := strings.Cut(strings.TrimPrefix(contract.CompilerVersion, "v"), "+")
+		matches := declared == meta.Solc
+		resp.CompilerVersionMatches = &matches
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
 // Helper functions
 
 func writeJSON(w http.ResponseWriter, status int, data any) {
@@ -485,6 +1354,22 @@ func writeError(w http.ResponseWriter, status int, code, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(ErrorResponse{
-		Error: ErrorDetail{Code: code, Message: message},
+		Error: ErrorDetail{Code: code, Message: message, RequestID: w.Header().Get(requestid.Header)},
+	})
+}
+
+// writeValidationError responds 422 VALIDATION_ERROR listing each invalid field.
+func writeValidationError(w http.ResponseWriter, err error) {
+	var fields validation.FieldErrors
+	errors.As(err, &fields)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Error: ErrorDetail{
+			Code:      errcodes.ValidationError,
+			Message:   "Request body is invalid: " + err.Error(),
+			Fields:    fields,
+			RequestID: w.Header().Get(requestid.Header),
+		},
 	})
 }
@@ -4,15 +4,28 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/pendergraft/contrafactory/internal/auth"
+	"github.com/pendergraft/contrafactory/internal/middleware/requestid"
 	"github.com/pendergraft/contrafactory/internal/packages/domain"
+	"github.com/pendergraft/contrafactory/internal/pagination"
+	"github.com/pendergraft/contrafactory/internal/storage"
+	"github.com/pendergraft/contrafactory/internal/validation"
 )
 
 // mockService implements Service for testing
@@ -20,6 +33,16 @@ type mockService struct {
 	packages  map[string]*domain.Package
 	contracts map[string][]domain.Contract
 	artifacts map[string][]byte
+
+	versionsErr    error
+	publishErr     error
+	owners         map[string]string // package name -> owning key ID
+	collaborators  map[string][]string
+	aliases        map[string]map[string]string // package name -> alias -> version
+	listFilter     domain.ListFilter
+	listPagination domain.PaginationParams
+	versionsOpts   domain.VersionsOptions
+	batchAtomic    bool
 }
 
 func newMockService() *mockService {
@@ -31,6 +54,9 @@ func newMockService() *mockService {
 }
 
 func (m *mockService) Publish(ctx context.Context, name, version string, ownerID string, req domain.PublishRequest) error {
+	if m.publishErr != nil {
+		return m.publishErr
+	}
 	key := name + "@" + version
 	m.packages[key] = &domain.Package{
 		Name:    name,
@@ -40,6 +66,24 @@ func (m *mockService) Publish(ctx context.Context, name, version string, ownerID
 	return nil
 }
 
+func (m *mockService) PublishBatch(ctx context.Context, ownerID string, items []domain.BatchItem, atomic bool) ([]domain.BatchItemResult, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no packages", domain.ErrInvalidBatch)
+	}
+	m.batchAtomic = atomic
+	results := make([]domain.BatchItemResult, len(items))
+	for i, item := range items {
+		results[i] = domain.BatchItemResult{Name: item.Name, Version: item.Version, Status: domain.BatchPublished}
+		if _, exists := m.packages[item.Name+"@"+item.Version]; exists {
+			results[i].Status = domain.BatchFailed
+			results[i].Err = domain.ErrVersionExists
+			continue
+		}
+		m.Publish(ctx, item.Name, item.Version, ownerID, item.Request)
+	}
+	return results, nil
+}
+
 func (m *mockService) Get(ctx context.Context, name, version string) (*domain.Package, error) {
 	key := name + "@" + version
 	if pkg, ok := m.packages[key]; ok {
@@ -48,29 +92,217 @@ func (m *mockService) Get(ctx context.Context, name, version string) (*domain.Pa
 	return nil, domain.ErrNotFound
 }
 
-func (m *mockService) GetVersions(ctx context.Context, name string, includePrerelease bool) (*domain.VersionsResult, error) {
+func (m *mockService) GetVersions(ctx context.Context, name string, opts domain.VersionsOptions) (*domain.VersionsResult, error) {
+	m.versionsOpts = opts
+	if m.versionsErr != nil {
+		return nil, m.versionsErr
+	}
+	if opts.Cursor == "bogus" {
+		return nil, fmt.Errorf("%w: %q is not a listed version", domain.ErrInvalidCursor, opts.Cursor)
+	}
 	var versions []string
 	for key := range m.packages {
-		if m.packages[key].Name == name {
+		if m.packages[key].Name == name && (opts.IncludePrerelease || !strings.Contains(m.packages[key].Version, "-")) {
 			versions = append(versions, m.packages[key].Version)
 		}
 	}
 	if len(versions) == 0 {
 		return nil, domain.ErrNotFound
 	}
-	return &domain.VersionsResult{Name: name, Versions: versions}, nil
+	slices.Sort(versions)
+	result := &domain.VersionsResult{Name: name, Versions: versions}
+	if opts.Limit > 0 && len(versions) > opts.Limit {
+		result.Versions = versions[:opts.Limit]
+		result.HasMore = true
+		result.NextCursor = versions[opts.Limit-1]
+	}
+	if opts.Detailed {
+		for _, v := range versions {
+			result.Details = append(result.Details, domain.VersionDetail{Version: v, Builder: "foundry", CompilerVersion: "0.8.28", CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)})
+		}
+	}
+	if opts.WithDeployments {
+		result.Deployments = make(map[string]domain.VersionDeployments)
+		for _, v := range versions {
+			result.Deployments[v] = domain.VersionDeployments{Count: 2, Verified: 1}
+		}
+	}
+	return result, nil
+}
+
+func (m *mockService) ListOwned(ctx context.Context, ownerID string, pagination domain.PaginationParams) (*domain.ListResult, error) {
+	var packages []domain.Package
+	for _, pkg := range m.packages {
+		if m.owners[pkg.Name] == ownerID {
+			packages = append(packages, *pkg)
+		}
+	}
+	return &domain.ListResult{Packages: packages}, nil
 }
 
 func (m *mockService) List(ctx context.Context, filter domain.ListFilter, pagination domain.PaginationParams) (*domain.ListResult, error) {
+	m.listFilter = filter
+	m.listPagination = pagination
 	var packages []domain.Package
 	for _, pkg := range m.packages {
 		packages = append(packages, *pkg)
 	}
-	return &domain.ListResult{Packages: packages}, nil
+	result := &domain.ListResult{Packages: packages}
+	if filter.Count {
+		total := len(packages)
+		result.Total = &total
+	}
+	if pagination.Before != "" {
+		result.HasMore = true
+		result.NextCursor = "test-pkg"
+		result.PrevCursor = "test-pkg"
+	}
+	return result, nil
+}
+
+func (m *mockService) LookupBytecode(ctx context.Context, hash string) ([]domain.BytecodeMatch, error) {
+	if hash == "bad" {
+		return nil, domain.ErrInvalidHash
+	}
+	var matches []domain.BytecodeMatch
+	for _, contracts := range m.contracts {
+		for _, c := range contracts {
+			if c.PrimaryHash == hash {
+				matches = append(matches, domain.BytecodeMatch{Package: "test-pkg", Version: "1.0.0", Contract: c.Name, Chain: "evm", MatchedOn: "bytecode"})
+			}
+		}
+	}
+	return matches, nil
+}
+
+func (m *mockService) LookupSelector(ctx context.Context, selector string) ([]domain.SelectorMatch, error) {
+	selector, err := domain.NormalizeSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	if selector != "0xa9059cbb" {
+		return nil, nil
+	}
+	return []domain.SelectorMatch{{Package: "test-pkg", Version: "1.0.0", Contract: "Token", Chain: "evm", Type: "function", Signature: "transfer(address,uint256)"}}, nil
+}
+
+func (m *mockService) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	return []domain.TagCount{{Tag: "defi", Packages: 2}, {Tag: "governance", Packages: 1}}, nil
+}
+
+func (m *mockService) GetOwner(ctx context.Context, name, callerID string, callerIsAdmin bool) (*domain.Owner, error) {
+	keyID, ok := m.owners[name]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	if !callerIsAdmin && callerID != keyID {
+		return nil, domain.ErrForbidden
+	}
+	return &domain.Owner{Name: "ci-release", KeyID: keyID, Since: time.Date(2025, 6, 15, 14, 30, 45, 0, time.UTC)}, nil
+}
+
+func (m *mockService) TransferOwner(ctx context.Context, name, callerID, toKeyID string) (*domain.Owner, error) {
+	keyID, ok := m.owners[name]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	if callerID != keyID {
+		return nil, domain.ErrForbidden
+	}
+	if toKeyID != "key-2" {
+		return nil, fmt.Errorf("%w: key %s does not exist or is revoked", domain.ErrInvalidOwner, toKeyID)
+	}
+	m.owners[name] = toKeyID
+	return &domain.Owner{Name: "someone-else", KeyID: toKeyID}, nil
+}
+
+func (m *mockService) ListCollaborators(ctx context.Context, name, callerID string, callerIsAdmin bool) ([]domain.Collaborator, error) {
+	keyID, ok := m.owners[name]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	if !callerIsAdmin && callerID != keyID && !slices.Contains(m.collaborators[name], callerID) {
+		return nil, domain.ErrForbidden
+	}
+	var collaborators []domain.Collaborator
+	for _, id := range m.collaborators[name] {
+		collaborators = append(collaborators, domain.Collaborator{Name: "ci-" + id, KeyID: id, Since: time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)})
+	}
+	return collaborators, nil
+}
+
+func (m *mockService) AddCollaborator(ctx context.Context, name, callerID, keyID string) ([]domain.Collaborator, error) {
+	if owner, ok := m.owners[name]; !ok {
+		return nil, domain.ErrNotFound
+	} else if callerID != owner {
+		return nil, domain.ErrForbidden
+	}
+	if keyID != "key-2" {
+		return nil, fmt.Errorf("%w: key %s does not exist or is revoked", domain.ErrInvalidCollaborator, keyID)
+	}
+	m.collaborators[name] = append(m.collaborators[name], keyID)
+	return m.ListCollaborators(ctx, name, callerID, false)
+}
+
+func (m *mockService) RemoveCollaborator(ctx context.Context, name, callerID, keyID string) ([]domain.Collaborator, error) {
+	if owner, ok := m.owners[name]; !ok {
+		return nil, domain.ErrNotFound
+	} else if callerID != owner {
+		return nil, domain.ErrForbidden
+	}
+	i := slices.Index(m.collaborators[name], keyID)
+	if i < 0 {
+		return nil, domain.ErrNotFound
+	}
+	m.collaborators[name] = slices.Delete(m.collaborators[name], i, i+1)
+	return m.ListCollaborators(ctx, name, callerID, false)
+}
+
+func (m *mockService) ListAliases(ctx context.Context, name string) ([]domain.Alias, error) {
+	var aliases []domain.Alias
+	for _, alias := range slices.Sorted(maps.Keys(m.aliases[name])) {
+		aliases = append(aliases, domain.Alias{Name: alias, Version: m.aliases[name][alias], UpdatedAt: time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)})
+	}
+	return aliases, nil
+}
+
+func (m *mockService) SetAlias(ctx context.Context, name, alias, version, callerID string) (*domain.Alias, error) {
+	if err := validation.ValidateAlias(alias); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidAlias, err)
+	}
+	if owner, ok := m.owners[name]; !ok {
+		return nil, domain.ErrNotFound
+	} else if callerID != owner {
+		return nil, domain.ErrForbidden
+	}
+	if _, ok := m.packages[name+"@"+version]; !ok {
+		return nil, domain.ErrNotFound
+	}
+	if m.aliases[name] == nil {
+		m.aliases[name] = map[string]string{}
+	}
+	m.aliases[name][alias] = version
+	return &domain.Alias{Name: alias, Version: version, UpdatedAt: time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)}, nil
+}
+
+func (m *mockService) DeleteAlias(ctx context.Context, name, alias, callerID string) error {
+	if owner, ok := m.owners[name]; !ok {
+		return domain.ErrNotFound
+	} else if callerID != owner {
+		return domain.ErrForbidden
+	}
+	if _, ok := m.aliases[name][alias]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(m.aliases[name], alias)
+	return nil
 }
 
 func (m *mockService) Delete(ctx context.Context, name, version string, ownerID string) error {
 	key := name + "@" + version
+	if _, ok := m.packages[key]; !ok {
+		return domain.ErrNotFound
+	}
 	delete(m.packages, key)
 	return nil
 }
@@ -103,9 +335,13 @@ func (m *mockService) GetArtifact(ctx context.Context, name, version, contractNa
 	return nil, domain.ErrNotFound
 }
 
-func (m *mockService) GetArchive(ctx context.Context, name, version string) ([]byte, error) {
+func (m *mockService) GetArchive(ctx context.Context, name, version string, format domain.ArchiveFormat) ([]byte, error) {
 	key := name + "@" + version
 	if _, ok := m.packages[key]; ok {
+		if format == domain.ArchiveZip {
+			// Empty zip: just the end of central directory record
+			return []byte("PK\x05\x06" + strings.Repeat("\x00", 18)), nil
+		}
 		// Return a minimal valid gzip/tar
 		return []byte{0x1f, 0x8b, 0x08, 0x00}, nil
 	}
@@ -145,6 +381,7 @@ func TestHandler_GetVersions(t *testing.T) {
 	svc := newMockService()
 	svc.packages["test-pkg@1.0.0"] = &domain.Package{Name: "test-pkg", Version: "1.0.0"}
 	svc.packages["test-pkg@2.0.0"] = &domain.Package{Name: "test-pkg", Version: "2.0.0"}
+	svc.packages["test-pkg@3.0.0-beta.1"] = &domain.Package{Name: "test-pkg", Version: "3.0.0-beta.1"}
 
 	router := setupRouter(svc)
 
@@ -161,6 +398,88 @@ func TestHandler_GetVersions(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "test-pkg", resp["name"])
 		assert.Len(t, resp["versions"], 2)
+		assert.NotContains(t, resp, "deployments")
+	})
+
+	t.Run("include prereleases", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/packages/test-pkg?include_prerelease=true", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp VersionsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Contains(t, resp.Versions, "3.0.0-beta.1")
+	})
+
+	t.Run("with deployments", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/packages/test-pkg?with_deployments=true", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp VersionsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp.Deployments, 2)
+		assert.Equal(t, VersionDeploymentsResponse{Count: 2, Verified: 1, AnyVerified: true}, resp.Deployments["1.0.0"])
+	})
+
+	t.Run("detailed", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/packages/test-pkg?detailed=true", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp DetailedVersionsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "test-pkg", resp.Name)
+		require.Len(t, resp.Versions, 2)
+		assert.Equal(t, "foundry", resp.Versions[0].Builder)
+		assert.Equal(t, "0.8.28", resp.Versions[0].CompilerVersion)
+		assert.Equal(t, "2024-05-01T10:00:00Z", resp.Versions[0].CreatedAt)
+	})
+
+	t.Run("paginated", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/packages/test-pkg?limit=1&cursor=3.0.0", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 1, svc.versionsOpts.Limit)
+		assert.Equal(t, "3.0.0", svc.versionsOpts.Cursor)
+
+		var resp VersionsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Len(t, resp.Versions, 1)
+		assert.Equal(t, 1, resp.Pagination.Limit)
+		assert.True(t, resp.Pagination.HasMore)
+		assert.Equal(t, resp.Versions[0], resp.Pagination.NextCursor)
+	})
+
+	t.Run("default and maximum page size", func(t *testing.T) {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/packages/test-pkg", nil))
+		assert.Equal(t, pagination.DefaultLimit, svc.versionsOpts.Limit)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/packages/test-pkg?limit=5000", nil))
+		assert.Equal(t, pagination.DefaultMaxLimit, svc.versionsOpts.Limit)
+	})
+
+	t.Run("invalid limit or cursor", func(t *testing.T) {
+		for _, query := range []string{"limit=0", "cursor=bogus"} {
+			req := httptest.NewRequest("GET", "/packages/test-pkg?"+query, nil)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
 	})
 
 	t.Run("non-existing package", func(t *testing.T) {
@@ -171,15 +490,29 @@ func TestHandler_GetVersions(t *testing.T) {
 
 		assert.Equal(t, http.StatusNotFound, rec.Code)
 	})
+
+	t.Run("store error", func(t *testing.T) {
+		svc.versionsErr = errors.New("getting latest version 2.0.0: connection reset")
+		defer func() { svc.versionsErr = nil }()
+
+		req := httptest.NewRequest("GET", "/packages/test-pkg", nil)
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "connection reset")
+	})
 }
 
 func TestHandler_Get(t *testing.T) {
 	svc := newMockService()
 	svc.packages["test-pkg@1.0.0"] = &domain.Package{
-		Name:    "test-pkg",
-		Version: "1.0.0",
-		Chain:   "evm",
-		Builder: "foundry",
+		Name:            "test-pkg",
+		Version:         "1.0.0",
+		Chain:           "evm",
+		Builder:         "foundry",
+		CompilerVersion: "0.8.28+commit.7893614a",
 	}
 	svc.contracts["test-pkg@1.0.0"] = []domain.Contract{
 		{Name: "Token"},
@@ -187,6 +520,36 @@ func TestHandler_Get(t *testing.T) {
 
 	router := setupRouter(svc)
 
+	getWarnings := func(t *testing.T, solc string) []any {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/packages/test-pkg/1.0.0?solc="+solc, nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		warnings, _ := resp["warnings"].([]any)
+		return warnings
+	}
+
+	t.Run("same minor solc", func(t *testing.T) {
+		assert.Empty(t, getWarnings(t, "0.8.19"))
+	})
+
+	t.Run("different minor solc", func(t *testing.T) {
+		warnings := getWarnings(t, "0.7.6")
+		require.Len(t, warnings, 1)
+		assert.Contains(t, warnings[0], "compiled with solc 0.8.28 but you are using 0.7.6")
+	})
+
+	t.Run("invalid solc", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/packages/test-pkg/1.0.0?solc=latest", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
 	t.Run("existing version", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/packages/test-pkg/1.0.0", nil)
 		rec := httptest.NewRecorder()
@@ -238,6 +601,97 @@ func TestHandler_Publish(t *testing.T) {
 	assert.Equal(t, "1.0.0", resp["version"])
 }
 
+func TestHandler_Publish_QuotaExceeded(t *testing.T) {
+	svc := newMockService()
+	svc.publishErr = fmt.Errorf("%w: artifact storage in use is 900 of 1000 bytes, this publish adds 200", domain.ErrQuotaExceeded)
+	router := setupRouter(svc)
+
+	req := httptest.NewRequest("POST", "/packages/new-pkg/1.0.0", bytes.NewBufferString(`{"chain":"evm","artifacts":[{"name":"Token"}]}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "QUOTA_EXCEEDED", resp.Error.Code)
+	assert.Contains(t, resp.Error.Message, "900 of 1000 bytes")
+}
+
+func TestHandler_Publish_ValidationError(t *testing.T) {
+	svc := newMockService()
+	router := setupRouter(svc)
+
+	req := httptest.NewRequest("POST", "/packages/new-pkg/1.0.0", bytes.NewBufferString(`{"artifacts":[{"name":"Token"},{"sourcePath":"src/Vault.sol"}]}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "VALIDATION_ERROR", resp.Error.Code)
+	assert.Equal(t, []validation.FieldError{
+		{Field: "chain", Message: "is required"},
+		{Field: "artifacts[1].name", Message: "is required"},
+	}, resp.Error.Fields)
+	assert.Empty(t, svc.packages, "nothing is published")
+
+	req = httptest.NewRequest("POST", "/packages/new-pkg/1.0.0", bytes.NewBufferString(`{"chain":"evm"}`))
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"artifacts"`)
+}
+
+func TestHandler_PublishBatch(t *testing.T) {
+	svc := newMockService()
+	svc.packages["existing@1.0.0"] = &domain.Package{Name: "existing", Version: "1.0.0"}
+
+	r := chi.NewRouter()
+	NewHandler(svc).RegisterBatchRoutes(r)
+
+	t.Run("all published", func(t *testing.T) {
+		body := `[{"name":"token","version":"1.0.0","request":{"chain":"evm","artifacts":[{"name":"Token","bytecode":"0x1234"}]}},
+			{"name":"registry","version":"1.0.0","request":{"chain":"evm","artifacts":[]}}]`
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("POST", "/publish-batch", bytes.NewBufferString(body)))
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var resp PublishBatchResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.True(t, resp.Atomic)
+		assert.Equal(t, 2, resp.Published)
+		assert.Equal(t, "evm", svc.packages["token@1.0.0"].Chain)
+	})
+
+	t.Run("best effort with a failure", func(t *testing.T) {
+		body := `[{"name":"existing","version":"1.0.0","request":{"chain":"evm"}},{"name":"fresh","version":"1.0.0","request":{"chain":"evm"}}]`
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("POST", "/publish-batch?mode=best-effort", bytes.NewBufferString(body)))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, svc.batchAtomic)
+		var resp PublishBatchResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, 1, resp.Published)
+		assert.Equal(t, 1, resp.Failed)
+		require.NotNil(t, resp.Results[0].Error)
+		assert.Equal(t, "VERSION_EXISTS", resp.Results[0].Error.Code)
+		assert.Equal(t, domain.BatchPublished, resp.Results[1].Status)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, tc := range []struct{ query, body string }{
+			{"", `[]`},
+			{"", `{"name":"x"}`},
+			{"?mode=sometimes", `[{"name":"x","version":"1.0.0","request":{}}]`},
+		} {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest("POST", "/publish-batch"+tc.query, bytes.NewBufferString(tc.body)))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, tc.body)
+		}
+	})
+}
+
 func TestHandler_Delete(t *testing.T) {
 	svc := newMockService()
 	svc.packages["test-pkg@1.0.0"] = &domain.Package{Name: "test-pkg", Version: "1.0.0"}
@@ -250,6 +704,11 @@ func TestHandler_Delete(t *testing.T) {
 	router.ServeHTTP(rec, req)
 
 	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// Deleting it again finds nothing
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("DELETE", "/packages/test-pkg/1.0.0", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
 }
 
 func TestHandler_List_LatestWithoutProject_Returns400(t *testing.T) {
@@ -275,6 +734,473 @@ func TestHandler_List_LatestWithoutProject_Returns400(t *testing.T) {
 	assert.Contains(t, errDetail["message"], "latest")
 }
 
+func TestHandler_List_Before(t *testing.T) {
+	svc := newMockService()
+	svc.packages["test-pkg@1.0.0"] = &domain.Package{Name: "test-pkg", Version: "1.0.0", Chain: "evm"}
+
+	router := setupRouter(svc)
+
+	t.Run("pages backward", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/packages/?before=zzz&limit=5", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, domain.PaginationParams{Limit: 5, Before: "zzz"}, svc.listPagination)
+
+		var resp ListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.True(t, resp.Pagination.HasMore)
+		assert.Equal(t, "test-pkg", resp.Pagination.NextCursor)
+		assert.Equal(t, "test-pkg", resp.Pagination.PrevCursor)
+	})
+
+	t.Run("cannot combine with cursor", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/packages/?before=zzz&cursor=aaa", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_REQUEST")
+	})
+}
+
+func TestHandler_List_Limit(t *testing.T) {
+	svc := newMockService()
+	router := setupRouter(svc)
+
+	t.Run("defaults and cap", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 20, svc.listPagination.Limit)
+
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/?limit=1000", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 100, svc.listPagination.Limit)
+	})
+
+	t.Run("configured max", func(t *testing.T) {
+		r := chi.NewRouter()
+		h := NewHandler(svc)
+		h.SetMaxPageSize(500)
+		r.Route("/packages", h.RegisterRoutes)
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/?limit=1000", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 500, svc.listPagination.Limit)
+	})
+
+	t.Run("non-numeric limit is rejected", func(t *testing.T) {
+		for _, path := range []string{"/packages/?limit=abc", "/packages/?limit=0"} {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+			assert.Contains(t, rec.Body.String(), "INVALID_REQUEST", path)
+		}
+	})
+}
+
+func TestHandler_List_CreatedAfter(t *testing.T) {
+	svc := newMockService()
+	router := setupRouter(svc)
+
+	t.Run("parses RFC3339", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/?created_after=2024-05-01T12:00:00%2B02:00", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, svc.listFilter.CreatedAfter.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("rejects other formats", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/?created_after=2024-05-01", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_REQUEST")
+	})
+}
+
+func TestHandler_Tags(t *testing.T) {
+	svc := newMockService()
+	router := setupRouter(svc)
+	NewHandler(svc).RegisterTagRoutes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/?tag=defi", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "defi", svc.listFilter.Tag)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/tags", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"tags":[{"tag":"defi","packages":2},{"tag":"governance","packages":1}]}`, rec.Body.String())
+}
+
+func TestHandler_List_MetadataFilters(t *testing.T) {
+	svc := newMockService()
+	router := setupRouter(svc)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/?metadata.audit_status=passed&metadata.team=core&chain=evm", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]string{"audit_status": "passed", "team": "core"}, svc.listFilter.Metadata)
+
+	for _, query := range []string{"metadata.=passed", "metadata.team=core&metadata.team=infra"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
+
+func TestHandler_List_Count(t *testing.T) {
+	svc := newMockService()
+	svc.packages["test-pkg@1.0.0"] = &domain.Package{Name: "test-pkg", Version: "1.0.0"}
+	router := setupRouter(svc)
+
+	t.Run("opt-in", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, svc.listFilter.Count)
+		assert.NotContains(t, rec.Body.String(), `"total"`)
+	})
+
+	t.Run("count=true", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/?count=true", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, svc.listFilter.Count)
+
+		var resp ListResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.NotNil(t, resp.Pagination.Total)
+		assert.Equal(t, 1, *resp.Pagination.Total)
+	})
+}
+
+// keyStore resolves API keys for the auth middleware in tests
+type keyStore map[string]*storage.APIKey
+
+func (k keyStore) CreateAPIKey(ctx context.Context, name string, scopes map[string]any) (string, error) {
+	return "", nil
+}
+
+func (k keyStore) ValidateAPIKey(ctx context.Context, key string) (*storage.APIKey, error) {
+	if apiKey, ok := k[key]; ok {
+		return apiKey, nil
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (k keyStore) GetAPIKey(ctx context.Context, id string) (*storage.APIKey, error) {
+	return nil, storage.ErrNotFound
+}
+
+func (k keyStore) ListAPIKeys(ctx context.Context) ([]storage.APIKey, error) { return nil, nil }
+
+func (k keyStore) RevokeAPIKey(ctx context.Context, id string) error { return nil }
+
+func TestHandler_GetOwner(t *testing.T) {
+	svc := newMockService()
+	svc.owners = map[string]string{"my-package": "key-1"}
+
+	keys := keyStore{
+		"owner-key": {ID: "key-1", Name: "ci-release"},
+		"other-key": {ID: "key-2", Name: "someone-else"},
+		"admin-key": {ID: "key-3", Name: "admin", Scopes: map[string]any{storage.ScopeAdmin: true}},
+	}
+	r := chi.NewRouter()
+	r.Route("/packages", func(r chi.Router) {
+		r.Use(auth.Middleware(keys, writeError))
+		NewHandler(svc).RegisterWriteRoutes(r)
+	})
+
+	get := func(name, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/packages/"+name+"/owner", nil)
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, key := range []string{"owner-key", "admin-key"} {
+		rec := get("my-package", key)
+		require.Equal(t, http.StatusOK, rec.Code, key)
+		assert.JSONEq(t, `{"name":"my-package","owner":"ci-release","since":"2025-06-15T14:30:45Z"}`, rec.Body.String())
+	}
+
+	assert.Equal(t, http.StatusForbidden, get("my-package", "other-key").Code)
+	assert.Equal(t, http.StatusNotFound, get("unowned", "owner-key").Code)
+	assert.Equal(t, http.StatusUnauthorized, get("my-package", "").Code)
+}
+
+func TestHandler_ListOwned(t *testing.T) {
+	svc := newMockService()
+	svc.packages["my-package@1.0.0"] = &domain.Package{Name: "my-package", Version: "1.0.0", Chain: "evm", Versions: []string{"1.0.0"}}
+	svc.packages["their-package@1.0.0"] = &domain.Package{Name: "their-package", Version: "1.0.0", Chain: "evm"}
+	svc.owners = map[string]string{"my-package": "key-1", "their-package": "key-2"}
+
+	keys := keyStore{"owner-key": {ID: "key-1", Name: "ci-release"}}
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(auth.Middleware(keys, writeError))
+		NewHandler(svc).RegisterAccountRoutes(r)
+	})
+
+	get := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/me/packages", nil)
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("owner-key")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp ListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "my-package", resp.Data[0].Name)
+	assert.Equal(t, []string{"1.0.0"}, resp.Data[0].Versions)
+
+	assert.Equal(t, http.StatusUnauthorized, get("").Code)
+}
+
+func TestHandler_TransferOwner(t *testing.T) {
+	svc := newMockService()
+	svc.owners = map[string]string{"my-package": "key-1"}
+
+	keys := keyStore{
+		"owner-key": {ID: "key-1", Name: "ci-release"},
+		"other-key": {ID: "key-2", Name: "someone-else"},
+	}
+	r := chi.NewRouter()
+	r.Route("/packages", func(r chi.Router) {
+		r.Use(auth.Middleware(keys, writeError))
+		NewHandler(svc).RegisterWriteRoutes(r)
+	})
+
+	post := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/packages/my-package/transfer-owner", strings.NewReader(body))
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusBadRequest, post("owner-key", `{}`).Code)
+	assert.Equal(t, http.StatusForbidden, post("other-key", `{"toKeyId":"key-2"}`).Code)
+
+	rec := post("owner-key", `{"toKeyId":"key-9"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "does not exist or is revoked")
+
+	rec = post("owner-key", `{"toKeyId":"key-2"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name":"my-package","owner":"someone-else"}`, rec.Body.String())
+	assert.Equal(t, "key-2", svc.owners["my-package"])
+}
+
+func TestHandler_Collaborators(t *testing.T) {
+	svc := newMockService()
+	svc.owners = map[string]string{"my-package": "key-1"}
+	svc.collaborators = map[string][]string{}
+
+	keys := keyStore{
+		"owner-key": {ID: "key-1", Name: "ci-release"},
+		"ci-key":    {ID: "key-2", Name: "ci-key-2"},
+		"other-key": {ID: "key-3", Name: "someone-else"},
+	}
+	r := chi.NewRouter()
+	r.Route("/packages", func(r chi.Router) {
+		r.Use(auth.Middleware(keys, writeError))
+		NewHandler(svc).RegisterWriteRoutes(r)
+	})
+
+	do := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/packages/my-package/collaborators"+path, strings.NewReader(body))
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusBadRequest, do("POST", "", "owner-key", `{}`).Code)
+	assert.Equal(t, http.StatusForbidden, do("POST", "", "other-key", `{"keyId":"key-2"}`).Code)
+
+	rec := do("POST", "", "owner-key", `{"keyId":"key-9"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "does not exist or is revoked")
+
+	rec = do("POST", "", "owner-key", `{"keyId":"key-2"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	want := `{"name":"my-package","collaborators":[{"keyId":"key-2","name":"ci-key-2","since":"2025-07-01T09:00:00Z"}]}`
+	assert.JSONEq(t, want, rec.Body.String())
+
+	// The collaborator can see the list; others can't
+	rec = do("GET", "", "ci-key", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, want, rec.Body.String())
+	assert.Equal(t, http.StatusForbidden, do("GET", "", "other-key", "").Code)
+
+	assert.Equal(t, http.StatusForbidden, do("DELETE", "/key-2", "ci-key", "").Code)
+	rec = do("DELETE", "/key-2", "owner-key", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name":"my-package","collaborators":[]}`, rec.Body.String())
+	assert.Equal(t, http.StatusNotFound, do("DELETE", "/key-2", "owner-key", "").Code)
+}
+
+func TestHandler_Aliases(t *testing.T) {
+	svc := newMockService()
+	svc.owners = map[string]string{"my-package": "key-1"}
+	svc.aliases = map[string]map[string]string{}
+	svc.packages["my-package@1.0.0"] = &domain.Package{Name: "my-package", Version: "1.0.0"}
+
+	keys := keyStore{
+		"owner-key": {ID: "key-1", Name: "ci-release"},
+		"other-key": {ID: "key-3", Name: "someone-else"},
+	}
+	r := chi.NewRouter()
+	r.Route("/packages", func(r chi.Router) {
+		NewHandler(svc).RegisterReadRoutes(r)
+		r.Group(func(r chi.Router) {
+			r.Use(auth.Middleware(keys, writeError))
+			NewHandler(svc).RegisterWriteRoutes(r)
+		})
+	})
+
+	do := func(method, path, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/packages/my-package/aliases"+path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/stable", "owner-key", `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do("POST", "/latest", "owner-key", `{"version":"1.0.0"}`).Code)
+	assert.Equal(t, http.StatusForbidden, do("POST", "/stable", "other-key", `{"version":"1.0.0"}`).Code)
+	assert.Equal(t, http.StatusNotFound, do("POST", "/stable", "owner-key", `{"version":"9.9.9"}`).Code)
+
+	rec := do("POST", "/stable", "owner-key", `{"version":"1.0.0"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"alias":"stable","version":"1.0.0","updatedAt":"2025-07-01T09:00:00Z"}`, rec.Body.String())
+
+	// Listing needs no key
+	rec = do("GET", "", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name":"my-package","aliases":[{"alias":"stable","version":"1.0.0","updatedAt":"2025-07-01T09:00:00Z"}]}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusForbidden, do("DELETE", "/stable", "other-key", "").Code)
+	assert.Equal(t, http.StatusNoContent, do("DELETE", "/stable", "owner-key", "").Code)
+	assert.Equal(t, http.StatusNotFound, do("DELETE", "/stable", "owner-key", "").Code)
+
+	rec = do("GET", "", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name":"my-package","aliases":[]}`, rec.Body.String())
+}
+
+func TestWriteError_IncludesRequestID(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(requestid.Middleware(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	r.Route("/packages", NewHandler(newMockService()).RegisterReadRoutes)
+
+	req := httptest.NewRequest("GET", "/packages/missing/1.0.0", nil)
+	req.Header.Set(requestid.Header, "req-123")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "req-123", rec.Header().Get(requestid.Header))
+	var resp ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "req-123", resp.Error.RequestID)
+}
+
+func TestHandler_LookupBytecode(t *testing.T) {
+	svc := newMockService()
+	svc.contracts["test-pkg@1.0.0"] = []domain.Contract{{Name: "Token", PrimaryHash: "abc123"}}
+
+	r := chi.NewRouter()
+	NewHandler(svc).RegisterLookupRoutes(r)
+
+	t.Run("matches", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", "/lookup/bytecode?hash=abc123", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp BytecodeLookupResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "abc123", resp.Hash)
+		assert.Equal(t, []BytecodeMatch{{Package: "test-pkg", Version: "1.0.0", Contract: "Token", Chain: "evm", MatchedOn: "bytecode"}}, resp.Matches)
+	})
+
+	t.Run("no matches is an empty list", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", "/lookup/bytecode?hash=def456", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"hash":"def456","matches":[]}`, rec.Body.String())
+	})
+
+	t.Run("hash reported with its algorithm", func(t *testing.T) {
+		digest := strings.Repeat("ab", 32)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", "/lookup/bytecode?hash=0x"+digest, nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"hash":"sha256:`+digest+`","matches":[]}`, rec.Body.String())
+	})
+
+	t.Run("missing or invalid hash", func(t *testing.T) {
+		for _, url := range []string{"/lookup/bytecode", "/lookup/bytecode?hash=bad"} {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest("GET", url, nil))
+			assert.Equal(t, http.StatusBadRequest, rec.Code, url)
+			assert.Contains(t, rec.Body.String(), "INVALID_REQUEST")
+		}
+	})
+}
+
+func TestHandler_LookupSelector(t *testing.T) {
+	r := chi.NewRouter()
+	NewHandler(newMockService()).RegisterLookupRoutes(r)
+
+	t.Run("matches", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", "/lookup/selector/0xA9059CBB", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp SelectorLookupResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "0xa9059cbb", resp.Selector)
+		assert.Equal(t, []SelectorMatch{{Package: "test-pkg", Version: "1.0.0", Contract: "Token", Chain: "evm", Type: "function", Signature: "transfer(address,uint256)"}}, resp.Matches)
+	})
+
+	t.Run("no matches is an empty list", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", "/lookup/selector/deadbeef", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"selector":"0xdeadbeef","matches":[]}`, rec.Body.String())
+	})
+
+	t.Run("invalid selector", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest("GET", "/lookup/selector/0x12", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_REQUEST")
+	})
+}
+
 func TestHandler_GetContract_IncludesCompilationTargetAndCompiler(t *testing.T) {
 	svc := newMockService()
 	svc.packages["test-pkg@1.0.0"] = &domain.Package{Name: "test-pkg", Version: "1.0.0", Chain: "evm"}
@@ -283,6 +1209,7 @@ func TestHandler_GetContract_IncludesCompilationTargetAndCompiler(t *testing.T)
 			Name:              "Token",
 			SourcePath:        "src/Token.sol",
 			Chain:             "evm",
+			Metadata:          map[string]string{"audit": "passed"},
 			CompilationTarget: map[string]string{"src/Token.sol": "Token"},
 			CompilerVersion:   "0.8.28",
 			CompilerSettings: map[string]any{
@@ -307,6 +1234,7 @@ func TestHandler_GetContract_IncludesCompilationTargetAndCompiler(t *testing.T)
 	require.NoError(t, err)
 	assert.Equal(t, "Token", resp["name"])
 	assert.Equal(t, "src/Token.sol", resp["sourcePath"])
+	assert.Equal(t, map[string]any{"audit": "passed"}, resp["metadata"])
 
 	compTarget, ok := resp["compilationTarget"].(map[string]any)
 	require.True(t, ok, "compilationTarget should be present")
@@ -325,6 +1253,38 @@ func TestHandler_GetContract_IncludesCompilationTargetAndCompiler(t *testing.T)
 	assert.Equal(t, float64(200), opt["runs"])
 }
 
+func TestHandler_GetArchive(t *testing.T) {
+	svc := newMockService()
+	svc.packages["test-pkg@1.0.0"] = &domain.Package{Name: "test-pkg", Version: "1.0.0", Chain: "evm"}
+	router := setupRouter(svc)
+
+	tests := []struct {
+		query       string
+		status      int
+		contentType string
+		filename    string
+	}{
+		{"", http.StatusOK, "application/gzip", "test-pkg-1.0.0.tar.gz"},
+		{"?format=tar.gz", http.StatusOK, "application/gzip", "test-pkg-1.0.0.tar.gz"},
+		{"?format=zip", http.StatusOK, "application/zip", "test-pkg-1.0.0.zip"},
+		{"?format=rar", http.StatusBadRequest, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/packages/test-pkg/1.0.0/archive"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			if tt.status != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tt.contentType, rec.Header().Get("Content-Type"))
+			assert.Contains(t, rec.Header().Get("Content-Disposition"), `filename="`+tt.filename+`"`)
+		})
+	}
+}
+
 func TestHandler_GetArtifact(t *testing.T) {
 	svc := newMockService()
 	svc.packages["test-pkg@1.0.0"] = &domain.Package{Name: "test-pkg", Version: "1.0.0"}
@@ -343,6 +1303,49 @@ func TestHandler_GetArtifact(t *testing.T) {
 		assert.Contains(t, rec.Body.String(), "function")
 	})
 
+	t.Run("by type", func(t *testing.T) {
+		svc.artifacts["test-pkg@1.0.0/Token/devdoc"] = []byte(`{"kind":"dev"}`)
+
+		for _, path := range []string{"artifacts/devdoc", "artifacts/abi"} {
+			req := httptest.NewRequest("GET", "/packages/test-pkg/1.0.0/contracts/Token/"+path, nil)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code, path)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"), path)
+		}
+	})
+
+	t.Run("signature", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/packages/test-pkg/1.0.0/contracts/Token/signature", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code, "unsigned contract")
+
+		svc.artifacts["test-pkg@1.0.0/Token/signature"] = []byte(`{"algorithm":"ed25519","publicKey":"k","signature":"s","digest":"sha256:00"}`)
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/test-pkg/1.0.0/contracts/Token/signature", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(), `"algorithm":"ed25519"`)
+	})
+
+	t.Run("etag", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/test-pkg/1.0.0/contracts/Token/abi", nil))
+		etag := rec.Header().Get("ETag")
+		assert.Equal(t, `"`+storage.HashContent([]byte(`[{"type":"function"}]`))+`"`, etag)
+		assert.True(t, strings.HasPrefix(etag, `"sha256:`))
+
+		req := httptest.NewRequest("GET", "/packages/test-pkg/1.0.0/contracts/Token/abi", nil)
+		req.Header.Set("If-None-Match", etag)
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
 	t.Run("non-existing artifact", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/packages/test-pkg/1.0.0/contracts/Token/bytecode", nil)
 		rec := httptest.NewRecorder()
@@ -352,3 +1355,165 @@ func TestHandler_GetArtifact(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, rec.Code)
 	})
 }
+
+func TestHandler_GetABI_Merge(t *testing.T) {
+	svc := newMockService()
+	svc.artifacts["test-pkg@1.0.0/Impl/abi"] = []byte(`[
+		{"type":"function","name":"burn","inputs":[{"type":"uint256"}]},
+		{"type":"function","name":"upgradeTo","inputs":[{"type":"address"}]}
+	]`)
+	svc.artifacts["test-pkg@1.0.0/Proxy/abi"] = []byte(`[
+		{"type":"function","name":"upgradeTo","inputs":[{"type":"address"}]},
+		{"type":"function","name":"collate_propagate_storage","inputs":[{"type":"bytes16"}]},
+		{"type":"event","name":"Upgraded","inputs":[{"type":"address","indexed":true}]}
+	]`)
+
+	router := setupRouter(svc)
+
+	t.Run("merged", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/test-pkg/1.0.0/contracts/Impl/abi?merge=Proxy", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var abi []map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &abi))
+		var names []string
+		for _, e := range abi {
+			names = append(names, e["name"].(string))
+		}
+		assert.Equal(t, []string{"burn", "upgradeTo", "Upgraded"}, names)
+
+		// collate_propagate_storage(bytes16) has the selector of burn(uint256)
+		assert.Equal(t, []string{"function 0x42966c68: Impl.burn(uint256), Proxy.collate_propagate_storage(bytes16)"},
+			rec.Header().Values("X-ABI-Collisions"))
+		assert.NotEmpty(t, rec.Header().Get("ETag"))
+	})
+
+	t.Run("unknown contract", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/test-pkg/1.0.0/contracts/Impl/abi?merge=Missing", nil))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Missing")
+	})
+}
+
+func TestHandler_GetVerifyPayload(t *testing.T) {
+	svc := newMockService()
+	svc.packages["test-pkg@1.0.0"] = &domain.Package{Name: "test-pkg", Version: "1.0.0", Chain: "evm"}
+	svc.contracts["test-pkg@1.0.0"] = []domain.Contract{
+		{
+			Name:            "Token",
+			SourcePath:      "src/Token.sol",
+			Chain:           "evm",
+			License:         "MIT",
+			CompilerVersion: "0.8.28+commit.7893614a",
+			CompilerSettings: map[string]any{
+				"evmVersion": "paris",
+				"optimizer":  map[string]any{"enabled": true, "runs": float64(10000)},
+			},
+		},
+		{Name: "Bare", SourcePath: "src/Bare.sol", Chain: "evm"},
+	}
+	svc.artifacts["test-pkg@1.0.0/Token/standard-json-input"] = []byte(`{"language":"Solidity"}`)
+
+	router := setupRouter(svc)
+
+	t.Run("etherscan", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/packages/test-pkg/1.0.0/contracts/Token/verify-payload?target=etherscan&chainId=1&address=0xabc&constructorArgs=0x0001", nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp VerifyPayloadResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "etherscan", resp.Target)
+		assert.Equal(t, "https://api.etherscan.io/v2/api?chainid=1", resp.URL)
+		assert.Equal(t, "verifysourcecode", resp.Form["action"])
+		assert.Equal(t, "1", resp.Form["chainid"])
+		assert.Equal(t, `{"language":"Solidity"}`, resp.Form["sourceCode"])
+		assert.Equal(t, "src/Token.sol:Token", resp.Form["contractname"])
+		assert.Equal(t, "v0.8.28+commit.7893614a", resp.Form["compilerversion"])
+		assert.Equal(t, "1", resp.Form["optimizationUsed"])
+		assert.Equal(t, "10000", resp.Form["runs"])
+		assert.Equal(t, "paris", resp.Form["evmversion"])
+		assert.Equal(t, "3", resp.Form["licenseType"])
+		assert.Equal(t, "0xabc", resp.Form["contractaddress"])
+		assert.Equal(t, "0001", resp.Form["constructorArguements"])
+		assert.NotContains(t, resp.Form, "apikey")
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{"unknown target", "/packages/test-pkg/1.0.0/contracts/Token/verify-payload?target=sourcify&chainId=1", http.StatusBadRequest},
+		{"missing chainId", "/packages/test-pkg/1.0.0/contracts/Token/verify-payload?target=etherscan", http.StatusBadRequest},
+		{"unknown contract", "/packages/test-pkg/1.0.0/contracts/Nope/verify-payload?target=etherscan&chainId=1", http.StatusNotFound},
+		{"no standard JSON input", "/packages/test-pkg/1.0.0/contracts/Bare/verify-payload?target=etherscan&chainId=1", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestHandler_GetBytecodeMetadata(t *testing.T) {
+	const tail = "a2646970667358221220" +
+		"8b2e5d6ca1e6e0bd8f9fa2e9a1a0d4d2f3e6b1d6a1c5b3f9d7e2c4a6b8d0e2f4" +
+		"64736f6c634300081c0033"
+	svc := newMockService()
+	svc.packages["test-pkg@1.0.0"] = &domain.Package{Name: "test-pkg", Version: "1.0.0", Chain: "evm"}
+	svc.contracts["test-pkg@1.0.0"] = []domain.Contract{
+		{Name: "Token", Chain: "evm", CompilerVersion: "0.8.28+commit.7893614a"},
+		{Name: "Stale", Chain: "evm", CompilerVersion: "0.8.20+commit.a1b79de6"},
+		{Name: "Bare", Chain: "evm"},
+	}
+	svc.artifacts["test-pkg@1.0.0/Token/deployed-bytecode"] = []byte("0x6080604052" + tail)
+	svc.artifacts["test-pkg@1.0.0/Stale/bytecode"] = []byte("0x6080604052" + tail)
+	svc.artifacts["test-pkg@1.0.0/Bare/deployed-bytecode"] = []byte("0x6080604052600080fd")
+
+	router := setupRouter(svc)
+	get := func(contract string) (*httptest.ResponseRecorder, BytecodeMetadataResponse) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/packages/test-pkg/1.0.0/contracts/"+contract+"/bytecode-metadata", nil))
+		var resp BytecodeMetadataResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec, resp
+	}
+
+	t.Run("matching compiler version", func(t *testing.T) {
+		rec, resp := get("Token")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "deployed-bytecode", resp.Source)
+		assert.Equal(t, "ipfs", resp.HashType)
+		assert.Equal(t, "0x12208b2e5d6ca1e6e0bd8f9fa2e9a1a0d4d2f3e6b1d6a1c5b3f9d7e2c4a6b8d0e2f4", resp.Hash)
+		assert.True(t, strings.HasPrefix(resp.IPFSCID, "Qm"))
+		assert.Equal(t, "0.8.28", resp.SolcVersion)
+		require.NotNil(t, resp.CompilerVersionMatches)
+		assert.True(t, *resp.CompilerVersionMatches)
+	})
+
+	t.Run("falls back to creation bytecode", func(t *testing.T) {
+		rec, resp := get("Stale")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "bytecode", resp.Source)
+		require.NotNil(t, resp.CompilerVersionMatches)
+		assert.False(t, *resp.CompilerVersionMatches)
+	})
+
+	t.Run("no metadata", func(t *testing.T) {
+		rec, _ := get("Bare")
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), "NO_METADATA")
+	})
+
+	t.Run("unknown contract", func(t *testing.T) {
+		rec, _ := get("Nope")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 
 	"github.com/pendergraft/contrafactory/internal/packages/domain"
+	"github.com/pendergraft/contrafactory/internal/validation"
 )
 
 // PublishRequest is the HTTP request body for publishing a package.
@@ -14,19 +15,27 @@ type PublishRequest struct {
 	Project   string            `json:"project,omitempty"`
 	Artifacts []ArtifactRequest `json:"artifacts"`
 	Metadata  map[string]string `json:"metadata,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
 }
 
 // ArtifactRequest is an artifact in a publish request.
 type ArtifactRequest struct {
-	Name              string               `json:"name"`
-	SourcePath        string               `json:"sourcePath"`
-	Chain             string               `json:"chain,omitempty"`
-	ABI               json.RawMessage      `json:"abi,omitempty"`
-	Bytecode          string               `json:"bytecode,omitempty"`
-	DeployedBytecode  string               `json:"deployedBytecode,omitempty"`
-	StandardJSONInput json.RawMessage      `json:"standardJsonInput,omitempty"`
-	StorageLayout     json.RawMessage      `json:"storageLayout,omitempty"`
-	Compiler          *CompilerInfoRequest `json:"compiler,omitempty"`
+	Name              string                     `json:"name"`
+	SourcePath        string                     `json:"sourcePath"`
+	Chain             string                     `json:"chain,omitempty"`
+	ABI               json.RawMessage            `json:"abi,omitempty"`
+	Bytecode          string                     `json:"bytecode,omitempty"`
+	DeployedBytecode  string                     `json:"deployedBytecode,omitempty"`
+	StandardJSONInput json.RawMessage            `json:"standardJsonInput,omitempty"`
+	StorageLayout     json.RawMessage            `json:"storageLayout,omitempty"`
+	Compiler          *CompilerInfoRequest       `json:"compiler,omitempty"`
+	IDL               json.RawMessage            `json:"idl,omitempty"`
+	Program           []byte                     `json:"program,omitempty"`
+	Labels            []string                   `json:"labels,omitempty"`
+	Metadata          map[string]string          `json:"metadata,omitempty"`
+	Extra             map[string]json.RawMessage `json:"extra,omitempty"`
+	Signature         string                     `json:"signature,omitempty"`
+	PublicKey         string                     `json:"publicKey,omitempty"`
 }
 
 // CompilerInfoRequest is compiler info in a publish request.
@@ -55,6 +64,7 @@ func (r PublishRequest) ToDomain() domain.PublishRequest {
 		Project:   r.Project,
 		Artifacts: artifacts,
 		Metadata:  r.Metadata,
+		Tags:      r.Tags,
 	}
 }
 
@@ -69,6 +79,13 @@ func (a ArtifactRequest) ToDomain() domain.Artifact {
 		DeployedBytecode:  a.DeployedBytecode,
 		StandardJSONInput: a.StandardJSONInput,
 		StorageLayout:     a.StorageLayout,
+		IDL:               a.IDL,
+		Program:           a.Program,
+		Labels:            a.Labels,
+		Metadata:          a.Metadata,
+		Extra:             a.Extra,
+		Signature:         a.Signature,
+		PublicKey:         a.PublicKey,
 	}
 	if a.Compiler != nil {
 		info := a.Compiler.ToDomain()
@@ -113,6 +130,8 @@ type Pagination struct {
 	Limit      int    `json:"limit"`
 	HasMore    bool   `json:"hasMore"`
 	NextCursor string `json:"nextCursor"`
+	PrevCursor string `json:"prevCursor"`
+	Total      *int   `json:"total,omitempty"` // only with ?count=true
 }
 
 // VersionsResponse is the response for getting package versions.
@@ -120,19 +139,102 @@ type VersionsResponse struct {
 	Name     string   `json:"name"`
 	Chain    string   `json:"chain"`
 	Builder  string   `json:"builder"`
-	Versions []string `json:"versions"`
+	Versions []string `json:"versions"` // newest first
+
+	// Deployments is keyed by version; only present with ?with_deployments=true.
+	Deployments map[string]VersionDeploymentsResponse `json:"deployments,omitempty"`
+
+	Pagination Pagination `json:"pagination"`
+}
+
+// DetailedVersionsResponse is the response for getting package versions with
+// ?detailed=true: its versions field, which shadows the embedded one, lists objects
+// instead of bare version strings.
+type DetailedVersionsResponse struct {
+	VersionsResponse
+	Versions []VersionDetailResponse `json:"versions"`
+}
+
+// VersionDetailResponse describes how one version of a package was built.
+type VersionDetailResponse struct {
+	Version         string `json:"version"`
+	Chain           string `json:"chain"`
+	Builder         string `json:"builder"`
+	CompilerVersion string `json:"compilerVersion"`
+	CreatedAt       string `json:"createdAt"`
+}
+
+// VersionDeploymentsResponse summarizes deployments recorded against a version.
+type VersionDeploymentsResponse struct {
+	Count       int  `json:"count"`
+	Verified    int  `json:"verified"`
+	AnyVerified bool `json:"anyVerified"`
 }
 
 // PackageResponse is the response for getting a package version.
 type PackageResponse struct {
 	Name            string         `json:"name"`
 	Version         string         `json:"version"`
+	Project         string         `json:"project,omitempty"`
 	Chain           string         `json:"chain"`
 	Builder         string         `json:"builder"`
 	CompilerVersion string         `json:"compilerVersion"`
 	Contracts       []string       `json:"contracts"`
 	CreatedAt       string         `json:"createdAt"`
 	Metadata        map[string]any `json:"metadata,omitempty"`
+	Tags            []string       `json:"tags,omitempty"`
+	// Warnings are non-blocking advisories, such as a compiler mismatch with the
+	// solc version given in the ?solc= query.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// OwnerResponse is the response for getting a package's owner.
+type OwnerResponse struct {
+	Name  string `json:"name"`
+	Owner string `json:"owner"` // name of the owning API key
+	Since string `json:"since,omitempty"`
+}
+
+// TransferOwnerRequest is the request body for transferring package ownership.
+type TransferOwnerRequest struct {
+	ToKeyID string `json:"toKeyId"`
+}
+
+// AddCollaboratorRequest is the request body for adding a package collaborator.
+type AddCollaboratorRequest struct {
+	KeyID string `json:"keyId"`
+}
+
+// CollaboratorsResponse lists the API keys that may publish to a package besides
+// its owner.
+type CollaboratorsResponse struct {
+	Name          string                 `json:"name"`
+	Collaborators []CollaboratorResponse `json:"collaborators"`
+}
+
+// CollaboratorResponse is one package collaborator.
+type CollaboratorResponse struct {
+	KeyID string `json:"keyId"`
+	Name  string `json:"name"` // name of the API key
+	Since string `json:"since,omitempty"`
+}
+
+// SetAliasRequest is the request body for pointing a package alias at a version.
+type SetAliasRequest struct {
+	Version string `json:"version"`
+}
+
+// AliasesResponse lists the aliases of a package.
+type AliasesResponse struct {
+	Name    string          `json:"name"`
+	Aliases []AliasResponse `json:"aliases"`
+}
+
+// AliasResponse is one package alias and the version it points at.
+type AliasResponse struct {
+	Alias     string `json:"alias"`
+	Version   string `json:"version"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
 }
 
 // PublishResponse is the response for publishing a package.
@@ -142,6 +244,29 @@ type PublishResponse struct {
 	Message string `json:"message"`
 }
 
+// PublishBatchItem is one package version in a batch publish request.
+type PublishBatchItem struct {
+	Name    string         `json:"name"`
+	Version string         `json:"version"`
+	Request PublishRequest `json:"request"`
+}
+
+// PublishBatchResponse is the response for a batch publish.
+type PublishBatchResponse struct {
+	Atomic    bool                 `json:"atomic"`
+	Published int                  `json:"published"`
+	Failed    int                  `json:"failed"`
+	Results   []PublishBatchResult `json:"results"`
+}
+
+// PublishBatchResult is the outcome of one item of a batch publish.
+type PublishBatchResult struct {
+	Name    string       `json:"name"`
+	Version string       `json:"version"`
+	Status  string       `json:"status"` // published, failed, rolled-back or skipped
+	Error   *ErrorDetail `json:"error,omitempty"`
+}
+
 // ContractsResponse is the response for listing contracts.
 type ContractsResponse struct {
 	Contracts []ContractItem `json:"contracts"`
@@ -149,9 +274,10 @@ type ContractsResponse struct {
 
 // ContractItem is a contract summary.
 type ContractItem struct {
-	Name       string `json:"name"`
-	SourcePath string `json:"sourcePath"`
-	Chain      string `json:"chain"`
+	Name       string   `json:"name"`
+	SourcePath string   `json:"sourcePath"`
+	Chain      string   `json:"chain"`
+	Labels     []string `json:"labels,omitempty"`
 }
 
 // ContractResponse is the response for getting a contract.
@@ -160,6 +286,8 @@ type ContractResponse struct {
 	SourcePath        string            `json:"sourcePath"`
 	Chain             string            `json:"chain"`
 	License           string            `json:"license"`
+	Labels            []string          `json:"labels,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
 	CompilationTarget map[string]string `json:"compilationTarget,omitempty"`
 	Compiler          *CompilerInfoResp `json:"compiler,omitempty"`
 }
@@ -178,11 +306,75 @@ type OptimizerInfoResp struct {
 	Runs    int  `json:"runs"`
 }
 
+// VerifyPayloadResponse is the explorer verification request built for a contract,
+// returned by the verify-payload dry run without being submitted.
+type VerifyPayloadResponse struct {
+	Target string            `json:"target"`
+	URL    string            `json:"url"`
+	Form   map[string]string `json:"form"`
+}
+
+// BytecodeMetadataResponse is the CBOR metadata decoded from a contract's bytecode.
+type BytecodeMetadataResponse struct {
+	Source       string `json:"source"`   // artifact it was read from: deployed-bytecode or bytecode
+	HashType     string `json:"hashType"` // ipfs, bzzr0, bzzr1 or none
+	Hash         string `json:"hash,omitempty"`
+	IPFSCID      string `json:"ipfsCid,omitempty"`
+	SolcVersion  string `json:"solcVersion,omitempty"` // absent before solc 0.5.9
+	Experimental bool   `json:"experimental"`
+	// DeclaredCompilerVersion is the compiler version the contract was published with;
+	// CompilerVersionMatches compares it to SolcVersion when both are known.
+	DeclaredCompilerVersion string `json:"declaredCompilerVersion,omitempty"`
+	CompilerVersionMatches  *bool  `json:"compilerVersionMatches,omitempty"`
+}
+
 // DeploymentsResponse is the response for getting package deployments.
 type DeploymentsResponse struct {
 	Deployments []DeploymentSummary `json:"deployments"`
 }
 
+// BytecodeLookupResponse is the response for looking up contracts by bytecode hash.
+type BytecodeLookupResponse struct {
+	Hash    string          `json:"hash"`
+	Matches []BytecodeMatch `json:"matches"`
+}
+
+// BytecodeMatch is a published contract matching a bytecode lookup.
+type BytecodeMatch struct {
+	Package   string `json:"package"`
+	Version   string `json:"version"`
+	Contract  string `json:"contract"`
+	Chain     string `json:"chain"`
+	MatchedOn string `json:"matchedOn"`
+}
+
+// TagsResponse lists every package tag in use.
+type TagsResponse struct {
+	Tags []TagCount `json:"tags"`
+}
+
+// TagCount is a package tag and how many packages have a version carrying it.
+type TagCount struct {
+	Tag      string `json:"tag"`
+	Packages int    `json:"packages"`
+}
+
+// SelectorLookupResponse is the response for looking up contracts by ABI selector.
+type SelectorLookupResponse struct {
+	Selector string          `json:"selector"`
+	Matches  []SelectorMatch `json:"matches"`
+}
+
+// SelectorMatch is a published contract whose ABI has a looked-up selector.
+type SelectorMatch struct {
+	Package   string `json:"package"`
+	Version   string `json:"version"`
+	Contract  string `json:"contract"`
+	Chain     string `json:"chain"`
+	Type      string `json:"type"`
+	Signature string `json:"signature"`
+}
+
 // ErrorResponse is the standard error response format.
 type ErrorResponse struct {
 	Error ErrorDetail `json:"error"`
@@ -190,6 +382,8 @@ type ErrorResponse struct {
 
 // ErrorDetail contains error information.
 type ErrorDetail struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code      string                  `json:"code"`
+	Message   string                  `json:"message"`
+	Fields    []validation.FieldError `json:"fields,omitempty"` // set for VALIDATION_ERROR
+	RequestID string                  `json:"requestId,omitempty"`
 }
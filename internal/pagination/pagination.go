@@ -0,0 +1,46 @@
+// Package pagination parses the limit and cursor query parameters shared by the
+// list endpoints, so every endpoint applies the same defaults and cap.
+package pagination
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// DefaultLimit is the page size when the request doesn't set limit.
+	DefaultLimit = 20
+	// DefaultMaxLimit caps limit when the server doesn't configure a maximum.
+	DefaultMaxLimit = 100
+)
+
+// ErrInvalidLimit is returned for a limit that isn't a positive integer.
+var ErrInvalidLimit = errors.New("limit must be a positive integer")
+
+// Params are the pagination parameters of a list request.
+type Params struct {
+	Limit  int
+	Cursor string
+}
+
+// Parse reads limit and cursor from the request's query. A missing limit is
+// DefaultLimit and one above maxLimit is lowered to it; maxLimit <= 0 means
+// DefaultMaxLimit.
+func Parse(r *http.Request, maxLimit int) (Params, error) {
+	if maxLimit <= 0 {
+		maxLimit = DefaultMaxLimit
+	}
+	p := Params{
+		Limit:  min(DefaultLimit, maxLimit),
+		Cursor: r.URL.Query().Get("cursor"),
+	}
+	if l := r.URL.Query().Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed <= 0 {
+			return Params{}, ErrInvalidLimit
+		}
+		p.Limit = min(parsed, maxLimit)
+	}
+	return p, nil
+}
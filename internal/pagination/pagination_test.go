@@ -0,0 +1,40 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		maxLimit int
+		want     Params
+		wantErr  bool
+	}{
+		{name: "defaults", query: "", want: Params{Limit: DefaultLimit}},
+		{name: "limit and cursor", query: "limit=50&cursor=abc", want: Params{Limit: 50, Cursor: "abc"}},
+		{name: "capped at default max", query: "limit=500", want: Params{Limit: DefaultMaxLimit}},
+		{name: "capped at configured max", query: "limit=500", maxLimit: 250, want: Params{Limit: 250}},
+		{name: "default lowered to a small max", query: "", maxLimit: 10, want: Params{Limit: 10}},
+		{name: "non-numeric", query: "limit=ten", wantErr: true},
+		{name: "zero", query: "limit=0", wantErr: true},
+		{name: "negative", query: "limit=-5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(httptest.NewRequest("GET", "/?"+tt.query, nil), tt.maxLimit)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidLimit)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+// Package provenance signs and verifies published artifacts, so consumers can check
+// that a contract's bytecode and ABI come from the holder of a publisher's key.
+//
+// A signature covers a digest of the contract's interface and code: the ABI and
+// creation bytecode for EVM contracts, the IDL and program binary for Solana
+// programs. Keys are ed25519.
+package provenance
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Algorithm is the only signature algorithm supported so far.
+const Algorithm = "ed25519"
+
+// ErrInvalidSignature is returned when a signature does not verify.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Signature is a signed digest of an artifact, as stored in its "signature" artifact.
+type Signature struct {
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"publicKey"` // base64 of the raw 32-byte ed25519 key
+	Signature string `json:"signature"` // base64
+	Digest    string `json:"digest"`    // "sha256:<hex>", see Digest
+}
+
+// domainSeparator prefixes the signed message so signatures can't be replayed as
+// signatures over something else.
+const domainSeparator = "contrafactory-artifact-signature-v1\n"
+
+// Digest returns the digest a signature covers, as "sha256:<hex>". The ABI is
+// compacted first, since JSON encoders (including the publish request's) are free to
+// re-indent it; code is taken as published.
+func Digest(abi, code []byte) (string, error) {
+	var compact bytes.Buffer
+	if len(abi) > 0 {
+		if err := json.Compact(&compact, abi); err != nil {
+			return "", fmt.Errorf("ABI is not valid JSON: %w", err)
+		}
+	}
+	abiHash := sha256.Sum256(compact.Bytes())
+	codeHash := sha256.Sum256(code)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "abi sha256:%x\ncode sha256:%x\n", abiHash, codeHash)
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Sign signs the digest of abi and code with key.
+func Sign(key ed25519.PrivateKey, abi, code []byte) (*Signature, error) {
+	digest, err := Digest(abi, code)
+	if err != nil {
+		return nil, err
+	}
+	sig := ed25519.Sign(key, []byte(domainSeparator+digest))
+	return &Signature{
+		Algorithm: Algorithm,
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		Signature: base64.StdEncoding.EncodeToString(sig),
+		Digest:    digest,
+	}, nil
+}
+
+// Verify checks that sig is a valid signature by its public key over abi and code.
+func Verify(sig *Signature, abi, code []byte) error {
+	if sig.Algorithm != Algorithm {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidSignature, sig.Algorithm)
+	}
+	pub, err := ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: signature is not base64", ErrInvalidSignature)
+	}
+
+	digest, err := Digest(abi, code)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if sig.Digest != "" && sig.Digest != digest {
+		return fmt.Errorf("%w: signed digest %s does not match the artifact (%s)", ErrInvalidSignature, sig.Digest, digest)
+	}
+	if !ed25519.Verify(pub, []byte(domainSeparator+digest), raw) {
+		return fmt.Errorf("%w: signature does not match the ABI and bytecode", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// ParsePublicKey parses a base64 raw ed25519 public key, or a PEM "PUBLIC KEY" block.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing public key: %w", err)
+		}
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key is %T, not ed25519", key)
+		}
+		return pub, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("public key is neither PEM nor base64")
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key is %d bytes, want %d", len(raw), ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// ParsePrivateKeyPEM parses a PEM "PRIVATE KEY" (PKCS #8) ed25519 key, as written by
+// `openssl genpkey -algorithm ed25519`.
+func ParsePrivateKeyPEM(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key: %w", err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is %T, not ed25519", key)
+	}
+	return priv, nil
+}
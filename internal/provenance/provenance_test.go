@@ -0,0 +1,91 @@
+package provenance
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	abi := []byte(`[{"type":"function","name":"transfer"}]`)
+	code := []byte("0x6080604052")
+
+	sig, err := Sign(key, abi, code)
+	require.NoError(t, err)
+	assert.Equal(t, Algorithm, sig.Algorithm)
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, sig.Digest)
+	require.NoError(t, Verify(sig, abi, code))
+
+	t.Run("ABI formatting doesn't matter", func(t *testing.T) {
+		indented := []byte("[\n  {\"type\": \"function\", \"name\": \"transfer\"}\n]")
+		assert.NoError(t, Verify(sig, indented, code))
+	})
+
+	t.Run("tampered bytecode", func(t *testing.T) {
+		assert.ErrorIs(t, Verify(sig, abi, []byte("0x6080604053")), ErrInvalidSignature)
+	})
+
+	t.Run("tampered ABI", func(t *testing.T) {
+		assert.ErrorIs(t, Verify(sig, []byte(`[]`), code), ErrInvalidSignature)
+	})
+
+	t.Run("another key", func(t *testing.T) {
+		other, _, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		forged := *sig
+		forged.PublicKey = base64.StdEncoding.EncodeToString(other)
+		assert.ErrorIs(t, Verify(&forged, abi, code), ErrInvalidSignature)
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		rsa := *sig
+		rsa.Algorithm = "rsa"
+		assert.ErrorIs(t, Verify(&rsa, abi, code), ErrInvalidSignature)
+	})
+}
+
+func TestDigest(t *testing.T) {
+	a, err := Digest([]byte(`[ ]`), []byte("0x00"))
+	require.NoError(t, err)
+	b, err := Digest([]byte(`[]`), []byte("0x00"))
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+
+	_, err = Digest([]byte(`[`), nil)
+	assert.Error(t, err)
+}
+
+func TestParseKeys(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	parsed, err := ParsePrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	_, err = ParsePrivateKeyPEM([]byte("not a key"))
+	assert.Error(t, err)
+
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	fromPEM, err := ParsePublicKey(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})))
+	require.NoError(t, err)
+	assert.True(t, pub.Equal(fromPEM))
+
+	fromBase64, err := ParsePublicKey(base64.StdEncoding.EncodeToString(pub))
+	require.NoError(t, err)
+	assert.True(t, pub.Equal(fromBase64))
+
+	_, err = ParsePublicKey(base64.StdEncoding.EncodeToString(pub[:16]))
+	assert.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pendergraft/contrafactory/internal/auth"
+	"github.com/pendergraft/contrafactory/internal/server/errcodes"
+)
+
+// cacheInvalidator evicts entries from the package read cache.
+type cacheInvalidator interface {
+	Invalidate(name, version string) int
+	InvalidateAll() int
+}
+
+// CacheInvalidateRequest selects the cache entries to evict: a package version,
+// every version of a package (version omitted), or everything.
+type CacheInvalidateRequest struct {
+	Package string `json:"package,omitempty"`
+	Version string `json:"version,omitempty"`
+	All     bool   `json:"all,omitempty"`
+}
+
+// CacheInvalidateResponse reports how many cache entries were evicted.
+type CacheInvalidateResponse struct {
+	Invalidated int `json:"invalidated"`
+}
+
+// handleCacheInvalidate evicts read-cache entries. Mirrors expose this so the
+// primary can keep their caches coherent after a publish or delete. Flushing the
+// cache is an admin operation, so it is refused without an admin key.
+func (s *Server) handleCacheInvalidate(w http.ResponseWriter, r *http.Request) {
+	if key := auth.GetAPIKeyFromContext(r.Context()); key == nil || !key.IsAdmin() {
+		writeError(w, http.StatusForbidden, errcodes.Forbidden, "Cache invalidation requires an admin API key")
+		return
+	}
+
+	var req CacheInvalidateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Invalid JSON")
+		return
+	}
+	if !req.All && req.Package == "" {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "package or all is required")
+		return
+	}
+	if req.All && (req.Package != "" || req.Version != "") {
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "all cannot be combined with package or version")
+		return
+	}
+
+	// Nothing to evict when caching is disabled
+	if s.cache == nil {
+		writeJSON(w, http.StatusOK, CacheInvalidateResponse{})
+		return
+	}
+
+	var n int
+	if req.All {
+		n = s.cache.InvalidateAll()
+	} else {
+		n = s.cache.Invalidate(req.Package, req.Version)
+	}
+	s.logger.Info("cache invalidated", "package", req.Package, "version", req.Version, "all", req.All, "entries", n)
+	writeJSON(w, http.StatusOK, CacheInvalidateResponse{Invalidated: n})
+}
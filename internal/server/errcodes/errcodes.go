@@ -0,0 +1,34 @@
+// Package errcodes defines the error codes the API returns in error responses
+// ({"error": {"code": ..., "message": ...}}). The server and pkg/client share
+// them so both sides agree on the wire values.
+package errcodes
+
+// Error codes. The values are part of the API and must not change.
+const (
+	// Request errors
+	InvalidRequest   = "INVALID_REQUEST"
+	ValidationError  = "VALIDATION_ERROR" // a well-formed body with missing or invalid fields; see error.fields
+	BadRequest       = "BAD_REQUEST"      // rejected by the request filter before routing
+	InvalidVersion   = "INVALID_VERSION"
+	InvalidLabel     = "INVALID_LABEL"
+	InvalidABI       = "INVALID_ABI"
+	InvalidSignature = "INVALID_SIGNATURE" // an artifact signature that doesn't verify
+
+	// Auth errors
+	Unauthorized = "UNAUTHORIZED"
+	Forbidden    = "FORBIDDEN"
+
+	// Resource errors
+	NotFound      = "NOT_FOUND"
+	VersionExists = "VERSION_EXISTS"
+	NoMetadata    = "NO_METADATA"
+	NotVerifiable = "NOT_VERIFIABLE"
+
+	// Limits
+	QuotaExceeded     = "QUOTA_EXCEEDED"
+	TooManyArtifacts  = "TOO_MANY_ARTIFACTS"
+	RateLimitExceeded = "RATE_LIMIT_EXCEEDED"
+
+	// Server errors
+	InternalError = "INTERNAL_ERROR"
+)
@@ -6,18 +6,27 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
 	"github.com/pendergraft/contrafactory/internal/auth"
 	"github.com/pendergraft/contrafactory/internal/chains"
+	"github.com/pendergraft/contrafactory/internal/chains/evm"
+	"github.com/pendergraft/contrafactory/internal/chains/networks"
+	"github.com/pendergraft/contrafactory/internal/chains/solana"
 	"github.com/pendergraft/contrafactory/internal/config"
 	deploymentsDomain "github.com/pendergraft/contrafactory/internal/deployments/domain"
 	deploymentsTransport "github.com/pendergraft/contrafactory/internal/deployments/transport"
+	"github.com/pendergraft/contrafactory/internal/graphql"
+	"github.com/pendergraft/contrafactory/internal/jsonnum"
+	"github.com/pendergraft/contrafactory/internal/middleware/cors"
 	"github.com/pendergraft/contrafactory/internal/middleware/logging"
 	"github.com/pendergraft/contrafactory/internal/middleware/ratelimit"
 	"github.com/pendergraft/contrafactory/internal/middleware/realip"
+	"github.com/pendergraft/contrafactory/internal/middleware/requestid"
 	"github.com/pendergraft/contrafactory/internal/middleware/security"
 	"github.com/pendergraft/contrafactory/internal/observability/metrics"
 	packagesDomain "github.com/pendergraft/contrafactory/internal/packages/domain"
@@ -38,27 +47,75 @@ type Server struct {
 	packagesSvc     packagesTransport.Service
 	deploymentsSvc  deploymentsTransport.Service
 	verificationSvc verificationTransport.Service
+
+	// Read cache for package data; nil when caching is disabled
+	cache cacheInvalidator
+
+	// version is the server build version reported by /health
+	version string
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithVersion sets the build version the server reports
+func WithVersion(version string) Option {
+	return func(s *Server) {
+		s.version = version
+	}
 }
 
 // New creates a new server
-func New(cfg *config.Config, store storage.Store, logger *slog.Logger) *Server {
+func New(cfg *config.Config, store storage.Store, logger *slog.Logger, opts ...Option) *Server {
 	s := &Server{
 		cfg:    cfg,
 		store:  store,
 		logger: logger,
 		router: chi.NewRouter(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
 
 	// Create chain registry
 	registry := chains.NewRegistry()
+	registry.Register(evm.NewChain())
+	registry.Register(solana.NewChain())
 
 	// Create domain services
-	pkgImpl := packagesDomain.NewService(store, store)
+	pkgImpl := packagesDomain.NewService(store, store,
+		packagesDomain.WithMaxArtifactsPerPublish(cfg.Limits.MaxArtifactsPerPublish),
+		packagesDomain.WithMaxOwnerArtifactBytes(int64(cfg.Limits.MaxOwnerStorageMB)<<20),
+		packagesDomain.WithMaxVersionsPerPackage(cfg.Limits.MaxVersionsPerPackage),
+		packagesDomain.WithCompilerPolicy(packagesDomain.CompilerPolicy{
+			AllowedVersions:    cfg.Compilers.AllowedVersions,
+			DeniedVersions:     cfg.Compilers.DeniedVersions,
+			AllowedEVMVersions: cfg.Compilers.AllowedEVMVersions,
+			DeniedEVMVersions:  cfg.Compilers.DeniedEVMVersions,
+		}),
+	)
 	deployImpl := deploymentsDomain.NewService(store, store)
-	verifyImpl := verificationDomain.NewService(store, store, registry)
-
-	// Wrap packages service with logging middleware
-	pkgSvc := packagesDomain.LoggingMiddleware(logger)(pkgImpl)
+	verifyOpts := []verificationDomain.Option{
+		verificationDomain.WithRPCTimeout(time.Duration(cfg.Verify.RPCTimeoutSeconds) * time.Second),
+	}
+	if cfg.Cache.Enabled {
+		verifyOpts = append(verifyOpts, verificationDomain.WithResultCache(
+			time.Duration(cfg.Cache.TTLSeconds)*time.Second,
+			int64(cfg.Cache.MaxSizeMB)*1024*1024,
+		))
+	}
+	verifyImpl := verificationDomain.NewService(store, store, registry, verifyOpts...)
+
+	// Wrap packages service with the read cache (if enabled) and logging middleware
+	var pkgSvc packagesTransport.Service = packagesDomain.LoggingMiddleware(logger)(pkgImpl)
+	if cfg.Cache.Enabled {
+		cache := packagesDomain.CachingMiddleware(
+			time.Duration(cfg.Cache.TTLSeconds)*time.Second,
+			int64(cfg.Cache.MaxSizeMB)*1024*1024,
+		)(pkgImpl)
+		s.cache = cache
+		pkgSvc = packagesDomain.LoggingMiddleware(logger)(cache)
+	}
 	s.packagesSvc = pkgSvc
 	s.deploymentsSvc = deployImpl
 	s.verificationSvc = verifyImpl
@@ -82,6 +139,9 @@ func (s *Server) MetricsHandler() http.Handler {
 func (s *Server) setupMiddleware() {
 	// Order matters! Security middleware runs first to block malicious requests early.
 
+	// 0. Request ID, so every response (including rejections below) can be correlated with logs
+	s.router.Use(requestid.Middleware(s.logger))
+
 	// 1. Real IP extraction (must be first to set client IP for other middleware)
 	s.router.Use(realip.Middleware(realip.Config{
 		TrustProxy:     s.cfg.Proxy.TrustProxy,
@@ -103,25 +163,10 @@ func (s *Server) setupMiddleware() {
 	}))
 
 	// 5. Standard middleware
-	s.router.Use(middleware.RequestID)
 	s.router.Use(logging.Middleware(s.logger))
 	s.router.Use(metrics.Middleware)
 	s.router.Use(middleware.Recoverer)
 	s.router.Use(middleware.Compress(5))
-
-	// 6. CORS
-	s.router.Use(func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Accept, Authorization, Content-Type, X-API-Key")
-			if r.Method == "OPTIONS" {
-				w.WriteHeader(http.StatusOK)
-				return
-			}
-			next.ServeHTTP(w, r)
-		})
-	})
 }
 
 func (s *Server) setupRoutes() {
@@ -141,6 +186,10 @@ func (s *Server) setupRoutes() {
 	// Wire up deployments lister to packages handler for version deployments endpoint
 	packagesHandler.SetDeploymentLister(&deploymentListerAdapter{svc: s.deploymentsSvc})
 
+	// Cap list page sizes at the configured maximum
+	packagesHandler.SetMaxPageSize(s.cfg.Limits.MaxPageSize)
+	deploymentsHandler.SetMaxPageSize(s.cfg.Limits.MaxPageSize)
+
 	// Auth middleware for write operations
 	requireAuth := func(r chi.Router) {
 		if s.cfg.Auth.Type == "api-key" {
@@ -150,6 +199,22 @@ func (s *Server) setupRoutes() {
 
 	// API v1 routes
 	s.router.Route("/api/v1", func(r chi.Router) {
+		// CORS for browser clients - off unless origins are configured. Runs before
+		// routing so preflight OPTIONS requests are answered for every path.
+		r.Use(cors.Middleware(cors.Config{
+			AllowedOrigins:   s.cfg.CORS.AllowedOrigins,
+			AllowedMethods:   s.cfg.CORS.AllowedMethods,
+			AllowedHeaders:   s.cfg.CORS.AllowedHeaders,
+			AllowCredentials: s.cfg.CORS.AllowCredentials,
+			MaxAgeSeconds:    s.cfg.CORS.MaxAgeSeconds,
+		}))
+
+		// Server limits - lets clients check requests before sending
+		r.Get("/limits", s.handleLimits)
+
+		// Known EVM networks - chain IDs, names and explorers
+		r.Get("/networks", s.handleNetworks)
+
 		// Packages - split read/write
 		r.Route("/packages", func(r chi.Router) {
 			// Read operations - no auth required
@@ -174,14 +239,105 @@ func (s *Server) setupRoutes() {
 			})
 		})
 
+		// GraphQL - read only (no auth), off unless enabled
+		if s.cfg.GraphQL.Enabled {
+			gqlHandler := graphql.NewHandler(s.packagesSvc, s.deploymentsSvc)
+			r.Get("/graphql", gqlHandler.ServeHTTP)
+			r.Post("/graphql", gqlHandler.ServeHTTP)
+		}
+
+		// Reverse lookups and tags - read only (no auth)
+		packagesHandler.RegisterLookupRoutes(r)
+		packagesHandler.RegisterTagRoutes(r)
+
 		// Verification - read only (no auth)
 		verificationHandler.RegisterRoutes(r)
+
+		// Batch publish, the caller's packages, cache administration and key
+		// introspection - auth required
+		r.Group(func(r chi.Router) {
+			requireAuth(r)
+			packagesHandler.RegisterBatchRoutes(r)
+			packagesHandler.RegisterAccountRoutes(r)
+			r.Post("/cache/invalidate", s.handleCacheInvalidate)
+			r.Get("/whoami", s.handleWhoAmI)
+		})
 	})
 }
 
 // Health check handler
+// HealthResponse is the response of the health checks
+type HealthResponse struct {
+	Status  string `json:"status"`
+	Version string `json:"version,omitempty"`
+}
+
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	writeJSON(w, http.StatusOK, HealthResponse{Status: "ok", Version: s.version})
+}
+
+// LimitsResponse describes server-enforced request limits.
+type LimitsResponse struct {
+	MaxArtifactsPerPublish int `json:"maxArtifactsPerPublish"` // 0 = unlimited
+	MaxBodySizeMB          int `json:"maxBodySizeMB"`
+	MaxOwnerStorageMB      int `json:"maxOwnerStorageMB"`     // 0 = unlimited
+	MaxVersionsPerPackage  int `json:"maxVersionsPerPackage"` // 0 = unlimited
+}
+
+// handleLimits reports the server's request limits.
+func (s *Server) handleLimits(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, LimitsResponse{
+		MaxArtifactsPerPublish: s.cfg.Limits.MaxArtifactsPerPublish,
+		MaxBodySizeMB:          s.cfg.Security.MaxBodySizeMB,
+		MaxOwnerStorageMB:      s.cfg.Limits.MaxOwnerStorageMB,
+		MaxVersionsPerPackage:  s.cfg.Limits.MaxVersionsPerPackage,
+	})
+}
+
+// NetworksResponse lists the EVM networks the server knows.
+type NetworksResponse struct {
+	Data []networks.Network `json:"data"`
+}
+
+// handleNetworks lists the known EVM networks. Deployments on other chain IDs are
+// accepted, but get no network name or explorer links.
+func (s *Server) handleNetworks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, NetworksResponse{Data: networks.All()})
+}
+
+// WhoAmIResponse describes the API key a request authenticated with.
+type WhoAmIResponse struct {
+	AuthEnabled bool     `json:"authEnabled"` // false when the server doesn't require keys
+	ID          string   `json:"id,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Scopes      []string `json:"scopes"`
+	CreatedAt   string   `json:"createdAt,omitempty"`
+}
+
+// handleWhoAmI reports the calling API key, so clients can check a key before saving it.
+func (s *Server) handleWhoAmI(w http.ResponseWriter, r *http.Request) {
+	key := auth.GetAPIKeyFromContext(r.Context())
+	if key == nil {
+		writeJSON(w, http.StatusOK, WhoAmIResponse{Scopes: []string{}})
+		return
+	}
+
+	// Scopes are stored as a map; report the granted ones by name
+	scopes := []string{}
+	for scope, v := range key.Scopes {
+		if granted, ok := v.(bool); !ok || granted {
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+
+	writeJSON(w, http.StatusOK, WhoAmIResponse{
+		AuthEnabled: true,
+		ID:          key.ID,
+		Name:        key.Name,
+		Scopes:      scopes,
+		CreatedAt:   key.CreatedAt,
+	})
 }
 
 // handleOpenAPISpec serves the OpenAPI specification.
@@ -200,12 +356,14 @@ func writeJSON(w http.ResponseWriter, status int, data any) {
 func writeError(w http.ResponseWriter, status int, code, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]any{
-		"error": map[string]any{
-			"code":    code,
-			"message": message,
-		},
-	})
+	detail := map[string]any{
+		"code":    code,
+		"message": message,
+	}
+	if id := w.Header().Get(requestid.Header); id != "" {
+		detail["requestId"] = id
+	}
+	json.NewEncoder(w).Encode(map[string]any{"error": detail})
 }
 
 // deploymentLister adapts the deployments service for listing by package
@@ -232,6 +390,7 @@ func (a *deploymentListerAdapter) ListByPackage(ctx context.Context, packageName
 			ContractName: s.ContractName,
 			Verified:     s.Verified,
 			TxHash:       s.TxHash,
+			BlockNumber:  jsonnum.New(s.BlockNumber, false),
 		}
 	}
 	return result, nil
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/config"
+	"github.com/pendergraft/contrafactory/internal/storage"
+)
+
+func newTestServer(t *testing.T, cfg *config.Config) (http.Handler, storage.Store) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store, err := storage.NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { store.Close() })
+	require.NoError(t, store.Migrate(context.Background()))
+	return New(cfg, store, logger).Handler(), store
+}
+
+func TestServer_CORS(t *testing.T) {
+	cfg, err := config.LoadFile("")
+	require.NoError(t, err)
+	cfg.CORS.AllowedOrigins = []string{"https://ui.example.com"}
+	h, _ := newTestServer(t, cfg)
+
+	t.Run("unconfigured origin gets no CORS headers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/packages", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("unconfigured origin preflight is not allowed", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/packages", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("configured origin preflight", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/packages", nil)
+		req.Header.Set("Origin", "https://ui.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://ui.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "GET, HEAD", rec.Header().Get("Access-Control-Allow-Methods"))
+	})
+
+	t.Run("configured origin request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/packages", nil)
+		req.Header.Set("Origin", "https://ui.example.com")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "https://ui.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestServer_CacheInvalidateRequiresAdmin(t *testing.T) {
+	invalidate := func(h http.Handler, key string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/cache/invalidate", strings.NewReader(`{"all":true}`))
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	t.Run("open server", func(t *testing.T) {
+		cfg, err := config.LoadFile("")
+		require.NoError(t, err)
+		cfg.Cache.Enabled = true
+		h, _ := newTestServer(t, cfg)
+
+		assert.Equal(t, http.StatusForbidden, invalidate(h, ""))
+	})
+
+	t.Run("api keys", func(t *testing.T) {
+		cfg, err := config.LoadFile("")
+		require.NoError(t, err)
+		cfg.Auth.Type = "api-key"
+		cfg.Cache.Enabled = true
+		h, store := newTestServer(t, cfg)
+
+		ctx := context.Background()
+		userKey, err := store.CreateAPIKey(ctx, "ci", nil)
+		require.NoError(t, err)
+		adminKey, err := store.CreateAPIKey(ctx, "ops", map[string]any{storage.ScopeAdmin: true})
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, invalidate(h, ""))
+		assert.Equal(t, http.StatusForbidden, invalidate(h, userKey))
+		assert.Equal(t, http.StatusOK, invalidate(h, adminKey))
+	})
+}
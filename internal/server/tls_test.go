@@ -0,0 +1,36 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsPort int
+		host      string
+		target    string
+		want      string
+	}{
+		{"default port", 443, "registry.example.com", "/api/v1/packages?limit=5", "https://registry.example.com/api/v1/packages?limit=5"},
+		{"strips the HTTP port", 443, "registry.example.com:80", "/health", "https://registry.example.com/health"},
+		{"custom port", 8443, "localhost:8081", "/api/v1/packages", "https://localhost:8443/api/v1/packages"},
+		{"IPv6", 443, "[::1]:8081", "/", "https://[::1]/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			req.Host = tt.host
+			rec := httptest.NewRecorder()
+			HTTPSRedirectHandler(tt.httpsPort).ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusPermanentRedirect, rec.Code)
+			assert.Equal(t, tt.want, rec.Header().Get("Location"))
+		})
+	}
+}
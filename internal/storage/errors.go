@@ -7,4 +7,5 @@ var (
 	ErrNotFound      = errors.New("not found")
 	ErrVersionExists = errors.New("version already exists")
 	ErrImmutable     = errors.New("version is immutable")
+	ErrInvalidCursor = errors.New("invalid cursor")
 )
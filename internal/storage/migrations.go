@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+)
+
+// migration is a single numbered schema change. Versions must be unique and
+// increasing; once released, a migration must never be edited, only followed
+// by a new one. Both stores share the numbering: a version is the same change
+// on SQLite and on Postgres, as a no-op where a store doesn't need it.
+type migration struct {
+	version     int
+	description string
+	up          func(ctx context.Context, tx *sql.Tx) error
+}
+
+// migrationDialect holds the store-specific SQL used by the migration runner.
+type migrationDialect struct {
+	createTable string // creates schema_migrations if missing
+	insert      string // records an applied version (version, description)
+}
+
+// runMigrations applies all pending migrations in version order. Each migration
+// runs in its own transaction together with its schema_migrations record, so a
+// failed migration leaves no partial state behind and is retried on next start.
+func runMigrations(ctx context.Context, db *sql.DB, dialect migrationDialect, migrations []migration, logger *slog.Logger) error {
+	last := 0
+	for _, m := range migrations {
+		if m.version <= last {
+			return fmt.Errorf("migration %d is out of order", m.version)
+		}
+		last = m.version
+	}
+
+	if _, err := db.ExecContext(ctx, dialect.createTable); err != nil {
+		return fmt.Errorf("creating schema_migrations table: %w", err)
+	}
+
+	applied, err := appliedMigrations(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("starting migration %d: %w", m.version, err)
+		}
+		if err := m.up(ctx, tx); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("applying migration %d (%s): %w", m.version, m.description, err)
+		}
+		if _, err := tx.ExecContext(ctx, dialect.insert, m.version, m.description); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("recording migration %d: %w", m.version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("committing migration %d: %w", m.version, err)
+		}
+
+		logger.Info("applied migration", "version", m.version, "description", m.description)
+	}
+
+	return nil
+}
+
+// backfillSelectors returns a migration func indexing the selectors of ABIs published
+// before the selectors table existed. insert adds a (contract_id, selector,
+// entry_type, signature) row, ignoring rows already present.
+func backfillSelectors(insert string) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		type abi struct {
+			contractID string
+			content    []byte
+		}
+		// Read everything first: the transaction's connection is busy until rows close
+		rows, err := tx.QueryContext(ctx, "SELECT contract_id, content FROM artifacts WHERE artifact_type = 'abi'")
+		if err != nil {
+			return fmt.Errorf("reading ABIs: %w", err)
+		}
+		var abis []abi
+		for rows.Next() {
+			var a abi
+			if err := rows.Scan(&a.contractID, &a.content); err != nil {
+				rows.Close()
+				return fmt.Errorf("scanning ABI: %w", err)
+			}
+			abis = append(abis, a)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("reading ABIs: %w", err)
+		}
+
+		for _, a := range abis {
+			for _, sel := range SelectorsFromABI(a.content) {
+				if _, err := tx.ExecContext(ctx, insert, a.contractID, sel.Selector, sel.Type, sel.Signature); err != nil {
+					return fmt.Errorf("indexing selector %s of contract %s: %w", sel.Selector, a.contractID, err)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// appliedMigrations returns the set of versions recorded in schema_migrations.
+func appliedMigrations(ctx context.Context, db *sql.DB) (map[int]bool, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("reading schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, fmt.Errorf("scanning schema_migrations: %w", err)
+		}
+		applied[v] = true
+	}
+	return applied, rows.Err()
+}
+
+// execStatements returns a migration func that executes the given SQL.
+func execStatements(stmts string) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, stmts)
+		return err
+	}
+}
@@ -0,0 +1,361 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestSQLiteDB(t *testing.T) *SQLiteStore {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), logger)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+var sqliteTestDialect = migrationDialect{
+	createTable: "CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, description TEXT NOT NULL)",
+	insert:      "INSERT INTO schema_migrations (version, description) VALUES (?, ?)",
+}
+
+func TestSQLiteMigrateRecordsVersions(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	// Running again must be a no-op
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("second Migrate() error = %v", err)
+	}
+
+	applied, err := appliedMigrations(ctx, store.db.DB)
+	if err != nil {
+		t.Fatalf("appliedMigrations() error = %v", err)
+	}
+	if len(applied) != len(sqliteMigrations) {
+		t.Errorf("applied %d migrations, want %d", len(applied), len(sqliteMigrations))
+	}
+	for _, m := range sqliteMigrations {
+		if !applied[m.version] {
+			t.Errorf("migration %d not recorded", m.version)
+		}
+	}
+}
+
+func TestSQLiteMigrateLegacyDatabase(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+
+	// A database created before the project column and schema_migrations existed
+	if _, err := store.db.ExecContext(ctx, `CREATE TABLE packages (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		version TEXT NOT NULL,
+		chain TEXT NOT NULL,
+		builder TEXT,
+		compiler_version TEXT,
+		compiler_settings TEXT,
+		metadata TEXT,
+		created_at TEXT DEFAULT (datetime('now')),
+		UNIQUE(name, version)
+	)`); err != nil {
+		t.Fatalf("creating legacy table: %v", err)
+	}
+
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	exists, err := sqliteColumnExists(ctx, tx, "packages", "project")
+	if err != nil {
+		t.Fatalf("sqliteColumnExists() error = %v", err)
+	}
+	if !exists {
+		t.Error("expected packages.project to be added")
+	}
+}
+
+func TestSQLiteMigrateHashPrefix(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	// Rows written before hashes carried their algorithm
+	if err := runMigrations(ctx, store.db.DB, sqliteTestDialect, sqliteMigrations[:4], logger); err != nil {
+		t.Fatalf("runMigrations() error = %v", err)
+	}
+	if err := store.CreatePackage(ctx, &Package{ID: "p1", Name: "tokens", Version: "1.0.0", Chain: "evm"}); err != nil {
+		t.Fatalf("CreatePackage: %v", err)
+	}
+	legacy := HashContent([]byte("0x6001"))[len("sha256:"):]
+	if _, err := store.db.ExecContext(ctx, "INSERT INTO contracts (id, package_id, name, chain, source_path, primary_hash) VALUES ('c1', 'p1', 'Token', 'evm', '', ?)", legacy); err != nil {
+		t.Fatalf("inserting legacy contract: %v", err)
+	}
+	if _, err := store.db.ExecContext(ctx, "INSERT INTO artifacts (id, contract_id, artifact_type, content_hash, content, size_bytes) VALUES ('a1', 'c1', 'deployed-bytecode', ?, '0x6001', 6)", legacy); err != nil {
+		t.Fatalf("inserting legacy artifact: %v", err)
+	}
+
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	matches, err := store.FindContractsByHash(ctx, HashContent([]byte("0x6001")))
+	if err != nil {
+		t.Fatalf("FindContractsByHash() error = %v", err)
+	}
+	if len(matches) != 2 {
+		t.Errorf("FindContractsByHash() = %+v, want bytecode and deployed-bytecode matches", matches)
+	}
+}
+
+func TestSQLiteMigrateBackfillsSelectors(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	// An ABI published before selectors were indexed
+	if err := runMigrations(ctx, store.db.DB, sqliteTestDialect, sqliteMigrations[:5], logger); err != nil {
+		t.Fatalf("runMigrations() error = %v", err)
+	}
+	if err := store.CreatePackage(ctx, &Package{ID: "p1", Name: "tokens", Version: "1.0.0", Chain: "evm"}); err != nil {
+		t.Fatalf("CreatePackage: %v", err)
+	}
+	if _, err := store.db.ExecContext(ctx, "INSERT INTO contracts (id, package_id, name, chain, source_path, primary_hash) VALUES ('c1', 'p1', 'Token', 'evm', '', '')"); err != nil {
+		t.Fatalf("inserting contract: %v", err)
+	}
+	if err := store.StoreArtifact(ctx, "c1", "abi", []byte(`[{"type":"function","name":"totalSupply","inputs":[]}]`)); err != nil {
+		t.Fatalf("StoreArtifact: %v", err)
+	}
+
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	matches, err := store.FindContractsBySelector(ctx, "0x18160ddd")
+	if err != nil {
+		t.Fatalf("FindContractsBySelector() error = %v", err)
+	}
+	if len(matches) != 1 || matches[0].Signature != "totalSupply()" {
+		t.Errorf("FindContractsBySelector() = %+v, want Token's totalSupply()", matches)
+	}
+}
+
+func TestSQLiteMigrateLowercasesDeploymentAddresses(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	// Deployments recorded before EVM addresses were stored lowercase: a checksummed
+	// one, the same deployment recorded again lowercase, and a Solana program
+	if err := runMigrations(ctx, store.db.DB, sqliteTestDialect, sqliteMigrations[:13], logger); err != nil {
+		t.Fatalf("runMigrations() error = %v", err)
+	}
+	if err := store.CreatePackage(ctx, &Package{ID: "p1", Name: "tokens", Version: "1.0.0", Chain: "evm"}); err != nil {
+		t.Fatalf("CreatePackage: %v", err)
+	}
+	const checksummed = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	const program = "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"
+	for _, d := range []*Deployment{
+		{ID: "d1", PackageID: "p1", ContractName: "Old", Chain: "evm", ChainID: "1", Address: checksummed, TxHash: "0xabc", BlockNumber: 42},
+		{ID: "d2", PackageID: "p1", ContractName: "New", Chain: "evm", ChainID: "1", Address: strings.ToLower(checksummed)},
+		{ID: "d3", PackageID: "p1", ContractName: "Program", Chain: "solana", ChainID: "mainnet-beta", Address: program},
+	} {
+		if err := store.RecordDeployment(ctx, d); err != nil {
+			t.Fatalf("RecordDeployment(%s): %v", d.ID, err)
+		}
+	}
+	// Only the older recording was verified
+	if err := store.UpdateVerificationStatus(ctx, "d1", true, []string{"etherscan"}); err != nil {
+		t.Fatalf("UpdateVerificationStatus: %v", err)
+	}
+	if _, err := store.db.ExecContext(ctx, "UPDATE deployments SET created_at = '2024-01-01 00:00:00' WHERE id = 'd1'"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	d, err := store.GetDeployment(ctx, "evm", "1", strings.ToLower(checksummed))
+	if err != nil {
+		t.Fatalf("GetDeployment() error = %v", err)
+	}
+	if d.ContractName != "New" {
+		t.Errorf("kept %q, want the most recently recorded deployment", d.ContractName)
+	}
+	if !d.Verified || !reflect.DeepEqual(d.VerifiedOn, []string{"etherscan"}) {
+		t.Errorf("verified = %v on %v, want the removed duplicate's verification merged in", d.Verified, d.VerifiedOn)
+	}
+	if d.TxHash != "0xabc" || d.BlockNumber != 42 {
+		t.Errorf("tx = %q in block %d, want the removed duplicate's transaction merged in", d.TxHash, d.BlockNumber)
+	}
+	var count int
+	if err := store.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM deployments WHERE chain = 'evm'").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("%d EVM deployments left, want the duplicate removed", count)
+	}
+
+	events, err := store.ListDeploymentEvents(ctx, "evm", "1", strings.ToLower(checksummed))
+	if err != nil {
+		t.Fatalf("ListDeploymentEvents() error = %v", err)
+	}
+	if len(events) != 3 {
+		t.Errorf("ListDeploymentEvents() = %+v, want both recordings' events and the verification", events)
+	}
+
+	if _, err := store.GetDeployment(ctx, "solana", "mainnet-beta", program); err != nil {
+		t.Errorf("Solana addresses are case-sensitive and must be kept: %v", err)
+	}
+}
+
+func TestMigrationVersionsMatchAcrossStores(t *testing.T) {
+	// A schema_migrations version must name the same change on every store
+	if len(sqliteMigrations) != len(postgresMigrations) {
+		t.Fatalf("%d SQLite migrations, %d Postgres migrations", len(sqliteMigrations), len(postgresMigrations))
+	}
+	for i, m := range sqliteMigrations {
+		pg := postgresMigrations[i]
+		if m.version != pg.version || m.description != pg.description {
+			t.Errorf("SQLite migration %d %q, Postgres migration %d %q", m.version, m.description, pg.version, pg.description)
+		}
+	}
+}
+
+func TestRunMigrations(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	t.Run("applies pending in order", func(t *testing.T) {
+		store := newTestSQLiteDB(t)
+
+		var order []int
+		record := func(v int) func(context.Context, *sql.Tx) error {
+			return func(context.Context, *sql.Tx) error {
+				order = append(order, v)
+				return nil
+			}
+		}
+
+		first := []migration{{version: 1, description: "one", up: record(1)}}
+		if err := runMigrations(ctx, store.db.DB, sqliteTestDialect, first, logger); err != nil {
+			t.Fatalf("runMigrations() error = %v", err)
+		}
+
+		all := append(first,
+			migration{version: 2, description: "two", up: record(2)},
+			migration{version: 3, description: "three", up: record(3)},
+		)
+		if err := runMigrations(ctx, store.db.DB, sqliteTestDialect, all, logger); err != nil {
+			t.Fatalf("runMigrations() error = %v", err)
+		}
+
+		want := []int{1, 2, 3}
+		if len(order) != len(want) {
+			t.Fatalf("ran %v, want %v", order, want)
+		}
+		for i := range want {
+			if order[i] != want[i] {
+				t.Fatalf("ran %v, want %v", order, want)
+			}
+		}
+	})
+
+	t.Run("failed migration is not recorded", func(t *testing.T) {
+		store := newTestSQLiteDB(t)
+
+		migrations := []migration{
+			{version: 1, description: "create", up: execStatements("CREATE TABLE t1 (id INTEGER)")},
+			{version: 2, description: "broken", up: func(context.Context, *sql.Tx) error { return errors.New("boom") }},
+		}
+		if err := runMigrations(ctx, store.db.DB, sqliteTestDialect, migrations, logger); err == nil {
+			t.Fatal("expected error from failing migration")
+		}
+
+		applied, err := appliedMigrations(ctx, store.db.DB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !applied[1] || applied[2] {
+			t.Errorf("applied = %v, want only version 1", applied)
+		}
+	})
+
+	t.Run("rejects out of order versions", func(t *testing.T) {
+		store := newTestSQLiteDB(t)
+
+		noop := func(context.Context, *sql.Tx) error { return nil }
+		migrations := []migration{
+			{version: 2, description: "two", up: noop},
+			{version: 1, description: "one", up: noop},
+		}
+		if err := runMigrations(ctx, store.db.DB, sqliteTestDialect, migrations, logger); err == nil {
+			t.Fatal("expected error for out of order migrations")
+		}
+	})
+}
+
+func TestSQLiteMigrateBackfillsDeploymentEvents(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	// Deployments recorded and verified before the timeline existed
+	if err := runMigrations(ctx, store.db.DB, sqliteTestDialect, sqliteMigrations[:9], logger); err != nil {
+		t.Fatalf("runMigrations() error = %v", err)
+	}
+	for _, stmt := range []string{
+		`INSERT INTO deployments (id, contract_name, chain, chain_id, address, verified, verified_at, verified_on, created_at)
+		VALUES ('d1', 'Token', 'evm', '1', '0xaa', 1, '2025-01-02 00:00:00', '["etherscan","sourcify"]', '2025-01-01 00:00:00')`,
+		`INSERT INTO deployments (id, contract_name, chain, chain_id, address, verified, verified_at, created_at)
+		VALUES ('d2', 'Vault', 'evm', '1', '0xbb', 1, '2025-01-03 00:00:00', '2025-01-01 00:00:00')`,
+	} {
+		if _, err := store.db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("inserting legacy deployment: %v", err)
+		}
+	}
+
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	events, err := store.ListDeploymentEvents(ctx, "evm", "1", "0xaa")
+	if err != nil {
+		t.Fatalf("ListDeploymentEvents() error = %v", err)
+	}
+	want := []DeploymentEvent{
+		{Type: DeploymentEventDeployed, Details: map[string]string{"contract": "Token"}, CreatedAt: "2025-01-01 00:00:00"},
+		{Type: DeploymentEventVerified, Details: map[string]string{"explorer": "etherscan"}, CreatedAt: "2025-01-02 00:00:00"},
+		{Type: DeploymentEventVerified, Details: map[string]string{"explorer": "sourcify"}, CreatedAt: "2025-01-02 00:00:00"},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %+v, want %+v", events, want)
+	}
+
+	events, err = store.ListDeploymentEvents(ctx, "evm", "1", "0xbb")
+	if err != nil {
+		t.Fatalf("ListDeploymentEvents() error = %v", err)
+	}
+	if len(events) != 2 || events[1].Type != DeploymentEventVerified || events[1].Details != nil {
+		t.Errorf("events = %+v, want deployed then verified without an explorer", events)
+	}
+}
@@ -14,7 +14,7 @@ import (
 
 // PostgresStore implements Store using PostgreSQL
 type PostgresStore struct {
-	db     *sql.DB
+	db     *queryLogger
 	logger *slog.Logger
 }
 
@@ -29,7 +29,7 @@ func NewPostgresStore(url string, logger *slog.Logger) (*PostgresStore, error) {
 		return nil, fmt.Errorf("pinging database: %w", err)
 	}
 
-	return &PostgresStore{db: db, logger: logger}, nil
+	return &PostgresStore{db: newQueryLogger(db, logger), logger: logger}, nil
 }
 
 // Close closes the database connection
@@ -39,7 +39,37 @@ func (s *PostgresStore) Close() error {
 
 // Migrate runs database migrations
 func (s *PostgresStore) Migrate(ctx context.Context) error {
-	schema := `
+	dialect := migrationDialect{
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			description TEXT NOT NULL,
+			applied_at TIMESTAMPTZ DEFAULT NOW()
+		)`,
+		insert: "INSERT INTO schema_migrations (version, description) VALUES ($1, $2)",
+	}
+	if err := runMigrations(ctx, s.db.DB, dialect, postgresMigrations, s.logger); err != nil {
+		return fmt.Errorf("running migrations: %w", err)
+	}
+
+	s.logger.Info("database migrations complete")
+	return nil
+}
+
+// postgresMigrations is the ordered list of Postgres schema migrations.
+// api_keys is created first since package_owners references it.
+var postgresMigrations = []migration{
+	{version: 1, description: "initial schema", up: execStatements(`
+	-- API keys
+	CREATE TABLE IF NOT EXISTS api_keys (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		key_hash TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL,
+		scopes JSONB,
+		created_at TIMESTAMPTZ DEFAULT NOW(),
+		last_used_at TIMESTAMPTZ,
+		revoked_at TIMESTAMPTZ
+	);
+
 	-- Package ownership
 	CREATE TABLE IF NOT EXISTS package_owners (
 		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -108,17 +138,6 @@ func (s *PostgresStore) Migrate(ctx context.Context) error {
 		UNIQUE(chain, chain_id, address)
 	);
 
-	-- API keys (created first since package_owners references it)
-	CREATE TABLE IF NOT EXISTS api_keys (
-		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-		key_hash TEXT NOT NULL UNIQUE,
-		name TEXT NOT NULL,
-		scopes JSONB,
-		created_at TIMESTAMPTZ DEFAULT NOW(),
-		last_used_at TIMESTAMPTZ,
-		revoked_at TIMESTAMPTZ
-	);
-
 	-- Blobs
 	CREATE TABLE IF NOT EXISTS blobs (
 		hash TEXT PRIMARY KEY,
@@ -133,38 +152,201 @@ func (s *PostgresStore) Migrate(ctx context.Context) error {
 	CREATE INDEX IF NOT EXISTS idx_contracts_primary_hash ON contracts(primary_hash);
 	CREATE INDEX IF NOT EXISTS idx_deployments_lookup ON deployments(chain, chain_id, address);
 	CREATE INDEX IF NOT EXISTS idx_artifacts_content_hash ON artifacts(content_hash);
-	`
+	`)},
+	{version: 2, description: "add packages.project", up: execStatements("ALTER TABLE packages ADD COLUMN IF NOT EXISTS project TEXT")},
+	{version: 3, description: "add contract_labels", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS contract_labels (
+		contract_id UUID NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+		label TEXT NOT NULL,
+		PRIMARY KEY (contract_id, label)
+	);
+	CREATE INDEX IF NOT EXISTS idx_contract_labels_label ON contract_labels(label);
+	`)},
+	{version: 4, description: "add package_owner_transfers", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS package_owner_transfers (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		package_name TEXT NOT NULL,
+		from_key_id UUID,
+		to_key_id UUID NOT NULL,
+		created_at TIMESTAMPTZ DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_package_owner_transfers_name ON package_owner_transfers(package_name);
+	`)},
+	{version: 5, description: "prefix content hashes with their algorithm", up: execStatements(`
+	UPDATE contracts SET primary_hash = 'sha256:' || primary_hash WHERE primary_hash NOT LIKE 'sha256:%';
+	UPDATE artifacts SET content_hash = 'sha256:' || content_hash WHERE content_hash NOT LIKE 'sha256:%';
+	`)},
+	{version: 6, description: "add selectors", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS selectors (
+		contract_id UUID NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+		selector TEXT NOT NULL,
+		entry_type TEXT NOT NULL,
+		signature TEXT NOT NULL,
+		PRIMARY KEY (contract_id, entry_type, selector)
+	);
+	CREATE INDEX IF NOT EXISTS idx_selectors_selector ON selectors(selector);
+	`)},
+	{version: 7, description: "index selectors of published ABIs", up: backfillSelectors(postgresInsertSelector)},
+	{version: 8, description: "add contracts.metadata", up: execStatements("ALTER TABLE contracts ADD COLUMN IF NOT EXISTS metadata JSONB")},
+	{version: 9, description: "add package_collaborators", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS package_collaborators (
+		package_name TEXT NOT NULL,
+		key_id UUID NOT NULL REFERENCES api_keys(id),
+		created_at TIMESTAMPTZ DEFAULT NOW(),
+		PRIMARY KEY (package_name, key_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_package_collaborators_key ON package_collaborators(key_id);
+	`)},
+	// Serves metadata @> containment filters; SQLite has no equivalent and scans
+	{version: 10, description: "index packages.metadata", up: execStatements("CREATE INDEX IF NOT EXISTS idx_packages_metadata ON packages USING GIN (metadata jsonb_path_ops)")},
+	{version: 11, description: "add deployment_events", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS deployment_events (
+		id BIGSERIAL PRIMARY KEY,
+		chain TEXT NOT NULL,
+		chain_id TEXT NOT NULL,
+		address TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		details JSONB,
+		created_at TIMESTAMPTZ DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_deployment_events_lookup ON deployment_events(chain, chain_id, address);
 
-	// Need to create api_keys first since package_owners references it
-	_, err := s.db.ExecContext(ctx, `
-		CREATE TABLE IF NOT EXISTS api_keys (
-			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-			key_hash TEXT NOT NULL UNIQUE,
-			name TEXT NOT NULL,
-			scopes JSONB,
-			created_at TIMESTAMPTZ DEFAULT NOW(),
-			last_used_at TIMESTAMPTZ,
-			revoked_at TIMESTAMPTZ
-		);
-	`)
+	-- Start existing deployments' timelines from what the deployments table knows
+	INSERT INTO deployment_events (chain, chain_id, address, event_type, details, created_at)
+	SELECT chain, chain_id, address, 'deployed', jsonb_build_object('contract', contract_name), COALESCE(created_at, NOW())
+	FROM deployments ORDER BY created_at;
+	INSERT INTO deployment_events (chain, chain_id, address, event_type, details, created_at)
+	SELECT d.chain, d.chain_id, d.address, 'verified', jsonb_build_object('explorer', e.explorer), COALESCE(d.verified_at, d.created_at)
+	FROM deployments d, unnest(d.verified_on) AS e(explorer) WHERE d.verified;
+	INSERT INTO deployment_events (chain, chain_id, address, event_type, created_at)
+	SELECT chain, chain_id, address, 'verified', COALESCE(verified_at, created_at)
+	FROM deployments WHERE verified AND COALESCE(cardinality(verified_on), 0) = 0;
+	`)},
+	{version: 12, description: "add package_aliases", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS package_aliases (
+		package_name TEXT NOT NULL,
+		alias TEXT NOT NULL,
+		version TEXT NOT NULL,
+		updated_at TIMESTAMPTZ DEFAULT NOW(),
+		PRIMARY KEY (package_name, alias)
+	);
+	`)},
+	{version: 13, description: "add package_tags", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS package_tags (
+		package_id UUID NOT NULL REFERENCES packages(id) ON DELETE CASCADE,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (package_id, tag)
+	);
+	CREATE INDEX IF NOT EXISTS idx_package_tags_tag ON package_tags(tag);
+	`)},
+	{version: 14, description: "lowercase EVM deployment addresses", up: execStatements(`
+	-- EVM addresses are stored lowercase since checksums are validated on record.
+	-- Where older rows differ only in case, keep the most recently recorded one,
+	-- first merging into it the verification and transaction details of the others
+	-- so that collapsing the duplicates loses nothing.
+	UPDATE deployments d SET
+		verified = (SELECT bool_or(COALESCE(o.verified, FALSE)) FROM deployments o
+			WHERE o.chain = d.chain AND o.chain_id = d.chain_id AND lower(o.address) = lower(d.address)),
+		verified_at = COALESCE((SELECT MIN(o.verified_at) FROM deployments o
+			WHERE o.chain = d.chain AND o.chain_id = d.chain_id AND lower(o.address) = lower(d.address)
+				AND o.verified), d.verified_at),
+		verified_on = COALESCE((SELECT array_agg(DISTINCT e ORDER BY e) FROM deployments o, unnest(o.verified_on) AS e
+			WHERE o.chain = d.chain AND o.chain_id = d.chain_id AND lower(o.address) = lower(d.address)), d.verified_on),
+		tx_hash = COALESCE(NULLIF(d.tx_hash, ''), (SELECT o.tx_hash FROM deployments o
+			WHERE o.chain = d.chain AND o.chain_id = d.chain_id AND lower(o.address) = lower(d.address)
+				AND COALESCE(o.tx_hash, '') <> '' ORDER BY o.created_at DESC LIMIT 1), d.tx_hash),
+		deployer_address = COALESCE(NULLIF(d.deployer_address, ''), (SELECT o.deployer_address FROM deployments o
+			WHERE o.chain = d.chain AND o.chain_id = d.chain_id AND lower(o.address) = lower(d.address)
+				AND COALESCE(o.deployer_address, '') <> '' ORDER BY o.created_at DESC LIMIT 1), d.deployer_address),
+		block_number = COALESCE(NULLIF(d.block_number, 0), (SELECT o.block_number FROM deployments o
+			WHERE o.chain = d.chain AND o.chain_id = d.chain_id AND lower(o.address) = lower(d.address)
+				AND COALESCE(o.block_number, 0) <> 0 ORDER BY o.created_at DESC LIMIT 1), d.block_number)
+	WHERE d.chain <> 'solana' AND EXISTS (
+		SELECT 1 FROM deployments o
+		WHERE o.chain = d.chain AND o.chain_id = d.chain_id
+			AND lower(o.address) = lower(d.address) AND o.id <> d.id
+	) AND NOT EXISTS (
+		SELECT 1 FROM deployments n
+		WHERE n.chain = d.chain AND n.chain_id = d.chain_id
+			AND lower(n.address) = lower(d.address) AND n.id <> d.id
+			AND (n.created_at > d.created_at OR (n.created_at = d.created_at AND n.id > d.id))
+	);
+	DELETE FROM deployments WHERE chain <> 'solana' AND EXISTS (
+		SELECT 1 FROM deployments n
+		WHERE n.chain = deployments.chain AND n.chain_id = deployments.chain_id
+			AND lower(n.address) = lower(deployments.address) AND n.id <> deployments.id
+			AND (n.created_at > deployments.created_at OR (n.created_at = deployments.created_at AND n.id > deployments.id))
+	);
+	UPDATE deployments SET address = lower(address) WHERE chain <> 'solana' AND address <> lower(address);
+	UPDATE deployment_events SET address = lower(address) WHERE chain <> 'solana' AND address <> lower(address);
+	`)},
+}
+
+// postgresInsertDeploymentEvent appends to a deployment's timeline.
+const postgresInsertDeploymentEvent = "INSERT INTO deployment_events (chain, chain_id, address, event_type, details) VALUES ($1, $2, $3, $4, $5)"
+
+// postgresInsertSelector indexes one ABI selector of a contract.
+const postgresInsertSelector = "INSERT INTO selectors (contract_id, selector, entry_type, signature) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING"
+
+// CreatePackage creates a new package
+func (s *PostgresStore) CreatePackage(ctx context.Context, pkg *Package) error {
+	// The version and its tags are stored together, so a failed tag insert doesn't
+	// leave a published version behind that a retry would collide with
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("creating api_keys table: %w", err)
+		return err
 	}
+	defer tx.Rollback()
 
-	_, err = s.db.ExecContext(ctx, schema)
+	if err := s.insertPackage(ctx, tx, pkg); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CreatePackageVersions stores each version with its tags, contracts and artifacts,
+// and claims unowned package names, in one transaction: either every version is
+// stored or none is.
+func (s *PostgresStore) CreatePackageVersions(ctx context.Context, versions []PackageVersion) error {
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("running migrations: %w", err)
+		return err
 	}
+	defer tx.Rollback()
 
-	// Add project column if it doesn't exist
-	_, _ = s.db.ExecContext(ctx, "ALTER TABLE packages ADD COLUMN IF NOT EXISTS project TEXT")
+	for _, v := range versions {
+		if err := s.insertPackageVersion(ctx, tx, v); err != nil {
+			return fmt.Errorf("storing %s@%s: %w", v.Package.Name, v.Package.Version, err)
+		}
+	}
+	return tx.Commit()
+}
 
-	s.logger.Info("database migrations complete")
+// insertPackageVersion writes one version of a CreatePackageVersions call.
+func (s *PostgresStore) insertPackageVersion(ctx context.Context, db execer, v PackageVersion) error {
+	if err := s.insertPackage(ctx, db, v.Package); err != nil {
+		return err
+	}
+	if v.OwnerKeyID != "" {
+		if err := s.insertPackageOwner(ctx, db, v.Package.Name, v.OwnerKeyID); err != nil {
+			return fmt.Errorf("claiming package: %w", err)
+		}
+	}
+	for _, c := range v.Contracts {
+		if err := s.insertContract(ctx, db, v.Package.ID, c.Contract); err != nil {
+			return fmt.Errorf("creating contract %s: %w", c.Contract.Name, err)
+		}
+		for _, a := range c.Artifacts {
+			if err := s.insertArtifact(ctx, db, c.Contract.ID, a.Type, a.Content); err != nil {
+				return fmt.Errorf("storing %s for %s: %w", a.Type, c.Contract.Name, err)
+			}
+		}
+	}
 	return nil
 }
 
-// CreatePackage creates a new package
-func (s *PostgresStore) CreatePackage(ctx context.Context, pkg *Package) error {
+// insertPackage writes a package row and its tags.
+func (s *PostgresStore) insertPackage(ctx context.Context, db execer, pkg *Package) error {
 	// Serialize metadata as JSONB
 	var metadataJSON []byte
 	if len(pkg.Metadata) > 0 {
@@ -193,8 +375,15 @@ func (s *PostgresStore) CreatePackage(ctx context.Context, pkg *Package) error {
 		INSERT INTO packages (id, name, version, project, chain, builder, compiler_version, compiler_settings, metadata)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
-	_, err := s.db.ExecContext(ctx, query, pkg.ID, pkg.Name, pkg.Version, nullIfEmpty(pkg.Project), pkg.Chain, pkg.Builder, pkg.CompilerVersion, compilerSettingsJSON, metadataJSON)
-	return err
+	if _, err := db.ExecContext(ctx, query, pkg.ID, pkg.Name, pkg.Version, nullIfEmpty(pkg.Project), pkg.Chain, pkg.Builder, pkg.CompilerVersion, compilerSettingsJSON, metadataJSON); err != nil {
+		return err
+	}
+	for _, tag := range pkg.Tags {
+		if _, err := db.ExecContext(ctx, "INSERT INTO package_tags (package_id, tag) VALUES ($1, $2) ON CONFLICT DO NOTHING", pkg.ID, tag); err != nil {
+			return fmt.Errorf("storing tag %q: %w", tag, err)
+		}
+	}
+	return nil
 }
 
 // GetPackage retrieves a package by name and version
@@ -238,11 +427,18 @@ func (s *PostgresStore) GetPackage(ctx context.Context, name, version string) (*
 		}
 	}
 
+	tags, err := queryPackageTags(ctx, s.db, "SELECT tag FROM package_tags WHERE package_id = $1 ORDER BY tag", pkg.ID)
+	if err != nil {
+		return nil, fmt.Errorf("reading tags: %w", err)
+	}
+	pkg.Tags = tags
+
 	pkg.CreatedAt = createdAt.Format("2006-01-02 15:04:05")
 	return &pkg, nil
 }
 
-// GetPackageVersions retrieves all versions of a package
+// GetPackageVersions retrieves the versions of a package, newest first. Prereleases
+// are left out unless includePrerelease is set.
 func (s *PostgresStore) GetPackageVersions(ctx context.Context, name string, includePrerelease bool) ([]string, error) {
 	query := `SELECT version FROM packages WHERE name = $1 ORDER BY created_at DESC`
 	rows, err := s.db.QueryContext(ctx, query, name)
@@ -259,12 +455,40 @@ func (s *PostgresStore) GetPackageVersions(ctx context.Context, name string, inc
 		}
 		versions = append(versions, v)
 	}
-	return versions, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !includePrerelease {
+		versions = withoutPrereleases(versions)
+	}
+	return versions, nil
+}
+
+// GetPackageVersionDetails retrieves every version of a package with its build
+// details, newest first, prereleases included.
+func (s *PostgresStore) GetPackageVersionDetails(ctx context.Context, name string) ([]VersionDetail, error) {
+	query := `SELECT version, chain, builder, compiler_version, created_at FROM packages WHERE name = $1 ORDER BY created_at DESC`
+	rows, err := s.db.QueryContext(ctx, query, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var details []VersionDetail
+	for rows.Next() {
+		var d VersionDetail
+		var createdAt time.Time
+		if err := rows.Scan(&d.Version, &d.Chain, &d.Builder, &d.CompilerVersion, &createdAt); err != nil {
+			return nil, err
+		}
+		d.CreatedAt = createdAt.Format("2006-01-02 15:04:05")
+		details = append(details, d)
+	}
+	return details, rows.Err()
 }
 
 // ListPackages lists packages with filtering and pagination
 func (s *PostgresStore) ListPackages(ctx context.Context, filter PackageFilter, pagination PaginationParams) (*PaginatedResult[Package], error) {
-	var whereClauses []string
 	var args []any
 	argIdx := 1
 	addArg := func(v any) int {
@@ -290,28 +514,15 @@ func (s *PostgresStore) ListPackages(ctx context.Context, filter PackageFilter,
 		argIdx = 2
 	}
 
-	if pagination.Cursor != "" {
-		whereClauses = append(whereClauses, fmt.Sprintf("%sname > $%d", tablePrefix, addArg(pagination.Cursor)))
-	}
-	if filter.Query != "" {
-		whereClauses = append(whereClauses, fmt.Sprintf("%sname ILIKE $%d", tablePrefix, addArg("%"+filter.Query+"%")))
-	}
-	if filter.Chain != "" {
-		whereClauses = append(whereClauses, fmt.Sprintf("%schain = $%d", tablePrefix, addArg(filter.Chain)))
-	}
-	if filter.Project != "" {
-		whereClauses = append(whereClauses, fmt.Sprintf("%sproject = $%d", tablePrefix, addArg(filter.Project)))
-	}
-	if filter.Version != "" {
-		whereClauses = append(whereClauses, fmt.Sprintf("%sversion = $%d", tablePrefix, addArg(filter.Version)))
-	}
-
-	if filter.Contract != "" && len(whereClauses) > 0 {
-		baseQuery += " WHERE " + strings.Join(whereClauses, " AND ")
-	} else if filter.Contract == "" && len(whereClauses) > 0 {
+	whereClauses := buildPostgresListPackagesWhereClauses(addArg, filter, pagination, tablePrefix)
+	if len(whereClauses) > 0 {
 		baseQuery += " WHERE " + strings.Join(whereClauses, " AND ")
 	}
-	baseQuery += fmt.Sprintf(" GROUP BY %sname, %schain, %sbuilder ORDER BY %sname LIMIT $%d", tablePrefix, tablePrefix, tablePrefix, tablePrefix, addArg(pagination.Limit+1))
+	direction := "ASC"
+	if pagination.Before != "" {
+		direction = "DESC"
+	}
+	baseQuery += fmt.Sprintf(" GROUP BY %sname, %schain, %sbuilder ORDER BY %sname %s LIMIT $%d", tablePrefix, tablePrefix, tablePrefix, tablePrefix, direction, addArg(pagination.Limit+1))
 
 	rows, err := s.db.QueryContext(ctx, baseQuery, args...)
 	if err != nil {
@@ -342,22 +553,116 @@ func (s *PostgresStore) ListPackages(ctx context.Context, filter PackageFilter,
 		})
 	}
 
-	hasMore := len(packages) > pagination.Limit
-	var nextCursor string
-	if hasMore {
-		packages = packages[:pagination.Limit]
+	return pageResult(packages, pagination, packageName), rows.Err()
+}
+
+// buildPostgresListPackagesWhereClauses builds WHERE clauses for ListPackages and
+// CountPackages; addArg appends an argument and returns its $n placeholder index.
+func buildPostgresListPackagesWhereClauses(addArg func(any) int, filter PackageFilter, pagination PaginationParams, tablePrefix string) []string {
+	var whereClauses []string
+	if pagination.Cursor != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("%sname > $%d", tablePrefix, addArg(pagination.Cursor)))
+	}
+	if pagination.Before != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("%sname < $%d", tablePrefix, addArg(pagination.Before)))
+	}
+	if filter.Query != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("%sname ILIKE $%d", tablePrefix, addArg("%"+filter.Query+"%")))
+	}
+	if filter.Chain != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("%schain = $%d", tablePrefix, addArg(filter.Chain)))
+	}
+	if filter.Project != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("%sproject = $%d", tablePrefix, addArg(filter.Project)))
+	}
+	if filter.Version != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("%sversion = $%d", tablePrefix, addArg(filter.Version)))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		whereClauses = append(whereClauses, fmt.Sprintf("%screated_at >= $%d", tablePrefix, addArg(filter.CreatedAfter)))
+	}
+	if filter.Label != "" {
+		// Qualify the outer id explicitly; a bare "id" would bind to the subquery's contracts
+		outer := tablePrefix
+		if outer == "" {
+			outer = "packages."
+		}
+		whereClauses = append(whereClauses, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM contracts lc
+			INNER JOIN contract_labels cl ON cl.contract_id = lc.id
+			WHERE lc.package_id = %sid AND cl.label = $%d)`, outer, addArg(filter.Label)))
+	}
+	if filter.Tag != "" {
+		outer := tablePrefix
+		if outer == "" {
+			outer = "packages."
+		}
+		whereClauses = append(whereClauses, fmt.Sprintf("EXISTS (SELECT 1 FROM package_tags pt WHERE pt.package_id = %sid AND pt.tag = $%d)", outer, addArg(filter.Tag)))
+	}
+	if filter.Owner != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("EXISTS (SELECT 1 FROM package_owners po WHERE po.package_name = %sname AND po.owner_key_id = $%d)", tablePrefix, addArg(filter.Owner)))
+	}
+	if len(filter.MetadataFilters) > 0 {
+		// One containment test for all pairs, answered from idx_packages_metadata
+		contained, _ := json.Marshal(filter.MetadataFilters)
+		whereClauses = append(whereClauses, fmt.Sprintf("%smetadata @> $%d::jsonb", tablePrefix, addArg(string(contained))))
+	}
+	return whereClauses
+}
+
+// ListPackagesByOwner lists the packages whose name ownerKeyID owns, paginated like
+// ListPackages
+func (s *PostgresStore) ListPackagesByOwner(ctx context.Context, ownerKeyID string, pagination PaginationParams) (*PaginatedResult[Package], error) {
+	return s.ListPackages(ctx, PackageFilter{Owner: ownerKeyID}, pagination)
+}
+
+// CountPackages counts the distinct package names matching filter (as ListPackages
+// would return them across all pages)
+func (s *PostgresStore) CountPackages(ctx context.Context, filter PackageFilter) (int, error) {
+	var args []any
+	addArg := func(v any) int {
+		args = append(args, v)
+		return len(args)
 	}
-	if len(packages) > 0 {
-		nextCursor = packages[len(packages)-1].Name
+
+	tablePrefix := ""
+	query := `SELECT COUNT(DISTINCT name) FROM packages`
+	if filter.Contract != "" {
+		tablePrefix = "p."
+		query = fmt.Sprintf(`SELECT COUNT(DISTINCT p.name) FROM packages p
+			INNER JOIN contracts c ON c.package_id = p.id AND LOWER(c.name) = LOWER($%d)`, addArg(filter.Contract))
+	}
+	if whereClauses := buildPostgresListPackagesWhereClauses(addArg, filter, PaginationParams{}, tablePrefix); len(whereClauses) > 0 {
+		query += " WHERE " + strings.Join(whereClauses, " AND ")
 	}
 
-	return &PaginatedResult[Package]{Data: packages, HasMore: hasMore, NextCursor: nextCursor}, rows.Err()
+	var count int
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
 }
 
 // DeletePackage deletes a package
 func (s *PostgresStore) DeletePackage(ctx context.Context, name, version string) error {
-	_, err := s.db.ExecContext(ctx, "DELETE FROM packages WHERE name = $1 AND version = $2", name, version)
-	return err
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Aliases pointing at the version go with it
+	if _, err := tx.ExecContext(ctx, "DELETE FROM package_aliases WHERE package_name = $1 AND version = $2", name, version); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM packages WHERE name = $1 AND version = $2", name, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListPackageTags lists every tag in use with the number of package names that
+// have a version carrying it, by tag
+func (s *PostgresStore) ListPackageTags(ctx context.Context) ([]TagCount, error) {
+	return queryTagCounts(ctx, s.db)
 }
 
 // PackageExists checks if a package exists
@@ -381,43 +686,264 @@ func (s *PostgresStore) GetPackageOwner(ctx context.Context, name string) (strin
 	return ownerID.String, nil
 }
 
+// GetPackageOwnerInfo returns the key that owns a package name, or ErrNotFound when unowned
+func (s *PostgresStore) GetPackageOwnerInfo(ctx context.Context, name string) (*PackageOwner, error) {
+	var owner PackageOwner
+	var keyID, keyName sql.NullString
+	var createdAt time.Time
+	query := `
+		SELECT o.owner_key_id, k.name, o.created_at
+		FROM package_owners o LEFT JOIN api_keys k ON k.id = o.owner_key_id
+		WHERE o.package_name = $1`
+	err := s.db.QueryRowContext(ctx, query, name).Scan(&keyID, &keyName, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	owner.KeyID = keyID.String
+	owner.KeyName = keyName.String
+	owner.CreatedAt = createdAt.Format("2006-01-02 15:04:05")
+	return &owner, nil
+}
+
+// GetOwnerArtifactBytes returns the total size of all artifacts in packages owned by ownerKeyID
+func (s *PostgresStore) GetOwnerArtifactBytes(ctx context.Context, ownerKeyID string) (int64, error) {
+	var total int64
+	query := `
+		SELECT COALESCE(SUM(a.size_bytes), 0)
+		FROM artifacts a
+		JOIN contracts c ON c.id = a.contract_id
+		JOIN packages p ON p.id = c.package_id
+		JOIN package_owners o ON o.package_name = p.name
+		WHERE o.owner_key_id::text = $1`
+	err := s.db.QueryRowContext(ctx, query, ownerKeyID).Scan(&total)
+	return total, err
+}
+
 // SetPackageOwner sets the owner of a package (first-come-first-served)
 func (s *PostgresStore) SetPackageOwner(ctx context.Context, name, ownerKeyID string) error {
+	return s.insertPackageOwner(ctx, s.db, name, ownerKeyID)
+}
+
+// insertPackageOwner claims a package name for ownerKeyID unless it is already owned.
+func (s *PostgresStore) insertPackageOwner(ctx context.Context, db execer, name, ownerKeyID string) error {
 	query := `INSERT INTO package_owners (package_name, owner_key_id) VALUES ($1, $2) ON CONFLICT (package_name) DO NOTHING`
-	_, err := s.db.ExecContext(ctx, query, name, ownerKeyID)
+	_, err := db.ExecContext(ctx, query, name, ownerKeyID)
 	return err
 }
 
+// UpdatePackageOwner hands a package from fromKeyID to toKeyID and records the
+// transfer in package_owner_transfers. It returns ErrNotFound when the package is
+// not currently owned by fromKeyID.
+func (s *PostgresStore) UpdatePackageOwner(ctx context.Context, name, fromKeyID, toKeyID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE package_owners SET owner_key_id = $1 WHERE package_name = $2 AND owner_key_id::text = $3`, toKeyID, name, fromKeyID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO package_owner_transfers (package_name, from_key_id, to_key_id)
+		VALUES ($1, $2, $3)`, name, fromKeyID, toKeyID); err != nil {
+		return fmt.Errorf("recording transfer: %w", err)
+	}
+	return tx.Commit()
+}
+
+// IsAuthorized reports whether keyID may publish to and delete from package name:
+// the name is unowned, or keyID owns it or is one of its collaborators.
+func (s *PostgresStore) IsAuthorized(ctx context.Context, name, keyID string) (bool, error) {
+	var authorized bool
+	query := `
+		SELECT NOT EXISTS (SELECT 1 FROM package_owners WHERE package_name = $1)
+			OR EXISTS (SELECT 1 FROM package_owners WHERE package_name = $1 AND owner_key_id::text = $2)
+			OR EXISTS (SELECT 1 FROM package_collaborators WHERE package_name = $1 AND key_id::text = $2)`
+	err := s.db.QueryRowContext(ctx, query, name, keyID).Scan(&authorized)
+	return authorized, err
+}
+
+// AddPackageCollaborator lets keyID publish to package name. Adding an existing
+// collaborator is a no-op.
+func (s *PostgresStore) AddPackageCollaborator(ctx context.Context, name, keyID string) error {
+	query := `INSERT INTO package_collaborators (package_name, key_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	_, err := s.db.ExecContext(ctx, query, name, keyID)
+	return err
+}
+
+// RemovePackageCollaborator revokes keyID's access to package name. It returns
+// ErrNotFound when keyID is not a collaborator.
+func (s *PostgresStore) RemovePackageCollaborator(ctx context.Context, name, keyID string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM package_collaborators WHERE package_name = $1 AND key_id::text = $2`, name, keyID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListPackageCollaborators lists the collaborators of package name, oldest first
+func (s *PostgresStore) ListPackageCollaborators(ctx context.Context, name string) ([]PackageCollaborator, error) {
+	query := `
+		SELECT c.key_id, k.name, c.created_at
+		FROM package_collaborators c LEFT JOIN api_keys k ON k.id = c.key_id
+		WHERE c.package_name = $1
+		ORDER BY c.created_at, c.key_id`
+	rows, err := s.db.QueryContext(ctx, query, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collaborators []PackageCollaborator
+	for rows.Next() {
+		var c PackageCollaborator
+		var keyName sql.NullString
+		var createdAt time.Time
+		if err := rows.Scan(&c.KeyID, &keyName, &createdAt); err != nil {
+			return nil, err
+		}
+		c.KeyName = keyName.String
+		c.CreatedAt = createdAt.Format("2006-01-02 15:04:05")
+		collaborators = append(collaborators, c)
+	}
+	return collaborators, rows.Err()
+}
+
+// SetPackageAlias points alias at a version of package name, moving it if it exists.
+func (s *PostgresStore) SetPackageAlias(ctx context.Context, name, alias, version string) error {
+	query := `
+		INSERT INTO package_aliases (package_name, alias, version, updated_at) VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (package_name, alias) DO UPDATE SET version = EXCLUDED.version, updated_at = EXCLUDED.updated_at`
+	_, err := s.db.ExecContext(ctx, query, name, alias, version)
+	return err
+}
+
+// GetPackageAlias returns the version alias points at, or ErrNotFound.
+func (s *PostgresStore) GetPackageAlias(ctx context.Context, name, alias string) (string, error) {
+	var version string
+	err := s.db.QueryRowContext(ctx, `SELECT version FROM package_aliases WHERE package_name = $1 AND alias = $2`, name, alias).Scan(&version)
+	if err == sql.ErrNoRows {
+		return "", ErrNotFound
+	}
+	return version, err
+}
+
+// ListPackageAliases lists the aliases of package name, by alias
+func (s *PostgresStore) ListPackageAliases(ctx context.Context, name string) ([]PackageAlias, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT alias, version, updated_at FROM package_aliases WHERE package_name = $1 ORDER BY alias`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var aliases []PackageAlias
+	for rows.Next() {
+		var a PackageAlias
+		var updatedAt time.Time
+		if err := rows.Scan(&a.Alias, &a.Version, &updatedAt); err != nil {
+			return nil, err
+		}
+		a.UpdatedAt = updatedAt.Format("2006-01-02 15:04:05")
+		aliases = append(aliases, a)
+	}
+	return aliases, rows.Err()
+}
+
+// DeletePackageAlias removes an alias. It returns ErrNotFound when there is none.
+func (s *PostgresStore) DeletePackageAlias(ctx context.Context, name, alias string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM package_aliases WHERE package_name = $1 AND alias = $2`, name, alias)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // CreateContract creates a new contract
 func (s *PostgresStore) CreateContract(ctx context.Context, packageID string, contract *Contract) error {
+	return s.insertContract(ctx, s.db, packageID, contract)
+}
+
+// insertContract writes a contract row with its labels and selectors.
+func (s *PostgresStore) insertContract(ctx context.Context, db execer, packageID string, contract *Contract) error {
 	query := `
-		INSERT INTO contracts (id, package_id, name, chain, source_path, license, primary_hash, metadata_hash)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO contracts (id, package_id, name, chain, source_path, license, primary_hash, metadata_hash, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
-	_, err := s.db.ExecContext(ctx, query, contract.ID, packageID, contract.Name, contract.Chain, contract.SourcePath, contract.License, contract.PrimaryHash, contract.MetadataHash)
-	return err
+	metadata, err := encodeContractMetadata(contract.Metadata)
+	if err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, query, contract.ID, packageID, contract.Name, contract.Chain, contract.SourcePath, contract.License, contract.PrimaryHash, contract.MetadataHash, nullIfEmpty(metadata)); err != nil {
+		return err
+	}
+	for _, label := range contract.Labels {
+		if _, err := db.ExecContext(ctx, "INSERT INTO contract_labels (contract_id, label) VALUES ($1, $2) ON CONFLICT DO NOTHING", contract.ID, label); err != nil {
+			return fmt.Errorf("storing label %q: %w", label, err)
+		}
+	}
+	for _, sel := range contract.Selectors {
+		if _, err := db.ExecContext(ctx, postgresInsertSelector, contract.ID, sel.Selector, sel.Type, sel.Signature); err != nil {
+			return fmt.Errorf("storing selector %s: %w", sel.Selector, err)
+		}
+	}
+	return nil
 }
 
 // GetContract retrieves a contract
 func (s *PostgresStore) GetContract(ctx context.Context, packageID, contractName string) (*Contract, error) {
 	query := `
-		SELECT id, package_id, name, chain, source_path, license, primary_hash, metadata_hash, created_at
+		SELECT id, package_id, name, chain, source_path, license, primary_hash, metadata_hash, metadata, created_at
 		FROM contracts
 		WHERE package_id = $1 AND name = $2
 	`
 	var c Contract
+	var metadata []byte
 	err := s.db.QueryRowContext(ctx, query, packageID, contractName).Scan(
-		&c.ID, &c.PackageID, &c.Name, &c.Chain, &c.SourcePath, &c.License, &c.PrimaryHash, &c.MetadataHash, &c.CreatedAt,
+		&c.ID, &c.PackageID, &c.Name, &c.Chain, &c.SourcePath, &c.License, &c.PrimaryHash, &c.MetadataHash, &metadata, &c.CreatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
 	}
-	return &c, err
+	if err != nil {
+		return nil, err
+	}
+	if c.Metadata, err = decodeContractMetadata(metadata); err != nil {
+		// Log but don't fail - metadata is optional
+		s.logger.Warn("failed to deserialize contract metadata", "contract", c.ID, "error", err)
+	}
+
+	labels, err := queryContractLabels(ctx, s.db, "SELECT contract_id, label FROM contract_labels WHERE contract_id = $1 ORDER BY label", c.ID)
+	if err != nil {
+		return nil, err
+	}
+	c.Labels = labels[c.ID]
+	return &c, nil
 }
 
 // ListContracts lists all contracts in a package
 func (s *PostgresStore) ListContracts(ctx context.Context, packageID string) ([]Contract, error) {
-	query := `SELECT id, package_id, name, chain, source_path, license, primary_hash, metadata_hash, created_at FROM contracts WHERE package_id = $1`
+	query := `SELECT id, package_id, name, chain, source_path, license, primary_hash, metadata_hash, metadata, created_at FROM contracts WHERE package_id = $1`
 	rows, err := s.db.QueryContext(ctx, query, packageID)
 	if err != nil {
 		return nil, err
@@ -427,23 +953,47 @@ func (s *PostgresStore) ListContracts(ctx context.Context, packageID string) ([]
 	var contracts []Contract
 	for rows.Next() {
 		var c Contract
-		if err := rows.Scan(&c.ID, &c.PackageID, &c.Name, &c.Chain, &c.SourcePath, &c.License, &c.PrimaryHash, &c.MetadataHash, &c.CreatedAt); err != nil {
+		var metadata []byte
+		if err := rows.Scan(&c.ID, &c.PackageID, &c.Name, &c.Chain, &c.SourcePath, &c.License, &c.PrimaryHash, &c.MetadataHash, &metadata, &c.CreatedAt); err != nil {
 			return nil, err
 		}
+		var err error
+		if c.Metadata, err = decodeContractMetadata(metadata); err != nil {
+			s.logger.Warn("failed to deserialize contract metadata", "contract", c.ID, "error", err)
+		}
 		contracts = append(contracts, c)
 	}
-	return contracts, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	labels, err := queryContractLabels(ctx, s.db, `
+		SELECT cl.contract_id, cl.label FROM contract_labels cl
+		INNER JOIN contracts c ON c.id = cl.contract_id
+		WHERE c.package_id = $1 ORDER BY cl.label`, packageID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range contracts {
+		contracts[i].Labels = labels[contracts[i].ID]
+	}
+	return contracts, nil
 }
 
 // StoreArtifact stores an artifact
 func (s *PostgresStore) StoreArtifact(ctx context.Context, contractID, artifactType string, content []byte) error {
-	hash := computeHash(content)
+	return s.insertArtifact(ctx, s.db, contractID, artifactType, content)
+}
+
+// insertArtifact writes an artifact, replacing any earlier one of the same type.
+func (s *PostgresStore) insertArtifact(ctx context.Context, db execer, contractID, artifactType string, content []byte) error {
+	hash := HashContent(content)
 	query := `
 		INSERT INTO artifacts (id, contract_id, artifact_type, content_hash, content, size_bytes)
 		VALUES ($1, $2, $3, $4, $5, $6)
 		ON CONFLICT(contract_id, artifact_type) DO UPDATE SET content = EXCLUDED.content, content_hash = EXCLUDED.content_hash, size_bytes = EXCLUDED.size_bytes
 	`
-	_, err := s.db.ExecContext(ctx, query, generateID(), contractID, artifactType, hash, content, len(content))
+	_, err := db.ExecContext(ctx, query, generateID(), contractID, artifactType, hash, content, len(content))
 	return err
 }
 
@@ -467,16 +1017,72 @@ func (s *PostgresStore) GetArtifactByHash(ctx context.Context, hash string) ([]b
 	return content, err
 }
 
+// FindContractsByHash finds contracts whose primary hash (creation bytecode or
+// program binary) or deployed bytecode artifact hashes to hash
+func (s *PostgresStore) FindContractsByHash(ctx context.Context, hash string) ([]BytecodeMatch, error) {
+	query := `
+		SELECT p.name, p.version, c.name, c.chain, CASE WHEN c.chain = 'solana' THEN 'program' ELSE 'bytecode' END
+		FROM contracts c
+		INNER JOIN packages p ON p.id = c.package_id
+		WHERE c.primary_hash = $1
+		UNION
+		SELECT p.name, p.version, c.name, c.chain, a.artifact_type
+		FROM artifacts a
+		INNER JOIN contracts c ON c.id = a.contract_id
+		INNER JOIN packages p ON p.id = c.package_id
+		WHERE a.content_hash = $1 AND a.artifact_type = 'deployed-bytecode'
+		ORDER BY 1, 2, 3
+	`
+	rows, err := s.db.QueryContext(ctx, query, hash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []BytecodeMatch
+	for rows.Next() {
+		var m BytecodeMatch
+		if err := rows.Scan(&m.PackageName, &m.Version, &m.ContractName, &m.Chain, &m.MatchedOn); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// FindContractsBySelector finds contracts whose ABI has a function, event or error
+// with the given selector
+func (s *PostgresStore) FindContractsBySelector(ctx context.Context, selector string) ([]SelectorMatch, error) {
+	query := `
+		SELECT p.name, p.version, c.name, c.chain, sel.entry_type, sel.signature
+		FROM selectors sel
+		INNER JOIN contracts c ON c.id = sel.contract_id
+		INNER JOIN packages p ON p.id = c.package_id
+		WHERE sel.selector = $1
+		ORDER BY 1, 2, 3, 5
+	`
+	rows, err := s.db.QueryContext(ctx, query, selector)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []SelectorMatch
+	for rows.Next() {
+		var m SelectorMatch
+		if err := rows.Scan(&m.PackageName, &m.Version, &m.ContractName, &m.Chain, &m.Type, &m.Signature); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
 // RecordDeployment records a deployment
 func (s *PostgresStore) RecordDeployment(ctx context.Context, d *Deployment) error {
-	// Convert deployment_data map to JSON
-	deploymentData := "{}"
-	if len(d.DeploymentData) > 0 {
-		dataBytes, err := json.Marshal(d.DeploymentData)
-		if err != nil {
-			return fmt.Errorf("marshaling deployment data: %w", err)
-		}
-		deploymentData = string(dataBytes)
+	deploymentData, err := encodeDeploymentData(d.DeploymentData)
+	if err != nil {
+		return err
 	}
 
 	query := `
@@ -490,35 +1096,103 @@ func (s *PostgresStore) RecordDeployment(ctx context.Context, d *Deployment) err
 			block_number = EXCLUDED.block_number,
 			deployment_data = EXCLUDED.deployment_data
 	`
-	_, err := s.db.ExecContext(ctx, query, d.ID, d.PackageID, d.ContractName, d.Chain, d.ChainID, d.Address, d.DeployerAddress, d.TxHash, d.BlockNumber, deploymentData)
-	return err
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, query, d.ID, d.PackageID, d.ContractName, d.Chain, d.ChainID, d.Address, d.DeployerAddress, d.TxHash, d.BlockNumber, deploymentData); err != nil {
+		return err
+	}
+	if err := insertDeploymentEvents(ctx, tx, postgresInsertDeploymentEvent, d.Chain, d.ChainID, d.Address, deployedEvent(d)); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // GetDeployment retrieves a deployment
 func (s *PostgresStore) GetDeployment(ctx context.Context, chain, chainID, address string) (*Deployment, error) {
 	query := `
-		SELECT id, package_id, contract_name, chain, chain_id, address, deployer_address, tx_hash, block_number, verified, created_at
-		FROM deployments
-		WHERE chain = $1 AND chain_id = $2 AND address = $3
+		SELECT d.id, d.package_id, COALESCE(p.name, ''), COALESCE(p.version, ''), d.contract_name, d.chain, d.chain_id, d.address,
+			d.deployer_address, d.tx_hash, d.block_number, d.deployment_data, d.verified,
+			COALESCE(array_to_json(d.verified_on)::text, ''), d.created_at
+		FROM deployments d
+		LEFT JOIN packages p ON p.id = d.package_id
+		WHERE d.chain = $1 AND d.chain_id = $2 AND d.address = $3
 	`
 	var d Deployment
+	var deploymentData []byte
+	var verifiedOn string
 	var createdAt time.Time
 	err := s.db.QueryRowContext(ctx, query, chain, chainID, address).Scan(
-		&d.ID, &d.PackageID, &d.ContractName, &d.Chain, &d.ChainID, &d.Address, &d.DeployerAddress, &d.TxHash, &d.BlockNumber, &d.Verified, &createdAt,
+		&d.ID, &d.PackageID, &d.PackageName, &d.PackageVersion, &d.ContractName, &d.Chain, &d.ChainID, &d.Address, &d.DeployerAddress, &d.TxHash, &d.BlockNumber, &deploymentData, &d.Verified, &verifiedOn, &createdAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
 	}
-	if err == nil {
-		d.CreatedAt = createdAt.Format("2006-01-02 15:04:05")
+	if err != nil {
+		return nil, err
+	}
+	d.CreatedAt = createdAt.Format("2006-01-02 15:04:05")
+	if d.DeploymentData, err = decodeDeploymentData(deploymentData); err != nil {
+		return nil, err
+	}
+	if d.VerifiedOn, err = decodeVerifiedOn(verifiedOn); err != nil {
+		return nil, err
 	}
-	return &d, err
+	return &d, nil
 }
 
-// ListDeployments lists deployments
+// ListDeployments lists deployments, newest first. Pages are keyed by deployment ID.
 func (s *PostgresStore) ListDeployments(ctx context.Context, filter DeploymentFilter, pagination PaginationParams) (*PaginatedResult[Deployment], error) {
-	query := `SELECT id, package_id, contract_name, chain, chain_id, address, verified, created_at FROM deployments ORDER BY created_at DESC LIMIT $1`
-	rows, err := s.db.QueryContext(ctx, query, pagination.Limit+1)
+	var whereClauses []string
+	var args []any
+	addArg := func(v any) int {
+		args = append(args, v)
+		return len(args)
+	}
+	if filter.Chain != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("d.chain = $%d", addArg(filter.Chain)))
+	}
+	if filter.ChainID != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("d.chain_id = $%d", addArg(filter.ChainID)))
+	}
+	if filter.Package != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("p.name = $%d", addArg(filter.Package)))
+	}
+	if filter.Verified != nil {
+		whereClauses = append(whereClauses, fmt.Sprintf("d.verified = $%d", addArg(*filter.Verified)))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		whereClauses = append(whereClauses, fmt.Sprintf("d.created_at >= $%d", addArg(filter.CreatedAfter)))
+	}
+	if pagination.Cursor != "" {
+		// The cursor is the ID of the last deployment on the previous page; continue
+		// after it in (created_at, id) order, since creation times can repeat.
+		// Compared as text so a malformed cursor is reported rather than a cast error.
+		var cursorID string
+		var cursorCreatedAt time.Time
+		err := s.db.QueryRowContext(ctx, "SELECT id, created_at FROM deployments WHERE id::text = $1", pagination.Cursor).Scan(&cursorID, &cursorCreatedAt)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: no deployment %q", ErrInvalidCursor, pagination.Cursor)
+		}
+		if err != nil {
+			return nil, err
+		}
+		at, id := addArg(cursorCreatedAt), addArg(cursorID)
+		whereClauses = append(whereClauses, fmt.Sprintf("(d.created_at < $%d OR (d.created_at = $%d AND d.id < $%d))", at, at, id))
+	}
+
+	query := `SELECT d.id, d.package_id, d.contract_name, d.chain, d.chain_id, d.address,
+			d.deployer_address, d.tx_hash, d.block_number, d.deployment_data, d.verified, d.created_at
+		FROM deployments d
+		LEFT JOIN packages p ON p.id = d.package_id`
+	if len(whereClauses) > 0 {
+		query += " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY d.created_at DESC, d.id DESC LIMIT $%d", addArg(pagination.Limit+1))
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -527,34 +1201,142 @@ func (s *PostgresStore) ListDeployments(ctx context.Context, filter DeploymentFi
 	var deployments []Deployment
 	for rows.Next() {
 		var d Deployment
+		var deploymentData []byte
 		var createdAt time.Time
-		if err := rows.Scan(&d.ID, &d.PackageID, &d.ContractName, &d.Chain, &d.ChainID, &d.Address, &d.Verified, &createdAt); err != nil {
+		if err := rows.Scan(&d.ID, &d.PackageID, &d.ContractName, &d.Chain, &d.ChainID, &d.Address, &d.DeployerAddress, &d.TxHash, &d.BlockNumber, &deploymentData, &d.Verified, &createdAt); err != nil {
 			return nil, err
 		}
 		d.CreatedAt = createdAt.Format("2006-01-02 15:04:05")
+		var err error
+		if d.DeploymentData, err = decodeDeploymentData(deploymentData); err != nil {
+			return nil, err
+		}
 		deployments = append(deployments, d)
 	}
 
+	// Only forward pages are supported, so no PrevCursor
 	hasMore := len(deployments) > pagination.Limit
 	if hasMore {
 		deployments = deployments[:pagination.Limit]
 	}
-
-	return &PaginatedResult[Deployment]{Data: deployments, HasMore: hasMore}, rows.Err()
+	result := &PaginatedResult[Deployment]{Data: deployments, HasMore: hasMore}
+	if len(deployments) > 0 {
+		result.NextCursor = deployments[len(deployments)-1].ID
+	}
+	return result, rows.Err()
 }
 
 // UpdateVerificationStatus updates a deployment's verification status
 func (s *PostgresStore) UpdateVerificationStatus(ctx context.Context, id string, verified bool, verifiedOn []string) error {
-	_, err := s.db.ExecContext(ctx, "UPDATE deployments SET verified = $1, verified_at = NOW() WHERE id = $2", verified, id)
-	return err
+	verifiedOnJSON, err := encodeVerifiedOn(verifiedOn)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Lock the row so concurrent updates see each other's explorers when diffing
+	var chain, chainID, address, wasVerifiedOnJSON string
+	var wasVerified bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT chain, chain_id, address, COALESCE(verified, FALSE), COALESCE(array_to_json(verified_on)::text, '')
+		FROM deployments WHERE id = $1 FOR UPDATE`, id).
+		Scan(&chain, &chainID, &address, &wasVerified, &wasVerifiedOnJSON)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	wasVerifiedOn, err := decodeVerifiedOn(wasVerifiedOnJSON)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE deployments
+		SET verified = $1, verified_at = NOW(), verified_on = ARRAY(SELECT jsonb_array_elements_text($2::jsonb))
+		WHERE id = $3
+	`, verified, verifiedOnJSON, id); err != nil {
+		return err
+	}
+	events := verificationEvents(wasVerified, wasVerifiedOn, verified, verifiedOn)
+	if err := insertDeploymentEvents(ctx, tx, postgresInsertDeploymentEvent, chain, chainID, address, events...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListDeploymentEvents returns the timeline of the deployment at chain/chainID/address,
+// oldest first
+func (s *PostgresStore) ListDeploymentEvents(ctx context.Context, chain, chainID, address string) ([]DeploymentEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT event_type, details, created_at FROM deployment_events
+		WHERE chain = $1 AND chain_id = $2 AND address = $3
+		ORDER BY id`, chain, chainID, address)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []DeploymentEvent
+	for rows.Next() {
+		var e DeploymentEvent
+		var details []byte
+		var createdAt time.Time
+		if err := rows.Scan(&e.Type, &details, &createdAt); err != nil {
+			return nil, err
+		}
+		e.CreatedAt = createdAt.Format("2006-01-02 15:04:05")
+		if e.Details, err = decodeEventDetails(details); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// CountDeploymentsByVersion returns deployment counts for every version of a package,
+// including versions with no deployments
+func (s *PostgresStore) CountDeploymentsByVersion(ctx context.Context, packageName string) ([]VersionDeploymentCount, error) {
+	query := `
+		SELECT p.version, COUNT(d.id), COALESCE(SUM(CASE WHEN d.verified THEN 1 ELSE 0 END), 0)
+		FROM packages p
+		LEFT JOIN deployments d ON d.package_id = p.id
+		WHERE p.name = $1
+		GROUP BY p.version
+	`
+	rows, err := s.db.QueryContext(ctx, query, packageName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []VersionDeploymentCount
+	for rows.Next() {
+		var c VersionDeploymentCount
+		if err := rows.Scan(&c.Version, &c.Deployments, &c.Verified); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
 }
 
 // CreateAPIKey creates a new API key
-func (s *PostgresStore) CreateAPIKey(ctx context.Context, name string) (string, error) {
+func (s *PostgresStore) CreateAPIKey(ctx context.Context, name string, scopes map[string]any) (string, error) {
 	key := generateAPIKey()
 	hash := hashAPIKey(key)
 	id := generateID()
-	_, err := s.db.ExecContext(ctx, "INSERT INTO api_keys (id, key_hash, name) VALUES ($1, $2, $3)", id, hash, name)
+	scopesJSON, err := encodeScopes(scopes)
+	if err != nil {
+		return "", err
+	}
+	_, err = s.db.ExecContext(ctx, "INSERT INTO api_keys (id, key_hash, name, scopes) VALUES ($1, $2, $3, $4::jsonb)", id, hash, name, scopesJSON)
 	if err != nil {
 		return "", err
 	}
@@ -566,18 +1348,49 @@ func (s *PostgresStore) ValidateAPIKey(ctx context.Context, key string) (*APIKey
 	hash := hashAPIKey(key)
 	var ak APIKey
 	var createdAt time.Time
-	err := s.db.QueryRowContext(ctx, "SELECT id, key_hash, name, created_at FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL", hash).Scan(
-		&ak.ID, &ak.KeyHash, &ak.Name, &createdAt,
+	var lastUsed sql.NullTime
+	var scopes string
+	err := s.db.QueryRowContext(ctx, "SELECT id, key_hash, name, COALESCE(scopes::text, ''), created_at, last_used_at FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL", hash).Scan(
+		&ak.ID, &ak.KeyHash, &ak.Name, &scopes, &createdAt, &lastUsed,
 	)
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
 	}
-	if err == nil {
-		ak.CreatedAt = createdAt.Format("2006-01-02 15:04:05")
+	if err != nil {
+		return nil, err
+	}
+	ak.CreatedAt = createdAt.Format("2006-01-02 15:04:05")
+	if ak.Scopes, err = decodeScopes(scopes); err != nil {
+		return nil, err
+	}
+
+	// Best-effort: a failed refresh must not fail the request it authenticates
+	if lastUsedStale(lastUsed.Time, time.Now()) {
+		_, _ = s.db.ExecContext(ctx, "UPDATE api_keys SET last_used_at = NOW() WHERE id = $1", ak.ID)
 	}
-	// Update last used
-	_, _ = s.db.ExecContext(ctx, "UPDATE api_keys SET last_used_at = NOW() WHERE id = $1", ak.ID)
-	return &ak, err
+	return &ak, nil
+}
+
+// GetAPIKey gets an active (not revoked) API key by ID
+func (s *PostgresStore) GetAPIKey(ctx context.Context, id string) (*APIKey, error) {
+	var ak APIKey
+	var createdAt time.Time
+	var scopes string
+	// Compare as text so malformed IDs are simply not found rather than a UUID syntax error
+	err := s.db.QueryRowContext(ctx, "SELECT id, name, COALESCE(scopes::text, ''), created_at FROM api_keys WHERE id::text = $1 AND revoked_at IS NULL", id).Scan(
+		&ak.ID, &ak.Name, &scopes, &createdAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	ak.CreatedAt = createdAt.Format("2006-01-02 15:04:05")
+	if ak.Scopes, err = decodeScopes(scopes); err != nil {
+		return nil, err
+	}
+	return &ak, nil
 }
 
 // ListAPIKeys lists all API keys
@@ -610,3 +1423,25 @@ func (s *PostgresStore) RevokeAPIKey(ctx context.Context, id string) error {
 	_, err := s.db.ExecContext(ctx, "UPDATE api_keys SET revoked_at = NOW() WHERE id = $1", id)
 	return err
 }
+
+// GarbageCollect deletes artifacts whose contract no longer exists and blobs no
+// artifact references, in one transaction. With dryRun it only reports them.
+func (s *PostgresStore) GarbageCollect(ctx context.Context, dryRun bool) (*GCResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := collectGarbage(ctx, tx, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
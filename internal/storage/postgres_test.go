@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"slices"
+	"testing"
+)
+
+// The Postgres store needs a live database; this checks the SQL it would send.
+func TestBuildPostgresListPackagesWhereClauses_Metadata(t *testing.T) {
+	var args []any
+	addArg := func(v any) int {
+		args = append(args, v)
+		return len(args)
+	}
+
+	filter := PackageFilter{Chain: "evm", MetadataFilters: map[string]string{"team": "core", "audit_status": "passed"}}
+	clauses := buildPostgresListPackagesWhereClauses(addArg, filter, PaginationParams{}, "p.")
+
+	want := []string{"p.chain = $1", "p.metadata @> $2::jsonb"}
+	if !slices.Equal(clauses, want) {
+		t.Errorf("clauses = %q, want %q", clauses, want)
+	}
+	if len(args) != 2 || args[1] != `{"audit_status":"passed","team":"core"}` {
+		t.Errorf("args = %q", args)
+	}
+}
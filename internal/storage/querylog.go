@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pendergraft/contrafactory/internal/middleware/requestid"
+)
+
+// queryLogger wraps a *sql.DB and logs each query's duration at debug level, with the
+// store method that ran it and the request ID of the request being served. With debug
+// logging off, queries go straight to the database. Durations cover executing the
+// query, not scanning its rows; queries inside transactions are not logged.
+type queryLogger struct {
+	*sql.DB
+	logger *slog.Logger
+}
+
+func newQueryLogger(db *sql.DB, logger *slog.Logger) *queryLogger {
+	return &queryLogger{DB: db, logger: logger}
+}
+
+// ExecContext executes a query without returning rows
+func (q *queryLogger) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	done := q.start(ctx)
+	result, err := q.DB.ExecContext(ctx, query, args...)
+	done(err)
+	return result, err
+}
+
+// QueryContext executes a query that returns rows
+func (q *queryLogger) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	done := q.start(ctx)
+	rows, err := q.DB.QueryContext(ctx, query, args...)
+	done(err)
+	return rows, err
+}
+
+// QueryRowContext executes a query that returns at most one row
+func (q *queryLogger) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	done := q.start(ctx)
+	row := q.DB.QueryRowContext(ctx, query, args...)
+	done(row.Err())
+	return row
+}
+
+// start begins timing a query and returns the func that logs it.
+func (q *queryLogger) start(ctx context.Context) func(err error) {
+	logger := requestid.Logger(ctx, q.logger)
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		return func(error) {}
+	}
+	operation := callerOperation()
+	start := time.Now()
+	return func(err error) {
+		attrs := []any{"operation", operation, "duration", time.Since(start)}
+		if err != nil && err != sql.ErrNoRows {
+			attrs = append(attrs, "error", err)
+		}
+		logger.Debug("storage query", attrs...)
+	}
+}
+
+// callerOperation names the store method running a query, e.g.
+// "SQLiteStore.ListPackages", from the call stack above the queryLogger.
+func callerOperation() string {
+	// 0 is callerOperation, 1 start, 2 the queryLogger method, 3 its caller
+	pc, _, _, ok := runtime.Caller(3)
+	if !ok {
+		return "unknown"
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimPrefix(name, "storage.")
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
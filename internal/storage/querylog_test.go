@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestQueryLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), logger)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	buf.Reset()
+	if _, err := store.GetPackage(ctx, "missing", "1.0.0"); err != ErrNotFound {
+		t.Fatalf("GetPackage() error = %v, want ErrNotFound", err)
+	}
+	out := buf.String()
+	for _, want := range []string{`msg="storage query"`, "operation=SQLiteStore.GetPackage", "duration="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %q does not contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, "error=") {
+		t.Errorf("log %q reports a missing row as an error", out)
+	}
+
+	t.Run("silent above debug", func(t *testing.T) {
+		var quiet bytes.Buffer
+		store.db.logger = slog.New(slog.NewTextHandler(&quiet, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		if _, err := store.GetPackageVersions(ctx, "missing", true); err != nil {
+			t.Fatalf("GetPackageVersions() error = %v", err)
+		}
+		if quiet.Len() != 0 {
+			t.Errorf("logged %q at info level", quiet.String())
+		}
+	})
+}
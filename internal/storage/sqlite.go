@@ -6,19 +6,33 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
 
 // SQLiteStore implements Store using SQLite
 type SQLiteStore struct {
-	db     *sql.DB
+	db     *queryLogger
 	logger *slog.Logger
+
+	// writeMu serializes the store's writes. SQLite allows one writer at a time;
+	// queueing writers here leaves the pool's other connections free for reads.
+	writeMu sync.Mutex
 }
 
+// sqliteMaxOpenConns is the default size of the SQLite connection pool
+// (storage.max_open_conns overrides it). One connection means a write can never
+// meet another of the server's own connections holding the lock; raising it lets
+// reads run alongside writes under WAL, with writeMu still queueing the writers.
+const sqliteMaxOpenConns = 1
+
 // NewSQLiteStore creates a new SQLite store
 func NewSQLiteStore(path string, logger *slog.Logger) (*SQLiteStore, error) {
 	// Ensure directory exists
@@ -27,22 +41,22 @@ func NewSQLiteStore(path string, logger *slog.Logger) (*SQLiteStore, error) {
 		return nil, fmt.Errorf("creating data directory: %w", err)
 	}
 
-	db, err := sql.Open("sqlite", path)
+	// Pragmas go in the DSN so that every pooled connection gets them, not just the
+	// first: WAL mode so reads don't block the writer, foreign keys, and a busy
+	// timeout so a write that still meets a lock (migrations, another process) waits
+	// for it instead of failing with "database is locked".
+	db, err := sql.Open("sqlite", path+"?_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("opening database: %w", err)
 	}
-
-	// Enable WAL mode for better concurrency
-	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
-		return nil, fmt.Errorf("enabling WAL mode: %w", err)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("opening database: %w", err)
 	}
 
-	// Enable foreign keys
-	if _, err := db.Exec("PRAGMA foreign_keys=ON"); err != nil {
-		return nil, fmt.Errorf("enabling foreign keys: %w", err)
-	}
+	db.SetMaxOpenConns(sqliteMaxOpenConns)
 
-	return &SQLiteStore{db: db, logger: logger}, nil
+	return &SQLiteStore{db: newQueryLogger(db, logger), logger: logger}, nil
 }
 
 // Close closes the database connection
@@ -52,7 +66,25 @@ func (s *SQLiteStore) Close() error {
 
 // Migrate runs database migrations
 func (s *SQLiteStore) Migrate(ctx context.Context) error {
-	schema := `
+	dialect := migrationDialect{
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			description TEXT NOT NULL,
+			applied_at TEXT DEFAULT (datetime('now'))
+		)`,
+		insert: "INSERT INTO schema_migrations (version, description) VALUES (?, ?)",
+	}
+	if err := runMigrations(ctx, s.db.DB, dialect, sqliteMigrations, s.logger); err != nil {
+		return fmt.Errorf("running migrations: %w", err)
+	}
+
+	s.logger.Info("database migrations complete")
+	return nil
+}
+
+// sqliteMigrations is the ordered list of SQLite schema migrations.
+var sqliteMigrations = []migration{
+	{version: 1, description: "initial schema", up: execStatements(`
 	-- Package ownership
 	CREATE TABLE IF NOT EXISTS package_owners (
 		id TEXT PRIMARY KEY,
@@ -146,27 +178,253 @@ func (s *SQLiteStore) Migrate(ctx context.Context) error {
 	CREATE INDEX IF NOT EXISTS idx_contracts_primary_hash ON contracts(primary_hash);
 	CREATE INDEX IF NOT EXISTS idx_deployments_lookup ON deployments(chain, chain_id, address);
 	CREATE INDEX IF NOT EXISTS idx_artifacts_content_hash ON artifacts(content_hash);
-	`
+	`)},
+	{version: 2, description: "add packages.project", up: sqliteAddColumn("packages", "project", "TEXT")},
+	{version: 3, description: "add contract_labels", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS contract_labels (
+		contract_id TEXT NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+		label TEXT NOT NULL,
+		PRIMARY KEY (contract_id, label)
+	);
+	CREATE INDEX IF NOT EXISTS idx_contract_labels_label ON contract_labels(label);
+	`)},
+	{version: 4, description: "add package_owner_transfers", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS package_owner_transfers (
+		id TEXT PRIMARY KEY,
+		package_name TEXT NOT NULL,
+		from_key_id TEXT,
+		to_key_id TEXT NOT NULL,
+		created_at TEXT DEFAULT (datetime('now'))
+	);
+	CREATE INDEX IF NOT EXISTS idx_package_owner_transfers_name ON package_owner_transfers(package_name);
+	`)},
+	{version: 5, description: "prefix content hashes with their algorithm", up: execStatements(`
+	UPDATE contracts SET primary_hash = 'sha256:' || primary_hash WHERE primary_hash NOT LIKE 'sha256:%';
+	UPDATE artifacts SET content_hash = 'sha256:' || content_hash WHERE content_hash NOT LIKE 'sha256:%';
+	`)},
+	{version: 6, description: "add selectors", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS selectors (
+		contract_id TEXT NOT NULL REFERENCES contracts(id) ON DELETE CASCADE,
+		selector TEXT NOT NULL,
+		entry_type TEXT NOT NULL,
+		signature TEXT NOT NULL,
+		PRIMARY KEY (contract_id, entry_type, selector)
+	);
+	CREATE INDEX IF NOT EXISTS idx_selectors_selector ON selectors(selector);
+	`)},
+	{version: 7, description: "index selectors of published ABIs", up: backfillSelectors(sqliteInsertSelector)},
+	{version: 8, description: "add contracts.metadata", up: sqliteAddColumn("contracts", "metadata", "TEXT")},
+	{version: 9, description: "add package_collaborators", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS package_collaborators (
+		package_name TEXT NOT NULL,
+		key_id TEXT NOT NULL REFERENCES api_keys(id),
+		created_at TEXT DEFAULT (datetime('now')),
+		PRIMARY KEY (package_name, key_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_package_collaborators_key ON package_collaborators(key_id);
+	`)},
+	// Postgres indexes packages.metadata here; SQLite metadata filters use json_extract
+	// without one. Kept so that a version means the same change on both stores.
+	{version: 10, description: "index packages.metadata", up: func(context.Context, *sql.Tx) error { return nil }},
+	{version: 11, description: "add deployment_events", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS deployment_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		chain TEXT NOT NULL,
+		chain_id TEXT NOT NULL,
+		address TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		details TEXT,
+		created_at TEXT DEFAULT (datetime('now'))
+	);
+	CREATE INDEX IF NOT EXISTS idx_deployment_events_lookup ON deployment_events(chain, chain_id, address);
+
+	-- Start existing deployments' timelines from what the deployments table knows
+	INSERT INTO deployment_events (chain, chain_id, address, event_type, details, created_at)
+	SELECT chain, chain_id, address, 'deployed', json_object('contract', contract_name), COALESCE(created_at, datetime('now'))
+	FROM deployments ORDER BY created_at;
+	INSERT INTO deployment_events (chain, chain_id, address, event_type, details, created_at)
+	SELECT d.chain, d.chain_id, d.address, 'verified', json_object('explorer', e.value), COALESCE(d.verified_at, d.created_at)
+	FROM deployments d, json_each(d.verified_on) e WHERE d.verified = 1;
+	INSERT INTO deployment_events (chain, chain_id, address, event_type, created_at)
+	SELECT chain, chain_id, address, 'verified', COALESCE(verified_at, created_at)
+	FROM deployments WHERE verified = 1 AND (verified_on IS NULL OR json_array_length(verified_on) = 0);
+	`)},
+	{version: 12, description: "add package_aliases", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS package_aliases (
+		package_name TEXT NOT NULL,
+		alias TEXT NOT NULL,
+		version TEXT NOT NULL,
+		updated_at TEXT DEFAULT (datetime('now')),
+		PRIMARY KEY (package_name, alias)
+	);
+	`)},
+	{version: 13, description: "add package_tags", up: execStatements(`
+	CREATE TABLE IF NOT EXISTS package_tags (
+		package_id TEXT NOT NULL REFERENCES packages(id) ON DELETE CASCADE,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (package_id, tag)
+	);
+	CREATE INDEX IF NOT EXISTS idx_package_tags_tag ON package_tags(tag);
+	`)},
+	{version: 14, description: "lowercase EVM deployment addresses", up: execStatements(`
+	-- EVM addresses are stored lowercase since checksums are validated on record.
+	-- Where older rows differ only in case, keep the most recently recorded one,
+	-- first merging into it the verification and transaction details of the others
+	-- so that collapsing the duplicates loses nothing.
+	UPDATE deployments SET
+		verified = (SELECT MAX(COALESCE(o.verified, 0)) FROM deployments o
+			WHERE o.chain = deployments.chain AND o.chain_id = deployments.chain_id AND lower(o.address) = lower(deployments.address)),
+		verified_at = COALESCE((SELECT MIN(o.verified_at) FROM deployments o
+			WHERE o.chain = deployments.chain AND o.chain_id = deployments.chain_id AND lower(o.address) = lower(deployments.address)
+				AND o.verified = 1), verified_at),
+		verified_on = COALESCE((SELECT json_group_array(DISTINCT e.value) FROM deployments o, json_each(COALESCE(NULLIF(o.verified_on, ''), '[]')) e
+			WHERE o.chain = deployments.chain AND o.chain_id = deployments.chain_id AND lower(o.address) = lower(deployments.address)
+			HAVING COUNT(*) > 0), verified_on),
+		tx_hash = COALESCE(NULLIF(tx_hash, ''), (SELECT o.tx_hash FROM deployments o
+			WHERE o.chain = deployments.chain AND o.chain_id = deployments.chain_id AND lower(o.address) = lower(deployments.address)
+				AND COALESCE(o.tx_hash, '') <> '' ORDER BY o.created_at DESC LIMIT 1), tx_hash),
+		deployer_address = COALESCE(NULLIF(deployer_address, ''), (SELECT o.deployer_address FROM deployments o
+			WHERE o.chain = deployments.chain AND o.chain_id = deployments.chain_id AND lower(o.address) = lower(deployments.address)
+				AND COALESCE(o.deployer_address, '') <> '' ORDER BY o.created_at DESC LIMIT 1), deployer_address),
+		block_number = COALESCE(NULLIF(block_number, 0), (SELECT o.block_number FROM deployments o
+			WHERE o.chain = deployments.chain AND o.chain_id = deployments.chain_id AND lower(o.address) = lower(deployments.address)
+				AND COALESCE(o.block_number, 0) <> 0 ORDER BY o.created_at DESC LIMIT 1), block_number)
+	WHERE chain <> 'solana' AND EXISTS (
+		SELECT 1 FROM deployments o
+		WHERE o.chain = deployments.chain AND o.chain_id = deployments.chain_id
+			AND lower(o.address) = lower(deployments.address) AND o.id <> deployments.id
+	) AND NOT EXISTS (
+		SELECT 1 FROM deployments n
+		WHERE n.chain = deployments.chain AND n.chain_id = deployments.chain_id
+			AND lower(n.address) = lower(deployments.address) AND n.id <> deployments.id
+			AND (n.created_at > deployments.created_at OR (n.created_at = deployments.created_at AND n.id > deployments.id))
+	);
+	DELETE FROM deployments WHERE chain <> 'solana' AND EXISTS (
+		SELECT 1 FROM deployments n
+		WHERE n.chain = deployments.chain AND n.chain_id = deployments.chain_id
+			AND lower(n.address) = lower(deployments.address) AND n.id <> deployments.id
+			AND (n.created_at > deployments.created_at OR (n.created_at = deployments.created_at AND n.id > deployments.id))
+	);
+	UPDATE deployments SET address = lower(address) WHERE chain <> 'solana' AND address <> lower(address);
+	UPDATE deployment_events SET address = lower(address) WHERE chain <> 'solana' AND address <> lower(address);
+	`)},
+}
+
+// sqliteInsertDeploymentEvent appends to a deployment's timeline.
+const sqliteInsertDeploymentEvent = "INSERT INTO deployment_events (chain, chain_id, address, event_type, details, created_at) VALUES (?, ?, ?, ?, ?, datetime('now'))"
+
+// sqliteInsertSelector indexes one ABI selector of a contract.
+const sqliteInsertSelector = "INSERT OR IGNORE INTO selectors (contract_id, selector, entry_type, signature) VALUES (?, ?, ?, ?)"
+
+// sqliteAddColumn returns a migration func that adds a column unless it already
+// exists. SQLite has no ADD COLUMN IF NOT EXISTS, so the column list is checked
+// first (databases created before schema_migrations may already have it).
+func sqliteAddColumn(table, column, colType string) func(ctx context.Context, tx *sql.Tx) error {
+	return func(ctx context.Context, tx *sql.Tx) error {
+		exists, err := sqliteColumnExists(ctx, tx, table, column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, colType))
+		return err
+	}
+}
 
-	_, err := s.db.ExecContext(ctx, schema)
+// sqliteColumnExists reports whether table has the named column.
+func sqliteColumnExists(ctx context.Context, tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", table))
 	if err != nil {
-		return fmt.Errorf("running migrations: %w", err)
+		return false, fmt.Errorf("reading columns of %s: %w", table, err)
 	}
+	defer rows.Close()
 
-	// Add project column if it doesn't exist (SQLite doesn't support IF NOT EXISTS for ADD COLUMN)
-	// Ignore error if column already exists (duplicate column name)
-	if _, err := s.db.ExecContext(ctx, "ALTER TABLE packages ADD COLUMN project TEXT"); err != nil {
-		if !strings.Contains(err.Error(), "duplicate column name") {
-			s.logger.Warn("adding project column (may already exist)", "error", err)
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return false, fmt.Errorf("scanning columns of %s: %w", table, err)
+		}
+		if name == column {
+			return true, nil
 		}
 	}
-
-	s.logger.Info("database migrations complete")
-	return nil
+	return false, rows.Err()
 }
 
 // CreatePackage creates a new package
 func (s *SQLiteStore) CreatePackage(ctx context.Context, pkg *Package) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	// The version and its tags are stored together, so a failed tag insert doesn't
+	// leave a published version behind that a retry would collide with
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.insertPackage(ctx, tx, pkg); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CreatePackageVersions stores each version with its tags, contracts and artifacts,
+// and claims unowned package names, in one transaction: either every version is
+// stored or none is.
+func (s *SQLiteStore) CreatePackageVersions(ctx context.Context, versions []PackageVersion) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, v := range versions {
+		if err := s.insertPackageVersion(ctx, tx, v); err != nil {
+			return fmt.Errorf("storing %s@%s: %w", v.Package.Name, v.Package.Version, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// insertPackageVersion writes one version of a CreatePackageVersions call.
+func (s *SQLiteStore) insertPackageVersion(ctx context.Context, db execer, v PackageVersion) error {
+	if err := s.insertPackage(ctx, db, v.Package); err != nil {
+		return err
+	}
+	if v.OwnerKeyID != "" {
+		if err := s.insertPackageOwner(ctx, db, v.Package.Name, v.OwnerKeyID); err != nil {
+			return fmt.Errorf("claiming package: %w", err)
+		}
+	}
+	for _, c := range v.Contracts {
+		if err := s.insertContract(ctx, db, v.Package.ID, c.Contract); err != nil {
+			return fmt.Errorf("creating contract %s: %w", c.Contract.Name, err)
+		}
+		for _, a := range c.Artifacts {
+			if err := s.insertArtifact(ctx, db, c.Contract.ID, a.Type, a.Content); err != nil {
+				return fmt.Errorf("storing %s for %s: %w", a.Type, c.Contract.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// insertPackage writes a package row and its tags.
+func (s *SQLiteStore) insertPackage(ctx context.Context, db execer, pkg *Package) error {
 	// Serialize metadata as JSON
 	var metadataJSON string
 	if len(pkg.Metadata) > 0 {
@@ -193,8 +451,15 @@ func (s *SQLiteStore) CreatePackage(ctx context.Context, pkg *Package) error {
 		INSERT INTO packages (id, name, version, project, chain, builder, compiler_version, compiler_settings, metadata, created_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'))
 	`
-	_, err := s.db.ExecContext(ctx, query, pkg.ID, pkg.Name, pkg.Version, nullIfEmpty(pkg.Project), pkg.Chain, pkg.Builder, pkg.CompilerVersion, compilerSettingsJSON, metadataJSON)
-	return err
+	if _, err := db.ExecContext(ctx, query, pkg.ID, pkg.Name, pkg.Version, nullIfEmpty(pkg.Project), pkg.Chain, pkg.Builder, pkg.CompilerVersion, compilerSettingsJSON, metadataJSON); err != nil {
+		return err
+	}
+	for _, tag := range pkg.Tags {
+		if _, err := db.ExecContext(ctx, "INSERT OR IGNORE INTO package_tags (package_id, tag) VALUES (?, ?)", pkg.ID, tag); err != nil {
+			return fmt.Errorf("storing tag %q: %w", tag, err)
+		}
+	}
+	return nil
 }
 
 // GetPackage retrieves a package by name and version
@@ -237,10 +502,17 @@ func (s *SQLiteStore) GetPackage(ctx context.Context, name, version string) (*Pa
 		}
 	}
 
+	tags, err := queryPackageTags(ctx, s.db, "SELECT tag FROM package_tags WHERE package_id = ? ORDER BY tag", pkg.ID)
+	if err != nil {
+		return nil, fmt.Errorf("reading tags: %w", err)
+	}
+	pkg.Tags = tags
+
 	return &pkg, nil
 }
 
-// GetPackageVersions retrieves all versions of a package
+// GetPackageVersions retrieves the versions of a package, newest first. Prereleases
+// are left out unless includePrerelease is set.
 func (s *SQLiteStore) GetPackageVersions(ctx context.Context, name string, includePrerelease bool) ([]string, error) {
 	query := `SELECT version FROM packages WHERE name = ? ORDER BY created_at DESC`
 	rows, err := s.db.QueryContext(ctx, query, name)
@@ -257,7 +529,34 @@ func (s *SQLiteStore) GetPackageVersions(ctx context.Context, name string, inclu
 		}
 		versions = append(versions, v)
 	}
-	return versions, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !includePrerelease {
+		versions = withoutPrereleases(versions)
+	}
+	return versions, nil
+}
+
+// GetPackageVersionDetails retrieves every version of a package with its build
+// details, newest first, prereleases included.
+func (s *SQLiteStore) GetPackageVersionDetails(ctx context.Context, name string) ([]VersionDetail, error) {
+	query := `SELECT version, chain, builder, compiler_version, created_at FROM packages WHERE name = ? ORDER BY created_at DESC`
+	rows, err := s.db.QueryContext(ctx, query, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var details []VersionDetail
+	for rows.Next() {
+		var d VersionDetail
+		if err := rows.Scan(&d.Version, &d.Chain, &d.Builder, &d.CompilerVersion, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		details = append(details, d)
+	}
+	return details, rows.Err()
 }
 
 // ListPackages lists packages with filtering and cursor-based pagination
@@ -289,7 +588,11 @@ func (s *SQLiteStore) ListPackages(ctx context.Context, filter PackageFilter, pa
 	if len(whereClauses) > 0 {
 		baseQuery += " WHERE " + strings.Join(whereClauses, " AND ")
 	}
-	baseQuery += " GROUP BY " + tablePrefix + "name, " + tablePrefix + "chain, " + tablePrefix + "builder ORDER BY " + tablePrefix + "name LIMIT ?"
+	order := tablePrefix + "name"
+	if pagination.Before != "" {
+		order += " DESC"
+	}
+	baseQuery += " GROUP BY " + tablePrefix + "name, " + tablePrefix + "chain, " + tablePrefix + "builder ORDER BY " + order + " LIMIT ?"
 	addArg(pagination.Limit + 1)
 
 	rows, err := s.db.QueryContext(ctx, baseQuery, args...)
@@ -321,23 +624,10 @@ func (s *SQLiteStore) ListPackages(ctx context.Context, filter PackageFilter, pa
 		})
 	}
 
-	hasMore := len(packages) > pagination.Limit
-	var nextCursor string
-	if hasMore {
-		packages = packages[:pagination.Limit]
-	}
-	if len(packages) > 0 {
-		nextCursor = packages[len(packages)-1].Name
-	}
-
-	return &PaginatedResult[Package]{
-		Data:       packages,
-		HasMore:    hasMore,
-		NextCursor: nextCursor,
-	}, rows.Err()
+	return pageResult(packages, pagination, packageName), rows.Err()
 }
 
-// buildListPackagesWhereClauses builds WHERE clauses for ListPackages (SQLite uses ? placeholders)
+// buildListPackagesWhereClauses builds WHERE clauses for ListPackages and CountPackages (SQLite uses ? placeholders)
 func buildListPackagesWhereClauses(args *[]any, argIdx *int, filter PackageFilter, pagination PaginationParams, tablePrefix string) []string {
 	var whereClauses []string
 	addArg := func(v any) {
@@ -349,6 +639,10 @@ func buildListPackagesWhereClauses(args *[]any, argIdx *int, filter PackageFilte
 		whereClauses = append(whereClauses, tablePrefix+"name > ?")
 		addArg(pagination.Cursor)
 	}
+	if pagination.Before != "" {
+		whereClauses = append(whereClauses, tablePrefix+"name < ?")
+		addArg(pagination.Before)
+	}
 	if filter.Query != "" {
 		whereClauses = append(whereClauses, tablePrefix+"name LIKE ?")
 		addArg("%" + filter.Query + "%")
@@ -365,13 +659,99 @@ func buildListPackagesWhereClauses(args *[]any, argIdx *int, filter PackageFilte
 		whereClauses = append(whereClauses, tablePrefix+"version = ?")
 		addArg(filter.Version)
 	}
+	if !filter.CreatedAfter.IsZero() {
+		whereClauses = append(whereClauses, tablePrefix+"created_at >= ?")
+		addArg(sqliteTime(filter.CreatedAfter))
+	}
+	if filter.Label != "" {
+		// Qualify the outer id explicitly; a bare "id" would bind to the subquery's contracts
+		outer := tablePrefix
+		if outer == "" {
+			outer = "packages."
+		}
+		whereClauses = append(whereClauses, `EXISTS (
+			SELECT 1 FROM contracts lc
+			INNER JOIN contract_labels cl ON cl.contract_id = lc.id
+			WHERE lc.package_id = `+outer+`id AND cl.label = ?)`)
+		addArg(filter.Label)
+	}
+	if filter.Tag != "" {
+		outer := tablePrefix
+		if outer == "" {
+			outer = "packages."
+		}
+		whereClauses = append(whereClauses, "EXISTS (SELECT 1 FROM package_tags pt WHERE pt.package_id = "+outer+"id AND pt.tag = ?)")
+		addArg(filter.Tag)
+	}
+	if filter.Owner != "" {
+		whereClauses = append(whereClauses, "EXISTS (SELECT 1 FROM package_owners po WHERE po.package_name = "+tablePrefix+"name AND po.owner_key_id = ?)")
+		addArg(filter.Owner)
+	}
+	// SQLite has no JSON index; each pair is looked up in the row's metadata with
+	// json_each, which unlike a json_extract path takes any key as a plain argument
+	for _, key := range slices.Sorted(maps.Keys(filter.MetadataFilters)) {
+		whereClauses = append(whereClauses, "EXISTS (SELECT 1 FROM json_each("+tablePrefix+"metadata) WHERE key = ? AND value = ?)")
+		addArg(key)
+		addArg(filter.MetadataFilters[key])
+	}
 	return whereClauses
 }
 
+// ListPackagesByOwner lists the packages whose name ownerKeyID owns, paginated like
+// ListPackages
+func (s *SQLiteStore) ListPackagesByOwner(ctx context.Context, ownerKeyID string, pagination PaginationParams) (*PaginatedResult[Package], error) {
+	return s.ListPackages(ctx, PackageFilter{Owner: ownerKeyID}, pagination)
+}
+
+// CountPackages counts the distinct package names matching filter (as ListPackages
+// would return them across all pages)
+func (s *SQLiteStore) CountPackages(ctx context.Context, filter PackageFilter) (int, error) {
+	var args []any
+	argIdx := 0
+
+	tablePrefix := ""
+	query := `SELECT COUNT(DISTINCT name) FROM packages`
+	if filter.Contract != "" {
+		tablePrefix = "p."
+		query = `SELECT COUNT(DISTINCT p.name) FROM packages p
+			INNER JOIN contracts c ON c.package_id = p.id AND LOWER(c.name) = LOWER(?)`
+		args = append(args, filter.Contract)
+		argIdx++
+	}
+	if whereClauses := buildListPackagesWhereClauses(&args, &argIdx, filter, PaginationParams{}, tablePrefix); len(whereClauses) > 0 {
+		query += " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	var count int
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
 // DeletePackage deletes a package
 func (s *SQLiteStore) DeletePackage(ctx context.Context, name, version string) error {
-	_, err := s.db.ExecContext(ctx, "DELETE FROM packages WHERE name = ? AND version = ?", name, version)
-	return err
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Aliases pointing at the version go with it
+	if _, err := tx.ExecContext(ctx, "DELETE FROM package_aliases WHERE package_name = ? AND version = ?", name, version); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM packages WHERE name = ? AND version = ?", name, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListPackageTags lists every tag in use with the number of package names that
+// have a version carrying it, by tag
+func (s *SQLiteStore) ListPackageTags(ctx context.Context) ([]TagCount, error) {
+	return queryTagCounts(ctx, s.db)
 }
 
 // PackageExists checks if a package exists
@@ -395,43 +775,282 @@ func (s *SQLiteStore) GetPackageOwner(ctx context.Context, name string) (string,
 	return ownerID.String, nil
 }
 
+// GetPackageOwnerInfo returns the key that owns a package name, or ErrNotFound when unowned
+func (s *SQLiteStore) GetPackageOwnerInfo(ctx context.Context, name string) (*PackageOwner, error) {
+	var owner PackageOwner
+	var keyName, createdAt sql.NullString
+	query := `
+		SELECT o.owner_key_id, k.name, o.created_at
+		FROM package_owners o LEFT JOIN api_keys k ON k.id = o.owner_key_id
+		WHERE o.package_name = ?`
+	err := s.db.QueryRowContext(ctx, query, name).Scan(&owner.KeyID, &keyName, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	owner.KeyName = keyName.String
+	owner.CreatedAt = createdAt.String
+	return &owner, nil
+}
+
+// GetOwnerArtifactBytes returns the total size of all artifacts in packages owned by ownerKeyID
+func (s *SQLiteStore) GetOwnerArtifactBytes(ctx context.Context, ownerKeyID string) (int64, error) {
+	var total int64
+	query := `
+		SELECT COALESCE(SUM(a.size_bytes), 0)
+		FROM artifacts a
+		JOIN contracts c ON c.id = a.contract_id
+		JOIN packages p ON p.id = c.package_id
+		JOIN package_owners o ON o.package_name = p.name
+		WHERE o.owner_key_id = ?`
+	err := s.db.QueryRowContext(ctx, query, ownerKeyID).Scan(&total)
+	return total, err
+}
+
 // SetPackageOwner sets the owner of a package (first-come-first-served)
 func (s *SQLiteStore) SetPackageOwner(ctx context.Context, name, ownerKeyID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	return s.insertPackageOwner(ctx, s.db, name, ownerKeyID)
+}
+
+// insertPackageOwner claims a package name for ownerKeyID unless it is already owned.
+func (s *SQLiteStore) insertPackageOwner(ctx context.Context, db execer, name, ownerKeyID string) error {
 	query := `INSERT OR IGNORE INTO package_owners (id, package_name, owner_key_id) VALUES (?, ?, ?)`
-	_, err := s.db.ExecContext(ctx, query, generateID(), name, ownerKeyID)
+	_, err := db.ExecContext(ctx, query, generateID(), name, ownerKeyID)
 	return err
 }
 
+// UpdatePackageOwner hands a package from fromKeyID to toKeyID and records the
+// transfer in package_owner_transfers. It returns ErrNotFound when the package is
+// not currently owned by fromKeyID.
+func (s *SQLiteStore) UpdatePackageOwner(ctx context.Context, name, fromKeyID, toKeyID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE package_owners SET owner_key_id = ? WHERE package_name = ? AND owner_key_id = ?`, toKeyID, name, fromKeyID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO package_owner_transfers (id, package_name, from_key_id, to_key_id, created_at)
+		VALUES (?, ?, ?, ?, datetime('now'))`, generateID(), name, fromKeyID, toKeyID); err != nil {
+		return fmt.Errorf("recording transfer: %w", err)
+	}
+	return tx.Commit()
+}
+
+// IsAuthorized reports whether keyID may publish to and delete from package name:
+// the name is unowned, or keyID owns it or is one of its collaborators.
+func (s *SQLiteStore) IsAuthorized(ctx context.Context, name, keyID string) (bool, error) {
+	var authorized bool
+	query := `
+		SELECT NOT EXISTS (SELECT 1 FROM package_owners WHERE package_name = ?)
+			OR EXISTS (SELECT 1 FROM package_owners WHERE package_name = ? AND owner_key_id = ?)
+			OR EXISTS (SELECT 1 FROM package_collaborators WHERE package_name = ? AND key_id = ?)`
+	err := s.db.QueryRowContext(ctx, query, name, name, keyID, name, keyID).Scan(&authorized)
+	return authorized, err
+}
+
+// AddPackageCollaborator lets keyID publish to package name. Adding an existing
+// collaborator is a no-op.
+func (s *SQLiteStore) AddPackageCollaborator(ctx context.Context, name, keyID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	query := `INSERT OR IGNORE INTO package_collaborators (package_name, key_id) VALUES (?, ?)`
+	_, err := s.db.ExecContext(ctx, query, name, keyID)
+	return err
+}
+
+// RemovePackageCollaborator revokes keyID's access to package name. It returns
+// ErrNotFound when keyID is not a collaborator.
+func (s *SQLiteStore) RemovePackageCollaborator(ctx context.Context, name, keyID string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM package_collaborators WHERE package_name = ? AND key_id = ?`, name, keyID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListPackageCollaborators lists the collaborators of package name, oldest first
+func (s *SQLiteStore) ListPackageCollaborators(ctx context.Context, name string) ([]PackageCollaborator, error) {
+	query := `
+		SELECT c.key_id, k.name, c.created_at
+		FROM package_collaborators c LEFT JOIN api_keys k ON k.id = c.key_id
+		WHERE c.package_name = ?
+		ORDER BY c.created_at, c.key_id`
+	rows, err := s.db.QueryContext(ctx, query, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var collaborators []PackageCollaborator
+	for rows.Next() {
+		var c PackageCollaborator
+		var keyName, createdAt sql.NullString
+		if err := rows.Scan(&c.KeyID, &keyName, &createdAt); err != nil {
+			return nil, err
+		}
+		c.KeyName = keyName.String
+		c.CreatedAt = createdAt.String
+		collaborators = append(collaborators, c)
+	}
+	return collaborators, rows.Err()
+}
+
+// SetPackageAlias points alias at a version of package name, moving it if it exists.
+func (s *SQLiteStore) SetPackageAlias(ctx context.Context, name, alias, version string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	query := `
+		INSERT INTO package_aliases (package_name, alias, version, updated_at) VALUES (?, ?, ?, datetime('now'))
+		ON CONFLICT (package_name, alias) DO UPDATE SET version = excluded.version, updated_at = excluded.updated_at`
+	_, err := s.db.ExecContext(ctx, query, name, alias, version)
+	return err
+}
+
+// GetPackageAlias returns the version alias points at, or ErrNotFound.
+func (s *SQLiteStore) GetPackageAlias(ctx context.Context, name, alias string) (string, error) {
+	var version string
+	err := s.db.QueryRowContext(ctx, `SELECT version FROM package_aliases WHERE package_name = ? AND alias = ?`, name, alias).Scan(&version)
+	if err == sql.ErrNoRows {
+		return "", ErrNotFound
+	}
+	return version, err
+}
+
+// ListPackageAliases lists the aliases of package name, by alias
+func (s *SQLiteStore) ListPackageAliases(ctx context.Context, name string) ([]PackageAlias, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT alias, version, updated_at FROM package_aliases WHERE package_name = ? ORDER BY alias`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var aliases []PackageAlias
+	for rows.Next() {
+		var a PackageAlias
+		var updatedAt sql.NullString
+		if err := rows.Scan(&a.Alias, &a.Version, &updatedAt); err != nil {
+			return nil, err
+		}
+		a.UpdatedAt = updatedAt.String
+		aliases = append(aliases, a)
+	}
+	return aliases, rows.Err()
+}
+
+// DeletePackageAlias removes an alias. It returns ErrNotFound when there is none.
+func (s *SQLiteStore) DeletePackageAlias(ctx context.Context, name, alias string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM package_aliases WHERE package_name = ? AND alias = ?`, name, alias)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // CreateContract creates a new contract
 func (s *SQLiteStore) CreateContract(ctx context.Context, packageID string, contract *Contract) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	return s.insertContract(ctx, s.db, packageID, contract)
+}
+
+// insertContract writes a contract row with its labels and selectors.
+func (s *SQLiteStore) insertContract(ctx context.Context, db execer, packageID string, contract *Contract) error {
 	query := `
-		INSERT INTO contracts (id, package_id, name, chain, source_path, license, primary_hash, metadata_hash, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, datetime('now'))
+		INSERT INTO contracts (id, package_id, name, chain, source_path, license, primary_hash, metadata_hash, metadata, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'))
 	`
-	_, err := s.db.ExecContext(ctx, query, contract.ID, packageID, contract.Name, contract.Chain, contract.SourcePath, contract.License, contract.PrimaryHash, contract.MetadataHash)
-	return err
+	metadata, err := encodeContractMetadata(contract.Metadata)
+	if err != nil {
+		return err
+	}
+	if _, err := db.ExecContext(ctx, query, contract.ID, packageID, contract.Name, contract.Chain, contract.SourcePath, contract.License, contract.PrimaryHash, contract.MetadataHash, nullIfEmpty(metadata)); err != nil {
+		return err
+	}
+	for _, label := range contract.Labels {
+		if _, err := db.ExecContext(ctx, "INSERT OR IGNORE INTO contract_labels (contract_id, label) VALUES (?, ?)", contract.ID, label); err != nil {
+			return fmt.Errorf("storing label %q: %w", label, err)
+		}
+	}
+	for _, sel := range contract.Selectors {
+		if _, err := db.ExecContext(ctx, sqliteInsertSelector, contract.ID, sel.Selector, sel.Type, sel.Signature); err != nil {
+			return fmt.Errorf("storing selector %s: %w", sel.Selector, err)
+		}
+	}
+	return nil
 }
 
 // GetContract retrieves a contract
 func (s *SQLiteStore) GetContract(ctx context.Context, packageID, contractName string) (*Contract, error) {
 	query := `
-		SELECT id, package_id, name, chain, source_path, license, primary_hash, metadata_hash, created_at
+		SELECT id, package_id, name, chain, source_path, license, primary_hash, metadata_hash, metadata, created_at
 		FROM contracts
 		WHERE package_id = ? AND name = ?
 	`
 	var c Contract
+	var metadata sql.NullString
 	err := s.db.QueryRowContext(ctx, query, packageID, contractName).Scan(
-		&c.ID, &c.PackageID, &c.Name, &c.Chain, &c.SourcePath, &c.License, &c.PrimaryHash, &c.MetadataHash, &c.CreatedAt,
+		&c.ID, &c.PackageID, &c.Name, &c.Chain, &c.SourcePath, &c.License, &c.PrimaryHash, &c.MetadataHash, &metadata, &c.CreatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
 	}
-	return &c, err
+	if err != nil {
+		return nil, err
+	}
+	if c.Metadata, err = decodeContractMetadata([]byte(metadata.String)); err != nil {
+		// Log but don't fail - metadata is optional
+		s.logger.Warn("failed to deserialize contract metadata", "contract", c.ID, "error", err)
+	}
+
+	labels, err := queryContractLabels(ctx, s.db, "SELECT contract_id, label FROM contract_labels WHERE contract_id = ? ORDER BY label", c.ID)
+	if err != nil {
+		return nil, err
+	}
+	c.Labels = labels[c.ID]
+	return &c, nil
 }
 
 // ListContracts lists all contracts in a package
 func (s *SQLiteStore) ListContracts(ctx context.Context, packageID string) ([]Contract, error) {
-	query := `SELECT id, package_id, name, chain, source_path, license, primary_hash, metadata_hash, created_at FROM contracts WHERE package_id = ?`
+	query := `SELECT id, package_id, name, chain, source_path, license, primary_hash, metadata_hash, metadata, created_at FROM contracts WHERE package_id = ?`
 	rows, err := s.db.QueryContext(ctx, query, packageID)
 	if err != nil {
 		return nil, err
@@ -441,23 +1060,50 @@ func (s *SQLiteStore) ListContracts(ctx context.Context, packageID string) ([]Co
 	var contracts []Contract
 	for rows.Next() {
 		var c Contract
-		if err := rows.Scan(&c.ID, &c.PackageID, &c.Name, &c.Chain, &c.SourcePath, &c.License, &c.PrimaryHash, &c.MetadataHash, &c.CreatedAt); err != nil {
+		var metadata sql.NullString
+		if err := rows.Scan(&c.ID, &c.PackageID, &c.Name, &c.Chain, &c.SourcePath, &c.License, &c.PrimaryHash, &c.MetadataHash, &metadata, &c.CreatedAt); err != nil {
 			return nil, err
 		}
+		var err error
+		if c.Metadata, err = decodeContractMetadata([]byte(metadata.String)); err != nil {
+			s.logger.Warn("failed to deserialize contract metadata", "contract", c.ID, "error", err)
+		}
 		contracts = append(contracts, c)
 	}
-	return contracts, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	labels, err := queryContractLabels(ctx, s.db, `
+		SELECT cl.contract_id, cl.label FROM contract_labels cl
+		INNER JOIN contracts c ON c.id = cl.contract_id
+		WHERE c.package_id = ? ORDER BY cl.label`, packageID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range contracts {
+		contracts[i].Labels = labels[contracts[i].ID]
+	}
+	return contracts, nil
 }
 
 // StoreArtifact stores an artifact
 func (s *SQLiteStore) StoreArtifact(ctx context.Context, contractID, artifactType string, content []byte) error {
-	hash := computeHash(content)
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	return s.insertArtifact(ctx, s.db, contractID, artifactType, content)
+}
+
+// insertArtifact writes an artifact, replacing any earlier one of the same type.
+func (s *SQLiteStore) insertArtifact(ctx context.Context, db execer, contractID, artifactType string, content []byte) error {
+	hash := HashContent(content)
 	query := `
 		INSERT INTO artifacts (id, contract_id, artifact_type, content_hash, content, size_bytes)
 		VALUES (?, ?, ?, ?, ?, ?)
 		ON CONFLICT(contract_id, artifact_type) DO UPDATE SET content = excluded.content, content_hash = excluded.content_hash, size_bytes = excluded.size_bytes
 	`
-	_, err := s.db.ExecContext(ctx, query, generateID(), contractID, artifactType, hash, content, len(content))
+	_, err := db.ExecContext(ctx, query, generateID(), contractID, artifactType, hash, content, len(content))
 	return err
 }
 
@@ -481,37 +1127,175 @@ func (s *SQLiteStore) GetArtifactByHash(ctx context.Context, hash string) ([]byt
 	return content, err
 }
 
+// FindContractsByHash finds contracts whose primary hash (creation bytecode or
+// program binary) or deployed bytecode artifact hashes to hash
+func (s *SQLiteStore) FindContractsByHash(ctx context.Context, hash string) ([]BytecodeMatch, error) {
+	query := `
+		SELECT p.name, p.version, c.name, c.chain, CASE WHEN c.chain = 'solana' THEN 'program' ELSE 'bytecode' END
+		FROM contracts c
+		INNER JOIN packages p ON p.id = c.package_id
+		WHERE c.primary_hash = ?
+		UNION
+		SELECT p.name, p.version, c.name, c.chain, a.artifact_type
+		FROM artifacts a
+		INNER JOIN contracts c ON c.id = a.contract_id
+		INNER JOIN packages p ON p.id = c.package_id
+		WHERE a.content_hash = ? AND a.artifact_type = 'deployed-bytecode'
+		ORDER BY 1, 2, 3
+	`
+	rows, err := s.db.QueryContext(ctx, query, hash, hash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []BytecodeMatch
+	for rows.Next() {
+		var m BytecodeMatch
+		if err := rows.Scan(&m.PackageName, &m.Version, &m.ContractName, &m.Chain, &m.MatchedOn); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// FindContractsBySelector finds contracts whose ABI has a function, event or error
+// with the given selector
+func (s *SQLiteStore) FindContractsBySelector(ctx context.Context, selector string) ([]SelectorMatch, error) {
+	query := `
+		SELECT p.name, p.version, c.name, c.chain, sel.entry_type, sel.signature
+		FROM selectors sel
+		INNER JOIN contracts c ON c.id = sel.contract_id
+		INNER JOIN packages p ON p.id = c.package_id
+		WHERE sel.selector = ?
+		ORDER BY 1, 2, 3, 5
+	`
+	rows, err := s.db.QueryContext(ctx, query, selector)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []SelectorMatch
+	for rows.Next() {
+		var m SelectorMatch
+		if err := rows.Scan(&m.PackageName, &m.Version, &m.ContractName, &m.Chain, &m.Type, &m.Signature); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
 // RecordDeployment records a deployment
 func (s *SQLiteStore) RecordDeployment(ctx context.Context, d *Deployment) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	query := `
 		INSERT INTO deployments (id, package_id, contract_name, chain, chain_id, address, deployer_address, tx_hash, block_number, deployment_data, created_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, datetime('now'))
 	`
-	_, err := s.db.ExecContext(ctx, query, d.ID, d.PackageID, d.ContractName, d.Chain, d.ChainID, d.Address, d.DeployerAddress, d.TxHash, d.BlockNumber, "{}")
-	return err
+	deploymentData, err := encodeDeploymentData(d.DeploymentData)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, query, d.ID, d.PackageID, d.ContractName, d.Chain, d.ChainID, d.Address, d.DeployerAddress, d.TxHash, d.BlockNumber, deploymentData); err != nil {
+		return err
+	}
+	if err := insertDeploymentEvents(ctx, tx, sqliteInsertDeploymentEvent, d.Chain, d.ChainID, d.Address, deployedEvent(d)); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 // GetDeployment retrieves a deployment
 func (s *SQLiteStore) GetDeployment(ctx context.Context, chain, chainID, address string) (*Deployment, error) {
 	query := `
-		SELECT id, package_id, contract_name, chain, chain_id, address, deployer_address, tx_hash, block_number, verified, created_at
-		FROM deployments
-		WHERE chain = ? AND chain_id = ? AND address = ?
+		SELECT d.id, d.package_id, COALESCE(p.name, ''), COALESCE(p.version, ''), d.contract_name, d.chain, d.chain_id, d.address,
+			d.deployer_address, d.tx_hash, d.block_number, d.deployment_data, d.verified, COALESCE(d.verified_on, ''), d.created_at
+		FROM deployments d
+		LEFT JOIN packages p ON p.id = d.package_id
+		WHERE d.chain = ? AND d.chain_id = ? AND d.address = ?
 	`
 	var d Deployment
+	var deploymentData sql.NullString
+	var verifiedOn string
 	err := s.db.QueryRowContext(ctx, query, chain, chainID, address).Scan(
-		&d.ID, &d.PackageID, &d.ContractName, &d.Chain, &d.ChainID, &d.Address, &d.DeployerAddress, &d.TxHash, &d.BlockNumber, &d.Verified, &d.CreatedAt,
+		&d.ID, &d.PackageID, &d.PackageName, &d.PackageVersion, &d.ContractName, &d.Chain, &d.ChainID, &d.Address, &d.DeployerAddress, &d.TxHash, &d.BlockNumber, &deploymentData, &d.Verified, &verifiedOn, &d.CreatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
 	}
-	return &d, err
+	if err != nil {
+		return nil, err
+	}
+	if d.DeploymentData, err = decodeDeploymentData([]byte(deploymentData.String)); err != nil {
+		return nil, err
+	}
+	if d.VerifiedOn, err = decodeVerifiedOn(verifiedOn); err != nil {
+		return nil, err
+	}
+	return &d, nil
 }
 
-// ListDeployments lists deployments
+// ListDeployments lists deployments, newest first. Pages are keyed by deployment ID.
 func (s *SQLiteStore) ListDeployments(ctx context.Context, filter DeploymentFilter, pagination PaginationParams) (*PaginatedResult[Deployment], error) {
-	query := `SELECT id, package_id, contract_name, chain, chain_id, address, verified, created_at FROM deployments ORDER BY created_at DESC LIMIT ?`
-	rows, err := s.db.QueryContext(ctx, query, pagination.Limit+1)
+	var whereClauses []string
+	var args []any
+	if filter.Chain != "" {
+		whereClauses = append(whereClauses, "d.chain = ?")
+		args = append(args, filter.Chain)
+	}
+	if filter.ChainID != "" {
+		whereClauses = append(whereClauses, "d.chain_id = ?")
+		args = append(args, filter.ChainID)
+	}
+	if filter.Package != "" {
+		whereClauses = append(whereClauses, "p.name = ?")
+		args = append(args, filter.Package)
+	}
+	if filter.Verified != nil {
+		whereClauses = append(whereClauses, "d.verified = ?")
+		args = append(args, *filter.Verified)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		whereClauses = append(whereClauses, "d.created_at >= ?")
+		args = append(args, sqliteTime(filter.CreatedAfter))
+	}
+	if pagination.Cursor != "" {
+		// The cursor is the ID of the last deployment on the previous page; continue
+		// after it in (created_at, id) order, since creation times can repeat
+		var cursorCreatedAt string
+		err := s.db.QueryRowContext(ctx, "SELECT created_at FROM deployments WHERE id = ?", pagination.Cursor).Scan(&cursorCreatedAt)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: no deployment %q", ErrInvalidCursor, pagination.Cursor)
+		}
+		if err != nil {
+			return nil, err
+		}
+		whereClauses = append(whereClauses, "(d.created_at < ? OR (d.created_at = ? AND d.id < ?))")
+		args = append(args, cursorCreatedAt, cursorCreatedAt, pagination.Cursor)
+	}
+
+	query := `SELECT d.id, d.package_id, d.contract_name, d.chain, d.chain_id, d.address,
+			d.deployer_address, d.tx_hash, d.block_number, d.deployment_data, d.verified, d.created_at
+		FROM deployments d
+		LEFT JOIN packages p ON p.id = d.package_id`
+	if len(whereClauses) > 0 {
+		query += " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+	query += " ORDER BY d.created_at DESC, d.id DESC LIMIT ?"
+	args = append(args, pagination.Limit+1)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -520,32 +1304,137 @@ func (s *SQLiteStore) ListDeployments(ctx context.Context, filter DeploymentFilt
 	var deployments []Deployment
 	for rows.Next() {
 		var d Deployment
-		if err := rows.Scan(&d.ID, &d.PackageID, &d.ContractName, &d.Chain, &d.ChainID, &d.Address, &d.Verified, &d.CreatedAt); err != nil {
+		var deploymentData sql.NullString
+		if err := rows.Scan(&d.ID, &d.PackageID, &d.ContractName, &d.Chain, &d.ChainID, &d.Address, &d.DeployerAddress, &d.TxHash, &d.BlockNumber, &deploymentData, &d.Verified, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		var err error
+		if d.DeploymentData, err = decodeDeploymentData([]byte(deploymentData.String)); err != nil {
 			return nil, err
 		}
 		deployments = append(deployments, d)
 	}
 
+	// Only forward pages are supported, so no PrevCursor
 	hasMore := len(deployments) > pagination.Limit
 	if hasMore {
 		deployments = deployments[:pagination.Limit]
 	}
-
-	return &PaginatedResult[Deployment]{Data: deployments, HasMore: hasMore}, rows.Err()
+	result := &PaginatedResult[Deployment]{Data: deployments, HasMore: hasMore}
+	if len(deployments) > 0 {
+		result.NextCursor = deployments[len(deployments)-1].ID
+	}
+	return result, rows.Err()
 }
 
 // UpdateVerificationStatus updates a deployment's verification status
 func (s *SQLiteStore) UpdateVerificationStatus(ctx context.Context, id string, verified bool, verifiedOn []string) error {
-	_, err := s.db.ExecContext(ctx, "UPDATE deployments SET verified = ?, verified_at = datetime('now') WHERE id = ?", verified, id)
-	return err
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	verifiedOnJSON, err := encodeVerifiedOn(verifiedOn)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var chain, chainID, address, wasVerifiedOnJSON string
+	var wasVerified bool
+	err = tx.QueryRowContext(ctx, "SELECT chain, chain_id, address, COALESCE(verified, 0), COALESCE(verified_on, '') FROM deployments WHERE id = ?", id).
+		Scan(&chain, &chainID, &address, &wasVerified, &wasVerifiedOnJSON)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	wasVerifiedOn, err := decodeVerifiedOn(wasVerifiedOnJSON)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE deployments SET verified = ?, verified_at = datetime('now'), verified_on = ? WHERE id = ?", verified, verifiedOnJSON, id); err != nil {
+		return err
+	}
+	events := verificationEvents(wasVerified, wasVerifiedOn, verified, verifiedOn)
+	if err := insertDeploymentEvents(ctx, tx, sqliteInsertDeploymentEvent, chain, chainID, address, events...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListDeploymentEvents returns the timeline of the deployment at chain/chainID/address,
+// oldest first
+func (s *SQLiteStore) ListDeploymentEvents(ctx context.Context, chain, chainID, address string) ([]DeploymentEvent, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT event_type, details, created_at FROM deployment_events
+		WHERE chain = ? AND chain_id = ? AND address = ?
+		ORDER BY id`, chain, chainID, address)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []DeploymentEvent
+	for rows.Next() {
+		var e DeploymentEvent
+		var details []byte
+		if err := rows.Scan(&e.Type, &details, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if e.Details, err = decodeEventDetails(details); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// CountDeploymentsByVersion returns deployment counts for every version of a package,
+// including versions with no deployments
+func (s *SQLiteStore) CountDeploymentsByVersion(ctx context.Context, packageName string) ([]VersionDeploymentCount, error) {
+	query := `
+		SELECT p.version, COUNT(d.id), COALESCE(SUM(CASE WHEN d.verified THEN 1 ELSE 0 END), 0)
+		FROM packages p
+		LEFT JOIN deployments d ON d.package_id = p.id
+		WHERE p.name = ?
+		GROUP BY p.version
+	`
+	rows, err := s.db.QueryContext(ctx, query, packageName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []VersionDeploymentCount
+	for rows.Next() {
+		var c VersionDeploymentCount
+		if err := rows.Scan(&c.Version, &c.Deployments, &c.Verified); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
 }
 
 // CreateAPIKey creates a new API key
-func (s *SQLiteStore) CreateAPIKey(ctx context.Context, name string) (string, error) {
+func (s *SQLiteStore) CreateAPIKey(ctx context.Context, name string, scopes map[string]any) (string, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	key := generateAPIKey()
 	hash := hashAPIKey(key)
 	id := generateID()
-	_, err := s.db.ExecContext(ctx, "INSERT INTO api_keys (id, key_hash, name, created_at) VALUES (?, ?, ?, datetime('now'))", id, hash, name)
+	scopesJSON, err := encodeScopes(scopes)
+	if err != nil {
+		return "", err
+	}
+	_, err = s.db.ExecContext(ctx, "INSERT INTO api_keys (id, key_hash, name, scopes, created_at) VALUES (?, ?, ?, ?, datetime('now'))", id, hash, name, scopesJSON)
 	if err != nil {
 		return "", err
 	}
@@ -556,15 +1445,46 @@ func (s *SQLiteStore) CreateAPIKey(ctx context.Context, name string) (string, er
 func (s *SQLiteStore) ValidateAPIKey(ctx context.Context, key string) (*APIKey, error) {
 	hash := hashAPIKey(key)
 	var ak APIKey
-	err := s.db.QueryRowContext(ctx, "SELECT id, key_hash, name, created_at FROM api_keys WHERE key_hash = ? AND revoked_at IS NULL", hash).Scan(
-		&ak.ID, &ak.KeyHash, &ak.Name, &ak.CreatedAt,
+	var scopes, lastUsed sql.NullString
+	err := s.db.QueryRowContext(ctx, "SELECT id, key_hash, name, scopes, created_at, last_used_at FROM api_keys WHERE key_hash = ? AND revoked_at IS NULL", hash).Scan(
+		&ak.ID, &ak.KeyHash, &ak.Name, &scopes, &ak.CreatedAt, &lastUsed,
 	)
 	if err == sql.ErrNoRows {
 		return nil, ErrNotFound
 	}
-	// Update last used
-	_, _ = s.db.ExecContext(ctx, "UPDATE api_keys SET last_used_at = datetime('now') WHERE id = ?", ak.ID)
-	return &ak, err
+	if err != nil {
+		return nil, err
+	}
+	if ak.Scopes, err = decodeScopes(scopes.String); err != nil {
+		return nil, err
+	}
+
+	// Best-effort and outside writeMu: auth on reads must not queue behind publishes,
+	// and busy_timeout covers the rare refresh that meets a writer
+	usedAt, _ := time.Parse("2006-01-02 15:04:05", lastUsed.String)
+	if lastUsedStale(usedAt, time.Now()) {
+		_, _ = s.db.ExecContext(ctx, "UPDATE api_keys SET last_used_at = datetime('now') WHERE id = ?", ak.ID)
+	}
+	return &ak, nil
+}
+
+// GetAPIKey gets an active (not revoked) API key by ID
+func (s *SQLiteStore) GetAPIKey(ctx context.Context, id string) (*APIKey, error) {
+	var ak APIKey
+	var scopes sql.NullString
+	err := s.db.QueryRowContext(ctx, "SELECT id, name, scopes, created_at FROM api_keys WHERE id = ? AND revoked_at IS NULL", id).Scan(
+		&ak.ID, &ak.Name, &scopes, &ak.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ak.Scopes, err = decodeScopes(scopes.String); err != nil {
+		return nil, err
+	}
+	return &ak, nil
 }
 
 // ListAPIKeys lists all API keys
@@ -592,6 +1512,34 @@ func (s *SQLiteStore) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
 
 // RevokeAPIKey revokes an API key
 func (s *SQLiteStore) RevokeAPIKey(ctx context.Context, id string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
 	_, err := s.db.ExecContext(ctx, "UPDATE api_keys SET revoked_at = datetime('now') WHERE id = ?", id)
 	return err
 }
+
+// GarbageCollect deletes artifacts whose contract no longer exists and blobs no
+// artifact references, in one transaction. With dryRun it only reports them.
+func (s *SQLiteStore) GarbageCollect(ctx context.Context, dryRun bool) (*GCResult, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := collectGarbage(ctx, tx, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -2,11 +2,22 @@ package storage
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"log/slog"
+
+	"github.com/pendergraft/contrafactory/internal/config"
 )
 
 func TestSQLiteStore(t *testing.T) {
@@ -211,6 +222,113 @@ func TestSQLiteStore(t *testing.T) {
 		}
 	})
 
+	t.Run("RecordAndGetDeploymentData", func(t *testing.T) {
+		d := &Deployment{
+			ID:           "deploy-1",
+			PackageID:    "test-id-1",
+			ContractName: "Token",
+			Chain:        "evm",
+			ChainID:      "1",
+			Address:      "0x1234567890abcdef1234567890abcdef12345678",
+			DeploymentData: DeploymentData{
+				ConstructorArgs: "0x01",
+				Libraries:       map[string]string{"Math": "0xabcdef1234567890abcdef1234567890abcdef12"},
+				Salt:            "0x00000000000000000000000000000000000000000000000000000000000000aa",
+				GasUsed:         1234567,
+				Timestamp:       time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+			TxHash:      "0xfeed",
+			BlockNumber: 19000000,
+		}
+		if err := store.RecordDeployment(ctx, d); err != nil {
+			t.Fatalf("RecordDeployment() error = %v", err)
+		}
+
+		got, err := store.GetDeployment(ctx, "evm", "1", d.Address)
+		if err != nil {
+			t.Fatalf("GetDeployment() error = %v", err)
+		}
+		if got.PackageName != "test-package" || got.PackageVersion != "1.0.0" {
+			t.Errorf("package = %s@%s, want test-package@1.0.0", got.PackageName, got.PackageVersion)
+		}
+		if !reflect.DeepEqual(got.DeploymentData, d.DeploymentData) {
+			t.Errorf("GetDeployment().DeploymentData = %+v, want %+v", got.DeploymentData, d.DeploymentData)
+		}
+
+		list, err := store.ListDeployments(ctx, DeploymentFilter{ChainID: "1"}, PaginationParams{Limit: 10})
+		if err != nil {
+			t.Fatalf("ListDeployments() error = %v", err)
+		}
+		if len(list.Data) != 1 {
+			t.Fatalf("ListDeployments() returned %d deployments, want 1", len(list.Data))
+		}
+		if item := list.Data[0]; item.TxHash != "0xfeed" || item.BlockNumber != 19000000 || !reflect.DeepEqual(item.DeploymentData, d.DeploymentData) {
+			t.Errorf("ListDeployments() = %+v, want tx hash, block number and deployment data", item)
+		}
+	})
+
+	t.Run("UpdateVerificationStatusPersistsVerifiedOn", func(t *testing.T) {
+		verifiedOn := []string{"etherscan", "blockscout:https://eth.blockscout.com"}
+		if err := store.UpdateVerificationStatus(ctx, "deploy-1", true, verifiedOn); err != nil {
+			t.Fatalf("UpdateVerificationStatus() error = %v", err)
+		}
+
+		got, err := store.GetDeployment(ctx, "evm", "1", "0x1234567890abcdef1234567890abcdef12345678")
+		if err != nil {
+			t.Fatalf("GetDeployment() error = %v", err)
+		}
+		if !got.Verified {
+			t.Error("Verified = false, want true")
+		}
+		if strings.Join(got.VerifiedOn, ",") != strings.Join(verifiedOn, ",") {
+			t.Errorf("VerifiedOn = %v, want %v", got.VerifiedOn, verifiedOn)
+		}
+
+		// Reset so later subtests see deploy-1 unverified
+		if err := store.UpdateVerificationStatus(ctx, "deploy-1", false, nil); err != nil {
+			t.Fatalf("UpdateVerificationStatus() error = %v", err)
+		}
+		got, err = store.GetDeployment(ctx, "evm", "1", "0x1234567890abcdef1234567890abcdef12345678")
+		if err != nil {
+			t.Fatalf("GetDeployment() error = %v", err)
+		}
+		if got.Verified || got.VerifiedOn != nil {
+			t.Errorf("after reset: Verified = %v, VerifiedOn = %v", got.Verified, got.VerifiedOn)
+		}
+	})
+
+	t.Run("CountDeploymentsByVersion", func(t *testing.T) {
+		d := &Deployment{
+			ID:           "deploy-2",
+			PackageID:    "test-id-1",
+			ContractName: "Token",
+			Chain:        "evm",
+			ChainID:      "10",
+			Address:      "0x1234567890abcdef1234567890abcdef12345678",
+		}
+		if err := store.RecordDeployment(ctx, d); err != nil {
+			t.Fatalf("RecordDeployment() error = %v", err)
+		}
+		if err := store.UpdateVerificationStatus(ctx, "deploy-2", true, nil); err != nil {
+			t.Fatalf("UpdateVerificationStatus() error = %v", err)
+		}
+
+		counts, err := store.CountDeploymentsByVersion(ctx, "test-package")
+		if err != nil {
+			t.Fatalf("CountDeploymentsByVersion() error = %v", err)
+		}
+		byVersion := make(map[string]VersionDeploymentCount)
+		for _, c := range counts {
+			byVersion[c.Version] = c
+		}
+		if got := byVersion["1.0.0"]; got.Deployments != 2 || got.Verified != 1 {
+			t.Errorf("1.0.0 counts = %+v, want 2 deployments, 1 verified", got)
+		}
+		if got, ok := byVersion["1.1.0"]; !ok || got.Deployments != 0 {
+			t.Errorf("1.1.0 counts = %+v (present %v), want 0 deployments", got, ok)
+		}
+	})
+
 	t.Run("DeletePackage", func(t *testing.T) {
 		if err := store.DeletePackage(ctx, "test-package", "1.1.0"); err != nil {
 			t.Fatalf("DeletePackage() error = %v", err)
@@ -246,23 +364,25 @@ func TestListPackagesFilters(t *testing.T) {
 
 	// Create packages: pkg-a and pkg-b in project "proj1", pkg-c in project "proj2"
 	// pkg-a has versions 1.0.0, 1.1.0, 2.0.0; pkg-b has 1.0.0; pkg-c has 1.0.0
+	// pkg-a@2.0.0 and pkg-b are audited
 	for _, p := range []struct {
 		id, name, version, project string
+		metadata                   map[string]string
 	}{
-		{"id-a1", "pkg-a", "1.0.0", "proj1"},
-		{"id-a2", "pkg-a", "1.1.0", "proj1"},
-		{"id-a3", "pkg-a", "2.0.0", "proj1"},
-		{"id-b1", "pkg-b", "1.0.0", "proj1"},
-		{"id-c1", "pkg-c", "1.0.0", "proj2"},
+		{"id-a1", "pkg-a", "1.0.0", "proj1", nil},
+		{"id-a2", "pkg-a", "1.1.0", "proj1", map[string]string{"audit_status": "pending"}},
+		{"id-a3", "pkg-a", "2.0.0", "proj1", map[string]string{"audit_status": "passed", "team": "core"}},
+		{"id-b1", "pkg-b", "1.0.0", "proj1", map[string]string{"audit_status": "passed"}},
+		{"id-c1", "pkg-c", "1.0.0", "proj2", nil},
 	} {
-		pkg := &Package{ID: p.id, Name: p.name, Version: p.version, Project: p.project, Chain: "evm", Builder: "foundry"}
+		pkg := &Package{ID: p.id, Name: p.name, Version: p.version, Project: p.project, Chain: "evm", Builder: "foundry", Metadata: p.metadata}
 		if err := store.CreatePackage(ctx, pkg); err != nil {
 			t.Fatalf("CreatePackage %s@%s: %v", p.name, p.version, err)
 		}
 	}
 
-	// Create contracts: Token in pkg-a, Registry in pkg-b
-	if err := store.CreateContract(ctx, "id-a1", &Contract{ID: "c1", PackageID: "id-a1", Name: "Token", Chain: "evm", SourcePath: "src/Token.sol", PrimaryHash: "h1"}); err != nil {
+	// Create contracts: Token in pkg-a (labeled), Registry in pkg-b
+	if err := store.CreateContract(ctx, "id-a1", &Contract{ID: "c1", PackageID: "id-a1", Name: "Token", Chain: "evm", SourcePath: "src/Token.sol", PrimaryHash: "h1", Labels: []string{"erc20", "upgradeable"}, Metadata: map[string]string{"audit": "passed"}}); err != nil {
 		t.Fatalf("CreateContract: %v", err)
 	}
 	if err := store.CreateContract(ctx, "id-b1", &Contract{ID: "c2", PackageID: "id-b1", Name: "Registry", Chain: "evm", SourcePath: "src/Registry.sol", PrimaryHash: "h2"}); err != nil {
@@ -330,6 +450,71 @@ func TestListPackagesFilters(t *testing.T) {
 		}
 	})
 
+	t.Run("label filter", func(t *testing.T) {
+		result, err := store.ListPackages(ctx, PackageFilter{Label: "erc20"}, PaginationParams{Limit: 10})
+		if err != nil {
+			t.Fatalf("ListPackages() error = %v", err)
+		}
+		if len(result.Data) != 1 || result.Data[0].Name != "pkg-a" {
+			t.Fatalf("ListPackages(label=erc20) = %v, want pkg-a", result.Data)
+		}
+		if len(result.Data[0].Versions) != 1 || result.Data[0].Versions[0] != "1.0.0" {
+			t.Errorf("ListPackages(label=erc20) versions = %v, want [1.0.0]", result.Data[0].Versions)
+		}
+
+		result, err = store.ListPackages(ctx, PackageFilter{Label: "erc721"}, PaginationParams{Limit: 10})
+		if err != nil {
+			t.Fatalf("ListPackages() error = %v", err)
+		}
+		if len(result.Data) != 0 {
+			t.Errorf("ListPackages(label=erc721) = %v, want none", result.Data)
+		}
+	})
+
+	t.Run("contract labels round trip", func(t *testing.T) {
+		c, err := store.GetContract(ctx, "id-a1", "Token")
+		if err != nil {
+			t.Fatalf("GetContract() error = %v", err)
+		}
+		if len(c.Labels) != 2 || c.Labels[0] != "erc20" || c.Labels[1] != "upgradeable" {
+			t.Errorf("GetContract().Labels = %v, want [erc20 upgradeable]", c.Labels)
+		}
+
+		contracts, err := store.ListContracts(ctx, "id-b1")
+		if err != nil {
+			t.Fatalf("ListContracts() error = %v", err)
+		}
+		if len(contracts) != 1 || len(contracts[0].Labels) != 0 {
+			t.Errorf("ListContracts(id-b1) = %+v, want one unlabeled contract", contracts)
+		}
+	})
+
+	t.Run("contract metadata round trip", func(t *testing.T) {
+		c, err := store.GetContract(ctx, "id-a1", "Token")
+		if err != nil {
+			t.Fatalf("GetContract() error = %v", err)
+		}
+		if len(c.Metadata) != 1 || c.Metadata["audit"] != "passed" {
+			t.Errorf("GetContract().Metadata = %v, want map[audit:passed]", c.Metadata)
+		}
+
+		contracts, err := store.ListContracts(ctx, "id-a1")
+		if err != nil {
+			t.Fatalf("ListContracts() error = %v", err)
+		}
+		if len(contracts) != 1 || contracts[0].Metadata["audit"] != "passed" {
+			t.Errorf("ListContracts(id-a1) = %+v, want metadata audit=passed", contracts)
+		}
+
+		c, err = store.GetContract(ctx, "id-b1", "Registry")
+		if err != nil {
+			t.Fatalf("GetContract() error = %v", err)
+		}
+		if c.Metadata != nil {
+			t.Errorf("GetContract(Registry).Metadata = %v, want nil", c.Metadata)
+		}
+	})
+
 	t.Run("project and latest", func(t *testing.T) {
 		result, err := store.ListPackages(ctx, PackageFilter{Project: "proj1", Latest: true}, PaginationParams{Limit: 10})
 		if err != nil {
@@ -344,6 +529,60 @@ func TestListPackagesFilters(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("metadata filters", func(t *testing.T) {
+		result, err := store.ListPackages(ctx, PackageFilter{MetadataFilters: map[string]string{"audit_status": "passed"}}, PaginationParams{Limit: 10})
+		if err != nil {
+			t.Fatalf("ListPackages() error = %v", err)
+		}
+		if len(result.Data) != 2 || result.Data[0].Name != "pkg-a" || result.Data[1].Name != "pkg-b" {
+			t.Fatalf("audited packages = %+v, want pkg-a and pkg-b", result.Data)
+		}
+		if !slices.Equal(result.Data[0].Versions, []string{"2.0.0"}) {
+			t.Errorf("audited pkg-a versions = %v, want only 2.0.0", result.Data[0].Versions)
+		}
+
+		// Every pair must match, combined with the other filters
+		result, err = store.ListPackages(ctx, PackageFilter{Contract: "token", MetadataFilters: map[string]string{"audit_status": "passed", "team": "core"}}, PaginationParams{Limit: 10})
+		if err != nil {
+			t.Fatalf("ListPackages() error = %v", err)
+		}
+		if len(result.Data) != 0 {
+			t.Errorf("Token is only in pkg-a 1.x, got %+v", result.Data)
+		}
+		result, err = store.ListPackages(ctx, PackageFilter{MetadataFilters: map[string]string{"audit_status": "passed", "team": "core"}}, PaginationParams{Limit: 10})
+		if err != nil {
+			t.Fatalf("ListPackages() error = %v", err)
+		}
+		if len(result.Data) != 1 || result.Data[0].Name != "pkg-a" {
+			t.Errorf("ListPackages(audited core) = %+v, want pkg-a", result.Data)
+		}
+	})
+
+	t.Run("count", func(t *testing.T) {
+		tests := []struct {
+			filter PackageFilter
+			want   int
+		}{
+			{PackageFilter{}, 3},
+			{PackageFilter{Project: "proj1"}, 2},
+			{PackageFilter{Version: "1.0.0"}, 3},
+			{PackageFilter{Contract: "token"}, 1},
+			{PackageFilter{Label: "erc20"}, 1},
+			{PackageFilter{Query: "pkg-c"}, 1},
+			{PackageFilter{MetadataFilters: map[string]string{"audit_status": "passed"}}, 2},
+			{PackageFilter{MetadataFilters: map[string]string{"audit_status": "Passed"}}, 0},
+		}
+		for _, tt := range tests {
+			got, err := store.CountPackages(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("CountPackages(%+v) error = %v", tt.filter, err)
+			}
+			if got != tt.want {
+				t.Errorf("CountPackages(%+v) = %d, want %d", tt.filter, got, tt.want)
+			}
+		}
+	})
 }
 
 func contains(s []string, v string) bool {
@@ -355,49 +594,1184 @@ func contains(s []string, v string) bool {
 	return false
 }
 
-func TestAPIKey(t *testing.T) {
-	tmpDir, err := os.MkdirTemp("", "contrafactory-test-*")
-	if err != nil {
-		t.Fatal(err)
-	}
-	defer os.RemoveAll(tmpDir)
-
-	dbPath := filepath.Join(tmpDir, "test.db")
+func TestListPackagesPagination(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
-
-	store, err := NewSQLiteStore(dbPath, logger)
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), logger)
 	if err != nil {
-		t.Fatal(err)
+		t.Fatalf("NewSQLiteStore() error = %v", err)
 	}
 	defer store.Close()
 
 	ctx := context.Background()
-	store.Migrate(ctx)
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	for _, name := range []string{"pkg-a", "pkg-b", "pkg-c", "pkg-d", "pkg-e"} {
+		pkg := &Package{ID: "id-" + name, Name: name, Version: "1.0.0", Chain: "evm", Builder: "foundry"}
+		if err := store.CreatePackage(ctx, pkg); err != nil {
+			t.Fatalf("CreatePackage %s: %v", name, err)
+		}
+	}
 
-	t.Run("CreateAndValidateAPIKey", func(t *testing.T) {
-		key, err := store.CreateAPIKey(ctx, "test-key")
+	list := func(pagination PaginationParams) *PaginatedResult[Package] {
+		t.Helper()
+		pagination.Limit = 2
+		result, err := store.ListPackages(ctx, PackageFilter{}, pagination)
 		if err != nil {
-			t.Fatalf("CreateAPIKey() error = %v", err)
+			t.Fatalf("ListPackages() error = %v", err)
 		}
-
-		if key == "" {
-			t.Fatal("CreateAPIKey() returned empty key")
+		return result
+	}
+	names := func(result *PaginatedResult[Package]) string {
+		var out []string
+		for _, p := range result.Data {
+			out = append(out, p.Name)
 		}
+		return strings.Join(out, ",")
+	}
+
+	// Forward through all pages
+	first := list(PaginationParams{})
+	if names(first) != "pkg-a,pkg-b" || !first.HasMore || first.PrevCursor != "" {
+		t.Fatalf("first page = %s (hasMore %v, prev %q)", names(first), first.HasMore, first.PrevCursor)
+	}
+	second := list(PaginationParams{Cursor: first.NextCursor})
+	if names(second) != "pkg-c,pkg-d" || !second.HasMore || second.PrevCursor != "pkg-c" {
+		t.Fatalf("second page = %s (hasMore %v, prev %q)", names(second), second.HasMore, second.PrevCursor)
+	}
+	last := list(PaginationParams{Cursor: second.NextCursor})
+	if names(last) != "pkg-e" || last.HasMore {
+		t.Fatalf("last page = %s (hasMore %v)", names(last), last.HasMore)
+	}
+
+	// And back again: each backward page matches the forward page it returns to
+	back := list(PaginationParams{Before: last.PrevCursor})
+	if names(back) != names(second) || !back.HasMore || back.NextCursor != second.NextCursor || back.PrevCursor != "pkg-c" {
+		t.Fatalf("back to second page = %s (hasMore %v, next %q, prev %q)", names(back), back.HasMore, back.NextCursor, back.PrevCursor)
+	}
+	back = list(PaginationParams{Before: back.PrevCursor})
+	if names(back) != names(first) || back.NextCursor != first.NextCursor || back.PrevCursor != "" {
+		t.Fatalf("back to first page = %s (next %q, prev %q)", names(back), back.NextCursor, back.PrevCursor)
+	}
+}
 
+func TestListPackagesByOwner(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	keyIDs := make(map[string]string)
+	for _, name := range []string{"key-1", "key-2"} {
+		key, err := store.CreateAPIKey(ctx, name, nil)
+		if err != nil {
+			t.Fatalf("CreateAPIKey() error = %v", err)
+		}
 		apiKey, err := store.ValidateAPIKey(ctx, key)
 		if err != nil {
 			t.Fatalf("ValidateAPIKey() error = %v", err)
 		}
+		keyIDs[name] = apiKey.ID
+	}
+	for i, p := range []struct{ name, version, owner string }{
+		{"pkg-a", "1.0.0", "key-1"},
+		{"pkg-a", "1.1.0", "key-1"},
+		{"pkg-b", "1.0.0", "key-2"},
+		{"pkg-c", "1.0.0", "key-1"},
+		{"pkg-d", "1.0.0", "key-1"},
+	} {
+		pkg := &Package{ID: fmt.Sprintf("id-%d", i), Name: p.name, Version: p.version, Chain: "evm", Builder: "foundry"}
+		if err := store.CreatePackage(ctx, pkg); err != nil {
+			t.Fatalf("CreatePackage %s@%s: %v", p.name, p.version, err)
+		}
+		if err := store.SetPackageOwner(ctx, p.name, keyIDs[p.owner]); err != nil {
+			t.Fatalf("SetPackageOwner %s: %v", p.name, err)
+		}
+	}
 
-		if apiKey.Name != "test-key" {
-			t.Errorf("ValidateAPIKey().Name = %v, want test-key", apiKey.Name)
+	first, err := store.ListPackagesByOwner(ctx, keyIDs["key-1"], PaginationParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListPackagesByOwner() error = %v", err)
+	}
+	if len(first.Data) != 2 || first.Data[0].Name != "pkg-a" || first.Data[1].Name != "pkg-c" || !first.HasMore {
+		t.Fatalf("first page = %+v (hasMore %v), want pkg-a, pkg-c", first.Data, first.HasMore)
+	}
+	if len(first.Data[0].Versions) != 2 {
+		t.Errorf("pkg-a versions = %v, want both", first.Data[0].Versions)
+	}
+
+	second, err := store.ListPackagesByOwner(ctx, keyIDs["key-1"], PaginationParams{Limit: 2, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("ListPackagesByOwner() error = %v", err)
+	}
+	if len(second.Data) != 1 || second.Data[0].Name != "pkg-d" || second.HasMore {
+		t.Fatalf("second page = %+v (hasMore %v), want pkg-d", second.Data, second.HasMore)
+	}
+
+	none, err := store.ListPackagesByOwner(ctx, "unknown-key", PaginationParams{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListPackagesByOwner() error = %v", err)
+	}
+	if len(none.Data) != 0 {
+		t.Errorf("ListPackagesByOwner(unknown-key) = %+v, want none", none.Data)
+	}
+}
+
+func TestGetPackageVersionsPrerelease(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), logger)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	for _, version := range []string{"1.0.0", "2.0.0-beta.1", "2.0.0-rc.1", "1.1.0+build-7"} {
+		pkg := &Package{ID: "id-" + version, Name: "token", Version: version, Chain: "evm", Builder: "foundry"}
+		if err := store.CreatePackage(ctx, pkg); err != nil {
+			t.Fatalf("CreatePackage %s: %v", version, err)
 		}
-	})
+	}
 
-	t.Run("InvalidAPIKey", func(t *testing.T) {
-		_, err := store.ValidateAPIKey(ctx, "invalid-key")
-		if err == nil {
-			t.Error("ValidateAPIKey() should return error for invalid key")
+	tests := []struct {
+		includePrerelease bool
+		want              []string
+	}{
+		{false, []string{"1.0.0", "1.1.0+build-7"}},
+		{true, []string{"1.0.0", "1.1.0+build-7", "2.0.0-beta.1", "2.0.0-rc.1"}},
+	}
+	for _, tt := range tests {
+		versions, err := store.GetPackageVersions(ctx, "token", tt.includePrerelease)
+		if err != nil {
+			t.Fatalf("GetPackageVersions(%v) error = %v", tt.includePrerelease, err)
 		}
-	})
+		sort.Strings(versions)
+		if strings.Join(versions, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("GetPackageVersions(%v) = %v, want %v", tt.includePrerelease, versions, tt.want)
+		}
+	}
+}
+
+func TestGetPackageVersionDetails(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	for _, pkg := range []*Package{
+		{ID: "id-1", Name: "token", Version: "1.0.0", Chain: "evm", Builder: "foundry", CompilerVersion: "0.8.20"},
+		{ID: "id-2", Name: "token", Version: "2.0.0-rc.1", Chain: "evm", Builder: "standard-json", CompilerVersion: "0.8.28"},
+		{ID: "id-3", Name: "vault", Version: "1.0.0", Chain: "evm", Builder: "foundry", CompilerVersion: "0.8.28"},
+	} {
+		if err := store.CreatePackage(ctx, pkg); err != nil {
+			t.Fatalf("CreatePackage %s@%s: %v", pkg.Name, pkg.Version, err)
+		}
+	}
+
+	details, err := store.GetPackageVersionDetails(ctx, "token")
+	if err != nil {
+		t.Fatalf("GetPackageVersionDetails() error = %v", err)
+	}
+	if len(details) != 2 {
+		t.Fatalf("GetPackageVersionDetails() = %d versions, want 2 (prereleases included)", len(details))
+	}
+	byVersion := make(map[string]VersionDetail)
+	for _, d := range details {
+		if d.CreatedAt == "" {
+			t.Errorf("%s: CreatedAt is empty", d.Version)
+		}
+		byVersion[d.Version] = d
+	}
+	if d := byVersion["2.0.0-rc.1"]; d.Builder != "standard-json" || d.CompilerVersion != "0.8.28" || d.Chain != "evm" {
+		t.Errorf("2.0.0-rc.1 = %+v", d)
+	}
+	if d := byVersion["1.0.0"]; d.Builder != "foundry" || d.CompilerVersion != "0.8.20" {
+		t.Errorf("1.0.0 = %+v", d)
+	}
+}
+
+func TestListCreatedAfter(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), logger)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	// One package version and one deployment per timestamp
+	times := map[string]string{
+		"1.0.0": "2026-01-01 09:59:59",
+		"1.1.0": "2026-01-01 10:00:00",
+		"1.2.0": "2026-01-01 10:00:01",
+	}
+	for version, createdAt := range times {
+		pkg := &Package{ID: "id-" + version, Name: "token", Version: version, Chain: "evm", Builder: "foundry"}
+		if err := store.CreatePackage(ctx, pkg); err != nil {
+			t.Fatalf("CreatePackage %s: %v", version, err)
+		}
+		d := &Deployment{ID: "deploy-" + version, PackageID: pkg.ID, ContractName: "Token", Chain: "evm", ChainID: "1",
+			Address: "0x00000000000000000000000000000000000000" + strings.ReplaceAll(version, ".", "")[:2]}
+		if err := store.RecordDeployment(ctx, d); err != nil {
+			t.Fatalf("RecordDeployment %s: %v", version, err)
+		}
+		if _, err := store.db.ExecContext(ctx, "UPDATE packages SET created_at = ? WHERE id = ?", createdAt, pkg.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.db.ExecContext(ctx, "UPDATE deployments SET created_at = ? WHERE id = ?", createdAt, d.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 10:00:00 in another zone, to check the bound is compared in UTC
+	bound := time.Date(2026, 1, 1, 12, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+
+	pkgs, err := store.ListPackages(ctx, PackageFilter{CreatedAfter: bound}, PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListPackages() error = %v", err)
+	}
+	if len(pkgs.Data) != 1 {
+		t.Fatalf("ListPackages() returned %d packages, want 1", len(pkgs.Data))
+	}
+	versions := pkgs.Data[0].Versions
+	sort.Strings(versions)
+	if strings.Join(versions, ",") != "1.1.0,1.2.0" {
+		t.Errorf("versions created after bound = %v, want [1.1.0 1.2.0] (inclusive)", versions)
+	}
+
+	deployments, err := store.ListDeployments(ctx, DeploymentFilter{CreatedAfter: bound}, PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListDeployments() error = %v", err)
+	}
+	var ids []string
+	for _, d := range deployments.Data {
+		ids = append(ids, d.ID)
+	}
+	if strings.Join(ids, ",") != "deploy-1.2.0,deploy-1.1.0" {
+		t.Errorf("deployments created after bound = %v, want [deploy-1.2.0 deploy-1.1.0]", ids)
+	}
+
+	// Just past the last timestamp: nothing
+	pkgs, err = store.ListPackages(ctx, PackageFilter{CreatedAfter: time.Date(2026, 1, 1, 10, 0, 2, 0, time.UTC)}, PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListPackages() error = %v", err)
+	}
+	if len(pkgs.Data) != 0 {
+		t.Errorf("ListPackages() after last version = %v, want none", pkgs.Data)
+	}
+
+	// Other deployment filters still apply
+	verified := true
+	deployments, err = store.ListDeployments(ctx, DeploymentFilter{CreatedAfter: bound, Verified: &verified}, PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListDeployments() error = %v", err)
+	}
+	if len(deployments.Data) != 0 {
+		t.Errorf("verified deployments = %d, want 0", len(deployments.Data))
+	}
+}
+
+func TestListDeploymentsPagination(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), logger)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	pkg := &Package{ID: "pkg-1", Name: "token", Version: "1.0.0", Chain: "evm", Builder: "foundry"}
+	if err := store.CreatePackage(ctx, pkg); err != nil {
+		t.Fatalf("CreatePackage() error = %v", err)
+	}
+	// Two deployments share a timestamp, so pages must break ties by ID
+	for i, createdAt := range []string{"2026-01-01 10:00:00", "2026-01-01 10:00:01", "2026-01-01 10:00:01"} {
+		d := &Deployment{ID: fmt.Sprintf("deploy-%d", i), PackageID: pkg.ID, ContractName: "Token", Chain: "evm", ChainID: "1",
+			Address: fmt.Sprintf("0x%040d", i)}
+		if err := store.RecordDeployment(ctx, d); err != nil {
+			t.Fatalf("RecordDeployment %d: %v", i, err)
+		}
+		if _, err := store.db.ExecContext(ctx, "UPDATE deployments SET created_at = ? WHERE id = ?", createdAt, d.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ids []string
+	page := PaginationParams{Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages == 3 {
+			t.Fatalf("ListDeployments() did not finish paging, got %v", ids)
+		}
+		result, err := store.ListDeployments(ctx, DeploymentFilter{}, page)
+		if err != nil {
+			t.Fatalf("ListDeployments(%+v) error = %v", page, err)
+		}
+		for _, d := range result.Data {
+			ids = append(ids, d.ID)
+		}
+		if !result.HasMore {
+			break
+		}
+		page.Cursor = result.NextCursor
+	}
+	if strings.Join(ids, ",") != "deploy-2,deploy-1,deploy-0" {
+		t.Errorf("paged deployments = %v, want [deploy-2 deploy-1 deploy-0]", ids)
+	}
+
+	_, err = store.ListDeployments(ctx, DeploymentFilter{}, PaginationParams{Limit: 2, Cursor: "no-such-deployment"})
+	if !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("ListDeployments() with unknown cursor error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestFindContractsByHash(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"), logger)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	creation, deployed := []byte("0x6080"), []byte("0x6001")
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		pkgID := "id-" + version
+		if err := store.CreatePackage(ctx, &Package{ID: pkgID, Name: "tokens", Version: version, Chain: "evm", Builder: "foundry"}); err != nil {
+			t.Fatalf("CreatePackage: %v", err)
+		}
+		contract := &Contract{ID: "c-" + version, PackageID: pkgID, Name: "Token", Chain: "evm", PrimaryHash: HashContent(creation)}
+		if err := store.CreateContract(ctx, pkgID, contract); err != nil {
+			t.Fatalf("CreateContract: %v", err)
+		}
+		if err := store.StoreArtifact(ctx, contract.ID, "deployed-bytecode", deployed); err != nil {
+			t.Fatalf("StoreArtifact: %v", err)
+		}
+	}
+
+	matches, err := store.FindContractsByHash(ctx, HashContent(deployed))
+	if err != nil {
+		t.Fatalf("FindContractsByHash() error = %v", err)
+	}
+	if len(matches) != 2 || matches[0].Version != "1.0.0" || matches[1].Version != "1.1.0" {
+		t.Fatalf("FindContractsByHash(deployed) = %+v, want Token in 1.0.0 and 1.1.0", matches)
+	}
+	if m := matches[0]; m.PackageName != "tokens" || m.ContractName != "Token" || m.MatchedOn != "deployed-bytecode" {
+		t.Errorf("match = %+v", m)
+	}
+
+	matches, err = store.FindContractsByHash(ctx, HashContent(creation))
+	if err != nil {
+		t.Fatalf("FindContractsByHash() error = %v", err)
+	}
+	if len(matches) != 2 || matches[0].MatchedOn != "bytecode" {
+		t.Errorf("FindContractsByHash(creation) = %+v, want 2 bytecode matches", matches)
+	}
+
+	matches, err = store.FindContractsByHash(ctx, HashContent([]byte("unknown")))
+	if err != nil || len(matches) != 0 {
+		t.Errorf("FindContractsByHash(unknown) = %+v, %v; want no matches", matches, err)
+	}
+}
+
+func TestFindContractsBySelector(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	abi := []byte(`[
+		{"type":"function","name":"transfer","inputs":[{"type":"address"},{"type":"uint256"}]},
+		{"type":"event","name":"Transfer","inputs":[{"type":"address"},{"type":"address"},{"type":"uint256"}]},
+		{"type":"constructor","inputs":[]}
+	]`)
+	selectors := SelectorsFromABI(abi)
+	if len(selectors) != 2 {
+		t.Fatalf("SelectorsFromABI() = %+v, want the function and the event", selectors)
+	}
+	if selectors[0].Selector != "0xa9059cbb" || selectors[0].Signature != "transfer(address,uint256)" {
+		t.Errorf("function selector = %+v", selectors[0])
+	}
+
+	for _, name := range []string{"vault", "tokens"} {
+		pkgID := "id-" + name
+		if err := store.CreatePackage(ctx, &Package{ID: pkgID, Name: name, Version: "1.0.0", Chain: "evm", Builder: "foundry"}); err != nil {
+			t.Fatalf("CreatePackage: %v", err)
+		}
+		contract := &Contract{ID: "c-" + name, PackageID: pkgID, Name: "Token", Chain: "evm", Selectors: selectors}
+		if err := store.CreateContract(ctx, pkgID, contract); err != nil {
+			t.Fatalf("CreateContract: %v", err)
+		}
+	}
+
+	matches, err := store.FindContractsBySelector(ctx, "0xa9059cbb")
+	if err != nil {
+		t.Fatalf("FindContractsBySelector() error = %v", err)
+	}
+	if len(matches) != 2 || matches[0].PackageName != "tokens" || matches[1].PackageName != "vault" {
+		t.Fatalf("FindContractsBySelector() = %+v, want Token in tokens and vault", matches)
+	}
+	if m := matches[0]; m.ContractName != "Token" || m.Type != "function" || m.Signature != "transfer(address,uint256)" {
+		t.Errorf("match = %+v", m)
+	}
+
+	matches, err = store.FindContractsBySelector(ctx, selectors[1].Selector)
+	if err != nil || len(matches) != 2 || matches[0].Type != "event" {
+		t.Errorf("FindContractsBySelector(event) = %+v, %v; want 2 event matches", matches, err)
+	}
+
+	matches, err = store.FindContractsBySelector(ctx, "0xdeadbeef")
+	if err != nil || len(matches) != 0 {
+		t.Errorf("FindContractsBySelector(unknown) = %+v, %v; want no matches", matches, err)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "contrafactory-test-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dbPath := filepath.Join(tmpDir, "test.db")
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	store, err := NewSQLiteStore(dbPath, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	store.Migrate(ctx)
+
+	t.Run("CreateAndValidateAPIKey", func(t *testing.T) {
+		key, err := store.CreateAPIKey(ctx, "test-key", nil)
+		if err != nil {
+			t.Fatalf("CreateAPIKey() error = %v", err)
+		}
+
+		if key == "" {
+			t.Fatal("CreateAPIKey() returned empty key")
+		}
+
+		apiKey, err := store.ValidateAPIKey(ctx, key)
+		if err != nil {
+			t.Fatalf("ValidateAPIKey() error = %v", err)
+		}
+
+		if apiKey.Name != "test-key" {
+			t.Errorf("ValidateAPIKey().Name = %v, want test-key", apiKey.Name)
+		}
+	})
+
+	t.Run("InvalidAPIKey", func(t *testing.T) {
+		_, err := store.ValidateAPIKey(ctx, "invalid-key")
+		if err == nil {
+			t.Error("ValidateAPIKey() should return error for invalid key")
+		}
+	})
+
+	t.Run("LastUsedRefreshIsThrottled", func(t *testing.T) {
+		key, err := store.CreateAPIKey(ctx, "busy-key", nil)
+		if err != nil {
+			t.Fatalf("CreateAPIKey() error = %v", err)
+		}
+		lastUsed := func() string {
+			var v sql.NullString
+			if err := store.db.QueryRowContext(ctx, "SELECT last_used_at FROM api_keys WHERE name = 'busy-key'").Scan(&v); err != nil {
+				t.Fatal(err)
+			}
+			return v.String
+		}
+		setLastUsed := func(v string) {
+			if _, err := store.db.ExecContext(ctx, "UPDATE api_keys SET last_used_at = ? WHERE name = 'busy-key'", v); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Validation doesn't wait for writers holding the store's write lock
+		store.writeMu.Lock()
+		done := make(chan error, 1)
+		go func() {
+			_, err := store.ValidateAPIKey(ctx, key)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			store.writeMu.Unlock()
+			if err != nil {
+				t.Fatalf("ValidateAPIKey() error = %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			store.writeMu.Unlock()
+			t.Fatal("ValidateAPIKey() blocked on the write lock")
+		}
+		if lastUsed() == "" {
+			t.Error("last_used_at not set on first use")
+		}
+
+		recent := sqliteTime(time.Now().Add(-10 * time.Second))
+		setLastUsed(recent)
+		if _, err := store.ValidateAPIKey(ctx, key); err != nil {
+			t.Fatalf("ValidateAPIKey() error = %v", err)
+		}
+		if got := lastUsed(); got != recent {
+			t.Errorf("last_used_at = %q, want %q left alone within a minute", got, recent)
+		}
+
+		setLastUsed("2020-01-01 00:00:00")
+		if _, err := store.ValidateAPIKey(ctx, key); err != nil {
+			t.Fatalf("ValidateAPIKey() error = %v", err)
+		}
+		if got := lastUsed(); got == "2020-01-01 00:00:00" {
+			t.Error("stale last_used_at not refreshed")
+		}
+	})
+
+	t.Run("AdminScope", func(t *testing.T) {
+		key, err := store.CreateAPIKey(ctx, "admin-key", map[string]any{ScopeAdmin: true})
+		if err != nil {
+			t.Fatalf("CreateAPIKey() error = %v", err)
+		}
+		apiKey, err := store.ValidateAPIKey(ctx, key)
+		if err != nil {
+			t.Fatalf("ValidateAPIKey() error = %v", err)
+		}
+		if !apiKey.IsAdmin() {
+			t.Errorf("IsAdmin() = false, want true (scopes %v)", apiKey.Scopes)
+		}
+	})
+
+	t.Run("PackageOwnerInfo", func(t *testing.T) {
+		key, err := store.CreateAPIKey(ctx, "ci-release", nil)
+		if err != nil {
+			t.Fatalf("CreateAPIKey() error = %v", err)
+		}
+		apiKey, err := store.ValidateAPIKey(ctx, key)
+		if err != nil {
+			t.Fatalf("ValidateAPIKey() error = %v", err)
+		}
+		if apiKey.IsAdmin() {
+			t.Error("IsAdmin() = true for a key without scopes")
+		}
+
+		if _, err := store.GetPackageOwnerInfo(ctx, "owned-package"); err != ErrNotFound {
+			t.Fatalf("GetPackageOwnerInfo() before claim error = %v, want ErrNotFound", err)
+		}
+		if err := store.SetPackageOwner(ctx, "owned-package", apiKey.ID); err != nil {
+			t.Fatalf("SetPackageOwner() error = %v", err)
+		}
+		owner, err := store.GetPackageOwnerInfo(ctx, "owned-package")
+		if err != nil {
+			t.Fatalf("GetPackageOwnerInfo() error = %v", err)
+		}
+		if owner.KeyID != apiKey.ID || owner.KeyName != "ci-release" || owner.CreatedAt == "" {
+			t.Errorf("GetPackageOwnerInfo() = %+v", owner)
+		}
+	})
+
+	t.Run("GetAPIKey", func(t *testing.T) {
+		key, err := store.CreateAPIKey(ctx, "lookup-key", nil)
+		if err != nil {
+			t.Fatalf("CreateAPIKey() error = %v", err)
+		}
+		apiKey, _ := store.ValidateAPIKey(ctx, key)
+
+		got, err := store.GetAPIKey(ctx, apiKey.ID)
+		if err != nil || got.Name != "lookup-key" {
+			t.Fatalf("GetAPIKey() = %+v, %v", got, err)
+		}
+
+		if err := store.RevokeAPIKey(ctx, apiKey.ID); err != nil {
+			t.Fatalf("RevokeAPIKey() error = %v", err)
+		}
+		if _, err := store.GetAPIKey(ctx, apiKey.ID); err != ErrNotFound {
+			t.Errorf("GetAPIKey() on revoked key error = %v, want ErrNotFound", err)
+		}
+		if _, err := store.GetAPIKey(ctx, "no-such-key"); err != ErrNotFound {
+			t.Errorf("GetAPIKey() on unknown key error = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("UpdatePackageOwner", func(t *testing.T) {
+		var ids []string
+		for _, name := range []string{"old-owner", "new-owner"} {
+			key, err := store.CreateAPIKey(ctx, name, nil)
+			if err != nil {
+				t.Fatalf("CreateAPIKey() error = %v", err)
+			}
+			apiKey, _ := store.ValidateAPIKey(ctx, key)
+			ids = append(ids, apiKey.ID)
+		}
+		from, to := ids[0], ids[1]
+
+		if err := store.SetPackageOwner(ctx, "moving-package", from); err != nil {
+			t.Fatalf("SetPackageOwner() error = %v", err)
+		}
+
+		// Only the current owner can hand the package on
+		if err := store.UpdatePackageOwner(ctx, "moving-package", to, from); err != ErrNotFound {
+			t.Errorf("UpdatePackageOwner() from non-owner error = %v, want ErrNotFound", err)
+		}
+		if err := store.UpdatePackageOwner(ctx, "moving-package", from, to); err != nil {
+			t.Fatalf("UpdatePackageOwner() error = %v", err)
+		}
+
+		owner, err := store.GetPackageOwner(ctx, "moving-package")
+		if err != nil || owner != to {
+			t.Errorf("GetPackageOwner() = %q, %v; want %q", owner, err, to)
+		}
+
+		var auditFrom, auditTo string
+		err = store.db.QueryRowContext(ctx, "SELECT from_key_id, to_key_id FROM package_owner_transfers WHERE package_name = ?", "moving-package").Scan(&auditFrom, &auditTo)
+		if err != nil {
+			t.Fatalf("reading transfer audit row: %v", err)
+		}
+		if auditFrom != from || auditTo != to {
+			t.Errorf("audit row = %s -> %s, want %s -> %s", auditFrom, auditTo, from, to)
+		}
+	})
+
+	t.Run("PackageCollaborators", func(t *testing.T) {
+		ids := make(map[string]string)
+		for _, name := range []string{"team-owner", "team-ci", "outsider"} {
+			key, err := store.CreateAPIKey(ctx, name, nil)
+			if err != nil {
+				t.Fatalf("CreateAPIKey() error = %v", err)
+			}
+			apiKey, _ := store.ValidateAPIKey(ctx, key)
+			ids[name] = apiKey.ID
+		}
+
+		// Unowned names are open to anyone
+		if ok, err := store.IsAuthorized(ctx, "team-package", ids["outsider"]); err != nil || !ok {
+			t.Errorf("IsAuthorized() on unowned package = %v, %v; want true", ok, err)
+		}
+
+		if err := store.SetPackageOwner(ctx, "team-package", ids["team-owner"]); err != nil {
+			t.Fatalf("SetPackageOwner() error = %v", err)
+		}
+		if err := store.AddPackageCollaborator(ctx, "team-package", ids["team-ci"]); err != nil {
+			t.Fatalf("AddPackageCollaborator() error = %v", err)
+		}
+		if err := store.AddPackageCollaborator(ctx, "team-package", ids["team-ci"]); err != nil {
+			t.Fatalf("AddPackageCollaborator() again error = %v", err)
+		}
+
+		for name, want := range map[string]bool{"team-owner": true, "team-ci": true, "outsider": false} {
+			if ok, err := store.IsAuthorized(ctx, "team-package", ids[name]); err != nil || ok != want {
+				t.Errorf("IsAuthorized(%s) = %v, %v; want %v", name, ok, err, want)
+			}
+		}
+		if ok, _ := store.IsAuthorized(ctx, "team-package", ""); ok {
+			t.Error("IsAuthorized() without a key on an owned package = true, want false")
+		}
+
+		collaborators, err := store.ListPackageCollaborators(ctx, "team-package")
+		if err != nil {
+			t.Fatalf("ListPackageCollaborators() error = %v", err)
+		}
+		if len(collaborators) != 1 || collaborators[0].KeyID != ids["team-ci"] || collaborators[0].KeyName != "team-ci" || collaborators[0].CreatedAt == "" {
+			t.Errorf("ListPackageCollaborators() = %+v, want team-ci", collaborators)
+		}
+
+		if err := store.RemovePackageCollaborator(ctx, "team-package", ids["team-ci"]); err != nil {
+			t.Fatalf("RemovePackageCollaborator() error = %v", err)
+		}
+		if err := store.RemovePackageCollaborator(ctx, "team-package", ids["team-ci"]); err != ErrNotFound {
+			t.Errorf("RemovePackageCollaborator() again error = %v, want ErrNotFound", err)
+		}
+		if ok, _ := store.IsAuthorized(ctx, "team-package", ids["team-ci"]); ok {
+			t.Error("IsAuthorized() after removal = true, want false")
+		}
+	})
+
+	t.Run("GetOwnerArtifactBytes", func(t *testing.T) {
+		key, err := store.CreateAPIKey(ctx, "quota-key", nil)
+		if err != nil {
+			t.Fatalf("CreateAPIKey() error = %v", err)
+		}
+		apiKey, _ := store.ValidateAPIKey(ctx, key)
+
+		if total, err := store.GetOwnerArtifactBytes(ctx, apiKey.ID); err != nil || total != 0 {
+			t.Fatalf("GetOwnerArtifactBytes() with nothing published = %d, %v; want 0", total, err)
+		}
+
+		// Two versions of an owned package count; an unowned package doesn't
+		for _, p := range []struct{ id, name, version string }{
+			{"quota-1", "quota-pkg", "1.0.0"},
+			{"quota-2", "quota-pkg", "1.1.0"},
+			{"quota-3", "other-pkg", "1.0.0"},
+		} {
+			if err := store.CreatePackage(ctx, &Package{ID: p.id, Name: p.name, Version: p.version, Chain: "evm"}); err != nil {
+				t.Fatalf("CreatePackage() error = %v", err)
+			}
+			contractID := p.id + "-token"
+			if err := store.CreateContract(ctx, p.id, &Contract{ID: contractID, PackageID: p.id, Name: "Token", Chain: "evm", SourcePath: "src/Token.sol", PrimaryHash: p.id}); err != nil {
+				t.Fatalf("CreateContract() error = %v", err)
+			}
+			if err := store.StoreArtifact(ctx, contractID, "abi", []byte("[]")); err != nil {
+				t.Fatalf("StoreArtifact() error = %v", err)
+			}
+			if err := store.StoreArtifact(ctx, contractID, "bytecode", []byte("0x6080")); err != nil {
+				t.Fatalf("StoreArtifact() error = %v", err)
+			}
+		}
+		if err := store.SetPackageOwner(ctx, "quota-pkg", apiKey.ID); err != nil {
+			t.Fatalf("SetPackageOwner() error = %v", err)
+		}
+
+		total, err := store.GetOwnerArtifactBytes(ctx, apiKey.ID)
+		if err != nil {
+			t.Fatalf("GetOwnerArtifactBytes() error = %v", err)
+		}
+		if total != 2*(2+6) {
+			t.Errorf("GetOwnerArtifactBytes() = %d, want %d", total, 2*(2+6))
+		}
+	})
+}
+
+func TestNewSQLiteStorePool(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	t.Run("default pool", func(t *testing.T) {
+		store, err := New(config.StorageConfig{Type: "sqlite", SQLite: config.SQLiteConfig{Path: path}}, logger)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		defer store.Close()
+		if got := store.(*SQLiteStore).db.Stats().MaxOpenConnections; got != 1 {
+			t.Errorf("MaxOpenConnections = %d, want 1", got)
+		}
+	})
+
+	t.Run("configured pool applies pragmas to every connection", func(t *testing.T) {
+		store, err := New(config.StorageConfig{Type: "sqlite", SQLite: config.SQLiteConfig{Path: path}, MaxOpenConns: 6}, logger)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		defer store.Close()
+		db := store.(*SQLiteStore).db.DB
+		if got := db.Stats().MaxOpenConnections; got != 6 {
+			t.Errorf("MaxOpenConnections = %d, want 6", got)
+		}
+
+		// Hold connections open so each check runs on a different one
+		ctx := context.Background()
+		for i := range 3 {
+			conn, err := db.Conn(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			var foreignKeys int
+			if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+				t.Fatal(err)
+			}
+			if foreignKeys != 1 {
+				t.Errorf("connection %d: foreign_keys = %d, want 1", i, foreignKeys)
+			}
+			var busyTimeout int
+			if err := conn.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout); err != nil {
+				t.Fatal(err)
+			}
+			if busyTimeout != 5000 {
+				t.Errorf("connection %d: busy_timeout = %d, want 5000", i, busyTimeout)
+			}
+		}
+	})
+}
+
+func TestSQLiteStoreConcurrentWrites(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	store, err := New(config.StorageConfig{Type: "sqlite", SQLite: config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "test.db")}, MaxOpenConns: 8}, logger)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	// Publishes write a package, its contracts and artifacts while other requests read
+	const publishes = 40
+	var wg sync.WaitGroup
+	errs := make(chan error, publishes*2)
+	for i := range publishes {
+		wg.Go(func() {
+			pkg := &Package{ID: fmt.Sprintf("pkg-%d", i), Name: fmt.Sprintf("pkg-%d", i), Version: "1.0.0", Chain: "evm", Builder: "foundry"}
+			if err := store.CreatePackage(ctx, pkg); err != nil {
+				errs <- fmt.Errorf("CreatePackage %s: %w", pkg.Name, err)
+				return
+			}
+			contract := &Contract{ID: "c-" + pkg.ID, PackageID: pkg.ID, Name: "Token", Chain: "evm", Labels: []string{"erc20"}}
+			if err := store.CreateContract(ctx, pkg.ID, contract); err != nil {
+				errs <- fmt.Errorf("CreateContract %s: %w", pkg.Name, err)
+				return
+			}
+			if err := store.StoreArtifact(ctx, contract.ID, "abi", []byte("[]")); err != nil {
+				errs <- fmt.Errorf("StoreArtifact %s: %w", pkg.Name, err)
+			}
+		})
+		wg.Go(func() {
+			if _, err := store.ListPackages(ctx, PackageFilter{}, PaginationParams{Limit: 100}); err != nil {
+				errs <- fmt.Errorf("ListPackages: %w", err)
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	count, err := store.CountPackages(ctx, PackageFilter{})
+	if err != nil {
+		t.Fatalf("CountPackages() error = %v", err)
+	}
+	if count != publishes {
+		t.Errorf("CountPackages() = %d, want %d", count, publishes)
+	}
+}
+
+func TestGarbageCollect(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	if err := store.CreatePackage(ctx, &Package{ID: "p1", Name: "tokens", Version: "1.0.0", Chain: "evm", Builder: "foundry"}); err != nil {
+		t.Fatalf("CreatePackage: %v", err)
+	}
+	if err := store.CreateContract(ctx, "p1", &Contract{ID: "c1", PackageID: "p1", Name: "Token", Chain: "evm"}); err != nil {
+		t.Fatalf("CreateContract: %v", err)
+	}
+	if err := store.StoreArtifact(ctx, "c1", "abi", []byte("[]")); err != nil {
+		t.Fatalf("StoreArtifact: %v", err)
+	}
+
+	// A live artifact backed by a blob, an orphaned artifact (NULL contract_id)
+	// holding the only reference to a second blob, and a blob nothing references
+	for _, stmt := range []string{
+		`UPDATE artifacts SET blob_store_ref = 'live' WHERE contract_id = 'c1'`,
+		`INSERT INTO artifacts (id, contract_id, artifact_type, content_hash, content, blob_store_ref, size_bytes) VALUES ('a-orphan', NULL, 'abi', 'h', x'00', 'freed', 10)`,
+		`INSERT INTO blobs (hash, content, size_bytes) VALUES ('live', x'00', 100), ('freed', x'00', 200), ('unused', x'00', 300)`,
+	} {
+		if _, err := store.db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	want := GCResult{OrphanedArtifacts: 1, OrphanedBlobs: 2, ReclaimedBytes: 510}
+	dry, err := store.GarbageCollect(ctx, true)
+	if err != nil {
+		t.Fatalf("GarbageCollect(dryRun) error = %v", err)
+	}
+	if *dry != want {
+		t.Errorf("GarbageCollect(dryRun) = %+v, want %+v", *dry, want)
+	}
+
+	got, err := store.GarbageCollect(ctx, false)
+	if err != nil {
+		t.Fatalf("GarbageCollect() error = %v", err)
+	}
+	if *got != want {
+		t.Errorf("GarbageCollect() = %+v, want %+v", *got, want)
+	}
+
+	var artifacts, blobs int
+	store.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM artifacts`).Scan(&artifacts)
+	store.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM blobs`).Scan(&blobs)
+	if artifacts != 1 || blobs != 1 {
+		t.Errorf("after GC: %d artifacts, %d blobs; want 1 and 1", artifacts, blobs)
+	}
+	if _, err := store.GetArtifact(ctx, "c1", "abi"); err != nil {
+		t.Errorf("live artifact: %v", err)
+	}
+
+	again, err := store.GarbageCollect(ctx, false)
+	if err != nil || *again != (GCResult{}) {
+		t.Errorf("second GarbageCollect() = %+v, %v; want nothing collected", again, err)
+	}
+}
+
+func TestDeploymentEvents(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if err := store.CreatePackage(ctx, &Package{ID: "p1", Name: "tokens", Version: "1.0.0", Chain: "evm", Builder: "foundry"}); err != nil {
+		t.Fatalf("CreatePackage: %v", err)
+	}
+
+	d := &Deployment{ID: "d1", PackageID: "p1", ContractName: "Token", Chain: "evm", ChainID: "1", Address: "0xaa", TxHash: "0xt", BlockNumber: 42}
+	if err := store.RecordDeployment(ctx, d); err != nil {
+		t.Fatalf("RecordDeployment: %v", err)
+	}
+	for _, update := range []struct {
+		verified   bool
+		verifiedOn []string
+	}{
+		{true, []string{"etherscan"}},
+		{true, []string{"etherscan"}}, // no change, no event
+		{true, []string{"etherscan", "blockscout:https://eth.blockscout.com"}},
+		{false, nil},
+	} {
+		if err := store.UpdateVerificationStatus(ctx, d.ID, update.verified, update.verifiedOn); err != nil {
+			t.Fatalf("UpdateVerificationStatus: %v", err)
+		}
+	}
+
+	events, err := store.ListDeploymentEvents(ctx, "evm", "1", "0xaa")
+	if err != nil {
+		t.Fatalf("ListDeploymentEvents() error = %v", err)
+	}
+	var got []string
+	for _, e := range events {
+		got = append(got, e.Type+" "+fmt.Sprint(e.Details))
+		if e.CreatedAt == "" {
+			t.Errorf("event %s has no timestamp", e.Type)
+		}
+	}
+	want := []string{
+		"deployed map[blockNumber:42 contract:Token txHash:0xt]",
+		"verified map[explorer:etherscan]",
+		"verified map[explorer:blockscout:https://eth.blockscout.com]",
+		"unverified map[]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %q, want %q", got, want)
+	}
+
+	events, err = store.ListDeploymentEvents(ctx, "evm", "1", "0xbb")
+	if err != nil || len(events) != 0 {
+		t.Errorf("ListDeploymentEvents(unknown) = %+v, %v; want none", events, err)
+	}
+}
+
+func TestPackageAliases(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	for _, v := range []string{"1.0.0", "1.1.0"} {
+		if err := store.CreatePackage(ctx, &Package{ID: "p" + v, Name: "tokens", Version: v, Chain: "evm", Builder: "foundry"}); err != nil {
+			t.Fatalf("CreatePackage: %v", err)
+		}
+	}
+
+	if _, err := store.GetPackageAlias(ctx, "tokens", "stable"); err != ErrNotFound {
+		t.Fatalf("GetPackageAlias() before set error = %v, want ErrNotFound", err)
+	}
+	if err := store.SetPackageAlias(ctx, "tokens", "stable", "1.0.0"); err != nil {
+		t.Fatalf("SetPackageAlias() error = %v", err)
+	}
+	if err := store.SetPackageAlias(ctx, "tokens", "next", "1.1.0"); err != nil {
+		t.Fatalf("SetPackageAlias() error = %v", err)
+	}
+	// Moving an alias replaces its version
+	if err := store.SetPackageAlias(ctx, "tokens", "stable", "1.1.0"); err != nil {
+		t.Fatalf("SetPackageAlias() move error = %v", err)
+	}
+	if v, err := store.GetPackageAlias(ctx, "tokens", "stable"); err != nil || v != "1.1.0" {
+		t.Errorf("GetPackageAlias() = %q, %v; want 1.1.0", v, err)
+	}
+
+	aliases, err := store.ListPackageAliases(ctx, "tokens")
+	if err != nil {
+		t.Fatalf("ListPackageAliases() error = %v", err)
+	}
+	if len(aliases) != 2 || aliases[0].Alias != "next" || aliases[1].Alias != "stable" || aliases[1].UpdatedAt == "" {
+		t.Errorf("ListPackageAliases() = %+v, want next and stable", aliases)
+	}
+
+	if err := store.DeletePackageAlias(ctx, "tokens", "next"); err != nil {
+		t.Fatalf("DeletePackageAlias() error = %v", err)
+	}
+	if err := store.DeletePackageAlias(ctx, "tokens", "next"); err != ErrNotFound {
+		t.Errorf("DeletePackageAlias() twice error = %v, want ErrNotFound", err)
+	}
+
+	// Deleting a version drops the aliases pointing at it
+	if err := store.DeletePackage(ctx, "tokens", "1.1.0"); err != nil {
+		t.Fatalf("DeletePackage() error = %v", err)
+	}
+	if _, err := store.GetPackageAlias(ctx, "tokens", "stable"); err != ErrNotFound {
+		t.Errorf("GetPackageAlias() after deleting its version error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPackageTags(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	packages := []Package{
+		{ID: "p1", Name: "tokens", Version: "1.0.0", Tags: []string{"defi", "erc20"}},
+		{ID: "p2", Name: "tokens", Version: "1.1.0", Tags: []string{"erc20"}},
+		{ID: "p3", Name: "dao", Version: "1.0.0", Tags: []string{"governance", "erc20"}},
+		{ID: "p4", Name: "misc", Version: "1.0.0"},
+	}
+	for i := range packages {
+		packages[i].Chain, packages[i].Builder = "evm", "foundry"
+		if err := store.CreatePackage(ctx, &packages[i]); err != nil {
+			t.Fatalf("CreatePackage: %v", err)
+		}
+	}
+
+	pkg, err := store.GetPackage(ctx, "tokens", "1.0.0")
+	if err != nil {
+		t.Fatalf("GetPackage() error = %v", err)
+	}
+	if !slices.Equal(pkg.Tags, []string{"defi", "erc20"}) {
+		t.Errorf("GetPackage().Tags = %v, want [defi erc20]", pkg.Tags)
+	}
+
+	result, err := store.ListPackages(ctx, PackageFilter{Tag: "defi"}, PaginationParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListPackages() error = %v", err)
+	}
+	if len(result.Data) != 1 || result.Data[0].Name != "tokens" || !slices.Equal(result.Data[0].Versions, []string{"1.0.0"}) {
+		t.Errorf("ListPackages(tag=defi) = %+v, want tokens@1.0.0", result.Data)
+	}
+	if count, err := store.CountPackages(ctx, PackageFilter{Tag: "erc20"}); err != nil || count != 2 {
+		t.Errorf("CountPackages(tag=erc20) = %d, %v, want 2", count, err)
+	}
+
+	tags, err := store.ListPackageTags(ctx)
+	if err != nil {
+		t.Fatalf("ListPackageTags() error = %v", err)
+	}
+	want := []TagCount{{Tag: "defi", Packages: 1}, {Tag: "erc20", Packages: 2}, {Tag: "governance", Packages: 1}}
+	if !slices.Equal(tags, want) {
+		t.Errorf("ListPackageTags() = %v, want %v", tags, want)
+	}
+
+	// Deleting the only version carrying a tag drops it from the list
+	if err := store.DeletePackage(ctx, "tokens", "1.0.0"); err != nil {
+		t.Fatalf("DeletePackage() error = %v", err)
+	}
+	tags, err = store.ListPackageTags(ctx)
+	if err != nil {
+		t.Fatalf("ListPackageTags() error = %v", err)
+	}
+	want = []TagCount{{Tag: "erc20", Packages: 2}, {Tag: "governance", Packages: 1}}
+	if !slices.Equal(tags, want) {
+		t.Errorf("ListPackageTags() after delete = %v, want %v", tags, want)
+	}
+}
+
+func TestCreatePackageTagFailureRollsBack(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if _, err := store.db.ExecContext(ctx, `CREATE TRIGGER reject_tag BEFORE INSERT ON package_tags
+		WHEN NEW.tag = 'broken' BEGIN SELECT RAISE(ABORT, 'tag rejected'); END`); err != nil {
+		t.Fatalf("creating trigger: %v", err)
+	}
+
+	pkg := &Package{ID: "p1", Name: "tokens", Version: "1.0.0", Chain: "evm", Tags: []string{"defi", "broken"}}
+	if err := store.CreatePackage(ctx, pkg); err == nil {
+		t.Fatal("CreatePackage() succeeded, want the tag insert to fail")
+	}
+	if _, err := store.GetPackage(ctx, "tokens", "1.0.0"); err != ErrNotFound {
+		t.Errorf("GetPackage() error = %v, want the version rolled back", err)
+	}
+
+	// A retry is not blocked by a half-published version
+	pkg.Tags = []string{"defi"}
+	if err := store.CreatePackage(ctx, pkg); err != nil {
+		t.Fatalf("retried CreatePackage() error = %v", err)
+	}
+}
+
+func TestCreatePackageVersionsIsAtomic(t *testing.T) {
+	store := newTestSQLiteDB(t)
+	ctx := context.Background()
+	if err := store.Migrate(ctx); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	key, err := store.CreateAPIKey(ctx, "publisher", nil)
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	apiKey, err := store.ValidateAPIKey(ctx, key)
+	if err != nil {
+		t.Fatalf("ValidateAPIKey() error = %v", err)
+	}
+	if _, err := store.db.ExecContext(ctx, `CREATE TRIGGER reject_artifact BEFORE INSERT ON artifacts
+		WHEN NEW.artifact_type = 'storage-layout' BEGIN SELECT RAISE(ABORT, 'artifact rejected'); END`); err != nil {
+		t.Fatalf("creating trigger: %v", err)
+	}
+
+	version := func(i int, name string, artifactTypes ...string) PackageVersion {
+		contract := &Contract{ID: fmt.Sprintf("c%d", i), Name: "Token", Chain: "evm", SourcePath: "src/Token.sol", PrimaryHash: "h"}
+		var artifacts []ArtifactContent
+		for _, typ := range artifactTypes {
+			artifacts = append(artifacts, ArtifactContent{Type: typ, Content: []byte(typ)})
+		}
+		return PackageVersion{
+			Package:    &Package{ID: fmt.Sprintf("p%d", i), Name: name, Version: "1.0.0", Chain: "evm", Tags: []string{"defi"}},
+			Contracts:  []ContractArtifacts{{Contract: contract, Artifacts: artifacts}},
+			OwnerKeyID: apiKey.ID,
+		}
+	}
+	versions := []PackageVersion{
+		version(1, "token", "abi", "bytecode"),
+		// Fails after its package, owner, contract and first artifact are written
+		version(2, "vault", "abi", "storage-layout"),
+		version(3, "registry", "abi"),
+	}
+	if err := store.CreatePackageVersions(ctx, versions); err == nil {
+		t.Fatal("CreatePackageVersions() succeeded, want the storage-layout insert to fail")
+	}
+
+	for _, table := range []string{"packages", "package_owners", "package_tags", "contracts", "artifacts"} {
+		var n int
+		if err := store.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&n); err != nil {
+			t.Fatalf("counting %s: %v", table, err)
+		}
+		if n != 0 {
+			t.Errorf("%s has %d rows after the failed batch, want 0", table, n)
+		}
+	}
+
+	// Without the failing artifact the same batch goes through
+	versions[1] = version(2, "vault", "abi")
+	if err := store.CreatePackageVersions(ctx, versions); err != nil {
+		t.Fatalf("retried CreatePackageVersions() error = %v", err)
+	}
+	owner, err := store.GetPackageOwner(ctx, "vault")
+	if err != nil || owner != apiKey.ID {
+		t.Errorf("GetPackageOwner(vault) = %q, %v, want %q", owner, err, apiKey.ID)
+	}
+	if content, err := store.GetArtifact(ctx, "c1", "bytecode"); err != nil || string(content) != "bytecode" {
+		t.Errorf("GetArtifact(c1, bytecode) = %q, %v", content, err)
+	}
 }
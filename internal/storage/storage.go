@@ -2,8 +2,10 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/pendergraft/contrafactory/internal/config"
 )
@@ -11,13 +13,29 @@ import (
 // PackageStore handles package operations
 type PackageStore interface {
 	CreatePackage(ctx context.Context, pkg *Package) error
+	CreatePackageVersions(ctx context.Context, versions []PackageVersion) error
 	GetPackage(ctx context.Context, name, version string) (*Package, error)
 	GetPackageVersions(ctx context.Context, name string, includePrerelease bool) ([]string, error)
+	GetPackageVersionDetails(ctx context.Context, name string) ([]VersionDetail, error)
 	ListPackages(ctx context.Context, filter PackageFilter, pagination PaginationParams) (*PaginatedResult[Package], error)
+	ListPackagesByOwner(ctx context.Context, ownerKeyID string, pagination PaginationParams) (*PaginatedResult[Package], error)
+	CountPackages(ctx context.Context, filter PackageFilter) (int, error)
 	DeletePackage(ctx context.Context, name, version string) error
 	PackageExists(ctx context.Context, name, version string) (bool, error)
 	GetPackageOwner(ctx context.Context, name string) (string, error)
+	GetPackageOwnerInfo(ctx context.Context, name string) (*PackageOwner, error)
 	SetPackageOwner(ctx context.Context, name, ownerKeyID string) error
+	UpdatePackageOwner(ctx context.Context, name, fromKeyID, toKeyID string) error
+	IsAuthorized(ctx context.Context, name, keyID string) (bool, error)
+	AddPackageCollaborator(ctx context.Context, name, keyID string) error
+	RemovePackageCollaborator(ctx context.Context, name, keyID string) error
+	ListPackageCollaborators(ctx context.Context, name string) ([]PackageCollaborator, error)
+	SetPackageAlias(ctx context.Context, name, alias, version string) error
+	GetPackageAlias(ctx context.Context, name, alias string) (string, error)
+	ListPackageAliases(ctx context.Context, name string) ([]PackageAlias, error)
+	DeletePackageAlias(ctx context.Context, name, alias string) error
+	ListPackageTags(ctx context.Context) ([]TagCount, error)
+	GetOwnerArtifactBytes(ctx context.Context, ownerKeyID string) (int64, error)
 }
 
 // ContractStore handles contract operations
@@ -28,6 +46,8 @@ type ContractStore interface {
 	StoreArtifact(ctx context.Context, contractID, artifactType string, content []byte) error
 	GetArtifact(ctx context.Context, contractID, artifactType string) ([]byte, error)
 	GetArtifactByHash(ctx context.Context, hash string) ([]byte, error)
+	FindContractsByHash(ctx context.Context, hash string) ([]BytecodeMatch, error)
+	FindContractsBySelector(ctx context.Context, selector string) ([]SelectorMatch, error)
 }
 
 // DeploymentStore handles deployment operations
@@ -36,16 +56,24 @@ type DeploymentStore interface {
 	GetDeployment(ctx context.Context, chain, chainID, address string) (*Deployment, error)
 	ListDeployments(ctx context.Context, filter DeploymentFilter, pagination PaginationParams) (*PaginatedResult[Deployment], error)
 	UpdateVerificationStatus(ctx context.Context, id string, verified bool, verifiedOn []string) error
+	CountDeploymentsByVersion(ctx context.Context, packageName string) ([]VersionDeploymentCount, error)
+	ListDeploymentEvents(ctx context.Context, chain, chainID, address string) ([]DeploymentEvent, error)
 }
 
 // APIKeyStore handles API key operations
 type APIKeyStore interface {
-	CreateAPIKey(ctx context.Context, name string) (key string, err error)
+	CreateAPIKey(ctx context.Context, name string, scopes map[string]any) (key string, err error)
 	ValidateAPIKey(ctx context.Context, key string) (*APIKey, error)
+	GetAPIKey(ctx context.Context, id string) (*APIKey, error)
 	ListAPIKeys(ctx context.Context) ([]APIKey, error)
 	RevokeAPIKey(ctx context.Context, id string) error
 }
 
+// MaintenanceStore handles housekeeping that runs outside request handling
+type MaintenanceStore interface {
+	GarbageCollect(ctx context.Context, dryRun bool) (*GCResult, error)
+}
+
 // Store combines all storage interfaces with lifecycle methods.
 // Domain services define their own minimal interfaces based on their actual usage.
 type Store interface {
@@ -53,12 +81,21 @@ type Store interface {
 	ContractStore
 	DeploymentStore
 	APIKeyStore
+	MaintenanceStore
 
 	// Lifecycle
 	Close() error
 	Migrate(ctx context.Context) error
 }
 
+// GCResult reports what a garbage collection pass removed, or would remove on a
+// dry run
+type GCResult struct {
+	OrphanedArtifacts int64 // artifacts rows whose contract no longer exists
+	OrphanedBlobs     int64 // blobs rows no artifact references
+	ReclaimedBytes    int64 // size_bytes of both
+}
+
 // Package represents a published package version
 type Package struct {
 	ID               string
@@ -70,12 +107,34 @@ type Package struct {
 	CompilerVersion  string
 	CompilerSettings map[string]any
 	Metadata         map[string]string
-	OwnerID          string // API key ID that first published this package
+	Tags             []string // Package-level tags for discovery (e.g. defi, governance)
+	OwnerID          string   // API key ID that first published this package
 	CreatedAt        string
 	Versions         []string // Used for list aggregation (not stored directly)
 	Contracts        []string // Used when inlining contracts in list response (not stored directly)
 }
 
+// PackageVersion is everything a publish stores for one version: the package with
+// its tags, its contracts and their artifacts, and the key that claims the package
+// name if nobody owns it yet
+type PackageVersion struct {
+	Package    *Package
+	Contracts  []ContractArtifacts
+	OwnerKeyID string // empty leaves the package unclaimed
+}
+
+// ContractArtifacts is a contract together with the artifacts stored under it
+type ContractArtifacts struct {
+	Contract  *Contract
+	Artifacts []ArtifactContent
+}
+
+// ArtifactContent is an artifact to store, by type (abi, bytecode, ...)
+type ArtifactContent struct {
+	Type    string
+	Content []byte
+}
+
 // Contract represents a contract within a package
 type Contract struct {
 	ID           string
@@ -86,9 +145,39 @@ type Contract struct {
 	License      string
 	PrimaryHash  string
 	MetadataHash string
+	Labels       []string          // Contract-level labels (e.g. erc20, upgradeable)
+	Selectors    []Selector        // ABI selectors to index; written on create, not read back
+	Metadata     map[string]string // Contract-level metadata (e.g. audit report)
 	CreatedAt    string
 }
 
+// Selector is a function, event or error selector from a contract's ABI, indexed
+// for reverse lookups
+type Selector struct {
+	Selector  string // 0x-prefixed hex: 4 bytes for functions and errors, 32 for events
+	Type      string // "function", "event" or "error"
+	Signature string // canonical signature, e.g. transfer(address,uint256)
+}
+
+// SelectorMatch is a contract whose ABI has a looked-up selector
+type SelectorMatch struct {
+	PackageName  string
+	Version      string
+	ContractName string
+	Chain        string
+	Type         string
+	Signature    string
+}
+
+// BytecodeMatch is a contract whose bytecode hashes to a looked-up value
+type BytecodeMatch struct {
+	PackageName  string
+	Version      string
+	ContractName string
+	Chain        string
+	MatchedOn    string // "bytecode", "program" (the primary hash) or "deployed-bytecode"
+}
+
 // Artifact represents a stored artifact (ABI, bytecode, etc.)
 type Artifact struct {
 	ID           string
@@ -102,6 +191,8 @@ type Artifact struct {
 type Deployment struct {
 	ID              string
 	PackageID       string
+	PackageName     string // joined from packages; only set by GetDeployment
+	PackageVersion  string // joined from packages; only set by GetDeployment
 	ContractName    string
 	Chain           string
 	ChainID         string
@@ -109,13 +200,55 @@ type Deployment struct {
 	DeployerAddress string
 	TxHash          string
 	BlockNumber     int64
-	DeploymentData  map[string]any
+	DeploymentData  DeploymentData
 	Verified        bool
 	VerifiedAt      string
 	VerifiedOn      []string
 	CreatedAt       string
 }
 
+// DeploymentData is what a deployment was made with, beyond where it lives: the
+// inputs needed to re-verify it and details of the deployment transaction. It is
+// stored as JSON in deployments.deployment_data.
+type DeploymentData struct {
+	ConstructorArgs string            `json:"constructorArgs,omitempty"` // ABI-encoded, 0x-prefixed
+	Libraries       map[string]string `json:"libraries,omitempty"`       // linked library name -> address
+	Salt            string            `json:"salt,omitempty"`            // CREATE2 salt
+	GasUsed         uint64            `json:"gasUsed,omitempty"`
+	Timestamp       time.Time         `json:"timestamp,omitzero"` // when the deployment was mined
+}
+
+// Deployment event types, appended to deployment_events by RecordDeployment and
+// UpdateVerificationStatus
+const (
+	DeploymentEventDeployed   = "deployed"   // recorded (again, if re-recorded)
+	DeploymentEventVerified   = "verified"   // verified, on the explorer in Details["explorer"] if any
+	DeploymentEventUnverified = "unverified" // verification withdrawn
+)
+
+// DeploymentEvent is one entry of a deployment's activity timeline
+type DeploymentEvent struct {
+	Type      string
+	Details   map[string]string
+	CreatedAt string
+}
+
+// VersionDetail describes how one version of a package was built
+type VersionDetail struct {
+	Version         string
+	Chain           string
+	Builder         string
+	CompilerVersion string
+	CreatedAt       string
+}
+
+// VersionDeploymentCount aggregates the deployments recorded against one package version
+type VersionDeploymentCount struct {
+	Version     string
+	Deployments int
+	Verified    int
+}
+
 // APIKey represents an API key
 type APIKey struct {
 	ID         string
@@ -127,6 +260,42 @@ type APIKey struct {
 	RevokedAt  string
 }
 
+// ScopeAdmin is the API key scope that grants access to every package's ownership details.
+const ScopeAdmin = "admin"
+
+// IsAdmin reports whether the key carries the admin scope.
+func (k *APIKey) IsAdmin() bool {
+	admin, _ := k.Scopes[ScopeAdmin].(bool)
+	return admin
+}
+
+// PackageOwner is the API key that owns a package name
+type PackageOwner struct {
+	KeyID     string
+	KeyName   string
+	CreatedAt string // when the name was claimed
+}
+
+// PackageCollaborator is an API key that may publish to a package name it doesn't own
+type PackageCollaborator struct {
+	KeyID     string
+	KeyName   string
+	CreatedAt string // when the key was added
+}
+
+// PackageAlias is a named pointer, such as "stable", at one version of a package
+type PackageAlias struct {
+	Alias     string
+	Version   string
+	UpdatedAt string // when the alias was last moved
+}
+
+// TagCount is a package tag and how many package names have a version carrying it
+type TagCount struct {
+	Tag      string
+	Packages int
+}
+
 // PackageFilter contains filter options for listing packages
 type PackageFilter struct {
 	Query    string
@@ -136,7 +305,16 @@ type PackageFilter struct {
 	Project  string
 	Version  string
 	Contract string
+	Label    string // Only packages with a contract carrying this label
+	Tag      string // Only package versions carrying this tag
+	Owner    string // Only packages whose name is owned by this API key ID
 	Latest   bool
+	// MetadataFilters keeps versions whose package metadata has every one of these
+	// key/value pairs (exact, case-sensitive matches).
+	MetadataFilters map[string]string
+	// CreatedAfter keeps versions created at or after this time (zero = no bound).
+	// The bound is inclusive so a sync resuming from the last seen timestamp misses nothing.
+	CreatedAfter time.Time
 }
 
 // DeploymentFilter contains filter options for listing deployments
@@ -145,12 +323,16 @@ type DeploymentFilter struct {
 	ChainID  string
 	Package  string
 	Verified *bool
+	// CreatedAfter keeps deployments recorded at or after this time (zero = no bound)
+	CreatedAfter time.Time
 }
 
-// PaginationParams contains pagination options
+// PaginationParams contains pagination options. Cursor pages forward from
+// (after) a key; Before pages backward from (before) a key. At most one is set.
 type PaginationParams struct {
 	Limit  int
 	Cursor string
+	Before string
 }
 
 // PaginatedResult contains paginated results
@@ -161,14 +343,38 @@ type PaginatedResult[T any] struct {
 	PrevCursor string
 }
 
-// New creates a new store based on configuration
+// New creates a new store based on configuration, applying its connection pool
+// settings over the backend's defaults.
 func New(cfg config.StorageConfig, logger *slog.Logger) (Store, error) {
 	switch cfg.Type {
 	case "sqlite":
-		return NewSQLiteStore(cfg.SQLite.Path, logger)
+		store, err := NewSQLiteStore(cfg.SQLite.Path, logger)
+		if err != nil {
+			return nil, err
+		}
+		configurePool(store.db.DB, cfg)
+		return store, nil
 	case "postgres":
-		return NewPostgresStore(cfg.Postgres.URL, logger)
+		store, err := NewPostgresStore(cfg.Postgres.URL, logger)
+		if err != nil {
+			return nil, err
+		}
+		configurePool(store.db.DB, cfg)
+		return store, nil
 	default:
 		return nil, fmt.Errorf("unknown storage type: %s", cfg.Type)
 	}
 }
+
+// configurePool applies the pool settings of cfg that are set (non-zero) to db.
+func configurePool(db *sql.DB, cfg config.StorageConfig) {
+	if cfg.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetimeSeconds > 0 {
+		db.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetimeSeconds) * time.Second)
+	}
+}
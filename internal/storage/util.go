@@ -1,14 +1,23 @@
 package storage
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/mod/semver"
+
+	"github.com/pendergraft/contrafactory/pkg/evmutil"
 )
 
 // generateID generates a new UUID
@@ -16,10 +25,16 @@ func generateID() string {
 	return uuid.New().String()
 }
 
-// computeHash computes SHA256 hash of content
-func computeHash(content []byte) string {
+// HashAlgorithm is the algorithm of artifact content hashes and contract primary
+// hashes, stored as their prefix.
+const HashAlgorithm = "sha256"
+
+// HashContent returns the stored hash of content: "sha256:" followed by the lowercase
+// hex digest. Clients recomputing a hash must hash the content exactly as published
+// (bytecode as its 0x-prefixed hex string).
+func HashContent(content []byte) string {
 	h := sha256.Sum256(content)
-	return hex.EncodeToString(h[:])
+	return HashAlgorithm + ":" + hex.EncodeToString(h[:])
 }
 
 // generateAPIKey generates a new API key
@@ -43,6 +58,174 @@ func nullIfEmpty(s string) interface{} {
 	return s
 }
 
+// encodeDeploymentData serializes deployment data as JSON ("{}" when empty)
+func encodeDeploymentData(data DeploymentData) (string, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("marshaling deployment data: %w", err)
+	}
+	return string(b), nil
+}
+
+// decodeDeploymentData parses stored deployment data JSON (the zero value when
+// empty). Unknown keys from older records are ignored.
+func decodeDeploymentData(raw []byte) (DeploymentData, error) {
+	var data DeploymentData
+	if len(raw) == 0 {
+		return data, nil
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return DeploymentData{}, fmt.Errorf("parsing deployment data: %w", err)
+	}
+	return data, nil
+}
+
+// encodeContractMetadata serializes contract metadata as JSON ("" when empty, stored
+// as NULL)
+func encodeContractMetadata(metadata map[string]string) (string, error) {
+	if len(metadata) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return "", fmt.Errorf("marshaling contract metadata: %w", err)
+	}
+	return string(b), nil
+}
+
+// decodeContractMetadata parses stored contract metadata (nil when empty)
+func decodeContractMetadata(raw []byte) (map[string]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var metadata map[string]string
+	if err := json.Unmarshal(raw, &metadata); err != nil {
+		return nil, fmt.Errorf("parsing contract metadata: %w", err)
+	}
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	return metadata, nil
+}
+
+// encodeScopes serializes API key scopes as JSON (NULL when empty)
+func encodeScopes(scopes map[string]any) (any, error) {
+	if len(scopes) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(scopes)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling scopes: %w", err)
+	}
+	return string(b), nil
+}
+
+// decodeScopes parses stored API key scopes (nil when empty)
+func decodeScopes(raw string) (map[string]any, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var scopes map[string]any
+	if err := json.Unmarshal([]byte(raw), &scopes); err != nil {
+		return nil, fmt.Errorf("parsing scopes: %w", err)
+	}
+	return scopes, nil
+}
+
+// execer runs a statement on either the store's pool or a transaction, so a write
+// can be made on its own or as one step of a larger transaction.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// queryContractLabels runs a query returning (contract_id, label) rows and
+// groups the labels by contract ID.
+func queryContractLabels(ctx context.Context, db *queryLogger, query string, args ...any) (map[string][]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying contract labels: %w", err)
+	}
+	defer rows.Close()
+
+	labels := make(map[string][]string)
+	for rows.Next() {
+		var contractID, label string
+		if err := rows.Scan(&contractID, &label); err != nil {
+			return nil, fmt.Errorf("scanning contract label: %w", err)
+		}
+		labels[contractID] = append(labels[contractID], label)
+	}
+	return labels, rows.Err()
+}
+
+// queryPackageTags runs a query returning one tag per row.
+func queryPackageTags(ctx context.Context, db *queryLogger, query string, args ...any) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// queryTagCounts counts, for every tag in use, the distinct package names with a
+// version carrying it. The query is the same in both dialects.
+func queryTagCounts(ctx context.Context, db *queryLogger) ([]TagCount, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT pt.tag, COUNT(DISTINCT p.name)
+		FROM package_tags pt
+		INNER JOIN packages p ON p.id = pt.package_id
+		GROUP BY pt.tag
+		ORDER BY pt.tag
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []TagCount
+	for rows.Next() {
+		var t TagCount
+		if err := rows.Scan(&t.Tag, &t.Packages); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// SelectorsFromABI returns the function, event and error selectors of a JSON ABI, for
+// indexing. An ABI that doesn't parse has none.
+func SelectorsFromABI(abi []byte) []Selector {
+	if len(abi) == 0 {
+		return nil
+	}
+	entries, err := evmutil.ParseABI(abi)
+	if err != nil {
+		return nil
+	}
+
+	var selectors []Selector
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if e.Selector == "" || seen[e.Key()] {
+			continue
+		}
+		seen[e.Key()] = true
+		selectors = append(selectors, Selector{Selector: e.Selector, Type: e.Type, Signature: e.Signature})
+	}
+	return selectors
+}
+
 // latestVersionBySemver returns the latest version from a list using semver sorting
 func latestVersionBySemver(versions []string) string {
 	if len(versions) == 0 {
@@ -66,3 +249,210 @@ func latestVersionBySemver(versions []string) string {
 	}
 	return latest
 }
+
+// withoutPrereleases returns versions minus those with a semver prerelease component
+// (e.g. 2.0.0-beta.1), keeping their order. Build metadata alone (1.0.0+build.5) is
+// not a prerelease.
+func withoutPrereleases(versions []string) []string {
+	stable := versions[:0]
+	for _, v := range versions {
+		if semver.Prerelease("v"+strings.TrimPrefix(v, "v")) == "" {
+			stable = append(stable, v)
+		}
+	}
+	return stable
+}
+
+// pageResult trims a page queried with LIMIT pagination.Limit+1 and computes its
+// cursors. Backward pages (pagination.Before set) are queried in descending key
+// order and are reversed here, so Data is always ascending. HasMore reports whether
+// a page follows this one; PrevCursor is set when a page precedes it.
+func pageResult[T any](items []T, pagination PaginationParams, key func(T) string) *PaginatedResult[T] {
+	overflow := len(items) > pagination.Limit
+	if overflow {
+		items = items[:pagination.Limit]
+	}
+	result := &PaginatedResult[T]{Data: items}
+	if len(items) == 0 {
+		return result
+	}
+
+	if pagination.Before != "" {
+		slices.Reverse(items)
+		// The page we came back from follows this one
+		result.HasMore = true
+		result.NextCursor = key(items[len(items)-1])
+		if overflow {
+			result.PrevCursor = key(items[0])
+		}
+		return result
+	}
+
+	result.HasMore = overflow
+	result.NextCursor = key(items[len(items)-1])
+	if pagination.Cursor != "" {
+		result.PrevCursor = key(items[0])
+	}
+	return result
+}
+
+func packageName(p Package) string { return p.Name }
+
+// encodeVerifiedOn serializes the explorers a deployment is verified on as a JSON array
+func encodeVerifiedOn(verifiedOn []string) (string, error) {
+	if verifiedOn == nil {
+		verifiedOn = []string{}
+	}
+	b, err := json.Marshal(verifiedOn)
+	if err != nil {
+		return "", fmt.Errorf("marshaling verified_on: %w", err)
+	}
+	return string(b), nil
+}
+
+// decodeVerifiedOn parses a stored verified_on JSON array (nil when empty)
+func decodeVerifiedOn(raw string) ([]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var verifiedOn []string
+	if err := json.Unmarshal([]byte(raw), &verifiedOn); err != nil {
+		return nil, fmt.Errorf("parsing verified_on: %w", err)
+	}
+	if len(verifiedOn) == 0 {
+		return nil, nil
+	}
+	return verifiedOn, nil
+}
+
+// lastUsedInterval is how stale an API key's last_used_at may get before validating
+// the key refreshes it. Refreshing it on every request would turn every
+// authenticated read into a write.
+const lastUsedInterval = time.Minute
+
+// lastUsedStale reports whether a key last used at lastUsed (zero when never used)
+// is due a last_used_at refresh at now.
+func lastUsedStale(lastUsed, now time.Time) bool {
+	return lastUsed.IsZero() || now.Sub(lastUsed) >= lastUsedInterval
+}
+
+// sqliteTime formats t like SQLite's datetime('now') (UTC, second precision) so it
+// compares correctly against stored created_at text
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// deployedEvent is the timeline entry for recording d
+func deployedEvent(d *Deployment) DeploymentEvent {
+	details := map[string]string{"contract": d.ContractName}
+	for key, value := range map[string]string{"txHash": d.TxHash, "deployer": d.DeployerAddress} {
+		if value != "" {
+			details[key] = value
+		}
+	}
+	if d.BlockNumber != 0 {
+		details["blockNumber"] = strconv.FormatInt(d.BlockNumber, 10)
+	}
+	return DeploymentEvent{Type: DeploymentEventDeployed, Details: details}
+}
+
+// verificationEvents are the timeline entries for a verification status change:
+// one "verified" event per newly listed explorer (or a bare one when the deployment
+// becomes verified without naming one), and "unverified" when it's withdrawn.
+func verificationEvents(wasVerified bool, wasVerifiedOn []string, verified bool, verifiedOn []string) []DeploymentEvent {
+	if !verified {
+		if wasVerified {
+			return []DeploymentEvent{{Type: DeploymentEventUnverified}}
+		}
+		return nil
+	}
+
+	var events []DeploymentEvent
+	for _, explorer := range verifiedOn {
+		if !slices.Contains(wasVerifiedOn, explorer) {
+			events = append(events, DeploymentEvent{Type: DeploymentEventVerified, Details: map[string]string{"explorer": explorer}})
+		}
+	}
+	if len(events) == 0 && !wasVerified {
+		events = append(events, DeploymentEvent{Type: DeploymentEventVerified})
+	}
+	return events
+}
+
+// insertDeploymentEvents appends events to the timeline of the deployment at
+// chain/chainID/address with insertQuery, the dialect's INSERT INTO
+// deployment_events (chain, chain_id, address, event_type, details).
+func insertDeploymentEvents(ctx context.Context, tx *sql.Tx, insertQuery, chain, chainID, address string, events ...DeploymentEvent) error {
+	for _, e := range events {
+		details, err := encodeEventDetails(e.Details)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, insertQuery, chain, chainID, address, e.Type, details); err != nil {
+			return fmt.Errorf("recording %s event: %w", e.Type, err)
+		}
+	}
+	return nil
+}
+
+// encodeEventDetails serializes event details as a JSON object (NULL when empty)
+func encodeEventDetails(details map[string]string) (any, error) {
+	if len(details) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(details)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling event details: %w", err)
+	}
+	return string(b), nil
+}
+
+// decodeEventDetails parses stored event details (nil when empty)
+func decodeEventDetails(raw []byte) (map[string]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var details map[string]string
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return nil, fmt.Errorf("parsing event details: %w", err)
+	}
+	return details, nil
+}
+
+// Orphan predicates shared by both backends. Artifacts are normally removed with
+// their contract by ON DELETE CASCADE, but rows written before foreign keys were
+// enforced, or with a NULL contract_id, are left behind. A blob is orphaned when
+// no artifact of a live contract references it, so a dry run counts the blobs
+// that deleting orphaned artifacts would free.
+const (
+	orphanedArtifactsWhere = `contract_id IS NULL OR NOT EXISTS (SELECT 1 FROM contracts c WHERE c.id = artifacts.contract_id)`
+	orphanedBlobsWhere     = `NOT EXISTS (SELECT 1 FROM artifacts a JOIN contracts c ON c.id = a.contract_id WHERE a.blob_store_ref = blobs.hash)`
+)
+
+// collectGarbage counts and, unless dryRun, deletes orphaned artifacts and then
+// the blobs no remaining artifact references, all within tx.
+func collectGarbage(ctx context.Context, tx *sql.Tx, dryRun bool) (*GCResult, error) {
+	result := &GCResult{}
+	for _, step := range []struct {
+		table, where string
+		count        *int64
+	}{
+		{"artifacts", orphanedArtifactsWhere, &result.OrphanedArtifacts},
+		{"blobs", orphanedBlobsWhere, &result.OrphanedBlobs},
+	} {
+		var bytes int64
+		query := `SELECT COUNT(*), COALESCE(SUM(size_bytes), 0) FROM ` + step.table + ` WHERE ` + step.where
+		if err := tx.QueryRowContext(ctx, query).Scan(step.count, &bytes); err != nil {
+			return nil, fmt.Errorf("counting orphaned %s: %w", step.table, err)
+		}
+		result.ReclaimedBytes += bytes
+
+		if dryRun || *step.count == 0 {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+step.table+` WHERE `+step.where); err != nil {
+			return nil, fmt.Errorf("deleting orphaned %s: %w", step.table, err)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package validation
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// abiItem is one entry of a Solidity JSON ABI
+type abiItem struct {
+	Type            string     `json:"type"`
+	Name            string     `json:"name"`
+	Inputs          []abiParam `json:"inputs"`
+	Outputs         []abiParam `json:"outputs"`
+	StateMutability string     `json:"stateMutability"`
+}
+
+// abiParam is an input, output or tuple component of an ABI entry
+type abiParam struct {
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Components []abiParam `json:"components"`
+}
+
+// ABI parameter types: an elementary type (uint256, bytes32, address) or tuple,
+// followed by any number of fixed or dynamic array suffixes
+var abiTypeRegex = regexp.MustCompile(`^[a-z][a-z0-9]*(\[[0-9]*\])*$`)
+
+// ValidateABI checks that raw is a JSON array of ABI entries: each has a known
+// type, functions, events and errors are named, every parameter has a type, and
+// tuples list their components. It returns the first problem found.
+func ValidateABI(raw json.RawMessage) error {
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) == 0 || trimmed[0] != '[' {
+		return errors.New("ABI must be a JSON array")
+	}
+	var items []abiItem
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return fmt.Errorf("ABI is not valid: %w", err)
+	}
+
+	for i, item := range items {
+		if err := validateABIItem(item); err != nil {
+			return fmt.Errorf("abi[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateABIItem(item abiItem) error {
+	switch item.Type {
+	case "", "function", "event", "error":
+		// The type defaults to function when omitted
+		if item.Name == "" {
+			return fmt.Errorf("%s has no name", cmp.Or(item.Type, "function"))
+		}
+	case "constructor", "receive", "fallback":
+		if len(item.Outputs) > 0 {
+			return fmt.Errorf("%s cannot have outputs", item.Type)
+		}
+	default:
+		return fmt.Errorf("unknown entry type %q", item.Type)
+	}
+
+	switch item.StateMutability {
+	case "", "pure", "view", "nonpayable", "payable":
+	default:
+		return fmt.Errorf("unknown stateMutability %q", item.StateMutability)
+	}
+
+	for i, p := range item.Inputs {
+		if err := validateABIParam(p); err != nil {
+			return fmt.Errorf("inputs[%d]: %w", i, err)
+		}
+	}
+	for i, p := range item.Outputs {
+		if err := validateABIParam(p); err != nil {
+			return fmt.Errorf("outputs[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateABIParam(p abiParam) error {
+	if !abiTypeRegex.MatchString(p.Type) {
+		if p.Type == "" {
+			return errors.New("has no type")
+		}
+		return fmt.Errorf("invalid type %q", p.Type)
+	}
+	if strings.HasPrefix(p.Type, "tuple") {
+		if len(p.Components) == 0 {
+			return fmt.Errorf("%s has no components", p.Type)
+		}
+		for i, c := range p.Components {
+			if err := validateABIParam(c); err != nil {
+				return fmt.Errorf("components[%d]: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
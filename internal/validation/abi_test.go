@@ -0,0 +1,53 @@
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidateABI(t *testing.T) {
+	tests := []struct {
+		name    string
+		abi     string
+		wantErr string
+	}{
+		{"empty array", `[]`, ""},
+		{"function", `[{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"type":"bool"}],"stateMutability":"nonpayable"}]`, ""},
+		{"type defaults to function", `[{"name":"balanceOf","inputs":[{"type":"address"}],"outputs":[{"type":"uint256"}]}]`, ""},
+		{"event", `[{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}],"anonymous":false}]`, ""},
+		{"error", `[{"type":"error","name":"Unauthorized","inputs":[]}]`, ""},
+		{"constructor", `[{"type":"constructor","inputs":[{"name":"owner","type":"address"}],"stateMutability":"payable"}]`, ""},
+		{"fallback and receive", `[{"type":"fallback","stateMutability":"payable"},{"type":"receive","stateMutability":"payable"}]`, ""},
+		{"tuples and arrays", `[{"type":"function","name":"batch","inputs":[{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"data","type":"bytes"},{"name":"ids","type":"uint256[2][]"}]}]}]`, ""},
+
+		{"object", `{"abi":[]}`, "must be a JSON array"},
+		{"null", `null`, "must be a JSON array"},
+		{"not JSON", `[{"type":`, "not valid"},
+		{"entries not objects", `["transfer(address,uint256)"]`, "not valid"},
+		{"unknown type", `[{"type":"modifier","name":"onlyOwner"}]`, `abi[0]: unknown entry type "modifier"`},
+		{"unnamed function", `[{"type":"error","name":"E"},{"type":"function","inputs":[]}]`, "abi[1]: function has no name"},
+		{"unnamed event", `[{"type":"event","inputs":[]}]`, "abi[0]: event has no name"},
+		{"constructor outputs", `[{"type":"constructor","outputs":[{"type":"uint256"}]}]`, "constructor cannot have outputs"},
+		{"bad mutability", `[{"type":"function","name":"f","stateMutability":"constant"}]`, `unknown stateMutability "constant"`},
+		{"param without type", `[{"type":"function","name":"f","inputs":[{"name":"x"}]}]`, "abi[0]: inputs[0]: has no type"},
+		{"bad param type", `[{"type":"function","name":"f","outputs":[{"type":"uint256[x]"}]}]`, `outputs[0]: invalid type "uint256[x]"`},
+		{"tuple without components", `[{"type":"function","name":"f","inputs":[{"type":"tuple"}]}]`, "inputs[0]: tuple has no components"},
+		{"nested component", `[{"type":"function","name":"f","inputs":[{"type":"tuple","components":[{"name":"a"}]}]}]`, "inputs[0]: components[0]: has no type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateABI(json.RawMessage(tt.abi))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateABI() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateABI() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
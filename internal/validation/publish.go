@@ -0,0 +1,44 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError is a problem with one field of a request body. Field is the JSON
+// path of the field, e.g. "artifacts[2].name".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// FieldErrors is every problem found in a request body.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, f := range e {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidatePublishRequest checks the shape of a publish request body: a chain and
+// at least one artifact, each with a name. It reports every problem rather than
+// the first, or nil when there are none. Contents (ABIs, labels, signatures) are
+// checked later by the packages domain.
+func ValidatePublishRequest(chain string, artifactNames []string) FieldErrors {
+	var errs FieldErrors
+	if strings.TrimSpace(chain) == "" {
+		errs = append(errs, FieldError{Field: "chain", Message: "is required"})
+	}
+	if len(artifactNames) == 0 {
+		errs = append(errs, FieldError{Field: "artifacts", Message: "must contain at least one artifact"})
+	}
+	for i, name := range artifactNames {
+		if strings.TrimSpace(name) == "" {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("artifacts[%d].name", i), Message: "is required"})
+		}
+	}
+	return errs
+}
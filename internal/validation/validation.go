@@ -2,11 +2,15 @@
 package validation
 
 import (
+	"encoding/hex"
 	"errors"
 	"regexp"
 	"strings"
 
+	"golang.org/x/crypto/sha3"
 	"golang.org/x/mod/semver"
+
+	"github.com/pendergraft/contrafactory/internal/base58"
 )
 
 // Package name validation
@@ -31,6 +35,112 @@ func ValidatePackageName(name string) error {
 	return nil
 }
 
+// Contract label validation
+// Labels: lowercase alphanumeric with hyphens, 1-32 chars (e.g. erc20, upgradeable)
+var labelRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,30}[a-z0-9])?$`)
+
+// NormalizeLabel trims and lowercases a contract label
+func NormalizeLabel(label string) string {
+	return strings.ToLower(strings.TrimSpace(label))
+}
+
+// ValidateLabel validates a normalized contract label
+func ValidateLabel(label string) error {
+	if label == "" {
+		return errors.New("label must not be empty")
+	}
+	if len(label) > 32 {
+		return errors.New("label too long (max 32 chars)")
+	}
+	if !labelRegex.MatchString(label) {
+		return errors.New("invalid label: must be lowercase alphanumeric with hyphens")
+	}
+	return nil
+}
+
+// Package tags share the label grammar (e.g. defi, erc20, governance)
+
+// NormalizeTag trims and lowercases a package tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// ValidateTag validates a normalized package tag
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return errors.New("tag must not be empty")
+	}
+	if len(tag) > 32 {
+		return errors.New("tag too long (max 32 chars)")
+	}
+	if !labelRegex.MatchString(tag) {
+		return errors.New("invalid tag: must be lowercase alphanumeric with hyphens")
+	}
+	return nil
+}
+
+// Package alias validation
+// Aliases: lowercase alphanumeric with hyphens, 1-32 chars, starting with a letter
+// so they can't be mistaken for versions (e.g. stable, next)
+var aliasRegex = regexp.MustCompile(`^[a-z]([a-z0-9-]{0,30}[a-z0-9])?$`)
+
+// ValidateAlias validates the name of a package version alias. "latest" and
+// names that parse as version ranges (x, v1) are reserved.
+func ValidateAlias(alias string) error {
+	if alias == "" {
+		return errors.New("alias must not be empty")
+	}
+	if len(alias) > 32 {
+		return errors.New("alias too long (max 32 chars)")
+	}
+	if !aliasRegex.MatchString(alias) {
+		return errors.New("invalid alias: must be lowercase alphanumeric with hyphens, starting with a letter")
+	}
+	if alias == "latest" || IsVersionRange(alias) {
+		return errors.New("alias " + alias + " is reserved")
+	}
+	return nil
+}
+
+// Artifact type validation
+// Types: lowercase alphanumeric with hyphens, 1-64 chars, starting with a letter (e.g. devdoc, method-identifiers)
+var artifactTypeRegex = regexp.MustCompile(`^[a-z]([a-z0-9-]{0,62}[a-z0-9])?$`)
+
+// ValidateArtifactType validates the name of an extra artifact type
+func ValidateArtifactType(artifactType string) error {
+	if artifactType == "" {
+		return errors.New("artifact type must not be empty")
+	}
+	if len(artifactType) > 64 {
+		return errors.New("artifact type too long (max 64 chars)")
+	}
+	if !artifactTypeRegex.MatchString(artifactType) {
+		return errors.New("invalid artifact type: must be lowercase alphanumeric with hyphens, starting with a letter")
+	}
+	return nil
+}
+
+// ValidateSourcePath validates the path of a source file bundled with a contract. It
+// must be relative and slash-separated without any ".." segment, so that extracting
+// it under a directory can never write outside that directory.
+func ValidateSourcePath(p string) error {
+	if p == "" {
+		return errors.New("source path must not be empty")
+	}
+	if strings.Contains(p, `\`) {
+		return errors.New("source path must use forward slashes")
+	}
+	if strings.HasPrefix(p, "/") || (len(p) >= 2 && p[1] == ':') {
+		return errors.New("source path must be relative")
+	}
+	for _, segment := range strings.Split(p, "/") {
+		if segment == ".." {
+			return errors.New("source path must not contain ..")
+		}
+	}
+	return nil
+}
+
 // ValidateVersion validates a semantic version string
 func ValidateVersion(v string) error {
 	// Normalize: strip leading 'v' if present, then add it back for semver library
@@ -107,7 +217,9 @@ func ResolveLatest(versions []string, includePrerelease bool) string {
 	return latest
 }
 
-// ValidateAddress validates an Ethereum address
+// ValidateAddress validates an Ethereum address.
+// Mixed-case addresses must carry a valid EIP-55 checksum; all-lowercase and
+// all-uppercase addresses are accepted as unchecksummed.
 func ValidateAddress(addr string) error {
 	if len(addr) != 42 {
 		return errors.New("invalid address length: must be 42 characters (0x + 40 hex)")
@@ -124,6 +236,65 @@ func ValidateAddress(addr string) error {
 			return errors.New("invalid address: contains non-hex characters")
 		}
 	}
+	digits := addr[2:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && addr != ChecksumAddress(addr) {
+		return errors.New("invalid address: EIP-55 checksum mismatch")
+	}
+	return nil
+}
+
+// NormalizeAddress returns the canonical form of an Ethereum address used for
+// storage and lookup (lowercase). The address must already be valid.
+func NormalizeAddress(addr string) string {
+	return strings.ToLower(addr)
+}
+
+// ChecksumAddress returns the EIP-55 mixed-case checksum encoding of a
+// 0x-prefixed hex address. Each hex letter is uppercased when the matching
+// nibble of keccak256(lowercase hex) is >= 8.
+func ChecksumAddress(addr string) string {
+	lower := strings.ToLower(strings.TrimPrefix(addr, "0x"))
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(lower))
+	hash := hex.EncodeToString(h.Sum(nil))
+
+	result := []byte(lower)
+	for i, c := range result {
+		if c >= 'a' && c <= 'f' && hash[i] >= '8' {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(result)
+}
+
+// solanaClusters are the cluster names accepted in place of an EVM chain ID
+var solanaClusters = map[string]bool{
+	"mainnet-beta": true,
+	"devnet":       true,
+	"testnet":      true,
+	"localnet":     true,
+}
+
+// ValidateSolanaCluster validates a Solana cluster name
+func ValidateSolanaCluster(cluster string) error {
+	if !solanaClusters[cluster] {
+		return errors.New("invalid cluster: must be one of mainnet-beta, devnet, testnet, localnet")
+	}
+	return nil
+}
+
+// ValidateSolanaAddress validates a base58-encoded Solana public key (program or account ID)
+func ValidateSolanaAddress(addr string) error {
+	if len(addr) < 32 || len(addr) > 44 {
+		return errors.New("invalid Solana address length: must be 32-44 base58 characters")
+	}
+	decoded, err := base58.Decode(addr)
+	if err != nil {
+		return errors.New("invalid Solana address: contains non-base58 characters")
+	}
+	if len(decoded) != 32 {
+		return errors.New("invalid Solana address: must decode to 32 bytes")
+	}
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -34,6 +35,89 @@ func TestValidatePackageName(t *testing.T) {
 	}
 }
 
+func TestValidateAlias(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"valid", "stable", false},
+		{"valid with hyphen", "release-candidate", false},
+		{"empty", "", true},
+		{"too long", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", true},
+		{"starts with digit", "1-stable", true},
+		{"version", "1.0.0", true},
+		{"contains uppercase", "Stable", true},
+		{"latest is reserved", "latest", true},
+		{"range", "x", true},
+		{"partial version", "v1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAlias(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAlias(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateLabel(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"valid simple", "erc20", false},
+		{"valid with hyphen", "access-control", false},
+		{"valid single char", "a", false},
+		{"empty", "", true},
+		{"too long", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", true},
+		{"contains uppercase", "ERC20", true},
+		{"contains space", "erc 20", true},
+		{"ends with hyphen", "erc20-", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLabel(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLabel(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTag(t *testing.T) {
+	for _, tag := range []string{"defi", "erc20", "cross-chain"} {
+		if err := ValidateTag(tag); err != nil {
+			t.Errorf("ValidateTag(%q) error = %v", tag, err)
+		}
+	}
+	for _, tag := range []string{"", "DeFi", "de fi", "-defi", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"} {
+		if err := ValidateTag(tag); err == nil {
+			t.Errorf("ValidateTag(%q) = nil, want error", tag)
+		}
+	}
+	if got := NormalizeTag("  DeFi "); got != "defi" {
+		t.Errorf("NormalizeTag() = %q, want defi", got)
+	}
+}
+
+func TestValidateSourcePath(t *testing.T) {
+	for _, p := range []string{"src/Token.sol", "lib/openzeppelin-contracts/contracts/token/ERC20/ERC20.sol", "./src/A.sol", "a..b.sol"} {
+		if err := ValidateSourcePath(p); err != nil {
+			t.Errorf("ValidateSourcePath(%q) error = %v", p, err)
+		}
+	}
+	for _, p := range []string{"", "../../../../etc/x", "src/../../x", "..", "/etc/passwd", `src\..\..\x`, "C:/Windows/x"} {
+		if err := ValidateSourcePath(p); err == nil {
+			t.Errorf("ValidateSourcePath(%q) = nil, want error", p)
+		}
+	}
+}
+
 func TestValidateVersion(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -166,6 +250,8 @@ func TestValidateAddress(t *testing.T) {
 		{"too short", "0x1234", true},
 		{"too long", "0x1234567890abcdef1234567890abcdef123456789", true},
 		{"invalid characters", "0x1234567890abcdef1234567890abcdef1234567g", true},
+		{"valid checksum", "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", false},
+		{"bad checksum", "0xF39Fd6e51aad88F6F4ce6aB8827279cffFb92266", true},
 	}
 
 	for _, tt := range tests {
@@ -177,3 +263,82 @@ func TestValidateAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestChecksumAddress(t *testing.T) {
+	// Test vectors from EIP-55
+	for _, want := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		for _, input := range []string{want, strings.ToLower(want), "0x" + strings.ToUpper(want[2:])} {
+			if got := ChecksumAddress(input); got != want {
+				t.Errorf("ChecksumAddress(%q) = %q, want %q", input, got, want)
+			}
+		}
+	}
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	a := NormalizeAddress("0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266")
+	b := NormalizeAddress("0xF39FD6E51AAD88F6F4CE6AB8827279CFFFB92266")
+	c := NormalizeAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	if a != b || a != c || a != "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266" {
+		t.Errorf("NormalizeAddress mismatch: %q, %q, %q", a, b, c)
+	}
+}
+
+func TestValidateSolanaAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"valid program id", "Fg6PaFpoGXkYsidMpWTK6W2BeZ7FEfcYkg476zPFsLnS", false},
+		{"valid system program", "11111111111111111111111111111111", false},
+		{"evm address", "0x1234567890123456789012345678901234567890", true},
+		{"invalid character", "Fg6PaFpoGXkYsidMpWTK6W2BeZ7FEfcYkg476zPFsLn0", true},
+		{"too short", "abc", true},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSolanaAddress(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSolanaAddress(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSolanaCluster(t *testing.T) {
+	for _, c := range []string{"mainnet-beta", "devnet", "testnet", "localnet"} {
+		if err := ValidateSolanaCluster(c); err != nil {
+			t.Errorf("ValidateSolanaCluster(%q) error = %v", c, err)
+		}
+	}
+	for _, c := range []string{"", "mainnet", "1"} {
+		if err := ValidateSolanaCluster(c); err == nil {
+			t.Errorf("ValidateSolanaCluster(%q) expected error", c)
+		}
+	}
+}
+
+func TestValidatePublishRequest(t *testing.T) {
+	if errs := ValidatePublishRequest("evm", []string{"Token"}); errs != nil {
+		t.Errorf("valid request: got %v", errs)
+	}
+
+	errs := ValidatePublishRequest(" ", []string{"Token", "", "Vault", " "})
+	want := "chain: is required; artifacts[1].name: is required; artifacts[3].name: is required"
+	if errs.Error() != want {
+		t.Errorf("got %q, want %q", errs.Error(), want)
+	}
+
+	errs = ValidatePublishRequest("evm", nil)
+	if len(errs) != 1 || errs[0].Field != "artifacts" {
+		t.Errorf("no artifacts: got %v", errs)
+	}
+}
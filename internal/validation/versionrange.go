@@ -0,0 +1,308 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Version ranges follow npm-style syntax:
+//
+//	^1.2.0          >=1.2.0 <2.0.0 (^0.2.0 is >=0.2.0 <0.3.0)
+//	~1.2.0          >=1.2.0 <1.3.0
+//	1.x, 1.2.*, 1   wildcards and partial versions
+//	>=1.0.0 <2.0.0  comparators, space-separated (AND)
+//	^1.0.0 || ^2.0.0  alternatives (OR)
+//
+// Prereleases only satisfy a range when one of its comparators names a prerelease of
+// the same major.minor.patch, so ^1.0.0 never resolves to 2.0.0-rc.1.
+
+var (
+	partialVersionRegex = regexp.MustCompile(`^v?\d+(\.\d+)?$`)
+	operatorSpaceRegex  = regexp.MustCompile(`(>=|<=|>|<|=|\^|~)\s+`)
+)
+
+// IsVersionRange reports whether v is a range expression rather than an exact
+// version or "latest".
+func IsVersionRange(v string) bool {
+	if v == "" || v == "latest" {
+		return false
+	}
+	if strings.ContainsAny(v, "^~<>=*| ") {
+		return true
+	}
+	if partialVersionRegex.MatchString(v) {
+		return true
+	}
+	core := strings.SplitN(NormalizeVersion(v), "-", 2)[0]
+	for _, part := range strings.Split(core, ".") {
+		if part == "x" || part == "X" {
+			return true
+		}
+	}
+	return false
+}
+
+// VersionRange is a parsed version range expression.
+type VersionRange struct {
+	expr string
+	sets [][]comparator // OR of AND-ed comparators
+}
+
+type comparator struct {
+	op      string // ">=", ">", "<", "<=", "="
+	version string // canonical "vX.Y.Z[-pre]"
+}
+
+// partialVersion is a possibly incomplete version; parts counts the numeric
+// components given before any wildcard.
+type partialVersion struct {
+	major, minor, patch int
+	prerelease          string
+	parts               int
+}
+
+func (p partialVersion) lower() string {
+	return fmt.Sprintf("v%d.%d.%d%s", p.major, p.minor, p.patch, p.prerelease)
+}
+
+// upper returns the exclusive upper bound of a partial version, or "" when unbounded.
+func (p partialVersion) upper() string {
+	switch p.parts {
+	case 1:
+		return fmt.Sprintf("v%d.0.0", p.major+1)
+	case 2:
+		return fmt.Sprintf("v%d.%d.0", p.major, p.minor+1)
+	default:
+		return ""
+	}
+}
+
+// ParseVersionRange parses a range expression.
+func ParseVersionRange(expr string) (*VersionRange, error) {
+	normalized := operatorSpaceRegex.ReplaceAllString(strings.TrimSpace(expr), "$1")
+	normalized = strings.ReplaceAll(normalized, ",", " ")
+	if normalized == "" {
+		return nil, errors.New("version range cannot be empty")
+	}
+
+	r := &VersionRange{expr: expr}
+	for _, alt := range strings.Split(normalized, "||") {
+		var set []comparator
+		fields := strings.Fields(alt)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid version range %q: empty alternative", expr)
+		}
+		for _, field := range fields {
+			cs, err := parseComparator(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version range %q: %w", expr, err)
+			}
+			set = append(set, cs...)
+		}
+		r.sets = append(r.sets, set)
+	}
+	return r, nil
+}
+
+func parseComparator(token string) ([]comparator, error) {
+	var op string
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(token, candidate) {
+			op = candidate
+			token = token[len(candidate):]
+			break
+		}
+	}
+
+	if op != "" && token == "" {
+		return nil, fmt.Errorf("missing version after %q", op)
+	}
+
+	p, err := parsePartialVersion(token)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "^":
+		upper := p.upper()
+		switch {
+		case p.major != 0 || p.parts == 1:
+			upper = fmt.Sprintf("v%d.0.0", p.major+1)
+		case p.minor != 0 || p.parts == 2:
+			upper = fmt.Sprintf("v%d.%d.0", p.major, p.minor+1)
+		case p.parts == 3:
+			upper = fmt.Sprintf("v%d.%d.%d", p.major, p.minor, p.patch+1)
+		}
+		return bounded(p, upper), nil
+	case "~":
+		upper := p.upper()
+		if p.parts >= 2 {
+			upper = fmt.Sprintf("v%d.%d.0", p.major, p.minor+1)
+		}
+		return bounded(p, upper), nil
+	case ">=":
+		return []comparator{{">=", p.lower()}}, nil
+	case "<":
+		return []comparator{{"<", p.lower()}}, nil
+	case ">":
+		if upper := p.upper(); upper != "" {
+			return []comparator{{">=", upper}}, nil
+		}
+		if p.parts == 0 {
+			return []comparator{{"<", "v0.0.0"}}, nil // >* matches nothing
+		}
+		return []comparator{{">", p.lower()}}, nil
+	case "<=":
+		if upper := p.upper(); upper != "" {
+			return []comparator{{"<", upper}}, nil
+		}
+		if p.parts == 0 {
+			return []comparator{{">=", "v0.0.0"}}, nil
+		}
+		return []comparator{{"<=", p.lower()}}, nil
+	default: // exact, partial or wildcard
+		if p.parts == 3 {
+			return []comparator{{"=", p.lower()}}, nil
+		}
+		return bounded(p, p.upper()), nil
+	}
+}
+
+func bounded(p partialVersion, upper string) []comparator {
+	cs := []comparator{{">=", p.lower()}}
+	if upper != "" {
+		cs = append(cs, comparator{"<", upper})
+	}
+	return cs
+}
+
+func parsePartialVersion(s string) (partialVersion, error) {
+	var p partialVersion
+	s = NormalizeVersion(s)
+	if s == "" || s == "*" || s == "x" || s == "X" {
+		return p, nil
+	}
+
+	// Drop build metadata, split off prerelease
+	s = strings.SplitN(s, "+", 2)[0]
+	core, pre, hasPre := strings.Cut(s, "-")
+
+	nums := strings.Split(core, ".")
+	if len(nums) > 3 {
+		return p, fmt.Errorf("invalid version %q", s)
+	}
+	values := []*int{&p.major, &p.minor, &p.patch}
+	wildcard := false
+	for i, n := range nums {
+		if n == "x" || n == "X" || n == "*" {
+			wildcard = true
+			continue
+		}
+		if wildcard {
+			return p, fmt.Errorf("invalid version %q: number after wildcard", s)
+		}
+		v, err := strconv.Atoi(n)
+		if err != nil || v < 0 {
+			return p, fmt.Errorf("invalid version %q", s)
+		}
+		*values[i] = v
+		p.parts++
+	}
+
+	if hasPre {
+		if p.parts != 3 {
+			return p, fmt.Errorf("invalid version %q: prerelease requires major.minor.patch", s)
+		}
+		p.prerelease = "-" + pre
+		if !semver.IsValid(p.lower()) {
+			return p, fmt.Errorf("invalid version %q", s)
+		}
+	}
+	return p, nil
+}
+
+// String returns the original expression.
+func (r *VersionRange) String() string {
+	return r.expr
+}
+
+// Match reports whether version satisfies the range.
+func (r *VersionRange) Match(version string) bool {
+	v := "v" + NormalizeVersion(version)
+	if !semver.IsValid(v) {
+		return false
+	}
+	v = semver.Canonical(v) // drops build metadata
+	for _, set := range r.sets {
+		if matchSet(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSet(set []comparator, v string) bool {
+	for _, c := range set {
+		cmp := semver.Compare(v, c.version)
+		ok := false
+		switch c.op {
+		case "=":
+			ok = cmp == 0
+		case ">=":
+			ok = cmp >= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	if semver.Prerelease(v) == "" {
+		return true
+	}
+	// Prereleases must be opted into by a comparator on the same release
+	core := strings.TrimSuffix(v, semver.Prerelease(v))
+	for _, c := range set {
+		if pre := semver.Prerelease(c.version); pre != "" && strings.TrimSuffix(c.version, pre) == core {
+			return true
+		}
+	}
+	return false
+}
+
+// IncludesPrerelease reports whether any comparator names a prerelease version.
+func (r *VersionRange) IncludesPrerelease() bool {
+	for _, set := range r.sets {
+		for _, c := range set {
+			if semver.Prerelease(c.version) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MaxSatisfying returns the highest version that satisfies the range, or "" if none does.
+func (r *VersionRange) MaxSatisfying(versions []string) string {
+	best := ""
+	for _, v := range versions {
+		if !r.Match(v) {
+			continue
+		}
+		if best == "" || CompareVersions(v, best) > 0 {
+			best = v
+		}
+	}
+	return best
+}
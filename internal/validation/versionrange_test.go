@@ -0,0 +1,118 @@
+package validation
+
+import "testing"
+
+func TestIsVersionRange(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"1.0.0", false},
+		{"v1.0.0", false},
+		{"1.0.0-beta.1", false},
+		{"latest", false},
+		{"", false},
+		{"^1.2.0", true},
+		{"~1.2.0", true},
+		{"1.x", true},
+		{"1.2.*", true},
+		{"1", true},
+		{"1.2", true},
+		{">=1.0.0 <2.0.0", true},
+		{"^1.0.0 || ^2.0.0", true},
+		{"*", true},
+	}
+
+	for _, tt := range tests {
+		if got := IsVersionRange(tt.input); got != tt.want {
+			t.Errorf("IsVersionRange(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestVersionRange_Match(t *testing.T) {
+	tests := []struct {
+		expr    string
+		version string
+		want    bool
+	}{
+		{"^1.2.0", "1.2.0", true},
+		{"^1.2.0", "1.9.3", true},
+		{"^1.2.0", "2.0.0", false},
+		{"^1.2.0", "1.1.9", false},
+		{"^0.2.3", "0.2.9", true},
+		{"^0.2.3", "0.3.0", false},
+		{"^0.0.3", "0.0.4", false},
+		{"~1.2.0", "1.2.9", true},
+		{"~1.2.0", "1.3.0", false},
+		{"~1", "1.9.0", true},
+		{"1.x", "1.5.0", true},
+		{"1.x", "2.0.0", false},
+		{"1.2.*", "1.2.7", true},
+		{"1.2", "1.3.0", false},
+		{"*", "3.1.4", true},
+		{"1.0.0", "1.0.0", true},
+		{"1.0.0", "1.0.1", false},
+		{">=1.0.0 <2.0.0", "1.5.0", true},
+		{">=1.0.0 <2.0.0", "2.0.0", false},
+		{">= 1.0.0, < 2.0.0", "1.5.0", true},
+		{">1.2", "1.2.5", false},
+		{">1.2", "1.3.0", true},
+		{"<=1.2", "1.2.5", true},
+		{"^1.0.0 || ^3.0.0", "3.1.0", true},
+		{"^1.0.0 || ^3.0.0", "2.1.0", false},
+		{"v^1.0.0", "1.0.0", false},
+		// Prereleases need an explicit opt-in on the same release
+		{"^1.0.0", "1.1.0-beta.1", false},
+		{"^1.0.0-beta.1", "1.0.0-beta.2", true},
+		{"^1.0.0-beta.1", "1.0.0", true},
+		{"^1.0.0-beta.1", "1.1.0-beta.1", false},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseVersionRange(tt.expr)
+		if err != nil {
+			if tt.want {
+				t.Errorf("ParseVersionRange(%q) error = %v", tt.expr, err)
+			}
+			continue
+		}
+		if got := r.Match(tt.version); got != tt.want {
+			t.Errorf("%q.Match(%q) = %v, want %v", tt.expr, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseVersionRange_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "^", "^abc", "1.x.3", "1.2-beta", "1.2.3.4", "^1.0.0 ||"} {
+		if _, err := ParseVersionRange(expr); err == nil {
+			t.Errorf("ParseVersionRange(%q) expected error", expr)
+		}
+	}
+}
+
+func TestVersionRange_MaxSatisfying(t *testing.T) {
+	versions := []string{"1.0.0", "1.2.0", "1.10.0", "2.0.0", "2.1.0-rc.1"}
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"^1.2.0", "1.10.0"},
+		{"1.x", "1.10.0"},
+		{"~1.2.0", "1.2.0"},
+		{"*", "2.0.0"},
+		{"^3.0.0", ""},
+		{">=2.1.0-rc.0", "2.1.0-rc.1"},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseVersionRange(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseVersionRange(%q) error = %v", tt.expr, err)
+		}
+		if got := r.MaxSatisfying(versions); got != tt.want {
+			t.Errorf("%q.MaxSatisfying() = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// Package blockscout submits contracts for source verification to a Blockscout
+// explorer using its smart-contract verification v2 API.
+package blockscout
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for request retries and verification polling
+const (
+	DefaultMaxRetries   = 3
+	DefaultPollInterval = 3 * time.Second
+	DefaultPollTimeout  = 2 * time.Minute
+)
+
+// Errors returned by Verify
+var (
+	ErrVerificationFailed  = errors.New("blockscout verification failed")
+	ErrVerificationTimeout = errors.New("timed out waiting for blockscout verification")
+)
+
+// Client talks to a single Blockscout instance.
+type Client struct {
+	instance     string
+	httpClient   *http.Client
+	maxRetries   int
+	retryBackoff time.Duration
+	pollInterval time.Duration
+	pollTimeout  time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets a custom HTTP client
+func WithHTTPClient(c *http.Client) Option {
+	return func(client *Client) {
+		client.httpClient = c
+	}
+}
+
+// WithRetries sets how often a request is retried after a network error, HTTP 429 or
+// 5xx response, and the initial backoff (doubled on each attempt)
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(client *Client) {
+		client.maxRetries = maxRetries
+		client.retryBackoff = backoff
+	}
+}
+
+// WithPolling sets how often and how long Verify polls for the verification result
+func WithPolling(interval, timeout time.Duration) Option {
+	return func(client *Client) {
+		client.pollInterval = interval
+		client.pollTimeout = timeout
+	}
+}
+
+// New creates a client for the Blockscout instance at instance (e.g. "https://eth.blockscout.com").
+func New(instance string, opts ...Option) *Client {
+	c := &Client{
+		instance:     strings.TrimRight(instance, "/"),
+		httpClient:   &http.Client{Timeout: 60 * time.Second},
+		maxRetries:   DefaultMaxRetries,
+		retryBackoff: time.Second,
+		pollInterval: DefaultPollInterval,
+		pollTimeout:  DefaultPollTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Instance returns the instance URL without a trailing slash.
+func (c *Client) Instance() string {
+	return c.instance
+}
+
+// VerifyRequest describes a contract to verify from its Standard JSON Input.
+type VerifyRequest struct {
+	Address         string
+	ContractName    string // e.g. "Token"
+	CompilerVersion string // full solc version, e.g. "0.8.28+commit.7893614a"
+	StandardJSON    []byte
+	ConstructorArgs string // hex; autodetected by Blockscout when empty
+	License         string // SPDX identifier, optional
+}
+
+// VerifyResult is the verification state reported by Blockscout.
+type VerifyResult struct {
+	Verified        bool
+	FullyVerified   bool
+	AlreadyVerified bool
+}
+
+// Verify submits the Standard JSON Input and waits until Blockscout reports the
+// contract as verified, the poll timeout passes or ctx is done.
+func (c *Client) Verify(ctx context.Context, req VerifyRequest) (*VerifyResult, error) {
+	if err := c.Submit(ctx, req); err != nil {
+		if errors.Is(err, errAlreadyVerified) {
+			return &VerifyResult{Verified: true, AlreadyVerified: true}, nil
+		}
+		return nil, err
+	}
+	return c.waitVerified(ctx, req.Address)
+}
+
+// errAlreadyVerified is returned by Submit when Blockscout rejects a contract it has already verified.
+var errAlreadyVerified = errors.New("contract is already verified")
+
+// Submit starts verification via /api/v2/smart-contracts/{address}/verification/via/standard-input.
+func (c *Client) Submit(ctx context.Context, req VerifyRequest) error {
+	if len(req.StandardJSON) == 0 {
+		return errors.New("standard JSON input is required")
+	}
+	if req.CompilerVersion == "" {
+		return errors.New("compiler version is required")
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	fields := map[string]string{
+		"compiler_version":            compilerVersion(req.CompilerVersion),
+		"contract_name":               req.ContractName,
+		"autodetect_constructor_args": strconv.FormatBool(req.ConstructorArgs == ""),
+	}
+	if req.ConstructorArgs != "" {
+		fields["constructor_args"] = strings.TrimPrefix(req.ConstructorArgs, "0x")
+	}
+	if license := licenseType(req.License); license != "" {
+		fields["license_type"] = license
+	}
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			return err
+		}
+	}
+	file, err := form.CreateFormFile("files[0]", "standard-input.json")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(req.StandardJSON); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("/api/v2/smart-contracts/%s/verification/via/standard-input", url.PathEscape(req.Address))
+	status, respBody, err := c.do(ctx, http.MethodPost, path, body.Bytes(), form.FormDataContentType())
+	if err != nil {
+		return err
+	}
+	if status >= 300 {
+		msg := errorMessage(respBody)
+		if strings.Contains(strings.ToLower(msg), "already verified") {
+			return errAlreadyVerified
+		}
+		return fmt.Errorf("%w: HTTP %d: %s", ErrVerificationFailed, status, msg)
+	}
+	return nil
+}
+
+// Status returns the verification state of the contract at address.
+func (c *Client) Status(ctx context.Context, address string) (*VerifyResult, error) {
+	status, body, err := c.do(ctx, http.MethodGet, "/api/v2/smart-contracts/"+url.PathEscape(address), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusNotFound {
+		return &VerifyResult{}, nil
+	}
+	if status >= 300 {
+		return nil, fmt.Errorf("blockscout returned HTTP %d: %s", status, errorMessage(body))
+	}
+
+	var contract struct {
+		IsVerified      bool `json:"is_verified"`
+		IsFullyVerified bool `json:"is_fully_verified"`
+	}
+	if err := json.Unmarshal(body, &contract); err != nil {
+		return nil, fmt.Errorf("parsing blockscout response: %w", err)
+	}
+	return &VerifyResult{Verified: contract.IsVerified, FullyVerified: contract.IsFullyVerified}, nil
+}
+
+func (c *Client) waitVerified(ctx context.Context, address string) (*VerifyResult, error) {
+	deadline := time.Now().Add(c.pollTimeout)
+	for {
+		result, err := c.Status(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		if result.Verified {
+			return result, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrVerificationTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(c.pollInterval):
+		}
+	}
+}
+
+// do sends a request, retrying network errors, HTTP 429 and 5xx responses with
+// exponential backoff (or the server's Retry-After). It returns the final status and body.
+func (c *Client) do(ctx context.Context, method, path string, body []byte, contentType string) (int, []byte, error) {
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		status, respBody, retryAfter, err := c.doOnce(ctx, method, path, body, contentType)
+		retryable := err != nil || status == http.StatusTooManyRequests || status >= 500
+		if !retryable || attempt >= c.maxRetries || ctx.Err() != nil {
+			if err != nil {
+				return 0, nil, fmt.Errorf("calling blockscout: %w", err)
+			}
+			return status, respBody, nil
+		}
+
+		wait := backoff
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return 0, nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *Client) doOnce(ctx context.Context, method, path string, body []byte, contentType string) (status int, respBody []byte, retryAfter time.Duration, err error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.instance+path, reader)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		retryAfter = time.Duration(secs) * time.Second
+	}
+	return resp.StatusCode, respBody, retryAfter, nil
+}
+
+// errorMessage extracts Blockscout's "message" field, falling back to the raw body.
+func errorMessage(body []byte) string {
+	var resp struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(body, &resp) == nil && resp.Message != "" {
+		return resp.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// compilerVersion returns the version in Blockscout's "v0.8.28+commit.7893614a" form.
+func compilerVersion(v string) string {
+	if strings.HasPrefix(v, "v") {
+		return v
+	}
+	return "v" + v
+}
+
+// licenseTypes maps SPDX identifiers to Blockscout's license_type values.
+var licenseTypes = map[string]string{
+	"UNLICENSED":   "none",
+	"Unlicense":    "unlicense",
+	"MIT":          "mit",
+	"GPL-2.0":      "gnu_gpl_v2",
+	"GPL-3.0":      "gnu_gpl_v3",
+	"LGPL-2.1":     "gnu_lgpl_v2_1",
+	"LGPL-3.0":     "gnu_lgpl_v3",
+	"BSD-2-Clause": "bsd_2_clause",
+	"BSD-3-Clause": "bsd_3_clause",
+	"MPL-2.0":      "mpl_2_0",
+	"OSL-3.0":      "osl_3_0",
+	"Apache-2.0":   "apache_2_0",
+	"AGPL-3.0":     "gnu_agpl_v3",
+	"BUSL-1.1":     "bsl_1_1",
+}
+
+// licenseType maps an SPDX identifier (ignoring -only/-or-later suffixes) to a
+// Blockscout license_type, or "" when there is no equivalent.
+func licenseType(spdx string) string {
+	spdx = strings.TrimSuffix(strings.TrimSuffix(spdx, "-only"), "-or-later")
+	return licenseTypes[spdx]
+}
@@ -0,0 +1,138 @@
+package blockscout
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAddress = "0x1234567890abcdef1234567890abcdef12345678"
+
+func newTestClient(url string) *Client {
+	return New(url+"/", WithRetries(2, time.Millisecond), WithPolling(time.Millisecond, time.Second))
+}
+
+func TestClient_Verify(t *testing.T) {
+	var submits, statusCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/smart-contracts/" + testAddress + "/verification/via/standard-input":
+			// First attempt fails with a transient error and is retried
+			if submits.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			require.Equal(t, http.MethodPost, r.Method)
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			assert.Equal(t, "v0.8.28+commit.7893614a", r.FormValue("compiler_version"))
+			assert.Equal(t, "Token", r.FormValue("contract_name"))
+			assert.Equal(t, "false", r.FormValue("autodetect_constructor_args"))
+			assert.Equal(t, "0001", r.FormValue("constructor_args"))
+			assert.Equal(t, "mit", r.FormValue("license_type"))
+
+			f, _, err := r.FormFile("files[0]")
+			require.NoError(t, err)
+			data, _ := io.ReadAll(f)
+			assert.JSONEq(t, `{"language":"Solidity"}`, string(data))
+
+			w.Write([]byte(`{"message":"Smart-contract verification started"}`))
+		case "/api/v2/smart-contracts/" + testAddress:
+			// Verified on the second poll
+			if statusCalls.Add(1) == 1 {
+				w.Write([]byte(`{"is_verified":false}`))
+				return
+			}
+			w.Write([]byte(`{"is_verified":true,"is_fully_verified":true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+	assert.Equal(t, srv.URL, c.Instance())
+
+	result, err := c.Verify(context.Background(), VerifyRequest{
+		Address:         testAddress,
+		ContractName:    "Token",
+		CompilerVersion: "0.8.28+commit.7893614a",
+		StandardJSON:    []byte(`{"language":"Solidity"}`),
+		ConstructorArgs: "0x0001",
+		License:         "MIT",
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Verified)
+	assert.True(t, result.FullyVerified)
+	assert.Equal(t, int32(2), submits.Load())
+	assert.Equal(t, int32(2), statusCalls.Load())
+}
+
+func TestClient_Verify_Errors(t *testing.T) {
+	req := VerifyRequest{Address: testAddress, ContractName: "Token", CompilerVersion: "0.8.28", StandardJSON: []byte(`{}`)}
+
+	t.Run("already verified", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"Already verified"}`))
+		}))
+		defer srv.Close()
+
+		result, err := newTestClient(srv.URL).Verify(context.Background(), req)
+		require.NoError(t, err)
+		assert.True(t, result.AlreadyVerified)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"message":"Invalid compiler version"}`))
+		}))
+		defer srv.Close()
+
+		_, err := newTestClient(srv.URL).Verify(context.Background(), req)
+		require.ErrorIs(t, err, ErrVerificationFailed)
+		assert.Contains(t, err.Error(), "Invalid compiler version")
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer srv.Close()
+
+		_, err := newTestClient(srv.URL).Verify(context.Background(), req)
+		require.ErrorIs(t, err, ErrVerificationFailed)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("never verified", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				w.Write([]byte(`{"message":"Smart-contract verification started"}`))
+				return
+			}
+			w.Write([]byte(`{"is_verified":false}`))
+		}))
+		defer srv.Close()
+
+		c := New(srv.URL, WithPolling(time.Millisecond, 10*time.Millisecond))
+		_, err := c.Verify(context.Background(), req)
+		assert.ErrorIs(t, err, ErrVerificationTimeout)
+	})
+}
+
+func TestLicenseType(t *testing.T) {
+	assert.Equal(t, "mit", licenseType("MIT"))
+	assert.Equal(t, "gnu_gpl_v3", licenseType("GPL-3.0-or-later"))
+	assert.Equal(t, "none", licenseType("UNLICENSED"))
+	assert.Equal(t, "", licenseType("WTFPL"))
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pendergraft/contrafactory/internal/storage"
+)
+
+// resultEntrySize approximates the size of a cached verification result.
+const resultEntrySize = 512
+
+// resultCache is an in-memory LRU cache of on-chain verification results with a TTL.
+type resultCache struct {
+	ttl        time.Duration
+	maxEntries int // 0 is unbounded
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type resultEntry struct {
+	key       string
+	result    VerifyResult
+	expiresAt time.Time
+}
+
+func newResultCache(ttl time.Duration, maxBytes int64) *resultCache {
+	maxEntries := 0
+	if maxBytes > 0 {
+		maxEntries = max(1, int(maxBytes/resultEntrySize))
+	}
+	return &resultCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// resultCacheKey builds "<chain>/<address>/<contract>/<rpc>/<code hash>". The code
+// hash changes whenever the stored artifact does, so stale results are never hit.
+// The RPC endpoint is caller-supplied, so results are only shared between callers
+// that asked the same node: otherwise a node returning the stored bytecode for any
+// address could plant a "verified" result served to everyone else.
+func resultCacheKey(req VerifyRequest, contractName string, code []byte) string {
+	chain := req.Cluster
+	if chain == "" {
+		chain = strconv.Itoa(req.ChainID)
+	}
+	return strings.Join([]string{chain, req.Address, contractName, req.RPCEndpoint, storage.HashContent(code)}, "/")
+}
+
+// get returns a copy of the cached result for key, marked as cached.
+func (c *resultCache) get(key string) (*VerifyResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*resultEntry)
+	if c.ttl > 0 && time.Now().After(entry.expiresAt) {
+		c.removeElement(el)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+
+	result := entry.result
+	result.Cached = true
+	return &result, true
+}
+
+// put stores a copy of result under key.
+func (c *resultCache) put(key string, result *VerifyResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.removeElement(el)
+	}
+	entry := &resultEntry{key: key, result: *result, expiresAt: time.Now().Add(c.ttl)}
+	c.entries[key] = c.lru.PushFront(entry)
+
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+func (c *resultCache) removeElement(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*resultEntry).key)
+}
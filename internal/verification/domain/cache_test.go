@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pendergraft/contrafactory/internal/chains"
+	"github.com/pendergraft/contrafactory/internal/storage"
+)
+
+func TestVerify_ResultCache(t *testing.T) {
+	bytecode := []byte("0x608060405234801561001057600080fd")
+
+	store := newMockStore()
+	store.packages["test-pkg@1.0.0"] = &storage.Package{ID: "pkg-123", Name: "test-pkg", Chain: "evm"}
+	store.contracts["pkg-123/MyContract"] = &storage.Contract{ID: "contract-456", PackageID: "pkg-123", Name: "MyContract"}
+	store.artifacts["contract-456/deployed-bytecode"] = bytecode
+
+	mockEVM := &mockChain{
+		name:             "evm",
+		deployedBytecode: bytecode,
+		verifyResult:     &chains.VerifyResult{Match: true, MatchType: "full", Message: "Bytecode matches exactly"},
+	}
+	registry := chains.NewRegistry()
+	registry.Register(mockEVM)
+	svc := NewService(store, store, registry, WithResultCache(time.Hour, 0))
+
+	req := VerifyRequest{
+		Package:     "test-pkg",
+		Version:     "1.0.0",
+		Contract:    "MyContract",
+		ChainID:     1,
+		Address:     "0x1234567890123456789012345678901234567890",
+		RPCEndpoint: "https://eth-mainnet.example.com",
+	}
+	ctx := context.Background()
+
+	first, err := svc.Verify(ctx, req)
+	require.NoError(t, err)
+	assert.False(t, first.Cached)
+	assert.False(t, first.CheckedAt.IsZero())
+
+	second, err := svc.Verify(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, second.Cached)
+	assert.Equal(t, "full", second.MatchType)
+	assert.Equal(t, first.CheckedAt, second.CheckedAt)
+	assert.Equal(t, 1, mockEVM.bytecodeCalls, "a cached result skips the RPC call")
+
+	// Neither another chain nor another RPC endpoint for the same chain shares the entry
+	req.ChainID = 10
+	_, err = svc.Verify(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, mockEVM.bytecodeCalls)
+
+	// A changed artifact hashes differently and is compared again
+	store.artifacts["contract-456/deployed-bytecode"] = []byte("0x6001")
+	result, err := svc.Verify(ctx, req)
+	require.NoError(t, err)
+	assert.False(t, result.Cached)
+	assert.Equal(t, 3, mockEVM.bytecodeCalls)
+
+	// Results where nothing could be compared are not cached
+	mockEVM.deployedBytecodeErr = errors.New("RPC connection failed")
+	req.ChainID = 137
+	for range 2 {
+		result, err := svc.Verify(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, result.Cached)
+	}
+	assert.Equal(t, 5, mockEVM.bytecodeCalls)
+}
+
+func TestVerify_ResultCacheKeyedByRPCEndpoint(t *testing.T) {
+	bytecode := []byte("0x608060405234801561001057600080fd")
+
+	store := newMockStore()
+	store.packages["test-pkg@1.0.0"] = &storage.Package{ID: "pkg-123", Name: "test-pkg", Chain: "evm"}
+	store.contracts["pkg-123/MyContract"] = &storage.Contract{ID: "contract-456", PackageID: "pkg-123", Name: "MyContract"}
+	store.artifacts["contract-456/deployed-bytecode"] = bytecode
+
+	// The caller's node answers with the stored bytecode, whatever is really on chain
+	mockEVM := &mockChain{
+		name:             "evm",
+		deployedBytecode: bytecode,
+		verifyResult:     &chains.VerifyResult{Match: true, MatchType: "full", Message: "Bytecode matches exactly"},
+	}
+	registry := chains.NewRegistry()
+	registry.Register(mockEVM)
+	svc := NewService(store, store, registry, WithResultCache(time.Hour, 0))
+
+	req := VerifyRequest{
+		Package:     "test-pkg",
+		Version:     "1.0.0",
+		Contract:    "MyContract",
+		ChainID:     1,
+		Address:     "0x1234567890123456789012345678901234567890",
+		RPCEndpoint: "https://attacker.example.com",
+	}
+	ctx := context.Background()
+
+	planted, err := svc.Verify(ctx, req)
+	require.NoError(t, err)
+	require.True(t, planted.Verified)
+
+	// An honest node disagrees; its caller must get its own comparison
+	mockEVM.deployedBytecode = []byte("0x6001")
+	mockEVM.verifyResult = &chains.VerifyResult{Match: false, Message: "Bytecode mismatch"}
+	req.RPCEndpoint = "https://eth-mainnet.example.com"
+	result, err := svc.Verify(ctx, req)
+	require.NoError(t, err)
+	assert.False(t, result.Cached)
+	assert.False(t, result.Verified)
+	assert.Equal(t, 2, mockEVM.bytecodeCalls)
+
+	// Each endpoint is still served from its own entry
+	again, err := svc.Verify(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, again.Cached)
+	assert.False(t, again.Verified)
+	assert.Equal(t, 2, mockEVM.bytecodeCalls)
+}
+
+func TestResultCache_ExpiryAndEviction(t *testing.T) {
+	result := &VerifyResult{Verified: true, MatchType: "full"}
+
+	expiring := newResultCache(time.Millisecond, 0)
+	expiring.put("a", result)
+	time.Sleep(5 * time.Millisecond)
+	_, ok := expiring.get("a")
+	assert.False(t, ok, "expired entries are not served")
+
+	small := newResultCache(time.Hour, 2*resultEntrySize)
+	small.put("a", result)
+	small.put("b", result)
+	_, ok = small.get("a") // a is now more recently used than b
+	require.True(t, ok)
+	small.put("c", result)
+
+	_, ok = small.get("b")
+	assert.False(t, ok, "least recently used entry is evicted")
+	for _, key := range []string{"a", "c"} {
+		_, ok := small.get(key)
+		assert.True(t, ok, key)
+	}
+
+	// Callers can't mutate the cached copy
+	got, _ := small.get("a")
+	got.Verified = false
+	again, _ := small.get("a")
+	assert.True(t, again.Verified)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/pendergraft/contrafactory/internal/chains"
 	"github.com/pendergraft/contrafactory/internal/storage"
@@ -30,31 +31,76 @@ type ContractStore interface {
 	GetArtifact(ctx context.Context, contractID, artifactType string) ([]byte, error)
 }
 
+// DefaultRPCTimeout bounds the on-chain calls of a single verification.
+const DefaultRPCTimeout = 15 * time.Second
+
 type service struct {
-	packages  PackageStore
-	contracts ContractStore
-	registry  *chains.Registry
+	packages   PackageStore
+	contracts  ContractStore
+	registry   *chains.Registry
+	rpcTimeout time.Duration
+	cache      *resultCache // nil unless WithResultCache
+}
+
+// Option configures the verification service.
+type Option func(*service)
+
+// WithRPCTimeout bounds the time spent on RPC calls for one verification, so a
+// hung endpoint can't hold the request until the server's write timeout. Zero or
+// negative keeps DefaultRPCTimeout.
+func WithRPCTimeout(d time.Duration) Option {
+	return func(s *service) {
+		if d > 0 {
+			s.rpcTimeout = d
+		}
+	}
+}
+
+// WithResultCache caches on-chain verification results for ttl, keyed by chain,
+// address, contract and a hash of the stored code, so repeated verifications of
+// an unchanged deployment skip the RPC calls. Republished code hashes differently
+// and misses. maxBytes bounds the cache's approximate size; zero is unbounded.
+func WithResultCache(ttl time.Duration, maxBytes int64) Option {
+	return func(s *service) {
+		s.cache = newResultCache(ttl, maxBytes)
+	}
 }
 
 // NewService creates a new verification service.
-func NewService(packages PackageStore, contracts ContractStore, registry *chains.Registry) *service {
-	return &service{
-		packages:  packages,
-		contracts: contracts,
-		registry:  registry,
+func NewService(packages PackageStore, contracts ContractStore, registry *chains.Registry, opts ...Option) *service {
+	s := &service{
+		packages:   packages,
+		contracts:  contracts,
+		registry:   registry,
+		rpcTimeout: DefaultRPCTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // Verify verifies a deployed contract matches the stored artifact.
 func (s *service) Verify(ctx context.Context, req VerifyRequest) (*VerifyResult, error) {
-	// Validate address
-	if err := validation.ValidateAddress(req.Address); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
-	}
+	if req.Cluster != "" {
+		// Solana: cluster + base58 program ID
+		if err := validation.ValidateSolanaCluster(req.Cluster); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidChainID, err)
+		}
+		if err := validation.ValidateSolanaAddress(req.Address); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+		}
+	} else {
+		// Validate address
+		if err := validation.ValidateAddress(req.Address); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+		}
+		req.Address = validation.NormalizeAddress(req.Address)
 
-	// Validate chain ID
-	if err := validation.ValidateChainID(req.ChainID); err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrInvalidChainID, err)
+		// Validate chain ID
+		if err := validation.ValidateChainID(req.ChainID); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidChainID, err)
+		}
 	}
 
 	// Get package
@@ -75,13 +121,17 @@ func (s *service) Verify(ctx context.Context, req VerifyRequest) (*VerifyResult,
 		return nil, fmt.Errorf("getting contract: %w", err)
 	}
 
-	// Get deployed bytecode from storage
-	storedBytecode, err := s.contracts.GetArtifact(ctx, contract.ID, "deployed-bytecode")
+	// Get deployed code from storage: runtime bytecode for EVM, the program binary for Solana
+	artifactType, artifactDesc := "deployed-bytecode", "deployed bytecode"
+	if pkg.Chain == "solana" {
+		artifactType, artifactDesc = "program", "program binary"
+	}
+	storedBytecode, err := s.contracts.GetArtifact(ctx, contract.ID, artifactType)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
-			return nil, fmt.Errorf("deployed bytecode not found for contract %s", req.Contract)
+			return nil, fmt.Errorf("%s not found for contract %s", artifactDesc, req.Contract)
 		}
-		return nil, fmt.Errorf("getting deployed bytecode: %w", err)
+		return nil, fmt.Errorf("getting %s: %w", artifactDesc, err)
 	}
 
 	// Get chain module
@@ -90,52 +140,163 @@ func (s *service) Verify(ctx context.Context, req VerifyRequest) (*VerifyResult,
 		return nil, ErrChainNotFound
 	}
 
-	// If RPC endpoint provided, fetch and verify on-chain bytecode
+	// If RPC endpoint provided, fetch and verify on-chain bytecode, unless the same
+	// code was recently compared at this address
 	if req.RPCEndpoint != "" {
-		onChainBytecode, err := chain.GetDeployedBytecode(ctx, req.RPCEndpoint, req.Address)
-		if err != nil {
-			return &VerifyResult{
-				Verified:  false,
-				MatchType: "none",
-				Message:   fmt.Sprintf("Failed to fetch on-chain bytecode: %v", err),
-			}, nil
+		var key string
+		if s.cache != nil {
+			key = resultCacheKey(req, contract.Name, storedBytecode)
+			if result, ok := s.cache.get(key); ok {
+				return result, nil
+			}
 		}
 
-		// Verify using chain module
-		result, err := chain.VerifyDeployment(ctx, chains.VerifyOptions{
-			RPC:          req.RPCEndpoint,
-			Address:      req.Address,
-			ExpectedCode: storedBytecode,
-		})
+		result, conclusive, err := s.verifyOnChain(ctx, chain, req, storedBytecode, contract)
 		if err != nil {
-			return nil, fmt.Errorf("verifying deployment: %w", err)
+			return nil, err
+		}
+		result.CheckedAt = time.Now().UTC()
+		if conclusive && s.cache != nil {
+			s.cache.put(key, result)
+		}
+		return result, nil
+	}
+
+	// Without RPC, just return the stored bytecode hash for manual verification
+	return &VerifyResult{
+		Verified:  false,
+		MatchType: "pending",
+		Message:   "Provide RPC endpoint for on-chain verification, or compare bytecode manually",
+		Details: &VerifyDetails{
+			ExpectedBytecodeHash: contract.PrimaryHash,
+		},
+	}, nil
+}
+
+// verifyOnChain compares the code deployed at req.Address with storedBytecode.
+// conclusive is false when nothing could be compared (the RPC call failed or timed
+// out), so the result must not be cached.
+func (s *service) verifyOnChain(ctx context.Context, chain chains.Chain, req VerifyRequest, storedBytecode []byte, contract *storage.Contract) (result *VerifyResult, conclusive bool, err error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, s.rpcTimeout)
+	defer cancel()
+
+	onChainBytecode, err := chain.GetDeployedBytecode(rpcCtx, req.RPCEndpoint, req.Address)
+	if err != nil {
+		if s.rpcTimedOut(ctx, rpcCtx) {
+			return s.rpcTimeoutResult(contract), false, nil
+		}
+		return &VerifyResult{
+			Verified:  false,
+			MatchType: "none",
+			Message:   fmt.Sprintf("Failed to fetch on-chain bytecode: %v", err),
+		}, false, nil
+	}
+
+	// Verify using chain module
+	verified, err := chain.VerifyDeployment(rpcCtx, chains.VerifyOptions{
+		RPC:          req.RPCEndpoint,
+		Address:      req.Address,
+		ExpectedCode: storedBytecode,
+	})
+	if err != nil {
+		if s.rpcTimedOut(ctx, rpcCtx) {
+			return s.rpcTimeoutResult(contract), false, nil
 		}
+		return nil, false, fmt.Errorf("verifying deployment: %w", err)
+	}
 
-		// Compare bytecodes
-		verified := string(storedBytecode) == string(onChainBytecode)
-		matchType := "none"
-		if verified {
-			matchType = "full"
-		} else if result.Match {
-			matchType = result.MatchType
-			verified = true
+	// A minimal proxy runs its implementation's code, so that's what is compared
+	if verified.MatchType == "clone" {
+		result, err := s.verifyClone(ctx, rpcCtx, chain, req.RPCEndpoint, verified, storedBytecode, contract)
+		return result, err == nil && result.MatchType == "clone", err
+	}
+
+	// Compare bytecodes
+	match := string(storedBytecode) == string(onChainBytecode)
+	matchType := "none"
+	if match {
+		matchType = "full"
+	} else if verified.Match {
+		matchType = verified.MatchType
+		match = true
+	}
+
+	return &VerifyResult{
+		Verified:  match,
+		MatchType: matchType,
+		Message:   verified.Message,
+		Details:   divergenceDetails(matchType, verified.Divergence),
+	}, true, nil
+}
+
+// verifyClone verifies an EIP-1167 minimal proxy by comparing the code of the
+// implementation it delegates to with the stored code. The match type stays "clone";
+// it is verified when the implementation matches.
+func (s *service) verifyClone(ctx, rpcCtx context.Context, chain chains.Chain, rpc string, proxy *chains.VerifyResult, expected []byte, contract *storage.Contract) (*VerifyResult, error) {
+	impl, err := chain.VerifyDeployment(rpcCtx, chains.VerifyOptions{
+		RPC:          rpc,
+		Address:      proxy.Implementation,
+		ExpectedCode: expected,
+	})
+	if err != nil {
+		if s.rpcTimedOut(ctx, rpcCtx) {
+			return s.rpcTimeoutResult(contract), nil
 		}
+		return nil, fmt.Errorf("verifying clone implementation %s: %w", proxy.Implementation, err)
+	}
 
+	details := &VerifyDetails{
+		Implementation:          proxy.Implementation,
+		ImplementationMatchType: impl.MatchType,
+	}
+	if !impl.Match {
 		return &VerifyResult{
-			Verified:  verified,
-			MatchType: matchType,
-			Message:   result.Message,
-			Details:   &VerifyDetails{},
+			Verified:  false,
+			MatchType: "clone",
+			Message:   fmt.Sprintf("%s, whose code does not match %s", proxy.Message, contract.Name),
+			Details:   details,
 		}, nil
 	}
+	return &VerifyResult{
+		Verified:  true,
+		MatchType: "clone",
+		Message:   fmt.Sprintf("%s, whose code matches %s (%s match)", proxy.Message, contract.Name, impl.MatchType),
+		Details:   details,
+	}, nil
+}
 
-	// Without RPC, just return the stored bytecode hash for manual verification
+// divergenceDetails reports where the bytecodes differ for partial and failed matches.
+func divergenceDetails(matchType string, d *chains.Divergence) *VerifyDetails {
+	details := &VerifyDetails{MetadataStripped: matchType == "partial"}
+	if d == nil || matchType == "full" {
+		return details
+	}
+	if d.Offset >= 0 {
+		details.DivergenceOffset = &d.Offset
+	}
+	if d.MetadataOffset >= 0 {
+		details.MetadataOffset = &d.MetadataOffset
+		details.MetadataLength = d.MetadataLength
+	}
+	return details
+}
+
+// rpcTimedOut reports whether the RPC calls ran out of time, as opposed to the
+// request itself being cancelled.
+func (s *service) rpcTimedOut(ctx, rpcCtx context.Context) bool {
+	return errors.Is(rpcCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+}
+
+// rpcTimeoutResult reports a verification that couldn't be completed because the
+// RPC endpoint didn't answer in time. It's pending rather than a mismatch: nothing
+// was compared.
+func (s *service) rpcTimeoutResult(contract *storage.Contract) *VerifyResult {
 	return &VerifyResult{
 		Verified:  false,
 		MatchType: "pending",
-		Message:   "Provide RPC endpoint for on-chain verification, or compare bytecode manually",
+		Message:   fmt.Sprintf("RPC timed out after %s; retry later or use another RPC endpoint", s.rpcTimeout),
 		Details: &VerifyDetails{
 			ExpectedBytecodeHash: contract.PrimaryHash,
 		},
-	}, nil
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -60,7 +61,10 @@ type mockChain struct {
 	deployedBytecode    []byte
 	deployedBytecodeErr error
 	verifyResult        *chains.VerifyResult
+	verifyResults       map[string]*chains.VerifyResult // by address, overriding verifyResult
 	verifyErr           error
+	hang                bool // block until the context is done, like an unresponsive RPC
+	bytecodeCalls       int
 }
 
 func (m *mockChain) Name() string                                     { return m.name }
@@ -69,6 +73,11 @@ func (m *mockChain) DetectBuilder(dir string) (chains.Builder, error) { return n
 func (m *mockChain) Builders() []chains.Builder                       { return nil }
 
 func (m *mockChain) GetDeployedBytecode(ctx context.Context, rpc string, address string) ([]byte, error) {
+	m.bytecodeCalls++
+	if m.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
 	if m.deployedBytecodeErr != nil {
 		return nil, m.deployedBytecodeErr
 	}
@@ -79,6 +88,9 @@ func (m *mockChain) VerifyDeployment(ctx context.Context, opts chains.VerifyOpti
 	if m.verifyErr != nil {
 		return nil, m.verifyErr
 	}
+	if r, ok := m.verifyResults[opts.Address]; ok {
+		return r, nil
+	}
 	return m.verifyResult, nil
 }
 
@@ -100,6 +112,21 @@ func TestVerify_InvalidAddress(t *testing.T) {
 	assert.True(t, errors.Is(err, ErrInvalidAddress))
 }
 
+func TestVerify_BadChecksum(t *testing.T) {
+	store := newMockStore()
+	svc := NewService(store, store, chains.NewRegistry())
+
+	_, err := svc.Verify(context.Background(), VerifyRequest{
+		Package:  "test-pkg",
+		Version:  "1.0.0",
+		Contract: "MyContract",
+		ChainID:  1,
+		Address:  "0xF39Fd6e51aad88F6F4ce6aB8827279cffFb92266", // first letter flipped
+	})
+
+	assert.True(t, errors.Is(err, ErrInvalidAddress))
+}
+
 func TestVerify_InvalidChainID(t *testing.T) {
 	store := newMockStore()
 	registry := chains.NewRegistry()
@@ -326,6 +353,11 @@ func TestVerify_WithRPC_PartialMatch(t *testing.T) {
 			Match:     true,
 			MatchType: "partial",
 			Message:   "Bytecode matches after stripping metadata",
+			Divergence: &chains.Divergence{
+				Offset:         40,
+				MetadataOffset: 30,
+				MetadataLength: 53,
+			},
 		},
 	}
 
@@ -346,6 +378,11 @@ func TestVerify_WithRPC_PartialMatch(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.True(t, result.Verified)
 	assert.Equal(t, "partial", result.MatchType)
+	require.NotNil(t, result.Details)
+	assert.True(t, result.Details.MetadataStripped)
+	assert.Equal(t, 40, *result.Details.DivergenceOffset)
+	assert.Equal(t, 30, *result.Details.MetadataOffset)
+	assert.Equal(t, 53, result.Details.MetadataLength)
 }
 
 func TestVerify_WithRPC_NoMatch(t *testing.T) {
@@ -372,6 +409,10 @@ func TestVerify_WithRPC_NoMatch(t *testing.T) {
 			Match:     false,
 			MatchType: "none",
 			Message:   "Bytecode does not match",
+			Divergence: &chains.Divergence{
+				Offset:         2,
+				MetadataOffset: -1,
+			},
 		},
 	}
 
@@ -392,6 +433,64 @@ func TestVerify_WithRPC_NoMatch(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.False(t, result.Verified)
 	assert.Equal(t, "none", result.MatchType)
+	require.NotNil(t, result.Details)
+	assert.Equal(t, 2, *result.Details.DivergenceOffset)
+	assert.Nil(t, result.Details.MetadataOffset)
+}
+
+func TestVerify_WithRPC_Clone(t *testing.T) {
+	const proxy = "0x1234567890123456789012345678901234567890"
+	const impl = "0xbebebebebebebebebebebebebebebebebebebebe"
+
+	store := newMockStore()
+	store.packages["test-pkg@1.0.0"] = &storage.Package{ID: "pkg-123", Name: "test-pkg", Chain: "evm"}
+	store.contracts["pkg-123/MyContract"] = &storage.Contract{ID: "contract-456", PackageID: "pkg-123", Name: "MyContract"}
+	store.artifacts["contract-456/deployed-bytecode"] = []byte("0x6080604052")
+
+	cloneResult := &chains.VerifyResult{
+		MatchType:      "clone",
+		Message:        "Deployed code is an EIP-1167 minimal proxy delegating to " + impl,
+		Implementation: impl,
+	}
+
+	tests := []struct {
+		name         string
+		implResult   *chains.VerifyResult
+		wantVerified bool
+		wantMessage  string
+	}{
+		{"implementation matches", &chains.VerifyResult{Match: true, MatchType: "partial"}, true, "whose code matches MyContract (partial match)"},
+		{"implementation differs", &chains.VerifyResult{MatchType: "none"}, false, "whose code does not match MyContract"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockEVM := &mockChain{
+				name:          "evm",
+				verifyResults: map[string]*chains.VerifyResult{proxy: cloneResult, impl: tt.implResult},
+			}
+			registry := chains.NewRegistry()
+			registry.Register(mockEVM)
+			svc := NewService(store, store, registry)
+
+			result, err := svc.Verify(context.Background(), VerifyRequest{
+				Package:     "test-pkg",
+				Version:     "1.0.0",
+				Contract:    "MyContract",
+				ChainID:     1,
+				Address:     proxy,
+				RPCEndpoint: "https://eth-mainnet.example.com",
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantVerified, result.Verified)
+			assert.Equal(t, "clone", result.MatchType)
+			assert.Contains(t, result.Message, tt.wantMessage)
+			require.NotNil(t, result.Details)
+			assert.Equal(t, impl, result.Details.Implementation)
+			assert.Equal(t, tt.implResult.MatchType, result.Details.ImplementationMatchType)
+		})
+	}
 }
 
 func TestVerify_WithRPC_FetchBytecodeError(t *testing.T) {
@@ -474,6 +573,47 @@ func TestVerify_WithRPC_VerificationError(t *testing.T) {
 	assert.Contains(t, err.Error(), "verifying deployment")
 }
 
+func TestVerify_WithRPC_Timeout(t *testing.T) {
+	store := newMockStore()
+	store.packages["test-pkg@1.0.0"] = &storage.Package{
+		ID:    "pkg-123",
+		Name:  "test-pkg",
+		Chain: "evm",
+	}
+	store.contracts["pkg-123/MyContract"] = &storage.Contract{
+		ID:        "contract-456",
+		PackageID: "pkg-123",
+		Name:      "MyContract",
+	}
+	store.artifacts["contract-456/deployed-bytecode"] = []byte("0x608060")
+
+	registry := chains.NewRegistry()
+	registry.Register(&mockChain{name: "evm", hang: true})
+	svc := NewService(store, store, registry, WithRPCTimeout(10*time.Millisecond))
+
+	req := VerifyRequest{
+		Package:     "test-pkg",
+		Version:     "1.0.0",
+		Contract:    "MyContract",
+		ChainID:     1,
+		Address:     "0x1234567890123456789012345678901234567890",
+		RPCEndpoint: "https://eth-mainnet.example.com",
+	}
+
+	result, err := svc.Verify(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, result.Verified)
+	assert.Equal(t, "pending", result.MatchType)
+	assert.Contains(t, result.Message, "RPC timed out after 10ms")
+
+	// A cancelled request is not reported as an RPC timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err = svc.Verify(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "none", result.MatchType)
+}
+
 func TestNewService(t *testing.T) {
 	store := newMockStore()
 	registry := chains.NewRegistry()
@@ -1,12 +1,15 @@
 // Package domain contains the business logic for contract verification.
 package domain
 
+import "time"
+
 // VerifyRequest is the request to verify a deployed contract.
 type VerifyRequest struct {
 	Package     string `json:"package"`
 	Version     string `json:"version"`
 	Contract    string `json:"contract"`
 	ChainID     int    `json:"chainId"`
+	Cluster     string `json:"cluster,omitempty"` // Solana cluster, used instead of chainId
 	Address     string `json:"address"`
 	RPCEndpoint string `json:"rpcEndpoint,omitempty"`
 }
@@ -14,9 +17,14 @@ type VerifyRequest struct {
 // VerifyResult is the result of a verification.
 type VerifyResult struct {
 	Verified  bool           `json:"verified"`
-	MatchType string         `json:"matchType"` // "full", "partial", "none"
+	MatchType string         `json:"matchType"` // "full", "partial", "none", "clone"
 	Message   string         `json:"message"`
 	Details   *VerifyDetails `json:"details,omitempty"`
+
+	// When the on-chain code was compared, and whether this result was served from
+	// the cache instead of comparing again
+	CheckedAt time.Time `json:"checkedAt,omitzero"`
+	Cached    bool      `json:"cached,omitempty"`
 }
 
 // VerifyDetails contains detailed verification information.
@@ -25,4 +33,14 @@ type VerifyDetails struct {
 	ActualBytecodeHash   string `json:"actualBytecodeHash,omitempty"`
 	MetadataStripped     bool   `json:"metadataStripped,omitempty"`
 	LibrariesLinked      bool   `json:"librariesLinked,omitempty"`
+
+	// Where the on-chain code departs from the artifact (partial and none matches)
+	DivergenceOffset *int `json:"divergenceOffset,omitempty"` // first differing byte
+	MetadataOffset   *int `json:"metadataOffset,omitempty"`   // start of the on-chain metadata
+	MetadataLength   int  `json:"metadataLength,omitempty"`   // metadata bytes stripped before comparing
+
+	// For clone matches: the implementation the EIP-1167 minimal proxy delegates to,
+	// and how its code compared with the artifact ("full", "partial" or "none")
+	Implementation          string `json:"implementation,omitempty"`
+	ImplementationMatchType string `json:"implementationMatchType,omitempty"`
 }
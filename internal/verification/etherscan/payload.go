@@ -0,0 +1,108 @@
+// Package etherscan builds source verification requests for Etherscan-compatible
+// explorers (the v2 multichain API's contract/verifysourcecode action).
+package etherscan
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// APIURL is the Etherscan v2 API endpoint; the target chain is selected by chainid.
+const APIURL = "https://api.etherscan.io/v2/api"
+
+// VerifyRequest describes a contract to verify from its Standard JSON Input.
+type VerifyRequest struct {
+	ChainID          string
+	Address          string // optional; the caller fills it in when empty
+	SourcePath       string // e.g. "src/Token.sol"
+	ContractName     string // e.g. "Token"
+	CompilerVersion  string // full solc version, e.g. "0.8.28+commit.7893614a"
+	StandardJSON     []byte
+	OptimizerEnabled bool
+	OptimizerRuns    int
+	EVMVersion       string // empty means the compiler default
+	ConstructorArgs  string // hex, optional
+	License          string // SPDX identifier, optional
+}
+
+// Form returns the verifysourcecode form fields, without the apikey.
+func (r VerifyRequest) Form() (map[string]string, error) {
+	if len(r.StandardJSON) == 0 {
+		return nil, errors.New("standard JSON input is required")
+	}
+	if r.CompilerVersion == "" {
+		return nil, errors.New("compiler version is required")
+	}
+
+	contractName := r.ContractName
+	if r.SourcePath != "" {
+		contractName = r.SourcePath + ":" + r.ContractName
+	}
+	evmVersion := r.EVMVersion
+	if evmVersion == "" {
+		evmVersion = "default"
+	}
+	optimizationUsed := "0"
+	if r.OptimizerEnabled {
+		optimizationUsed = "1"
+	}
+
+	form := map[string]string{
+		"module":           "contract",
+		"action":           "verifysourcecode",
+		"chainid":          r.ChainID,
+		"codeformat":       "solidity-standard-json-input",
+		"sourceCode":       string(r.StandardJSON),
+		"contractname":     contractName,
+		"compilerversion":  compilerVersion(r.CompilerVersion),
+		"optimizationUsed": optimizationUsed,
+		"runs":             strconv.Itoa(r.OptimizerRuns),
+		"evmversion":       evmVersion,
+		"licenseType":      strconv.Itoa(licenseType(r.License)),
+	}
+	if r.Address != "" {
+		form["contractaddress"] = r.Address
+	}
+	if r.ConstructorArgs != "" {
+		// Etherscan's field name is misspelled
+		form["constructorArguements"] = strings.TrimPrefix(r.ConstructorArgs, "0x")
+	}
+	return form, nil
+}
+
+// compilerVersion returns the version in Etherscan's "v0.8.28+commit.7893614a" form.
+func compilerVersion(v string) string {
+	if strings.HasPrefix(v, "v") {
+		return v
+	}
+	return "v" + v
+}
+
+// licenseTypes maps SPDX identifiers to Etherscan's numeric licenseType values.
+var licenseTypes = map[string]int{
+	"UNLICENSED":   1,
+	"Unlicense":    2,
+	"MIT":          3,
+	"GPL-2.0":      4,
+	"GPL-3.0":      5,
+	"LGPL-2.1":     6,
+	"LGPL-3.0":     7,
+	"BSD-2-Clause": 8,
+	"BSD-3-Clause": 9,
+	"MPL-2.0":      10,
+	"OSL-3.0":      11,
+	"Apache-2.0":   12,
+	"AGPL-3.0":     13,
+	"BUSL-1.1":     14,
+}
+
+// licenseType maps an SPDX identifier (ignoring -only/-or-later suffixes) to an
+// Etherscan licenseType, or 1 ("No License") when there is no equivalent.
+func licenseType(spdx string) int {
+	spdx = strings.TrimSuffix(strings.TrimSuffix(spdx, "-only"), "-or-later")
+	if t, ok := licenseTypes[spdx]; ok {
+		return t
+	}
+	return 1
+}
@@ -0,0 +1,35 @@
+package etherscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyRequest_Form(t *testing.T) {
+	form, err := VerifyRequest{
+		ChainID:         "8453",
+		ContractName:    "Token",
+		CompilerVersion: "v0.8.28+commit.7893614a",
+		StandardJSON:    []byte(`{}`),
+	}.Form()
+	require.NoError(t, err)
+	assert.Equal(t, "Token", form["contractname"])
+	assert.Equal(t, "v0.8.28+commit.7893614a", form["compilerversion"])
+	assert.Equal(t, "0", form["optimizationUsed"])
+	assert.Equal(t, "default", form["evmversion"])
+	assert.Equal(t, "1", form["licenseType"])
+	assert.NotContains(t, form, "contractaddress")
+	assert.NotContains(t, form, "constructorArguements")
+
+	_, err = VerifyRequest{ContractName: "Token", StandardJSON: []byte(`{}`)}.Form()
+	assert.Error(t, err)
+}
+
+func TestLicenseType(t *testing.T) {
+	assert.Equal(t, 3, licenseType("MIT"))
+	assert.Equal(t, 5, licenseType("GPL-3.0-or-later"))
+	assert.Equal(t, 14, licenseType("BUSL-1.1"))
+	assert.Equal(t, 1, licenseType("WTFPL"))
+}
@@ -8,9 +8,12 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/pendergraft/contrafactory/internal/middleware/requestid"
+	"github.com/pendergraft/contrafactory/internal/server/errcodes"
 	"github.com/pendergraft/contrafactory/internal/verification/domain"
 )
 
@@ -37,13 +40,13 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 func (h *Handler) handleVerify(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Failed to read request body")
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Failed to read request body")
 		return
 	}
 
 	var req VerifyRequest
 	if err := json.Unmarshal(body, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Invalid JSON")
+		writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Invalid JSON")
 		return
 	}
 
@@ -51,25 +54,36 @@ func (h *Handler) handleVerify(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrNotFound):
-			writeError(w, http.StatusNotFound, "NOT_FOUND", "Package or contract not found")
+			writeError(w, http.StatusNotFound, errcodes.NotFound, "Package or contract not found")
 		case errors.Is(err, domain.ErrInvalidAddress):
-			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
 		case errors.Is(err, domain.ErrInvalidChainID):
-			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, err.Error())
 		case errors.Is(err, domain.ErrChainNotFound):
-			writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "Chain not supported")
+			writeError(w, http.StatusBadRequest, errcodes.InvalidRequest, "Chain not supported")
 		default:
-			writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to verify contract")
+			writeError(w, http.StatusInternalServerError, errcodes.InternalError, "Failed to verify contract")
 		}
 		return
 	}
 
-	writeJSON(w, http.StatusOK, VerifyResponse{
-		Success: result.Verified,
-		Message: result.Message,
-		ChainID: strconv.Itoa(req.ChainID),
-		Address: req.Address,
-	})
+	chainID := req.Cluster
+	if chainID == "" {
+		chainID = strconv.Itoa(req.ChainID)
+	}
+	resp := VerifyResponse{
+		Success:   result.Verified,
+		MatchType: result.MatchType,
+		Message:   result.Message,
+		ChainID:   chainID,
+		Address:   req.Address,
+		Details:   result.Details,
+		Cached:    result.Cached,
+	}
+	if !result.CheckedAt.IsZero() {
+		resp.CheckedAt = result.CheckedAt.Format(time.RFC3339)
+	}
+	writeJSON(w, http.StatusOK, resp)
 }
 
 // Helper functions
@@ -84,6 +98,6 @@ func writeError(w http.ResponseWriter, status int, code, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(ErrorResponse{
-		Error: ErrorDetail{Code: code, Message: message},
+		Error: ErrorDetail{Code: code, Message: message, RequestID: w.Header().Get(requestid.Header)},
 	})
 }
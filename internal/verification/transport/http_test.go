@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -48,10 +49,19 @@ func setupRouter(svc Service) *chi.Mux {
 
 func TestHandler_Verify(t *testing.T) {
 	svc := newMockService()
+	offset, metadataOffset := 140, 120
 	svc.results["my-pkg@1.0.0/Token"] = &domain.VerifyResult{
 		Verified:  true,
-		MatchType: "full",
+		MatchType: "partial",
 		Message:   "Bytecode matches",
+		Details: &domain.VerifyDetails{
+			MetadataStripped: true,
+			DivergenceOffset: &offset,
+			MetadataOffset:   &metadataOffset,
+			MetadataLength:   53,
+		},
+		CheckedAt: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		Cached:    true,
 	}
 
 	router := setupRouter(svc)
@@ -80,6 +90,11 @@ func TestHandler_Verify(t *testing.T) {
 		assert.Equal(t, "Bytecode matches", resp.Message)
 		assert.Equal(t, "1", resp.ChainID)
 		assert.Equal(t, "0x1234567890abcdef1234567890abcdef12345678", resp.Address)
+		assert.Equal(t, "partial", resp.MatchType)
+		assert.Contains(t, rec.Body.String(), `"divergenceOffset":140`)
+		assert.Contains(t, rec.Body.String(), `"metadataLength":53`)
+		assert.True(t, resp.Cached)
+		assert.Equal(t, "2026-03-04T05:06:07Z", resp.CheckedAt)
 	})
 
 	t.Run("pending verification", func(t *testing.T) {
@@ -104,6 +119,8 @@ func TestHandler_Verify(t *testing.T) {
 		require.NoError(t, err)
 		assert.False(t, resp.Success)
 		assert.Equal(t, "Verification pending", resp.Message)
+		assert.NotContains(t, rec.Body.String(), "checkedAt")
+		assert.NotContains(t, rec.Body.String(), "cached")
 	})
 }
 
@@ -9,6 +9,7 @@ type VerifyRequest struct {
 	Version     string `json:"version"`
 	Contract    string `json:"contract"`
 	ChainID     int    `json:"chainId"`
+	Cluster     string `json:"cluster,omitempty"`
 	Address     string `json:"address"`
 	RPCEndpoint string `json:"rpcEndpoint,omitempty"`
 }
@@ -20,6 +21,7 @@ func (r VerifyRequest) ToDomain() domain.VerifyRequest {
 		Version:     r.Version,
 		Contract:    r.Contract,
 		ChainID:     r.ChainID,
+		Cluster:     r.Cluster,
 		Address:     r.Address,
 		RPCEndpoint: r.RPCEndpoint,
 	}
@@ -27,10 +29,14 @@ func (r VerifyRequest) ToDomain() domain.VerifyRequest {
 
 // VerifyResponse is the response for a verification request.
 type VerifyResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	ChainID string `json:"chainId,omitempty"`
-	Address string `json:"address,omitempty"`
+	Success   bool                  `json:"success"`
+	MatchType string                `json:"matchType,omitempty"` // full, partial, none or pending
+	Message   string                `json:"message"`
+	ChainID   string                `json:"chainId,omitempty"`
+	Address   string                `json:"address,omitempty"`
+	Details   *domain.VerifyDetails `json:"details,omitempty"`
+	CheckedAt string                `json:"checkedAt,omitempty"` // when the on-chain code was compared
+	Cached    bool                  `json:"cached,omitempty"`    // served from the server's verification cache
 }
 
 // ErrorResponse is the standard error response format.
@@ -40,6 +46,7 @@ type ErrorResponse struct {
 
 // ErrorDetail contains error information.
 type ErrorDetail struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"requestId,omitempty"`
 }
@@ -5,11 +5,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
+
+	"github.com/pendergraft/contrafactory/internal/server/errcodes"
+	"github.com/pendergraft/contrafactory/internal/validation"
 )
 
 // Client is a Contrafactory API client
@@ -17,6 +22,7 @@ type Client struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
+	bearerAuth bool
 }
 
 // Option configures a Client
@@ -29,6 +35,24 @@ func WithHTTPClient(c *http.Client) Option {
 	}
 }
 
+// WithTimeout sets the timeout of each request, 30s by default. Zero means no
+// timeout. A client given by WithHTTPClient earlier is copied, not modified.
+func WithTimeout(d time.Duration) Option {
+	return func(client *Client) {
+		hc := *client.httpClient
+		hc.Timeout = d
+		client.httpClient = &hc
+	}
+}
+
+// WithBearerAuth also sends the API key as an Authorization: Bearer header, for
+// gateways and proxies that strip X-API-Key.
+func WithBearerAuth() Option {
+	return func(client *Client) {
+		client.bearerAuth = true
+	}
+}
+
 // New creates a new Contrafactory client
 func New(baseURL, apiKey string, opts ...Option) *Client {
 	c := &Client{
@@ -48,14 +72,18 @@ func New(baseURL, apiKey string, opts ...Option) *Client {
 
 // Package represents a package in the registry
 type Package struct {
-	Name            string   `json:"name"`
-	Version         string   `json:"version,omitempty"`
-	Chain           string   `json:"chain,omitempty"`
-	Builder         string   `json:"builder,omitempty"`
-	CompilerVersion string   `json:"compilerVersion,omitempty"`
-	Contracts       []string `json:"contracts,omitempty"`
-	CreatedAt       string   `json:"createdAt,omitempty"`
-	Versions        []string `json:"versions,omitempty"`
+	Name            string         `json:"name"`
+	Version         string         `json:"version,omitempty"`
+	Project         string         `json:"project,omitempty"`
+	Chain           string         `json:"chain,omitempty"`
+	Builder         string         `json:"builder,omitempty"`
+	CompilerVersion string         `json:"compilerVersion,omitempty"`
+	Contracts       []string       `json:"contracts,omitempty"`
+	CreatedAt       string         `json:"createdAt,omitempty"`
+	Versions        []string       `json:"versions,omitempty"`
+	Metadata        map[string]any `json:"metadata,omitempty"`
+	Tags            []string       `json:"tags,omitempty"`
+	Warnings        []string       `json:"warnings,omitempty"` // advisories, e.g. a solc mismatch
 }
 
 // Contract represents a contract in a package
@@ -65,6 +93,8 @@ type Contract struct {
 	Chain             string            `json:"chain"`
 	SourcePath        string            `json:"sourcePath"`
 	License           string            `json:"license,omitempty"`
+	Labels            []string          `json:"labels,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
 	CompilationTarget map[string]string `json:"compilationTarget,omitempty"`
 	Compiler          *CompilerInfo     `json:"compiler,omitempty"`
 }
@@ -78,28 +108,78 @@ type VersionDeployment struct {
 	TxHash       string `json:"txHash,omitempty"`
 }
 
+// BytecodeMatch is a published contract whose bytecode matched a lookup
+type BytecodeMatch struct {
+	Package   string `json:"package"`
+	Version   string `json:"version"`
+	Contract  string `json:"contract"`
+	Chain     string `json:"chain"`
+	MatchedOn string `json:"matchedOn"` // "bytecode", "deployed-bytecode" or "program"
+}
+
+// SelectorMatch is a published contract whose ABI has a looked-up selector
+type SelectorMatch struct {
+	Package   string `json:"package"`
+	Version   string `json:"version"`
+	Contract  string `json:"contract"`
+	Chain     string `json:"chain"`
+	Type      string `json:"type"`      // "function", "error" or "event"
+	Signature string `json:"signature"` // e.g. transfer(address,uint256)
+}
+
 // Deployment represents a recorded deployment
 type Deployment struct {
-	ID              string   `json:"id"`
-	PackageID       string   `json:"packageId"`
-	ContractName    string   `json:"contractName"`
-	Chain           string   `json:"chain"`
-	ChainID         string   `json:"chainId"`
-	Address         string   `json:"address"`
-	DeployerAddress string   `json:"deployerAddress,omitempty"`
-	TxHash          string   `json:"txHash,omitempty"`
-	BlockNumber     int64    `json:"blockNumber,omitempty"`
-	Verified        bool     `json:"verified"`
-	VerifiedOn      []string `json:"verifiedOn,omitempty"`
-	CreatedAt       string   `json:"createdAt"`
+	ID              string            `json:"id"`
+	PackageID       string            `json:"packageId"`
+	PackageName     string            `json:"packageName,omitempty"`
+	PackageVersion  string            `json:"packageVersion,omitempty"`
+	ContractName    string            `json:"contractName"`
+	Chain           string            `json:"chain"`
+	ChainID         string            `json:"chainId"`
+	Address         string            `json:"address"`
+	DeployerAddress string            `json:"deployerAddress,omitempty"`
+	TxHash          string            `json:"txHash,omitempty"`
+	BlockNumber     int64             `json:"blockNumber,omitempty"`
+	ConstructorArgs string            `json:"constructorArgs,omitempty"`
+	Libraries       map[string]string `json:"libraries,omitempty"`
+	Salt            string            `json:"salt,omitempty"`
+	GasUsed         uint64            `json:"gasUsed,omitempty"`
+	Timestamp       string            `json:"timestamp,omitempty"`
+	Verified        bool              `json:"verified"`
+	VerifiedOn      []string          `json:"verifiedOn,omitempty"`
+	CreatedAt       string            `json:"createdAt"`
+}
+
+// VerificationStatus is the explorer verification status of a deployment
+type VerificationStatus struct {
+	ChainID    string   `json:"chainId"`
+	Address    string   `json:"address"`
+	Status     string   `json:"status"` // verified or unverified
+	Verified   bool     `json:"verified"`
+	VerifiedOn []string `json:"verifiedOn,omitempty"`
 }
 
 // PublishRequest is the request for publishing a package
 type PublishRequest struct {
-	Chain     string     `json:"chain"`
-	Builder   string     `json:"builder,omitempty"`
-	Project   string     `json:"project,omitempty"`
-	Artifacts []Artifact `json:"artifacts"`
+	Chain     string            `json:"chain"`
+	Builder   string            `json:"builder,omitempty"`
+	Project   string            `json:"project,omitempty"`
+	Artifacts []Artifact        `json:"artifacts"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+}
+
+// Validate checks the request has a chain and at least one artifact, each with a
+// name, using the same rules as the server. Publish calls it before sending.
+func (r PublishRequest) Validate() error {
+	names := make([]string, len(r.Artifacts))
+	for i, a := range r.Artifacts {
+		names[i] = a.Name
+	}
+	if errs := validation.ValidatePublishRequest(r.Chain, names); errs != nil {
+		return fmt.Errorf("invalid publish request: %w", errs)
+	}
+	return nil
 }
 
 // Artifact represents a contract artifact for publishing
@@ -107,12 +187,25 @@ type Artifact struct {
 	Name              string          `json:"name"`
 	SourcePath        string          `json:"sourcePath"`
 	License           string          `json:"license,omitempty"`
-	ABI               json.RawMessage `json:"abi"`
+	ABI               json.RawMessage `json:"abi,omitempty"`
 	Bytecode          string          `json:"bytecode"`
 	DeployedBytecode  string          `json:"deployedBytecode"`
 	StandardJSONInput json.RawMessage `json:"standardJsonInput,omitempty"`
 	StorageLayout     json.RawMessage `json:"storageLayout,omitempty"`
 	Compiler          *CompilerInfo   `json:"compiler,omitempty"`
+	IDL               json.RawMessage `json:"idl,omitempty"`     // Solana: Anchor IDL
+	Program           []byte          `json:"program,omitempty"` // Solana: program binary
+	Labels            []string        `json:"labels,omitempty"`
+
+	// Contract-level metadata (e.g. audit status), alongside PublishRequest.Metadata
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Extra artifacts by type (e.g. "devdoc", "method-identifiers"); see GetArtifactByType
+	Extra map[string]json.RawMessage `json:"extra,omitempty"`
+
+	// Publisher signature over the ABI and bytecode (publish only); see GetSignature
+	Signature string `json:"signature,omitempty"`
+	PublicKey string `json:"publicKey,omitempty"`
 }
 
 // CompilerInfo contains compiler settings
@@ -135,6 +228,7 @@ type VerifyRequest struct {
 	Version     string `json:"version"`
 	Contract    string `json:"contract"`
 	ChainID     int    `json:"chainId"`
+	Cluster     string `json:"cluster,omitempty"` // Solana cluster, instead of ChainID
 	Address     string `json:"address"`
 	RPCEndpoint string `json:"rpcEndpoint,omitempty"`
 }
@@ -145,11 +239,18 @@ type VerifyResult struct {
 	MatchType string         `json:"matchType"`
 	Message   string         `json:"message"`
 	Details   *VerifyDetails `json:"details,omitempty"`
+	CheckedAt string         `json:"checkedAt,omitempty"` // when the on-chain code was compared
+	Cached    bool           `json:"cached,omitempty"`    // served from the server's verification cache
 }
 
 // VerifyDetails contains additional verification details
 type VerifyDetails struct {
 	ExpectedBytecodeHash string `json:"expectedBytecodeHash,omitempty"`
+
+	// For "clone" matches: the implementation the EIP-1167 minimal proxy delegates
+	// to, and how its code compared with the artifact
+	Implementation          string `json:"implementation,omitempty"`
+	ImplementationMatchType string `json:"implementationMatchType,omitempty"`
 }
 
 // DeploymentRequest is the request for recording a deployment
@@ -158,12 +259,16 @@ type DeploymentRequest struct {
 	Version         string            `json:"version"`
 	Contract        string            `json:"contract"`
 	ChainID         int               `json:"chainId"`
-	Address         string            `json:"address"`
+	Cluster         string            `json:"cluster,omitempty"` // Solana cluster, instead of ChainID
+	Address         string            `json:"address"`           // EVM address or Solana program ID
 	TxHash          string            `json:"txHash,omitempty"`
 	DeployerAddress string            `json:"deployerAddress,omitempty"`
 	BlockNumber     int64             `json:"blockNumber,omitempty"`
 	ConstructorArgs string            `json:"constructorArgs,omitempty"`
 	Libraries       map[string]string `json:"libraries,omitempty"`
+	Salt            string            `json:"salt,omitempty"` // CREATE2 salt, bytes32 hex
+	GasUsed         uint64            `json:"gasUsed,omitempty"`
+	Timestamp       string            `json:"timestamp,omitempty"` // RFC3339 time the deployment was mined
 }
 
 // ListPackagesResponse is the response for listing packages
@@ -177,18 +282,112 @@ type Pagination struct {
 	Limit      int    `json:"limit"`
 	HasMore    bool   `json:"hasMore"`
 	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+	Total      *int   `json:"total,omitempty"` // only when requested with ListPackagesOptions.Count
 }
 
+// ErrorCode is the machine-readable code of an API error response.
+type ErrorCode string
+
+// Error codes returned by the server.
+const (
+	ErrorCodeInvalidRequest    ErrorCode = errcodes.InvalidRequest
+	ErrorCodeValidationError   ErrorCode = errcodes.ValidationError
+	ErrorCodeBadRequest        ErrorCode = errcodes.BadRequest
+	ErrorCodeInvalidVersion    ErrorCode = errcodes.InvalidVersion
+	ErrorCodeInvalidLabel      ErrorCode = errcodes.InvalidLabel
+	ErrorCodeInvalidABI        ErrorCode = errcodes.InvalidABI
+	ErrorCodeInvalidSignature  ErrorCode = errcodes.InvalidSignature
+	ErrorCodeUnauthorized      ErrorCode = errcodes.Unauthorized
+	ErrorCodeForbidden         ErrorCode = errcodes.Forbidden
+	ErrorCodeNotFound          ErrorCode = errcodes.NotFound
+	ErrorCodeVersionExists     ErrorCode = errcodes.VersionExists
+	ErrorCodeNoMetadata        ErrorCode = errcodes.NoMetadata
+	ErrorCodeNotVerifiable     ErrorCode = errcodes.NotVerifiable
+	ErrorCodeQuotaExceeded     ErrorCode = errcodes.QuotaExceeded
+	ErrorCodeTooManyArtifacts  ErrorCode = errcodes.TooManyArtifacts
+	ErrorCodeRateLimitExceeded ErrorCode = errcodes.RateLimitExceeded
+	ErrorCodeInternalError     ErrorCode = errcodes.InternalError
+)
+
+// FieldError is a problem with one field of a request body, identified by its
+// JSON path (e.g. "artifacts[2].name").
+type FieldError = validation.FieldError
+
 // APIError represents an API error response
 type APIError struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code       ErrorCode    `json:"code"`
+	Message    string       `json:"message"`
+	Fields     []FieldError `json:"fields,omitempty"`    // the invalid fields of a VALIDATION_ERROR
+	RequestID  string       `json:"requestId,omitempty"` // correlates the failure with server logs
+	StatusCode int          `json:"-"`
 }
 
+// Error includes the request ID for server-side (5xx) failures so it ends up in
+// whatever the user pastes into a bug report.
 func (e *APIError) Error() string {
+	if e.StatusCode >= 500 && e.RequestID != "" {
+		return fmt.Sprintf("%s: %s (request ID: %s)", e.Code, e.Message, e.RequestID)
+	}
 	return fmt.Sprintf("%s: %s", e.Code, e.Message)
 }
 
+// HasErrorCode reports whether err is an API error with the given code.
+func HasErrorCode(err error, code ErrorCode) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// IsNotFound reports whether err means the requested resource doesn't exist.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && (apiErr.Code == ErrorCodeNotFound || apiErr.StatusCode == http.StatusNotFound)
+}
+
+// IsVersionExists reports whether err is a publish rejected because the version
+// is already in the registry.
+func IsVersionExists(err error) bool {
+	return HasErrorCode(err, ErrorCodeVersionExists)
+}
+
+// IsForbidden reports whether err is a request refused for the API key used, e.g.
+// changing a package owned by another key.
+func IsForbidden(err error) bool {
+	return HasErrorCode(err, ErrorCodeForbidden)
+}
+
+// Health is the response of the server's health check
+type Health struct {
+	Status  string `json:"status"`
+	Version string `json:"version,omitempty"` // empty for servers that don't report it
+}
+
+// GetHealth checks the server's health and gets its version
+func (c *Client) GetHealth(ctx context.Context) (*Health, error) {
+	var resp Health
+	if err := c.get(ctx, "/health", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Limits describes server-enforced request limits
+type Limits struct {
+	MaxArtifactsPerPublish int `json:"maxArtifactsPerPublish"` // 0 = unlimited
+	MaxBodySizeMB          int `json:"maxBodySizeMB"`
+	MaxOwnerStorageMB      int `json:"maxOwnerStorageMB"`     // 0 = unlimited
+	MaxVersionsPerPackage  int `json:"maxVersionsPerPackage"` // 0 = unlimited
+}
+
+// GetLimits gets the server's request limits
+func (c *Client) GetLimits(ctx context.Context) (*Limits, error) {
+	var resp Limits
+	if err := c.get(ctx, "/api/v1/limits", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // ListPackages lists packages in the registry
 func (c *Client) ListPackages(ctx context.Context) (*ListPackagesResponse, error) {
 	var resp ListPackagesResponse
@@ -198,19 +397,287 @@ func (c *Client) ListPackages(ctx context.Context) (*ListPackagesResponse, error
 	return &resp, nil
 }
 
+// ListPackagesOptions filters a package listing
+type ListPackagesOptions struct {
+	Query    string // substring match on package name
+	Chain    string
+	Project  string
+	Contract string // only packages containing a contract with this name
+	Label    string // only packages with a contract carrying this label
+	Tag      string // only packages with a version carrying this tag
+	Limit    int    // page size (server default when zero)
+	Cursor   string // Pagination.NextCursor of the previous page
+	Count    bool   // also return the total number of matches in Pagination.Total
+	// Metadata only returns versions whose package metadata has all these key/value pairs
+	Metadata map[string]string
+	// CreatedAfter only returns packages created at or after this time (ignored when zero)
+	CreatedAfter time.Time
+}
+
+// ListPackagesWithOptions lists packages matching the given filters
+func (c *Client) ListPackagesWithOptions(ctx context.Context, opts ListPackagesOptions) (*ListPackagesResponse, error) {
+	query := url.Values{}
+	if opts.Query != "" {
+		query.Set("q", opts.Query)
+	}
+	if opts.Chain != "" {
+		query.Set("chain", opts.Chain)
+	}
+	if opts.Project != "" {
+		query.Set("project", opts.Project)
+	}
+	if opts.Contract != "" {
+		query.Set("contract", opts.Contract)
+	}
+	if opts.Label != "" {
+		query.Set("label", opts.Label)
+	}
+	if opts.Tag != "" {
+		query.Set("tag", opts.Tag)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Cursor != "" {
+		query.Set("cursor", opts.Cursor)
+	}
+	if opts.Count {
+		query.Set("count", "true")
+	}
+	if !opts.CreatedAfter.IsZero() {
+		query.Set("created_after", opts.CreatedAfter.Format(time.RFC3339))
+	}
+	for key, value := range opts.Metadata {
+		query.Set("metadata."+key, value)
+	}
+	path := "/api/v1/packages"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var resp ListPackagesResponse
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListMyPackages lists the packages owned by the client's API key. limit is the page
+// size (server default when zero) and cursor the Pagination.NextCursor of the
+// previous page.
+func (c *Client) ListMyPackages(ctx context.Context, limit int, cursor string) (*ListPackagesResponse, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	path := "/api/v1/me/packages"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var resp ListPackagesResponse
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // GetPackage gets a package by name
 func (c *Client) GetPackage(ctx context.Context, name string) (*Package, error) {
-	var resp Package
-	if err := c.get(ctx, "/api/v1/packages/"+url.PathEscape(name), &resp); err != nil {
+	return c.getPackageVersions(ctx, name, url.Values{})
+}
+
+// PackageOwner is the API key that owns a package name
+type PackageOwner struct {
+	Name  string `json:"name"`
+	Owner string `json:"owner"` // name of the owning API key
+	Since string `json:"since,omitempty"`
+}
+
+// GetPackageOwner gets who owns a package. The server only answers for the
+// owning key itself or an admin key; anyone else gets a FORBIDDEN error.
+func (c *Client) GetPackageOwner(ctx context.Context, name string) (*PackageOwner, error) {
+	var resp PackageOwner
+	if err := c.get(ctx, "/api/v1/packages/"+url.PathEscape(name)+"/owner", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// TransferPackageOwner hands a package to the API key with ID toKeyID. Only the
+// current owner may transfer a package.
+func (c *Client) TransferPackageOwner(ctx context.Context, name, toKeyID string) (*PackageOwner, error) {
+	var resp PackageOwner
+	path := "/api/v1/packages/" + url.PathEscape(name) + "/transfer-owner"
+	if err := c.post(ctx, path, map[string]string{"toKeyId": toKeyID}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PackageCollaborators lists the API keys that may publish to and delete from a
+// package besides its owner.
+type PackageCollaborators struct {
+	Name          string                `json:"name"`
+	Collaborators []PackageCollaborator `json:"collaborators"`
+}
+
+// PackageCollaborator is one collaborator key of a package
+type PackageCollaborator struct {
+	KeyID string `json:"keyId"`
+	Name  string `json:"name"` // name of the API key
+	Since string `json:"since,omitempty"`
+}
+
+// ListPackageCollaborators lists the collaborators of a package. The server
+// answers for the owner, its collaborators and admin keys.
+func (c *Client) ListPackageCollaborators(ctx context.Context, name string) (*PackageCollaborators, error) {
+	var resp PackageCollaborators
+	if err := c.get(ctx, "/api/v1/packages/"+url.PathEscape(name)+"/collaborators", &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
 }
 
+// AddPackageCollaborator lets the API key with ID keyID publish to and delete from
+// a package. Only the owner may add collaborators.
+func (c *Client) AddPackageCollaborator(ctx context.Context, name, keyID string) (*PackageCollaborators, error) {
+	var resp PackageCollaborators
+	path := "/api/v1/packages/" + url.PathEscape(name) + "/collaborators"
+	if err := c.post(ctx, path, map[string]string{"keyId": keyID}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RemovePackageCollaborator revokes a collaborator's access to a package. Only
+// the owner may remove collaborators.
+func (c *Client) RemovePackageCollaborator(ctx context.Context, name, keyID string) (*PackageCollaborators, error) {
+	path := "/api/v1/packages/" + url.PathEscape(name) + "/collaborators/" + url.PathEscape(keyID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp PackageCollaborators
+	if err := c.do(req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// TagCount is a package tag and how many packages carry it
+type TagCount struct {
+	Tag      string `json:"tag"`
+	Packages int    `json:"packages"`
+}
+
+// ListTags lists every package tag in use with how many packages carry it.
+func (c *Client) ListTags(ctx context.Context) ([]TagCount, error) {
+	var resp struct {
+		Tags []TagCount `json:"tags"`
+	}
+	if err := c.get(ctx, "/api/v1/tags", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tags, nil
+}
+
+// PackageAliases lists the aliases of a package
+type PackageAliases struct {
+	Name    string         `json:"name"`
+	Aliases []PackageAlias `json:"aliases"`
+}
+
+// PackageAlias is a named pointer to a published version, such as "stable" or
+// "audited". Reads accept an alias wherever they accept a version.
+type PackageAlias struct {
+	Alias     string `json:"alias"`
+	Version   string `json:"version"`
+	UpdatedAt string `json:"updatedAt,omitempty"`
+}
+
+// ListPackageAliases lists the aliases of a package.
+func (c *Client) ListPackageAliases(ctx context.Context, name string) (*PackageAliases, error) {
+	var resp PackageAliases
+	if err := c.get(ctx, "/api/v1/packages/"+url.PathEscape(name)+"/aliases", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetPackageAlias points alias at a published version of a package, creating or
+// moving it. Only the owner may set aliases.
+func (c *Client) SetPackageAlias(ctx context.Context, name, alias, version string) (*PackageAlias, error) {
+	var resp PackageAlias
+	path := "/api/v1/packages/" + url.PathEscape(name) + "/aliases/" + url.PathEscape(alias)
+	if err := c.post(ctx, path, map[string]string{"version": version}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeletePackageAlias removes an alias of a package. Only the owner may remove
+// aliases.
+func (c *Client) DeletePackageAlias(ctx context.Context, name, alias string) error {
+	return c.delete(ctx, "/api/v1/packages/"+url.PathEscape(name)+"/aliases/"+url.PathEscape(alias))
+}
+
+// GetVersions lists the published versions of a package, newest first. Prereleases
+// are only included when includePrerelease is set.
+func (c *Client) GetVersions(ctx context.Context, name string, includePrerelease bool) ([]string, error) {
+	query := url.Values{}
+	if includePrerelease {
+		query.Set("include_prerelease", "true")
+	}
+	pkg, err := c.getPackageVersions(ctx, name, query)
+	if err != nil {
+		return nil, err
+	}
+	return pkg.Versions, nil
+}
+
+// getPackageVersions gets a package with its versions, following the server's
+// version pages until all are read.
+func (c *Client) getPackageVersions(ctx context.Context, name string, query url.Values) (*Package, error) {
+	query.Set("limit", "100")
+	var pkg *Package
+	for {
+		var resp struct {
+			Package
+			Pagination Pagination `json:"pagination"`
+		}
+		if err := c.get(ctx, "/api/v1/packages/"+url.PathEscape(name)+"?"+query.Encode(), &resp); err != nil {
+			return nil, err
+		}
+		if pkg == nil {
+			pkg = &resp.Package
+		} else {
+			pkg.Versions = append(pkg.Versions, resp.Versions...)
+		}
+		if !resp.Pagination.HasMore || resp.Pagination.NextCursor == "" {
+			return pkg, nil
+		}
+		query.Set("cursor", resp.Pagination.NextCursor)
+	}
+}
+
 // GetPackageVersion gets a specific package version
 func (c *Client) GetPackageVersion(ctx context.Context, name, version string) (*Package, error) {
+	return c.GetPackageVersionForSolc(ctx, name, version, "")
+}
+
+// GetPackageVersionForSolc gets a specific package version, asking the server to
+// compare solc, the compiler the caller builds with, against the one the package
+// was published with. A major or minor mismatch comes back in Package.Warnings.
+func (c *Client) GetPackageVersionForSolc(ctx context.Context, name, version, solc string) (*Package, error) {
 	var resp Package
 	path := fmt.Sprintf("/api/v1/packages/%s/%s", url.PathEscape(name), url.PathEscape(version))
+	if solc != "" {
+		path += "?" + url.Values{"solc": {solc}}.Encode()
+	}
 	if err := c.get(ctx, path, &resp); err != nil {
 		return nil, err
 	}
@@ -219,10 +686,20 @@ func (c *Client) GetPackageVersion(ctx context.Context, name, version string) (*
 
 // Publish publishes a new package version
 func (c *Client) Publish(ctx context.Context, name, version string, req PublishRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
 	path := fmt.Sprintf("/api/v1/packages/%s/%s", url.PathEscape(name), url.PathEscape(version))
 	return c.post(ctx, path, req, nil)
 }
 
+// PublishJSON publishes a new package version from an already encoded publish
+// request, for callers with their own artifact types. The server validates it.
+func (c *Client) PublishJSON(ctx context.Context, name, version string, body json.RawMessage) error {
+	path := fmt.Sprintf("/api/v1/packages/%s/%s", url.PathEscape(name), url.PathEscape(version))
+	return c.post(ctx, path, body, nil)
+}
+
 // GetABI gets the ABI for a contract
 func (c *Client) GetABI(ctx context.Context, name, version, contract string) (json.RawMessage, error) {
 	path := fmt.Sprintf("/api/v1/packages/%s/%s/contracts/%s/abi",
@@ -258,6 +735,49 @@ func (c *Client) GetStorageLayout(ctx context.Context, name, version, contract s
 	return c.getRaw(ctx, path)
 }
 
+// GetIDL gets the Anchor IDL for a Solana program
+func (c *Client) GetIDL(ctx context.Context, name, version, contract string) (json.RawMessage, error) {
+	path := fmt.Sprintf("/api/v1/packages/%s/%s/contracts/%s/idl",
+		url.PathEscape(name), url.PathEscape(version), url.PathEscape(contract))
+	return c.getRaw(ctx, path)
+}
+
+// GetProgram gets the program binary (.so) for a Solana program
+func (c *Client) GetProgram(ctx context.Context, name, version, contract string) ([]byte, error) {
+	path := fmt.Sprintf("/api/v1/packages/%s/%s/contracts/%s/program",
+		url.PathEscape(name), url.PathEscape(version), url.PathEscape(contract))
+	return c.getRaw(ctx, path)
+}
+
+// Signature is the publisher signature of a signed contract, checked by the server
+// when the contract was published.
+type Signature struct {
+	Algorithm string `json:"algorithm"` // ed25519
+	PublicKey string `json:"publicKey"` // base64 raw public key
+	Signature string `json:"signature"` // base64
+	Digest    string `json:"digest"`    // "sha256:<hex>" of the ABI and bytecode that were signed
+}
+
+// GetSignature gets the publisher signature of a contract; a NOT_FOUND error means
+// the contract wasn't signed.
+func (c *Client) GetSignature(ctx context.Context, name, version, contract string) (*Signature, error) {
+	var resp Signature
+	path := fmt.Sprintf("/api/v1/packages/%s/%s/contracts/%s/signature",
+		url.PathEscape(name), url.PathEscape(version), url.PathEscape(contract))
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetArtifactByType gets any stored artifact of a contract by type, including extra
+// artifacts published under a custom type (e.g. "devdoc", "method-identifiers")
+func (c *Client) GetArtifactByType(ctx context.Context, name, version, contract, artifactType string) ([]byte, error) {
+	path := fmt.Sprintf("/api/v1/packages/%s/%s/contracts/%s/artifacts/%s",
+		url.PathEscape(name), url.PathEscape(version), url.PathEscape(contract), url.PathEscape(artifactType))
+	return c.getRaw(ctx, path)
+}
+
 // RecordDeployment records a deployment
 func (c *Client) RecordDeployment(ctx context.Context, req DeploymentRequest) error {
 	return c.post(ctx, "/api/v1/deployments", req, nil)
@@ -273,6 +793,28 @@ func (c *Client) GetDeployment(ctx context.Context, chainID, address string) (*D
 	return &resp, nil
 }
 
+// MarkDeploymentVerified records that a deployment was verified on an explorer,
+// e.g. "etherscan" or "blockscout:https://eth.blockscout.com"
+func (c *Client) MarkDeploymentVerified(ctx context.Context, chainID, address, explorer string) (*Deployment, error) {
+	var resp Deployment
+	path := fmt.Sprintf("/api/v1/deployments/%s/%s/verified", url.PathEscape(chainID), url.PathEscape(address))
+	if err := c.post(ctx, path, map[string]string{"explorer": explorer}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetVerificationStatus reports whether a recorded deployment has been verified on
+// an explorer.
+func (c *Client) GetVerificationStatus(ctx context.Context, chainID, address string) (*VerificationStatus, error) {
+	var resp VerificationStatus
+	query := url.Values{"chainId": {chainID}, "address": {address}}
+	if err := c.get(ctx, "/api/v1/deployments/verification-status?"+query.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // Verify verifies a deployed contract
 func (c *Client) Verify(ctx context.Context, req VerifyRequest) (*VerifyResult, error) {
 	var resp VerifyResult
@@ -316,9 +858,43 @@ func (c *Client) GetVersionDeployments(ctx context.Context, name, version string
 	return resp.Deployments, nil
 }
 
+// LookupBytecode finds published contracts whose bytecode has the given hash,
+// "sha256:<hex>" (a bare hex digest is taken to be sha256). Hashes are taken over the
+// 0x-prefixed lowercase hex form artifacts are published in.
+func (c *Client) LookupBytecode(ctx context.Context, hash string) ([]BytecodeMatch, error) {
+	var resp struct {
+		Matches []BytecodeMatch `json:"matches"`
+	}
+	if err := c.get(ctx, "/api/v1/lookup/bytecode?hash="+url.QueryEscape(hash), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Matches, nil
+}
+
+// LookupSelector finds published contracts whose ABI has a function or error with the
+// given 4-byte selector, or an event with the given 32-byte topic (0x-prefixed hex).
+func (c *Client) LookupSelector(ctx context.Context, selector string) ([]SelectorMatch, error) {
+	var resp struct {
+		Matches []SelectorMatch `json:"matches"`
+	}
+	if err := c.get(ctx, "/api/v1/lookup/selector/"+url.PathEscape(selector), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Matches, nil
+}
+
 // GetArchive gets the archive for a package version
 func (c *Client) GetArchive(ctx context.Context, name, version string) ([]byte, error) {
+	return c.GetArchiveFormat(ctx, name, version, "")
+}
+
+// GetArchiveFormat gets the archive for a package version as "tar.gz" or "zip"
+// ("" for the server default, tar.gz)
+func (c *Client) GetArchiveFormat(ctx context.Context, name, version, format string) ([]byte, error) {
 	path := fmt.Sprintf("/api/v1/packages/%s/%s/archive", url.PathEscape(name), url.PathEscape(version))
+	if format != "" {
+		path += "?format=" + url.QueryEscape(format)
+	}
 	return c.getRaw(ctx, path)
 }
 
@@ -462,6 +1038,9 @@ func (c *Client) do(req *http.Request, result any) error {
 func (c *Client) setHeaders(req *http.Request) {
 	if c.apiKey != "" {
 		req.Header.Set("X-API-Key", c.apiKey)
+		if c.bearerAuth {
+			req.Header.Set("Authorization", "Bearer "+c.apiKey)
+		}
 	}
 	req.Header.Set("Accept", "application/json")
 }
@@ -470,8 +1049,16 @@ func (c *Client) parseError(resp *http.Response) error {
 	var errResp struct {
 		Error APIError `json:"error"`
 	}
+	requestID := resp.Header.Get("X-Request-Id")
 	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+		if resp.StatusCode >= 500 && requestID != "" {
+			return fmt.Errorf("HTTP %d: %s (request ID: %s)", resp.StatusCode, resp.Status, requestID)
+		}
 		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
 	}
+	errResp.Error.StatusCode = resp.StatusCode
+	if errResp.Error.RequestID == "" {
+		errResp.Error.RequestID = requestID
+	}
 	return &errResp.Error
 }
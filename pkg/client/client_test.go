@@ -3,9 +3,15 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestClient_ListPackages(t *testing.T) {
@@ -43,6 +49,83 @@ func TestClient_ListPackages(t *testing.T) {
 	}
 }
 
+func TestClient_ListPackagesWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := map[string]string{"q": "token", "chain": "evm", "project": "myproj", "contract": "Vault", "tag": "defi", "limit": "5", "created_after": "2024-05-01T10:00:00Z", "metadata.audit_status": "passed"}
+		for key, value := range want {
+			if got := r.URL.Query().Get(key); got != value {
+				t.Errorf("query %s = %q, want %q", key, got, value)
+			}
+		}
+		if r.URL.Query().Has("label") {
+			t.Errorf("unexpected label parameter")
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"data":       []map[string]any{{"name": "token-vault", "contracts": []string{"Vault"}}},
+			"pagination": map[string]any{"limit": 5},
+		})
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key")
+	resp, err := client.ListPackagesWithOptions(context.Background(), ListPackagesOptions{
+		Query: "token", Chain: "evm", Project: "myproj", Contract: "Vault", Tag: "defi", Limit: 5,
+		CreatedAfter: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Metadata:     map[string]string{"audit_status": "passed"},
+	})
+	if err != nil {
+		t.Fatalf("ListPackagesWithOptions() error = %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0].Contracts[0] != "Vault" {
+		t.Errorf("ListPackagesWithOptions() = %+v", resp.Data)
+	}
+}
+
+func TestClient_ListTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/tags" {
+			t.Errorf("path = %s, want /api/v1/tags", r.URL.Path)
+		}
+		w.Write([]byte(`{"tags":[{"tag":"defi","packages":3},{"tag":"governance","packages":1}]}`))
+	}))
+	defer server.Close()
+
+	tags, err := New(server.URL, "").ListTags(context.Background())
+	if err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+	want := []TagCount{{Tag: "defi", Packages: 3}, {Tag: "governance", Packages: 1}}
+	if !slices.Equal(tags, want) {
+		t.Errorf("ListTags() = %v, want %v", tags, want)
+	}
+}
+
+func TestClient_ListMyPackages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/me/packages" {
+			t.Errorf("path = %s, want /api/v1/me/packages", r.URL.Path)
+		}
+		if r.URL.Query().Get("limit") != "50" || r.URL.Query().Get("cursor") != "c1" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"data":       []map[string]any{{"name": "token"}},
+			"pagination": map[string]any{"limit": 50},
+		})
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key")
+	resp, err := client.ListMyPackages(context.Background(), 50, "c1")
+	if err != nil {
+		t.Fatalf("ListMyPackages() error = %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0].Name != "token" {
+		t.Errorf("ListMyPackages() = %+v", resp.Data)
+	}
+}
+
 func TestClient_GetPackage(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v1/packages/my-package" {
@@ -70,6 +153,169 @@ func TestClient_GetPackage(t *testing.T) {
 	}
 }
 
+func TestClient_GetPackageVersionForSolc(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("solc"); got != "0.7.6" {
+			t.Errorf("Expected solc=0.7.6, got %q", got)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"name":            "my-package",
+			"version":         "1.0.0",
+			"compilerVersion": "0.8.28",
+			"warnings":        []string{"package was compiled with solc 0.8.28 but you are using 0.7.6"},
+		})
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "")
+	pkg, err := client.GetPackageVersionForSolc(context.Background(), "my-package", "1.0.0", "0.7.6")
+	if err != nil {
+		t.Fatalf("GetPackageVersionForSolc() error = %v", err)
+	}
+	if len(pkg.Warnings) != 1 {
+		t.Errorf("GetPackageVersionForSolc().Warnings = %v, want one warning", pkg.Warnings)
+	}
+}
+
+func TestClient_RemovePackageCollaborator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/packages/my-package/collaborators/key-2" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"name":          "my-package",
+			"collaborators": []map[string]any{{"keyId": "key-3", "name": "deployer"}},
+		})
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key")
+	resp, err := client.RemovePackageCollaborator(context.Background(), "my-package", "key-2")
+	if err != nil {
+		t.Fatalf("RemovePackageCollaborator() error = %v", err)
+	}
+	if len(resp.Collaborators) != 1 || resp.Collaborators[0].KeyID != "key-3" {
+		t.Errorf("RemovePackageCollaborator() = %+v", resp.Collaborators)
+	}
+}
+
+func TestClient_SetPackageAlias(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/packages/my-package/aliases/stable" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["version"] != "1.2.0" {
+			t.Errorf("version = %q, want 1.2.0", body["version"])
+		}
+		json.NewEncoder(w).Encode(map[string]any{"alias": "stable", "version": "1.2.0"})
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "test-key")
+	alias, err := client.SetPackageAlias(context.Background(), "my-package", "stable", "1.2.0")
+	if err != nil {
+		t.Fatalf("SetPackageAlias() error = %v", err)
+	}
+	if alias.Alias != "stable" || alias.Version != "1.2.0" {
+		t.Errorf("SetPackageAlias() = %+v", alias)
+	}
+}
+
+func TestClient_GetArtifactByType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/packages/my-package/1.0.0/contracts/Token/artifacts/devdoc" {
+			t.Errorf("Expected devdoc artifact path, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"kind":"dev"}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "")
+	content, err := client.GetArtifactByType(context.Background(), "my-package", "1.0.0", "Token", "devdoc")
+	if err != nil {
+		t.Fatalf("GetArtifactByType() error = %v", err)
+	}
+	if string(content) != `{"kind":"dev"}` {
+		t.Errorf("GetArtifactByType() = %s", content)
+	}
+}
+
+func TestClient_GetArchiveFormat(t *testing.T) {
+	var gotFormat []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/packages/my-package/1.0.0/archive" {
+			t.Errorf("Expected archive path, got %s", r.URL.Path)
+		}
+		gotFormat = append(gotFormat, r.URL.RawQuery)
+		w.Write([]byte("archive"))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "")
+	if _, err := client.GetArchive(context.Background(), "my-package", "1.0.0"); err != nil {
+		t.Fatalf("GetArchive() error = %v", err)
+	}
+	content, err := client.GetArchiveFormat(context.Background(), "my-package", "1.0.0", "zip")
+	if err != nil {
+		t.Fatalf("GetArchiveFormat() error = %v", err)
+	}
+	if string(content) != "archive" {
+		t.Errorf("GetArchiveFormat() = %s", content)
+	}
+	if len(gotFormat) != 2 || gotFormat[0] != "" || gotFormat[1] != "format=zip" {
+		t.Errorf("queries = %q, want [\"\" \"format=zip\"]", gotFormat)
+	}
+}
+
+func TestClient_GetVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		versions := []string{"1.0.0"}
+		if r.URL.Query().Get("include_prerelease") == "true" {
+			versions = append(versions, "1.1.0-beta.1")
+		}
+		json.NewEncoder(w).Encode(map[string]any{"name": "my-package", "versions": versions})
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "")
+	versions, err := client.GetVersions(context.Background(), "my-package", false)
+	if err != nil {
+		t.Fatalf("GetVersions() error = %v", err)
+	}
+	if len(versions) != 1 {
+		t.Errorf("GetVersions() returned %v, want only stable versions", versions)
+	}
+
+	versions, err = client.GetVersions(context.Background(), "my-package", true)
+	if err != nil {
+		t.Fatalf("GetVersions() error = %v", err)
+	}
+	if len(versions) != 2 {
+		t.Errorf("GetVersions(includePrerelease) returned %v, want 2 versions", versions)
+	}
+}
+
+func TestClient_GetVersionsFollowsPages(t *testing.T) {
+	pages := map[string]map[string]any{
+		"":      {"versions": []string{"3.0.0", "2.0.0"}, "pagination": map[string]any{"hasMore": true, "nextCursor": "2.0.0"}},
+		"2.0.0": {"versions": []string{"1.0.0"}, "pagination": map[string]any{"hasMore": false, "nextCursor": "1.0.0"}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(pages[r.URL.Query().Get("cursor")])
+	}))
+	defer server.Close()
+
+	versions, err := New(server.URL, "").GetVersions(context.Background(), "my-package", false)
+	if err != nil {
+		t.Fatalf("GetVersions() error = %v", err)
+	}
+	if !slices.Equal(versions, []string{"3.0.0", "2.0.0", "1.0.0"}) {
+		t.Errorf("GetVersions() = %v, want every page in order", versions)
+	}
+}
+
 func TestClient_Publish(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v1/packages/my-package/1.0.0" {
@@ -113,6 +359,61 @@ func TestClient_Publish(t *testing.T) {
 	}
 }
 
+func TestClient_PublishJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"idl":{}`) {
+			t.Errorf("Expected the body to be sent as given, got %s", body)
+		}
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]any{
+			"error": map[string]string{"code": "VERSION_EXISTS", "message": "Version already exists"},
+		})
+	}))
+	defer server.Close()
+
+	err := New(server.URL, "").PublishJSON(context.Background(), "my-package", "1.0.0", json.RawMessage(`{"chain":"solana","artifacts":[{"name":"p","idl":{}}]}`))
+	if !IsVersionExists(err) {
+		t.Errorf("PublishJSON() error = %v, want VERSION_EXISTS", err)
+	}
+}
+
+func TestClient_WithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		json.NewEncoder(w).Encode(map[string]any{"name": "my-package"})
+	}))
+	defer server.Close()
+
+	shared := &http.Client{}
+	client := New(server.URL, "", WithHTTPClient(shared), WithTimeout(20*time.Millisecond))
+	if _, err := client.GetPackage(context.Background(), "my-package"); err == nil {
+		t.Error("GetPackage() succeeded, want a timeout")
+	}
+	if shared.Timeout != 0 {
+		t.Errorf("WithTimeout modified the client given to WithHTTPClient (Timeout = %v)", shared.Timeout)
+	}
+}
+
+func TestClient_WithBearerAuth(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-API-Key")+"|"+r.Header.Get("Authorization"))
+		json.NewEncoder(w).Encode(map[string]any{"name": "my-package"})
+	}))
+	defer server.Close()
+
+	for _, client := range []*Client{New(server.URL, "cf_key_1"), New(server.URL, "cf_key_1", WithBearerAuth()), New(server.URL, "", WithBearerAuth())} {
+		if _, err := client.GetPackage(context.Background(), "my-package"); err != nil {
+			t.Fatalf("GetPackage() error = %v", err)
+		}
+	}
+	want := []string{"cf_key_1|", "cf_key_1|Bearer cf_key_1", "|"}
+	if !slices.Equal(got, want) {
+		t.Errorf("headers = %q, want %q", got, want)
+	}
+}
+
 func TestClient_GetDeployment(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v1/deployments/31337/0x1234567890abcdef1234567890abcdef12345678" {
@@ -210,3 +511,114 @@ func TestClient_ErrorHandling(t *testing.T) {
 		t.Errorf("Expected code NOT_FOUND, got %s", apiErr.Code)
 	}
 }
+
+func TestClient_PublishValidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error":{"code":"VALIDATION_ERROR","message":"Request body is invalid","fields":[{"field":"artifacts[0].name","message":"is required"}]}}`))
+	}))
+	defer server.Close()
+	c := New(server.URL, "")
+
+	// Rejected before anything is sent
+	err := c.Publish(context.Background(), "my-package", "1.0.0", PublishRequest{Artifacts: []Artifact{{Name: "Token"}, {}}})
+	if err == nil || !strings.Contains(err.Error(), "chain: is required; artifacts[1].name: is required") {
+		t.Errorf("Publish() error = %v", err)
+	}
+
+	// The server's field errors are kept on the APIError
+	req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+	var apiErr *APIError
+	if err := c.do(req, nil); !errors.As(err, &apiErr) {
+		t.Fatalf("do() error = %v, want *APIError", err)
+	}
+	if apiErr.Code != ErrorCodeValidationError || len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "artifacts[0].name" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+}
+
+func TestErrorPredicates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/packages/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"Package not found"}}`))
+		case "/api/v1/packages/taken/1.0.0":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":{"code":"VERSION_EXISTS","message":"Version already exists and is immutable"}}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":{"code":"FORBIDDEN","message":"Package owned by another user"}}`))
+		}
+	}))
+	defer server.Close()
+	c := New(server.URL, "")
+
+	_, err := c.GetPackage(context.Background(), "missing")
+	if !IsNotFound(err) || IsVersionExists(err) || IsForbidden(err) {
+		t.Errorf("GetPackage(missing) error %v: want only IsNotFound", err)
+	}
+
+	err = c.Publish(context.Background(), "taken", "1.0.0", PublishRequest{Chain: "evm", Artifacts: []Artifact{{Name: "Token"}}})
+	if !IsVersionExists(err) || IsNotFound(err) {
+		t.Errorf("Publish(taken) error %v: want only IsVersionExists", err)
+	}
+	if !HasErrorCode(fmt.Errorf("publishing: %w", err), ErrorCodeVersionExists) {
+		t.Error("HasErrorCode should see through wrapping")
+	}
+
+	err = c.DeletePackage(context.Background(), "owned", "1.0.0")
+	if !IsForbidden(err) {
+		t.Errorf("DeletePackage(owned) error %v: want IsForbidden", err)
+	}
+
+	if IsNotFound(nil) || IsNotFound(fmt.Errorf("connection refused")) {
+		t.Error("IsNotFound must be false for nil and non-API errors")
+	}
+}
+
+func TestClient_ServerErrorIncludesRequestID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":{"code":"INTERNAL_ERROR","message":"Failed to publish package","requestId":"req-123"}}`))
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL, "").GetPackage(context.Background(), "my-package")
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("Expected APIError, got %T", err)
+	}
+	if apiErr.RequestID != "req-123" || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+	if want := "INTERNAL_ERROR: Failed to publish package (request ID: req-123)"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestClient_GetLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/limits" {
+			t.Errorf("Expected path /api/v1/limits, got %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]int{
+			"maxArtifactsPerPublish": 50,
+			"maxBodySizeMB":          10,
+		})
+	}))
+	defer server.Close()
+
+	client := New(server.URL, "")
+	limits, err := client.GetLimits(context.Background())
+	if err != nil {
+		t.Fatalf("GetLimits() error = %v", err)
+	}
+	if limits.MaxArtifactsPerPublish != 50 {
+		t.Errorf("MaxArtifactsPerPublish = %d, want 50", limits.MaxArtifactsPerPublish)
+	}
+	if limits.MaxBodySizeMB != 10 {
+		t.Errorf("MaxBodySizeMB = %d, want 10", limits.MaxBodySizeMB)
+	}
+}
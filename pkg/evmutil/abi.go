@@ -0,0 +1,184 @@
+package evmutil
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// ABIEntry is one item of a contract ABI.
+type ABIEntry struct {
+	Type      string // function, event, error, constructor, fallback or receive
+	Name      string
+	Signature string // canonical signature, e.g. transfer(address,uint256)
+	// Selector identifies functions and errors (4 bytes) and events (topic 0, 32
+	// bytes) as 0x-prefixed hex. Empty for constructor, fallback and receive.
+	Selector string
+	Raw      json.RawMessage // the entry as it appears in the ABI
+}
+
+// Key identifies the entry within an ABI: entries with the same key are the same
+// callable (or event) as far as the EVM is concerned, whatever their names.
+func (e ABIEntry) Key() string {
+	if e.Selector == "" {
+		return e.Type
+	}
+	return e.Type + " " + e.Selector
+}
+
+// abiParam is an ABI input, possibly a tuple.
+type abiParam struct {
+	Type       string     `json:"type"`
+	Components []abiParam `json:"components"`
+}
+
+// ParseABI parses a JSON ABI into its entries, computing their signatures and selectors.
+func ParseABI(data []byte) ([]ABIEntry, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("ABI must be a JSON array: %w", err)
+	}
+
+	entries := make([]ABIEntry, len(raw))
+	for i, r := range raw {
+		var item struct {
+			Type   string     `json:"type"`
+			Name   string     `json:"name"`
+			Inputs []abiParam `json:"inputs"`
+		}
+		if err := json.Unmarshal(r, &item); err != nil {
+			return nil, fmt.Errorf("ABI entry %d: %w", i, err)
+		}
+		if item.Type == "" {
+			item.Type = "function" // the ABI spec's default
+		}
+
+		e := ABIEntry{Type: item.Type, Name: item.Name, Raw: r}
+		switch item.Type {
+		case "function", "error", "event":
+			e.Signature = item.Name + "(" + canonicalTypes(item.Inputs) + ")"
+			hash := Keccak256([]byte(e.Signature))
+			if item.Type != "event" {
+				hash = hash[:4]
+			}
+			e.Selector = "0x" + hex.EncodeToString(hash)
+		}
+		entries[i] = e
+	}
+	return entries, nil
+}
+
+// canonicalTypes renders parameters as a comma-separated canonical type list,
+// expanding tuples to their component types.
+func canonicalTypes(params []abiParam) string {
+	types := make([]string, len(params))
+	for i, p := range params {
+		if strings.HasPrefix(p.Type, "tuple") {
+			types[i] = "(" + canonicalTypes(p.Components) + ")" + strings.TrimPrefix(p.Type, "tuple")
+		} else {
+			types[i] = p.Type
+		}
+	}
+	return strings.Join(types, ",")
+}
+
+// Keccak256 returns the Keccak-256 hash of data as used by the EVM.
+func Keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// NamedABI is a contract's ABI to merge.
+type NamedABI struct {
+	Contract string
+	ABI      json.RawMessage
+}
+
+// ABICollision is a selector claimed by entries with different signatures in the
+// merged contracts, e.g. a proxy admin function clashing with an implementation
+// function. Only the first entry is kept in the merged ABI.
+type ABICollision struct {
+	Type     string
+	Selector string
+	Entries  []ABICollisionEntry
+}
+
+// ABICollisionEntry is one of the colliding entries.
+type ABICollisionEntry struct {
+	Contract  string
+	Signature string
+}
+
+// String describes the collision, e.g.
+// "function 0x42966c68: Impl.burn(uint256), Proxy.collate_propagate_storage(bytes16)".
+func (c ABICollision) String() string {
+	entries := make([]string, len(c.Entries))
+	for i, e := range c.Entries {
+		entries[i] = e.Contract + "." + e.Signature
+	}
+	return c.Type + " " + c.Selector + ": " + strings.Join(entries, ", ")
+}
+
+// MergedABI is the outcome of MergeABIs.
+type MergedABI struct {
+	ABI        json.RawMessage // a JSON ABI array
+	Collisions []ABICollision
+}
+
+// MergeABIs combines ABIs into one, e.g. a proxy's and its implementation's for
+// tools calling through the proxy. Entries are deduplicated by Key: the first
+// contract to declare a selector wins, and declarations of it with a different
+// signature are reported as collisions. Entry order follows the inputs.
+func MergeABIs(abis []NamedABI) (*MergedABI, error) {
+	var merged []json.RawMessage
+	kept := make(map[string]ABIEntry)
+	keptBy := make(map[string]string)
+	collisions := make(map[string]int) // key -> index in result.Collisions
+
+	result := &MergedABI{}
+	for _, a := range abis {
+		entries, err := ParseABI(a.ABI)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a.Contract, err)
+		}
+		for _, e := range entries {
+			key := e.Key()
+			first, seen := kept[key]
+			if !seen {
+				kept[key] = e
+				keptBy[key] = a.Contract
+				merged = append(merged, e.Raw)
+				continue
+			}
+			if e.Signature == first.Signature {
+				continue
+			}
+
+			i, ok := collisions[key]
+			if !ok {
+				i = len(result.Collisions)
+				collisions[key] = i
+				result.Collisions = append(result.Collisions, ABICollision{
+					Type:     e.Type,
+					Selector: e.Selector,
+					Entries:  []ABICollisionEntry{{Contract: keptBy[key], Signature: first.Signature}},
+				})
+			}
+			result.Collisions[i].Entries = append(result.Collisions[i].Entries, ABICollisionEntry{Contract: a.Contract, Signature: e.Signature})
+		}
+	}
+
+	if merged == nil {
+		merged = []json.RawMessage{}
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	result.ABI = data
+	return result, nil
+}
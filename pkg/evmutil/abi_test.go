@@ -0,0 +1,100 @@
+package evmutil
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseABI(t *testing.T) {
+	abi := `[
+		{"type":"function","name":"transfer","inputs":[{"type":"address"},{"type":"uint256"}]},
+		{"name":"f","inputs":[{"type":"tuple[]","components":[{"type":"address"},{"type":"uint256"}]}]},
+		{"type":"event","name":"Transfer","inputs":[{"type":"address","indexed":true},{"type":"address","indexed":true},{"type":"uint256"}]},
+		{"type":"error","name":"InsufficientBalance","inputs":[{"type":"uint256"}]},
+		{"type":"constructor","inputs":[]}
+	]`
+	entries, err := ParseABI([]byte(abi))
+	if err != nil {
+		t.Fatalf("ParseABI() error = %v", err)
+	}
+
+	want := []struct{ typ, signature, selector string }{
+		{"function", "transfer(address,uint256)", "0xa9059cbb"},
+		{"function", "f((address,uint256)[])", ""},
+		{"event", "Transfer(address,address,uint256)", "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+		{"error", "InsufficientBalance(uint256)", ""},
+		{"constructor", "", ""},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("ParseABI() returned %d entries, want %d", len(entries), len(want))
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Type != w.typ || e.Signature != w.signature {
+			t.Errorf("entry %d = %s %s, want %s %s", i, e.Type, e.Signature, w.typ, w.signature)
+		}
+		if w.selector != "" && e.Selector != w.selector {
+			t.Errorf("entry %d selector = %s, want %s", i, e.Selector, w.selector)
+		}
+	}
+	if entries[4].Key() != "constructor" {
+		t.Errorf("constructor key = %q", entries[4].Key())
+	}
+
+	if _, err := ParseABI([]byte(`{"type":"function"}`)); err == nil {
+		t.Error("ParseABI(object) should fail")
+	}
+}
+
+func TestMergeABIs(t *testing.T) {
+	impl := `[
+		{"type":"constructor","inputs":[{"type":"uint256"}]},
+		{"type":"function","name":"burn","inputs":[{"type":"uint256"}]},
+		{"type":"function","name":"upgradeTo","inputs":[{"type":"address"}]}
+	]`
+	proxy := `[
+		{"type":"constructor","inputs":[{"type":"address"}]},
+		{"type":"function","name":"upgradeTo","inputs":[{"name":"newImplementation","type":"address"}]},
+		{"type":"function","name":"collate_propagate_storage","inputs":[{"type":"bytes16"}]},
+		{"type":"event","name":"Upgraded","inputs":[{"type":"address","indexed":true}]}
+	]`
+
+	merged, err := MergeABIs([]NamedABI{{Contract: "Impl", ABI: json.RawMessage(impl)}, {Contract: "Proxy", ABI: json.RawMessage(proxy)}})
+	if err != nil {
+		t.Fatalf("MergeABIs() error = %v", err)
+	}
+
+	entries, err := ParseABI(merged.ABI)
+	if err != nil {
+		t.Fatalf("merged ABI is not valid: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Type+" "+e.Signature)
+	}
+	want := []string{
+		"constructor ", // the first contract's
+		"function burn(uint256)",
+		"function upgradeTo(address)", // declared by both, kept once
+		"event Upgraded(address)",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("merged entries = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("merged entry %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if len(merged.Collisions) != 1 {
+		t.Fatalf("collisions = %v, want 1", merged.Collisions)
+	}
+	if c := merged.Collisions[0].String(); c != "function 0x42966c68: Impl.burn(uint256), Proxy.collate_propagate_storage(bytes16)" {
+		t.Errorf("collision = %s", c)
+	}
+
+	if _, err := MergeABIs([]NamedABI{{Contract: "Bad", ABI: json.RawMessage(`nope`)}}); err == nil {
+		t.Error("MergeABIs() should reject malformed ABIs")
+	}
+}
@@ -0,0 +1,174 @@
+// Package evmutil provides EVM bytecode helpers shared by the Contrafactory server
+// and CLI, so that both compare bytecode the same way.
+package evmutil
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Match types reported by CompareBytecode
+const (
+	MatchFull    = "full"    // identical, including metadata
+	MatchPartial = "partial" // identical once the CBOR metadata is stripped
+	MatchNone    = "none"
+	MatchClone   = "clone" // an EIP-1167 minimal proxy; compare its implementation instead
+)
+
+// Comparison is the outcome of CompareBytecode.
+type Comparison struct {
+	Match     bool
+	MatchType string // MatchFull, MatchPartial, MatchNone or MatchClone
+	Message   string
+
+	// Implementation is the address a MatchClone deployment delegates to
+	Implementation string
+
+	// Where the bytecodes diverge, for debugging mismatches. For a partial match the
+	// divergence lies at or after MetadataOffset; for no match it's in the code itself.
+	DivergenceOffset int // first differing byte (after library linking); -1 when identical
+	MetadataOffset   int // start of the deployed code's CBOR metadata; -1 when it has none
+	MetadataLength   int // bytes of metadata stripped from the deployed code
+}
+
+// CBOR metadata marker (Solidity >=0.6.0) - "ipfs" in CBOR
+var metadataMarker = []byte{0xa2, 0x64, 0x69, 0x70, 0x66, 0x73}
+
+// Library placeholder pattern: __$<34 hex chars>$__
+var libraryPlaceholder = regexp.MustCompile(`__\$[a-f0-9]{34}\$__`)
+
+// StripMetadata removes the CBOR metadata appended to bytecode. The metadata starts
+// at the marker and ends with its 2-byte length, so everything from the marker on goes.
+func StripMetadata(bytecode []byte) []byte {
+	// Find last occurrence of metadata marker
+	idx := bytes.LastIndex(bytecode, metadataMarker)
+	if idx == -1 {
+		return bytecode // No metadata found
+	}
+	return bytecode[:idx]
+}
+
+// DecodeHex decodes hex bytecode as found in files and API responses: surrounding
+// whitespace and a 0x prefix are allowed.
+func DecodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "0x")
+	code, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex bytecode: %w", err)
+	}
+	return code, nil
+}
+
+// CompareBytecode compares deployed bytecode to artifact bytecode. The artifact may be
+// raw bytes or 0x-prefixed hex; library placeholders in it are replaced with the
+// addresses in libraries before comparing.
+func CompareBytecode(deployed, artifact []byte, libraries map[string]string) Comparison {
+	// Handle hex-encoded bytecode
+	if len(artifact) > 2 && artifact[0] == '0' && artifact[1] == 'x' {
+		decoded, err := hex.DecodeString(string(artifact[2:]))
+		if err == nil {
+			artifact = decoded
+		}
+	}
+
+	// Substitute library placeholders if present
+	if len(libraries) > 0 {
+		artifact = substituteLibraries(artifact, libraries)
+	}
+
+	// Try exact match first
+	if bytes.Equal(deployed, artifact) {
+		return Comparison{
+			Match:            true,
+			MatchType:        MatchFull,
+			Message:          "Bytecode matches exactly including metadata",
+			DivergenceOffset: -1,
+			MetadataOffset:   -1,
+		}
+	}
+
+	// Strip metadata and compare
+	deployedStripped := StripMetadata(deployed)
+	artifactStripped := StripMetadata(artifact)
+
+	c := Comparison{
+		DivergenceOffset: firstDifference(deployed, artifact),
+		MetadataOffset:   -1,
+	}
+	if len(deployedStripped) < len(deployed) {
+		c.MetadataOffset = len(deployedStripped)
+		c.MetadataLength = len(deployed) - len(deployedStripped)
+	}
+
+	if bytes.Equal(deployedStripped, artifactStripped) {
+		c.Match = true
+		c.MatchType = MatchPartial
+		c.Message = "Executable code matches, metadata differs (different source paths, comments, or build environment)"
+		return c
+	}
+
+	// A minimal proxy never matches the artifact; point at the implementation it runs
+	if impl, ok := MinimalProxyImplementation(deployed); ok {
+		c.MatchType = MatchClone
+		c.Implementation = impl
+		c.Message = "Deployed code is an EIP-1167 minimal proxy delegating to " + impl
+		return c
+	}
+
+	// No match
+	c.MatchType = MatchNone
+	c.Message = "Bytecode does not match"
+	return c
+}
+
+// firstDifference returns the index of the first byte where a and b differ, which is
+// the shorter length when one is a prefix of the other, or -1 when they are equal.
+func firstDifference(a, b []byte) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	if len(a) == len(b) {
+		return -1
+	}
+	return n
+}
+
+// substituteLibraries replaces library placeholders with actual addresses
+func substituteLibraries(bytecode []byte, libraries map[string]string) []byte {
+	bytecodeHex := hex.EncodeToString(bytecode)
+
+	for _, addr := range libraries {
+		// Remove 0x prefix from address if present
+		addr = strings.TrimPrefix(addr, "0x")
+		addr = strings.ToLower(addr)
+
+		// The placeholder is the first 17 bytes of keccak256(fullyQualifiedName)
+		// Format: __$<34 hex chars>$__
+		// For simplicity, we'll try to find any placeholder and replace with the address
+		// In practice, you'd hash the library name to find the exact placeholder
+
+		// Simplified approach: replace any placeholder with the address
+		// Real implementation would compute keccak256(name)[:17] to match specific placeholders
+		if libraryPlaceholder.MatchString(bytecodeHex) {
+			// Replace placeholder with address (padded to 40 chars)
+			bytecodeHex = libraryPlaceholder.ReplaceAllStringFunc(bytecodeHex, func(match string) string {
+				return addr
+			})
+		}
+	}
+
+	result, _ := hex.DecodeString(bytecodeHex)
+	return result
+}
+
+// HasLibraryPlaceholders checks if bytecode contains library placeholders
+func HasLibraryPlaceholders(bytecode []byte) bool {
+	return libraryPlaceholder.Match(bytecode) ||
+		libraryPlaceholder.MatchString(string(bytecode))
+}
@@ -0,0 +1,75 @@
+package evmutil
+
+import (
+	"bytes"
+	"testing"
+)
+
+// withMetadata appends a minimal "ipfs" CBOR section (marker plus a fake hash) to code.
+func withMetadata(code []byte, hash byte) []byte {
+	out := append([]byte{}, code...)
+	out = append(out, 0xa2, 0x64, 0x69, 0x70, 0x66, 0x73, 0x58, 0x22, hash)
+	return append(out, 0x00, 0x09)
+}
+
+func TestStripMetadata(t *testing.T) {
+	code := []byte{0x60, 0x80, 0x60, 0x40}
+	if got := StripMetadata(withMetadata(code, 1)); !bytes.Equal(got, code) {
+		t.Errorf("StripMetadata() = %x, want %x", got, code)
+	}
+	if got := StripMetadata(code); !bytes.Equal(got, code) {
+		t.Errorf("StripMetadata() without metadata = %x, want %x", got, code)
+	}
+}
+
+func TestCompareBytecode(t *testing.T) {
+	code := []byte{0x60, 0x80, 0x60, 0x40}
+
+	tests := []struct {
+		name           string
+		deployed       []byte
+		artifact       []byte
+		wantMatch      bool
+		wantType       string
+		wantDivergence int
+		wantMetaOffset int
+		wantMetaLength int
+	}{
+		{"exact", withMetadata(code, 1), withMetadata(code, 1), true, MatchFull, -1, -1, 0},
+		{"hex artifact", code, []byte("0x60806040"), true, MatchFull, -1, -1, 0},
+		{"metadata differs", withMetadata(code, 1), withMetadata(code, 2), true, MatchPartial, 12, 4, 11},
+		{"code differs", withMetadata(code, 1), withMetadata([]byte{0x60, 0x80, 0x60, 0x50}, 1), false, MatchNone, 3, 4, 11},
+		{"artifact is a prefix", code, code[:3], false, MatchNone, 3, -1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareBytecode(tt.deployed, tt.artifact, nil)
+			if got.Match != tt.wantMatch || got.MatchType != tt.wantType {
+				t.Errorf("CompareBytecode() = %v/%s, want %v/%s", got.Match, got.MatchType, tt.wantMatch, tt.wantType)
+			}
+			if got.DivergenceOffset != tt.wantDivergence {
+				t.Errorf("DivergenceOffset = %d, want %d", got.DivergenceOffset, tt.wantDivergence)
+			}
+			if got.MetadataOffset != tt.wantMetaOffset || got.MetadataLength != tt.wantMetaLength {
+				t.Errorf("metadata = %d+%d, want %d+%d", got.MetadataOffset, got.MetadataLength, tt.wantMetaOffset, tt.wantMetaLength)
+			}
+		})
+	}
+}
+
+func TestDecodeHex(t *testing.T) {
+	for _, in := range []string{"0x60806040", "60806040", "  0x60806040\n"} {
+		got, err := DecodeHex(in)
+		if err != nil {
+			t.Fatalf("DecodeHex(%q) error: %v", in, err)
+		}
+		if !bytes.Equal(got, []byte{0x60, 0x80, 0x60, 0x40}) {
+			t.Errorf("DecodeHex(%q) = %x", in, got)
+		}
+	}
+
+	if _, err := DecodeHex("0xzz"); err == nil {
+		t.Error("DecodeHex(0xzz) expected error")
+	}
+}
@@ -0,0 +1,243 @@
+package evmutil
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Metadata hash types reported by BytecodeMetadata.HashType, following solc's
+// bytecodeHash setting
+const (
+	HashIPFS  = "ipfs"
+	HashBzzr0 = "bzzr0" // legacy Swarm (solc < 0.6.0)
+	HashBzzr1 = "bzzr1"
+	HashNone  = "none"
+)
+
+// BytecodeMetadata is the CBOR map solc appends to bytecode. Only the hash
+// matching the compiler's bytecodeHash setting is present.
+type BytecodeMetadata struct {
+	IPFS         []byte // multihash (0x1220 + sha256) of the metadata JSON
+	Bzzr0        []byte // legacy Swarm hash (solc < 0.6.0)
+	Bzzr1        []byte // Swarm hash
+	Solc         string // compiler version, e.g. "0.8.28"; empty before solc 0.5.9
+	Experimental bool
+	Length       int // size of the trailing section, CBOR plus its 2-byte length
+}
+
+// HashType returns which metadata hash the bytecode embeds.
+func (m *BytecodeMetadata) HashType() string {
+	switch {
+	case len(m.IPFS) > 0:
+		return HashIPFS
+	case len(m.Bzzr1) > 0:
+		return HashBzzr1
+	case len(m.Bzzr0) > 0:
+		return HashBzzr0
+	default:
+		return HashNone
+	}
+}
+
+// Hash returns the embedded metadata hash, or nil when compiled with bytecodeHash none.
+func (m *BytecodeMetadata) Hash() []byte {
+	switch m.HashType() {
+	case HashIPFS:
+		return m.IPFS
+	case HashBzzr1:
+		return m.Bzzr1
+	case HashBzzr0:
+		return m.Bzzr0
+	default:
+		return nil
+	}
+}
+
+// ParseMetadataCBOR decodes the CBOR metadata solc appends to bytecode, which may
+// be raw bytes or a 0x-prefixed hex string.
+func ParseMetadataCBOR(bytecode []byte) (*BytecodeMetadata, error) {
+	code, err := decodeBytecode(bytecode)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) < 2 {
+		return nil, errors.New("bytecode too short for metadata")
+	}
+
+	cborLen := int(binary.BigEndian.Uint16(code[len(code)-2:]))
+	if cborLen == 0 || cborLen > len(code)-2 {
+		return nil, errors.New("no CBOR metadata found")
+	}
+	cbor := code[len(code)-2-cborLen : len(code)-2]
+
+	d := &cborDecoder{data: cbor}
+	major, n, err := d.head()
+	if err != nil || major != cborMap {
+		return nil, errors.New("no CBOR metadata found")
+	}
+
+	meta := &BytecodeMetadata{Length: cborLen + 2}
+	for i := uint64(0); i < n; i++ {
+		key, err := d.text()
+		if err != nil {
+			return nil, fmt.Errorf("decoding metadata key: %w", err)
+		}
+		switch key {
+		case "ipfs":
+			meta.IPFS, err = d.bytes()
+		case "bzzr0":
+			meta.Bzzr0, err = d.bytes()
+		case "bzzr1":
+			meta.Bzzr1, err = d.bytes()
+		case "solc":
+			meta.Solc, err = d.solcVersion()
+		case "experimental":
+			meta.Experimental, err = d.boolean()
+		default:
+			err = d.skip()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding metadata %q: %w", key, err)
+		}
+	}
+	if d.pos != len(cbor) {
+		return nil, errors.New("trailing data after CBOR metadata")
+	}
+	return meta, nil
+}
+
+func decodeBytecode(bytecode []byte) ([]byte, error) {
+	s := strings.TrimSpace(string(bytecode))
+	if !strings.HasPrefix(s, "0x") {
+		return bytecode, nil
+	}
+	code, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex bytecode: %w", err)
+	}
+	return code, nil
+}
+
+// CBOR major types used by solc metadata
+const (
+	cborUint   = 0
+	cborBytes  = 2
+	cborText   = 3
+	cborMap    = 5
+	cborSimple = 7
+)
+
+// cborDecoder reads the small CBOR subset solc emits: a map of text keys to byte
+// strings, text strings and booleans.
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) head() (major byte, arg uint64, err error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, errors.New("unexpected end of CBOR")
+	}
+	b := d.data[d.pos]
+	d.pos++
+	major, info := b>>5, b&0x1f
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info == 24 && d.pos+1 <= len(d.data):
+		arg = uint64(d.data[d.pos])
+		d.pos++
+	case info == 25 && d.pos+2 <= len(d.data):
+		arg = uint64(binary.BigEndian.Uint16(d.data[d.pos:]))
+		d.pos += 2
+	case info == 26 && d.pos+4 <= len(d.data):
+		arg = uint64(binary.BigEndian.Uint32(d.data[d.pos:]))
+		d.pos += 4
+	default:
+		return 0, 0, errors.New("unsupported CBOR encoding")
+	}
+	return major, arg, nil
+}
+
+func (d *cborDecoder) payload(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errors.New("unexpected end of CBOR")
+	}
+	p := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return p, nil
+}
+
+func (d *cborDecoder) text() (string, error) {
+	major, n, err := d.head()
+	if err != nil {
+		return "", err
+	}
+	if major != cborText {
+		return "", errors.New("expected text string")
+	}
+	p, err := d.payload(n)
+	return string(p), err
+}
+
+func (d *cborDecoder) bytes() ([]byte, error) {
+	major, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	if major != cborBytes {
+		return nil, errors.New("expected byte string")
+	}
+	return d.payload(n)
+}
+
+// solcVersion reads the solc entry: 3 bytes (major, minor, patch) for releases,
+// a full version string for prerelease builds.
+func (d *cborDecoder) solcVersion() (string, error) {
+	major, n, err := d.head()
+	if err != nil {
+		return "", err
+	}
+	p, err := d.payload(n)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case major == cborBytes && len(p) == 3:
+		return fmt.Sprintf("%d.%d.%d", p[0], p[1], p[2]), nil
+	case major == cborText:
+		return string(p), nil
+	default:
+		return "", errors.New("unexpected solc version encoding")
+	}
+}
+
+func (d *cborDecoder) boolean() (bool, error) {
+	major, v, err := d.head()
+	if err != nil {
+		return false, err
+	}
+	if major != cborSimple || (v != 20 && v != 21) {
+		return false, errors.New("expected boolean")
+	}
+	return v == 21, nil
+}
+
+func (d *cborDecoder) skip() error {
+	major, n, err := d.head()
+	if err != nil {
+		return err
+	}
+	switch major {
+	case cborUint, cborSimple:
+		return nil
+	case cborBytes, cborText:
+		_, err := d.payload(n)
+		return err
+	default:
+		return errors.New("unsupported CBOR value")
+	}
+}
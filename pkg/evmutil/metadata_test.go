@@ -0,0 +1,91 @@
+package evmutil
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Runtime code tails as solc emits them: the end of the contract's code followed by
+// the CBOR metadata map and its 2-byte length.
+const (
+	// solc 0.8.20, default settings: {"ipfs": multihash, "solc": 0.8.20}
+	tailSolc0820 = "fea2646970667358221220" +
+		"8b2e5d6ca1e6e0bd8f9fa2e9a1a0d4d2f3e6b1d6a1c5b3f9d7e2c4a6b8d0e2f4" +
+		"64736f6c63430008140033"
+	// solc 0.4.19 (WETH9 era): {"bzzr0": swarm hash}, no compiler version
+	tailSolc0419 = "00a165627a7a72305820" +
+		"deb4c2ccab3c2fdca32ab3f46728389c2fe2c165d5fafa07661e4e004f6c344a" +
+		"0029"
+	// solc 0.5.17 with experimental features: {"bzzr1": hash, "experimental": true, "solc": 0.5.17}
+	tailSolc0517Experimental = "fea365627a7a72315820" +
+		"0f5a3e1b5d1c1b0e5a27c6d5a1c0c7b2c8a7a1e2f7d3c6b9a8e5d4c3b2a1f0e9" +
+		"6c6578706572696d656e74616cf564736f6c63430005110040"
+	// solc 0.8.28 with bytecodeHash none: {"solc": 0.8.28}
+	tailSolc0828NoHash = "fea164736f6c634300081c000a"
+)
+
+func TestParseMetadataCBOR(t *testing.T) {
+	tests := []struct {
+		name             string
+		bytecode         string
+		wantSolc         string
+		wantHashType     string
+		wantHash         string
+		wantExperimental bool
+		wantLength       int
+	}{
+		{"ipfs", "0x6080604052348015600e575f80fd5b50" + tailSolc0820, "0.8.20", HashIPFS,
+			"12208b2e5d6ca1e6e0bd8f9fa2e9a1a0d4d2f3e6b1d6a1c5b3f9d7e2c4a6b8d0e2f4", false, 53},
+		{"bzzr0 without version", "0x606060405260043610" + tailSolc0419, "", HashBzzr0,
+			"deb4c2ccab3c2fdca32ab3f46728389c2fe2c165d5fafa07661e4e004f6c344a", false, 43},
+		{"bzzr1 experimental", "0x6080604052" + tailSolc0517Experimental, "0.5.17", HashBzzr1,
+			"0f5a3e1b5d1c1b0e5a27c6d5a1c0c7b2c8a7a1e2f7d3c6b9a8e5d4c3b2a1f0e9", true, 66},
+		{"no hash", "0x6080604052" + tailSolc0828NoHash, "0.8.28", HashNone, "", false, 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, err := ParseMetadataCBOR([]byte(tt.bytecode))
+			if err != nil {
+				t.Fatalf("ParseMetadataCBOR() error = %v", err)
+			}
+			if meta.Solc != tt.wantSolc {
+				t.Errorf("Solc = %q, want %q", meta.Solc, tt.wantSolc)
+			}
+			if meta.HashType() != tt.wantHashType {
+				t.Errorf("HashType() = %q, want %q", meta.HashType(), tt.wantHashType)
+			}
+			if got := hex.EncodeToString(meta.Hash()); got != tt.wantHash {
+				t.Errorf("Hash() = %s, want %s", got, tt.wantHash)
+			}
+			if meta.Experimental != tt.wantExperimental {
+				t.Errorf("Experimental = %v, want %v", meta.Experimental, tt.wantExperimental)
+			}
+			if meta.Length != tt.wantLength {
+				t.Errorf("Length = %d, want %d", meta.Length, tt.wantLength)
+			}
+		})
+	}
+}
+
+func TestParseMetadataCBOR_RawBytes(t *testing.T) {
+	code, err := hex.DecodeString("6080604052" + tailSolc0820)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, err := ParseMetadataCBOR(code)
+	if err != nil {
+		t.Fatalf("ParseMetadataCBOR() error = %v", err)
+	}
+	if meta.Solc != "0.8.20" {
+		t.Errorf("Solc = %q, want 0.8.20", meta.Solc)
+	}
+}
+
+func TestParseMetadataCBOR_NoMetadata(t *testing.T) {
+	for _, bytecode := range []string{"0x6080604052600080fd", "0x", "0x6080604052600080fd0002"} {
+		if _, err := ParseMetadataCBOR([]byte(bytecode)); err == nil {
+			t.Errorf("ParseMetadataCBOR(%s) expected an error", bytecode)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package evmutil
+
+import (
+	"bytes"
+	"encoding/hex"
+)
+
+// EIP-1167 minimal proxy runtime code: the prefix, the 20-byte implementation
+// address, then the suffix.
+var (
+	minimalProxyPrefix = []byte{0x36, 0x3d, 0x3d, 0x37, 0x3d, 0x3d, 0x3d, 0x36, 0x3d, 0x73}
+	minimalProxySuffix = []byte{0x5a, 0xf4, 0x3d, 0x82, 0x80, 0x3e, 0x90, 0x3d, 0x91, 0x60, 0x2b, 0x57, 0xfd, 0x5b, 0xf3}
+)
+
+// MinimalProxyImplementation reports whether code is the runtime code of an EIP-1167
+// minimal proxy (a "clone"), and if so returns the 0x-prefixed lowercase address of
+// the implementation it delegates to.
+func MinimalProxyImplementation(code []byte) (string, bool) {
+	if len(code) != len(minimalProxyPrefix)+20+len(minimalProxySuffix) ||
+		!bytes.HasPrefix(code, minimalProxyPrefix) || !bytes.HasSuffix(code, minimalProxySuffix) {
+		return "", false
+	}
+	addr := code[len(minimalProxyPrefix) : len(minimalProxyPrefix)+20]
+	return "0x" + hex.EncodeToString(addr), true
+}
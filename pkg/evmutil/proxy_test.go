@@ -0,0 +1,49 @@
+package evmutil
+
+import "testing"
+
+func TestMinimalProxyImplementation(t *testing.T) {
+	const impl = "bebebebebebebebebebebebebebebebebebebebe"
+
+	tests := []struct {
+		name     string
+		code     string
+		wantImpl string
+		wantOK   bool
+	}{
+		{"clone", "363d3d373d3d3d363d73" + impl + "5af43d82803e903d91602b57fd5bf3", "0x" + impl, true},
+		{"truncated", "363d3d373d3d3d363d73" + impl + "5af43d82803e903d91602b57fd5b", "", false},
+		{"other code", "6080604052348015600f57600080fd5b50", "", false},
+		{"empty", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := DecodeHex(tt.code)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotImpl, gotOK := MinimalProxyImplementation(code)
+			if gotImpl != tt.wantImpl || gotOK != tt.wantOK {
+				t.Errorf("MinimalProxyImplementation() = %q, %v, want %q, %v", gotImpl, gotOK, tt.wantImpl, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCompareBytecodeClone(t *testing.T) {
+	clone, _ := DecodeHex("363d3d373d3d3d363d73bebebebebebebebebebebebebebebebebebebebe5af43d82803e903d91602b57fd5bf3")
+
+	got := CompareBytecode(clone, []byte("0x6080604052"), nil)
+	if got.Match || got.MatchType != MatchClone {
+		t.Errorf("CompareBytecode() = %v/%s, want false/%s", got.Match, got.MatchType, MatchClone)
+	}
+	if got.Implementation != "0xbebebebebebebebebebebebebebebebebebebebe" {
+		t.Errorf("Implementation = %q", got.Implementation)
+	}
+
+	// A clone artifact compared with itself is still a full match
+	if got := CompareBytecode(clone, clone, nil); got.MatchType != MatchFull {
+		t.Errorf("CompareBytecode(clone, clone) = %s, want %s", got.MatchType, MatchFull)
+	}
+}
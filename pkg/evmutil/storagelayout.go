@@ -0,0 +1,214 @@
+package evmutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Kinds of storage layout change reported by DiffStorageLayouts
+const (
+	ChangeAppended    = "appended"     // new variable after all old storage (safe)
+	ChangeGapConsumed = "gap-consumed" // new variable placed in a former __gap (safe)
+	ChangeGapResized  = "gap-resized"  // __gap shrunk to make room, same end slot (safe)
+	ChangeRenamed     = "renamed"      // same slot, offset and type under a new name (safe)
+	ChangeTypeChanged = "type-changed" // different type at the same slot and offset
+	ChangeMoved       = "moved"        // variable now lives at a different slot or offset
+	ChangeRemoved     = "removed"      // variable no longer present
+	ChangeInserted    = "inserted"     // new variable overlapping existing storage
+)
+
+// StorageLayout is the solc storageLayout output.
+type StorageLayout struct {
+	Storage []StorageVariable      `json:"storage"`
+	Types   map[string]StorageType `json:"types"`
+}
+
+// StorageVariable is one state variable in a storage layout.
+type StorageVariable struct {
+	Contract string `json:"contract"`
+	Label    string `json:"label"`
+	Offset   int    `json:"offset"`
+	Slot     string `json:"slot"`
+	Type     string `json:"type"`
+}
+
+// StorageType describes a type referenced by StorageVariable.Type.
+type StorageType struct {
+	Encoding      string `json:"encoding"`
+	Label         string `json:"label"`
+	NumberOfBytes string `json:"numberOfBytes"`
+}
+
+// StorageChange is one difference between two storage layouts.
+type StorageChange struct {
+	Kind    string
+	Label   string
+	Safe    bool
+	Message string
+}
+
+// StorageDiff is the outcome of DiffStorageLayouts.
+type StorageDiff struct {
+	Changes []StorageChange
+	Safe    bool // true when every change is safe for a proxy upgrade
+}
+
+// ParseStorageLayout parses a solc storageLayout artifact.
+func ParseStorageLayout(data []byte) (*StorageLayout, error) {
+	var layout StorageLayout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("parsing storage layout: %w", err)
+	}
+	return &layout, nil
+}
+
+// position is where a variable lives in storage, in bytes from slot 0.
+type position struct {
+	start *big.Int
+	size  *big.Int
+}
+
+func (p position) end() *big.Int {
+	return new(big.Int).Add(p.start, p.size)
+}
+
+func (l *StorageLayout) position(v StorageVariable) (position, error) {
+	slot, ok := new(big.Int).SetString(v.Slot, 10)
+	if !ok {
+		return position{}, fmt.Errorf("variable %s: invalid slot %q", v.Label, v.Slot)
+	}
+	start := new(big.Int).Mul(slot, big.NewInt(32))
+	start.Add(start, big.NewInt(int64(v.Offset)))
+	size := big.NewInt(32)
+	if n, err := strconv.ParseInt(l.Types[v.Type].NumberOfBytes, 10, 64); err == nil {
+		size = big.NewInt(n)
+	}
+	return position{start: start, size: size}, nil
+}
+
+// typeLabel returns the human-readable type of v ("uint256", "mapping(address => uint256)"),
+// which unlike the type ID doesn't embed AST IDs that change between compilations.
+func (l *StorageLayout) typeLabel(v StorageVariable) string {
+	if t, ok := l.Types[v.Type]; ok && t.Label != "" {
+		return t.Label
+	}
+	return v.Type
+}
+
+func isGap(label string) bool {
+	return strings.HasPrefix(label, "__gap")
+}
+
+// DiffStorageLayouts compares the storage layout of an upgraded implementation (newer)
+// with the one currently behind the proxy (older) and reports every change, flagging
+// those that would corrupt existing storage. New variables are safe when they are
+// appended after all existing storage or take space freed by shrinking a __gap.
+func DiffStorageLayouts(older, newer *StorageLayout) (*StorageDiff, error) {
+	diff := &StorageDiff{Safe: true}
+	add := func(c StorageChange) {
+		diff.Changes = append(diff.Changes, c)
+		if !c.Safe {
+			diff.Safe = false
+		}
+	}
+
+	oldPos := make([]position, len(older.Storage))
+	oldEnd := new(big.Int)
+	for i, v := range older.Storage {
+		p, err := older.position(v)
+		if err != nil {
+			return nil, err
+		}
+		oldPos[i] = p
+		if p.end().Cmp(oldEnd) > 0 {
+			oldEnd = p.end()
+		}
+	}
+
+	newPos := make([]position, len(newer.Storage))
+	newAt := make(map[string]int, len(newer.Storage))
+	newByLabel := make(map[string]int, len(newer.Storage))
+	for i, v := range newer.Storage {
+		p, err := newer.position(v)
+		if err != nil {
+			return nil, err
+		}
+		newPos[i] = p
+		newAt[p.start.String()] = i
+		newByLabel[v.Label] = i
+	}
+
+	matched := make(map[int]bool, len(newer.Storage))
+	for i, v := range older.Storage {
+		oldType := older.typeLabel(v)
+
+		// Something else now at this position is only a rename or retype when the old
+		// variable is gone; otherwise the variables were reordered and it's reported as moved
+		_, stillPresent := newByLabel[v.Label]
+		if j, ok := newAt[oldPos[i].start.String()]; ok && (newer.Storage[j].Label == v.Label || !stillPresent) {
+			matched[j] = true
+			nv := newer.Storage[j]
+			newType := newer.typeLabel(nv)
+			switch {
+			case newType != oldType:
+				add(StorageChange{Kind: ChangeTypeChanged, Label: v.Label, Message: fmt.Sprintf(
+					"%s: type changed from %s to %s at slot %s", v.Label, oldType, newType, v.Slot)})
+			case nv.Label != v.Label:
+				add(StorageChange{Kind: ChangeRenamed, Label: v.Label, Safe: true, Message: fmt.Sprintf(
+					"%s renamed to %s at slot %s", v.Label, nv.Label, v.Slot)})
+			}
+			continue
+		}
+
+		j, ok := newByLabel[v.Label]
+		if !ok {
+			add(StorageChange{Kind: ChangeRemoved, Label: v.Label, Message: fmt.Sprintf(
+				"%s (%s) at slot %s was removed", v.Label, oldType, v.Slot)})
+			continue
+		}
+		matched[j] = true
+		nv := newer.Storage[j]
+
+		// A shrunk gap that still ends where it did leaves every later variable in place
+		if isGap(v.Label) && newPos[j].end().Cmp(oldPos[i].end()) == 0 {
+			add(StorageChange{Kind: ChangeGapResized, Label: v.Label, Safe: true, Message: fmt.Sprintf(
+				"%s resized from %s to %s", v.Label, oldType, newer.typeLabel(nv))})
+			continue
+		}
+		add(StorageChange{Kind: ChangeMoved, Label: v.Label, Message: fmt.Sprintf(
+			"%s moved from slot %s offset %d to slot %s offset %d", v.Label, v.Slot, v.Offset, nv.Slot, nv.Offset)})
+	}
+
+	for j, nv := range newer.Storage {
+		if matched[j] {
+			continue
+		}
+		p := newPos[j]
+		switch {
+		case p.start.Cmp(oldEnd) >= 0:
+			add(StorageChange{Kind: ChangeAppended, Label: nv.Label, Safe: true, Message: fmt.Sprintf(
+				"%s (%s) appended at slot %s", nv.Label, newer.typeLabel(nv), nv.Slot)})
+		case withinGap(older, oldPos, p):
+			add(StorageChange{Kind: ChangeGapConsumed, Label: nv.Label, Safe: true, Message: fmt.Sprintf(
+				"%s (%s) added at slot %s in space freed from a storage gap", nv.Label, newer.typeLabel(nv), nv.Slot)})
+		default:
+			add(StorageChange{Kind: ChangeInserted, Label: nv.Label, Message: fmt.Sprintf(
+				"%s (%s) inserted at slot %s, overlapping existing storage", nv.Label, newer.typeLabel(nv), nv.Slot)})
+		}
+	}
+
+	return diff, nil
+}
+
+// withinGap reports whether p lies entirely inside one of the older layout's __gap variables.
+func withinGap(older *StorageLayout, oldPos []position, p position) bool {
+	for i, v := range older.Storage {
+		if isGap(v.Label) && p.start.Cmp(oldPos[i].start) >= 0 && p.end().Cmp(oldPos[i].end()) <= 0 {
+			return true
+		}
+	}
+	return false
+}
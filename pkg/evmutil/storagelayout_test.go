@@ -0,0 +1,94 @@
+package evmutil
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// layout builds a StorageLayout from "label:type:slot:offset" entries.
+func layout(vars ...string) *StorageLayout {
+	l := &StorageLayout{Types: map[string]StorageType{
+		"t_uint256":            {Label: "uint256", NumberOfBytes: "32"},
+		"t_address":            {Label: "address", NumberOfBytes: "20"},
+		"t_bool":               {Label: "bool", NumberOfBytes: "1"},
+		"t_array(t_uint256)50": {Label: "uint256[50]", NumberOfBytes: "1600"},
+		"t_array(t_uint256)49": {Label: "uint256[49]", NumberOfBytes: "1568"},
+		"t_array(t_uint256)48": {Label: "uint256[48]", NumberOfBytes: "1536"},
+	}}
+	for _, v := range vars {
+		parts := strings.Split(v, ":")
+		offset, _ := strconv.Atoi(parts[3])
+		l.Storage = append(l.Storage, StorageVariable{Label: parts[0], Type: parts[1], Slot: parts[2], Offset: offset})
+	}
+	return l
+}
+
+func TestDiffStorageLayouts(t *testing.T) {
+	base := layout("owner:t_address:0:0", "paused:t_bool:0:2", "total:t_uint256:1:0")
+
+	tests := []struct {
+		name      string
+		older     *StorageLayout
+		newer     *StorageLayout
+		wantSafe  bool
+		wantKinds []string
+	}{
+		{"unchanged", base, base, true, nil},
+		{"appended", base, layout("owner:t_address:0:0", "paused:t_bool:0:2", "total:t_uint256:1:0", "cap:t_uint256:2:0"),
+			true, []string{ChangeAppended}},
+		{"renamed", base, layout("admin:t_address:0:0", "paused:t_bool:0:2", "total:t_uint256:1:0"),
+			true, []string{ChangeRenamed}},
+		{"type changed", base, layout("owner:t_address:0:0", "paused:t_bool:0:2", "total:t_address:1:0"),
+			false, []string{ChangeTypeChanged}},
+		{"removed", base, layout("owner:t_address:0:0", "paused:t_bool:0:2"),
+			false, []string{ChangeRemoved}},
+		{"reordered", base, layout("owner:t_address:0:0", "total:t_uint256:1:0", "paused:t_bool:2:0"),
+			false, []string{ChangeMoved}},
+		{"inserted", base, layout("owner:t_address:0:0", "paused:t_bool:0:2", "cap:t_uint256:1:0", "total:t_uint256:2:0"),
+			false, []string{ChangeMoved, ChangeInserted}},
+		{"gap shrunk",
+			layout("total:t_uint256:0:0", "__gap:t_array(t_uint256)50:1:0"),
+			layout("total:t_uint256:0:0", "cap:t_uint256:1:0", "__gap:t_array(t_uint256)49:2:0"),
+			true, []string{ChangeGapResized, ChangeGapConsumed}},
+		{"gap shrunk too much",
+			layout("total:t_uint256:0:0", "__gap:t_array(t_uint256)50:1:0"),
+			layout("total:t_uint256:0:0", "cap:t_uint256:1:0", "__gap:t_array(t_uint256)48:2:0"),
+			false, []string{ChangeMoved, ChangeGapConsumed}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := DiffStorageLayouts(tt.older, tt.newer)
+			if err != nil {
+				t.Fatalf("DiffStorageLayouts() error = %v", err)
+			}
+			if diff.Safe != tt.wantSafe {
+				t.Errorf("Safe = %v, want %v (changes: %+v)", diff.Safe, tt.wantSafe, diff.Changes)
+			}
+			var kinds []string
+			for _, c := range diff.Changes {
+				kinds = append(kinds, c.Kind)
+			}
+			if strings.Join(kinds, ",") != strings.Join(tt.wantKinds, ",") {
+				t.Errorf("change kinds = %v, want %v", kinds, tt.wantKinds)
+			}
+		})
+	}
+}
+
+func TestDiffStorageLayouts_InvalidSlot(t *testing.T) {
+	if _, err := DiffStorageLayouts(layout("x:t_uint256:zero:0"), layout()); err == nil {
+		t.Error("expected an error for a non-numeric slot")
+	}
+}
+
+func TestParseStorageLayout(t *testing.T) {
+	l, err := ParseStorageLayout([]byte(`{"storage":[{"label":"x","offset":0,"slot":"0","type":"t_uint256"}],"types":{"t_uint256":{"label":"uint256","numberOfBytes":"32"}}}`))
+	if err != nil {
+		t.Fatalf("ParseStorageLayout() error = %v", err)
+	}
+	if len(l.Storage) != 1 || l.Types["t_uint256"].Label != "uint256" {
+		t.Errorf("ParseStorageLayout() = %+v", l)
+	}
+}
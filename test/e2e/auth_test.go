@@ -178,7 +178,7 @@ func TestAuth_PackageOwnership(t *testing.T) {
 // getErrorCode extracts the error code from an API error
 func getErrorCode(err error) string {
 	if apiErr, ok := err.(*client.APIError); ok {
-		return apiErr.Code
+		return string(apiErr.Code)
 	}
 	return ""
 }
@@ -41,7 +41,7 @@ func TestDeployment_RecordDeployment(t *testing.T) {
 		assert.NotEmpty(t, deployment.PackageID, "PackageID should be set")
 		assert.Equal(t, "Token", deployment.ContractName)
 		assert.Equal(t, "31337", deployment.ChainID)
-		assert.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", deployment.Address)
+		assert.Equal(t, "0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266", deployment.Address, "addresses are stored lowercase")
 		assert.Equal(t, int64(12345), deployment.BlockNumber)
 		assert.NotNil(t, deployment.VerifiedOn, "VerifiedOn should be present (may be empty for unverified deployments)")
 	})
@@ -4,6 +4,7 @@ package e2e
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
 
@@ -24,6 +25,14 @@ func TestHealth_Endpoints(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+		var health struct {
+			Status  string `json:"status"`
+			Version string `json:"version"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&health))
+		assert.Equal(t, "ok", health.Status)
+		assert.Equal(t, "e2e", health.Version)
 	})
 
 	t.Run("/healthz returns 200", func(t *testing.T) {
@@ -220,7 +220,7 @@ func startServerE(connString string) (*httptest.Server, storage.Store, error) {
 	registry.Register(evm.NewChain())
 
 	// Create server
-	srv := server.New(cfg, store, logger)
+	srv := server.New(cfg, store, logger, server.WithVersion("e2e"))
 
 	// Wrap server handler with auth bypass middleware for testing
 	handler := srv.Handler()
@@ -237,7 +237,7 @@ func newClient(testServer *httptest.Server, apiKey string) *client.Client {
 
 // createTestAPIKey creates a test API key using the store directly
 func createTestAPIKey(t *testing.T, store storage.Store, name string) string {
-	key, err := store.CreateAPIKey(context.Background(), name)
+	key, err := store.CreateAPIKey(context.Background(), name, nil)
 	require.NoError(t, err, "Failed to create API key")
 	return key
 }